@@ -0,0 +1,46 @@
+package bench
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdBench(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "bench",
+			Short: "Measures the performance of the environment",
+			Long: `Measures the time to first byte of a static file served through traefik, the latency of a php-fpm hello
+world script, the round-trip time of the file synchronization between the host and the containers and the insert
+throughput of the database, and prints a comparable score. It helps to compare mutagen sync with native mounts or
+different Docker Desktop settings.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdBench(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running bench command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Int("iterations", 20, "number of measured requests and file sync round-trips")
+	cmd.Flags().Int("rows", 1000, "number of rows inserted into the database")
+
+	return cmd
+}
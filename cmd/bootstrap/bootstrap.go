@@ -9,6 +9,7 @@ import (
 	cmdpkg "github.com/rewardenv/reward/cmd"
 	"github.com/rewardenv/reward/internal/config"
 	"github.com/rewardenv/reward/internal/logic"
+	"github.com/rewardenv/reward/pkg/util"
 )
 
 func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
@@ -24,7 +25,11 @@ func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
 				return nil, cobra.ShellCompDirectiveNoFileComp
 			},
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := logic.New(conf).RunCmdBootstrap()
+				client := logic.New(conf)
+
+				err := client.RunCmdBootstrap()
+				client.RecordHistory(cmd, args, err)
+
 				if err != nil {
 					return fmt.Errorf("error running bootstrap command: %w", err)
 				}
@@ -42,7 +47,7 @@ func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
 	)
 	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_no_pull", conf.AppName()), cmd.Flags().Lookup("no-pull"))
 
-	if conf.EnvType() == "magento1" || conf.EnvType() == "magento2" || conf.EnvType() == "shopware" {
+	if util.ContainsString([]string{"magento1", "magento2", "shopware", "sylius", "akeneo", "oro"}, conf.EnvType()) {
 		// --full
 		cmd.Flags().Bool("full", false, "includes sample data install and reindexing")
 		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_full_bootstrap", conf.AppName()), cmd.Flags().Lookup("full"))
@@ -65,7 +70,7 @@ func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
 			cmd.Flags().Lookup("reset-admin-url"))
 
 		// --magento-type
-		cmd.Flags().String("magento-type", "community", "magento type to install (community or enterprise)")
+		cmd.Flags().String("magento-type", "community", "magento type to install (community, enterprise or mage-os)")
 		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_magento_type", conf.AppName()),
 			cmd.Flags().Lookup("magento-type"))
 
@@ -100,6 +105,17 @@ func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
 		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_db_prefix", conf.AppName()), cmd.Flags().Lookup("db-prefix"))
 	}
 
+	if conf.EnvType() == "magento2" {
+		// --from-dump
+		cmd.Flags().String("from-dump", "", "bootstrap from a database dump (.sql, .sql.gz, .sql.zst) of an existing store")
+		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_bootstrap_from_dump", conf.AppName()),
+			cmd.Flags().Lookup("from-dump"))
+
+		// --media
+		cmd.Flags().String("media", "", "media archive (.tar, .tgz, .tar.zst) extracted to pub/media (with --from-dump)")
+		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_bootstrap_media", conf.AppName()), cmd.Flags().Lookup("media"))
+	}
+
 	if conf.EnvType() == "wordpress" {
 		// --db-prefix
 		cmd.Flags().String("db-prefix", "", "database table prefix")
@@ -127,5 +143,32 @@ func NewBootstrapCmd(conf *config.Config) *cmdpkg.Command {
 			cmd.Flags().Lookup("with-sampledata"))
 	}
 
+	if conf.EnvType() == "sylius" {
+		// --sylius-version
+		cmd.Flags().String(
+			"sylius-version", version.Must(conf.SyliusVersion()).Original(), "sylius version",
+		)
+		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_sylius_version", conf.AppName()),
+			cmd.Flags().Lookup("sylius-version"))
+	}
+
+	if conf.EnvType() == "akeneo" {
+		// --akeneo-version
+		cmd.Flags().String(
+			"akeneo-version", version.Must(conf.AkeneoVersion()).Original(), "akeneo pim version",
+		)
+		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_akeneo_version", conf.AppName()),
+			cmd.Flags().Lookup("akeneo-version"))
+	}
+
+	if conf.EnvType() == "oro" {
+		// --oro-version
+		cmd.Flags().String(
+			"oro-version", version.Must(conf.OroVersion()).Original(), "orocommerce version",
+		)
+		_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_oro_version", conf.AppName()),
+			cmd.Flags().Lookup("oro-version"))
+	}
+
 	return cmd
 }
@@ -0,0 +1,61 @@
+package buildimages
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdBuildImages(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "build-images <image> [<image>...]",
+			Short: "Builds the images of a fork of the rewardenv images",
+			Long: `Builds the images of a fork of the rewardenv images with docker buildx. The images are built from the
+<context>/<image> directories (eg. php-fpm, mariadb, nginx). The php-fpm, mariadb, elasticsearch, opensearch, rabbitmq,
+redis and varnish images are built for every supported version (with the version as build argument, eg. PHP_VERSION)
+and tagged with the version, the other images are tagged with latest.`,
+			Example: `  # build the php-fpm images for linux/amd64 and linux/arm64 and push them to the registry
+  reward build-images php-fpm --registry registry.example.com/acme --push
+
+  # build the php-fpm 8.1 image and use it in the current environment
+  reward build-images php-fpm --registry registry.example.com/acme --platform linux/amd64 \
+    --versions 8.1 --update-overrides`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveFilterDirs
+			},
+			Args: cobra.MinimumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdBuildImages(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running build-images command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("context", ".", "directory of the image build contexts")
+	cmd.Flags().String("registry", "", "registry (and namespace) of the images, eg. registry.example.com/acme")
+	cmd.Flags().String("platform", "", "platforms of the images (default \"linux/amd64,linux/arm64\")")
+	cmd.Flags().StringSlice("versions", nil, "build these versions only (eg. 8.1,8.2)")
+	cmd.Flags().Bool("push", false, "push the images to the registry (required for multiple platforms)")
+	cmd.Flags().Bool("update-overrides", false, "use the built images in the current environment")
+	cmd.Flags().Bool("dry-run", false, "print the buildx commands without running them")
+
+	return cmd
+}
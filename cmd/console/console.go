@@ -0,0 +1,40 @@
+package console
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdConsole(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "console [command]",
+			Short: "Runs the console of the application (bin/console, typo3, craft)",
+			Long: `Runs the console of the application in the php-fpm container: bin/console for the Symfony based
+environments, typo3 for TYPO3 and craft for Craft CMS. The arguments are passed to the console as they are
+(eg. reward console cache:clear --env=prod, reward console cache:flush, reward console migrate/all).`,
+			DisableFlagParsing: true,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdConsole(args)
+				if err != nil {
+					return fmt.Errorf("error running console command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdDaemon(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "daemon",
+			Short: "Serves a localhost API for IDE plugins and speeds up the commands",
+			Long: `Serves a localhost API to list the environments, start and stop them, stream their logs and show their
+status, eg. for IDE plugins. The address and the token of the API are saved to daemon.json in the home directory of
+reward. While the daemon is running, the commands use the docker and docker-compose versions detected by it instead
+of running the preflight checks on every invocation.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDaemon()
+				if err != nil {
+					return fmt.Errorf("error running daemon command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("listen", "127.0.0.1:7474", "the localhost address the API listens on")
+	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_daemon_address", conf.AppName()), cmd.Flags().Lookup("listen"))
+
+	cmd.AddCommands(
+		newCmdDaemonInstall(conf),
+		newCmdDaemonUninstall(conf),
+	)
+
+	return cmd
+}
+
+func newCmdDaemonInstall(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "install",
+			Short: "Registers the daemon to be started at login",
+			Long: `Registers the daemon as a LaunchAgent on macOS or as a systemd user unit on Linux, so it's started at
+login (eg. for a menu bar companion) and restarted if it exits.`,
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDaemonInstall()
+				if err != nil {
+					return fmt.Errorf("error running daemon install command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	return cmd
+}
+
+func newCmdDaemonUninstall(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "uninstall",
+			Short: "Stops the daemon and removes its registration",
+			Long:  `Stops the daemon and removes its LaunchAgent on macOS or its systemd user unit on Linux.`,
+			Args:  cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDaemonUninstall()
+				if err != nil {
+					return fmt.Errorf("error running daemon uninstall command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	return cmd
+}
@@ -25,11 +25,19 @@ func NewCmdDB(conf *config.Config) *cmdpkg.Command {
 				return nil, cobra.ShellCompDirectiveNoFileComp
 			},
 			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-				if !conf.IsSvcEnabled("db") || !conf.Docker.ContainerRunning("db") {
-					return docker.ErrCannotFindContainer("db", nil)
+				// the checks of the root command are not run as this hook overrides it
+				err := conf.CheckReadOnly(cmd, args)
+				if err != nil {
+					return err
 				}
 
-				return nil
+				if !conf.IsSvcEnabled("db") {
+					return docker.ErrCannotFindContainer(conf.DBContainer(), nil)
+				}
+
+				_, err = conf.Docker.RunningEnvServiceContainer(conf.DBContainer())
+
+				return err
 			},
 			RunE: func(cmd *cobra.Command, args []string) error {
 				err := cmd.Help()
@@ -47,6 +55,10 @@ func NewCmdDB(conf *config.Config) *cmdpkg.Command {
 		newCmdDBConnect(conf),
 		newCmdDBImport(conf),
 		newCmdDBDump(conf),
+		newCmdDBRewriteURLs(conf),
+		newCmdDBUpgrade(conf),
+		newCmdDBExpose(conf),
+		newCmdDBUnexpose(conf),
 	)
 
 	return cmd
@@ -87,7 +99,8 @@ func newCmdDBImport(conf *config.Config) *cmdpkg.Command {
 		Command: &cobra.Command{
 			Use:   "import",
 			Short: "Reads data from stdin and loads it into the current project's mysql database",
-			Long:  `Reads data from stdin and loads it into the current project's mysql database`,
+			Long: `Reads data from stdin and loads it into the current project's mysql database. Dumps compressed with
+gzip, zstd, bzip2 or xz are decompressed automatically.`,
 			ValidArgsFunction: func(
 				cmd *cobra.Command,
 				args []string,
@@ -97,7 +110,11 @@ func newCmdDBImport(conf *config.Config) *cmdpkg.Command {
 			},
 			FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := logic.New(conf).RunCmdDBImport(cmd, args)
+				client := logic.New(conf)
+
+				err := client.RunCmdDBImport(cmd, args)
+				client.RecordHistory(cmd, args, err)
+
 				if err != nil {
 					return fmt.Errorf("error running db import command: %w", err)
 				}
@@ -111,6 +128,8 @@ func newCmdDBImport(conf *config.Config) *cmdpkg.Command {
 	cmd.Flags().Bool("root", false, "import as mysql root user")
 	cmd.Flags().Int("line-buffer-size", 10, "line buffer size in mb for database import")
 	_ = conf.BindPFlag("db_import_line_buffer_size", cmd.Flags().Lookup("line-buffer-size"))
+	cmd.Flags().String("rewrite-from", "", "rewrite this url in the configuration after the import (see rewrite-urls)")
+	cmd.Flags().String("rewrite-to", "", "the url which replaces --rewrite-from (default: the environment's url)")
 
 	return cmd
 }
@@ -130,7 +149,12 @@ func newCmdDBDump(conf *config.Config) *cmdpkg.Command {
 			},
 			FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := logic.New(conf).RunCmdDBDump(cmd, args)
+				client := logic.New(conf)
+
+				// a dump is not recorded in the history, but the webhooks are notified about the backup
+				err := client.RunCmdDBDump(cmd, args)
+				client.SendWebhooks(cmd, args, err)
+
 				if err != nil {
 					return fmt.Errorf("error running db dump command: %w", err)
 				}
@@ -142,6 +166,144 @@ func newCmdDBDump(conf *config.Config) *cmdpkg.Command {
 	}
 
 	cmd.Flags().Bool("root", false, "dump database as mysql root user")
+	cmd.Flags().String("compress", "", "compress the dump (valid options: gzip, zstd)")
 
 	return cmd
 }
+
+func newCmdDBRewriteURLs(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "rewrite-urls",
+			Short: "Replaces a URL in the configuration stored in the database",
+			Long: `Replaces a URL in the configuration stored in the database (core_config_data of Magento and the
+options of WordPress). The lengths of the PHP serialized strings are updated, so serialized values remain valid.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdDBRewriteURLs(cmd)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running db rewrite-urls command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("from", "", "the url to replace (eg. https://www.example.com)")
+	cmd.Flags().String("to", "", "the url which replaces --from (default: the environment's url)")
+	cmd.Flags().Bool("dry-run", false, "only report the number of values which would be rewritten")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func newCmdDBUpgrade(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "upgrade",
+			Short: "Recreates the database with another MariaDB version",
+			Long: `Recreates the database with another MariaDB version. The database is dumped with the running version,
+the data directory is copied to a snapshot volume, the volume is recreated, MARIADB_VERSION is set in the .env file
+and the dump is imported with the new version.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdDBUpgrade(cmd)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running db upgrade command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("to", "", "the MariaDB version to upgrade to (eg. 10.11)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func newCmdDBExpose(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "expose",
+			Short: "Publishes the port of the database on localhost for database GUIs",
+			Long: `Publishes the port of the database on localhost and prints the connection settings for database GUIs
+(eg. TablePlus, DBeaver). A random free port is used by default. The db container is not recreated, the port is
+published by a proxy container until it's removed using db unexpose or the environment is brought down.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDBExpose(cmd)
+				if err != nil {
+					return fmt.Errorf("error running db expose command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Int("port", 0, "port of localhost to publish the database on (0: random free port)")
+
+	return cmd
+}
+
+func newCmdDBUnexpose(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "unexpose",
+			Short: "Removes the port of the database published by db expose",
+			Long:  `Removes the port of the database published by db expose`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDBUnexpose()
+				if err != nil {
+					return fmt.Errorf("error running db unexpose command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
@@ -0,0 +1,44 @@
+package detect
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdDetect(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "detect",
+			Short: "Detects the required services and versions from the project code",
+			Long: `Inspects composer.json, composer.lock, app/etc/env.php, wp-config.php and .env.example to infer the required
+services and versions (eg. redis, rabbitmq, elasticsearch version, php constraint) and proposes configuration
+changes.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDetect(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running detect command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("apply", false, "write the proposed settings to the .env file")
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdDoctor(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "doctor",
+			Short: "Checks the common causes of problems and prints how to fix them",
+			Long: `Checks the common causes of problems: the reachability and the version of docker and docker compose,
+the CA certificate, the DNS resolution of the .test domains, mutagen, the conflicts of the http and https ports and
+the file permissions in the home directory. In an environment directory it checks the service versions as well. The
+command exits with an error if a check fails, with --strict the warnings fail as well.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdDoctor(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running doctor command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("strict", false, "exit with an error if a check has a warning")
+
+	return cmd
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func NewCmdEnv(conf *config.Config) *cmdpkg.Command {
-	return &cmdpkg.Command{
+	cmd := &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:                "env",
 			Short:              "Controls an environment from any point within the root project directory",
@@ -20,7 +20,50 @@ func NewCmdEnv(conf *config.Config) *cmdpkg.Command {
 			ValidArgsFunction:  dockercompose.Completer(),
 			DisableFlagParsing: true,
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := logic.New(conf).RunCmdEnv(args)
+				client := logic.New(conf)
+
+				err := client.RunCmdEnv(args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdEnvList(conf),
+		newCmdEnvClone(conf),
+		newCmdEnvPromote(conf),
+		newCmdEnvGet(conf),
+		newCmdEnvSet(conf),
+		newCmdEnvDiff(conf),
+		newCmdEnvRestart(conf),
+		newCmdEnvRotateCredentials(conf),
+	)
+
+	return cmd
+}
+
+// NewCmdEnvGlobal returns the env command used outside of the environments, it only lists the environments.
+func NewCmdEnvGlobal(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "env [command]",
+			Short: "Lists the environments, the other env commands are available in the environment directories",
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
 				if err != nil {
 					return fmt.Errorf("error running env command: %w", err)
 				}
@@ -30,4 +73,298 @@ func NewCmdEnv(conf *config.Config) *cmdpkg.Command {
 		},
 		Config: conf,
 	}
+
+	cmd.AddCommands(
+		newCmdEnvList(conf),
+	)
+
+	return cmd
+}
+
+func newCmdEnvList(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:     "list",
+			Aliases: []string{"ls"},
+			Short:   "Lists the environments initialized on this machine",
+			Long: `Lists the environments initialized by env-init or started by env up on this machine with their type,
+directory, status and domains. The environments whose directory was removed are listed as missing.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdEnvList(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running env list command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("prune", false, "remove the missing environments from the list")
+
+	return cmd
+}
+
+func newCmdEnvClone(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "clone <new-name>",
+			Short: "Duplicates the current environment under a new name and domain",
+			Long: `Duplicates the current environment under a new name and domain. The project files, the docker volumes and
+the database are copied to the clone, so risky upgrades can be tested in parallel with the original environment.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdEnvClone(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env clone command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("dir", "", "directory of the clone (default: ../<new-name>)")
+	cmd.Flags().String("domain", "", "traefik domain of the clone (default: the current domain with the new name)")
+	cmd.Flags().StringSlice("exclude", []string{}, "project paths which are not copied to the clone")
+	cmd.Flags().Bool("skip-volumes", false, "do not copy the docker volumes to the clone")
+	cmd.Flags().Bool("skip-db", false, "do not copy the database to the clone")
+
+	return cmd
+}
+
+func newCmdEnvPromote(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "promote <clone>",
+			Short: "Switches the domain of the current environment to one of its clones",
+			Long: `Switches the traefik domain and extra hosts of the current environment and one of its clones, so the
+clone serves the primary domain and the current environment is available on the clone's domain.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdEnvPromote(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env promote command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("dir", "", "directory of the clone (default: ../<clone>)")
+	cmd.Flags().Bool("force", false, "promote the environment even if it's not a clone of the current environment")
+
+	return cmd
+}
+
+func newCmdEnvGet(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "get <key>",
+			Short: "Prints the value of a setting of the .env file",
+			Long:  `Prints the value of a setting of the project .env file.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return envSettingKeys(conf, args), cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdEnvGet(args)
+				if err != nil {
+					return fmt.Errorf("error running env get command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
+
+func newCmdEnvSet(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "set <key> <value>",
+			Short: "Changes the value of a setting of the .env file",
+			Long: `Changes the value of a setting of the project .env file. The value is validated against the type of
+the setting, and the comments and the order of the settings in the file are preserved.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return envSettingKeys(conf, args), cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdEnvSet(args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env set command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
+
+func newCmdEnvDiff(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "diff",
+			Short: "Reports the services which don't match the current configuration",
+			Long: `Compares the containers of the environment with the freshly rendered configuration and reports the
+services which have to be recreated (using env up -d) to apply the changes of the .env file and the templates.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdEnvDiff(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running env diff command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("exit-code", false, "exit with an error if any service is stale")
+
+	return cmd
+}
+
+func newCmdEnvRestart(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "restart [service...]",
+			Short: "Restarts or recreates services of the environment",
+			Long: `Restarts or recreates the passed services (or all services) without running env up. The services which
+depend on them are reloaded as well (eg. restarting php-fpm reloads nginx, so it picks up the new upstream address).
+Use --apply-config to render the configuration first and recreate the services whose configuration changed.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdEnvRestart(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env restart command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool(
+		"apply-config", false, "render the configuration and recreate the services whose configuration changed",
+	)
+	cmd.Flags().Bool("recreate", false, "recreate the containers instead of restarting them")
+
+	return cmd
+}
+
+func newCmdEnvRotateCredentials(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "rotate-credentials [db|rabbitmq|redis...]",
+			Short: "Generates new passwords for the services of the environment",
+			Long: `Generates new passwords for the database, RabbitMQ and Redis (or the passed services), applies them in the
+running services and saves them to the .env file. The credentials in app/etc/env.php are updated for Magento 2, the
+configuration of other applications has to be updated manually if it doesn't read the .env file.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return []string{"db", "rabbitmq", "redis"}, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdEnvRotateCredentials(args)
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running env rotate-credentials command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
+
+// envSettingKeys returns the keys of the known .env settings for the completion of the first argument.
+func envSettingKeys(conf *config.Config, args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+
+	keys := make([]string, 0)
+	for _, s := range conf.EnvSettings() {
+		if s.ReadOnly == "" {
+			keys = append(keys, s.Key)
+		}
+	}
+
+	return keys
 }
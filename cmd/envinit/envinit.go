@@ -48,5 +48,13 @@ func NewCmdEnvInit(conf *config.Config) *cmdpkg.Command {
 
 	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_env_type", conf.AppName()), cmd.Flags().Lookup("environment-type"))
 
+	cmd.Flags().String("magento-type", "community", "magento type of the new magento2 environment")
+	_ = cmd.RegisterFlagCompletionFunc(
+		"magento-type",
+		func(envInitCmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return conf.ValidMagentoTypes(), cobra.ShellCompDirectiveDefault
+		},
+	)
+
 	return cmd
 }
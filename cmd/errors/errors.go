@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdErrors(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "errors [command]",
+			Short: "Inspects the php-fpm, nginx and application error logs of the environment",
+			Long:  `Inspects the php-fpm, nginx and application error logs of the environment`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running errors command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdErrorsTail(conf),
+	)
+
+	return cmd
+}
+
+func newCmdErrorsTail(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "tail",
+			Short: "Follows the php-fpm, nginx and application error logs of the environment in a single stream",
+			Long: `Follows the php-fpm, nginx and application error logs of the environment in a single stream. The
+application logs are read from var/log (Magento, Symfony, Shopware), storage/logs (Laravel, Craft CMS) or
+wp-content/debug.log (WordPress).`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdErrorsTail(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running errors tail command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("grep", "", "only show the lines matching the regular expression")
+	cmd.Flags().Duration("since", 0, "show the lines newer than this duration (eg. 15m, 1h) instead of the last lines")
+	cmd.Flags().IntP("lines", "n", 10, "number of lines to show from each log before following")
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package fixpermissions
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdFixPermissions(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "fix-permissions [path1] [path2]",
+			Short: "Changes the ownership of the writable directories to the configured user and group IDs",
+			Long: `Changes the ownership of the writable directories (eg. var, generated, pub/media) inside the container
+to the user and group IDs configured by REWARD_UID and REWARD_GID (defaults to the invoking user).`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				container := conf.DefaultSyncedContainer(conf.EnvType())
+				if !conf.Docker.ContainerRunning(container) {
+					return docker.ErrCannotFindContainer(container, nil)
+				}
+
+				return nil
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdFixPermissions(args)
+				if err != nil {
+					return fmt.Errorf("error running fix-permissions command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
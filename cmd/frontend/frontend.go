@@ -0,0 +1,106 @@
+package frontend
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdFrontend(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "frontend [command]",
+			Short: "Runs the frontend tooling of the Hyvä theme in the node sidecar",
+			Long:  `Runs the frontend tooling of the Hyvä theme in the node sidecar`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running frontend command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdFrontendWatch(conf),
+		newCmdFrontendGrunt(conf),
+	)
+
+	return cmd
+}
+
+func newCmdFrontendWatch(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "watch",
+			Short: "Starts the tailwind watcher of the Hyvä theme and streams its output",
+			Long:  `Starts the tailwind watcher of the Hyvä theme and streams its output`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdFrontendWatch(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running frontend watch command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().IntP("lines", "n", 50, "number of lines to show")
+
+	return cmd
+}
+
+func newCmdFrontendGrunt(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "grunt [task]...",
+			Short: "Runs grunt tasks of the Magento frontend workflow in the node sidecar",
+			Long: `Runs grunt tasks of the Magento frontend workflow in the node sidecar. The clean, exec, less and watch tasks
+run for the configured theme unless a target is given (eg. less:luma).`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return []string{"clean", "exec", "less", "watch"}, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdFrontendGrunt(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				if err != nil {
+					return fmt.Errorf("error running frontend grunt command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("theme", "", "theme to run the tasks for (default: REWARD_FRONTEND_THEME)")
+
+	return cmd
+}
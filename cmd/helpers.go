@@ -22,6 +22,21 @@ type Command struct {
 	Config *config.Config
 }
 
+// ExitError is an error which exits the application with a specific exit code, so the scripts calling it can tell
+// the failures apart (eg. a timeout from an unknown service).
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func (c *Command) AddCommands(commands ...*Command) {
 	for _, command := range commands {
 		c.AddCommand(command.Command)
@@ -0,0 +1,74 @@
+package home
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdHome(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "home",
+			Short: "Prints the directories and the configuration file used by reward",
+			Long: `Prints the home directory (certificates, service configurations, plugins), the configuration file and
+the cache directory used by reward. On Linux they're in the XDG base directories unless ~/.reward exists. The home
+directory can be overridden by the REWARD_HOME environment variable or the --app-dir flag.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdHome()
+				if err != nil {
+					return fmt.Errorf("error running home command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdHomeMigrate(conf),
+	)
+
+	return cmd
+}
+
+func newCmdHomeMigrate(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "migrate",
+			Short: "Moves ~/.reward and ~/.reward.yml to the XDG base directories",
+			Long: `Moves the legacy home directory and configuration file to the XDG base directories on Linux: the cache
+to $XDG_CACHE_HOME/reward, ~/.reward.yml to $XDG_CONFIG_HOME/reward/reward.yml and the rest of ~/.reward to
+$XDG_DATA_HOME/reward. The common services have to be stopped (svc down) before the migration.`,
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+				err := logic.New(conf).RunCmdHomeMigrate(dryRun)
+				if err != nil {
+					return fmt.Errorf("error running home migrate command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "print the files and directories which would be moved")
+
+	return cmd
+}
@@ -23,7 +23,14 @@ func NewCmdInstall(conf *config.Config) *cmdpkg.Command {
 			) ([]string, cobra.ShellCompDirective) {
 				return nil, cobra.ShellCompDirectiveNoFileComp
 			},
-			Args: cobra.ExactArgs(0),
+			Args: func(cmd *cobra.Command, args []string) error {
+				// `--dns coredns` is parsed as --dns without a value followed by an argument.
+				if len(args) == 1 && cmd.Flags().Changed("dns") && cmd.Flags().Lookup("dns").Value.String() == "true" {
+					return cmd.Flags().Set("dns", args[0])
+				}
+
+				return cobra.ExactArgs(0)(cmd, args)
+			},
 			RunE: func(cmd *cobra.Command, args []string) error {
 				err := logic.New(conf).RunCmdInstall()
 				if err != nil {
@@ -39,7 +46,8 @@ func NewCmdInstall(conf *config.Config) *cmdpkg.Command {
 	cmd.Flags().Bool("reinstall", false, "reinstall configurations")
 	cmd.Flags().Bool("uninstall", false, "uninstall configurations")
 	cmd.Flags().Bool("ca-cert", false, "install ca-certificate only")
-	cmd.Flags().Bool("dns", false, "install dns settings only")
+	cmd.Flags().String("dns", "", "install dns settings only, optionally switching the dns server (dnsmasq or coredns)")
+	cmd.Flags().Lookup("dns").NoOptDefVal = "true"
 	cmd.Flags().Bool("ssh-key", false, "install ssh key only")
 	cmd.Flags().Bool("ssh-config", false, "install ssh config only")
 	cmd.Flags().Bool("ignore-ca-cert", false, "ignore ca-certificate creation")
@@ -0,0 +1,106 @@
+package nginx
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdNginx(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "nginx [command]",
+			Short: "Interacts with the nginx service on an environment",
+			Long:  `Interacts with the nginx service on an environment`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				if !conf.Docker.ContainerRunning(conf.NginxContainer()) {
+					return docker.ErrCannotFindContainer(conf.NginxContainer(), nil)
+				}
+
+				return nil
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running nginx command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdNginxTest(conf),
+		newCmdNginxReload(conf),
+	)
+
+	return cmd
+}
+
+func newCmdNginxTest(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "test",
+			Short: "Regenerates the nginx presets and tests the nginx configuration inside the container",
+			Long:  `Regenerates the nginx presets and tests the nginx configuration inside the container`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdNginxTest()
+				if err != nil {
+					return fmt.Errorf("error running nginx test command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
+
+func newCmdNginxReload(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "reload",
+			Short: "Tests the nginx configuration and reloads nginx inside the container",
+			Long:  `Tests the nginx configuration and reloads nginx inside the container`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdNginxReload()
+				if err != nil {
+					return fmt.Errorf("error running nginx reload command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
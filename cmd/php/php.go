@@ -0,0 +1,147 @@
+package php
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdPHP(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "php [command]",
+			Short: "Inspects the php-fpm service on an environment",
+			Long:  `Inspects the php-fpm service on an environment`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				if !conf.Docker.ContainerRunning("php-fpm") {
+					return docker.ErrCannotFindContainer("php-fpm", nil)
+				}
+
+				return nil
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running php command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdPHPStatus(conf),
+		newCmdPHPSlowlog(conf),
+	)
+
+	return cmd
+}
+
+func newCmdPHPStatus(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "status",
+			Short: "Shows the active and idle php-fpm workers and the listen queue",
+			Long:  `Shows the active and idle php-fpm workers and the listen queue`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdPHPStatus(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running php status command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("full", false, "show the status of every php-fpm worker")
+
+	return cmd
+}
+
+func newCmdPHPSlowlog(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "slowlog [command]",
+			Short: "Inspects the php-fpm slowlog",
+			Long:  `Inspects the php-fpm slowlog`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running php slowlog command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdPHPSlowlogTail(conf),
+	)
+
+	return cmd
+}
+
+func newCmdPHPSlowlogTail(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "tail",
+			Short: "Prints the stack traces of the slow php requests",
+			Long:  `Prints the stack traces of the php requests which ran longer than the slowlog timeout`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdPHPSlowlogTail(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running php slowlog tail command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().BoolP("follow", "f", false, "follow the slowlog")
+	cmd.Flags().IntP("lines", "n", 50, "number of lines to show")
+
+	return cmd
+}
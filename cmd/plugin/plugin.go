@@ -28,6 +28,7 @@ func NewCmdPlugin(conf *config.Config) *cmdpkg.Command {
 		NewCmdPluginList(conf),
 		NewCmdPluginListAvailable(conf),
 		NewCmdPluginInstall(conf),
+		NewCmdPluginUpdate(conf),
 		NewCmdPluginRemove(conf),
 	)
 
@@ -79,9 +80,10 @@ func NewCmdPluginListAvailable(conf *config.Config) *cmdpkg.Command {
 func NewCmdPluginInstall(conf *config.Config) *cmdpkg.Command {
 	cmd := &cmdpkg.Command{
 		Command: &cobra.Command{
-			Use:   "install pluginname",
+			Use:   "install pluginname[@version]",
 			Short: "Install a plugin",
-			Long:  `Install a plugin`,
+			Long: `Install a plugin. The plugin can be pinned to a version (eg. foo@1.2.0), the pinned plugins are not
+updated by plugin update. Installing the plugin without a version unpins it.`,
 			RunE: func(cmd *cobra.Command, args []string) error {
 				err := logic.New(conf).RunCmdPluginInstall(&cmdpkg.Command{Command: cmd, Config: conf},
 					args)
@@ -102,6 +104,33 @@ func NewCmdPluginInstall(conf *config.Config) *cmdpkg.Command {
 	return cmd
 }
 
+// NewCmdPluginUpdate provides a way to update the installed plugins.
+func NewCmdPluginUpdate(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "update [pluginname...]",
+			Short: "Update the installed plugins",
+			Long: `Update the installed plugins (or the given plugins) if a newer version is available online. The pinned
+plugins are not updated.`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdPluginUpdate(&cmdpkg.Command{Command: cmd, Config: conf},
+					args)
+				if err != nil {
+					return fmt.Errorf("error updating plugins: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().BoolP("dry-run", "n", false, "only prints the available updates")
+	cmd.Flags().Bool("prerelease", false, "allow checking prerelease versions")
+
+	return cmd
+}
+
 // NewCmdPluginRemove provides a way to delete installed plugins.
 func NewCmdPluginRemove(conf *config.Config) *cmdpkg.Command {
 	return &cmdpkg.Command{
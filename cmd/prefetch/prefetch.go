@@ -0,0 +1,43 @@
+package prefetch
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdPrefetch(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "prefetch",
+			Short: "Pulls the missing and updated images of the environments",
+			Long: `Pulls the missing and updated images of the common services and the environments which have containers
+on the docker host, so the first env up isn't blocked by the downloads. It doesn't start the environments, so it can be
+run from a login script, eg. (reward prefetch --delay 5m >/dev/null 2>&1 &)`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdPrefetch(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running prefetch command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Duration("delay", 0, "time to wait before pulling the images (eg. 5m after login)")
+	cmd.Flags().Bool("skip-services", false, "don't pull the images of the common services")
+
+	return cmd
+}
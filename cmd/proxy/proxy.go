@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdProxy(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "proxy [command]",
+			Short: "Manages the routes to the applications running on the host",
+			Long: `Manages the traefik routes to the applications running directly on the host, so they are served with
+the same domain and TLS setup as the environments`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running proxy command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdProxyAdd(conf),
+		newCmdProxyList(conf),
+		newCmdProxyRemove(conf),
+	)
+
+	return cmd
+}
+
+func newCmdProxyAdd(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "add <domain> --to <host:port>",
+			Short: "Adds a route from the domain to an application running on the host",
+			Long: `Adds a traefik route from the domain to an application running on the host. The certificate of the
+domain is signed by the local CA. The application has to listen on an address which is reachable from the containers
+(eg. 0.0.0.0 on Linux).`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdProxyAdd(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				if err != nil {
+					return fmt.Errorf("error running proxy add command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("to", "", "address of the application on the host (eg. localhost:3000)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func newCmdProxyList(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:     "ls",
+			Aliases: []string{"list"},
+			Short:   "Lists the routes to the applications running on the host",
+			Long:    `Lists the routes to the applications running on the host`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdProxyList()
+				if err != nil {
+					return fmt.Errorf("error running proxy ls command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
+
+func newCmdProxyRemove(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:     "rm <domain>",
+			Aliases: []string{"remove"},
+			Short:   "Removes the route of the domain",
+			Long:    `Removes the route of the domain. The certificate of the domain is kept.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdProxyRemove(args)
+				if err != nil {
+					return fmt.Errorf("error running proxy rm command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
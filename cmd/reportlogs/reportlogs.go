@@ -0,0 +1,46 @@
+package reportlogs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdReportLogs(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "report-logs [service...]",
+			Short: "Collects the recent logs and the configuration of the environment to share them",
+			Long: `Collects the recent logs of the services (all running services by default) and the configuration of the
+environment into a tar.gz archive, or prints them as markdown text with --text. The values of the secret settings
+(passwords, tokens, keys) are redacted in the configuration and in the logs.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdReportLogs(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				if err != nil {
+					return fmt.Errorf("error running report-logs command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Duration("since", 30*time.Minute, "collect the logs newer than this duration (eg. 15m, 1h)")
+	cmd.Flags().StringP("output", "o", "", "write the report to this file (default: <env>-logs-<time>.tar.gz)")
+	cmd.Flags().Bool("text", false, "write the report as markdown text (to stdout unless --output is set)")
+
+	return cmd
+}
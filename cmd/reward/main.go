@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
@@ -8,8 +9,10 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	cmdpkg "github.com/rewardenv/reward/cmd"
 	"github.com/rewardenv/reward/cmd/root"
 	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/timing"
 )
 
 var (
@@ -26,6 +29,8 @@ func main() {
 		syscall.SIGQUIT,
 	)
 
+	stopProfiling := startProfiling(os.Args[1:])
+
 	app := config.New(APPNAME, VERSION)
 
 	cobra.OnInitialize(func() {
@@ -35,6 +40,8 @@ func main() {
 	go func() {
 		<-sig
 
+		stopProfiling()
+
 		if err := app.Cleanup(); err != nil {
 			os.Exit(1)
 		}
@@ -43,9 +50,21 @@ func main() {
 	}()
 
 	err := root.NewCmdRoot(app).Execute()
+
+	stopProfiling()
+
+	if app.GetBool("profile") {
+		_ = timing.Write(os.Stderr, timing.Elapsed())
+	}
+
 	if err != nil {
 		log.Error(err)
 
+		var exitErr *cmdpkg.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
 		os.Exit(1)
 	}
 	_ = app.Cleanup()
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// profileFlag returns the value of the profiling flag (eg. --cpuprofile) from the arguments or the environment
+// variable (eg. REWARD_CPUPROFILE). The flags are read before the command line is parsed, so the whole command is
+// profiled.
+func profileFlag(args []string, name string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return strings.TrimPrefix(arg, "--"+name+"=")
+		}
+
+		if arg == "--"+name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+
+	return os.Getenv(strings.ToUpper(APPNAME + "_" + name))
+}
+
+// startProfiling starts the CPU profiling if --cpuprofile is set. The returned function stops it and writes the heap
+// profile if --memprofile is set. The profiles can be analyzed using `go tool pprof`.
+func startProfiling(args []string) func() {
+	var (
+		cpuProfile = profileFlag(args, "cpuprofile")
+		memProfile = profileFlag(args, "memprofile")
+		cpuFile    *os.File
+	)
+
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			log.Warnf("Cannot create cpu profile: %s", err)
+		} else if err = pprof.StartCPUProfile(f); err != nil {
+			log.Warnf("Cannot start cpu profiling: %s", err)
+
+			_ = f.Close()
+		} else {
+			cpuFile = f
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+
+			_ = cpuFile.Close()
+
+			log.Debugf("CPU profile written to %s.", cpuProfile)
+		}
+
+		if memProfile != "" {
+			err := writeMemProfile(memProfile)
+			if err != nil {
+				log.Warnf("Cannot write memory profile: %s", err)
+			}
+		}
+	}
+}
+
+func writeMemProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer f.Close()
+
+	// the statistics are up to date after a garbage collection
+	runtime.GC()
+
+	err = pprof.WriteHeapProfile(f)
+	if err != nil {
+		return fmt.Errorf("cannot write heap profile: %w", err)
+	}
+
+	log.Debugf("Memory profile written to %s.", path)
+
+	return nil
+}
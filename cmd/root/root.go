@@ -3,7 +3,6 @@ package root
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -11,23 +10,50 @@ import (
 	"github.com/spf13/cobra"
 
 	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/cmd/bench"
 	"github.com/rewardenv/reward/cmd/blackfire"
 	"github.com/rewardenv/reward/cmd/bootstrap"
+	"github.com/rewardenv/reward/cmd/buildimages"
 	"github.com/rewardenv/reward/cmd/completion"
+	"github.com/rewardenv/reward/cmd/console"
+	"github.com/rewardenv/reward/cmd/daemon"
 	"github.com/rewardenv/reward/cmd/db"
 	"github.com/rewardenv/reward/cmd/debug"
+	"github.com/rewardenv/reward/cmd/detect"
+	"github.com/rewardenv/reward/cmd/doctor"
 	"github.com/rewardenv/reward/cmd/env"
 	"github.com/rewardenv/reward/cmd/envinit"
+	"github.com/rewardenv/reward/cmd/errors"
+	"github.com/rewardenv/reward/cmd/fixpermissions"
+	"github.com/rewardenv/reward/cmd/frontend"
+	"github.com/rewardenv/reward/cmd/history"
+	"github.com/rewardenv/reward/cmd/home"
 	"github.com/rewardenv/reward/cmd/info"
 	"github.com/rewardenv/reward/cmd/install"
+	"github.com/rewardenv/reward/cmd/nginx"
+	"github.com/rewardenv/reward/cmd/php"
 	"github.com/rewardenv/reward/cmd/plugin"
+	"github.com/rewardenv/reward/cmd/prefetch"
+	"github.com/rewardenv/reward/cmd/proxy"
+	"github.com/rewardenv/reward/cmd/reportlogs"
+	"github.com/rewardenv/reward/cmd/search"
 	"github.com/rewardenv/reward/cmd/selfupdate"
 	"github.com/rewardenv/reward/cmd/shell"
 	"github.com/rewardenv/reward/cmd/shortcuts"
 	"github.com/rewardenv/reward/cmd/signcertificate"
+	"github.com/rewardenv/reward/cmd/status"
 	"github.com/rewardenv/reward/cmd/svc"
 	"github.com/rewardenv/reward/cmd/sync"
+	"github.com/rewardenv/reward/cmd/traffic"
+	"github.com/rewardenv/reward/cmd/try"
+	"github.com/rewardenv/reward/cmd/tunnel"
+	"github.com/rewardenv/reward/cmd/undo"
+	"github.com/rewardenv/reward/cmd/upgradeenv"
+	"github.com/rewardenv/reward/cmd/varnish"
+	"github.com/rewardenv/reward/cmd/vendor"
 	"github.com/rewardenv/reward/cmd/version"
+	"github.com/rewardenv/reward/cmd/wait"
+	"github.com/rewardenv/reward/cmd/whoami"
 	"github.com/rewardenv/reward/internal/config"
 	"github.com/rewardenv/reward/internal/logic"
 	"github.com/rewardenv/reward/pkg/util"
@@ -91,26 +117,59 @@ func NewCmdRoot(conf *config.Config) *cmdpkg.Command {
 
 	if conf.EnvInitialized() {
 		cmd.AddGroups("Environment Commands:",
+			bench.NewCmdBench(conf),
 			blackfire.NewBlackfireCmd(conf),
 			bootstrap.NewBootstrapCmd(conf),
+			console.NewCmdConsole(conf),
 			db.NewCmdDB(conf),
 			debug.NewCmdDebug(conf),
 			env.NewCmdEnv(conf),
+			errors.NewCmdErrors(conf),
+			fixpermissions.NewCmdFixPermissions(conf),
+			frontend.NewCmdFrontend(conf),
+			history.NewCmdHistory(conf),
+			nginx.NewCmdNginx(conf),
+			php.NewCmdPHP(conf),
+			reportlogs.NewCmdReportLogs(conf),
+			search.NewCmdSearch(conf),
 			shell.NewCmdShell(conf),
 			sync.NewCmdSync(conf),
+			traffic.NewCmdTraffic(conf),
+			upgradeenv.NewCmdUpgradeEnv(conf),
+			varnish.NewCmdVarnish(conf),
+			vendor.NewCmdVendor(conf),
+			wait.NewCmdWait(conf),
 		)
 	}
 
 	cmd.AddGroups("Global Commands:",
+		buildimages.NewCmdBuildImages(conf),
+		daemon.NewCmdDaemon(conf),
+		detect.NewCmdDetect(conf),
+		doctor.NewCmdDoctor(conf),
 		envinit.NewCmdEnvInit(conf),
+		home.NewCmdHome(conf),
 		info.NewCmdInfo(conf),
 		install.NewCmdInstall(conf),
 		selfupdate.NewCmdSelfUpdate(conf),
 		signcertificate.NewCmdSignCertificate(conf),
 		plugin.NewCmdPlugin(conf),
+		prefetch.NewCmdPrefetch(conf),
+		proxy.NewCmdProxy(conf),
+		status.NewCmdStatus(conf),
 		svc.NewCmdSvc(conf),
+		try.NewCmdTry(conf),
+		tunnel.NewCmdTunnel(conf),
+		undo.NewCmdUndo(conf),
+		whoami.NewCmdWhoami(conf),
 	)
 
+	if !conf.EnvInitialized() {
+		envCmd := env.NewCmdEnvGlobal(conf)
+		envCmd.GroupID = "Global Commands:"
+		cmd.AddCommands(envCmd)
+	}
+
 	cmd.AddCommands(
 		completion.NewCompletionCmd(conf),
 		version.NewCmdVersion(conf),
@@ -119,6 +178,7 @@ func NewCmdRoot(conf *config.Config) *cmdpkg.Command {
 	configurePlugins(cmd)
 	configureShortcuts(cmd)
 	configureHiddenCommands(cmd)
+	configureCommandFlags(cmd)
 
 	return cmd
 }
@@ -127,8 +187,8 @@ func configureFlags(cmd *cmdpkg.Command) {
 	// --app-dir
 	cmd.PersistentFlags().String(
 		"app-dir",
-		filepath.Join(util.HomeDir(), fmt.Sprintf(".%s", cmd.Config.AppName())),
-		"app home directory",
+		config.DefaultAppHomeDir(cmd.Config.AppName()),
+		"app home directory (or set the REWARD_HOME environment variable)",
 	)
 	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_home_dir", cmd.Config.AppName()),
 		cmd.PersistentFlags().Lookup("app-dir"))
@@ -145,23 +205,77 @@ func configureFlags(cmd *cmdpkg.Command) {
 	)
 	_ = cmd.Config.BindPFlag("debug", cmd.PersistentFlags().Lookup("debug"))
 
+	// --output
+	cmd.PersistentFlags().String(
+		"output", "text", "output format of the commands and the logs (options: text, json)",
+	)
+	_ = cmd.Config.BindPFlag("output", cmd.PersistentFlags().Lookup("output"))
+
 	// --assume-yes
 	cmd.PersistentFlags().BoolP(
 		"assume-yes", "y", false, "Automatic yes to prompts.",
 	)
 	_ = cmd.Config.BindPFlag("assume_yes", cmd.PersistentFlags().Lookup("assume-yes"))
 
+	// --no-color
+	cmd.PersistentFlags().Bool(
+		"no-color", false, "disable colors and emoji in output (or set the NO_COLOR environment variable)",
+	)
+	_ = cmd.Config.BindPFlag("no_color", cmd.PersistentFlags().Lookup("no-color"))
+
 	// --disable-colors
 	cmd.PersistentFlags().Bool(
 		"disable-colors", false, "disable colors in output",
 	)
+	_ = cmd.PersistentFlags().MarkDeprecated("disable-colors", "use --no-color instead")
 	_ = cmd.Config.BindPFlag("disable_colors", cmd.PersistentFlags().Lookup("disable-colors"))
 
+	// --no-ansi
+	cmd.PersistentFlags().Bool(
+		"no-ansi", false, "render the progress as plain log lines instead of spinners and progress bars",
+	)
+	_ = cmd.Config.BindPFlag("no_ansi", cmd.PersistentFlags().Lookup("no-ansi"))
+
+	// --skip-checks
+	cmd.PersistentFlags().Bool(
+		"skip-checks", false, "skip the preflight checks of the docker and docker-compose versions",
+	)
+	_ = cmd.Config.BindPFlag("skip_checks", cmd.PersistentFlags().Lookup("skip-checks"))
+
+	// --wait-lock
+	cmd.PersistentFlags().Bool(
+		"wait-lock", false, "wait for the other commands running on the same environment to finish instead of failing",
+	)
+	_ = cmd.Config.BindPFlag("wait_lock", cmd.PersistentFlags().Lookup("wait-lock"))
+
+	// --force
+	cmd.PersistentFlags().Bool(
+		"force", false, "overwrite the generated configuration files even if they were modified",
+	)
+	_ = cmd.Config.BindPFlag("force", cmd.PersistentFlags().Lookup("force"))
+
+	// --force-destructive
+	cmd.PersistentFlags().Bool(
+		"force-destructive", false, "run the commands which delete or replace data in read-only mode",
+	)
+
+	// --profile
+	cmd.PersistentFlags().Bool(
+		"profile", false, "print how long the phases of the command took (eg. rendering templates, docker compose)",
+	)
+	_ = cmd.Config.BindPFlag("profile", cmd.PersistentFlags().Lookup("profile"))
+
+	// --cpuprofile, --memprofile: they're read before the command line is parsed (see main)
+	cmd.PersistentFlags().String("cpuprofile", "", "write a cpu profile of the command to the file")
+	cmd.PersistentFlags().String("memprofile", "", "write a memory profile of the command to the file")
+	_ = cmd.PersistentFlags().MarkHidden("cpuprofile")
+	_ = cmd.PersistentFlags().MarkHidden("memprofile")
+
 	// --config
 	cmd.PersistentFlags().StringP(
 		"config",
 		"c",
-		filepath.Join(util.HomeDir(), fmt.Sprintf(".%s.yml", cmd.Config.AppName())),
+		config.DefaultConfigFile(cmd.Config.AppName()),
 		"config file",
 	)
 	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_config_file", cmd.Config.AppName()),
@@ -209,6 +323,14 @@ func configureHiddenCommands(cmd *cmdpkg.Command) {
 			}
 		}
 	}
+
+	if len(cmd.Config.ConsoleCommand()) == 0 {
+		for _, v := range cmd.Commands() {
+			if v.Name() == "console" {
+				v.Hidden = true
+			}
+		}
+	}
 }
 
 func configurePlugins(cmd *cmdpkg.Command) {
@@ -262,11 +384,89 @@ func configureShortcuts(cmd *cmdpkg.Command) {
 	cmd.AddGroups("Shortcuts:", sc...)
 }
 
+// configureCommandFlags inserts the default flags of the command from the configuration (eg. --wait for env up)
+// into the arguments right after the command, so the flags passed on the command line override them.
+func configureCommandFlags(cmd *cmdpkg.Command) {
+	if len(os.Args) < 2 {
+		return
+	}
+
+	if args := commandFlagsArgs(cmd, os.Args[1:]); args != nil {
+		cmd.SetArgs(args)
+	}
+}
+
+// commandFlagsArgs returns the arguments with the default flags of the command inserted, or nil if the command has
+// no default flags. The commands which pass their arguments to docker compose (eg. env, svc) don't have subcommands
+// for the docker compose commands, so the first positional argument (eg. up of env up) is part of the command path,
+// and the flags are inserted after it.
+func commandFlagsArgs(cmd *cmdpkg.Command, args []string) []string {
+	target, _, err := cmd.Command.Find(args)
+	if err != nil || target == cmd.Command {
+		return nil
+	}
+
+	var chain []*cobra.Command
+	for c := target; c != cmd.Command; c = c.Parent() {
+		chain = append([]*cobra.Command{c}, chain...)
+	}
+
+	// the arguments of the command path can be aliases, and the persistent flags can precede them
+	matched, end := 0, -1
+
+	for i := 0; i < len(args) && matched < len(chain); i++ {
+		if args[i] == chain[matched].Name() || chain[matched].HasAlias(args[i]) {
+			matched++
+			end = i
+		}
+	}
+
+	if matched < len(chain) {
+		return nil
+	}
+
+	commandPath := strings.TrimPrefix(target.CommandPath(), cmd.Command.Name()+" ")
+
+	if target.DisableFlagParsing {
+		passthrough := end
+
+		for i := end + 1; i < len(args); i++ {
+			if !strings.HasPrefix(args[i], "-") {
+				commandPath += " " + args[i]
+				passthrough = i
+
+				break
+			}
+		}
+
+		// the flags are not passed to docker compose as its global flags
+		if passthrough == end {
+			return nil
+		}
+
+		end = passthrough
+	}
+
+	flags := cmd.Config.CommandFlags(commandPath)
+	if len(flags) == 0 {
+		return nil
+	}
+
+	log.Debugf("Adding the default flags of %s: %s", commandPath, strings.Join(flags, " "))
+
+	return append(append(append([]string{}, args[:end+1]...), flags...), args[end+1:]...)
+}
+
 func validateFlags(cmd *cmdpkg.Command) error {
 	driver := cmd.Config.GetString(fmt.Sprintf("%s_driver", cmd.Config.AppName()))
 	if !regexp.MustCompile(`^docker-compose$`).MatchString(driver) {
 		return fmt.Errorf("invalid value for --driver: %s", driver)
 	}
 
+	output := cmd.Config.Output()
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid value for --output: %s", output)
+	}
+
 	return nil
 }
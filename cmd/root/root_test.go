@@ -0,0 +1,86 @@
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+)
+
+type RootTestSuite struct {
+	suite.Suite
+}
+
+func TestRootTestSuite(t *testing.T) {
+	suite.Run(t, new(RootTestSuite))
+}
+
+func (suite *RootTestSuite) TestCommandFlagsArgs() {
+	wd, err := os.Getwd()
+	assert.NoError(suite.T(), err)
+
+	dir := suite.T().TempDir()
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(dir, ".env"), []byte("REWARD_ENV_TYPE=magento2\n"), 0o600))
+	assert.NoError(suite.T(), os.Chdir(dir))
+
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	suite.T().Setenv("HOME", dir)
+
+	conf := config.New("reward", "0.0.1")
+	conf.Set("reward_command_flags", map[string]interface{}{
+		"env":     "--env-global",
+		"env up":  "--wait",
+		"db dump": []interface{}{"--compress", "gzip"},
+	})
+
+	defer conf.Set("reward_command_flags", nil)
+
+	cmd := NewCmdRoot(conf)
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "passthrough command",
+			args: []string{"env", "up", "-d"},
+			want: []string{"env", "up", "--wait", "-d"},
+		},
+		{
+			name: "passthrough command with flags",
+			args: []string{"--log-level", "debug", "env", "-d", "up"},
+			want: []string{"--log-level", "debug", "env", "-d", "up", "--wait"},
+		},
+		{
+			name: "passthrough command without compose command",
+			args: []string{"env"},
+		},
+		{
+			name: "other compose command",
+			args: []string{"env", "down"},
+		},
+		{
+			name: "subcommand",
+			args: []string{"db", "dump", "--compress", "zstd"},
+			want: []string{"db", "dump", "--compress", "gzip", "--compress", "zstd"},
+		},
+		{
+			name: "without default flags",
+			args: []string{"db", "import"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, commandFlagsArgs(cmd, tt.args))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdSearch(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "search [command]",
+			Short: "Manages the search engine of the environment",
+			Long:  `Manages the search engine (Elasticsearch or OpenSearch) of the environment`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running search command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdSearchMigrate(conf),
+	)
+
+	return cmd
+}
+
+func newCmdSearchMigrate(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "migrate",
+			Short: "Migrates the indices from Elasticsearch to OpenSearch",
+			Long: `Migrates the indices from Elasticsearch to OpenSearch and switches the environment to OpenSearch. The
+indices are snapshotted, OpenSearch is started and the snapshot is restored (Elasticsearch 7.10 and older) or the
+indices are reindexed from Elasticsearch (Elasticsearch 7.11 and newer). The Elasticsearch volume is kept.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client := logic.New(conf)
+
+				err := client.RunCmdSearchMigrate()
+				client.RecordHistory(cmd, args, err)
+
+				if err != nil {
+					return fmt.Errorf("error running search migrate command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
@@ -42,5 +42,16 @@ func NewCmdShell(conf *config.Config) *cmdpkg.Command {
 	cmd.Flags().StringVar(&conf.ShellUser, "user", "", "the user inside the container")
 	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_shell_user", conf.AppName()), cmd.Flags().Lookup("user"))
 
+	cmd.Flags().Bool("root", false, "launch the shell as root user (same as --user=root)")
+
+	cmd.Flags().String("shell", "", "the preferred shell (options: bash, zsh, sh), falls back to bash or sh")
+	_ = cmd.Config.BindPFlag(fmt.Sprintf("%s_shell", conf.AppName()), cmd.Flags().Lookup("shell"))
+	_ = cmd.RegisterFlagCompletionFunc(
+		"shell",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return config.ValidShells(), cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
 	return cmd
 }
@@ -0,0 +1,49 @@
+package status
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdStatus(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "status",
+			Short: "Shows the state of the environment and common service containers",
+			Long: `Shows the state of the environment and common service containers. With --watch it keeps running and
+prints the container events (start, die, oom, health status), warns when a container runs out of memory and
+reconnects the common services (eg. traefik) to the environment networks when they are recreated.
+
+In an environment directory it warns about the configured PHP, Elasticsearch and MariaDB versions which are past
+their end of life or not supported by the Magento version of the project. With --strict these are an error, so the
+command can be used in CI.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdStatus(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running status command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().BoolP("watch", "w", false, "keep watching the container events")
+	cmd.Flags().Bool("strict", false, "exit with an error if a service version is past its end of life or unsupported")
+
+	return cmd
+}
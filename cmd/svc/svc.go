@@ -12,7 +12,7 @@ import (
 )
 
 func NewCmdSvc(conf *config.Config) *cmdpkg.Command {
-	return &cmdpkg.Command{
+	cmd := &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:                "svc",
 			Short:              "Orchestrates global services such as traefik, portainer and dnsmasq via docker-compose",
@@ -30,4 +30,38 @@ func NewCmdSvc(conf *config.Config) *cmdpkg.Command {
 		},
 		Config: conf,
 	}
+
+	cmd.AddCommands(
+		newCmdSvcCredentials(conf),
+	)
+
+	return cmd
+}
+
+func newCmdSvcCredentials(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "credentials",
+			Short: "Prints the credentials of the traefik dashboard",
+			Long: `Prints the URL and the generated basic auth credentials of the traefik dashboard and API. The credentials
+are generated when the global services are brought up the first time.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdSvcCredentials()
+				if err != nil {
+					return fmt.Errorf("error running svc credentials command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
 }
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -51,6 +52,9 @@ func NewCmdSync(conf *config.Config) *cmdpkg.Command {
 		newCmdSyncResume(conf),
 		newCmdSyncReset(conf),
 		newCmdSyncTerminate(conf),
+		newCmdSyncBench(conf),
+		newCmdSyncConflicts(conf),
+		newCmdSyncSelfUpdate(conf),
 		// newCmdSyncDaemon(conf),
 	)
 
@@ -284,6 +288,99 @@ func newCmdSyncTerminate(conf *config.Config) *cmdpkg.Command {
 	}
 }
 
+func newCmdSyncSelfUpdate(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "self-update",
+			Short: "Installs the pinned version of the mutagen binary managed by reward",
+			Long: `Installs the pinned version (reward_mutagen_version) of the mutagen binary managed by reward. The
+mutagen daemon is stopped, the sync sessions have to be started again.`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdSyncSelfUpdate(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error updating mutagen: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("force", false, "reinstall mutagen even if the pinned version is installed")
+
+	return cmd
+}
+
+func newCmdSyncBench(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "bench",
+			Short: "Measures the file sync latency between the host and the containers",
+			Long: `Writes timestamped files on the host and measures when they appear inside the synced container (and vice
+versa), then reports the latency percentiles of both directions.`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdSyncBench(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error measuring sync latency: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Int("iterations", 20, "number of files synced in each direction")
+	cmd.Flags().Duration("timeout", 30*time.Second, "time to wait for a file to be synced")
+
+	return cmd
+}
+
+func newCmdSyncConflicts(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "conflicts",
+			Short: "Reviews and resolves the conflicts of the mutagen sync sessions",
+			Long: `Lists the conflicting changes of the host and the container, and asks which version is kept. The
+conflicts are only kept for review in two-way-safe sync mode (reward_sync_mode), in the other modes they're resolved by
+mutagen.`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdSyncConflicts(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error resolving sync conflicts: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().Bool("list", false, "only list the conflicts")
+	cmd.Flags().String("keep", "", "resolve all the conflicts without asking by keeping the host or the container version")
+
+	return cmd
+}
+
 // TODO
 // func newCmdSyncDaemon(c *config.Config) *cmdpkg.Command {
 // 	cmd := &cmdpkg.Command{
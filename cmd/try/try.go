@@ -0,0 +1,50 @@
+package try
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdTry(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "try [type]",
+			Short: "Provisions a throwaway environment to try a platform",
+			Long: `Provisions a throwaway environment with sample data in a temporary directory and prints its URL and admin
+credentials. The environments (including their containers, volumes and directories) are removed with --cleanup.`,
+			Example: `  reward try magento2 --version 2.4.7
+  reward try --cleanup`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				if len(args) == 0 {
+					return []string{"magento2"}, cobra.ShellCompDirectiveNoFileComp
+				}
+
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdTry(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				if err != nil {
+					return fmt.Errorf("error running try command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("version", "", "version of the platform (default: the latest supported version)")
+	cmd.Flags().Bool("cleanup", false, "remove the throwaway environments")
+
+	return cmd
+}
@@ -0,0 +1,92 @@
+package undo
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdUndo(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "undo [command]",
+			Short: "Restores the data removed or overwritten by the destructive operations",
+			Long: `Restores the data removed or overwritten by the destructive operations from the trash in the home
+directory: the volumes removed by env down --volumes and the modified generated files overwritten with --force. The
+operations are kept for reward_trash_retention days, and the oldest ones are removed if the trash is larger than
+reward_trash_max_size.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running undo command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdUndoLast(conf),
+		newCmdUndoList(conf),
+	)
+
+	return cmd
+}
+
+func newCmdUndoLast(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "last",
+			Short: "Restores the data removed or overwritten by the most recent destructive operation",
+			Long: `Restores the data removed or overwritten by the most recent destructive operation. The volumes are
+created again if they don't exist, they cannot be restored while a container uses them.`,
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdUndoLast()
+				if err != nil {
+					return fmt.Errorf("error running undo last command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	return cmd
+}
+
+func newCmdUndoList(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:     "list",
+			Aliases: []string{"ls"},
+			Short:   "Lists the destructive operations which can be undone",
+			Args:    cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdUndoList()
+				if err != nil {
+					return fmt.Errorf("error running undo list command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	return cmd
+}
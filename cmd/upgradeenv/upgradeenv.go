@@ -0,0 +1,49 @@
+package upgradeenv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdUpgradeEnv(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "upgrade-env <version>",
+			Short: "Upgrades Magento to the given version in a clone of the environment",
+			Long: `Runs the recommended Magento upgrade workflow in a clone of the environment: the environment is cloned,
+the service versions required by the target version are applied to the clone, the Magento metapackage constraint is
+bumped, and composer update, setup:upgrade and setup:di:compile are run in the clone. The incompatible packages and
+the compilation errors are reported. The environment itself is not changed, the clone can be promoted with
+env promote when the upgrade is finished.`,
+			Example: `  reward upgrade-env 2.4.7
+  reward upgrade-env 2.4.7-p1 --name shop-next --dir ../shop-next`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdUpgradeEnv(&cmdpkg.Command{Command: cmd, Config: conf}, args)
+				if err != nil {
+					return fmt.Errorf("error running upgrade-env command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().String("name", "", "name of the clone (default: the environment name suffixed with the version)")
+	cmd.Flags().String("dir", "", "directory of the clone (default: a sibling directory named after the clone)")
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+package varnish
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdVarnish(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "varnish [command]",
+			Short: "Interacts with the varnish service on an environment",
+			Long:  `Interacts with the varnish service on an environment`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				if !conf.SvcEnabledStrict("varnish") || !conf.Docker.ContainerRunning("varnish") {
+					return docker.ErrCannotFindContainer("varnish", nil)
+				}
+
+				return nil
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := cmd.Help()
+				if err != nil {
+					return fmt.Errorf("error running varnish command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.AddCommands(
+		newCmdVarnishReload(conf),
+	)
+
+	return cmd
+}
+
+func newCmdVarnishReload(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "reload",
+			Short: "Recompiles and loads the VCL (including the project snippets) without recreating the container",
+			Long:  `Recompiles and loads the VCL (including the project snippets) without recreating the container`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdVarnishReload()
+				if err != nil {
+					return fmt.Errorf("error running varnish reload command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
@@ -9,17 +9,21 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	"github.com/rewardenv/reward/cmd"
+	cmdpkg "github.com/rewardenv/reward/cmd"
 	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
 	"github.com/rewardenv/reward/internal/shell"
 )
 
-func NewCmdVersion(conf *config.Config) *cmd.Command {
-	versionCmd := &cmd.Command{
+func NewCmdVersion(conf *config.Config) *cmdpkg.Command {
+	versionCmd := &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:   "version",
 			Short: "Print the version information",
-			Long:  fmt.Sprintf(`Print the version information for the %s application.`, conf.AppName()),
+			Long: fmt.Sprintf(
+				`Print the version information for the %s application, docker, docker-compose, mutagen and the installed
+plugins. Attach the output to the bug reports.`, conf.AppName(),
+			),
 			ValidArgsFunction: func(
 				cmd *cobra.Command,
 				args []string,
@@ -27,8 +31,20 @@ func NewCmdVersion(conf *config.Config) *cmd.Command {
 			) ([]string, cobra.ShellCompDirective) {
 				return nil, cobra.ShellCompDirectiveNoFileComp
 			},
-			Run: func(cmd *cobra.Command, args []string) {
-				NewCmdVersionApp(conf).Run(cmd, []string{})
+			RunE: func(cmd *cobra.Command, args []string) error {
+				short, _ := cmd.Flags().GetBool("short")
+				if short {
+					NewCmdVersionApp(conf).Run(cmd, []string{})
+
+					return nil
+				}
+
+				err := logic.New(conf).RunCmdVersion(&cmdpkg.Command{Command: cmd})
+				if err != nil {
+					return fmt.Errorf("error running version command: %w", err)
+				}
+
+				return nil
 			},
 		},
 		Config: conf,
@@ -36,6 +52,7 @@ func NewCmdVersion(conf *config.Config) *cmd.Command {
 
 	// version
 	versionCmd.Flags().BoolP("short", "s", false, "Print version only")
+	versionCmd.Flags().Bool("json", false, "Print the version information as JSON")
 
 	appVersionCmd := NewCmdVersionApp(conf)
 	appVersionCmd.Flags().BoolP("short", "s", false, "Print version only")
@@ -58,8 +75,8 @@ func NewCmdVersion(conf *config.Config) *cmd.Command {
 	return versionCmd
 }
 
-func NewCmdVersionApp(conf *config.Config) *cmd.Command {
-	return &cmd.Command{
+func NewCmdVersionApp(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:   conf.AppName(),
 			Short: fmt.Sprintf("Print the version information for %s", conf.AppName()),
@@ -89,16 +106,20 @@ func NewCmdVersionApp(conf *config.Config) *cmd.Command {
 	}
 }
 
-func NewCmdVersionDocker(conf *config.Config) *cmd.Command {
-	return &cmd.Command{
+func NewCmdVersionDocker(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:   "docker",
 			Short: "Print the version information for docker",
 			Long:  `Print the version information for Docker installed on your system.`,
-			Run: func(cmd *cobra.Command, args []string) {
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if conf.Docker == nil {
+					return fmt.Errorf("error running version docker command: %w", conf.InitErr())
+				}
+
 				data, err := conf.Docker.ServerVersion(context.Background())
 				if err != nil {
-					log.Panicln(err)
+					return fmt.Errorf("error running version docker command: %w", err)
 				}
 
 				shortDockerVersion, _ := cmd.Flags().GetBool("short-docker-version")
@@ -121,25 +142,27 @@ func NewCmdVersionDocker(conf *config.Config) *cmd.Command {
 					log.Printf("docker API version: %s\n", data.APIVersion)
 					log.Printf("docker platform: %s\n", data.Platform.Name)
 				}
+
+				return nil
 			},
 		},
 		Config: conf,
 	}
 }
 
-func NewCmdVersionDockerCompose(conf *config.Config) *cmd.Command {
-	return &cmd.Command{
+func NewCmdVersionDockerCompose(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
 		Command: &cobra.Command{
 			Use:   "docker-compose",
 			Short: "Print the version information for docker-compose",
 			Long:  `Print the version information for docker-compose installed on your system.`,
-			Run: func(cmd *cobra.Command, args []string) {
+			RunE: func(cmd *cobra.Command, args []string) error {
 				out, err := conf.DockerCompose.RunCommand([]string{"version", "--short"},
 					shell.WithCatchOutput(true),
 					shell.WithSuppressOutput(true),
 				)
 				if err != nil {
-					log.Panicln(err)
+					return fmt.Errorf("error running version docker-compose command: %w", err)
 				}
 
 				short, _ := cmd.Flags().GetBool("short")
@@ -147,10 +170,12 @@ func NewCmdVersionDockerCompose(conf *config.Config) *cmd.Command {
 					//nolint:forbidigo
 					fmt.Printf("%s\n", strings.TrimSpace(string(out)))
 
-					return
+					return nil
 				}
 
 				log.Printf("docker-compose version: %s", strings.TrimSpace(string(out)))
+
+				return nil
 			},
 		},
 		Config: conf,
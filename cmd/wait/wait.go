@@ -0,0 +1,46 @@
+package wait
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdWait(conf *config.Config) *cmdpkg.Command {
+	cmd := &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "wait",
+			Short: "Waits until the services of the environment are ready",
+			Long: `Waits until the services of the environment (or the services passed using --for) are ready. The database,
+Elasticsearch, OpenSearch, Redis and RabbitMQ are ready when they accept connections, the other services when their
+container is running and healthy. It exits with 124 on timeout and with 3 if a service doesn't exist.`,
+			ValidArgsFunction: func(
+				cmd *cobra.Command,
+				args []string,
+				toComplete string,
+			) ([]string, cobra.ShellCompDirective) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdWait(&cmdpkg.Command{Command: cmd, Config: conf})
+				if err != nil {
+					return fmt.Errorf("error running wait command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+
+	cmd.Flags().StringSlice("for", nil, "the services to wait for, eg. db,elasticsearch (default: all services)")
+	cmd.Flags().Duration("timeout", 2*time.Minute, "the time the services have to become ready")
+
+	return cmd
+}
@@ -0,0 +1,36 @@
+package whoami
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+func NewCmdWhoami(conf *config.Config) *cmdpkg.Command {
+	return &cmdpkg.Command{
+		Command: &cobra.Command{
+			Use:   "whoami",
+			Short: "Prints the user and namespace used for naming the environments",
+			Long:  `Prints the user and namespace used for naming the environments`,
+			ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) (
+				[]string, cobra.ShellCompDirective,
+			) {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			},
+			Args: cobra.ExactArgs(0),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				err := logic.New(conf).RunCmdWhoami()
+				if err != nil {
+					return fmt.Errorf("error running whoami command: %w", err)
+				}
+
+				return nil
+			},
+		},
+		Config: conf,
+	}
+}
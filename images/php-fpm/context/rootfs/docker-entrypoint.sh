@@ -1,6 +1,21 @@
 #!/bin/bash
 set -e
 
+# Remap the www-data user to the IDs of the host user (REWARD_UID and REWARD_GID), so the files created in the
+# bind mounted project directory are owned by the host user on Linux. The entrypoint is executed again as the
+# remapped user, because the IDs of the running process cannot be changed.
+WWWDATA_UID="$(id -u www-data)"
+WWWDATA_GID="$(id -g www-data)"
+if [ "$(id -un)" = "www-data" ] && command -v sudo >/dev/null 2>&1 &&
+  [ "${REWARD_UID:-0}" != "0" ] && [ "${REWARD_GID:-0}" != "0" ] &&
+  { [ "${REWARD_UID}" != "${WWWDATA_UID}" ] || [ "${REWARD_GID}" != "${WWWDATA_GID}" ]; }; then
+  sudo groupmod -o -g "${REWARD_GID}" www-data
+  sudo usermod -o -u "${REWARD_UID}" -g "${REWARD_GID}" www-data
+  sudo find /home/www-data /etc/php /var/lib/php /var/log -xdev \
+    \( -user "${WWWDATA_UID}" -o -group "${WWWDATA_GID}" \) -exec chown -h www-data:www-data {} + 2>/dev/null || true
+  exec sudo -E -H -u www-data "$0" "$@"
+fi
+
 # PHP
 PHP_PREFIX="/etc/php"
 PHP_PREFIX_LONG="${PHP_PREFIX}/${PHP_VERSION}"
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ComposerPackages contains the root package and the required and locked packages of a composer project.
+type ComposerPackages struct {
+	Name       string
+	Version    string
+	Require    map[string]string
+	RequireDev map[string]string
+	// Locked contains the exact versions of the packages installed from composer.lock.
+	Locked map[string]string
+}
+
+//nolint:tagliatelle
+type composerJSON struct {
+	Name       string            `json:"name"`
+	Version    string            `json:"version"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+//nolint:tagliatelle
+type composerLock struct {
+	Packages []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"packages"`
+	PackagesDev []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"packages-dev"`
+}
+
+// ComposerPackages returns the composer packages of the project in the web root. It returns nil if the project
+// doesn't have a composer.json file.
+func (c *Config) ComposerPackages() (*ComposerPackages, error) {
+	return ReadComposerPackages(filepath.Join(c.Cwd(), c.WebRoot()))
+}
+
+// ReadComposerPackages reads composer.json and composer.lock (if exists) from the given directory. It returns nil
+// if the directory doesn't contain a composer.json file.
+func ReadComposerPackages(dir string) (*ComposerPackages, error) {
+	jsonPath := filepath.Join(dir, "composer.json")
+	if !util.FileExists(jsonPath) {
+		return nil, nil
+	}
+
+	data, err := FS.ReadFile(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", jsonPath, err)
+	}
+
+	var composer composerJSON
+
+	err = json.Unmarshal(data, &composer)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal %s: %w", jsonPath, err)
+	}
+
+	pkgs := &ComposerPackages{
+		Name:       composer.Name,
+		Version:    composer.Version,
+		Require:    composer.Require,
+		RequireDev: composer.RequireDev,
+		Locked:     make(map[string]string),
+	}
+
+	if pkgs.Require == nil {
+		pkgs.Require = make(map[string]string)
+	}
+
+	if pkgs.RequireDev == nil {
+		pkgs.RequireDev = make(map[string]string)
+	}
+
+	lockPath := filepath.Join(dir, "composer.lock")
+	if !util.FileExists(lockPath) {
+		return pkgs, nil
+	}
+
+	data, err = FS.ReadFile(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", lockPath, err)
+	}
+
+	var lock composerLock
+
+	// An invalid lock file (eg. during a merge conflict) shouldn't prevent using the constraints from composer.json.
+	err = json.Unmarshal(data, &lock)
+	if err != nil {
+		log.Debugf("Cannot unmarshal %s, ignoring it: %s", lockPath, err)
+
+		return pkgs, nil
+	}
+
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		pkgs.Locked[p.Name] = strings.TrimPrefix(p.Version, "v")
+	}
+
+	return pkgs, nil
+}
+
+// Find returns the first of the given packages which is installed or required, and its version. The locked
+// version is returned if the package is in composer.lock, otherwise the version constraint from composer.json.
+func (p *ComposerPackages) Find(names ...string) (name, ver string, locked, ok bool) {
+	for _, name := range names {
+		if v, ok := p.Locked[name]; ok {
+			return name, v, true, true
+		}
+
+		if v, ok := p.Require[name]; ok {
+			return name, v, false, true
+		}
+	}
+
+	return "", "", false, false
+}
+
+// ResolveVersionConstraint returns the lowest version mentioned in a composer version constraint which satisfies
+// the constraint (eg. "~2.4.5" returns 2.4.5, ">=2.4 <2.5" returns 2.4.0, "2.4.6-p3" returns 2.4.6-p3).
+// If none of them satisfies the constraint, the lowest mentioned version is returned.
+func ResolveVersionConstraint(constraint string) (*version.Version, error) {
+	candidates := versionCandidates(constraint)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no valid version found in constraint: %s", constraint)
+	}
+
+	for _, candidate := range candidates {
+		if VersionSatisfiesConstraint(candidate, constraint) {
+			return candidate, nil
+		}
+	}
+
+	return candidates[0], nil
+}
+
+// VersionSatisfiesConstraint returns true if the version satisfies the composer version constraint. Patch
+// versions (eg. 2.4.6-p3) are compared by their major.minor.patch part. It returns false if the constraint cannot
+// be parsed.
+func VersionSatisfiesConstraint(v *version.Version, constraint string) bool {
+	constraints, err := semver.NewConstraint(normalizeComposerConstraint(constraint))
+	if err != nil {
+		return false
+	}
+
+	sv, err := semver.NewVersion(v.Core().String())
+	if err != nil {
+		return false
+	}
+
+	return constraints.Check(sv)
+}
+
+// versionCandidates returns the versions mentioned in the constraint padded to major.minor.patch in ascending order.
+func versionCandidates(constraint string) []*version.Version {
+	// Numbers which are part of a pre-release suffix (eg. the 1 in 2.4.7-beta1) are not versions.
+	re := regexp.MustCompile(`(?:^|[^a-zA-Z0-9.\-])(\d+(?:\.\d+){0,2}(?:-p\.?\d+)?)`)
+
+	var candidates []*version.Version
+
+	for _, m := range re.FindAllStringSubmatch(constraint, -1) {
+		core, patch, _ := strings.Cut(m[1], "-")
+		for strings.Count(core, ".") < 2 {
+			core += ".0"
+		}
+
+		if patch != "" {
+			core += "-" + patch
+		}
+
+		v, err := version.NewVersion(core)
+		if err != nil {
+			continue
+		}
+
+		candidates = append(candidates, v)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LessThan(candidates[j])
+	})
+
+	return candidates
+}
+
+// normalizeComposerConstraint converts a composer version constraint to the syntax of the semver package.
+// Composer separates AND constraints by spaces or commas and OR constraints by "|" or "||", and it allows
+// stability flags (eg. @dev).
+func normalizeComposerConstraint(constraint string) string {
+	constraint = regexp.MustCompile(`@[a-zA-Z]+`).ReplaceAllString(constraint, "")
+	constraint = regexp.MustCompile(`(>=|<=|!=|>|<|=|~|\^)\s+`).ReplaceAllString(constraint, "$1")
+
+	parts := regexp.MustCompile(`\s*\|\|?\s*`).Split(strings.TrimSpace(constraint), -1)
+	for i, part := range parts {
+		parts[i] = strings.Join(regexp.MustCompile(`\s*,\s*|\s+`).Split(part, -1), ", ")
+	}
+
+	return strings.Join(parts, " || ")
+}
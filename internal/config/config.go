@@ -3,19 +3,19 @@ package config
 import (
 	"container/list"
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/Masterminds/semver"
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-units"
 	"github.com/hashicorp/go-version"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -25,6 +25,7 @@ import (
 	"github.com/rewardenv/reward/internal/docker"
 	"github.com/rewardenv/reward/internal/dockercompose"
 	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/internal/trash"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
@@ -33,6 +34,8 @@ var (
 	ErrEnvNameIsInvalid = fmt.Errorf("environment name is invalid, it should match RFC1178")
 	// ErrEnvIsEmpty occurs when environment name is empty.
 	ErrEnvIsEmpty = fmt.Errorf("env name is empty. please run `reward env-init`")
+	// ErrHomeDirUnknown occurs when the home directory of the user cannot be determined (eg. $HOME is not set).
+	ErrHomeDirUnknown = fmt.Errorf("cannot determine the home directory, please set $HOME")
 	// ErrUnknownAction occurs when an unknown actions is called.
 	ErrUnknownAction = fmt.Errorf("unknown action error")
 
@@ -50,8 +53,65 @@ var (
 
 	// ErrUnknownEnvType occurs when an unknown environment type is specified.
 	ErrUnknownEnvType = fmt.Errorf("unknown env type")
+
+	// ErrUnknownMagentoType occurs when an unknown Magento type is specified.
+	ErrUnknownMagentoType = fmt.Errorf("unknown magento type, valid options: community, enterprise, mage-os")
+
+	// ErrInvalidShell occurs when an unsupported shell is selected.
+	ErrInvalidShell = fmt.Errorf("invalid shell, valid options: bash, zsh, sh")
+
+	// ErrTraefikHTTP3NotSupported occurs when HTTP/3 is enabled but the configured traefik version doesn't support it.
+	ErrTraefikHTTP3NotSupported = func(v string) error {
+		return fmt.Errorf(
+			"HTTP/3 requires traefik 2.6 or newer, but reward_traefik_version is %s. "+
+				"Set reward_traefik_version to 2.6 or newer, or disable reward_traefik_http3", v,
+		)
+	}
+
+	// ErrTraefikRollingRestartHTTP3 occurs when HTTP/3 and the rolling restart of traefik are enabled together. The
+	// port proxy forwards only TCP connections.
+	ErrTraefikRollingRestartHTTP3 = fmt.Errorf(
+		"HTTP/3 is not supported with reward_traefik_rolling_restart. Disable one of them",
+	)
+
+	// ErrUnknownContainerUI occurs when the configured container management UI is not supported.
+	ErrUnknownContainerUI = func(ui string) error {
+		return fmt.Errorf("unknown container ui: %s, valid options: portainer, yacht, none", ui)
+	}
+
+	// ErrUnknownDNSServer occurs when the configured DNS server of the common services is not supported.
+	ErrUnknownDNSServer = func(server string) error {
+		return fmt.Errorf("unknown dns server: %s, valid options: dnsmasq, coredns", server)
+	}
+
+	// ErrInvalidGlitchTipDSN occurs when the GlitchTip DSN of the environment is not a valid DSN.
+	ErrInvalidGlitchTipDSN = func(dsn string) error {
+		return fmt.Errorf("invalid glitchtip dsn: %s, it should look like https://<key>@glitchtip.reward.test/<project>", dsn)
+	}
+
+	// ErrUnknownSyncMode occurs when the configured mutagen sync mode is not supported.
+	ErrUnknownSyncMode = func(mode string) error {
+		return fmt.Errorf(
+			"unknown sync mode: %s, valid options: two-way-safe, two-way-resolved, one-way-safe, one-way-replica", mode,
+		)
+	}
+
+	// ErrOffline occurs when a network resource is requested in offline mode.
+	ErrOffline = fmt.Errorf("offline mode is enabled, network access is disabled")
+
+	// ErrReadOnly occurs when a destructive command is called in read-only mode without --force-destructive.
+	ErrReadOnly = func(command string) error {
+		return fmt.Errorf(
+			"`%s` is refused in read-only mode as it deletes or replaces data, use --force-destructive to run it anyway",
+			command,
+		)
+	}
 )
 
+// ConfigSchemaVersion is the version of the format of the configuration file and the settings. It's increased when
+// the settings are renamed or their meaning is changed.
+const ConfigSchemaVersion = 1
+
 // FS is the implementation of Afero Filesystem. It's a filesystem wrapper and used for testing.
 var FS = &afero.Afero{Fs: afero.NewOsFs()}
 
@@ -64,6 +124,21 @@ type Config struct {
 	ShellContainer      string
 	DefaultShellCommand string
 	TmpFiles            *list.List
+
+	// initErr is the error which occurred while initializing the configuration (eg. the docker client cannot be
+	// created). It's returned by Check, so the commands which don't need docker (eg. version) still work.
+	initErr error
+	// cwd is the working directory resolved by Init.
+	cwd string
+	// locks are the locks acquired by Lock, they're released by Cleanup.
+	locks []*util.FileLock
+	// envFile is the content of the .env file read by Init, and envFileGenerated is the content with the generated
+	// values of the ${random:N} references, it's written by PersistEnvFileReferences.
+	envFile, envFileGenerated string
+
+	// settings is the typed copy of the settings read on the hot paths, see settings.go.
+	settings   *settings
+	settingsMu sync.Mutex
 }
 
 func New(name, ver string) *Config {
@@ -80,6 +155,19 @@ func New(name, ver string) *Config {
 }
 
 func (c *Config) Init() *Config {
+	c.initErr = nil
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		c.initErr = fmt.Errorf("cannot determine the working directory: %w", err)
+	}
+
+	c.cwd = cwd
+
+	if util.HomeDir() == "" {
+		c.initErr = ErrHomeDirUnknown
+	}
+
 	c.AddConfigPath(".")
 
 	cfg := c.GetString(fmt.Sprintf("%s_config_file", c.AppName()))
@@ -104,7 +192,19 @@ func (c *Config) Init() *Config {
 		log.Debugf("%s", err)
 	}
 
+	if err := c.resolveEnvFileReferences(); err != nil {
+		log.Warnf("%s", err)
+	}
+
+	c.SetDefault(fmt.Sprintf("%s_shared_mode", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_namespace", c.AppName()), namespaceFromUsername(util.Username()))
+
+	if c.SharedMode() {
+		c.applyNamespace()
+	}
+
 	c.SetDefault("silence_errors", true)
+	c.SetDefault(fmt.Sprintf("%s_home_dir", c.AppName()), DefaultAppHomeDir(c.AppName()))
 	c.SetDefault(fmt.Sprintf("%s_ssl_dir", c.AppName()), filepath.Join(c.AppHomeDir(), "ssl"))
 	c.SetDefault(fmt.Sprintf("%s_composer_dir", c.AppName()), filepath.Join(util.HomeDir(), ".composer"))
 	c.SetDefault(fmt.Sprintf("%s_ssh_dir", c.AppName()), filepath.Join(util.HomeDir(), ".ssh"))
@@ -122,6 +222,10 @@ func (c *Config) Init() *Config {
 	c.SetDefault(fmt.Sprintf("%s_ssl_cert_dir", c.AppName()), filepath.Join(c.SSLDir(), c.SSLCertBaseDir()))
 	c.SetDefault(fmt.Sprintf("%s_resolve_domain_to_traefik", c.AppName()), true)
 	c.SetDefault(fmt.Sprintf("%s_traefik_allow_http", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_traefik_access_log", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_traefik_http3", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_traefik_version", c.AppName()), "2.2")
+	c.SetDefault(fmt.Sprintf("%s_traefik_dashboard", c.AppName()), true)
 
 	c.SetDefault(
 		fmt.Sprintf("%s_services", c.AppName()), []string{
@@ -136,6 +240,7 @@ func (c *Config) Init() *Config {
 	c.SetDefault(
 		fmt.Sprintf("%s_optional_services", c.AppName()), []string{
 			"adminer",
+			"glitchtip",
 		},
 	)
 
@@ -164,10 +269,8 @@ func (c *Config) Init() *Config {
 	)
 
 	// Sync
-	c.SetDefault(
-		fmt.Sprintf("%s_mutagen_url", c.AppName()),
-		"https://github.com/mutagen-io/mutagen/releases/download/v0.14.0/mutagen_windows_amd64_v0.14.0.zip",
-	)
+	c.SetDefault(fmt.Sprintf("%s_mutagen_managed", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_mutagen_version", c.AppName()), "0.17.5")
 	c.SetDefault(fmt.Sprintf("%s_mutagen_required_version", c.AppName()), "0.11.8")
 
 	if util.OSDistro() == "windows" || util.OSDistro() == "darwin" {
@@ -182,6 +285,7 @@ func (c *Config) Init() *Config {
 	c.SetDefault(fmt.Sprintf("%s_tunnel", c.AppName()), true)
 	c.SetDefault(fmt.Sprintf("%s_elastichq", c.AppName()), true)
 	c.SetDefault(fmt.Sprintf("%s_adminer", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_glitchtip", c.AppName()), false)
 
 	// Env Defaults
 	c.SetDefault(fmt.Sprintf("%s_env_synced_container", c.AppName()), "php-fpm")
@@ -198,11 +302,14 @@ func (c *Config) Init() *Config {
 	c.SetDefault(fmt.Sprintf("%s_magento_disable_tfa", c.AppName()), false)
 	c.SetDefault(fmt.Sprintf("%s_reset_admin_url", c.AppName()), false)
 
-	if c.EnvType() == "magento1" {
-		c.SetDefault(fmt.Sprintf("%s_magento_version", c.AppName()), "1.9.4")
-	} else {
-		c.SetDefault(fmt.Sprintf("%s_magento_version", c.AppName()), "2.4.5-p1")
-	}
+	c.SetDefault(
+		fmt.Sprintf("%s_version_matrix_url", c.AppName()),
+		"https://raw.githubusercontent.com/rewardenv/reward/main/assets/versions/versions.json",
+	)
+	c.SetDefault(fmt.Sprintf("%s_version_matrix_refresh_interval", c.AppName()), "24h")
+	c.SetDefault(fmt.Sprintf("%s_version_matrix_public_key", c.AppName()), defaultVersionMatrixPublicKey)
+
+	c.ApplyVersionMatrixDefaults()
 
 	c.SetDefault(fmt.Sprintf("%s_magento_type", c.AppName()), "community")
 	c.SetDefault(fmt.Sprintf("%s_magento_mode", c.AppName()), "developer")
@@ -210,20 +317,66 @@ func (c *Config) Init() *Config {
 	c.SetDefault(fmt.Sprintf("%s_crypt_key", c.AppName()), "")
 	c.SetDefault(fmt.Sprintf("%s_shopware_version", c.AppName()), "6.4.18.0")
 	c.SetDefault(fmt.Sprintf("%s_shopware_mode", c.AppName()), "production")
+	c.SetDefault(fmt.Sprintf("%s_sylius_version", c.AppName()), "1.12")
+	c.SetDefault(fmt.Sprintf("%s_akeneo_version", c.AppName()), "7.0")
+	c.SetDefault(fmt.Sprintf("%s_oro_version", c.AppName()), "5.1")
+
+	c.SetDefault(fmt.Sprintf("%s_build_images_platforms", c.AppName()), "linux/amd64,linux/arm64")
 
 	c.SetDefault(fmt.Sprintf("%s_env_db_command", c.AppName()), "mysql")
 	c.SetDefault(fmt.Sprintf("%s_env_db_dump_command", c.AppName()), "mysqldump")
 	c.SetDefault(fmt.Sprintf("%s_env_db_container", c.AppName()), "db")
+	c.SetDefault(fmt.Sprintf("%s_db_ready_timeout", c.AppName()), 2*time.Minute)
+	c.SetDefault(fmt.Sprintf("%s_notify_after", c.AppName()), time.Minute)
+	c.SetDefault(fmt.Sprintf("%s_trash_retention", c.AppName()), 7)
+	c.SetDefault(fmt.Sprintf("%s_trash_max_size", c.AppName()), "10GiB")
+	c.SetDefault(fmt.Sprintf("%s_daemon_address", c.AppName()), "127.0.0.1:7474")
+	c.SetDefault(fmt.Sprintf("%s_offline", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_bandwidth_limit", c.AppName()), "")
+	c.SetDefault(fmt.Sprintf("%s_pull_concurrency", c.AppName()), 0)
 	c.SetDefault(fmt.Sprintf("%s_single_web_container", c.AppName()), false)
 
+	// Bind mounts are only affected on Linux, other systems use the image's default IDs (eg. for mutagen).
+	if runtime.GOOS == "linux" {
+		c.SetDefault(fmt.Sprintf("%s_uid", c.AppName()), util.UID())
+		c.SetDefault(fmt.Sprintf("%s_gid", c.AppName()), util.GID())
+	} else {
+		c.SetDefault(fmt.Sprintf("%s_uid", c.AppName()), 1000)
+		c.SetDefault(fmt.Sprintf("%s_gid", c.AppName()), 1000)
+	}
+
+	c.SetDefault("nginx_custom_configs_path", fmt.Sprintf(".%s/nginx", c.AppName()))
+	c.SetDefault("nginx_overlay_path", fmt.Sprintf(".%s/nginx.d", c.AppName()))
+	c.SetDefault("varnish_custom_configs_path", fmt.Sprintf(".%s/varnish", c.AppName()))
+	c.SetDefault(fmt.Sprintf("%s_nginx_gzip", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_nginx_brotli", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_nginx_hsts", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_nginx_csp", c.AppName()), "")
+	c.SetDefault(fmt.Sprintf("%s_nginx_csp_report_only", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_nginx_real_ip", c.AppName()), false)
+	c.SetDefault(
+		fmt.Sprintf("%s_nginx_real_ip_from", c.AppName()),
+		[]string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
+	)
+
 	c.SetLogging()
 
-	c.Docker = docker.Must(docker.NewClient(c.DockerHost()))
+	dockerClient, err := docker.NewClient(c.DockerHost())
+	if err != nil && c.initErr == nil {
+		c.initErr = err
+	}
+
+	c.Docker = dockerClient
 	c.DockerCompose = dockercompose.NewClient(c.Shell, c.TmpFiles)
 
 	return c
 }
 
+// InitErr returns the error which occurred while initializing the configuration, the commands cannot run if it's set.
+func (c *Config) InitErr() error {
+	return c.initErr
+}
+
 // SetLogging sets the logging level based on the command line flags and environment variables.
 func (c *Config) SetLogging() {
 	switch {
@@ -243,11 +396,17 @@ func (c *Config) SetLogging() {
 		log.SetLevel(log.ErrorLevel)
 	}
 
+	if c.JSONOutput() {
+		log.SetFormatter(&log.JSONFormatter{})
+
+		return
+	}
+
 	log.SetFormatter(
 		&log.TextFormatter{
-			DisableColors:          c.GetBool("disable_colors"),
+			// the colors are used only if the log output is a terminal
+			DisableColors:          c.NoColor(),
 			PadLevelText:           true,
-			ForceColors:            true,
 			DisableLevelTruncation: true,
 			FullTimestamp:          true,
 			DisableTimestamp:       !c.GetBool("debug"),
@@ -274,27 +433,32 @@ func (c *Config) SilenceErrors() bool {
 }
 
 func (c *Config) Check(cmd *cobra.Command, args []string) error {
-	if cmd.Name() == "self-update" || cmd.Name() == "version" || cmd.Name() == "completion" || cmd.Name() == "help" {
+	if cmd.Name() == "self-update" || cmd.Name() == "version" || cmd.Name() == "completion" || cmd.Name() == "help" ||
+		cmd.Name() == "doctor" {
 		return nil
 	}
 
+	if c.initErr != nil {
+		return c.initErr
+	}
+
 	err := c.CheckInvokerUser(cmd)
 	if err != nil {
 		return fmt.Errorf("error checking invoker user: %w", err)
 	}
 
-	if !c.Installed() && cmd.Name() != "install" {
-		return fmt.Errorf("reward is not installed")
+	err = c.CheckReadOnly(cmd, args)
+	if err != nil {
+		return err
 	}
 
-	err = c.Docker.Check()
-	if err != nil {
-		return fmt.Errorf("error checking docker: %w", err)
+	if !c.Installed() && cmd.Name() != "install" {
+		return fmt.Errorf("reward is not installed")
 	}
 
-	err = c.DockerCompose.Check()
+	err = c.Preflight()
 	if err != nil {
-		return fmt.Errorf("error checking docker-compose: %w", err)
+		return fmt.Errorf("error running preflight checks: %w", err)
 	}
 
 	err = c.EnvCheck()
@@ -302,15 +466,20 @@ func (c *Config) Check(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error checking env: %w", err)
 	}
 
-	return nil
+	return c.Lock(cmd, args)
 }
 
 func (c *Config) SkipCleanup() bool {
 	return c.GetBool(fmt.Sprintf("%s_skip_cleanup", c.AppName()))
 }
 
-// Cleanup removes all the temporary template files.
+// Cleanup releases the locks and removes all the temporary template files.
 func (c *Config) Cleanup() error {
+	err := c.Unlock()
+	if err != nil {
+		log.Warnln(err)
+	}
+
 	log.Debugln("Cleaning up temporary files...")
 
 	if c.SkipCleanup() {
@@ -328,7 +497,7 @@ func (c *Config) Cleanup() error {
 	for e := c.TmpFiles.Front(); e != nil; e = e.Next() {
 		log.Tracef("Cleaning up: %s", e.Value)
 
-		err := os.Remove(fmt.Sprint(e.Value))
+		err = os.Remove(fmt.Sprint(e.Value))
 		if err != nil {
 			return fmt.Errorf("failed to remove temporary file: %w", err)
 		}
@@ -341,7 +510,7 @@ func (c *Config) Cleanup() error {
 
 // AppName returns the application's name.
 func (c *Config) AppName() string {
-	return c.GetString("app_name")
+	return c.loadedSettings().appName
 }
 
 // AppHomeDir returns the application's home directory.
@@ -354,14 +523,128 @@ func (c *Config) AppVersion() string {
 	return c.GetString(fmt.Sprintf("%s_version", c.AppName()))
 }
 
+// ServiceMemoryLimit returns the memory limit of the docker-compose service (eg. 2g) set by the
+// reward_<service>_memory setting (eg. reward_php_fpm_memory). It returns an empty string if it's not set.
+func (c *Config) ServiceMemoryLimit(service string) string {
+	return c.GetString(c.ServiceMemoryLimitKey(service))
+}
+
+// ServiceMemoryLimitKey returns the name of the setting which limits the memory of the docker-compose service.
+func (c *Config) ServiceMemoryLimitKey(service string) string {
+	return fmt.Sprintf("%s_%s_memory", c.AppName(), strings.ReplaceAll(service, "-", "_"))
+}
+
+// ImageOverrides returns the custom images of the services by service names (reward_image_overrides), which replace
+// the images of the templates. It's a map in the config file, or a list of service=image pairs separated by spaces or
+// commas in the .env file (eg. REWARD_IMAGE_OVERRIDES="php-fpm=acme/php-fpm:8.1 nginx=acme/nginx:1.24").
+func (c *Config) ImageOverrides() map[string]string {
+	key := fmt.Sprintf("%s_image_overrides", c.AppName())
+
+	s, ok := c.Get(key).(string)
+	if !ok {
+		return c.GetStringMapString(key)
+	}
+
+	overrides := make(map[string]string)
+
+	for _, pair := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		service, image, found := strings.Cut(pair, "=")
+		if !found || service == "" || image == "" {
+			log.Warnf("Invalid image override: %s, it must be in service=image format.", pair)
+
+			continue
+		}
+
+		overrides[service] = image
+	}
+
+	return overrides
+}
+
+// LabelEnvName returns the label which contains the environment name of the containers, networks and volumes.
+func (c *Config) LabelEnvName() string {
+	return fmt.Sprintf("dev.%s.env-name", c.AppName())
+}
+
+// LabelEnvType returns the label which contains the environment type of the containers, networks and volumes.
+func (c *Config) LabelEnvType() string {
+	return fmt.Sprintf("dev.%s.env-type", c.AppName())
+}
+
+// LabelVersion returns the label which contains the application version which created the containers.
+func (c *Config) LabelVersion() string {
+	return fmt.Sprintf("dev.%s.version", c.AppName())
+}
+
 // EnvName returns the environment name in lowercase format.
 func (c *Config) EnvName() string {
-	return strings.ToLower(c.GetString(fmt.Sprintf("%s_env_name", c.AppName())))
+	return c.loadedSettings().envName
+}
+
+// SharedMode returns true if the application runs on a shared dev server where every developer has their own
+// namespace.
+func (c *Config) SharedMode() bool {
+	return c.GetBool(fmt.Sprintf("%s_shared_mode", c.AppName()))
+}
+
+// Namespace returns the developer's namespace used in shared mode (defaults to the username).
+func (c *Config) Namespace() string {
+	return c.GetString(fmt.Sprintf("%s_namespace", c.AppName()))
+}
+
+// PortOffset returns the offset added to the exposed ports of the environment.
+func (c *Config) PortOffset() int {
+	return c.GetInt(fmt.Sprintf("%s_port_offset", c.AppName()))
+}
+
+// PortOffsetConfigured returns true if the port offset is set explicitly in the config files or the environment.
+func (c *Config) PortOffsetConfigured() bool {
+	return c.IsSet(fmt.Sprintf("%s_port_offset", c.AppName()))
+}
+
+// PreferredPortOffset returns the port offset used in shared mode if it's not in use by another namespace.
+func (c *Config) PreferredPortOffset() int {
+	return (util.UID() % 100) * 100
+}
+
+// CloneOf returns the name of the environment the current environment was cloned from.
+func (c *Config) CloneOf() string {
+	return strings.ToLower(c.GetString(fmt.Sprintf("%s_clone_of", c.AppName())))
+}
+
+// CloneOfDir returns the project directory of the environment the current environment was cloned from.
+func (c *Config) CloneOfDir() string {
+	return c.GetString(fmt.Sprintf("%s_clone_of_dir", c.AppName()))
+}
+
+// applyNamespace prefixes the environment name and the traefik domain with the developer's namespace, so multiple
+// developers can run the same project on a shared server without collisions.
+func (c *Config) applyNamespace() {
+	ns := c.Namespace()
+	if ns == "" {
+		return
+	}
+
+	envNameKey := fmt.Sprintf("%s_env_name", c.AppName())
+	if envName := c.GetString(envNameKey); envName != "" && !strings.HasPrefix(envName, ns+"-") {
+		c.Set(envNameKey, fmt.Sprintf("%s-%s", ns, envName))
+	}
+
+	if domain := c.TraefikDomain(); domain != "" && !strings.HasPrefix(domain, ns+".") {
+		c.Set("traefik_domain", fmt.Sprintf("%s.%s", ns, domain))
+	}
+}
+
+// namespaceFromUsername converts the username to a valid hostname label.
+func namespaceFromUsername(username string) string {
+	ns := regexp.MustCompile(`[^a-z0-9-]+`).ReplaceAllString(strings.ToLower(username), "-")
+
+	return strings.Trim(ns, "-")
 }
 
 // EnvType returns the environment type in lowercase format.
 func (c *Config) EnvType() string {
-	return strings.ToLower(c.GetString(fmt.Sprintf("%s_env_type", c.AppName())))
+	return c.loadedSettings().envType
 }
 
 func (c *Config) EnvInitialized() bool {
@@ -370,16 +653,48 @@ func (c *Config) EnvInitialized() bool {
 	return err == nil
 }
 
+// NoColor returns true if the colors are disabled in the output by the --no-color flag, the no_color setting or the
+// NO_COLOR environment variable (https://no-color.org). The deprecated disable_colors setting is supported as well.
+func (c *Config) NoColor() bool {
+	return c.GetBool("no_color") || c.GetBool("disable_colors") || os.Getenv("NO_COLOR") != ""
+}
+
+// NoANSI returns true if the progress of the commands should be logged as plain lines instead of being rendered with
+// ANSI escape sequences (spinners, progress bars).
+func (c *Config) NoANSI() bool {
+	return c.GetBool("no_ansi")
+}
+
+// Output returns the output format of the commands set by the --output flag (text or json).
+func (c *Config) Output() string {
+	if output := c.GetString("output"); output != "" {
+		return output
+	}
+
+	return "text"
+}
+
+// JSONOutput returns true if the commands print their results as JSON and the logs are written as JSON lines, so the
+// output can be processed by scripts.
+func (c *Config) JSONOutput() bool {
+	return c.Output() == "json"
+}
+
 // IsDebug returns true if debug mode is set.
 func (c *Config) IsDebug() bool {
 	return c.GetBool("debug")
 }
 
-// Cwd returns the current working directory.
+// Cwd returns the working directory resolved by Init, or the current working directory if the configuration is not
+// initialized. It's empty if the working directory cannot be determined, Check returns the error in this case.
 func (c *Config) Cwd() string {
+	if c.cwd != "" {
+		return c.cwd
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
-		log.Panicln(err)
+		log.Debugf("Cannot determine the working directory: %s", err)
 	}
 
 	return cwd
@@ -427,9 +742,56 @@ func (c *Config) BlackfireContainer() string {
 	return c.GetString(fmt.Sprintf("%s_env_blackfire_container", c.AppName()))
 }
 
+// NewRelicEnabled returns true if the New Relic PHP agent and daemon are enabled.
+func (c *Config) NewRelicEnabled() bool {
+	return c.GetBool(fmt.Sprintf("%s_newrelic", c.AppName()))
+}
+
+// DatadogEnabled returns true if the Datadog PHP tracer and agent are enabled.
+func (c *Config) DatadogEnabled() bool {
+	return c.GetBool(fmt.Sprintf("%s_datadog", c.AppName()))
+}
+
+// APMWarnings returns the warnings about the enabled APM agents which are missing their license or API keys. The
+// agents start without the keys, but they don't report anything.
+func (c *Config) APMWarnings() []string {
+	var warnings []string
+
+	if c.NewRelicEnabled() && c.GetString("newrelic_license_key") == "" {
+		warnings = append(warnings, "New Relic is enabled, but NEWRELIC_LICENSE_KEY is not set.")
+	}
+
+	if c.DatadogEnabled() && c.GetString("dd_api_key") == "" {
+		warnings = append(warnings, "Datadog is enabled, but DD_API_KEY is not set.")
+	}
+
+	return warnings
+}
+
+// GlitchTipDSN returns the DSN of the GlitchTip project of the environment, as it's shown on the GlitchTip UI.
+func (c *Config) GlitchTipDSN() string {
+	return c.GetString("glitchtip_dsn")
+}
+
+// GlitchTipInternalDSN returns the GlitchTip DSN of the environment rewritten to the glitchtip container, so the
+// server side SDKs send the events directly on the docker network instead of through traefik.
+func (c *Config) GlitchTipInternalDSN() (string, error) {
+	dsn := c.GlitchTipDSN()
+
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || strings.Trim(u.Path, "/") == "" {
+		return "", ErrInvalidGlitchTipDSN(dsn)
+	}
+
+	u.Scheme = "http"
+	u.Host = "glitchtip:8000"
+
+	return u.String(), nil
+}
+
 // IsSvcEnabled returns true if the s service is enabled for the current environment.
 func (c *Config) IsSvcEnabled(s string) bool {
-	return c.GetBool(fmt.Sprintf("%s_%s", c.AppName(), s))
+	return c.service(s).enabled
 }
 
 // CheckInvokerUser returns an error if the invoker user is root.
@@ -447,6 +809,63 @@ func (c *Config) CheckInvokerUser(cmd *cobra.Command) error {
 	return nil
 }
 
+// ReadOnly returns true if the destructive commands are refused, eg. on shared demo servers. It's enabled by the
+// REWARD_READONLY setting, or for the members of the REWARD_READONLY_GROUP OS group.
+func (c *Config) ReadOnly() bool {
+	if c.GetBool(fmt.Sprintf("%s_readonly", c.AppName())) {
+		return true
+	}
+
+	group := c.GetString(fmt.Sprintf("%s_readonly_group", c.AppName()))
+
+	return group != "" && util.UserInGroup(group)
+}
+
+// CheckReadOnly returns an error if the command is destructive and it's called in read-only mode without the
+// --force-destructive flag.
+func (c *Config) CheckReadOnly(cmd *cobra.Command, args []string) error {
+	if !c.ReadOnly() {
+		return nil
+	}
+
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	if !DestructiveCommand(command, args) {
+		return nil
+	}
+
+	// the flags of the commands which pass their arguments to docker compose are not parsed
+	if force, _ := cmd.Flags().GetBool("force-destructive"); force || util.ContainsString(args, "--force-destructive") {
+		log.Warnf("Running `%s` in read-only mode.", command)
+
+		return nil
+	}
+
+	return ErrReadOnly(strings.TrimSpace(command + " " + strings.Join(args, " ")))
+}
+
+// DestructiveCommand returns true if the command (eg. "db import") deletes or replaces the data of the environment
+// or the global services.
+func DestructiveCommand(command string, args []string) bool {
+	switch command {
+	case "db import", "db upgrade", "env promote", "env rotate-credentials", "search migrate", "undo last":
+		return true
+	case "env", "svc":
+		if len(args) == 0 || args[0] != "down" {
+			return false
+		}
+
+		for _, arg := range args[1:] {
+			if arg == "-v" || arg == "--volumes" || strings.HasPrefix(arg, "--volumes=") {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
 // SyncedContainer returns the container name of the synced container from REWARD_ENV_SYNCED_CONTAINER variable.
 func (c *Config) SyncedContainer() string {
 	return c.GetString(fmt.Sprintf("%s_env_synced_container", c.AppName()))
@@ -500,30 +919,68 @@ func (c *Config) SetPHPDefaults(envType string) {
 }
 
 func (c *Config) SetPWADefaults() {
-	viper.SetDefault(fmt.Sprintf("%s_node", c.AppName()), true)
-	viper.SetDefault(fmt.Sprintf("%s_db", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_opensearch_dashboards", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_node", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_db", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch_dashboards", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
+}
+
+// SetStaticDefaults disables every service of the static environments except nginx. The node container can be
+// enabled to run the build watcher.
+func (c *Config) SetStaticDefaults() {
+	c.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_node", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_db", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch_dashboards", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_sync_enabled", c.AppName()), false)
+}
+
+// SetTypo3Defaults sets the detected composer mode of TYPO3, so the templates serve the right document root.
+func (c *Config) SetTypo3Defaults() {
+	c.SetDefault("typo3_composer_mode", c.Typo3ComposerMode())
+}
+
+// SetCustomDefaults disables every service of the custom environments, the services are defined by the compose
+// files of the project.
+func (c *Config) SetCustomDefaults() {
+	c.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_node", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_db", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch_dashboards", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_mercure", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_sync_enabled", c.AppName()), false)
 }
 
 func (c *Config) SetNonLocalDefaults() {
-	viper.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), true)
-	viper.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), true)
-	viper.SetDefault(fmt.Sprintf("%s_db", c.AppName()), true)
-	viper.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_php_fpm", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_nginx", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_db", c.AppName()), true)
+	c.SetDefault(fmt.Sprintf("%s_redis", c.AppName()), true)
 }
 
 func (c *Config) SetLocalDefaults() {
-	viper.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
-	viper.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_varnish", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_elasticsearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_opensearch", c.AppName()), false)
+	c.SetDefault(fmt.Sprintf("%s_rabbitmq", c.AppName()), false)
 }
 
 // TODO: test if this works as expected.
@@ -564,14 +1021,141 @@ func (c *Config) MutagenSyncIgnore() string {
 	return c.GetString(fmt.Sprintf("%s_sync_ignore", c.AppName()))
 }
 
+// SyncMode returns the mutagen sync mode of the environment, which decides how the conflicts are resolved. In
+// two-way-safe mode the conflicting changes are kept on both sides until they're resolved using sync conflicts, in
+// two-way-resolved mode the host wins. If it's empty, the mode of the mutagen sync file is used.
+func (c *Config) SyncMode() (string, error) {
+	mode := strings.ToLower(c.GetString(fmt.Sprintf("%s_sync_mode", c.AppName())))
+
+	switch mode {
+	case "", "two-way-safe", "two-way-resolved", "one-way-safe", "one-way-replica":
+		return mode, nil
+	default:
+		return "", ErrUnknownSyncMode(mode)
+	}
+}
+
+// SyncProtectedPaths returns the paths relative to the web root which are synced from the host to the container only,
+// so they're never overwritten by the changes made in the container. If it's not set, the configuration files of the
+// environment type are protected.
+func (c *Config) SyncProtectedPaths() []string {
+	key := fmt.Sprintf("%s_sync_protected_paths", c.AppName())
+
+	if !c.IsSet(key) {
+		switch c.EnvType() {
+		case "magento2":
+			return []string{"app/etc/env.php", ".env"}
+		case "magento1":
+			return []string{"app/etc/local.xml", ".env"}
+		case "wordpress":
+			return []string{"wp-config.php", ".env"}
+		default:
+			return []string{".env"}
+		}
+	}
+
+	s, ok := c.Get(key).(string)
+	if !ok {
+		return c.GetStringSlice(key)
+	}
+
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+}
+
+// VendorVolume returns true if the vendor and node_modules directories live only in container volumes instead of being
+// synced between the host and the container. It's only used if the sync is enabled.
+func (c *Config) VendorVolume() bool {
+	return c.SyncEnabled() && c.GetBool(fmt.Sprintf("%s_vendor_volume", c.AppName()))
+}
+
+// VendorVolumeDirs returns the directories relative to the web root which live in container volumes if VendorVolume
+// is enabled.
+func (c *Config) VendorVolumeDirs() []string {
+	return []string{"vendor", "node_modules"}
+}
+
+// FrontendSyncIgnore returns the mutagen ignore rules for the files generated by the frontend sidecar. The
+// container generates the preprocessed LESS files of the grunt workflow, the tailwind dependencies and the compiled
+// CSS of the Hyvä theme itself, so syncing them back and forth would only cause churn (and watcher feedback loops).
+func (c *Config) FrontendSyncIgnore() []string {
+	if !c.FrontendEnabled() || c.EnvType() != "magento2" {
+		return nil
+	}
+
+	theme := c.HyvaTheme()
+	if theme == "" {
+		theme = "*/*"
+	}
+
+	return []string{
+		"/var/view_preprocessed",
+		fmt.Sprintf("/app/design/frontend/%s/web/tailwind/node_modules", theme),
+		fmt.Sprintf("/app/design/frontend/%s/web/css/styles.css", theme),
+	}
+}
+
 // WebRoot returns the content of the WEB_ROOT variable.
 func (c *Config) WebRoot() string {
 	return c.GetString(fmt.Sprintf("%s_web_root", c.AppName()))
 }
 
-// MutagenURL returns the content of the REWARD_MUTAGEN_URL variable.
+// MutagenURL returns the content of the REWARD_MUTAGEN_URL variable. If it's empty, the archive of the pinned
+// version is downloaded from the mutagen releases.
 func (c *Config) MutagenURL() string {
-	return c.GetString(fmt.Sprintf("%s_mutagen_url", c.AppName()))
+	if url := c.GetString(fmt.Sprintf("%s_mutagen_url", c.AppName())); url != "" {
+		return url
+	}
+
+	return c.MutagenReleaseURL(c.MutagenVersion())
+}
+
+// MutagenReleaseURL returns the URL of the mutagen release archive of the version for the current OS and
+// architecture.
+func (c *Config) MutagenReleaseURL(v string) string {
+	ext := "tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = "zip"
+	}
+
+	return fmt.Sprintf(
+		"https://github.com/mutagen-io/mutagen/releases/download/v%[1]s/mutagen_%[2]s_%[3]s_v%[1]s.%[4]s",
+		strings.TrimPrefix(v, "v"), runtime.GOOS, runtime.GOARCH, ext,
+	)
+}
+
+// MutagenManaged returns true if the application uses its own mutagen binary installed in the app home instead of
+// the one found in $PATH.
+func (c *Config) MutagenManaged() bool {
+	return c.GetBool(fmt.Sprintf("%s_mutagen_managed", c.AppName()))
+}
+
+// MutagenVersion returns the pinned version of the managed mutagen binary.
+func (c *Config) MutagenVersion() string {
+	return strings.TrimPrefix(c.GetString(fmt.Sprintf("%s_mutagen_version", c.AppName())), "v")
+}
+
+// MutagenDir returns the directory of the managed mutagen binary and its agents.
+func (c *Config) MutagenDir() string {
+	return filepath.Join(c.AppHomeDir(), "mutagen")
+}
+
+// MutagenBinary returns the path of the managed mutagen binary, or "mutagen" if it's not managed.
+func (c *Config) MutagenBinary() string {
+	if !c.MutagenManaged() {
+		return "mutagen"
+	}
+
+	if runtime.GOOS == "windows" {
+		return filepath.Join(c.MutagenDir(), "mutagen.exe")
+	}
+
+	return filepath.Join(c.MutagenDir(), "mutagen")
+}
+
+// MutagenSHA256 returns the SHA256 checksum of the file of the REWARD_MUTAGEN_URL variable. If it's empty, the
+// checksum is looked up in the SHA256SUMS file of the release.
+func (c *Config) MutagenSHA256() string {
+	return c.GetString(fmt.Sprintf("%s_mutagen_sha256", c.AppName()))
 }
 
 // MutagenRequiredVersion returns the content of the REWARD_MUTAGEN_VERSION variable.
@@ -727,49 +1311,188 @@ VARNISH_VERSION=6.5
 `, strings.ToUpper(c.AppName()),
 		),
 
-		"symfony": fmt.Sprintf(
+		"static": fmt.Sprintf(
+			`%[1]v_NODE=false
+%[1]v_SYNC_ENABLED=false
+
+STATIC_BUILD_DIR=dist
+STATIC_SPA=true
+STATIC_WATCH_COMMAND="npm run watch"
+NODE_VERSION=16
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"sylius": fmt.Sprintf(
 			`%[1]v_DB=true
-%[1]v_REDIS=true
+%[1]v_REDIS=false
 %[1]v_RABBITMQ=false
 %[1]v_ELASTICSEARCH=false
 %[1]v_OPENSEARCH=false
-%[1]v_OPENSEARCH_DASHBOARDS=false
 %[1]v_VARNISH=false
-%[1]v_MERCURE=false
 
-MARIADB_VERSION=10.4
-NODE_VERSION=16
-PHP_VERSION=7.4
-RABBITMQ_VERSION=3.8
+MARIADB_VERSION=10.6
+NODE_VERSION=18
+PHP_VERSION=8.1
 REDIS_VERSION=6.0
-VARNISH_VERSION=6.5
 COMPOSER_VERSION=2
+
+APP_ENV=dev
+DATABASE_URL=mysql://sylius:sylius@db:3306/sylius?serverVersion=mariadb-10.6.0
 `, strings.ToUpper(c.AppName()),
 		),
 
-		"shopware": fmt.Sprintf(
+		"akeneo": fmt.Sprintf(
 			`%[1]v_DB=true
-%[1]v_REDIS=true
+%[1]v_REDIS=false
 %[1]v_RABBITMQ=false
-%[1]v_ELASTICSEARCH=false
-%[1]v_OPENSEARCH=true
+%[1]v_ELASTICSEARCH=true
+%[1]v_OPENSEARCH=false
 %[1]v_VARNISH=false
 
-MARIADB_VERSION=10.4
-NODE_VERSION=16
-PHP_VERSION=7.4
-RABBITMQ_VERSION=3.8
-REDIS_VERSION=6.0
-VARNISH_VERSION=6.5
-COMPOSER_VERSION=2.4.4
+MYSQL_VERSION=8.0
+ELASTICSEARCH_VERSION=8.4
+ELASTICSEARCH_HEAP_SIZE=512m
+ELASTICSEARCH_INDEX_DEFAULTS=mapping.total_fields.limit=10000
+NODE_VERSION=18
+PHP_VERSION=8.1
+COMPOSER_VERSION=2
+AKENEO_JOB_QUEUES="ui_job import_export_job data_maintenance_job"
+
+APP_ENV=dev
+APP_DATABASE_HOST=db
+APP_DATABASE_PORT=3306
+APP_DATABASE_NAME=akeneo_pim
+APP_DATABASE_USER=akeneo_pim
+APP_DATABASE_PASSWORD=akeneo_pim
+APP_INDEX_HOSTS=elasticsearch:9200
 `, strings.ToUpper(c.AppName()),
 		),
 
-		"wordpress": fmt.Sprintf(
-			`MARIADB_VERSION=10.4
-NODE_VERSION=16
-PHP_VERSION=7.4
-COMPOSER_VERSION=2
+		"oro": fmt.Sprintf(
+			`%[1]v_DB=true
+%[1]v_REDIS=false
+%[1]v_RABBITMQ=false
+%[1]v_ELASTICSEARCH=false
+%[1]v_OPENSEARCH=false
+%[1]v_VARNISH=false
+
+POSTGRES_VERSION=15
+NODE_VERSION=18
+PHP_VERSION=8.2
+COMPOSER_VERSION=2
+
+ORO_ENV=dev
+ORO_DB_URL=postgres://oro:oro@db:5432/oro?sslmode=disable&charset=utf8&serverVersion=15
+ORO_MQ_DSN=dbal:
+ORO_SEARCH_ENGINE_DSN=orm:
+ORO_MAILER_DSN=smtp://mailhog:1025
+ORO_WEBSOCKET_SERVER_DSN=//0.0.0.0:8080
+ORO_WEBSOCKET_FRONTEND_DSN=//*:443/ws
+ORO_WEBSOCKET_BACKEND_DSN=tcp://websocket:8080
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"custom": fmt.Sprintf(
+			`%[1]v_SYNC_ENABLED=false
+
+CUSTOM_COMPOSE_FILES=docker-compose.yml
+CUSTOM_HTTP_SERVICE=app
+CUSTOM_HTTP_PORT=80
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"typo3": fmt.Sprintf(
+			`%[1]v_DB=true
+%[1]v_REDIS=false
+%[1]v_RABBITMQ=false
+%[1]v_ELASTICSEARCH=false
+%[1]v_OPENSEARCH=false
+%[1]v_VARNISH=false
+
+MARIADB_VERSION=10.6
+NODE_VERSION=18
+PHP_VERSION=8.1
+COMPOSER_VERSION=2
+
+MYSQL_CHARACTER_SET_SERVER=utf8mb4
+MYSQL_COLLATION_SERVER=utf8mb4_unicode_ci
+
+TYPO3_CONTEXT=Development
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"craft": fmt.Sprintf(
+			`%[1]v_DB=true
+%[1]v_REDIS=false
+%[1]v_RABBITMQ=false
+%[1]v_ELASTICSEARCH=false
+%[1]v_OPENSEARCH=false
+%[1]v_VARNISH=false
+
+MARIADB_VERSION=10.6
+NODE_VERSION=18
+PHP_VERSION=8.1
+COMPOSER_VERSION=2
+
+MYSQL_CHARACTER_SET_SERVER=utf8mb4
+MYSQL_COLLATION_SERVER=utf8mb4_unicode_ci
+
+CRAFT_QUEUE_RUNNER=false
+CRAFT_ENVIRONMENT=dev
+CRAFT_DEV_MODE=true
+CRAFT_DB_DRIVER=mysql
+CRAFT_DB_SERVER=db
+CRAFT_DB_PORT=3306
+CRAFT_DB_DATABASE=craft
+CRAFT_DB_USER=craft
+CRAFT_DB_PASSWORD=craft
+CRAFT_DB_TABLE_PREFIX=
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"symfony": fmt.Sprintf(
+			`%[1]v_DB=true
+%[1]v_REDIS=true
+%[1]v_RABBITMQ=false
+%[1]v_ELASTICSEARCH=false
+%[1]v_OPENSEARCH=false
+%[1]v_OPENSEARCH_DASHBOARDS=false
+%[1]v_VARNISH=false
+%[1]v_MERCURE=false
+
+MARIADB_VERSION=10.4
+NODE_VERSION=16
+PHP_VERSION=7.4
+RABBITMQ_VERSION=3.8
+REDIS_VERSION=6.0
+VARNISH_VERSION=6.5
+COMPOSER_VERSION=2
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"shopware": fmt.Sprintf(
+			`%[1]v_DB=true
+%[1]v_REDIS=true
+%[1]v_RABBITMQ=false
+%[1]v_ELASTICSEARCH=false
+%[1]v_OPENSEARCH=true
+%[1]v_VARNISH=false
+
+MARIADB_VERSION=10.4
+NODE_VERSION=16
+PHP_VERSION=7.4
+RABBITMQ_VERSION=3.8
+REDIS_VERSION=6.0
+VARNISH_VERSION=6.5
+COMPOSER_VERSION=2.4.4
+`, strings.ToUpper(c.AppName()),
+		),
+
+		"wordpress": fmt.Sprintf(
+			`MARIADB_VERSION=10.4
+NODE_VERSION=16
+PHP_VERSION=7.4
+COMPOSER_VERSION=2
 
 %[1]v_DB=true
 %[1]v_REDIS=false
@@ -827,106 +1550,98 @@ func (c *Config) ShopwareVersion() (*version.Version, error) {
 	return v, nil
 }
 
-func (c *Config) ShopwareMode() string {
-	return c.GetString(fmt.Sprintf("%s_shopware_mode", c.AppName()))
+// SyliusVersion returns the version of Sylius which is installed by the bootstrap command.
+func (c *Config) SyliusVersion() (*version.Version, error) {
+	v, err := version.NewVersion(c.GetString(fmt.Sprintf("%s_sylius_version", c.AppName())))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sylius version: %w", err)
+	}
+
+	return v, nil
 }
 
-// MagentoVersion returns a *version.Version object which contains the Magento version.
-func (c *Config) MagentoVersion() (*version.Version, error) {
-	log.Debugln("Looking up Magento version...")
+// AkeneoVersion returns the version of Akeneo PIM which is installed by the bootstrap command.
+func (c *Config) AkeneoVersion() (*version.Version, error) {
+	v, err := version.NewVersion(c.GetString(fmt.Sprintf("%s_akeneo_version", c.AppName())))
+	if err != nil {
+		return nil, fmt.Errorf("invalid akeneo version: %w", err)
+	}
 
-	magentoVersion := new(version.Version)
+	return v, nil
+}
 
-	type ComposerJSON struct {
-		Name    string            `json:"name"`
-		Version string            `json:"version"`
-		Require map[string]string `json:"require"`
+// OroVersion returns the version of OroCommerce which is installed by the bootstrap command.
+func (c *Config) OroVersion() (*version.Version, error) {
+	v, err := version.NewVersion(c.GetString(fmt.Sprintf("%s_oro_version", c.AppName())))
+	if err != nil {
+		return nil, fmt.Errorf("invalid oro version: %w", err)
 	}
 
-	var composerJSON ComposerJSON
+	return v, nil
+}
 
-	if util.FileExists("composer.json") {
-		data, err := FS.ReadFile("composer.json")
-		if err != nil {
-			log.Debugln("...cannot read composer.json. Using .env settings.")
+// CustomComposeFiles returns the compose files of the custom environments (CUSTOM_COMPOSE_FILES), relative to the
+// project directory. The files are separated by spaces or commas, the default is docker-compose.yml.
+func (c *Config) CustomComposeFiles() []string {
+	files := strings.FieldsFunc(c.GetString("custom_compose_files"), func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(files) == 0 {
+		files = []string{"docker-compose.yml"}
+	}
 
-			magentoVersion = c.MagentoVersionFromConfig()
+	for i, file := range files {
+		if !filepath.IsAbs(file) {
+			files[i] = filepath.Join(c.Cwd(), file)
 		}
+	}
 
-		if err = json.Unmarshal(data, &composerJSON); err != nil {
-			log.Debugln("...cannot unmarshal composer.json. Using .env settings.")
+	return files
+}
 
-			magentoVersion = c.MagentoVersionFromConfig()
+// ConsoleCommand returns the command line of the console of the application (eg. bin/console of Symfony) which is
+// run by the console command. It returns nil if the application of the environment type doesn't have a console.
+func (c *Config) ConsoleCommand() []string {
+	switch c.EnvType() {
+	case "symfony", "shopware", "sylius", "akeneo", "oro":
+		return []string{"php", "bin/console"}
+	case "typo3":
+		if c.Typo3ComposerMode() {
+			return []string{"vendor/bin/typo3"}
 		}
 
-		if util.CheckRegexInString(
-			`^magento/magento2(ce|ee)$`,
-			composerJSON.Name,
-		) && composerJSON.Version != "" {
-			re := regexp.MustCompile(semver.SemVerRegex)
-			ver := re.Find([]byte(composerJSON.Version))
+		return []string{"php", "typo3/sysext/core/bin/typo3"}
+	case "craft":
+		return []string{"php", "craft"}
+	default:
+		return nil
+	}
+}
 
-			log.Debugf(
-				"...using magento/magento2(ce|ee) package version from composer.json. Found version: %s.",
-				ver,
-			)
+// Typo3ComposerMode returns true if TYPO3 is installed by composer. In composer mode the document root is the public
+// directory and the console is installed to vendor/bin. It's detected by the composer.json file in the web root
+// unless the TYPO3_COMPOSER_MODE variable is set.
+func (c *Config) Typo3ComposerMode() bool {
+	if c.IsSet("typo3_composer_mode") {
+		return c.GetBool("typo3_composer_mode")
+	}
 
-			magentoVersion, err = version.NewVersion(string(ver))
-			if err != nil {
-				return nil, fmt.Errorf("cannot parse Magento version from composer.json: %w", err)
-			}
-		}
+	return util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "composer.json"))
+}
 
-		if magentoVersion.String() == "" {
-			for key, val := range composerJSON.Require {
-				if util.CheckRegexInString(`^magento/product-(enterprise|community)-edition$`, key) {
-					re := regexp.MustCompile(semver.SemVerRegex)
-					ver := re.Find([]byte(val))
-
-					log.Debugf(
-						"...using magento/product-(enterprise-community)-edition "+
-							"package version from composer.json. Found version: %s.",
-						ver,
-					)
-
-					magentoVersion, err = version.NewVersion(string(ver))
-					if err != nil {
-						return nil, fmt.Errorf(
-							"cannot parse Magento version from composer.json: %w",
-							err,
-						)
-					}
-				} else if util.CheckRegexInString(`^magento/magento-cloud-metapackage$`, key) {
-					re := regexp.MustCompile(semver.SemVerRegex)
-					ver := re.Find([]byte(val))
-
-					log.Debugf(
-						"...using magento/magento-cloud-metapackage package version from composer.json. Found version: %s.",
-						ver,
-					)
-
-					magentoVersion, err = version.NewVersion(string(ver))
-					if err != nil {
-						return nil, fmt.Errorf(
-							"cannot parse Magento version from composer.json: %w",
-							err,
-						)
-					}
-				}
-			}
-		}
+func (c *Config) ShopwareMode() string {
+	return c.GetString(fmt.Sprintf("%s_shopware_mode", c.AppName()))
+}
 
-		return magentoVersion, nil
+// MagentoVersion returns a *version.Version object which contains the Magento version.
+// See MagentoVersionInfo for the lookup order.
+func (c *Config) MagentoVersion() (*version.Version, error) {
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		return nil, err
 	}
 
-	magentoVersion = c.MagentoVersionFromConfig()
-
-	log.Debugf(
-		"...cannot find Magento version in composer.json, using .env settings. Version: %s.",
-		magentoVersion.String(),
-	)
-
-	return magentoVersion, nil
+	return info.Version, nil
 }
 
 // MagentoVersionFromConfig returns a *version.Version object from Config settings.
@@ -949,6 +1664,35 @@ func (c *Config) ServiceDomain() string {
 	return c.GetString(fmt.Sprintf("%s_service_domain", c.AppName()))
 }
 
+// TunnelEnabled returns true if the tunnel service is enabled.
+func (c *Config) TunnelEnabled() bool {
+	return c.GetBool(fmt.Sprintf("%s_tunnel", c.AppName()))
+}
+
+// TunnelHost returns the hostname of the tunnel service.
+func (c *Config) TunnelHost() string {
+	return fmt.Sprintf("tunnel.%s.test", c.AppName())
+}
+
+// TunnelPort returns the published port of the tunnel service.
+func (c *Config) TunnelPort() string {
+	if port := c.GetString(fmt.Sprintf("%s_tunnel_port", c.AppName())); port != "" {
+		return port
+	}
+
+	return "2222"
+}
+
+// TunnelDir returns the directory of the tunnel ssh client key and host keys.
+func (c *Config) TunnelDir() string {
+	return filepath.Join(c.AppHomeDir(), "tunnel")
+}
+
+// TunnelHostKeysDir returns the directory of the tunnel sshd host keys.
+func (c *Config) TunnelHostKeysDir() string {
+	return filepath.Join(c.TunnelDir(), "host_keys")
+}
+
 func (c *Config) SSLBaseDir() string {
 	return c.GetString(fmt.Sprintf("%s_ssl_base_dir", c.AppName()))
 }
@@ -1008,25 +1752,21 @@ func (c *Config) DockerPeeredServices(action, networkName string) error {
 			log.Debugln("Network aliases for Traefik container:", networkSettings.Aliases)
 		}
 
-		containers, err := c.Docker.ContainerList(
-			ctx, types.ContainerListOptions{
-				Filters: filters.NewArgs(
-					filters.KeyValuePair{
-						Key:   "name",
-						Value: svc,
-					},
-				),
-			},
+		// The common services are looked up by their labels, as the name filter matches unrelated containers which
+		// contain the name of the service (eg. my-traefik-test).
+		containers, err := c.Docker.RunningContainersByLabels(
+			fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), svc),
+			fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.AppName()),
 		)
 		if err != nil {
-			return fmt.Errorf("cannot list containers: %w", err)
+			return err
 		}
 
 		for _, container := range containers {
 			if action == "connect" {
 				log.Debugf("Connecting container: %s to network %s...", container.Names, networkName)
 
-				err = c.Docker.NetworkConnect(ctx, networkName, container.ID, networkSettings)
+				err = c.Docker.API.NetworkConnect(ctx, networkName, container.ID, networkSettings)
 				if err != nil {
 					log.Debugf("%s", err)
 				}
@@ -1037,7 +1777,7 @@ func (c *Config) DockerPeeredServices(action, networkName string) error {
 			if action == "disconnect" {
 				log.Debugf("Disconnecting container: %s from network %s.", container.Names, networkName)
 
-				err = c.Docker.NetworkDisconnect(ctx, networkName, container.ID, false)
+				err = c.Docker.API.NetworkDisconnect(ctx, networkName, container.ID, false)
 				if err != nil {
 					log.Debugf("%s", err)
 				}
@@ -1073,11 +1813,156 @@ func (c *Config) TraefikFullDomain() string {
 	return fmt.Sprintf("%s.%s", c.TraefikSubdomain(), c.TraefikDomain())
 }
 
+// TraefikVersion returns the version (image tag) of the traefik image.
+func (c *Config) TraefikVersion() string {
+	return c.GetString(fmt.Sprintf("%s_traefik_version", c.AppName()))
+}
+
+// TraefikHTTPPort returns the port on which traefik listens for HTTP connections on the host.
+func (c *Config) TraefikHTTPPort() string {
+	if port := c.GetString(fmt.Sprintf("%s_traefik_http_port", c.AppName())); port != "" {
+		return port
+	}
+
+	return "80"
+}
+
+// TraefikHTTPSPort returns the port on which traefik listens for HTTPS connections on the host.
+func (c *Config) TraefikHTTPSPort() string {
+	if port := c.GetString(fmt.Sprintf("%s_traefik_https_port", c.AppName())); port != "" {
+		return port
+	}
+
+	return "443"
+}
+
+// TraefikHTTP3 returns true if HTTP/3 is enabled on the https entrypoint of traefik.
+func (c *Config) TraefikHTTP3() bool {
+	return c.GetBool(fmt.Sprintf("%s_traefik_http3", c.AppName()))
+}
+
+// CheckTraefikHTTP3 returns an error if HTTP/3 is enabled but the configured traefik version doesn't support the
+// http3 entrypoint option (added in traefik 2.6) or the rolling restart of traefik is enabled. Non-numeric image
+// tags (eg. latest) are not checked.
+func (c *Config) CheckTraefikHTTP3() error {
+	if !c.TraefikHTTP3() {
+		return nil
+	}
+
+	if c.TraefikRollingRestart() {
+		return ErrTraefikRollingRestartHTTP3
+	}
+
+	v, err := version.NewVersion(strings.TrimPrefix(c.TraefikVersion(), "v"))
+	if err != nil {
+		return nil
+	}
+
+	if v.Core().LessThan(version.Must(version.NewVersion("2.6"))) {
+		return ErrTraefikHTTP3NotSupported(c.TraefikVersion())
+	}
+
+	return nil
+}
+
+// TraefikRollingRestart returns true if the ports of traefik are published by a port proxy, so traefik can be
+// restarted without downtime.
+func (c *Config) TraefikRollingRestart() bool {
+	return c.GetBool(fmt.Sprintf("%s_traefik_rolling_restart", c.AppName()))
+}
+
+// TraefikDashboard returns true if the traefik dashboard is enabled. If it's disabled, only the API of traefik is
+// routed.
+func (c *Config) TraefikDashboard() bool {
+	return c.GetBool(fmt.Sprintf("%s_traefik_dashboard", c.AppName()))
+}
+
+// TraefikDashboardAllowedIPs returns the additional IP ranges (eg. the LAN) the traefik dashboard and API can be
+// reached from. By default, they can be reached from the loopback and the docker network of the common services only.
+func (c *Config) TraefikDashboardAllowedIPs() []string {
+	var ips []string
+
+	// the environment variables and the .env file contain a comma separated list
+	for _, value := range c.GetStringSlice(fmt.Sprintf("%s_traefik_dashboard_allowed_ips", c.AppName())) {
+		for _, ip := range strings.Split(value, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+	}
+
+	return ips
+}
+
+// TraefikCredentialsFile returns the file which contains the generated basic auth credentials of the traefik
+// dashboard and API.
+func (c *Config) TraefikCredentialsFile() string {
+	return filepath.Join(c.AppHomeDir(), "etc/traefik/credentials.json")
+}
+
+// ContainerUI returns the container management UI of the common services (portainer, yacht or none). If it's not
+// set, portainer is used unless it's disabled by reward_portainer.
+func (c *Config) ContainerUI() (string, error) {
+	ui := strings.ToLower(c.GetString(fmt.Sprintf("%s_container_ui", c.AppName())))
+
+	switch ui {
+	case "":
+		if c.GetBool(fmt.Sprintf("%s_portainer", c.AppName())) {
+			return "portainer", nil
+		}
+
+		return "none", nil
+	case "portainer", "yacht", "none":
+		return ui, nil
+	default:
+		return "", ErrUnknownContainerUI(ui)
+	}
+}
+
+// ContainerUICredentialsFile returns the file which contains the generated credentials of the container UI.
+func (c *Config) ContainerUICredentialsFile(ui string) string {
+	return filepath.Join(c.AppHomeDir(), "etc", ui, "credentials.json")
+}
+
+// DNSServer returns the DNS server of the common services which resolves the .test domains (dnsmasq or coredns).
+func (c *Config) DNSServer() (string, error) {
+	server := strings.ToLower(c.GetString(fmt.Sprintf("%s_dns_server", c.AppName())))
+
+	switch server {
+	case "":
+		return "dnsmasq", nil
+	case "dnsmasq", "coredns":
+		return server, nil
+	default:
+		return "", ErrUnknownDNSServer(server)
+	}
+}
+
+// CorednsDir returns the directory of the generated CoreDNS configuration and its DNS-over-TLS certificate.
+func (c *Config) CorednsDir() string {
+	return filepath.Join(c.AppHomeDir(), "etc", "coredns")
+}
+
+// TraefikAccessLog returns true if the traefik access log is enabled in Viper settings.
+func (c *Config) TraefikAccessLog() bool {
+	return c.GetBool(fmt.Sprintf("%s_traefik_access_log", c.AppName()))
+}
+
+// TraefikAccessLogDir returns the host directory mounted into the traefik container for its logs.
+func (c *Config) TraefikAccessLogDir() string {
+	return filepath.Join(c.AppHomeDir(), "log", "traefik")
+}
+
+// TraefikAccessLogFile returns the path of the traefik access log file on the host.
+func (c *Config) TraefikAccessLogFile() string {
+	return filepath.Join(c.TraefikAccessLogDir(), "access.log")
+}
+
 // SvcEnabledPermissive returns true if the s service is enabled in Viper settings. This function is also going to
 // return true if the service is not mentioned in Viper settings (defaults to true).
 func (c *Config) SvcEnabledPermissive(s string) bool {
-	if c.IsSet(fmt.Sprintf("%s_%s", c.AppName(), s)) {
-		return c.GetBool(fmt.Sprintf("%s_%s", c.AppName(), s))
+	if svc := c.service(s); svc.set {
+		return svc.enabled
 	}
 
 	return true
@@ -1086,22 +1971,26 @@ func (c *Config) SvcEnabledPermissive(s string) bool {
 // SvcEnabledStrict returns true if the s service is enabled in Viper settings. This function is going to
 // return false if the service is not mentioned in Viper settings (defaults to false).
 func (c *Config) SvcEnabledStrict(s string) bool {
-	if c.IsSet(fmt.Sprintf("%s_%s", c.AppName(), s)) {
-		return c.GetBool(fmt.Sprintf("%s_%s", c.AppName(), s))
-	}
+	svc := c.service(s)
 
-	return false
+	return svc.set && svc.enabled
 }
 
-func (c *Config) PluginsAvailable() map[string]*Plugin {
+// PluginsAvailable returns the plugins which can be installed, by their names.
+func (c *Config) PluginsAvailable() (map[string]*Plugin, error) {
 	plugins := make(map[string]*Plugin)
 
 	err := c.UnmarshalKey(fmt.Sprintf("%s_plugins_available", c.AppName()), &plugins)
 	if err != nil {
-		log.Fatalf("Cannot unmarshal available plugins: %s", err)
+		return nil, fmt.Errorf("cannot unmarshal available plugins: %w", err)
 	}
 
-	return plugins
+	return plugins, nil
+}
+
+// DownloadCacheDir returns the directory of the downloaded binaries and archives (eg. self-update, mutagen, plugins).
+func (c *Config) DownloadCacheDir() string {
+	return filepath.Join(c.CacheDir(), "downloads")
 }
 
 func (c *Config) PluginsDir() string {
@@ -1112,6 +2001,12 @@ func (c *Config) PluginsConfigDir() string {
 	return c.GetString(fmt.Sprintf("%s_plugins_config_dir", c.AppName()))
 }
 
+// PluginPinsFile returns the path of the file which records the versions the plugins are pinned to (eg. by
+// `plugin install foo@1.2.0`), the pinned plugins are not updated.
+func (c *Config) PluginPinsFile() string {
+	return filepath.Join(c.PluginsConfigDir(), "pins.json")
+}
+
 func (c *Config) Plugins() []*Plugin {
 	content, err := FS.ReadDir(c.PluginsDir())
 	if err != nil {
@@ -1143,8 +2038,13 @@ func (c *Config) Plugins() []*Plugin {
 		}
 	}
 
+	available, err := c.PluginsAvailable()
+	if err != nil {
+		log.Warnf("%s", err)
+	}
+
 	for _, plugin := range plugins {
-		for _, availablePlugin := range c.PluginsAvailable() {
+		for _, availablePlugin := range available {
 			if plugin.Name == availablePlugin.Name {
 				plugin.Description = availablePlugin.Description
 			}
@@ -1158,6 +2058,30 @@ func (c *Config) Shortcuts() map[string]string {
 	return c.GetStringMapString(fmt.Sprintf("%s_shortcuts", c.AppName()))
 }
 
+// CommandFlags returns the default flags of the command (eg. "env up"). They're set in the reward_command_flags map
+// of the global configuration, or in the REWARD_COMMAND_FLAGS_<COMMAND> setting of the project (eg.
+// REWARD_COMMAND_FLAGS_ENV_UP=--wait), which overrides the global one.
+func (c *Config) CommandFlags(commandPath string) []string {
+	key := strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(commandPath))
+	if flags := c.GetString(fmt.Sprintf("%s_command_flags_%s", c.AppName(), key)); flags != "" {
+		return strings.Fields(flags)
+	}
+
+	switch flags := c.GetStringMap(fmt.Sprintf("%s_command_flags", c.AppName()))[commandPath].(type) {
+	case string:
+		return strings.Fields(flags)
+	case []interface{}:
+		args := make([]string, 0, len(flags))
+		for _, flag := range flags {
+			args = append(args, fmt.Sprint(flag))
+		}
+
+		return args
+	}
+
+	return nil
+}
+
 // ComposerVersion returns the Composer Version defined in Config settings.
 func (c *Config) ComposerVersion() *version.Version {
 	if c.GetString("composer_version") != "1" {
@@ -1169,11 +2093,7 @@ func (c *Config) ComposerVersion() *version.Version {
 
 // ServiceEnabled returns true if service is enabled in Config settings.
 func (c *Config) ServiceEnabled(servicename string) bool {
-	if c.IsSet(fmt.Sprintf("%s_%s", c.AppName(), servicename)) {
-		return c.GetBool(fmt.Sprintf("%s_%s", c.AppName(), servicename))
-	}
-
-	return false
+	return c.SvcEnabledStrict(servicename)
 }
 
 // MagentoBackendFrontname returns Magento admin path from Config settings.
@@ -1223,6 +2143,16 @@ func (c *Config) NoPull() bool {
 	return c.GetBool(fmt.Sprintf("%s_no_pull", c.AppName()))
 }
 
+// BootstrapDump returns the database dump which the environment is bootstrapped from instead of a fresh install.
+func (c *Config) BootstrapDump() string {
+	return c.GetString(fmt.Sprintf("%s_bootstrap_from_dump", c.AppName()))
+}
+
+// BootstrapMedia returns the media archive which is extracted when the environment is bootstrapped from a dump.
+func (c *Config) BootstrapMedia() string {
+	return c.GetString(fmt.Sprintf("%s_bootstrap_media", c.AppName()))
+}
+
 // WithSampleData checks if Magento 2 sample data is enabled in configs.
 func (c *Config) WithSampleData() bool {
 	return c.GetBool(fmt.Sprintf("%s_with_sampledata", c.AppName()))
@@ -1238,16 +2168,45 @@ func (c *Config) ResetAdminURL() bool {
 	return c.GetBool(fmt.Sprintf("%s_reset_admin_url", c.AppName()))
 }
 
-// MagentoType returns Magento type: enterprise or community (default: community).
+// MagentoType returns Magento type: enterprise, community or mage-os (default: community).
 func (c *Config) MagentoType() string {
-	if c.GetString(fmt.Sprintf("%s_magento_type", c.AppName())) == "enterprise" ||
-		c.GetString(fmt.Sprintf("%s_magento_type", c.AppName())) == "commerce" {
+	switch c.GetString(fmt.Sprintf("%s_magento_type", c.AppName())) {
+	case "enterprise", "commerce":
 		c.Set(fmt.Sprintf("%s_magento_type", c.AppName()), "enterprise")
+	case "mage-os", "mageos":
+		c.Set(fmt.Sprintf("%s_magento_type", c.AppName()), "mage-os")
 	}
 
 	return c.GetString(fmt.Sprintf("%s_magento_type", c.AppName()))
 }
 
+// ValidMagentoTypes returns the supported Magento types.
+func (c *Config) ValidMagentoTypes() []string {
+	return []string{"community", "enterprise", "mage-os"}
+}
+
+// FrontendEnabled returns true if the frontend (node) sidecar is enabled.
+func (c *Config) FrontendEnabled() bool {
+	return c.GetBool(fmt.Sprintf("%s_frontend", c.AppName()))
+}
+
+// HyvaTheme returns the Hyvä theme (Vendor/theme) which is watched by the frontend sidecar.
+func (c *Config) HyvaTheme() string {
+	return strings.Trim(c.GetString(fmt.Sprintf("%s_hyva_theme", c.AppName())), "/")
+}
+
+// FrontendTheme returns the theme (as defined in dev/tools/grunt/configs/themes.js) which is passed to the grunt
+// tasks of the frontend sidecar.
+func (c *Config) FrontendTheme() string {
+	return c.GetString(fmt.Sprintf("%s_frontend_theme", c.AppName()))
+}
+
+// MagentoAdminPassword returns the password of the admin user created by the bootstrap process. If it's empty, a
+// random password is generated.
+func (c *Config) MagentoAdminPassword() string {
+	return c.GetString(fmt.Sprintf("%s_magento_admin_password", c.AppName()))
+}
+
 // MagentoMode returns Magento mode: developer or production (default: developer).
 func (c *Config) MagentoMode() string {
 	return c.GetString(fmt.Sprintf("%s_magento_mode", c.AppName()))
@@ -1276,11 +2235,186 @@ func (c *Config) DBContainer() string {
 	return c.GetString(fmt.Sprintf("%s_env_db_container", c.AppName()))
 }
 
+// DBReadyTimeout returns the time the database has to accept connections before the database commands fail.
+func (c *Config) DBReadyTimeout() time.Duration {
+	return c.GetDuration(fmt.Sprintf("%s_db_ready_timeout", c.AppName()))
+}
+
+// SafetyDump returns true if the database is dumped before its volume is removed by `env down --volumes`.
+func (c *Config) SafetyDump() bool {
+	return c.GetBool(fmt.Sprintf("%s_safety_dump", c.AppName()))
+}
+
+// SafetyDumpDir returns the directory of the database dumps created before the database volume is removed.
+func (c *Config) SafetyDumpDir() string {
+	return filepath.Join(c.Cwd(), fmt.Sprintf(".%s", c.AppName()), "safety-dumps")
+}
+
+// Trash returns the trash of the destructive operations in the application home directory. The operations are kept
+// for reward_trash_retention days (0 disables the trash), and the oldest ones are removed if the trash is larger than
+// reward_trash_max_size.
+func (c *Config) Trash() *trash.Trash {
+	maxSize, err := units.RAMInBytes(c.GetString(fmt.Sprintf("%s_trash_max_size", c.AppName())))
+	if err != nil {
+		log.Warnf("Invalid %s_trash_max_size, the size of the trash is not limited: %s", c.AppName(), err)
+	}
+
+	return trash.New(
+		filepath.Join(c.AppHomeDir(), "trash"), c.GetInt(fmt.Sprintf("%s_trash_retention", c.AppName())), maxSize,
+	)
+}
+
+// RabbitMQUser returns the user of RabbitMQ the application connects with.
+func (c *Config) RabbitMQUser() string {
+	if user := c.GetString("rabbitmq_user"); user != "" {
+		return user
+	}
+
+	return "guest"
+}
+
+// RabbitMQPassword returns the password of the RabbitMQ user.
+func (c *Config) RabbitMQPassword() string {
+	if pass := c.GetString("rabbitmq_password"); pass != "" {
+		return pass
+	}
+
+	return "guest"
+}
+
+// RedisPassword returns the password of Redis. It's empty if Redis doesn't require authentication.
+func (c *Config) RedisPassword() string {
+	return c.GetString("redis_password")
+}
+
+// NotifyAfter returns the duration after which a desktop notification is emitted when the command finishes. The
+// notifications are disabled if it's zero.
+func (c *Config) NotifyAfter() time.Duration {
+	return c.GetDuration(fmt.Sprintf("%s_notify_after", c.AppName()))
+}
+
+// Webhooks returns the webhooks which are notified about the lifecycle events of the environments.
+func (c *Config) Webhooks() []*Webhook {
+	var webhooks []*Webhook
+
+	err := c.UnmarshalKey(fmt.Sprintf("%s_webhooks", c.AppName()), &webhooks)
+	if err != nil {
+		log.Warnf("Cannot unmarshal webhooks: %s", err)
+
+		return nil
+	}
+
+	return webhooks
+}
+
 // SingleWebContainer returns true if Single Web Container setting is enabled in Viper settings.
 func (c *Config) SingleWebContainer() bool {
 	return c.GetBool(fmt.Sprintf("%s_single_web_container", c.AppName()))
 }
 
+// UID returns the user ID which should own the files written to the bind mounts.
+func (c *Config) UID() int {
+	return c.GetInt(fmt.Sprintf("%s_uid", c.AppName()))
+}
+
+// GID returns the group ID which should own the files written to the bind mounts.
+func (c *Config) GID() int {
+	return c.GetInt(fmt.Sprintf("%s_gid", c.AppName()))
+}
+
+// FixPermissionsPaths returns the paths (relative to the synced dir) whose ownership is fixed by the
+// fix-permissions command.
+func (c *Config) FixPermissionsPaths() []string {
+	if c.IsSet(fmt.Sprintf("%s_fix_permissions_paths", c.AppName())) {
+		return c.GetStringSlice(fmt.Sprintf("%s_fix_permissions_paths", c.AppName()))
+	}
+
+	switch c.EnvType() {
+	case "magento2":
+		return []string{"var", "generated", "pub/media", "pub/static"}
+	case "magento1":
+		return []string{"var", "media"}
+	case "shopware":
+		return []string{"var", "files", "public/media", "public/thumbnail"}
+	case "wordpress":
+		return []string{"wp-content/uploads"}
+	case "laravel":
+		return []string{"storage", "bootstrap/cache"}
+	default:
+		return []string{"var"}
+	}
+}
+
+// HistoryFile returns the path of the environment's history log.
+func (c *Config) HistoryFile() string {
+	return filepath.Join(c.Cwd(), fmt.Sprintf(".%s", c.AppName()), "history.log")
+}
+
+// EnvironmentsFile returns the path of the file which records the environments initialized by env-init or started
+// by env up, they're listed by env list.
+func (c *Config) EnvironmentsFile() string {
+	return filepath.Join(c.AppHomeDir(), "environments.json")
+}
+
+// TryEnvironmentsFile returns the path of the file which records the ephemeral environments created by the try
+// command.
+func (c *Config) TryEnvironmentsFile() string {
+	return filepath.Join(c.AppHomeDir(), "try.json")
+}
+
+// NginxContainer returns the name of the container which runs nginx.
+func (c *Config) NginxContainer() string {
+	if c.SingleWebContainer() {
+		return "php-fpm"
+	}
+
+	return "nginx"
+}
+
+// NginxCustomConfigsPath returns the project directory which is mounted as nginx snippets directory.
+func (c *Config) NginxCustomConfigsPath() string {
+	return filepath.Join(c.Cwd(), c.GetString("nginx_custom_configs_path"))
+}
+
+// NginxOverlayPath returns the project directory which contains the additional nginx configuration includes.
+func (c *Config) NginxOverlayPath() string {
+	return filepath.Join(c.Cwd(), c.GetString("nginx_overlay_path"))
+}
+
+// VarnishCustomConfigsPath returns the project directory which contains the VCL snippets.
+func (c *Config) VarnishCustomConfigsPath() string {
+	return filepath.Join(c.Cwd(), c.GetString("varnish_custom_configs_path"))
+}
+
+// PHPSlowlog returns the destination of the php-fpm slowlog, by default it's written to the container's stderr.
+func (c *Config) PHPSlowlog() string {
+	return c.GetString(fmt.Sprintf("%s_php_slowlog", c.AppName()))
+}
+
+// NginxBrotli returns true if the brotli compression preset is enabled.
+func (c *Config) NginxBrotli() bool {
+	return c.GetBool(fmt.Sprintf("%s_nginx_brotli", c.AppName()))
+}
+
+// NginxPresetsEnabled returns true if any of the nginx compression, header or real-ip presets are enabled.
+func (c *Config) NginxPresetsEnabled() bool {
+	return c.GetBool(fmt.Sprintf("%s_nginx_gzip", c.AppName())) ||
+		c.NginxBrotli() ||
+		c.GetBool(fmt.Sprintf("%s_nginx_hsts", c.AppName())) ||
+		c.GetString(fmt.Sprintf("%s_nginx_csp", c.AppName())) != "" ||
+		c.GetBool(fmt.Sprintf("%s_nginx_real_ip", c.AppName()))
+}
+
+// ValidShells returns the shells which can be selected using the --shell flag.
+func ValidShells() []string {
+	return []string{"bash", "zsh", "sh"}
+}
+
+// PreferredShell returns the shell selected by the --shell flag or REWARD_SHELL setting.
+func (c *Config) PreferredShell() string {
+	return c.GetString(c.AppName() + "_shell")
+}
+
 // SetShellContainer changes the container used for the reward shell command.
 func (c *Config) SetShellContainer(envType string) {
 	c.ShellContainer = c.defaultShellContainer(envType)
@@ -1305,17 +2439,29 @@ func (c *Config) defaultShellContainer(envType string) string {
 	switch envType {
 	case "pwa-studio":
 		return "node"
+	case "static":
+		return "nginx"
+	case "custom":
+		if svc := c.GetString("custom_http_service"); svc != "" {
+			return svc
+		}
+
+		return "app"
 	default:
 		return "php-fpm"
 	}
 }
 
 func (c *Config) defaultShellCommand(containerName string) string {
-	conf := viper.GetString(c.AppName() + "_shell_command")
+	conf := c.GetString(c.AppName() + "_shell_command")
 	if conf != "" {
 		return conf
 	}
 
+	if preferred := c.PreferredShell(); preferred != "" {
+		return preferred
+	}
+
 	switch containerName {
 	case "php-fpm":
 		return "bash"
@@ -1350,6 +2496,44 @@ func (c *Config) InstallMarkerFilePath() string {
 	return filepath.Join(c.AppHomeDir(), ".installed")
 }
 
+// Offline returns true if offline mode is enabled: the network calls are skipped (eg. the version matrix refresh,
+// the downloads and the image pulls), and only the locally cached images and assets are used.
+func (c *Config) Offline() bool {
+	return c.GetBool(fmt.Sprintf("%s_offline", c.AppName()))
+}
+
+// BandwidthLimit returns the bandwidth limit of the network transfers (eg. the downloads) in bytes per second. Zero
+// means unlimited.
+func (c *Config) BandwidthLimit() int64 {
+	limit := c.GetString(fmt.Sprintf("%s_bandwidth_limit", c.AppName()))
+	if limit == "" || limit == "0" {
+		return 0
+	}
+
+	bytesPerSecond, err := units.RAMInBytes(limit)
+	if err != nil {
+		log.Warnf("Invalid %s_bandwidth_limit, the bandwidth is not limited: %s", c.AppName(), err)
+
+		return 0
+	}
+
+	return bytesPerSecond
+}
+
+// PullConcurrency returns the number of the images pulled concurrently by docker compose. Zero means the default of
+// docker compose. If the bandwidth is limited, the images are pulled one by one by default.
+func (c *Config) PullConcurrency() int {
+	if n := c.GetInt(fmt.Sprintf("%s_pull_concurrency", c.AppName())); n > 0 {
+		return n
+	}
+
+	if c.BandwidthLimit() > 0 {
+		return 1
+	}
+
+	return 0
+}
+
 func (c *Config) GitHubToken() string {
 	return c.GetString("github_token")
 }
@@ -1371,10 +2555,25 @@ func (c *Config) AdditionalServices() []string {
 }
 
 type Plugin struct {
-	Name        string
-	Path        string
-	Description string
-	URL         string
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	// SHA256 is the checksum of the archive if the URL points to an archive instead of a GitHub releases API.
+	SHA256 string `json:"sha256,omitempty"`
+	// Version is the latest version of the plugin if the URL points to an archive, it replaces the {version}
+	// placeholder of the URL.
+	Version string `json:"version,omitempty"`
+}
+
+// Webhook is an HTTP endpoint (eg. a Slack incoming webhook) which is notified about the lifecycle events. The
+// events are glob patterns (eg. "*.failed"), all the events are sent if they're empty. The payload is rendered from
+// the template if it's set, otherwise the default payload of the type (slack or generic) is sent.
+type Webhook struct {
+	URL      string
+	Type     string
+	Events   []string
+	Template string
 }
 
 func (p *Plugin) String() string {
@@ -0,0 +1,759 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type ConfigTestSuite struct {
+	suite.Suite
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func newTestConfig(settings map[string]interface{}) *Config {
+	v := viper.New()
+	v.Set("app_name", "reward")
+
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	return &Config{Viper: v}
+}
+
+func (suite *ConfigTestSuite) TestCheckTraefikHTTP3() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "disabled with old traefik",
+			settings: map[string]interface{}{"reward_traefik_http3": false, "reward_traefik_version": "2.2"},
+		},
+		{
+			name:     "enabled with old traefik",
+			settings: map[string]interface{}{"reward_traefik_http3": true, "reward_traefik_version": "2.2"},
+			wantErr:  true,
+		},
+		{
+			name:     "enabled with traefik 2.5",
+			settings: map[string]interface{}{"reward_traefik_http3": true, "reward_traefik_version": "v2.5.7"},
+			wantErr:  true,
+		},
+		{
+			name:     "enabled with traefik 2.6",
+			settings: map[string]interface{}{"reward_traefik_http3": true, "reward_traefik_version": "2.6"},
+		},
+		{
+			name:     "enabled with traefik 3",
+			settings: map[string]interface{}{"reward_traefik_http3": true, "reward_traefik_version": "3.0"},
+		},
+		{
+			name:     "enabled with non-numeric tag",
+			settings: map[string]interface{}{"reward_traefik_http3": true, "reward_traefik_version": "latest"},
+		},
+		{
+			name: "enabled with rolling restart",
+			settings: map[string]interface{}{
+				"reward_traefik_http3":           true,
+				"reward_traefik_version":         "2.6",
+				"reward_traefik_rolling_restart": true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			err := newTestConfig(tt.settings).CheckTraefikHTTP3()
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestContainerUI() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+		wantErr  bool
+	}{
+		{name: "portainer by default", settings: map[string]interface{}{"reward_portainer": true}, want: "portainer"},
+		{name: "portainer disabled", settings: map[string]interface{}{"reward_portainer": false}, want: "none"},
+		{
+			name:     "yacht",
+			settings: map[string]interface{}{"reward_portainer": true, "reward_container_ui": "Yacht"},
+			want:     "yacht",
+		},
+		{name: "disabled", settings: map[string]interface{}{"reward_container_ui": "none"}, want: "none"},
+		{name: "unknown", settings: map[string]interface{}{"reward_container_ui": "rancher"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := newTestConfig(tt.settings).ContainerUI()
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestDNSServer() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+		wantErr  bool
+	}{
+		{name: "dnsmasq by default", want: "dnsmasq"},
+		{name: "coredns", settings: map[string]interface{}{"reward_dns_server": "CoreDNS"}, want: "coredns"},
+		{name: "unknown", settings: map[string]interface{}{"reward_dns_server": "unbound"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := newTestConfig(tt.settings).DNSServer()
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestNoColor() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		env      string
+		want     bool
+	}{
+		{name: "colors by default", want: false},
+		{name: "no_color", settings: map[string]interface{}{"no_color": true}, want: true},
+		{name: "deprecated disable_colors", settings: map[string]interface{}{"disable_colors": true}, want: true},
+		{name: "NO_COLOR environment variable", env: "1", want: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.env)
+
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).NoColor())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestNamespaceFromUsername() {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+	}{
+		{name: "simple", username: "alice", want: "alice"},
+		{name: "uppercase", username: "Alice", want: "alice"},
+		{name: "dots and underscores", username: "alice.smith_2", want: "alice-smith-2"},
+		{name: "domain user", username: `CORP\alice`, want: "corp-alice"},
+		{name: "leading and trailing separators", username: "_alice_", want: "alice"},
+		{name: "email", username: "alice@example.com", want: "alice-example-com"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, namespaceFromUsername(tt.username))
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestApplyNamespace() {
+	tests := []struct {
+		name       string
+		settings   map[string]interface{}
+		wantEnv    string
+		wantDomain string
+	}{
+		{
+			name:       "prefixes env name and domain",
+			settings:   map[string]interface{}{"reward_env_name": "shop", "traefik_domain": "shop.test"},
+			wantEnv:    "alice-shop",
+			wantDomain: "alice.shop.test",
+		},
+		{
+			name: "already prefixed",
+			settings: map[string]interface{}{
+				"reward_env_name": "alice-shop", "traefik_domain": "alice.shop.test",
+			},
+			wantEnv:    "alice-shop",
+			wantDomain: "alice.shop.test",
+		},
+		{
+			name:     "no domain",
+			settings: map[string]interface{}{"reward_env_name": "shop"},
+			wantEnv:  "alice-shop",
+		},
+		{
+			name: "empty namespace",
+			settings: map[string]interface{}{
+				"reward_namespace": "", "reward_env_name": "shop", "traefik_domain": "shop.test",
+			},
+			wantEnv:    "shop",
+			wantDomain: "shop.test",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			settings := map[string]interface{}{"reward_namespace": "alice"}
+			for key, value := range tt.settings {
+				settings[key] = value
+			}
+
+			c := newTestConfig(settings)
+			c.applyNamespace()
+
+			assert.Equal(t, tt.wantEnv, c.EnvName())
+			assert.Equal(t, tt.wantDomain, c.TraefikDomain())
+			assert.False(t, c.PortOffsetConfigured(), "the port offset is allocated by the env commands")
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestFrontendSyncIgnore() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "disabled",
+			settings: map[string]interface{}{"reward_env_type": "magento2", "reward_hyva_theme": "Acme/hyva"},
+		},
+		{
+			name:     "not magento2",
+			settings: map[string]interface{}{"reward_env_type": "laravel", "reward_frontend": true},
+		},
+		{
+			name: "theme",
+			settings: map[string]interface{}{
+				"reward_env_type": "magento2", "reward_frontend": true, "reward_hyva_theme": "/Acme/hyva/",
+			},
+			want: []string{
+				"/var/view_preprocessed",
+				"/app/design/frontend/Acme/hyva/web/tailwind/node_modules",
+				"/app/design/frontend/Acme/hyva/web/css/styles.css",
+			},
+		},
+		{
+			name:     "no theme",
+			settings: map[string]interface{}{"reward_env_type": "magento2", "reward_frontend": true},
+			want: []string{
+				"/var/view_preprocessed",
+				"/app/design/frontend/*/*/web/tailwind/node_modules",
+				"/app/design/frontend/*/*/web/css/styles.css",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).FrontendSyncIgnore())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestConsoleCommand() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "symfony",
+			settings: map[string]interface{}{"reward_env_type": "sylius"},
+			want:     []string{"php", "bin/console"},
+		},
+		{
+			name:     "typo3 composer mode",
+			settings: map[string]interface{}{"reward_env_type": "typo3", "typo3_composer_mode": "true"},
+			want:     []string{"vendor/bin/typo3"},
+		},
+		{
+			name:     "typo3 legacy mode",
+			settings: map[string]interface{}{"reward_env_type": "typo3", "typo3_composer_mode": "false"},
+			want:     []string{"php", "typo3/sysext/core/bin/typo3"},
+		},
+		{
+			name:     "craft",
+			settings: map[string]interface{}{"reward_env_type": "craft"},
+			want:     []string{"php", "craft"},
+		},
+		{
+			name:     "not available",
+			settings: map[string]interface{}{"reward_env_type": "magento2"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).ConsoleCommand())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestAPMWarnings() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     int
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "keys set",
+			settings: map[string]interface{}{
+				"reward_newrelic": "true", "newrelic_license_key": "key",
+				"reward_datadog": "true", "dd_api_key": "key",
+			},
+		},
+		{
+			name:     "keys missing",
+			settings: map[string]interface{}{"reward_newrelic": "true", "reward_datadog": "true"},
+			want:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Len(t, newTestConfig(tt.settings).APMWarnings(), tt.want)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestGlitchTipInternalDSN() {
+	tests := []struct {
+		name    string
+		dsn     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "public dsn",
+			dsn:  "https://0123456789abcdef@glitchtip.reward.test/2",
+			want: "http://0123456789abcdef@glitchtip:8000/2",
+		},
+		{
+			name:    "missing key",
+			dsn:     "https://glitchtip.reward.test/2",
+			wantErr: true,
+		},
+		{
+			name:    "missing project",
+			dsn:     "https://0123456789abcdef@glitchtip.reward.test/",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := newTestConfig(map[string]interface{}{"glitchtip_dsn": tt.dsn}).GlitchTipInternalDSN()
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestCustomComposeFiles() {
+	cwd, err := os.Getwd()
+	assert.NoError(suite.T(), err)
+
+	tests := []struct {
+		name  string
+		files string
+		want  []string
+	}{
+		{
+			name: "default",
+			want: []string{filepath.Join(cwd, "docker-compose.yml")},
+		},
+		{
+			name:  "multiple files",
+			files: "compose.yml, compose.dev.yml /opt/compose.yml",
+			want: []string{
+				filepath.Join(cwd, "compose.yml"),
+				filepath.Join(cwd, "compose.dev.yml"),
+				"/opt/compose.yml",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			conf := newTestConfig(map[string]interface{}{"custom_compose_files": tt.files})
+			assert.Equal(t, tt.want, conf.CustomComposeFiles())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestImageOverrides() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     map[string]string
+	}{
+		{
+			name: "unset",
+			want: map[string]string{},
+		},
+		{
+			name: "map",
+			settings: map[string]interface{}{
+				"reward_image_overrides": map[string]interface{}{"php-fpm": "acme/php-fpm:8.1"},
+			},
+			want: map[string]string{"php-fpm": "acme/php-fpm:8.1"},
+		},
+		{
+			name: "list",
+			settings: map[string]interface{}{
+				"reward_image_overrides": "php-fpm=acme/php-fpm:8.1, nginx=acme/nginx:1.24 invalid",
+			},
+			want: map[string]string{"php-fpm": "acme/php-fpm:8.1", "nginx": "acme/nginx:1.24"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).ImageOverrides())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestMutagenBinary() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "managed",
+			settings: map[string]interface{}{"reward_mutagen_managed": true, "reward_home_dir": "/home/.reward"},
+			want:     filepath.Join("/home/.reward", "mutagen", "mutagen"),
+		},
+		{
+			name:     "unmanaged",
+			settings: map[string]interface{}{"reward_mutagen_managed": false, "reward_home_dir": "/home/.reward"},
+			want:     "mutagen",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" && tt.want != "mutagen" {
+				tt.want += ".exe"
+			}
+
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).MutagenBinary())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestMutagenURL() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "pinned version",
+			settings: map[string]interface{}{"reward_mutagen_version": "v0.17.5"},
+			want:     "https://github.com/mutagen-io/mutagen/releases/download/v0.17.5/mutagen_",
+		},
+		{
+			name:     "custom url",
+			settings: map[string]interface{}{"reward_mutagen_url": "https://example.com/mutagen.tar.gz"},
+			want:     "https://example.com/mutagen.tar.gz",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.True(t, strings.HasPrefix(newTestConfig(tt.settings).MutagenURL(), tt.want))
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestSyncProtectedPaths() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name:     "magento2 default",
+			settings: map[string]interface{}{"reward_env_type": "magento2"},
+			want:     []string{"app/etc/env.php", ".env"},
+		},
+		{
+			name:     "from .env",
+			settings: map[string]interface{}{"reward_env_type": "magento2", "reward_sync_protected_paths": ".env, auth.json"},
+			want:     []string{".env", "auth.json"},
+		},
+		{
+			name:     "disabled",
+			settings: map[string]interface{}{"reward_env_type": "magento2", "reward_sync_protected_paths": ""},
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, newTestConfig(tt.settings).SyncProtectedPaths())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestSyncMode() {
+	got, err := newTestConfig(map[string]interface{}{"reward_sync_mode": "Two-Way-Safe"}).SyncMode()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "two-way-safe", got)
+
+	_, err = newTestConfig(map[string]interface{}{"reward_sync_mode": "both"}).SyncMode()
+	assert.Error(suite.T(), err)
+}
+
+func (suite *ConfigTestSuite) TestDestructiveCommand() {
+	tests := []struct {
+		command string
+		args    []string
+		want    bool
+	}{
+		{command: "db import", want: true},
+		{command: "db dump", want: false},
+		{command: "env promote", args: []string{"myproject-upgrade"}, want: true},
+		{command: "env", args: []string{"down", "-v"}, want: true},
+		{command: "env", args: []string{"down", "--volumes", "--force-destructive"}, want: true},
+		{command: "env", args: []string{"down", "--volumes=db,elasticsearch"}, want: true},
+		{command: "env", args: []string{"down"}, want: false},
+		{command: "env", args: []string{"up", "-v"}, want: false},
+		{command: "svc", args: []string{"down", "-v"}, want: true},
+		{command: "svc", args: []string{"down", "--volumes"}, want: true},
+		{command: "svc", args: []string{"down"}, want: false},
+		{command: "env rotate-credentials", want: true},
+		{command: "env rotate-credentials", args: []string{"db"}, want: true},
+		{command: "search migrate", want: true},
+		{command: "search", want: false},
+		{command: "undo last", want: true},
+		{command: "undo list", want: false},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(strings.Join(append([]string{tt.command}, tt.args...), " "), func(t *testing.T) {
+			assert.Equal(t, tt.want, DestructiveCommand(tt.command, tt.args))
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestSettingsDropped() {
+	c := newTestConfig(map[string]interface{}{
+		"reward_env_name": "MyProject",
+		"reward_db":       true,
+	})
+
+	assert.Equal(suite.T(), "myproject", c.EnvName())
+	assert.True(suite.T(), c.ServiceEnabled("db"))
+	assert.False(suite.T(), c.ServiceEnabled("redis"))
+	assert.True(suite.T(), c.SvcEnabledPermissive("redis"))
+
+	// the typed copy of the settings is dropped when they change
+	c.Set("reward_env_name", "other")
+	c.Set("reward_db", false)
+	c.SetDefault("reward_redis", true)
+
+	assert.Equal(suite.T(), "other", c.EnvName())
+	assert.False(suite.T(), c.ServiceEnabled("db"))
+	assert.True(suite.T(), c.ServiceEnabled("redis"))
+}
+
+func BenchmarkServiceEnabled(b *testing.B) {
+	c := newTestConfig(map[string]interface{}{"reward_db": true})
+
+	for i := 0; i < b.N; i++ {
+		c.ServiceEnabled("db")
+	}
+}
+
+func (suite *ConfigTestSuite) TestDockerPeeredServices() {
+	common := func(id, name string) types.Container {
+		return types.Container{ID: id, State: "running", Labels: map[string]string{
+			"dev.reward.container.name":   name,
+			"dev.reward.environment.name": "reward",
+		}}
+	}
+
+	fake := &docker.Fake{Containers: []types.Container{
+		common("traefik", "traefik"),
+		common("mailhog", "mailhog"),
+		common("adminer", "adminer"),
+		// a container of an environment with the same service name is not peered
+		{ID: "other", State: "running", Labels: map[string]string{
+			"dev.reward.container.name":   "mailhog",
+			"dev.reward.environment.name": "myproject",
+		}},
+	}}
+
+	c := newTestConfig(map[string]interface{}{
+		"reward_services":          []string{"traefik", "mailhog"},
+		"reward_optional_services": []string{"adminer"},
+	})
+	c.Docker = docker.NewClientWithAPI(fake)
+
+	assert.NoError(suite.T(), c.DockerPeeredServices("connect", "myproject_default"))
+	assert.ElementsMatch(suite.T(), []string{"traefik", "mailhog"}, fake.Connections["myproject_default"])
+
+	assert.NoError(suite.T(), c.DockerPeeredServices("disconnect", "myproject_default"))
+	assert.Empty(suite.T(), fake.Connections["myproject_default"])
+
+	assert.ErrorIs(suite.T(), c.DockerPeeredServices("restart", "myproject_default"), ErrUnknownAction)
+}
+
+func (suite *ConfigTestSuite) TestBandwidthLimit() {
+	tests := []struct {
+		name            string
+		limit           string
+		concurrency     int
+		want            int64
+		wantConcurrency int
+	}{
+		{name: "unlimited"},
+		{name: "zero", limit: "0"},
+		{name: "limited", limit: "1MiB", want: 1024 * 1024, wantConcurrency: 1},
+		{name: "limited with concurrency", limit: "500k", concurrency: 2, want: 500 * 1024, wantConcurrency: 2},
+		{name: "concurrency only", concurrency: 4, wantConcurrency: 4},
+		{name: "invalid", limit: "fast"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestConfig(map[string]interface{}{
+				"reward_bandwidth_limit":  tt.limit,
+				"reward_pull_concurrency": tt.concurrency,
+			})
+
+			assert.Equal(t, tt.want, c.BandwidthLimit())
+			assert.Equal(t, tt.wantConcurrency, c.PullConcurrency())
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestCommandFlags() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		command  string
+		want     []string
+	}{
+		{
+			name:    "not set",
+			command: "env up",
+		},
+		{
+			name: "global list",
+			settings: map[string]interface{}{
+				"reward_command_flags": map[string]interface{}{"db dump": []interface{}{"--compress", "gzip"}},
+			},
+			command: "db dump",
+			want:    []string{"--compress", "gzip"},
+		},
+		{
+			name: "global string",
+			settings: map[string]interface{}{
+				"reward_command_flags": map[string]interface{}{"env up": "--wait  --wait-timeout 5m"},
+			},
+			command: "env up",
+			want:    []string{"--wait", "--wait-timeout", "5m"},
+		},
+		{
+			name: "project overrides global",
+			settings: map[string]interface{}{
+				"reward_command_flags":        map[string]interface{}{"env up": "--wait"},
+				"reward_command_flags_env_up": "--build",
+			},
+			command: "env up",
+			want:    []string{"--build"},
+		},
+		{
+			name:     "project with dash",
+			settings: map[string]interface{}{"reward_command_flags_upgrade_env": "--name next"},
+			command:  "upgrade-env",
+			want:     []string{"--name", "next"},
+		},
+		{
+			name: "other command",
+			settings: map[string]interface{}{
+				"reward_command_flags": map[string]interface{}{"env up": "--wait"},
+			},
+			command: "env down",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).CommandFlags(tt.command))
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestTraefikDashboardAllowedIPs() {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{name: "unset"},
+		{name: "comma separated", value: "192.168.1.0/24, 10.0.0.0/8", want: []string{"192.168.1.0/24", "10.0.0.0/8"}},
+		{name: "list", value: []string{"192.168.1.0/24"}, want: []string{"192.168.1.0/24"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestConfig(map[string]interface{}{"reward_traefik_dashboard_allowed_ips": tt.value})
+
+			assert.Equal(t, tt.want, c.TraefikDashboardAllowedIPs())
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+)
+
+// daemonTimeout is the time the daemon has to answer the commands, they fall back to the local checks if it doesn't.
+const daemonTimeout = 500 * time.Millisecond
+
+// DaemonState is the address and the token of the running daemon. It's saved to the home directory of the application
+// when the daemon starts, so the commands and the IDE plugins can find it.
+type DaemonState struct {
+	Address string `json:"address"`
+	Token   string `json:"token"`
+	PID     int    `json:"pid"`
+}
+
+// DaemonAddress returns the localhost address the daemon listens on.
+func (c *Config) DaemonAddress() string {
+	return c.GetString(fmt.Sprintf("%s_daemon_address", c.AppName()))
+}
+
+// DaemonStateFile returns the path of the file the state of the running daemon is saved to.
+func (c *Config) DaemonStateFile() string {
+	return filepath.Join(c.AppHomeDir(), "daemon.json")
+}
+
+// WriteDaemonState saves the state of the running daemon. The file is only readable by the user as the token grants
+// access to the API of the daemon.
+func (c *Config) WriteDaemonState(state *DaemonState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("cannot marshal daemon state: %w", err)
+	}
+
+	err = os.MkdirAll(c.AppHomeDir(), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	err = os.WriteFile(c.DaemonStateFile(), content, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write daemon state: %w", err)
+	}
+
+	return nil
+}
+
+// DaemonState returns the state of the running daemon, it's nil if the daemon is not running.
+func (c *Config) DaemonState() *DaemonState {
+	content, err := os.ReadFile(c.DaemonStateFile())
+	if err != nil {
+		return nil
+	}
+
+	var state DaemonState
+
+	err = json.Unmarshal(content, &state)
+	if err != nil || state.Address == "" {
+		return nil
+	}
+
+	return &state
+}
+
+// DaemonRequest sends a GET request to the API of the running daemon and decodes the json response into v.
+func (c *Config) DaemonRequest(state *DaemonState, path string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+state.Address+path, nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+state.Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("daemon responded with %s", resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("cannot decode response: %w", err)
+	}
+
+	return nil
+}
+
+// ComponentVersions returns the installed versions of docker and docker-compose.
+func (c *Config) ComponentVersions() (map[string]string, error) {
+	versions := make(map[string]string, len(compatibilityMatrix))
+
+	for _, component := range compatibilityMatrix {
+		installed, err := component.Version(c)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch %s version: %w", component.Component, err)
+		}
+
+		versions[component.Component] = installed.String()
+	}
+
+	return versions, nil
+}
+
+// daemonComponentVersions returns the versions of docker and docker-compose detected by the running daemon, so the
+// commands don't have to run docker and docker-compose to check them. It's nil if the daemon is not running.
+func (c *Config) daemonComponentVersions() map[string]*version.Version {
+	state := c.DaemonState()
+	if state == nil {
+		return nil
+	}
+
+	var resp struct {
+		Versions map[string]string `json:"versions"`
+	}
+
+	err := c.DaemonRequest(state, "/v1/preflight", &resp)
+	if err != nil {
+		log.Debugf("Cannot get the component versions from the daemon: %s", err)
+
+		return nil
+	}
+
+	versions := make(map[string]*version.Version, len(resp.Versions))
+
+	for component, v := range resp.Versions {
+		parsed, err := version.NewVersion(v)
+		if err != nil {
+			return nil
+		}
+
+		versions[component] = parsed
+	}
+
+	return versions
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrInvalidEnvFileReference occurs when a reference of the .env file cannot be resolved.
+var ErrInvalidEnvFileReference = func(ref string) error {
+	return fmt.Errorf("invalid reference in .env file: %s", ref)
+}
+
+const randomCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var (
+	envFileLineRegex      = regexp.MustCompile(`^(\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*)(.*)$`)
+	envFileReferenceRegex = regexp.MustCompile(`\$\{(random|env):([^}]*)\}`)
+)
+
+// resolveEnvFileReferences resolves the ${random:N} and ${env:NAME} references of the .env file in memory. The
+// resolved values replace the values of the .env file in the configuration, so the flags and the environment variables
+// still take precedence. The .env file is not written, the generated values are persisted by PersistEnvFileReferences.
+func (c *Config) resolveEnvFileReferences() error {
+	content, err := FS.ReadFile(".env")
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	values, updated, err := resolveEnvFile(string(content), os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	settings := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		settings[key] = value
+	}
+
+	err = c.MergeConfigMap(settings)
+	if err != nil {
+		return fmt.Errorf("cannot merge .env file references: %w", err)
+	}
+
+	c.envFile, c.envFileGenerated = string(content), updated
+
+	return nil
+}
+
+// PersistEnvFileReferences writes the generated values of the ${random:N} references to the .env file, so they remain
+// stable in later runs. The .env file is not written if it was changed since the configuration was loaded.
+func (c *Config) PersistEnvFileReferences() error {
+	if c.envFileGenerated == c.envFile {
+		return nil
+	}
+
+	stat, err := FS.Stat(".env")
+	if err != nil {
+		return fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	content, err := FS.ReadFile(".env")
+	if err != nil {
+		return fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	if string(content) != c.envFile {
+		log.Warnln("The .env file was changed, the generated values are not persisted.")
+
+		return nil
+	}
+
+	log.Debugln("Persisting generated values to .env file...")
+
+	err = FS.WriteFile(".env", []byte(c.envFileGenerated), stat.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("cannot write .env file: %w", err)
+	}
+
+	c.envFile = c.envFileGenerated
+
+	return nil
+}
+
+// resolveEnvFile returns the resolved values of the keys which contain references and the content of the .env file
+// with the ${random:N} references replaced by the generated values. Single-quoted values are not resolved.
+func resolveEnvFile(content string, getenv func(string) string) (map[string]string, string, error) {
+	values := make(map[string]string)
+	lines := strings.SplitAfter(content, "\n")
+
+	for i, line := range lines {
+		m := envFileLineRegex.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if m == nil || !envFileReferenceRegex.MatchString(m[3]) || strings.HasPrefix(m[3], "'") {
+			continue
+		}
+
+		var err error
+
+		persisted := envFileReferenceRegex.ReplaceAllStringFunc(m[3], func(ref string) string {
+			r := envFileReferenceRegex.FindStringSubmatch(ref)
+			if r[1] != "random" {
+				return ref
+			}
+
+			n, e := strconv.Atoi(r[2])
+			if e != nil || n < 1 || n > 256 {
+				err = ErrInvalidEnvFileReference(ref)
+
+				return ref
+			}
+
+			s, e := randomString(n)
+			if e != nil {
+				err = e
+			}
+
+			return s
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		resolved := envFileReferenceRegex.ReplaceAllStringFunc(persisted, func(ref string) string {
+			return getenv(envFileReferenceRegex.FindStringSubmatch(ref)[2])
+		})
+
+		if len(resolved) >= 2 && strings.HasPrefix(resolved, `"`) && strings.HasSuffix(resolved, `"`) {
+			resolved = resolved[1 : len(resolved)-1]
+		}
+
+		values[m[2]] = resolved
+		lines[i] = m[1] + persisted + line[len(strings.TrimRight(line, "\r\n")):]
+	}
+
+	return values, strings.Join(lines, ""), nil
+}
+
+// randomString returns a cryptographically secure random alphanumeric string of length n.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(randomCharset))))
+		if err != nil {
+			return "", fmt.Errorf("cannot generate random value: %w", err)
+		}
+
+		b[i] = randomCharset[idx.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvFileTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvFileTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvFileTestSuite))
+}
+
+func (suite *EnvFileTestSuite) TestResolveEnvFile() {
+	getenv := func(name string) string {
+		return map[string]string{"HOME": "/home/user"}[name]
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		wantValues  map[string]string
+		wantContent string
+		wantErr     bool
+	}{
+		{
+			name:        "no references",
+			content:     "REWARD_ENV_NAME=test\n# ${random:8}\n",
+			wantValues:  map[string]string{},
+			wantContent: "REWARD_ENV_NAME=test\n# ${random:8}\n",
+		},
+		{
+			name:        "env reference is resolved but not persisted",
+			content:     "REWARD_ENV_NAME=test\nREWARD_COMPOSER_DIR=\"${env:HOME}/.composer\"\n",
+			wantValues:  map[string]string{"REWARD_COMPOSER_DIR": "/home/user/.composer"},
+			wantContent: "REWARD_ENV_NAME=test\nREWARD_COMPOSER_DIR=\"${env:HOME}/.composer\"\n",
+		},
+		{
+			name:        "single-quoted value is literal",
+			content:     "REWARD_DB_PASSWORD='${random:16}'\n",
+			wantValues:  map[string]string{},
+			wantContent: "REWARD_DB_PASSWORD='${random:16}'\n",
+		},
+		{
+			name:    "invalid random length",
+			content: "REWARD_DB_PASSWORD=${random:x}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			values, content, err := resolveEnvFile(tt.content, getenv)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantValues, values)
+			assert.Equal(t, tt.wantContent, content)
+		})
+	}
+}
+
+func (suite *EnvFileTestSuite) TestResolveEnvFileRandom() {
+	values, content, err := resolveEnvFile("# comment\r\nexport REWARD_DB_PASSWORD=${random:16}\r\n", nil)
+	suite.NoError(err)
+
+	suite.Regexp(regexp.MustCompile(`^[a-zA-Z0-9]{16}$`), values["REWARD_DB_PASSWORD"])
+	suite.Equal("# comment\r\nexport REWARD_DB_PASSWORD="+values["REWARD_DB_PASSWORD"]+"\r\n", content)
+
+	// The persisted value is stable.
+	values2, content2, err := resolveEnvFile(content, nil)
+	suite.NoError(err)
+	suite.Empty(values2)
+	suite.Equal(content, content2)
+}
+
+func (suite *EnvFileTestSuite) TestResolveEnvFileReferences() {
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+	defer func() {
+		FS = &afero.Afero{Fs: afero.NewOsFs()}
+	}()
+
+	content := "REWARD_DB_PASSWORD=${random:16}\nREWARD_REDIS_PASSWORD=${random:16}\nREWARD_PATH=${env:HOME}/bin\n"
+	assert.NoError(suite.T(), FS.WriteFile(".env", []byte(content), 0o600))
+
+	suite.T().Setenv("HOME", "/home/user")
+	suite.T().Setenv("REWARD_REDIS_PASSWORD", "fromenv")
+
+	v := viper.New()
+	v.Set("app_name", "reward")
+	v.AutomaticEnv()
+	v.SetConfigFile(".env")
+	v.SetConfigType("dotenv")
+	v.SetFs(FS)
+	assert.NoError(suite.T(), v.ReadInConfig())
+
+	c := &Config{Viper: v}
+	assert.NoError(suite.T(), c.resolveEnvFileReferences())
+
+	password := c.GetString("reward_db_password")
+	suite.Regexp(regexp.MustCompile(`^[a-zA-Z0-9]{16}$`), password)
+	suite.Equal("/home/user/bin", c.GetString("reward_path"))
+
+	// the environment variables take precedence over the resolved values
+	suite.Equal("fromenv", c.GetString("reward_redis_password"))
+
+	// the .env file is not written while the configuration is loaded
+	got, err := FS.ReadFile(".env")
+	assert.NoError(suite.T(), err)
+	suite.Equal(content, string(got))
+
+	assert.NoError(suite.T(), c.PersistEnvFileReferences())
+
+	got, err = FS.ReadFile(".env")
+	assert.NoError(suite.T(), err)
+	suite.Contains(string(got), "REWARD_DB_PASSWORD="+password+"\n")
+	suite.Contains(string(got), "REWARD_PATH=${env:HOME}/bin\n")
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+var (
+	// ErrInvalidEnvSettingKey occurs when the key is not a valid .env variable name.
+	ErrInvalidEnvSettingKey = func(key string) error {
+		return fmt.Errorf("invalid setting name: %s", key)
+	}
+	// ErrInvalidEnvSettingValue occurs when the value doesn't match the type of the setting.
+	ErrInvalidEnvSettingValue = func(key, value, expected string) error {
+		return fmt.Errorf("invalid value for %s: %q (expected %s)", key, value, expected)
+	}
+	// ErrReadOnlyEnvSetting occurs when a setting cannot be changed by editing the .env file.
+	ErrReadOnlyEnvSetting = func(key, hint string) error {
+		return fmt.Errorf("%s cannot be changed, %s", key, hint)
+	}
+)
+
+// EnvSettingType is the type of the value of an .env setting.
+type EnvSettingType string
+
+const (
+	EnvSettingString  EnvSettingType = "string"
+	EnvSettingBool    EnvSettingType = "boolean"
+	EnvSettingInt     EnvSettingType = "integer"
+	EnvSettingVersion EnvSettingType = "version"
+)
+
+// EnvSetting describes a known setting of the project .env file.
+type EnvSetting struct {
+	Key  string
+	Type EnvSettingType
+	// CLIOnly is true if the setting is only read by the CLI, so the containers don't have to be recreated.
+	CLIOnly bool
+	// ReadOnly contains the reason (and the alternative) if the setting cannot be changed in the .env file.
+	ReadOnly string
+}
+
+var (
+	envSettingKeyRegex     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	envSettingVersionRegex = regexp.MustCompile(`^\d+(\.\d+)*(-[0-9A-Za-z.]+)?$`)
+)
+
+// EnvSettings returns the known settings of the project .env file.
+func (c *Config) EnvSettings() []EnvSetting {
+	app := strings.ToUpper(c.AppName())
+
+	settings := []EnvSetting{
+		{
+			Key:      app + "_ENV_NAME",
+			Type:     EnvSettingString,
+			ReadOnly: fmt.Sprintf("use `%s env clone` to duplicate the environment under a new name", c.AppName()),
+		},
+		{
+			Key:      app + "_ENV_TYPE",
+			Type:     EnvSettingString,
+			ReadOnly: fmt.Sprintf("use `%s env-init` to initialize the environment again", c.AppName()),
+		},
+		{Key: app + "_WEB_ROOT", Type: EnvSettingString},
+		{Key: app + "_SYNC_IGNORE", Type: EnvSettingString, CLIOnly: true},
+		{Key: app + "_FRONTEND_THEME", Type: EnvSettingString, CLIOnly: true},
+		{Key: app + "_HYVA_THEME", Type: EnvSettingString},
+		{Key: app + "_PORT_OFFSET", Type: EnvSettingInt},
+		{Key: app + "_NGINX_GZIP_COMP_LEVEL", Type: EnvSettingInt},
+		{Key: app + "_NGINX_BROTLI_COMP_LEVEL", Type: EnvSettingInt},
+		{Key: app + "_NGINX_HSTS_MAX_AGE", Type: EnvSettingInt},
+		{Key: app + "_PHP_SLOWLOG_TIMEOUT", Type: EnvSettingInt},
+		{Key: "TRAEFIK_DOMAIN", Type: EnvSettingString},
+		{Key: "TRAEFIK_SUBDOMAIN", Type: EnvSettingString},
+		{Key: "TRAEFIK_EXTRA_HOSTS", Type: EnvSettingString},
+	}
+
+	for _, s := range []string{
+		"DB", "REDIS", "ELASTICSEARCH", "OPENSEARCH", "OPENSEARCH_DASHBOARDS", "VARNISH", "RABBITMQ", "MERCURE",
+		"ALLURE", "SELENIUM", "SELENIUM_DEBUG", "BLACKFIRE", "SPLIT_SALES", "SPLIT_CHECKOUT", "TEST_DB", "MAGEPACK",
+		"FRONTEND", "SYNC_ENABLED", "SHARED_COMPOSER", "SINGLE_WEB_CONTAINER", "NGINX_GZIP", "NGINX_BROTLI",
+		"NGINX_HSTS", "NGINX_CSP_REPORT_ONLY", "NGINX_REAL_IP",
+	} {
+		settings = append(settings, EnvSetting{Key: fmt.Sprintf("%s_%s", app, s), Type: EnvSettingBool})
+	}
+
+	for _, s := range []string{
+		"COMPOSER", "ELASTICSEARCH", "MAGEPACK", "MARIADB", "NGINX", "NODE", "OPENSEARCH", "PHP", "RABBITMQ", "REDIS",
+		"VARNISH", "XDEBUG",
+	} {
+		settings = append(settings, EnvSetting{Key: s + "_VERSION", Type: EnvSettingVersion})
+	}
+
+	return settings
+}
+
+// EnvSetting returns the known setting of the .env file with the given key.
+func (c *Config) EnvSetting(key string) (EnvSetting, bool) {
+	for _, s := range c.EnvSettings() {
+		if strings.EqualFold(s.Key, key) {
+			return s, true
+		}
+	}
+
+	return EnvSetting{}, false
+}
+
+// ValidateEnvSetting validates the value of an .env setting. It returns an error if the value cannot be used and
+// warnings if the key is unknown or the version is not known to be supported.
+func (c *Config) ValidateEnvSetting(key, value string) ([]string, error) {
+	if !envSettingKeyRegex.MatchString(key) {
+		return nil, ErrInvalidEnvSettingKey(key)
+	}
+
+	setting, ok := c.EnvSetting(key)
+	if !ok {
+		return []string{fmt.Sprintf("%s is not a known setting", key)}, nil
+	}
+
+	if setting.ReadOnly != "" {
+		return nil, ErrReadOnlyEnvSetting(key, setting.ReadOnly)
+	}
+
+	// Empty values fall back to the defaults.
+	if value == "" {
+		return nil, nil
+	}
+
+	switch setting.Type {
+	case EnvSettingBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return nil, ErrInvalidEnvSettingValue(key, value, "true or false")
+		}
+	case EnvSettingInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return nil, ErrInvalidEnvSettingValue(key, value, "an integer")
+		}
+	case EnvSettingVersion:
+		if !envSettingVersionRegex.MatchString(value) {
+			return nil, ErrInvalidEnvSettingValue(key, value, "a version number")
+		}
+
+		supported := c.VersionMatrix().Services[key]
+		if len(supported) > 0 && !util.ContainsString(supported, value) {
+			return []string{fmt.Sprintf(
+				"%s=%s is not a known supported version (supported: %s)", key, value, strings.Join(supported, ", "),
+			)}, nil
+		}
+	case EnvSettingString:
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *ConfigTestSuite) TestValidateEnvSetting() {
+	tests := []struct {
+		name         string
+		key          string
+		value        string
+		wantWarnings int
+		wantErr      bool
+	}{
+		{name: "valid boolean", key: "REWARD_VARNISH", value: "false"},
+		{name: "invalid boolean", key: "REWARD_VARNISH", value: "maybe", wantErr: true},
+		{name: "invalid integer", key: "REWARD_PORT_OFFSET", value: "ten", wantErr: true},
+		{name: "empty value", key: "REWARD_PORT_OFFSET", value: ""},
+		{name: "supported version", key: "PHP_VERSION", value: "8.1"},
+		{name: "unsupported version", key: "PHP_VERSION", value: "5.4", wantWarnings: 1},
+		{name: "invalid version", key: "PHP_VERSION", value: "latest", wantErr: true},
+		{name: "read-only setting", key: "REWARD_ENV_NAME", value: "other", wantErr: true},
+		{name: "invalid key", key: "REWARD-DB", value: "true", wantErr: true},
+		{name: "unknown setting", key: "MY_SETTING", value: "anything", wantWarnings: 1},
+	}
+
+	c := newTestConfig(nil)
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			warnings, err := c.ValidateEnvSetting(tt.key, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, warnings, tt.wantWarnings)
+		})
+	}
+}
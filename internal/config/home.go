@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// LegacyAppHomeDir returns the home directory of the application used before the XDG base directories were
+// supported (~/.reward). It's still used if it exists.
+func LegacyAppHomeDir(appName string) string {
+	return filepath.Join(util.HomeDir(), fmt.Sprintf(".%s", appName))
+}
+
+// LegacyConfigFile returns the configuration file used before the XDG base directories were supported
+// (~/.reward.yml). It's still used if it exists.
+func LegacyConfigFile(appName string) string {
+	return filepath.Join(util.HomeDir(), fmt.Sprintf(".%s.yml", appName))
+}
+
+// DefaultAppHomeDir returns the default home directory of the application (certificates, service configurations,
+// plugins). It's the REWARD_HOME environment variable if it's set, ~/.reward if it exists or the OS is not linux, and
+// $XDG_DATA_HOME/reward (~/.local/share/reward) otherwise.
+func DefaultAppHomeDir(appName string) string {
+	if dir := os.Getenv(strings.ToUpper(appName) + "_HOME"); dir != "" {
+		return dir
+	}
+
+	legacy := LegacyAppHomeDir(appName)
+	if !useXDG() || dirExists(legacy) {
+		return legacy
+	}
+
+	return XDGAppHomeDir(appName)
+}
+
+// DefaultConfigFile returns the default configuration file of the application. It's ~/.reward.yml if it exists or
+// the OS is not linux, and $XDG_CONFIG_HOME/reward/reward.yml (~/.config/reward/reward.yml) otherwise.
+func DefaultConfigFile(appName string) string {
+	legacy := LegacyConfigFile(appName)
+	if !useXDG() || fileExists(legacy) {
+		return legacy
+	}
+
+	return XDGConfigFile(appName)
+}
+
+// XDGConfigFile returns the configuration file of the application in $XDG_CONFIG_HOME.
+func XDGConfigFile(appName string) string {
+	return filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), appName, fmt.Sprintf("%s.yml", appName))
+}
+
+// XDGAppHomeDir returns the home directory of the application in $XDG_DATA_HOME.
+func XDGAppHomeDir(appName string) string {
+	return filepath.Join(xdgDir("XDG_DATA_HOME", ".local", "share"), appName)
+}
+
+// XDGCacheDir returns the cache directory of the application in $XDG_CACHE_HOME.
+func XDGCacheDir(appName string) string {
+	return filepath.Join(xdgDir("XDG_CACHE_HOME", ".cache"), appName)
+}
+
+// CacheDir returns the directory of the files which can be downloaded or generated again (eg. the downloaded
+// binaries). It's the reward_cache_dir setting if it's set. Otherwise it's in $XDG_CACHE_HOME if the home directory
+// is in $XDG_DATA_HOME, or it's in the home directory, so an overridden home directory contains everything.
+func (c *Config) CacheDir() string {
+	if dir := c.GetString(fmt.Sprintf("%s_cache_dir", c.AppName())); dir != "" {
+		return dir
+	}
+
+	return c.defaultCacheDir()
+}
+
+// defaultCacheDir returns the default of CacheDir.
+func (c *Config) defaultCacheDir() string {
+	if useXDG() && filepath.Clean(c.AppHomeDir()) == filepath.Clean(XDGAppHomeDir(c.AppName())) {
+		return XDGCacheDir(c.AppName())
+	}
+
+	return filepath.Join(c.AppHomeDir(), "cache")
+}
+
+// useXDG returns true if the XDG base directories are used by default.
+func useXDG() bool {
+	return runtime.GOOS == "linux"
+}
+
+// xdgDir returns the value of the XDG environment variable, or the fallback relative to the user's home directory if
+// it's not set or it's not an absolute path (as the XDG Base Directory Specification requires).
+func xdgDir(env string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+
+	return filepath.Join(append([]string{util.HomeDir()}, fallback...)...)
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
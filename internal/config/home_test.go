@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *ConfigTestSuite) TestDefaultAppHomeDir() {
+	if runtime.GOOS != "linux" {
+		suite.T().Skip("the XDG base directories are used on linux")
+	}
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		legacy     bool
+		wantHome   string
+		wantConfig string
+		wantCache  string
+	}{
+		{
+			name:       "xdg defaults",
+			wantHome:   ".local/share/reward",
+			wantConfig: ".config/reward/reward.yml",
+			wantCache:  ".cache/reward",
+		},
+		{
+			name:       "xdg variables",
+			env:        map[string]string{"XDG_DATA_HOME": "/data", "XDG_CONFIG_HOME": "/config", "XDG_CACHE_HOME": "/cache"},
+			wantHome:   "/data/reward",
+			wantConfig: "/config/reward/reward.yml",
+			wantCache:  "/cache/reward",
+		},
+		{
+			name:       "relative xdg variables are ignored",
+			env:        map[string]string{"XDG_DATA_HOME": "data"},
+			wantHome:   ".local/share/reward",
+			wantConfig: ".config/reward/reward.yml",
+			wantCache:  ".cache/reward",
+		},
+		{
+			name:       "legacy home directory",
+			env:        map[string]string{"XDG_DATA_HOME": "/data"},
+			legacy:     true,
+			wantHome:   ".reward",
+			wantConfig: ".reward.yml",
+			wantCache:  ".reward/cache",
+		},
+		{
+			name:       "home override",
+			env:        map[string]string{"REWARD_HOME": "/srv/reward"},
+			wantHome:   "/srv/reward",
+			wantConfig: ".config/reward/reward.yml",
+			wantCache:  "/srv/reward/cache",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			for _, env := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "REWARD_HOME"} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			if tt.legacy {
+				assert.NoError(t, os.Mkdir(filepath.Join(home, ".reward"), 0o755))
+				assert.NoError(t, os.WriteFile(filepath.Join(home, ".reward.yml"), nil, 0o600))
+			}
+
+			abs := func(path string) string {
+				if filepath.IsAbs(path) {
+					return path
+				}
+
+				return filepath.Join(home, path)
+			}
+
+			c := newTestConfig(map[string]interface{}{"reward_home_dir": DefaultAppHomeDir("reward")})
+
+			assert.Equal(t, abs(tt.wantHome), c.AppHomeDir())
+			assert.Equal(t, abs(tt.wantConfig), DefaultConfigFile("reward"))
+			assert.Equal(t, abs(tt.wantCache), c.defaultCacheDir())
+		})
+	}
+}
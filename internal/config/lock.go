@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrLocked occurs when another command holds the lock of the project or the application home directory.
+var ErrLocked = func(appName, what string, pid int) error {
+	return fmt.Errorf("%s, wait for it to finish or run the command with --wait-lock", lockHolder(appName, what, pid))
+}
+
+// lockPollInterval is the interval of the attempts to acquire a lock with --wait-lock.
+const lockPollInterval = 500 * time.Millisecond
+
+// projectLockedCommands and homeLockedCommands are the commands which change the generated configurations of the
+// project or the files in the application home directory. The commands which pass their arguments to docker compose
+// are listed with their first argument.
+var (
+	projectLockedCommands = []string{
+		"bootstrap", "db import", "db upgrade", "db expose", "db unexpose",
+		"env build", "env create", "env down", "env kill", "env pull", "env restart", "env rm", "env start",
+		"env stop", "env up",
+		"env clone", "env promote", "env rotate-credentials", "env set",
+		"sync start", "sync stop", "sync flush", "sync pause", "sync resume", "sync reset", "sync terminate",
+	}
+	homeLockedCommands = []string{
+		"install", "home migrate", "sign-certificate",
+		"svc build", "svc create", "svc down", "svc kill", "svc pull", "svc restart", "svc rm", "svc start",
+		"svc stop", "svc up",
+		"daemon install", "daemon uninstall", "plugin install", "plugin remove", "prefetch",
+		"proxy add", "proxy rm", "tunnel rotate-keys", "undo last",
+	}
+)
+
+// CommandLocks returns whether the command (eg. "env up") has to hold the lock of the project and the lock of the
+// application home directory, so the commands changing the same files don't run at the same time.
+func CommandLocks(command string, args []string) (project, home bool) {
+	if command == "env" || command == "svc" {
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "-") {
+				command = command + " " + arg
+
+				break
+			}
+		}
+	}
+
+	return util.ContainsString(projectLockedCommands, command), util.ContainsString(homeLockedCommands, command)
+}
+
+// ProjectLockFile returns the path of the lock file of the project.
+func (c *Config) ProjectLockFile() string {
+	return filepath.Join(c.Cwd(), fmt.Sprintf(".%s", c.AppName()), fmt.Sprintf("%s.lock", c.AppName()))
+}
+
+// HomeLockFile returns the path of the lock file of the application home directory.
+func (c *Config) HomeLockFile() string {
+	return filepath.Join(c.AppHomeDir(), fmt.Sprintf("%s.lock", c.AppName()))
+}
+
+// WaitLock returns true if the commands should wait for the locks held by other commands instead of failing.
+func (c *Config) WaitLock() bool {
+	return c.GetBool("wait_lock")
+}
+
+// Lock acquires the locks the command has to hold (see CommandLocks). The lock of the application home directory is
+// acquired first, so two commands never wait for each other. The locks are released by Cleanup or when the process
+// exits.
+func (c *Config) Lock(cmd *cobra.Command, args []string) error {
+	// the flags of the commands which pass their arguments to docker compose are not parsed
+	if util.ContainsString(args, "--wait-lock") {
+		c.Set("wait_lock", true)
+	}
+
+	command := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	project, home := CommandLocks(command, args)
+
+	if home {
+		err := c.lock(c.HomeLockFile(), "the home directory")
+		if err != nil {
+			return err
+		}
+	}
+
+	if project && c.EnvInitialized() {
+		err := c.lock(c.ProjectLockFile(), fmt.Sprintf("the environment %s", c.EnvName()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lock acquires the lock of the file. If the file cannot be locked for another reason than another command holding
+// the lock (eg. the directory is not writable), the command runs without the lock.
+//
+// The locks held are passed to the application invoked by itself (eg. `db upgrade` runs `env stop`) in the
+// REWARD_HELD_LOCKS environment variable, so it doesn't wait for its parent.
+func (c *Config) lock(path, what string) error {
+	held := filepath.SplitList(os.Getenv(c.heldLocksEnv()))
+	if util.ContainsString(held, path) {
+		log.Debugf("The lock %s is held by the parent process.", path)
+
+		return nil
+	}
+
+	waiting := false
+
+	for {
+		lock, pid, err := util.TryLockFile(path)
+		if err == nil {
+			log.Debugf("Acquired the lock %s.", path)
+
+			c.locks = append(c.locks, lock)
+
+			return os.Setenv(c.heldLocksEnv(), strings.Join(append(held, path), string(filepath.ListSeparator)))
+		}
+
+		if !errors.Is(err, util.ErrFileLocked) {
+			log.Warnf("Cannot lock %s, running without the lock: %s", what, err)
+
+			return nil
+		}
+
+		if !c.WaitLock() {
+			return ErrLocked(c.AppName(), what, pid)
+		}
+
+		if !waiting {
+			log.Printf("Waiting for the lock, %s...", lockHolder(c.AppName(), what, pid))
+
+			waiting = true
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Unlock releases the locks acquired by Lock.
+func (c *Config) Unlock() error {
+	var errs []string
+
+	for _, lock := range c.locks {
+		err := lock.Unlock()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	c.locks = nil
+
+	if len(errs) > 0 {
+		return fmt.Errorf("cannot release locks: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// lockHolder describes the command holding the lock, eg. "another reward command is running (pid 42) on the
+// environment shop".
+func lockHolder(appName, what string, pid int) string {
+	if pid > 0 {
+		return fmt.Sprintf("another %s command is running (pid %d) on %s", appName, pid, what)
+	}
+
+	return fmt.Sprintf("another %s command is running on %s", appName, what)
+}
+
+// heldLocksEnv returns the name of the environment variable which contains the locks held by the parent process.
+func (c *Config) heldLocksEnv() string {
+	return fmt.Sprintf("%s_HELD_LOCKS", strings.ToUpper(c.AppName()))
+}
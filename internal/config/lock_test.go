@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+func (suite *ConfigTestSuite) TestCommandLocks() {
+	tests := []struct {
+		command     string
+		args        []string
+		wantProject bool
+		wantHome    bool
+	}{
+		{command: "env", args: []string{"up", "-d"}, wantProject: true},
+		{command: "env", args: []string{"--wait-lock", "up"}, wantProject: true},
+		{command: "env", args: []string{"logs", "-f"}},
+		{command: "env", args: []string{"exec", "php-fpm", "bash"}},
+		{command: "env"},
+		{command: "env restart", wantProject: true},
+		{command: "db import", wantProject: true},
+		{command: "db connect"},
+		{command: "sync start", wantProject: true},
+		{command: "sync monitor"},
+		{command: "svc", args: []string{"up"}, wantHome: true},
+		{command: "svc", args: []string{"ps"}},
+		{command: "install", wantHome: true},
+		{command: "home"},
+		{command: "status"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(strings.Join(append([]string{tt.command}, tt.args...), " "), func(t *testing.T) {
+			project, home := CommandLocks(tt.command, tt.args)
+
+			assert.Equal(t, tt.wantProject, project)
+			assert.Equal(t, tt.wantHome, home)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestLock() {
+	dir := suite.T().TempDir()
+	c := newTestConfig(map[string]interface{}{"reward_home_dir": dir})
+
+	suite.T().Setenv("REWARD_HELD_LOCKS", "")
+
+	held, _, err := util.TryLockFile(c.HomeLockFile())
+	assert.NoError(suite.T(), err)
+
+	err = c.lock(c.HomeLockFile(), "the home directory")
+	assert.EqualError(suite.T(), err, ErrLocked("reward", "the home directory", os.Getpid()).Error())
+	assert.Contains(suite.T(), err.Error(), "another reward command is running (pid ")
+
+	assert.NoError(suite.T(), held.Unlock())
+
+	// the lock is passed to the commands invoked by the application itself
+	assert.NoError(suite.T(), c.lock(c.HomeLockFile(), "the home directory"))
+	assert.Equal(suite.T(), filepath.Join(dir, "reward.lock"), os.Getenv("REWARD_HELD_LOCKS"))
+
+	child := newTestConfig(map[string]interface{}{"reward_home_dir": dir})
+	assert.NoError(suite.T(), child.lock(child.HomeLockFile(), "the home directory"))
+	assert.Empty(suite.T(), child.locks)
+
+	assert.NoError(suite.T(), c.Unlock())
+	assert.Empty(suite.T(), c.locks)
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrUnknownMageOSVersion occurs when the Magento version of a Mage-OS release is not in the version matrix.
+var ErrUnknownMageOSVersion = func(s string) error {
+	return fmt.Errorf("unknown Mage-OS version: %s, it's not in the version matrix", s)
+}
+
+// Magento version sources.
+const (
+	MagentoVersionSourceComposerLock = "composer.lock"
+	MagentoVersionSourceComposerJSON = "composer.json"
+	MagentoVersionSourceConfig       = "config"
+)
+
+// MagentoVersionInfo describes the Magento installation of the project.
+type MagentoVersionInfo struct {
+	// Edition is one of community, enterprise, cloud or mage-os.
+	Edition string
+	// Version is the exact version if it's read from composer.lock, otherwise the lowest version satisfying the
+	// composer.json constraint (or the configured version).
+	Version *version.Version
+	// Package is the composer package the version is read from.
+	Package string
+	// Source is where the version is read from: composer.lock, composer.json or config.
+	Source string
+	// B2B is true if the Adobe Commerce B2B extension is required.
+	B2B bool
+	// MagentoVersion is the Magento Open Source version a Mage-OS release is based on, otherwise it equals Version.
+	// Use it to look up the required services and the available features.
+	MagentoVersion *version.Version
+}
+
+// magentoPackages are the composer packages which determine the Magento version in order of precedence.
+// The product packages come first, because in composer.lock they contain the exact Magento version, while the
+// cloud metapackage has its own versioning.
+var magentoPackages = []struct {
+	name    string
+	edition string
+}{
+	{"magento/product-enterprise-edition", "enterprise"},
+	{"magento/product-community-edition", "community"},
+	{"mage-os/product-community-edition", "mage-os"},
+	{"magento/magento-cloud-metapackage", "cloud"},
+	{"magento/magento2-ee-base", "enterprise"},
+	{"magento/magento2-base", "community"},
+}
+
+// magentoB2BPackages are the composer packages of the Adobe Commerce B2B extension.
+var magentoB2BPackages = []string{"magento/extension-b2b", "magento/module-b2b"}
+
+// MagentoVersionInfo returns the Magento edition and version of the project. The exact installed version is read
+// from composer.lock, then the version constraints are resolved from composer.json, and finally the configured
+// version is used.
+func (c *Config) MagentoVersionInfo() (*MagentoVersionInfo, error) {
+	log.Debugln("Looking up Magento version...")
+
+	// An unreadable composer.json shouldn't break the commands which only use the version as a default.
+	pkgs, err := c.ComposerPackages()
+	if err != nil {
+		log.Debugf("...%s, using .env settings.", err)
+	}
+
+	info, err := magentoVersionFromComposer(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		info = &MagentoVersionInfo{
+			Edition: c.MagentoType(),
+			Version: c.MagentoVersionFromConfig(),
+			Source:  MagentoVersionSourceConfig,
+		}
+	}
+
+	info.MagentoVersion = info.Version
+
+	if info.Edition == "mage-os" {
+		info.MagentoVersion = c.VersionMatrix().MageOSMagentoVersion(info.Version)
+		if info.MagentoVersion == nil {
+			return nil, ErrUnknownMageOSVersion(info.Version.String())
+		}
+	}
+
+	log.Debugf(
+		"...found Magento %s version %s in %s (package: %s, b2b: %t, magento version: %s).",
+		info.Edition, info.Version, info.Source, info.Package, info.B2B, info.MagentoVersion,
+	)
+
+	return info, nil
+}
+
+// magentoVersionFromComposer returns the Magento version from the composer packages, or nil if the project
+// doesn't require Magento.
+func magentoVersionFromComposer(pkgs *ComposerPackages) (*MagentoVersionInfo, error) {
+	if pkgs == nil {
+		return nil, nil
+	}
+
+	var info *MagentoVersionInfo
+
+	for _, p := range magentoPackages {
+		if v, ok := pkgs.Locked[p.name]; ok {
+			ver, err := version.NewVersion(v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse %s version %s from composer.lock: %w", p.name, v, err)
+			}
+
+			info = &MagentoVersionInfo{
+				Edition: p.edition,
+				Version: ver,
+				Package: p.name,
+				Source:  MagentoVersionSourceComposerLock,
+			}
+
+			break
+		}
+	}
+
+	// Magento source repositories (eg. git clones) define the version in the root package.
+	if info == nil && util.CheckRegexInString(`^magento/magento2(ce|ee)$`, pkgs.Name) && pkgs.Version != "" {
+		ver, err := ResolveVersionConstraint(pkgs.Version)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse Magento version from composer.json: %w", err)
+		}
+
+		edition := "community"
+		if strings.HasSuffix(pkgs.Name, "ee") {
+			edition = "enterprise"
+		}
+
+		info = &MagentoVersionInfo{
+			Edition: edition,
+			Version: ver,
+			Package: pkgs.Name,
+			Source:  MagentoVersionSourceComposerJSON,
+		}
+	}
+
+	if info == nil {
+		for _, p := range magentoPackages {
+			constraint, ok := pkgs.Require[p.name]
+			if !ok {
+				continue
+			}
+
+			ver, err := ResolveVersionConstraint(constraint)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot resolve %s version constraint %s from composer.json: %w", p.name, constraint, err,
+				)
+			}
+
+			info = &MagentoVersionInfo{
+				Edition: p.edition,
+				Version: ver,
+				Package: p.name,
+				Source:  MagentoVersionSourceComposerJSON,
+			}
+
+			break
+		}
+	}
+
+	if info == nil {
+		return nil, nil
+	}
+
+	if _, _, _, ok := pkgs.Find(magentoB2BPackages...); ok {
+		info.B2B = true
+	}
+
+	return info, nil
+}
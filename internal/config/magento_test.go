@@ -0,0 +1,228 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type MagentoTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MagentoTestSuite) SetupTest() {
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = FS
+}
+
+func TestMagentoTestSuite(t *testing.T) {
+	suite.Run(t, new(MagentoTestSuite))
+}
+
+func (suite *MagentoTestSuite) TestResolveVersionConstraint() {
+	tests := []struct {
+		name       string
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{name: "exact version", constraint: "2.4.5", want: "2.4.5"},
+		{name: "patch release", constraint: "2.4.6-p3", want: "2.4.6-p3"},
+		{name: "tilde", constraint: "~2.4.5", want: "2.4.5"},
+		{name: "caret", constraint: "^2.4", want: "2.4.0"},
+		{name: "wildcard", constraint: "2.4.*", want: "2.4.0"},
+		{name: "space separated range", constraint: ">=2.4 <2.5", want: "2.4.0"},
+		{name: "comma separated range", constraint: ">=2.4.3, <2.4.7", want: "2.4.3"},
+		{name: "no mentioned version satisfies", constraint: ">2.4.3 <2.5", want: "2.4.3"},
+		{name: "or alternatives", constraint: "~2.4.6||~2.4.5", want: "2.4.5"},
+		{name: "single pipe alternatives", constraint: "2.4.6 | 2.4.4", want: "2.4.4"},
+		{name: "stability flag", constraint: "2.4.6@dev", want: "2.4.6"},
+		{name: "develop branch", constraint: "2.4-develop", want: "2.4.0"},
+		{name: "no version", constraint: "dev-main", wantErr: true},
+		{name: "empty", constraint: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := ResolveVersionConstraint(tt.constraint)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func (suite *MagentoTestSuite) TestMagentoVersionFromComposer() {
+	tests := []struct {
+		name         string
+		composerJSON string
+		composerLock string
+		want         *MagentoVersionInfo
+		wantVersion  string
+		wantErr      bool
+	}{
+		{
+			name:         "no composer.json",
+			composerJSON: "",
+			want:         nil,
+		},
+		{
+			name:         "not a magento project",
+			composerJSON: `{"require": {"laravel/framework": "^10.0"}}`,
+			want:         nil,
+		},
+		{
+			name:         "community edition constraint",
+			composerJSON: `{"require": {"magento/product-community-edition": "~2.4.5"}}`,
+			want: &MagentoVersionInfo{
+				Edition: "community",
+				Package: "magento/product-community-edition",
+				Source:  MagentoVersionSourceComposerJSON,
+			},
+			wantVersion: "2.4.5",
+		},
+		{
+			name:         "enterprise edition range",
+			composerJSON: `{"require": {"magento/product-enterprise-edition": ">=2.4 <2.5"}}`,
+			want: &MagentoVersionInfo{
+				Edition: "enterprise",
+				Package: "magento/product-enterprise-edition",
+				Source:  MagentoVersionSourceComposerJSON,
+			},
+			wantVersion: "2.4.0",
+		},
+		{
+			name:         "locked version wins over constraint",
+			composerJSON: `{"require": {"magento/product-community-edition": "~2.4.6"}}`,
+			composerLock: `{"packages": [{"name": "magento/product-community-edition", "version": "2.4.6-p3"}]}`,
+			want: &MagentoVersionInfo{
+				Edition: "community",
+				Package: "magento/product-community-edition",
+				Source:  MagentoVersionSourceComposerLock,
+			},
+			wantVersion: "2.4.6-p3",
+		},
+		{
+			name:         "cloud metapackage with locked product",
+			composerJSON: `{"require": {"magento/magento-cloud-metapackage": ">=2.4.6 <2.4.7"}}`,
+			composerLock: `{"packages": [
+				{"name": "magento/magento-cloud-metapackage", "version": "2.4.6"},
+				{"name": "magento/product-enterprise-edition", "version": "2.4.6-p2"}
+			]}`,
+			want: &MagentoVersionInfo{
+				Edition: "enterprise",
+				Package: "magento/product-enterprise-edition",
+				Source:  MagentoVersionSourceComposerLock,
+			},
+			wantVersion: "2.4.6-p2",
+		},
+		{
+			name:         "cloud metapackage constraint",
+			composerJSON: `{"require": {"magento/magento-cloud-metapackage": ">=2.4.6 <2.4.7"}}`,
+			want: &MagentoVersionInfo{
+				Edition: "cloud",
+				Package: "magento/magento-cloud-metapackage",
+				Source:  MagentoVersionSourceComposerJSON,
+			},
+			wantVersion: "2.4.6",
+		},
+		{
+			name:         "mage-os",
+			composerJSON: `{"require": {"mage-os/product-community-edition": "^1.0"}}`,
+			composerLock: `{"packages": [{"name": "mage-os/product-community-edition", "version": "1.0.1"}]}`,
+			want: &MagentoVersionInfo{
+				Edition: "mage-os",
+				Package: "mage-os/product-community-edition",
+				Source:  MagentoVersionSourceComposerLock,
+			},
+			wantVersion: "1.0.1",
+		},
+		{
+			name: "b2b",
+			composerJSON: `{"require": {
+				"magento/product-enterprise-edition": "2.4.5-p1",
+				"magento/extension-b2b": "^1.3"
+			}}`,
+			want: &MagentoVersionInfo{
+				Edition: "enterprise",
+				Package: "magento/product-enterprise-edition",
+				Source:  MagentoVersionSourceComposerJSON,
+				B2B:     true,
+			},
+			wantVersion: "2.4.5-p1",
+		},
+		{
+			name:         "magento source repository",
+			composerJSON: `{"name": "magento/magento2ce", "version": "2.4.7-beta1"}`,
+			want: &MagentoVersionInfo{
+				Edition: "community",
+				Package: "magento/magento2ce",
+				Source:  MagentoVersionSourceComposerJSON,
+			},
+			wantVersion: "2.4.7",
+		},
+		{
+			name:         "invalid lock file falls back to composer.json",
+			composerJSON: `{"require": {"magento/product-community-edition": "2.4.4"}}`,
+			composerLock: `<<<<<<< HEAD`,
+			want: &MagentoVersionInfo{
+				Edition: "community",
+				Package: "magento/product-community-edition",
+				Source:  MagentoVersionSourceComposerJSON,
+			},
+			wantVersion: "2.4.4",
+		},
+		{
+			name:         "invalid composer.json",
+			composerJSON: `{`,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			suite.SetupTest()
+
+			if tt.composerJSON != "" {
+				_ = FS.WriteFile("/project/composer.json", []byte(tt.composerJSON), os.FileMode(0o644))
+			}
+
+			if tt.composerLock != "" {
+				_ = FS.WriteFile("/project/composer.lock", []byte(tt.composerLock), os.FileMode(0o644))
+			}
+
+			pkgs, err := ReadComposerPackages("/project")
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			got, err := magentoVersionFromComposer(pkgs)
+			assert.NoError(t, err)
+
+			if tt.want == nil {
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.Equal(t, tt.wantVersion, got.Version.String())
+
+			got.Version = nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
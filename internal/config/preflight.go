@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrUnsupportedVersion occurs when the installed version of a component is not supported.
+var ErrUnsupportedVersion = func(component string, installed *version.Version, supported string) error {
+	return fmt.Errorf(
+		"%s version %s is not supported, supported versions: %s (use --skip-checks to skip the checks)",
+		component, installed, supported,
+	)
+}
+
+// compatibility describes the versions of a component which work with the application.
+type compatibility struct {
+	// Component is the name of the component.
+	Component string
+	// Supported is the constraint of the supported versions. Other versions fail the preflight checks.
+	Supported string
+	// Tested is the constraint of the tested versions. Supported but untested versions (eg. a new major release of
+	// docker) only log a warning.
+	Tested string
+	// Version returns the installed version of the component.
+	Version func(c *Config) (*version.Version, error)
+}
+
+// compatibilityMatrix contains the versions of docker and docker-compose which work with the application.
+var compatibilityMatrix = []compatibility{
+	{
+		Component: "docker",
+		Supported: ">= 20.4.0",
+		Tested:    "< 28.0.0",
+		Version: func(c *Config) (*version.Version, error) {
+			return c.Docker.Version()
+		},
+	},
+	{
+		Component: "docker-compose",
+		Supported: ">= 1.25.0",
+		Tested:    "< 3.0.0",
+		Version: func(c *Config) (*version.Version, error) {
+			return c.DockerCompose.Version()
+		},
+	},
+}
+
+// check returns an error if the installed version of the component is not supported, and whether it's tested.
+func (component compatibility) check(installed *version.Version) (bool, error) {
+	if !version.MustConstraints(version.NewConstraint(component.Supported)).Check(installed) {
+		return false, ErrUnsupportedVersion(component.Component, installed, component.Supported)
+	}
+
+	return version.MustConstraints(version.NewConstraint(component.Tested)).Check(installed), nil
+}
+
+// CheckComponentVersion fetches the installed version of the component (docker or docker-compose) and returns an
+// error if it's not supported. If the version is supported but not tested yet, the constraint of the tested versions
+// is returned.
+func (c *Config) CheckComponentVersion(name string) (*version.Version, string, error) {
+	for _, component := range compatibilityMatrix {
+		if component.Component != name {
+			continue
+		}
+
+		installed, err := component.Version(c)
+		if err != nil {
+			return nil, "", err
+		}
+
+		tested, err := component.check(installed)
+		if err != nil || tested {
+			return installed, "", err
+		}
+
+		return installed, component.Tested, nil
+	}
+
+	return nil, "", fmt.Errorf("unknown component: %s", name)
+}
+
+// SkipChecks returns true if the preflight checks of the docker and docker-compose versions are disabled by the
+// --skip-checks flag.
+func (c *Config) SkipChecks() bool {
+	return c.GetBool("skip_checks")
+}
+
+// Preflight checks if the installed versions of docker and docker-compose are supported.
+func (c *Config) Preflight() error {
+	if c.SkipChecks() {
+		log.Debugln("Skipping preflight checks...")
+
+		return nil
+	}
+
+	return c.preflight(compatibilityMatrix, c.daemonComponentVersions())
+}
+
+// preflight checks the versions of the components. The versions which are already detected (eg. by the daemon) are
+// not fetched again.
+func (c *Config) preflight(matrix []compatibility, detected map[string]*version.Version) error {
+	for _, component := range matrix {
+		log.Debugf("Checking %s version...", component.Component)
+
+		installed, ok := detected[component.Component]
+		if !ok {
+			var err error
+
+			installed, err = component.Version(c)
+			if err != nil {
+				return fmt.Errorf("cannot fetch %s version: %w", component.Component, err)
+			}
+		}
+
+		tested, err := component.check(installed)
+		if err != nil {
+			return err
+		}
+
+		if !tested {
+			log.Warnf(
+				"%s version %s has not been tested with %s yet (tested versions: %s). Please report any issues.",
+				component.Component, installed, c.AppName(), component.Tested,
+			)
+
+			continue
+		}
+
+		log.Debugf("...%s version %s is supported.", component.Component, installed)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PreflightTestSuite struct {
+	suite.Suite
+}
+
+func TestPreflightTestSuite(t *testing.T) {
+	suite.Run(t, new(PreflightTestSuite))
+}
+
+func (suite *PreflightTestSuite) TestPreflight() {
+	installed := func(v string, err error) func(*Config) (*version.Version, error) {
+		return func(*Config) (*version.Version, error) {
+			if err != nil {
+				return nil, err
+			}
+
+			return version.NewVersion(v)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		version func(*Config) (*version.Version, error)
+		wantErr bool
+	}{
+		{name: "supported", version: installed("24.0.7", nil)},
+		{name: "untested newer version", version: installed("28.1.0", nil)},
+		{name: "too old", version: installed("19.3.0", nil), wantErr: true},
+		{name: "not installed", version: installed("", fmt.Errorf("command not found")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			err := newTestConfig(nil).preflight([]compatibility{
+				{Component: "docker", Supported: ">= 20.4.0", Tested: "< 28.0.0", Version: tt.version},
+			}, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (suite *PreflightTestSuite) TestPreflightDetectedVersions() {
+	matrix := []compatibility{
+		{
+			Component: "docker",
+			Supported: ">= 20.4.0",
+			Tested:    "< 28.0.0",
+			Version: func(*Config) (*version.Version, error) {
+				return nil, fmt.Errorf("docker should not be run")
+			},
+		},
+	}
+
+	assert.NoError(suite.T(), newTestConfig(nil).preflight(matrix, map[string]*version.Version{
+		"docker": version.Must(version.NewVersion("24.0.7")),
+	}))
+	assert.Error(suite.T(), newTestConfig(nil).preflight(matrix, map[string]*version.Version{
+		"docker": version.Must(version.NewVersion("19.3.0")),
+	}))
+}
+
+func (suite *PreflightTestSuite) TestPreflightSkipChecks() {
+	c := newTestConfig(map[string]interface{}{"skip_checks": true})
+
+	// the docker client is not initialized, so the checks would panic if they ran
+	assert.NoError(suite.T(), c.Preflight())
+}
+
+func (suite *PreflightTestSuite) TestCompatibilityMatrix() {
+	for _, component := range compatibilityMatrix {
+		_, err := version.NewConstraint(component.Supported)
+		assert.NoError(suite.T(), err, component.Component)
+
+		_, err = version.NewConstraint(component.Tested)
+		assert.NoError(suite.T(), err, component.Component)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// settings is the typed copy of the settings which are read on the hot paths (eg. the service checks of the
+// templates and the commands). A viper lookup lowercases the key and searches all the layers (overrides, flags,
+// environment, config files, defaults), and the keys are concatenated with the app name on every call. The copy is
+// loaded on the first read after the settings are merged, and it's dropped whenever a setting is changed, so viper
+// remains the source of truth.
+type settings struct {
+	appName  string
+	envName  string
+	envType  string
+	services map[string]serviceSetting
+}
+
+// serviceSetting is the state of the <app>_<service> setting of a service.
+type serviceSetting struct {
+	set     bool
+	enabled bool
+}
+
+// loadedSettings returns the typed copy of the settings, it's loaded if it was dropped.
+func (c *Config) loadedSettings() *settings {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	if c.settings == nil {
+		appName := c.Viper.GetString("app_name")
+
+		c.settings = &settings{
+			appName:  appName,
+			envName:  strings.ToLower(c.Viper.GetString(fmt.Sprintf("%s_env_name", appName))),
+			envType:  strings.ToLower(c.Viper.GetString(fmt.Sprintf("%s_env_type", appName))),
+			services: make(map[string]serviceSetting),
+		}
+	}
+
+	return c.settings
+}
+
+// service returns the state of the setting of the service. The services are loaded when they're read first.
+func (c *Config) service(name string) serviceSetting {
+	s := c.loadedSettings()
+
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	svc, ok := s.services[name]
+	if !ok {
+		key := fmt.Sprintf("%s_%s", s.appName, name)
+		svc = serviceSetting{set: c.Viper.IsSet(key), enabled: c.Viper.GetBool(key)}
+		s.services[name] = svc
+	}
+
+	return svc
+}
+
+// dropSettings drops the typed copy of the settings, it's loaded again on the next read.
+func (c *Config) dropSettings() {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	c.settings = nil
+}
+
+// Set sets the value of the setting and drops the typed copy of the settings.
+func (c *Config) Set(key string, value interface{}) {
+	c.Viper.Set(key, value)
+	c.dropSettings()
+}
+
+// SetDefault sets the default value of the setting and drops the typed copy of the settings.
+func (c *Config) SetDefault(key string, value interface{}) {
+	c.Viper.SetDefault(key, value)
+	c.dropSettings()
+}
+
+// ReadInConfig reads the config file and drops the typed copy of the settings.
+func (c *Config) ReadInConfig() error {
+	defer c.dropSettings()
+
+	return c.Viper.ReadInConfig() //nolint:wrapcheck
+}
+
+// MergeInConfig merges the config file into the settings and drops the typed copy of the settings.
+func (c *Config) MergeInConfig() error {
+	defer c.dropSettings()
+
+	return c.Viper.MergeInConfig() //nolint:wrapcheck
+}
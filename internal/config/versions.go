@@ -0,0 +1,370 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/assets"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// defaultVersionMatrixPublicKey is the ed25519 public key used to verify the signature of the remote version matrix.
+const defaultVersionMatrixPublicKey = "y4VY/BKy7GIBNtrDA3F1J4QhaLJ+in6bj6eSZthSmv0="
+
+// ErrInvalidVersionMatrixSignature occurs when the signature of the version matrix cannot be verified.
+var ErrInvalidVersionMatrixSignature = fmt.Errorf("invalid version matrix signature")
+
+// ErrServiceVersionLifecycle occurs when service versions are past their end of life or not supported by the
+// Magento version of the project, and the check is strict.
+var ErrServiceVersionLifecycle = func(n int) error {
+	return fmt.Errorf("%d service version(s) are past their end of life or unsupported", n)
+}
+
+// serviceVersionSwitches are the settings which enable the services of the version settings. The services of the
+// other version settings (eg. PHP_VERSION) are always enabled.
+var serviceVersionSwitches = map[string]string{
+	"ELASTICSEARCH_VERSION": "elasticsearch",
+	"MARIADB_VERSION":       "db",
+	"OPENSEARCH_VERSION":    "opensearch",
+	"RABBITMQ_VERSION":      "rabbitmq",
+	"REDIS_VERSION":         "redis",
+	"VARNISH_VERSION":       "varnish",
+}
+
+// VersionMatrix contains the default versions of the supported platforms and services. A copy is embedded into the
+// binary, and it can be updated from a signed remote file, so new releases are supported without a new release of
+// the application.
+type VersionMatrix struct {
+	Magento1 struct {
+		Default string `json:"default"`
+	} `json:"magento1"`
+	Magento2 struct {
+		Default  string           `json:"default"`
+		Releases []MagentoRelease `json:"releases"`
+	} `json:"magento2"`
+	MageOS struct {
+		Default string `json:"default"`
+		// Releases map the Mage-OS releases to the Magento Open Source release they are based on.
+		Releases []struct {
+			From    string `json:"from"`
+			Magento string `json:"magento"`
+		} `json:"releases"`
+	} `json:"mage_os"`
+	// EnvDefaults are the service versions written to the .env file of new environments per environment type.
+	EnvDefaults map[string]map[string]string `json:"env_defaults"`
+	// Services are the supported versions of the services.
+	Services map[string][]string `json:"services"`
+	// EOL are the end of life dates (YYYY-MM-DD) of the service versions.
+	EOL map[string]map[string]string `json:"eol"`
+}
+
+// MagentoRelease contains the services of the Magento 2 releases starting from a version.
+type MagentoRelease struct {
+	From string `json:"from"`
+	// Services are the service versions required by the releases.
+	Services map[string]string `json:"services"`
+	// Supported are the service versions supported by the releases.
+	Supported map[string][]string `json:"supported"`
+}
+
+// VersionMatrixFile returns the path of the cached remote version matrix.
+func (c *Config) VersionMatrixFile() string {
+	return filepath.Join(c.AppHomeDir(), "versions.json")
+}
+
+// VersionMatrixSignatureFile returns the path of the signature of the cached remote version matrix.
+func (c *Config) VersionMatrixSignatureFile() string {
+	return c.VersionMatrixFile() + ".sig"
+}
+
+// VersionMatrixURL returns the URL of the remote version matrix. The signature is downloaded from the same URL
+// with a .sig suffix.
+func (c *Config) VersionMatrixURL() string {
+	return c.GetString(fmt.Sprintf("%s_version_matrix_url", c.AppName()))
+}
+
+// VersionMatrixRefreshInterval returns how often the remote version matrix is downloaded.
+func (c *Config) VersionMatrixRefreshInterval() time.Duration {
+	return c.GetDuration(fmt.Sprintf("%s_version_matrix_refresh_interval", c.AppName()))
+}
+
+// VerifyVersionMatrix verifies the base64 encoded ed25519 signature of the version matrix.
+func (c *Config) VerifyVersionMatrix(data, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(
+		c.GetString(fmt.Sprintf("%s_version_matrix_public_key", c.AppName())),
+	)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", ErrInvalidVersionMatrixSignature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidVersionMatrixSignature, err)
+	}
+
+	if !ed25519.Verify(key, data, sig) {
+		return ErrInvalidVersionMatrixSignature
+	}
+
+	return nil
+}
+
+// VersionMatrix returns the cached remote version matrix if it exists and its signature is valid, otherwise it
+// returns the version matrix embedded into the binary.
+func (c *Config) VersionMatrix() *VersionMatrix {
+	if matrix, err := c.cachedVersionMatrix(); err == nil {
+		return matrix
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Debugf("Cannot use cached version matrix, using the embedded one: %s", err)
+	}
+
+	data, err := assets.Assets.ReadFile("versions/versions.json")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	var matrix VersionMatrix
+
+	err = json.Unmarshal(data, &matrix)
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	return &matrix
+}
+
+func (c *Config) cachedVersionMatrix() (*VersionMatrix, error) {
+	data, err := FS.ReadFile(c.VersionMatrixFile())
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := FS.ReadFile(c.VersionMatrixSignatureFile())
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.VerifyVersionMatrix(data, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var matrix VersionMatrix
+
+	err = json.Unmarshal(data, &matrix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal version matrix: %w", err)
+	}
+
+	return &matrix, nil
+}
+
+// ApplyVersionMatrixDefaults sets the default platform versions from the version matrix.
+func (c *Config) ApplyVersionMatrixDefaults() {
+	matrix := c.VersionMatrix()
+
+	switch {
+	case c.EnvType() == "magento1":
+		c.SetDefault(fmt.Sprintf("%s_magento_version", c.AppName()), matrix.Magento1.Default)
+	case c.MagentoType() == "mage-os":
+		c.SetDefault(fmt.Sprintf("%s_magento_version", c.AppName()), matrix.MageOS.Default)
+	default:
+		c.SetDefault(fmt.Sprintf("%s_magento_version", c.AppName()), matrix.Magento2.Default)
+	}
+}
+
+// MagentoServices returns the service versions required by the given Magento 2 version.
+func (m *VersionMatrix) MagentoServices(v *version.Version) map[string]string {
+	if r := m.magentoRelease(v); r != nil {
+		return r.Services
+	}
+
+	return nil
+}
+
+// MagentoSupportedServices returns the service versions supported by the given Magento 2 version.
+func (m *VersionMatrix) MagentoSupportedServices(v *version.Version) map[string][]string {
+	if r := m.magentoRelease(v); r != nil {
+		return r.Supported
+	}
+
+	return nil
+}
+
+func (m *VersionMatrix) magentoRelease(v *version.Version) *MagentoRelease {
+	for i, r := range m.Magento2.Releases {
+		from, err := version.NewVersion(r.From)
+		if err != nil {
+			continue
+		}
+
+		// Patch releases (eg. 2.4.6-p3) are parsed as pre-releases, so only compare the major.minor.patch part.
+		if !v.Core().LessThan(from) {
+			return &m.Magento2.Releases[i]
+		}
+	}
+
+	return nil
+}
+
+// MageOSMagentoVersion returns the Magento Open Source version the given Mage-OS release is based on. Mage-OS
+// mirrored the Magento releases before 1.0, so these versions are returned as they are.
+func (m *VersionMatrix) MageOSMagentoVersion(v *version.Version) *version.Version {
+	if v.Segments()[0] >= 2 {
+		return v
+	}
+
+	for _, r := range m.MageOS.Releases {
+		from, err := version.NewVersion(r.From)
+		if err != nil || v.Core().LessThan(from) {
+			continue
+		}
+
+		magento, err := version.NewVersion(r.Magento)
+		if err != nil {
+			return nil
+		}
+
+		return magento
+	}
+
+	return nil
+}
+
+// ValidateServiceVersions returns a warning for every configured service version which is not in the version matrix.
+func (c *Config) ValidateServiceVersions() []string {
+	services := c.VersionMatrix().Services
+
+	keys := make([]string, 0, len(services))
+	for k := range services {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var warnings []string
+
+	for _, key := range keys {
+		configured := c.GetString(strings.ToLower(key))
+		if configured == "" || util.ContainsString(services[key], configured) {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"%s=%s is not a known supported version (supported: %s)",
+			key, configured, strings.Join(services[key], ", "),
+		))
+	}
+
+	return append(warnings, c.validateMagentoServiceVersions()...)
+}
+
+// validateMagentoServiceVersions returns a warning if the configured PHP version is newer than the one required by
+// the Magento version of the project, because composer's platform check fails in that case.
+func (c *Config) validateMagentoServiceVersions() []string {
+	if c.EnvType() != "magento2" {
+		return nil
+	}
+
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		return nil
+	}
+
+	required := c.VersionMatrix().MagentoServices(info.MagentoVersion)
+	if required["PHP_VERSION"] == "" {
+		return nil
+	}
+
+	configured, err := version.NewVersion(c.GetString("php_version"))
+	if err != nil {
+		return nil
+	}
+
+	if configured.Core().GreaterThan(version.Must(version.NewVersion(required["PHP_VERSION"]))) {
+		return []string{fmt.Sprintf(
+			"PHP_VERSION=%s is newer than the version required by Magento %s (%s, found in %s)",
+			c.GetString("php_version"), info.Version, required["PHP_VERSION"], info.Source,
+		)}
+	}
+
+	return nil
+}
+
+// ServiceVersionLifecycleWarnings returns a warning for every enabled service whose configured version is past its
+// end of life at the given time, or is not supported by the Magento version of the project.
+func (c *Config) ServiceVersionLifecycleWarnings(now time.Time) []string {
+	matrix := c.VersionMatrix()
+
+	var warnings []string
+
+	for _, key := range sortedKeys(matrix.EOL) {
+		configured := c.GetString(strings.ToLower(key))
+		if configured == "" || !c.serviceVersionEnabled(key) {
+			continue
+		}
+
+		eol, err := time.Parse("2006-01-02", matrix.EOL[key][configured])
+		if err != nil || now.Before(eol) {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"%s=%s reached its end of life on %s", key, configured, eol.Format("2006-01-02"),
+		))
+	}
+
+	if c.EnvType() != "magento2" {
+		return warnings
+	}
+
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		return warnings
+	}
+
+	supported := matrix.MagentoSupportedServices(info.MagentoVersion)
+
+	for _, key := range sortedKeys(supported) {
+		configured := c.GetString(strings.ToLower(key))
+		if configured == "" || !c.serviceVersionEnabled(key) || util.ContainsString(supported[key], configured) {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"%s=%s is not supported by Magento %s (supported: %s, found in %s)",
+			key, configured, info.Version, strings.Join(supported[key], ", "), info.Source,
+		))
+	}
+
+	return warnings
+}
+
+// serviceVersionEnabled returns true if the service of the version setting (eg. MARIADB_VERSION) is enabled.
+func (c *Config) serviceVersionEnabled(key string) bool {
+	service, ok := serviceVersionSwitches[key]
+
+	return !ok || c.SvcEnabledStrict(service)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/assets"
+)
+
+type VersionsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *VersionsTestSuite) SetupTest() {
+	// Don't use the cached version matrix of the user.
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+}
+
+func TestVersionsTestSuite(t *testing.T) {
+	suite.Run(t, new(VersionsTestSuite))
+}
+
+func (suite *VersionsTestSuite) TestVerifyVersionMatrix() {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(suite.T(), err)
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(suite.T(), err)
+
+	data := []byte(`{"magento2": {"default": "2.4.6"}}`)
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)) + "\n")
+
+	tests := []struct {
+		name      string
+		key       string
+		data      []byte
+		signature []byte
+		wantErr   bool
+	}{
+		{
+			name:      "good signature",
+			key:       base64.StdEncoding.EncodeToString(pub),
+			data:      data,
+			signature: signature,
+		},
+		{
+			name:      "modified data",
+			key:       base64.StdEncoding.EncodeToString(pub),
+			data:      []byte(`{"magento2": {"default": "2.4.7"}}`),
+			signature: signature,
+			wantErr:   true,
+		},
+		{
+			name:      "bad signature",
+			key:       base64.StdEncoding.EncodeToString(pub),
+			data:      data,
+			signature: []byte("not a signature"),
+			wantErr:   true,
+		},
+		{
+			name:      "other key",
+			key:       base64.StdEncoding.EncodeToString(otherPub),
+			data:      data,
+			signature: signature,
+			wantErr:   true,
+		},
+		{
+			name:      "bad key",
+			key:       base64.StdEncoding.EncodeToString([]byte("short")),
+			data:      data,
+			signature: signature,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestConfig(map[string]interface{}{"reward_version_matrix_public_key": tt.key})
+
+			err := c.VerifyVersionMatrix(tt.data, tt.signature)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidVersionMatrixSignature)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (suite *VersionsTestSuite) TestEmbeddedVersionMatrixSignature() {
+	data, err := assets.Assets.ReadFile("versions/versions.json")
+	assert.NoError(suite.T(), err)
+
+	signature, err := assets.Assets.ReadFile("versions/versions.json.sig")
+	assert.NoError(suite.T(), err)
+
+	c := newTestConfig(map[string]interface{}{"reward_version_matrix_public_key": defaultVersionMatrixPublicKey})
+
+	assert.NoError(
+		suite.T(),
+		c.VerifyVersionMatrix(data, signature),
+		"the version matrix signature is outdated, run `make sign-versions`",
+	)
+}
+
+func (suite *VersionsTestSuite) TestMagentoServices() {
+	matrix := newTestConfig(nil).VersionMatrix()
+
+	tests := []struct {
+		name    string
+		version string
+		wantPHP string
+	}{
+		{name: "latest", version: "2.4.7", wantPHP: "8.2"},
+		{name: "first release of a range", version: "2.4.6", wantPHP: "8.2"},
+		{name: "patch release", version: "2.4.6-p3", wantPHP: "8.2"},
+		{name: "patch release of older range", version: "2.4.5-p1", wantPHP: "8.1"},
+		{name: "2.4.0", version: "2.4.0", wantPHP: "7.4"},
+		{name: "2.3", version: "2.3.7-p4", wantPHP: "7.3"},
+		{name: "unsupported", version: "2.2.11"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			services := matrix.MagentoServices(version.Must(version.NewVersion(tt.version)))
+			if tt.wantPHP == "" {
+				assert.Nil(t, services)
+
+				return
+			}
+
+			assert.Equal(t, tt.wantPHP, services["PHP_VERSION"])
+		})
+	}
+}
+
+func (suite *VersionsTestSuite) TestMageOSMagentoVersion() {
+	matrix := newTestConfig(nil).VersionMatrix()
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{name: "first release", version: "1.0.0", want: "2.4.7"},
+		{name: "patch release", version: "1.0.4", want: "2.4.7"},
+		{name: "mirrored magento release", version: "2.4.6-p3", want: "2.4.6-p3"},
+		{name: "unknown", version: "0.9.0"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got := matrix.MageOSMagentoVersion(version.Must(version.NewVersion(tt.version)))
+			if tt.want == "" {
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func (suite *VersionsTestSuite) TestServiceVersionLifecycleWarnings() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     []string
+	}{
+		{
+			name: "supported versions",
+			settings: map[string]interface{}{
+				"reward_env_type":        "magento2",
+				"reward_magento_version": "2.4.7",
+				"reward_db":              true,
+				"php_version":            "8.2",
+				"mariadb_version":        "10.6",
+			},
+		},
+		{
+			name: "end of life and unsupported",
+			settings: map[string]interface{}{
+				"reward_env_type":        "magento2",
+				"reward_magento_version": "2.4.7",
+				"reward_db":              true,
+				"reward_elasticsearch":   true,
+				"php_version":            "7.4",
+				"mariadb_version":        "10.6",
+				"elasticsearch_version":  "7.16",
+			},
+			want: []string{
+				"ELASTICSEARCH_VERSION=7.16 reached its end of life on 2023-06-07",
+				"PHP_VERSION=7.4 reached its end of life on 2022-11-28",
+				"ELASTICSEARCH_VERSION=7.16 is not supported by Magento 2.4.7 (supported: 7.17, found in config)",
+				"PHP_VERSION=7.4 is not supported by Magento 2.4.7 (supported: 8.1, 8.2, found in config)",
+			},
+		},
+		{
+			name: "disabled service",
+			settings: map[string]interface{}{
+				"reward_env_type":        "magento2",
+				"reward_magento_version": "2.4.7",
+				"reward_elasticsearch":   false,
+				"php_version":            "8.2",
+				"elasticsearch_version":  "7.16",
+			},
+		},
+		{
+			name: "not magento",
+			settings: map[string]interface{}{
+				"reward_env_type": "laravel",
+				"reward_db":       true,
+				"mariadb_version": "10.3",
+			},
+			want: []string{"MARIADB_VERSION=10.3 reached its end of life on 2023-05-25"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestConfig(tt.settings).ServiceVersionLifecycleWarnings(now))
+		})
+	}
+}
@@ -152,30 +152,43 @@ func (c *Client) InstallCACertificate(caDir string) error {
 
 	case "darwin":
 		return c.darwinInstallCACertificate(caCertificatePEMFilePath)
+	}
+
+	var err error
 
+	switch osDistro {
 	case "ubuntu", "debian", "pop", "elementary", "linuxmint":
-		return c.debianInstallCACertificate(caCertificatePEMFilePath)
+		err = c.debianInstallCACertificate(caCertificatePEMFilePath)
 
 	case "fedora", "centos":
-		return c.rhelInstallCACertificate(caCertificatePEMFilePath)
+		err = c.rhelInstallCACertificate(caCertificatePEMFilePath)
 
 	case "arch", "manjaro":
-		return c.archInstallCACertificate(caCertificatePEMFilePath)
+		err = c.archInstallCACertificate(caCertificatePEMFilePath)
 
 	default:
 		return fmt.Errorf("your operating system is not supported. yet. :(")
 	}
+
+	if err != nil {
+		return err
+	}
+
+	if util.IsWSL() {
+		if err := c.wslInstallCACertificate(caCertificatePEMFilePath); err != nil {
+			log.Warnf("Cannot install CA Certificate for Windows: %s", err)
+		}
+	}
+
+	return nil
 }
 
 func (c *Client) archInstallCACertificate(caCertificatePEMFilePath string) error {
 	log.Println("Installing CA certificate for Arch based Linux distribution (requires sudo privileges)...")
 
 	//nolint:gosec
-	cmd := exec.Command("/bin/sh",
-		"-c",
-		fmt.Sprintf("sudo cp -va %s /etc/ca-certificates/trust-source/anchors/%s-local-ca.cert.pem",
-			caCertificatePEMFilePath,
-			c.config.AppName()))
+	cmd := exec.Command("sudo", "cp", "-va", caCertificatePEMFilePath,
+		fmt.Sprintf("/etc/ca-certificates/trust-source/anchors/%s-local-ca.cert.pem", c.config.AppName()))
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -187,7 +200,7 @@ func (c *Client) archInstallCACertificate(caCertificatePEMFilePath string) error
 		return fmt.Errorf("error running command: %w", err)
 	}
 
-	cmd = exec.Command("/bin/sh", "-c", "sudo update-ca-trust")
+	cmd = exec.Command("sudo", "update-ca-trust")
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -208,12 +221,8 @@ func (c *Client) rhelInstallCACertificate(caCertificatePEMFilePath string) error
 	log.Println("Installing CA certificate for RHEL based Linux distribution (requires sudo privileges)...")
 
 	//nolint:gosec
-	cmd := exec.Command("/bin/sh",
-		"-c",
-		fmt.Sprintf("sudo cp -va %s /etc/pki/ca-trust/source/anchors/%s-local-ca.cert.pem",
-			caCertificatePEMFilePath,
-			c.config.AppName()),
-	)
+	cmd := exec.Command("sudo", "cp", "-va", caCertificatePEMFilePath,
+		fmt.Sprintf("/etc/pki/ca-trust/source/anchors/%s-local-ca.cert.pem", c.config.AppName()))
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -225,7 +234,7 @@ func (c *Client) rhelInstallCACertificate(caCertificatePEMFilePath string) error
 		return fmt.Errorf("error copying ca certificate: %w", err)
 	}
 
-	cmd = exec.Command("/bin/sh", "-c", "sudo update-ca-trust")
+	cmd = exec.Command("sudo", "update-ca-trust")
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -246,12 +255,8 @@ func (c *Client) debianInstallCACertificate(caCertificatePEMFilePath string) err
 	log.Println("Installing CA Certificate for Debian based Linux distribution (requires sudo privileges)...")
 
 	//nolint:gosec
-	cmd := exec.Command("/bin/sh",
-		"-c",
-		fmt.Sprintf("sudo cp -va %s /usr/local/share/ca-certificates/%s-local-ca.cert.pem",
-			caCertificatePEMFilePath,
-			c.config.AppName()),
-	)
+	cmd := exec.Command("sudo", "cp", "-va", caCertificatePEMFilePath,
+		fmt.Sprintf("/usr/local/share/ca-certificates/%s-local-ca.cert.pem", c.config.AppName()))
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -263,7 +268,7 @@ func (c *Client) debianInstallCACertificate(caCertificatePEMFilePath string) err
 		return fmt.Errorf("error copying ca certificate: %w", err)
 	}
 
-	cmd = exec.Command("/bin/sh", "-c", "sudo update-ca-certificates")
+	cmd = exec.Command("sudo", "update-ca-certificates")
 
 	log.Debugf("Running command: %s", cmd)
 
@@ -304,25 +309,56 @@ func (c *Client) darwinInstallCACertificate(caCertificatePEMFilePath string) err
 }
 
 func (c *Client) windowsInstallCACertificate(caCertificatePEMFilePath string) error {
-	log.Println("Installing CA Certificate for Windows (requires admin privileges)...")
+	content, err := os.ReadFile(caCertificatePEMFilePath)
+	if err != nil {
+		return fmt.Errorf("cannot read ca certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return fmt.Errorf("cannot decode ca certificate: %s", caCertificatePEMFilePath)
+	}
 
-	if !util.IsAdmin() {
-		return fmt.Errorf("not enough privileges to install CA Certificate")
+	// Without admin privileges the certificate is trusted only for the current user, Windows asks for confirmation.
+	if util.IsAdmin() {
+		log.Println("Installing CA Certificate to the Local Computer store...")
+	} else {
+		log.Println("Installing CA Certificate to the Current User store (confirm the security warning)...")
 	}
 
-	cmd := exec.Command("certutil", "-addstore", "-f", "Root", caCertificatePEMFilePath)
+	err = util.AddCertificateToRootStore(block.Bytes, util.IsAdmin())
+	if err != nil {
+		return fmt.Errorf("error installing ca certificate: %w", err)
+	}
+
+	log.Println("...CA Certificate installed.")
+
+	return nil
+}
+
+// wslInstallCACertificate installs the CA certificate to the Current User store of Windows when the command runs
+// inside WSL, so the browsers running on Windows trust the certificates too.
+func (c *Client) wslInstallCACertificate(caCertificatePEMFilePath string) error {
+	log.Println("Installing CA Certificate for Windows (confirm the security warning)...")
+
+	out, err := exec.Command("wslpath", "-w", caCertificatePEMFilePath).Output()
+	if err != nil {
+		return fmt.Errorf("cannot convert ca certificate path to windows path: %w", err)
+	}
+
+	cmd := exec.Command("certutil.exe", "-user", "-addstore", "-f", "Root", string(bytes.TrimSpace(out)))
 
 	log.Debugf("Running command: %s", cmd)
 
-	out, err := cmd.CombinedOutput()
+	out, err = cmd.CombinedOutput()
 
 	log.Tracef("Command output: %s", out)
 
 	if err != nil {
-		return fmt.Errorf("error installing ca certificate: %w", err)
+		return fmt.Errorf("error installing ca certificate to windows: %w", err)
 	}
 
-	log.Println("...CA Certificate installed.")
+	log.Println("...CA Certificate installed for Windows.")
 
 	return nil
 }
@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+)
+
+// DockerAPI is the subset of the Docker Engine API used by the core operations of the application (eg. looking up
+// the containers and connecting the common services to the environment networks). It's implemented by the docker
+// client, and by Fake for the tests and the packages which drive these operations without a docker engine.
+type DockerAPI interface { //nolint:revive
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error)
+	NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+	NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error
+	VolumeInspect(ctx context.Context, volumeID string) (types.Volume, error)
+}
+
+// NewClientWithAPI returns a client whose core operations use the api. The operations which need the full docker
+// client (eg. Version, CloneContainer) are not available.
+func NewClientWithAPI(api DockerAPI) *Client {
+	return &Client{API: api}
+}
@@ -0,0 +1,125 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	log "github.com/sirupsen/logrus"
+)
+
+// ContainerDetails returns the details of the container by its ID or name.
+func (c *Client) ContainerDetails(id string) (*Container, error) {
+	inspect, err := c.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect container: %w", err)
+	}
+
+	return newContainer(inspect), nil
+}
+
+// CloneContainer creates and starts a copy of the container with the name. The copy is connected to the networks of
+// the container with the same aliases, but it publishes no ports and it has no docker-compose labels, so
+// docker-compose doesn't manage it. It returns the ID of the copy.
+func (c *Client) CloneContainer(id, name string) (string, error) {
+	log.Debugf("Cloning container %s as %s...", id, name)
+
+	ctx := context.Background()
+
+	inspect, err := c.ContainerInspect(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("cannot inspect container: %w", err)
+	}
+
+	config := *inspect.Config
+	config.Hostname = ""
+	config.Labels = cloneLabels(inspect.Config.Labels)
+
+	hostConfig := *inspect.HostConfig
+	hostConfig.PortBindings = nil
+
+	// The container is connected to its primary network (the network mode) when it's created, and to the other
+	// networks afterwards.
+	primary := string(hostConfig.NetworkMode)
+	endpoints := make(map[string]*network.EndpointSettings)
+
+	if settings, ok := inspect.NetworkSettings.Networks[primary]; ok {
+		endpoints[primary] = &network.EndpointSettings{Aliases: cloneAliases(settings.Aliases, inspect.ID)}
+	}
+
+	created, err := c.ContainerCreate(
+		ctx, &config, &hostConfig, &network.NetworkingConfig{EndpointsConfig: endpoints}, nil, name,
+	)
+	if err != nil {
+		return "", fmt.Errorf("cannot create container %s: %w", name, err)
+	}
+
+	for networkName, settings := range inspect.NetworkSettings.Networks {
+		if networkName == primary {
+			continue
+		}
+
+		err = c.NetworkConnect(ctx, networkName, created.ID, &network.EndpointSettings{
+			Aliases: cloneAliases(settings.Aliases, inspect.ID),
+		})
+		if err != nil {
+			return "", fmt.Errorf("cannot connect container %s to network %s: %w", name, networkName, err)
+		}
+	}
+
+	err = c.ContainerStart(ctx, created.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return "", fmt.Errorf("cannot start container %s: %w", name, err)
+	}
+
+	log.Debugln("...container cloned.")
+
+	return created.ID, nil
+}
+
+// RemoveContainer stops the container gracefully (it's killed after the timeout) and removes it.
+func (c *Client) RemoveContainer(id string, timeout time.Duration) error {
+	ctx := context.Background()
+
+	err := c.ContainerStop(ctx, id, &timeout)
+	if err != nil {
+		return fmt.Errorf("cannot stop container %s: %w", id, err)
+	}
+
+	err = c.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		return fmt.Errorf("cannot remove container %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// cloneLabels returns the labels of the container without the labels of docker-compose.
+func cloneLabels(labels map[string]string) map[string]string {
+	cloned := make(map[string]string, len(labels))
+
+	for key, value := range labels {
+		if !strings.HasPrefix(key, "com.docker.compose.") {
+			cloned[key] = value
+		}
+	}
+
+	return cloned
+}
+
+// cloneAliases returns the network aliases of the container without the alias of its short ID, which is added by
+// Docker to every container.
+func cloneAliases(aliases []string, id string) []string {
+	cloned := make([]string, 0, len(aliases))
+
+	for _, alias := range aliases {
+		if !strings.HasPrefix(id, alias) {
+			cloned = append(cloned, alias)
+		}
+	}
+
+	return cloned
+}
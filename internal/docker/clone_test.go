@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *DockerTestSuite) TestCloneLabels() {
+	got := cloneLabels(map[string]string{
+		"com.docker.compose.project": "reward",
+		"com.docker.compose.service": "traefik",
+		"dev.reward.container.name":  "traefik",
+		"traefik.enable":             "true",
+	})
+
+	assert.Equal(suite.T(), map[string]string{
+		"dev.reward.container.name": "traefik",
+		"traefik.enable":            "true",
+	}, got)
+}
+
+func (suite *DockerTestSuite) TestCloneAliases() {
+	tests := []struct {
+		name    string
+		aliases []string
+		want    []string
+	}{
+		{name: "empty", aliases: nil, want: []string{}},
+		{
+			name:    "without the short id",
+			aliases: []string{"traefik", "traefik-upstream", "0123456789ab"},
+			want:    []string{"traefik", "traefik-upstream"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cloneAliases(tt.aliases, "0123456789abcdef0123456789abcdef"))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -13,8 +14,6 @@ import (
 	"github.com/spf13/viper"
 )
 
-var requiredVersion = "20.4.0"
-
 var (
 	// ErrDockerAPIIsUnreachable occurs when Docker is not running
 	// or the user who runs the application cannot call Docker API.
@@ -22,11 +21,6 @@ var (
 		return fmt.Errorf("docker api is unreachable: %w", err)
 	}
 
-	// ErrDockerVersionMismatch occurs when the Docker version is too old.
-	ErrDockerVersionMismatch = func(s string) error {
-		return fmt.Errorf("docker version is too old: %s", s)
-	}
-
 	// ErrCannotFindContainer occurs when the application cannot find the requested container.
 	ErrCannotFindContainer = func(s string, err error) error {
 		return fmt.Errorf("cannot find container: %s, error: %w", s, err)
@@ -42,6 +36,16 @@ var (
 		return fmt.Errorf("too many containers found: %s", s)
 	}
 
+	// ErrServiceContainerNotFound occurs when the service of the docker-compose project has no container.
+	ErrServiceContainerNotFound = func(project, service string) error {
+		return fmt.Errorf("cannot find container of service %s in project %s", service, project)
+	}
+
+	// ErrServiceContainerNotRunning occurs when the container of the service is not running.
+	ErrServiceContainerNotRunning = func(service, state string) error {
+		return fmt.Errorf("container of service %s is not running (state: %s)", service, state)
+	}
+
 	// ErrCannotFindNetwork occurs when the application cannot find the requested network during container inspection.
 	ErrCannotFindNetwork = func(s string) error {
 		return fmt.Errorf("cannot find network: %s", s)
@@ -50,6 +54,55 @@ var (
 
 type Client struct {
 	*dockerpkg.Client
+	// API is used by the core operations, it's the docker client itself, or a Fake in the tests.
+	API DockerAPI
+
+	version   *version.Version
+	versionMu sync.Mutex
+}
+
+// Container contains the details of the container of a docker-compose service.
+type Container struct {
+	ID      string
+	Name    string
+	Project string
+	Service string
+	// WorkingDir is the directory of the docker-compose project, it's the root directory of the environment.
+	WorkingDir string
+	// State is the state of the container (eg. running, exited).
+	State string
+	// Health is the health status of the container (eg. healthy, starting), it's empty if it has no healthcheck.
+	Health string
+	// ExitCode is the exit code of the last run of the container.
+	ExitCode int
+	// OOMKilled is true if the last run of the container was killed by the OOM killer.
+	OOMKilled bool
+	// MemoryLimit is the memory limit of the container in bytes, it's zero if the container is not limited.
+	MemoryLimit int64
+	// ExtraHosts are the additional host name mappings of the container (eg. host.docker.internal:host-gateway).
+	ExtraHosts []string
+	// IPs are the IP addresses of the container by network names.
+	IPs    map[string]string
+	Mounts []Mount
+}
+
+// Mount is a volume or a bind mount of a container.
+type Mount struct {
+	Type        string
+	Name        string
+	Source      string
+	Destination string
+	RW          bool
+}
+
+// Running returns true if the container is running.
+func (c *Container) Running() bool {
+	return c.State == "running"
+}
+
+// Healthy returns true if the container is running and it's healthy or it has no healthcheck.
+func (c *Container) Healthy() bool {
+	return c.Running() && (c.Health == "" || c.Health == "healthy")
 }
 
 func (c *Client) AppName() string {
@@ -87,18 +140,20 @@ func NewClient(dockerHost string) (*Client, error) {
 
 	return &Client{
 		Client: docker,
+		API:    docker,
 	}, nil
 }
 
-func Must(c *Client, err error) *Client {
-	if err != nil {
-		log.Fatalln(err)
-	}
+// Version returns the version of the docker engine. The version is memoized, call InvalidateVersion if the docker
+// engine is changed.
+func (c *Client) Version() (*version.Version, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
 
-	return c
-}
+	if c.version != nil {
+		return c.version, nil
+	}
 
-func (c *Client) dockerVersion() (*version.Version, error) {
 	log.Debugln("Fetching docker version...")
 
 	data, err := c.ServerVersion(context.Background())
@@ -113,57 +168,17 @@ func (c *Client) dockerVersion() (*version.Version, error) {
 
 	log.Debugf("...docker version is: %s.", v.String())
 
-	return v, nil
-}
-
-func (c *Client) isMinimumVersionInstalled() bool {
-	log.Debugln("Checking if minimum docker version is installed...")
+	c.version = v
 
-	v, err := c.dockerVersion()
-	if err != nil {
-		log.Traceln("...cannot fetch docker version.")
-
-		return false
-	}
-
-	if v.LessThan(version.Must(version.NewVersion(requiredVersion))) {
-		log.Tracef("...docker version is too old. Your version is: %s, required version: %s.",
-			v.String(),
-			requiredVersion,
-		)
-
-		return false
-	}
-
-	log.Debugln("...minimum docker version is installed.")
-
-	return true
+	return v, nil
 }
 
-// Check checks if docker-engine is running or not.
-func (c *Client) Check() error {
-	log.Debugln("Checking Docker...")
-
-	if !c.isMinimumVersionInstalled() {
-		ver, err := c.dockerVersion()
-		if err != nil {
-			log.Traceln("...cannot fetch docker version.")
-
-			return fmt.Errorf("cannot fetch docker version: %w", err)
-		}
-
-		return ErrDockerVersionMismatch(
-			fmt.Sprintf(
-				"your docker version is %s, required version: %s",
-				ver.String(),
-				requiredVersion,
-			),
-		)
-	}
-
-	log.Debugln("...docker version is appropriate.")
+// InvalidateVersion removes the memoized version of the docker engine.
+func (c *Client) InvalidateVersion() {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
 
-	return nil
+	c.version = nil
 }
 
 func (c *Client) verifyContainerResults(containers []types.Container) error {
@@ -195,20 +210,12 @@ func (c *Client) ContainerAddressInNetwork(containerName, environmentName, netwo
 
 	ctx := context.Background()
 
-	containers, err := c.ContainerList(ctx, types.ContainerListOptions{
-		Filters: filters.NewArgs(
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
-			},
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), environmentName),
-			},
-		),
-	})
+	containers, err := c.RunningContainersByLabels(
+		fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
+		fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), environmentName),
+	)
 	if err != nil {
-		return "", fmt.Errorf("cannot list containers: %w", err)
+		return "", err
 	}
 
 	err = c.verifyContainerResults(containers)
@@ -237,20 +244,12 @@ func (c *Client) ContainerGatewayInNetwork(containerName, networkName string) (s
 
 	ctx := context.Background()
 
-	containers, err := c.ContainerList(ctx, types.ContainerListOptions{
-		Filters: filters.NewArgs(
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
-			},
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.EnvName()),
-			},
-		),
-	})
+	containers, err := c.RunningContainersByLabels(
+		fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
+		fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.EnvName()),
+	)
 	if err != nil {
-		return "", fmt.Errorf("cannot list containers: %w", err)
+		return "", err
 	}
 
 	err = c.verifyContainerResults(containers)
@@ -273,94 +272,176 @@ func (c *Client) ContainerGatewayInNetwork(containerName, networkName string) (s
 	return val.Gateway, nil
 }
 
-// ContainerIDByName returns a container ID of the containerName running in the current environment.
-func (c *Client) ContainerIDByName(containerName string) (string, error) {
-	log.Debugln("Looking up container ID by name...")
+// RunningContainersByLabels returns the running containers which have all the specified labels (key=value or key).
+func (c *Client) RunningContainersByLabels(labels ...string) ([]types.Container, error) {
+	args := filters.NewArgs()
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	containers, err := c.API.ContainerList(context.Background(), types.ContainerListOptions{Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list containers: %w", err)
+	}
+
+	return containers, nil
+}
+
+// ServiceContainer returns the container of the service in the docker-compose project (including the stopped one).
+// The container is resolved by the project and service labels set by docker-compose, the one-off containers (eg.
+// docker-compose run) are ignored. It returns an error if the service has no containers or more than one.
+func (c *Client) ServiceContainer(project, service string) (*Container, error) {
+	log.Debugf("Looking up container of service %s in project %s...", service, project)
 
-	containers, err := c.ContainerList(context.Background(), types.ContainerListOptions{
+	ctx := context.Background()
+
+	containers, err := c.API.ContainerList(ctx, types.ContainerListOptions{
+		All: true,
 		Filters: filters.NewArgs(
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
-			},
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.EnvName()),
-			},
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", project)),
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", service)),
+			filters.Arg("label", "com.docker.compose.oneoff=False"),
 		),
 	})
 	if err != nil {
-		return "", fmt.Errorf("cannot list containers: %w", err)
+		return nil, fmt.Errorf("cannot list containers: %w", err)
 	}
 
-	err = c.verifyContainerResults(containers)
+	switch {
+	case len(containers) == 0:
+		return nil, ErrServiceContainerNotFound(project, service)
+	case len(containers) > 1:
+		names := make([]string, 0, len(containers))
+		for _, container := range containers {
+			names = append(names, container.Names...)
+		}
+
+		return nil, ErrTooManyContainersFound("containers: " + strings.Join(names, " "))
+	}
+
+	inspect, err := c.API.ContainerInspect(ctx, containers[0].ID)
 	if err != nil {
-		return "", ErrCannotFindContainer(containerName, err)
+		return nil, fmt.Errorf("cannot inspect container: %w", err)
 	}
 
-	log.Debugln("...container ID by name found.")
+	log.Debugln("...container of service found.")
 
-	return containers[0].ID, nil
+	return newContainer(inspect), nil
 }
 
-// ContainerNamesByName returns a container names of the containerName running in the current environment.
-func (c *Client) ContainerNamesByName(containerName string) ([]string, error) {
-	log.Debugln("Looking up container Names by name...")
+// EnvServiceContainer returns the container of the service in the current environment.
+func (c *Client) EnvServiceContainer(service string) (*Container, error) {
+	return c.ServiceContainer(c.EnvName(), service)
+}
 
-	containers, err := c.ContainerList(context.Background(), types.ContainerListOptions{
-		Filters: filters.NewArgs(
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
-			},
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.EnvName()),
-			},
-		),
-	})
+// RunningEnvServiceContainer returns the container of the service in the current environment. It returns an error if
+// the container is not running.
+func (c *Client) RunningEnvServiceContainer(service string) (*Container, error) {
+	container, err := c.EnvServiceContainer(service)
 	if err != nil {
-		return nil, fmt.Errorf("cannot list containers: %w", err)
+		return nil, err
 	}
 
-	err = c.verifyContainerResults(containers)
-	if err != nil {
-		return nil, ErrCannotFindContainer(containerName, err)
+	if !container.Running() {
+		return nil, ErrServiceContainerNotRunning(service, container.State)
+	}
+
+	return container, nil
+}
+
+func newContainer(inspect types.ContainerJSON) *Container {
+	container := &Container{
+		ID:   inspect.ID,
+		Name: strings.TrimPrefix(inspect.Name, "/"),
+		IPs:  make(map[string]string),
+	}
+
+	if inspect.Config != nil {
+		container.Project = inspect.Config.Labels["com.docker.compose.project"]
+		container.Service = inspect.Config.Labels["com.docker.compose.service"]
+		container.WorkingDir = inspect.Config.Labels["com.docker.compose.project.working_dir"]
 	}
 
-	log.Debugln("...container ID by name found.")
+	if inspect.ContainerJSONBase != nil && inspect.State != nil {
+		container.State = inspect.State.Status
+		container.ExitCode = inspect.State.ExitCode
+		container.OOMKilled = inspect.State.OOMKilled
 
-	return containers[0].Names, nil
+		if inspect.State.Health != nil {
+			container.Health = inspect.State.Health.Status
+		}
+	}
+
+	if inspect.ContainerJSONBase != nil && inspect.HostConfig != nil {
+		container.MemoryLimit = inspect.HostConfig.Memory
+		container.ExtraHosts = inspect.HostConfig.ExtraHosts
+	}
+
+	if inspect.NetworkSettings != nil {
+		for name, network := range inspect.NetworkSettings.Networks {
+			if network != nil && network.IPAddress != "" {
+				container.IPs[name] = network.IPAddress
+			}
+		}
+	}
+
+	for _, m := range inspect.Mounts {
+		container.Mounts = append(container.Mounts, Mount{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			RW:          m.RW,
+		})
+	}
+
+	return container
 }
 
-// ContainerStateByName returns the container state of the containerName running in the current environment.
-func (c *Client) ContainerStateByName(containerName string) (string, error) {
-	log.Debugln("Looking up container state by name...")
+// ContainersByLabel returns the details of the containers (including the stopped ones) that have the specified
+// label.
+func (c *Client) ContainersByLabel(label string) ([]types.ContainerJSON, error) {
+	log.Debugln("Looking up containers by label...")
 
-	containers, err := c.ContainerList(context.Background(), types.ContainerListOptions{
+	containers, err := c.API.ContainerList(context.Background(), types.ContainerListOptions{
+		All: true,
 		Filters: filters.NewArgs(
 			filters.KeyValuePair{
 				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.container.name=%s", c.AppName(), containerName),
-			},
-			filters.KeyValuePair{
-				Key:   "label",
-				Value: fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.EnvName()),
+				Value: label,
 			},
 		),
 	})
 	if err != nil {
-		return "", fmt.Errorf("cannot list containers: %w", err)
+		return nil, fmt.Errorf("cannot list containers: %w", err)
 	}
 
-	err = c.verifyContainerResults(containers)
+	results := make([]types.ContainerJSON, 0, len(containers))
+	for _, container := range containers {
+		details, err := c.API.ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot inspect container %s: %w", container.ID, err)
+		}
+
+		results = append(results, details)
+	}
+
+	return results, nil
+}
+
+// ContainerDetailsByLabel returns the containers (including the stopped ones) that have the specified label.
+func (c *Client) ContainerDetailsByLabel(label string) ([]*Container, error) {
+	details, err := c.ContainersByLabel(label)
 	if err != nil {
-		return "", ErrCannotFindContainer(containerName, err)
+		return nil, err
 	}
 
-	log.Debugln("...container state by name found.")
+	containers := make([]*Container, 0, len(details))
+	for _, d := range details {
+		containers = append(containers, newContainer(d))
+	}
 
-	return containers[0].State, nil
+	return containers, nil
 }
 
 // NetworkNamesByLabel returns a list of network names that have the specified label.
@@ -384,23 +465,153 @@ func (c *Client) NetworkNamesByLabel(label string) ([]string, error) {
 	}
 
 	results := make([]string, 0, len(networks))
-	for i, network := range networks {
-		results[i] = network.Name
+	for _, network := range networks {
+		results = append(results, network.Name)
 	}
 
 	return results, nil
 }
 
-// ContainerRunning returns true if container is running.
+// NetworkLabelsByLabel returns the labels of the networks that have the specified label.
+func (c *Client) NetworkLabelsByLabel(label string) ([]map[string]string, error) {
+	log.Debugln("Looking up network labels by label...")
+
+	networks, err := c.NetworkList(context.Background(), types.NetworkListOptions{
+		Filters: filters.NewArgs(
+			filters.KeyValuePair{
+				Key:   "label",
+				Value: label,
+			},
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list networks: %w", err)
+	}
+
+	results := make([]map[string]string, 0, len(networks))
+	for _, network := range networks {
+		log.Tracef("Found network: %s.", network.Name)
+
+		results = append(results, network.Labels)
+	}
+
+	return results, nil
+}
+
+// VolumeNamesByLabel returns a list of volume names that have the specified label.
+func (c *Client) VolumeNamesByLabel(label string) ([]string, error) {
+	log.Debugln("Looking up volume names by label...")
+
+	volumes, err := c.VolumeList(context.Background(), filters.NewArgs(
+		filters.KeyValuePair{
+			Key:   "label",
+			Value: label,
+		},
+	))
+	if err != nil {
+		return []string{}, fmt.Errorf("cannot list volumes: %w", err)
+	}
+
+	results := make([]string, 0, len(volumes.Volumes))
+	for _, v := range volumes.Volumes {
+		log.Tracef("Found volume: %s.", v.Name)
+
+		results = append(results, v.Name)
+	}
+
+	return results, nil
+}
+
+// VolumeLabels returns the labels of the volume.
+func (c *Client) VolumeLabels(name string) (map[string]string, error) {
+	volume, err := c.VolumeInspect(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect volume %s: %w", name, err)
+	}
+
+	return volume.Labels, nil
+}
+
+// ExistingVolumeLabels returns the labels of the volume, and false if the volume doesn't exist.
+func (c *Client) ExistingVolumeLabels(name string) (map[string]string, bool, error) {
+	volume, err := c.API.VolumeInspect(context.Background(), name)
+	if dockerpkg.IsErrNotFound(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot inspect volume %s: %w", name, err)
+	}
+
+	return volume.Labels, true, nil
+}
+
+// ContainerRunning returns true if the container of the service is running in the current environment.
 func (c *Client) ContainerRunning(container string) bool {
-	_, err := c.ContainerIDByName(container)
+	_, err := c.RunningEnvServiceContainer(container)
 
 	return err == nil
 }
 
+// NetworkSubnets returns the subnets of the docker network.
+func (c *Client) NetworkSubnets(networkName string) ([]string, error) {
+	networks, err := c.API.NetworkList(context.Background(), types.NetworkListOptions{
+		Filters: filters.NewArgs(
+			filters.KeyValuePair{
+				Key:   "name",
+				Value: networkName,
+			},
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list networks: %w", err)
+	}
+
+	var subnets []string
+
+	// The name filter matches substrings of the network names as well.
+	for _, network := range networks {
+		if network.Name != networkName {
+			continue
+		}
+
+		for _, config := range network.IPAM.Config {
+			if config.Subnet != "" {
+				subnets = append(subnets, config.Subnet)
+			}
+		}
+	}
+
+	return subnets, nil
+}
+
+// NetworkLabels returns the labels of the docker network, and false if the network doesn't exist.
+func (c *Client) NetworkLabels(networkName string) (map[string]string, bool, error) {
+	networks, err := c.API.NetworkList(context.Background(), types.NetworkListOptions{
+		Filters: filters.NewArgs(
+			filters.KeyValuePair{
+				Key:   "name",
+				Value: networkName,
+			},
+		),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot list networks: %w", err)
+	}
+
+	// The name filter matches substrings of the network names as well.
+	for _, network := range networks {
+		if network.Name == networkName {
+			return network.Labels, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
 // NetworkExist returns true if the docker network exists.
 func (c *Client) NetworkExist(networkName string) (bool, error) {
-	networks, err := c.NetworkList(context.Background(), types.NetworkListOptions{
+	networks, err := c.API.NetworkList(context.Background(), types.NetworkListOptions{
 		Filters: filters.NewArgs(
 			filters.KeyValuePair{
 				Key:   "name",
@@ -412,9 +623,12 @@ func (c *Client) NetworkExist(networkName string) (bool, error) {
 		return false, fmt.Errorf("cannot list networks: %w", err)
 	}
 
-	if len(networks) == 0 {
-		return false, nil
+	// The name filter matches substrings of the network names as well.
+	for _, network := range networks {
+		if network.Name == networkName {
+			return true, nil
+		}
 	}
 
-	return true, nil
+	return false, nil
 }
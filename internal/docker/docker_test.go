@@ -3,6 +3,10 @@ package docker
 import (
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -19,7 +23,7 @@ func TestDockerTestSuite(t *testing.T) {
 	suite.Run(t, new(DockerTestSuite))
 }
 
-func (suite *DockerTestSuite) TestClient_dockerVersion() {
+func (suite *DockerTestSuite) TestClient_Version() {
 	tests := []struct {
 		name    string
 		want    *version.Version
@@ -33,10 +37,12 @@ func (suite *DockerTestSuite) TestClient_dockerVersion() {
 	}
 	for _, tt := range tests {
 		suite.T().Run(tt.name, func(t *testing.T) {
-			c := Must(NewClient(""))
-			got, err := c.dockerVersion()
+			c, err := NewClient("")
+			assert.NoError(t, err)
+
+			got, err := c.Version()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("dockerVersion() error = %s, wantErr %t", err, tt.wantErr)
+				t.Errorf("Version() error = %s, wantErr %t", err, tt.wantErr)
 
 				return
 			}
@@ -46,60 +52,67 @@ func (suite *DockerTestSuite) TestClient_dockerVersion() {
 	}
 }
 
-func (suite *DockerTestSuite) TestClient_isMinimumVersionInstalled() {
-	// Change the requiredVersion of Docker to a fake the behaviour of the test.
-	tests := []struct {
-		name            string
-		requiredVersion string
-		want            bool
-	}{
-		{
-			name:            "should be valid (using the real required docker version)",
-			requiredVersion: "20.4.0",
-			want:            true,
+func (suite *DockerTestSuite) TestNewContainer() {
+	inspect := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   "abc123",
+			Name: "/shop-php-fpm-1",
+			State: &types.ContainerState{
+				Status:    "running",
+				ExitCode:  137,
+				OOMKilled: true,
+				Health:    &types.Health{Status: "starting"},
+			},
+			HostConfig: &container.HostConfig{Resources: container.Resources{Memory: 1024}},
 		},
-		{
-			name:            "should fail (using a fake required docker version)",
-			requiredVersion: "30.1.1",
-			want:            false,
+		Config: &container.Config{
+			Labels: map[string]string{
+				"com.docker.compose.project": "shop",
+				"com.docker.compose.service": "php-fpm",
+			},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"shop_default": {IPAddress: "172.18.0.3"},
+				"detached":     {},
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "shop_appdata", Destination: "/var/www/html", RW: true},
 		},
 	}
-	for _, tt := range tests {
-		suite.T().Run(tt.name, func(t *testing.T) {
-			requiredVersion = tt.requiredVersion
-			c := Must(NewClient(""))
 
-			assert.Equal(t, c.isMinimumVersionInstalled(), tt.want)
-		})
-	}
-}
+	got := newContainer(inspect)
 
-func (suite *DockerTestSuite) TestClient_Check() {
-	// Change the requiredVersion of Docker to a fake the behaviour of the test.
-	tests := []struct {
-		name            string
-		requiredVersion string
-		wantErr         bool
-	}{
-		{
-			name:            "should be valid (using the real required docker version)",
-			requiredVersion: "20.4.0",
-			wantErr:         false,
-		},
-		{
-			name:            "should fail (using a fake required docker version)",
-			requiredVersion: "30.1.1",
-			wantErr:         true,
+	assert.Equal(suite.T(), &Container{
+		ID:          "abc123",
+		Name:        "shop-php-fpm-1",
+		Project:     "shop",
+		Service:     "php-fpm",
+		State:       "running",
+		Health:      "starting",
+		ExitCode:    137,
+		OOMKilled:   true,
+		MemoryLimit: 1024,
+		IPs:         map[string]string{"shop_default": "172.18.0.3"},
+		Mounts: []Mount{
+			{Type: "volume", Name: "shop_appdata", Destination: "/var/www/html", RW: true},
 		},
-	}
-	for _, tt := range tests {
-		suite.T().Run(tt.name, func(t *testing.T) {
-			requiredVersion = tt.requiredVersion
-			c := Must(NewClient(""))
+	}, got)
+	assert.True(suite.T(), got.Running())
+	assert.False(suite.T(), got.Healthy(), "starting container should not be healthy")
 
-			if err := c.Check(); (err != nil) != tt.wantErr {
-				assert.Failf(t, "Check() error = %s, wantErr %s", err.Error(), tt.wantErr)
-			}
-		})
-	}
+	got.Health = ""
+	assert.True(suite.T(), got.Healthy(), "running container without healthcheck should be healthy")
+}
+
+func (suite *DockerTestSuite) TestParseDiskUsage() {
+	got, err := parseDiskUsage(`Filesystem     1024-blocks     Used Available Capacity Mounted on
+overlay           61255492 52307840   5806328      91% /
+`)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &DiskUsage{Total: 61255492 * 1024, Available: 5806328 * 1024}, got)
+
+	_, err = parseDiskUsage("df: /: No such file or directory")
+	assert.Error(suite.T(), err)
 }
@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	log "github.com/sirupsen/logrus"
+)
+
+// The actions of the container events which are watched.
+const (
+	EventStart  = "start"
+	EventDie    = "die"
+	EventOOM    = "oom"
+	EventHealth = "health_status"
+)
+
+// Event is a lifecycle event of a container.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	Container string    `json:"container"`
+	Project   string    `json:"project"`
+	Service   string    `json:"service"`
+	// ExitCode is set for die events.
+	ExitCode string `json:"exit_code,omitempty"`
+	// Health is set for health_status events (eg. healthy, unhealthy).
+	Health string `json:"health,omitempty"`
+}
+
+// String returns the event in a human readable format.
+func (e Event) String() string {
+	service := e.Service
+	if service == "" {
+		service = e.Container
+	}
+
+	s := fmt.Sprintf("%s %s/%s %s", e.Time.Format("15:04:05"), e.Project, service, e.Action)
+
+	switch {
+	case e.ExitCode != "":
+		s += fmt.Sprintf(" (exit code: %s)", e.ExitCode)
+	case e.Health != "":
+		s += fmt.Sprintf(" (%s)", e.Health)
+	}
+
+	return s
+}
+
+// WatchContainerEvents subscribes to the Docker events API and calls the handler with the start, die, oom and health
+// status events of the containers which have the label (key=value or key). It blocks until the context is cancelled
+// or the connection to the Docker API is lost.
+func (c *Client) WatchContainerEvents(ctx context.Context, label string, handler func(Event)) error {
+	log.Debugln("Subscribing to container events...")
+
+	messages, errs := c.Events(ctx, types.EventsOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", "container"),
+			filters.Arg("label", label),
+			filters.Arg("event", EventStart),
+			filters.Arg("event", EventDie),
+			filters.Arg("event", EventOOM),
+			filters.Arg("event", EventHealth),
+		),
+	})
+
+	for {
+		select {
+		case message := <-messages:
+			handler(newEvent(message))
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			return fmt.Errorf("cannot receive container events: %w", err)
+		}
+	}
+}
+
+func newEvent(message events.Message) Event {
+	event := Event{
+		Time:      time.Unix(0, message.TimeNano),
+		Action:    message.Action,
+		Container: message.Actor.Attributes["name"],
+		Project:   message.Actor.Attributes["com.docker.compose.project"],
+		Service:   message.Actor.Attributes["com.docker.compose.service"],
+	}
+
+	switch {
+	case message.Action == EventDie:
+		event.ExitCode = message.Actor.Attributes["exitCode"]
+	case strings.HasPrefix(message.Action, EventHealth+":"):
+		event.Action = EventHealth
+		event.Health = strings.TrimSpace(strings.TrimPrefix(message.Action, EventHealth+":"))
+	}
+
+	return event
+}
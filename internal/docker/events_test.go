@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *DockerTestSuite) TestNewEvent() {
+	attributes := func(extra map[string]string) map[string]string {
+		a := map[string]string{
+			"name":                       "shop-php-fpm-1",
+			"com.docker.compose.project": "shop",
+			"com.docker.compose.service": "php-fpm",
+		}
+		for k, v := range extra {
+			a[k] = v
+		}
+
+		return a
+	}
+
+	tests := []struct {
+		name    string
+		message events.Message
+		want    Event
+		str     string
+	}{
+		{
+			name: "die event with exit code",
+			message: events.Message{
+				Action:   "die",
+				TimeNano: time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local).UnixNano(),
+				Actor:    events.Actor{Attributes: attributes(map[string]string{"exitCode": "137"})},
+			},
+			want: Event{
+				Time:      time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local),
+				Action:    EventDie,
+				Container: "shop-php-fpm-1",
+				Project:   "shop",
+				Service:   "php-fpm",
+				ExitCode:  "137",
+			},
+			str: "10:00:00 shop/php-fpm die (exit code: 137)",
+		},
+		{
+			name: "health status event",
+			message: events.Message{
+				Action:   "health_status: unhealthy",
+				TimeNano: time.Date(2023, 1, 1, 10, 0, 1, 0, time.Local).UnixNano(),
+				Actor:    events.Actor{Attributes: attributes(nil)},
+			},
+			want: Event{
+				Time:      time.Date(2023, 1, 1, 10, 0, 1, 0, time.Local),
+				Action:    EventHealth,
+				Container: "shop-php-fpm-1",
+				Project:   "shop",
+				Service:   "php-fpm",
+				Health:    "unhealthy",
+			},
+			str: "10:00:01 shop/php-fpm health_status (unhealthy)",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got := newEvent(tt.message)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.str, got.String())
+		})
+	}
+}
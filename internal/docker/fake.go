@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+)
+
+// Fake is an in-memory implementation of DockerAPI. The containers are filtered by their labels and the networks by
+// their names like by the docker engine, and the network connections are recorded in the Connections map (network
+// name to container IDs).
+type Fake struct {
+	Containers  []types.Container
+	Networks    []types.NetworkResource
+	Volumes     []types.Volume
+	Connections map[string][]string
+
+	mu sync.Mutex
+}
+
+// ContainerList returns the containers matching the label filters. The stopped containers are returned only if
+// options.All is set.
+func (f *Fake) ContainerList(_ context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var containers []types.Container
+
+	for _, container := range f.Containers {
+		if !options.All && container.State != "running" {
+			continue
+		}
+
+		if !fakeLabelsMatch(container.Labels, options.Filters.Get("label")) {
+			continue
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// ContainerInspect returns the details of the container with the ID.
+func (f *Fake) ContainerInspect(_ context.Context, containerID string) (types.ContainerJSON, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, c := range f.Containers {
+		if c.ID != containerID {
+			continue
+		}
+
+		name := containerID
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    c.ID,
+				Name:  name,
+				State: &types.ContainerState{Status: c.State, Running: c.State == "running"},
+			},
+			Config: &container.Config{Labels: c.Labels},
+		}, nil
+	}
+
+	return types.ContainerJSON{}, ErrCannotFindContainer(containerID, fmt.Errorf("no such container"))
+}
+
+// NetworkList returns the networks whose names contain the name filter, like the docker engine.
+func (f *Fake) NetworkList(_ context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var (
+		names    = options.Filters.Get("name")
+		networks []types.NetworkResource
+	)
+
+	for _, n := range f.Networks {
+		matched := len(names) == 0
+
+		for _, name := range names {
+			if strings.Contains(n.Name, name) {
+				matched = true
+			}
+		}
+
+		if matched {
+			networks = append(networks, n)
+		}
+	}
+
+	return networks, nil
+}
+
+// NetworkConnect records the connection of the container to the network.
+func (f *Fake) NetworkConnect(_ context.Context, networkID, containerID string, _ *network.EndpointSettings) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Connections == nil {
+		f.Connections = make(map[string][]string)
+	}
+
+	for _, id := range f.Connections[networkID] {
+		if id == containerID {
+			return fmt.Errorf("endpoint with name %s already exists in network %s", containerID, networkID)
+		}
+	}
+
+	f.Connections[networkID] = append(f.Connections[networkID], containerID)
+
+	return nil
+}
+
+// NetworkDisconnect removes the connection of the container from the network.
+func (f *Fake) NetworkDisconnect(_ context.Context, networkID, containerID string, _ bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, id := range f.Connections[networkID] {
+		if id == containerID {
+			f.Connections[networkID] = append(f.Connections[networkID][:i], f.Connections[networkID][i+1:]...)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("container %s is not connected to network %s", containerID, networkID)
+}
+
+// VolumeInspect returns the volume with the name, or a not found error like the docker engine.
+func (f *Fake) VolumeInspect(_ context.Context, volumeID string) (types.Volume, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, v := range f.Volumes {
+		if v.Name == volumeID {
+			return v, nil
+		}
+	}
+
+	return types.Volume{}, errdefs.NotFound(fmt.Errorf("no such volume: %s", volumeID))
+}
+
+// fakeLabelsMatch returns true if the labels match all the label filters (key or key=value).
+func fakeLabelsMatch(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		key, value, hasValue := strings.Cut(filter, "=")
+
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+
+	return true
+}
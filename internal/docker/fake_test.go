@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FakeTestSuite struct {
+	suite.Suite
+}
+
+func TestFakeTestSuite(t *testing.T) {
+	suite.Run(t, new(FakeTestSuite))
+}
+
+func (suite *FakeTestSuite) TestNetworkExist() {
+	c := NewClientWithAPI(&Fake{
+		Networks: []types.NetworkResource{{Name: "myproject_default"}, {Name: "reward"}},
+	})
+
+	tests := []struct {
+		network string
+		want    bool
+	}{
+		{network: "myproject_default", want: true},
+		// the name filter of the engine matches substrings, they're not reported as existing networks
+		{network: "myproject", want: false},
+		{network: "other_default", want: false},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.network, func(t *testing.T) {
+			got, err := c.NetworkExist(tt.network)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *FakeTestSuite) TestRunningContainersByLabels() {
+	c := NewClientWithAPI(&Fake{
+		Containers: []types.Container{
+			{ID: "1", State: "running", Labels: map[string]string{"dev.reward.container.name": "traefik"}},
+			{ID: "2", State: "exited", Labels: map[string]string{"dev.reward.container.name": "traefik"}},
+			{ID: "3", State: "running", Labels: map[string]string{"dev.reward.container.name": "my-traefik-test"}},
+		},
+	})
+
+	containers, err := c.RunningContainersByLabels("dev.reward.container.name=traefik")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), containers, 1)
+	assert.Equal(suite.T(), "1", containers[0].ID)
+}
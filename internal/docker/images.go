@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	dockerpkg "github.com/docker/docker/client"
+)
+
+// ErrImageNotFound occurs when the image doesn't exist on the docker host.
+var ErrImageNotFound = fmt.Errorf("image not found")
+
+// Image contains the runtime configuration of an image.
+type Image struct {
+	Ref string
+	// ExposedPorts are the ports exposed by the image in port/protocol format (eg. 9000/tcp), sorted.
+	ExposedPorts []string
+	Entrypoint   []string
+	Cmd          []string
+}
+
+// Image returns the runtime configuration of the image on the docker host. It returns ErrImageNotFound if the image
+// is not pulled yet.
+func (c *Client) Image(ref string) (*Image, error) {
+	inspect, _, err := c.ImageInspectWithRaw(context.Background(), ref)
+	if err != nil {
+		if dockerpkg.IsErrNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, ref)
+		}
+
+		return nil, fmt.Errorf("cannot inspect image %s: %w", ref, err)
+	}
+
+	image := &Image{Ref: ref}
+
+	if inspect.Config != nil {
+		for port := range inspect.Config.ExposedPorts {
+			image.ExposedPorts = append(image.ExposedPorts, string(port))
+		}
+
+		sort.Strings(image.ExposedPorts)
+
+		image.Entrypoint = inspect.Config.Entrypoint
+		image.Cmd = inspect.Config.Cmd
+	}
+
+	return image, nil
+}
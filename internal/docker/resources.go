@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrCannotParseDiskUsage occurs when the output of df cannot be parsed.
+var ErrCannotParseDiskUsage = func(s string) error {
+	return fmt.Errorf("cannot parse disk usage: %s", s)
+}
+
+// DiskUsage is the usage of a filesystem in bytes.
+type DiskUsage struct {
+	Total     uint64
+	Available uint64
+}
+
+// MemoryTotal returns the memory available for Docker (the memory of the Docker Desktop VM on macOS and Windows).
+func (c *Client) MemoryTotal() (int64, error) {
+	info, err := c.Info(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("cannot get docker info: %w", err)
+	}
+
+	return info.MemTotal, nil
+}
+
+// MemoryUsage returns the memory used by the container in bytes (without the inactive page cache, the same way as
+// docker stats calculates it).
+func (c *Client) MemoryUsage(containerID string) (uint64, error) {
+	log.Debugf("Getting memory usage of container %s...", containerID)
+
+	resp, err := c.ContainerStatsOneShot(context.Background(), containerID)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var stats types.StatsJSON
+
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+	if err != nil {
+		return 0, fmt.Errorf("cannot decode container stats: %w", err)
+	}
+
+	usage := stats.MemoryStats.Usage
+
+	// cgroup v1 reports total_inactive_file, cgroup v2 reports inactive_file.
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if v, ok := stats.MemoryStats.Stats[key]; ok && v < usage {
+			return usage - v, nil
+		}
+	}
+
+	return usage, nil
+}
+
+// DiskUsage returns the usage of the root filesystem of the running container. The root filesystem of the
+// containers is on the disk of Docker (the disk of the Docker Desktop VM on macOS and Windows).
+func (c *Client) DiskUsage(containerID string) (*DiskUsage, error) {
+	log.Debugf("Getting disk usage in container %s...", containerID)
+
+	ctx := context.Background()
+
+	exec, err := c.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{"df", "-Pk", "/"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create exec: %w", err)
+	}
+
+	resp, err := c.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot attach to exec: %w", err)
+	}
+	defer resp.Close()
+
+	var stdout, stderr bytes.Buffer
+
+	_, err = stdcopy.StdCopy(&stdout, &stderr, resp.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exec output: %w", err)
+	}
+
+	return parseDiskUsage(stdout.String())
+}
+
+// parseDiskUsage parses the output of `df -Pk`.
+func parseDiskUsage(out string) (*DiskUsage, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return nil, ErrCannotParseDiskUsage(out)
+	}
+
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return nil, ErrCannotParseDiskUsage(out)
+	}
+
+	total, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, ErrCannotParseDiskUsage(out)
+	}
+
+	available, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return nil, ErrCannotParseDiskUsage(out)
+	}
+
+	return &DiskUsage{Total: total * 1024, Available: available * 1024}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	compose "github.com/docker/cli/cli/compose/types"
 	"github.com/hashicorp/go-version"
@@ -18,17 +19,12 @@ import (
 	"github.com/rewardenv/reward/internal/shell"
 )
 
-const (
-	requiredVersion = "1.25.0"
-)
-
-var ErrDockerComposeVersionMismatch = func(s string) error {
-	return fmt.Errorf("docker-compose version is too old: %s", s)
-}
-
 type Client struct {
 	shell.Shell
 	TmpFiles *list.List
+
+	version   *version.Version
+	versionMu sync.Mutex
 }
 
 func NewClient(s shell.Shell, tmpFiles *list.List) *Client {
@@ -56,26 +52,16 @@ func NewMockClient(command string, output []byte, err error) *Client {
 	}
 }
 
-func (c *Client) Check() error {
-	ver, err := c.Version()
-	if err != nil {
-		return fmt.Errorf("failed to fetch docker-compose version: %w", err)
-	}
+// Version returns the version of docker-compose. The version is memoized, call InvalidateVersion if docker-compose
+// is changed.
+func (c *Client) Version() (*version.Version, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
 
-	if !c.isMinimumVersionInstalled() {
-		return ErrDockerComposeVersionMismatch(
-			fmt.Sprintf(
-				"your docker-compose version is %s, required version: %s",
-				ver.String(),
-				requiredVersion,
-			),
-		)
+	if c.version != nil {
+		return c.version, nil
 	}
 
-	return nil
-}
-
-func (c *Client) Version() (*version.Version, error) {
 	log.Debugln("Checking docker-compose version...")
 
 	data, err := c.RunCommand([]string{"version", "--short"},
@@ -92,28 +78,17 @@ func (c *Client) Version() (*version.Version, error) {
 
 	log.Debugf("...docker-compose version is: %s.", v.String())
 
+	c.version = v
+
 	return v, nil
 }
 
-func (c *Client) isMinimumVersionInstalled() bool {
-	log.Debugln("Checking docker-compose version requirements...")
-
-	v, err := c.Version()
-	if err != nil {
-		log.Debugln("...docker-compose version requirements not met.")
-
-		return false
-	}
-
-	if v.LessThan(version.Must(version.NewVersion(requiredVersion))) {
-		log.Debugln("...docker-compose version requirements not met.")
-
-		return false
-	}
-
-	log.Debugln("...docker-compose version requirements are met.")
+// InvalidateVersion removes the memoized version of docker-compose.
+func (c *Client) InvalidateVersion() {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
 
-	return true
+	c.version = nil
 }
 
 // RunCommand runs the passed parameters with docker-compose and returns the output.
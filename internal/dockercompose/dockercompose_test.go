@@ -2,7 +2,6 @@ package dockercompose
 
 import (
 	"container/list"
-	"fmt"
 	"io"
 	"os"
 	"reflect"
@@ -10,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/rewardenv/reward/internal/shell"
@@ -83,94 +83,25 @@ func (suite *DockerComposeTestSuite) TestMockClient_Version() {
 	}
 }
 
-func (suite *DockerComposeTestSuite) TestClient_minimumVersionInstalled() {
-	type fields struct {
-		Shell shell.Shell
-	}
+func (suite *DockerComposeTestSuite) TestClient_VersionMemoized() {
+	mock := shell.NewMockShell("", []byte("2.13.0"), nil)
+	c := NewClient(mock, list.New())
 
-	tests := []struct {
-		name   string
-		fields fields
-		want   bool
-	}{
-		{
-			name: "version below required version",
-			fields: fields{
-				shell.NewMockShell("", []byte("1.0"), nil),
-			},
-			want: false,
-		},
-		{
-			name: "version above required version",
-			fields: fields{
-				shell.NewMockShell("", []byte("2.0.0"), nil),
-			},
-			want: true,
-		},
-		{
-			name: "version is not valid",
-			fields: fields{
-				shell.NewMockShell("", []byte("not-a-valid-version"), nil),
-			},
-			want: false,
-		},
-	}
+	got, err := c.Version()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.13.0", got.String())
 
-	for _, tt := range tests {
-		suite.T().Run(tt.name, func(t *testing.T) {
-			c := &Client{
-				Shell: tt.fields.Shell,
-			}
-			if got := c.isMinimumVersionInstalled(); got != tt.want {
-				t.Errorf("minimumVersionInstalled() = %t, want %t", got, tt.want)
-			}
-		})
-	}
-}
+	mock.Output = []byte("2.20.0")
 
-func (suite *DockerComposeTestSuite) TestClient_Check() {
-	type fields struct {
-		Shell shell.Shell
-	}
+	got, err = c.Version()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.13.0", got.String(), "the version should be memoized")
 
-	tests := []struct {
-		name    string
-		fields  fields
-		wantErr bool
-	}{
-		{
-			name: "valid",
-			fields: fields{
-				shell.NewMockShell("", []byte("2.13.0"), nil),
-			},
-			wantErr: false,
-		},
-		{
-			name: "in valid version",
-			fields: fields{
-				shell.NewMockShell("", []byte("1.24.0"), nil),
-			},
-			wantErr: true,
-		},
-		{
-			name: "docker compose is not installed",
-			fields: fields{
-				shell.NewMockShell("", []byte("1.0.0"), fmt.Errorf("command not found")),
-			},
-			wantErr: true,
-		},
-	}
+	c.InvalidateVersion()
 
-	for _, tt := range tests {
-		suite.T().Run(tt.name, func(t *testing.T) {
-			c := &Client{
-				Shell: tt.fields.Shell,
-			}
-			if err := c.Check(); (err != nil) != tt.wantErr {
-				t.Errorf("Check() error = %s, wantErr %t", err, tt.wantErr)
-			}
-		})
-	}
+	got, err = c.Version()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.20.0", got.String())
 }
 
 func (suite *DockerComposeTestSuite) TestClient_RunCommand() {
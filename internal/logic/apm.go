@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// apmAgent is an APM agent which is installed in the php-fpm container when the environment is started.
+type apmAgent struct {
+	name string
+	// extension is the name of the PHP extension of the agent, the agent is not reinstalled if it's loaded.
+	extension string
+	// keySetting is the setting of the license or API key, which is passed to the containers in the environment.
+	keySetting string
+	keyEnv     string
+	// install installs the extension, ini configures it from the environment of the container.
+	install string
+	ini     string
+}
+
+// apmAgents are the supported APM agents by the names of their settings.
+var apmAgents = map[string]apmAgent{
+	"newrelic": {
+		name:       "New Relic",
+		extension:  "newrelic",
+		keySetting: "newrelic_license_key",
+		keyEnv:     "NEWRELIC_LICENSE_KEY",
+		install: `release=$(curl -fsSL https://download.newrelic.com/php_agent/release/ \
+  | grep -o 'newrelic-php5-[0-9.]*-linux\.tar\.gz' | head -n 1)
+curl -fsSL "https://download.newrelic.com/php_agent/release/${release}" | tar -xz -C /tmp
+NR_INSTALL_SILENT=1 NR_INSTALL_USE_CP_NOT_LN=1 /tmp/newrelic-php5-*-linux/newrelic-install install
+rm -rf /tmp/newrelic-php5-*-linux`,
+		ini: `newrelic.license="${NEWRELIC_LICENSE_KEY}"
+newrelic.appname="${NEWRELIC_APPNAME}"
+newrelic.daemon.address="${NEWRELIC_DAEMON_ADDRESS}"
+newrelic.daemon.dont_launch=3`,
+	},
+	"datadog": {
+		name:       "Datadog",
+		extension:  "ddtrace",
+		keySetting: "dd_api_key",
+		keyEnv:     "DD_API_KEY",
+		install: `curl -fsSL -o /tmp/datadog-setup.php \
+  https://github.com/DataDog/dd-trace-php/releases/latest/download/datadog-setup.php
+php /tmp/datadog-setup.php --php-bin=all
+rm -f /tmp/datadog-setup.php`,
+		ini: `datadog.agent_host="${DD_AGENT_HOST}"
+datadog.trace.agent_port="${DD_TRACE_AGENT_PORT}"
+datadog.service="${DD_SERVICE}"
+datadog.env="${DD_ENV}"`,
+	},
+}
+
+// enabledAPMAgents returns the names of the enabled APM agents.
+func (c *Client) enabledAPMAgents() []string {
+	var agents []string
+
+	if c.NewRelicEnabled() {
+		agents = append(agents, "newrelic")
+	}
+
+	if c.DatadogEnabled() {
+		agents = append(agents, "datadog")
+	}
+
+	return agents
+}
+
+// exportAPMKeys passes the license and API keys of the enabled APM agents to docker compose in the environment, so
+// the keys are not written to the compose configuration. The keys set in the environment are not overridden.
+func (c *Client) exportAPMKeys() {
+	for _, name := range c.enabledAPMAgents() {
+		agent := apmAgents[name]
+
+		if key := c.GetString(agent.keySetting); key != "" && os.Getenv(agent.keyEnv) == "" {
+			_ = os.Setenv(agent.keyEnv, key)
+		}
+	}
+}
+
+// apmAgentScript returns the script which installs the agent in the php-fpm container if its extension is not loaded
+// yet, configures it and reloads php-fpm. The ini file references the environment variables, so the keys are not
+// stored in the container.
+func apmAgentScript(agent apmAgent) string {
+	return strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("php -m | grep -qx %s && exit 0", agent.extension),
+		agent.install,
+		fmt.Sprintf("cat > \"$(php -r 'echo PHP_CONFIG_FILE_SCAN_DIR;')/zz-reward-%s.ini\" <<'EOF'", agent.extension),
+		agent.ini,
+		"EOF",
+		"pkill -USR2 -o -f 'php-fpm: master' || true",
+	}, "\n")
+}
+
+// installAPMAgents installs the enabled APM agents in the php-fpm container. The agents are installed at runtime,
+// so they work with the default php-fpm images, and they are installed again when the container is recreated.
+func (c *Client) installAPMAgents() {
+	if !c.SvcEnabledStrict("php_fpm") {
+		return
+	}
+
+	for _, name := range c.enabledAPMAgents() {
+		agent := apmAgents[name]
+
+		log.Printf("Installing the %s agent in the php-fpm container...", agent.name)
+
+		out, err := c.RunCmdEnvDockerComposeOutput([]string{
+			"exec", "-T", "--user", "root", "php-fpm", "bash", "-c", apmAgentScript(agent),
+		})
+		if err != nil {
+			log.Warnf("Cannot install the %s agent: %s", agent.name, strings.TrimSpace(out))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type APMTestSuite struct {
+	suite.Suite
+}
+
+func TestAPMTestSuite(t *testing.T) {
+	suite.Run(t, new(APMTestSuite))
+}
+
+func (suite *APMTestSuite) TestExportAPMKeys() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		env      string
+		want     string
+	}{
+		{
+			name:     "disabled",
+			settings: map[string]interface{}{"newrelic_license_key": "license"},
+		},
+		{
+			name:     "enabled",
+			settings: map[string]interface{}{"reward_newrelic": true, "newrelic_license_key": "license"},
+			want:     "license",
+		},
+		{
+			name:     "set in the environment",
+			settings: map[string]interface{}{"reward_newrelic": true, "newrelic_license_key": "license"},
+			env:      "other",
+			want:     "other",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			t.Setenv("NEWRELIC_LICENSE_KEY", tt.env)
+
+			newTestClient(tt.settings).exportAPMKeys()
+
+			assert.Equal(t, tt.want, os.Getenv("NEWRELIC_LICENSE_KEY"))
+		})
+	}
+}
+
+func (suite *APMTestSuite) TestAPMAgentScript() {
+	for name, agent := range apmAgents {
+		suite.T().Run(name, func(t *testing.T) {
+			script := apmAgentScript(agent)
+
+			assert.Contains(t, script, "php -m | grep -qx "+agent.extension+" && exit 0")
+			assert.Contains(t, script, "/zz-reward-"+agent.extension+".ini\" <<'EOF'")
+			assert.NotContains(t, script, agent.keySetting)
+		})
+	}
+}
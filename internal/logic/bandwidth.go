@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// composeParallelLimitEnv is the environment variable of docker compose which limits the number of its concurrent
+// operations (eg. the image pulls).
+const composeParallelLimitEnv = "COMPOSE_PARALLEL_LIMIT"
+
+// composePullCommands are the docker compose commands which pull the missing images.
+var composePullCommands = []string{"pull", "up", "create", "run"}
+
+// limitPullConcurrency limits the number of the images pulled concurrently by the docker compose command if the pull
+// concurrency is configured (or the bandwidth is limited), so the pulls don't saturate a slow connection. The limit
+// is passed in the environment, which is inherited by docker compose, and it doesn't override the limit set by the
+// user.
+func (c *Client) limitPullConcurrency(args []string) {
+	n := c.PullConcurrency()
+	if n <= 0 || len(args) == 0 || os.Getenv(composeParallelLimitEnv) != "" {
+		return
+	}
+
+	for _, command := range composePullCommands {
+		if args[0] == command {
+			log.Debugf("Limiting the concurrent image pulls to %d...", n)
+
+			_ = os.Setenv(composeParallelLimitEnv, strconv.Itoa(n))
+
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BandwidthTestSuite struct {
+	suite.Suite
+}
+
+func TestBandwidthTestSuite(t *testing.T) {
+	suite.Run(t, new(BandwidthTestSuite))
+}
+
+func (suite *BandwidthTestSuite) TestLimitPullConcurrency() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		env      string
+		args     []string
+		want     string
+	}{
+		{
+			name: "unlimited",
+			args: []string{"pull"},
+		},
+		{
+			name:     "bandwidth limit",
+			settings: map[string]interface{}{"reward_bandwidth_limit": "1MiB"},
+			args:     []string{"up", "-d"},
+			want:     "1",
+		},
+		{
+			name:     "pull concurrency",
+			settings: map[string]interface{}{"reward_bandwidth_limit": "1MiB", "reward_pull_concurrency": 3},
+			args:     []string{"pull"},
+			want:     "3",
+		},
+		{
+			name:     "not pulling",
+			settings: map[string]interface{}{"reward_pull_concurrency": 3},
+			args:     []string{"stop"},
+		},
+		{
+			name:     "set by the user",
+			settings: map[string]interface{}{"reward_pull_concurrency": 3},
+			env:      "2",
+			args:     []string{"pull"},
+			want:     "2",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			t.Setenv(composeParallelLimitEnv, tt.env)
+
+			newTestClient(tt.settings).limitPullConcurrency(tt.args)
+
+			assert.Equal(t, tt.want, os.Getenv(composeParallelLimitEnv))
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+// ErrBenchContainerNotRunning occurs when the container which is required by a benchmark is not running.
+var ErrBenchContainerNotRunning = func(container string) error {
+	return fmt.Errorf("container is not running: %s", container)
+}
+
+// The reference results score 100 points. They are the results of a Linux host with native mounts, so a higher score
+// is better and the scores of the same metric are comparable between setups.
+const (
+	benchReferenceStaticTTFB    = 5 * time.Millisecond
+	benchReferencePHPLatency    = 10 * time.Millisecond
+	benchReferenceSyncRoundTrip = 50 * time.Millisecond
+	benchReferenceDBInserts     = 1000.0
+
+	benchSyncTimeout = 30 * time.Second
+	benchFilePrefix  = "reward-bench"
+)
+
+// benchResult is the result of a benchmark metric.
+type benchResult struct {
+	Metric string
+	Result string
+	Score  float64
+	Err    error
+}
+
+// RunCmdBench measures the performance of the environment: the time to first byte of a static file served through
+// traefik, the latency of a php-fpm hello world script, the round-trip time of the file synchronization between the
+// host and the containers and the insert throughput of the database.
+func (c *Client) RunCmdBench(cmd *cmdpkg.Command) error {
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	rows, _ := cmd.Flags().GetInt("rows")
+
+	if iterations < 1 {
+		iterations = 1
+	}
+
+	if rows < 1 {
+		rows = 1
+	}
+
+	results := []benchResult{
+		c.benchStaticTTFB(iterations),
+		c.benchPHPLatency(iterations),
+		c.benchSyncRoundTrip(iterations),
+		c.benchDBInserts(rows),
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Metric", "Result", "Score"})
+
+	for _, r := range results {
+		if r.Err != nil {
+			log.Debugf("Benchmark %s failed: %s", r.Metric, r.Err)
+			t.AppendRow(table.Row{r.Metric, fmt.Sprintf("skipped: %s", r.Err), "-"})
+
+			continue
+		}
+
+		t.AppendRow(table.Row{r.Metric, r.Result, fmt.Sprintf("%.0f", r.Score)})
+	}
+
+	t.AppendFooter(table.Row{"", "Total", fmt.Sprintf("%.0f", benchScore(results))})
+	t.Render()
+
+	log.Println("Higher scores are better, 100 points equals a Linux host with native mounts.")
+
+	return nil
+}
+
+// benchStaticTTFB measures the time to first byte of a static file requested through traefik.
+func (c *Client) benchStaticTTFB(iterations int) benchResult {
+	result := benchResult{Metric: "Static file TTFB (traefik)"}
+
+	container := c.DefaultSyncedContainer(c.EnvType())
+	if !c.Docker.ContainerRunning(container) {
+		result.Err = ErrBenchContainerNotRunning(container)
+
+		return result
+	}
+
+	dir := c.DefaultSyncedDir(c.EnvType()) + benchPublicDir(c.EnvType())
+	file := benchFilePrefix + ".txt"
+
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", container, "sh", "-c", fmt.Sprintf("echo ok > %s/%s", dir, file),
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("cannot create static file: %s: %w", strings.TrimSpace(out), err)
+
+		return result
+	}
+
+	defer func() {
+		_, _ = c.RunCmdEnvDockerComposeOutput([]string{"exec", "-T", container, "rm", "-f", dir + "/" + file})
+	}()
+
+	client := c.traefikHTTPClient(c.TraefikFullDomain(), 10*time.Second)
+	url := fmt.Sprintf("https://%s/%s", c.TraefikFullDomain(), file)
+
+	// The first request establishes the connection, it is not measured.
+	samples := make([]time.Duration, 0, iterations)
+
+	for i := 0; i <= iterations; i++ {
+		ttfb, err := benchRequest(client, url)
+		if err != nil {
+			result.Err = err
+
+			return result
+		}
+
+		if i > 0 {
+			samples = append(samples, ttfb)
+		}
+	}
+
+	latency := median(samples)
+	result.Result = fmt.Sprintf("%s (median of %d)", latency.Round(10*time.Microsecond), iterations)
+	result.Score = latencyScore(latency, benchReferenceStaticTTFB)
+
+	return result
+}
+
+func benchRequest(client *http.Client, url string) (time.Duration, error) {
+	var start, firstByte time.Time
+
+	req, err := http.NewRequestWithContext(
+		httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+			GotFirstResponseByte: func() {
+				firstByte = time.Now()
+			},
+		}),
+		http.MethodGet,
+		url,
+		nil,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create request: %w", err)
+	}
+
+	start = time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("cannot request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected response status of %s: %s", url, resp.Status)
+	}
+
+	return firstByte.Sub(start), nil
+}
+
+// benchPHPLatency measures the latency of a hello world script requested from php-fpm through FastCGI.
+func (c *Client) benchPHPLatency(iterations int) benchResult {
+	result := benchResult{Metric: "PHP-FPM hello world latency"}
+
+	if !c.Docker.ContainerRunning("php-fpm") {
+		result.Err = ErrBenchContainerNotRunning("php-fpm")
+
+		return result
+	}
+
+	script := fmt.Sprintf("/tmp/%s.php", benchFilePrefix)
+
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", "php-fpm", "sh", "-c",
+		fmt.Sprintf(`printf '<?php echo "ok";' > %[1]s
+for i in $(seq 0 %[2]d); do
+  s=$(date +%%s%%N)
+  SCRIPT_NAME=%[1]s SCRIPT_FILENAME=%[1]s REQUEST_METHOD=GET \
+    cgi-fcgi -bind -connect 127.0.0.1:${NGINX_UPSTREAM_PORT:-9000} > /dev/null || exit 1
+  echo $(($(date +%%s%%N) - s))
+done
+rm -f %[1]s`, script, iterations),
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("cannot request php-fpm: %s: %w", strings.TrimSpace(out), err)
+
+		return result
+	}
+
+	samples, err := parseDurations(out)
+	if err != nil || len(samples) < 2 {
+		result.Err = fmt.Errorf("cannot parse php-fpm latencies: %s", strings.TrimSpace(out))
+
+		return result
+	}
+
+	// The first request warms up the opcache, it is not measured.
+	latency := median(samples[1:])
+	result.Result = fmt.Sprintf("%s (median of %d)", latency.Round(10*time.Microsecond), iterations)
+	result.Score = latencyScore(latency, benchReferencePHPLatency)
+
+	return result
+}
+
+// benchSyncRoundTrip measures the time it takes until a file created on the host appears in the container plus the
+// time until a file created in the container appears on the host.
+func (c *Client) benchSyncRoundTrip(iterations int) benchResult {
+	result := benchResult{Metric: "File sync round-trip"}
+
+	probe, err := c.startSyncProbe(benchSyncTimeout)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+	defer probe.Close()
+
+	samples := make([]time.Duration, 0, iterations)
+
+	for i := 0; i < iterations; i++ {
+		hostToContainer, err := probe.HostToContainer()
+		if err != nil {
+			result.Err = err
+
+			return result
+		}
+
+		containerToHost, err := probe.ContainerToHost()
+		if err != nil {
+			result.Err = err
+
+			return result
+		}
+
+		samples = append(samples, hostToContainer+containerToHost)
+	}
+
+	latency := median(samples)
+	result.Result = fmt.Sprintf("%s (median of %d)", latency.Round(100*time.Microsecond), iterations)
+	result.Score = latencyScore(latency, benchReferenceSyncRoundTrip)
+
+	return result
+}
+
+// benchDBInserts measures the throughput of single row inserts (one transaction per row) into an InnoDB table.
+func (c *Client) benchDBInserts(rows int) benchResult {
+	result := benchResult{Metric: "Database inserts"}
+
+	if !c.Docker.ContainerRunning(c.DBContainer()) {
+		result.Err = ErrBenchContainerNotRunning(c.DBContainer())
+
+		return result
+	}
+
+	table := strings.ReplaceAll(benchFilePrefix, "-", "_")
+
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", c.DBContainer(), "sh", "-c",
+		fmt.Sprintf(`q() { MYSQL_PWD="$MYSQL_PASSWORD" %[1]s -u"$MYSQL_USER" "$MYSQL_DATABASE" "$@"; }
+q -e 'DROP TABLE IF EXISTS %[2]s; CREATE TABLE %[2]s (id INT AUTO_INCREMENT PRIMARY KEY, v INT) ENGINE=InnoDB' || exit 1
+s=$(date +%%s%%N)
+seq %[3]d | sed 's/.*/INSERT INTO %[2]s (v) VALUES (&);/' | q || exit 1
+e=$(date +%%s%%N)
+q -e 'DROP TABLE %[2]s'
+echo $((e - s))`, c.DBCommand(), table, rows),
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("cannot insert rows: %s: %w", strings.TrimSpace(out), err)
+
+		return result
+	}
+
+	samples, err := parseDurations(out)
+	if err != nil || len(samples) != 1 || samples[0] <= 0 {
+		result.Err = fmt.Errorf("cannot parse insert duration: %s", strings.TrimSpace(out))
+
+		return result
+	}
+
+	throughput := float64(rows) / samples[0].Seconds()
+	result.Result = fmt.Sprintf("%.0f rows/s (%d rows)", throughput, rows)
+	result.Score = 100 * throughput / benchReferenceDBInserts
+
+	return result
+}
+
+// benchPublicDir returns the public directory of the web server (the NGINX_PUBLIC variable of the environment type).
+func benchPublicDir(envType string) string {
+	switch envType {
+	case "magento2":
+		return "/pub"
+	case "laravel", "shopware", "symfony":
+		return "/public"
+	}
+
+	return ""
+}
+
+// parseDurations parses the durations printed in nanoseconds, one per line. Other lines are ignored.
+func parseDurations(s string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		durations = append(durations, time.Duration(n))
+	}
+
+	if len(durations) == 0 {
+		return nil, fmt.Errorf("no durations found")
+	}
+
+	return durations, nil
+}
+
+func median(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+
+	return (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+}
+
+func latencyScore(latency, reference time.Duration) float64 {
+	if latency <= 0 {
+		latency = time.Microsecond
+	}
+
+	return 100 * float64(reference) / float64(latency)
+}
+
+// benchScore returns the geometric mean of the scores of the successful benchmarks, so a single metric cannot
+// dominate the total score.
+func benchScore(results []benchResult) float64 {
+	sum, n := 0.0, 0
+
+	for _, r := range results {
+		if r.Err != nil || r.Score <= 0 {
+			continue
+		}
+
+		sum += math.Log(r.Score)
+		n++
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return math.Exp(sum / float64(n))
+}
@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BenchTestSuite struct {
+	suite.Suite
+}
+
+func TestBenchTestSuite(t *testing.T) {
+	suite.Run(t, new(BenchTestSuite))
+}
+
+func (suite *BenchTestSuite) TestMedian() {
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    time.Duration
+	}{
+		{name: "empty", samples: nil, want: 0},
+		{name: "odd", samples: []time.Duration{30, 10, 20}, want: 20},
+		{name: "even", samples: []time.Duration{40, 10, 30, 20}, want: 25},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, median(tt.samples))
+		})
+	}
+}
+
+func (suite *BenchTestSuite) TestParseDurations() {
+	got, err := parseDurations("Warning: something\n1500000\n\n2500000\n")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []time.Duration{1500 * time.Microsecond, 2500 * time.Microsecond}, got)
+
+	_, err = parseDurations("command not found\n")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *BenchTestSuite) TestBenchScore() {
+	tests := []struct {
+		name    string
+		results []benchResult
+		want    float64
+	}{
+		{name: "no results", want: 0},
+		{
+			name:    "geometric mean",
+			results: []benchResult{{Score: 400}, {Score: 25}},
+			want:    100,
+		},
+		{
+			name:    "failed benchmarks are skipped",
+			results: []benchResult{{Score: 50}, {Score: 1000, Err: errors.New("container is not running")}},
+			want:    50,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, benchScore(tt.results), 0.001)
+		})
+	}
+}
+
+func (suite *BenchTestSuite) TestLatencyScore() {
+	assert.InDelta(suite.T(), 200.0, latencyScore(5*time.Millisecond, 10*time.Millisecond), 0.001)
+	assert.InDelta(suite.T(), 50.0, latencyScore(20*time.Millisecond, 10*time.Millisecond), 0.001)
+}
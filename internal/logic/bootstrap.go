@@ -3,17 +3,26 @@ package logic
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-version"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/rewardenv/reward/internal/config"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+const (
+	magentoRepositoryURL = "https://repo.magento.com/"
+	mageOSRepositoryURL  = "https://repo.mage-os.org/"
+)
+
 type bootstrapper struct {
 	*Client
 	composerVerbosityFlag string
 	debug                 bool
+	// magentoVersionInfo is the Magento version of the project, it's resolved when the Magento 2 bootstrap starts.
+	magentoVersionInfo *config.MagentoVersionInfo
 }
 
 func newBootstrapper(c *Client) *bootstrapper {
@@ -31,6 +40,8 @@ func newBootstrapper(c *Client) *bootstrapper {
 
 // RunCmdBootstrap represents the bootstrap command.
 func (c *Client) RunCmdBootstrap() error {
+	c.RefreshVersionMatrix()
+
 	switch c.EnvType() {
 	case "magento2":
 		err := newBootstrapper(c).bootstrapMagento2()
@@ -52,6 +63,21 @@ func (c *Client) RunCmdBootstrap() error {
 		if err != nil {
 			return fmt.Errorf("error bootstrapping shopware: %w", err)
 		}
+	case "sylius":
+		err := newBootstrapper(c).bootstrapSylius()
+		if err != nil {
+			return fmt.Errorf("error bootstrapping sylius: %w", err)
+		}
+	case "akeneo":
+		err := newBootstrapper(c).bootstrapAkeneo()
+		if err != nil {
+			return fmt.Errorf("error bootstrapping akeneo: %w", err)
+		}
+	case "oro":
+		err := newBootstrapper(c).bootstrapOro()
+		if err != nil {
+			return fmt.Errorf("error bootstrapping oro: %w", err)
+		}
 	default:
 		return fmt.Errorf("currently not supported for bootstrapping")
 	}
@@ -68,24 +94,20 @@ func (c *bootstrapper) prepare() error {
 	}
 
 	log.Println("...common services started.")
-	log.Println("Preparing certificate...")
 
-	err = c.RunCmdSignCertificate([]string{c.TraefikDomain()}, true)
+	step := c.newProgress().Step("Preparing certificate")
+
+	err = step.Finish(c.RunCmdSignCertificate([]string{c.TraefikDomain()}, true))
 	if err != nil {
 		return fmt.Errorf("cannot sign certificate: %w", err)
 	}
 
-	log.Println("...certificate ready.")
-
+	// the pull, build and up commands render their own steps
 	if !c.NoPull() {
-		log.Println("Pulling images...")
-
 		err = c.RunCmdEnv([]string{"pull"})
 		if err != nil {
 			return fmt.Errorf("cannot pull env containers: %w", err)
 		}
-
-		log.Println("...images pulled.")
 	}
 
 	log.Println("Preparing environment...")
@@ -100,6 +122,14 @@ func (c *bootstrapper) prepare() error {
 		return fmt.Errorf("cannot start env containers: %w", err)
 	}
 
+	// the installers and the imports fail if the database is not ready yet
+	if c.IsSvcEnabled("db") {
+		err = c.waitForDBReady(c.DBReadyTimeout())
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Println("...environment ready.")
 
 	return nil
@@ -133,13 +163,19 @@ func (c *bootstrapper) download() (bool, error) {
 
 			freshInstall = true
 
+			repositoryURL, project := magentoRepositoryURL, fmt.Sprintf("magento/project-%s-edition", c.MagentoType())
+			if c.MagentoType() == "mage-os" {
+				repositoryURL, project = mageOSRepositoryURL, "mage-os/project-community-edition"
+			}
+
 			err = c.RunCmdEnvExec(
 				fmt.Sprintf(
 					"composer create-project %s --profile --no-install "+
-						"--repository-url=https://repo.magento.com/ "+
-						"magento/project-%s-edition=%s /tmp/magento-tmp/",
+						"--repository-url=%s "+
+						"%s=%s /tmp/magento-tmp/",
 					composerVerbosityFlag,
-					c.MagentoType(),
+					repositoryURL,
+					project,
 					magentoVersion.String(),
 				),
 			)
@@ -218,11 +254,115 @@ func (c *bootstrapper) download() (bool, error) {
 
 			log.Println("...Shopware downloaded.")
 		}
+
+	case "sylius":
+		if !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "composer.json")) {
+			log.Println("Creating Sylius composer project...")
+
+			freshInstall = true
+
+			syliusVersion, err := c.SyliusVersion()
+			if err != nil {
+				return false, fmt.Errorf("cannot determine sylius version: %w", err)
+			}
+
+			err = c.createComposerProject("sylius/sylius-standard", composerVersionConstraint(syliusVersion))
+			if err != nil {
+				return false, fmt.Errorf("cannot create composer sylius project: %w", err)
+			}
+
+			log.Println("...Sylius composer project created.")
+		}
+
+	case "akeneo":
+		if !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "composer.json")) {
+			log.Println("Creating Akeneo PIM composer project...")
+
+			freshInstall = true
+
+			akeneoVersion, err := c.AkeneoVersion()
+			if err != nil {
+				return false, fmt.Errorf("cannot determine akeneo version: %w", err)
+			}
+
+			err = c.createComposerProject("akeneo/pim-community-standard", composerVersionConstraint(akeneoVersion))
+			if err != nil {
+				return false, fmt.Errorf("cannot create composer akeneo project: %w", err)
+			}
+
+			log.Println("...Akeneo PIM composer project created.")
+		}
+
+	case "oro":
+		if !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "composer.json")) {
+			log.Println("Creating OroCommerce composer project...")
+
+			freshInstall = true
+
+			oroVersion, err := c.OroVersion()
+			if err != nil {
+				return false, fmt.Errorf("cannot determine oro version: %w", err)
+			}
+
+			err = c.createComposerProject("oro/commerce-crm-application", composerVersionConstraint(oroVersion))
+			if err != nil {
+				return false, fmt.Errorf("cannot create composer oro project: %w", err)
+			}
+
+			log.Println("...OroCommerce composer project created.")
+		}
 	}
 
 	return freshInstall, nil
 }
 
+// createComposerProject creates the composer project in a temporary directory and moves it to the web root. The
+// .env file of the project contains the defaults of the application, it's merged into the .env file of the
+// environment, so the settings of the environment (eg. the database credentials) take precedence.
+func (c *bootstrapper) createComposerProject(project, constraint string) error {
+	err := c.RunCmdEnvExec(
+		fmt.Sprintf(
+			"composer create-project %s --profile --no-install --no-scripts %s=%s /tmp/project-tmp/",
+			c.composerVerbosityFlag,
+			project,
+			constraint,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create composer project: %w", err)
+	}
+
+	err = c.RunCmdEnvExec(
+		"if [ -f /tmp/project-tmp/.env ]; then " +
+			"cat /tmp/project-tmp/.env /var/www/html/.env > /tmp/project.env && " +
+			"cat /tmp/project.env > /var/www/html/.env && " +
+			"rm -f /tmp/project-tmp/.env /tmp/project.env; " +
+			"fi",
+	)
+	if err != nil {
+		return fmt.Errorf("cannot merge .env file: %w", err)
+	}
+
+	err = c.RunCmdEnvExec("rsync -au --remove-source-files --chmod=D2775,F644 /tmp/project-tmp/ /var/www/html/")
+	if err != nil {
+		return fmt.Errorf("cannot move project files: %w", err)
+	}
+
+	return nil
+}
+
+// composerVersionConstraint returns the composer version constraint of the version. The versions without a patch
+// number (eg. 1.12) install the latest patch release.
+func composerVersionConstraint(v *version.Version) string {
+	if strings.Count(v.Original(), ".") >= 2 {
+		return v.Original()
+	}
+
+	segments := v.Segments()
+
+	return fmt.Sprintf("~%d.%d.0", segments[0], segments[1])
+}
+
 func (c *bootstrapper) composerInstall() error {
 	if c.SkipComposerInstall() {
 		return nil
@@ -0,0 +1,144 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/sethvargo/go-password/password"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// akeneoDemoCatalog is the catalog of the demo products which is installed by the full bootstrap.
+const akeneoDemoCatalog = "vendor/akeneo/pim-community-dev/src/Akeneo/Platform/Bundle/InstallerBundle/" +
+	"Resources/fixtures/icecat_demo_dev"
+
+// bootstrapAkeneo runs a full Akeneo PIM bootstrap process.
+func (c *bootstrapper) bootstrapAkeneo() error {
+	akeneoVersion, err := c.AkeneoVersion()
+	if err != nil {
+		return fmt.Errorf("cannot determine akeneo version: %w", err)
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf("Would you like to bootstrap Akeneo PIM %s?", akeneoVersion.Original())) {
+		return nil
+	}
+
+	log.Printf("Bootstrapping Akeneo PIM %s...", akeneoVersion.Original())
+
+	err = c.prepare()
+	if err != nil {
+		return fmt.Errorf("error during bootstrap preparation: %w", err)
+	}
+
+	err = c.composerPreInstall()
+	if err != nil {
+		return err
+	}
+
+	freshInstall, err := c.download()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerInstall()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerPostInstall()
+	if err != nil {
+		return err
+	}
+
+	err = c.installAkeneo(freshInstall)
+	if err != nil {
+		return err
+	}
+
+	adminPassword := ""
+	if freshInstall {
+		adminPassword, err = c.installAkeneoConfigureAdminUser()
+		if err != nil {
+			return err
+		}
+	}
+
+	// the job queue consumer waits for the installation, restarting it skips the remaining delay
+	err = c.RunCmdEnv([]string{"restart", "php-worker"})
+	if err != nil {
+		return fmt.Errorf("cannot restart job queue consumer: %w", err)
+	}
+
+	log.Printf("Base Url: https://%s", c.TraefikFullDomain())
+
+	if adminPassword != "" {
+		log.Println("Admin user: admin")
+		log.Printf("Admin password: %s", adminPassword)
+	}
+
+	log.Println("...bootstrap process finished.")
+
+	return nil
+}
+
+func (c *bootstrapper) installAkeneo(freshInstall bool) error {
+	log.Println("Installing Akeneo PIM assets...")
+
+	err := c.RunCmdEnvExec("php bin/console pim:installer:assets --symlink --clean")
+	if err != nil {
+		return fmt.Errorf("cannot install assets: %w", err)
+	}
+
+	log.Println("...assets installed.")
+
+	if freshInstall {
+		log.Println("Installing Akeneo PIM database...")
+
+		catalog := ""
+		if c.FullBootstrap() {
+			catalog = "--catalog " + akeneoDemoCatalog
+		}
+
+		err = c.RunCmdEnvExec(fmt.Sprintf("php -d memory_limit=-1 bin/console pim:installer:db %s", catalog))
+		if err != nil {
+			return fmt.Errorf("cannot install database: %w", err)
+		}
+
+		log.Println("...database installed.")
+	}
+
+	log.Println("Building front-end...")
+
+	err = c.RunCmdEnvExec("yarn install && yarn run update-extensions && yarn run less && yarn run webpack-dev")
+	if err != nil {
+		return fmt.Errorf("cannot build front-end: %w", err)
+	}
+
+	log.Println("...front-end built.")
+
+	return nil
+}
+
+func (c *bootstrapper) installAkeneoConfigureAdminUser() (string, error) {
+	log.Println("Creating admin user...")
+
+	adminPassword, err := password.Generate(16, 2, 0, false, false)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate admin password: %w", err)
+	}
+
+	err = c.RunCmdEnvExec(
+		fmt.Sprintf(
+			`php bin/console pim:user:create admin "%s" admin@example.com Admin Local en_US --admin --no-interaction`,
+			adminPassword,
+		),
+	)
+	if err != nil {
+		return "", fmt.Errorf("cannot create admin user: %w", err)
+	}
+
+	log.Println("...admin user created.")
+
+	return adminPassword, nil
+}
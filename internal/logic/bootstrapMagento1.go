@@ -17,14 +17,14 @@ import (
 // bootstrapMagento1 runs a full Magento 1 bootstrap process.
 // Note: it will not install Magento 1 from zero, but only configures Magento 1's local.xml.
 func (c *bootstrapper) bootstrapMagento1() error {
-	magentoVersion, err := c.MagentoVersion()
+	info, err := c.MagentoVersionInfo()
 	if err != nil {
 		return fmt.Errorf("cannot to get magento version: %w", err)
 	}
 
-	log.Printf("Bootstrapping Magento %s...", magentoVersion.String())
+	log.Printf("Bootstrapping Magento %s (version found in %s)...", info.Version.String(), info.Source)
 
-	if !util.AskForConfirmation("Would you like to bootstrap Magento v" + magentoVersion.String() + "?") {
+	if !util.AskForConfirmation("Would you like to bootstrap Magento v" + info.Version.String() + "?") {
 		return nil
 	}
 
@@ -8,23 +8,43 @@ import (
 	"github.com/sethvargo/go-password/password"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/rewardenv/reward/internal/config"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
 // bootstrapMagento2 runs a full Magento 2 bootstrap process.
 func (c *bootstrapper) bootstrapMagento2() error {
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		return fmt.Errorf("cannot determine the magento version: %w", err)
+	}
+
+	c.magentoVersionInfo = info
+
+	if c.BootstrapDump() != "" {
+		return c.bootstrapMagento2FromDump()
+	}
+
 	if !util.AskForConfirmation(
 		fmt.Sprintf(
 			"Would you like to bootstrap Magento v%s?",
-			c.magento2Version().String(),
+			info.Version.String(),
 		),
 	) {
 		return nil
 	}
 
-	log.Printf("Bootstrapping Magento %s...", c.magento2Version().String())
+	log.Printf("Bootstrapping Magento %s %s (version found in %s)...", info.Edition, info.Version, info.Source)
+
+	if info.Edition == "mage-os" {
+		log.Printf("...Mage-OS %s is based on Magento Open Source %s.", info.Version, info.MagentoVersion)
+	}
+
+	if info.B2B {
+		log.Println("...the project requires the Adobe Commerce B2B extension.")
+	}
 
-	err := c.prepare()
+	err = c.prepare()
 	if err != nil {
 		return fmt.Errorf("error during preparation: %w", err)
 	}
@@ -39,6 +59,11 @@ func (c *bootstrapper) bootstrapMagento2() error {
 		return fmt.Errorf("error during download: %w", err)
 	}
 
+	err = c.composerConfigureMageOS()
+	if err != nil {
+		return fmt.Errorf("error during composer repository configuration: %w", err)
+	}
+
 	err = c.composerInstall()
 	if err != nil {
 		return fmt.Errorf("error during composer install: %w", err)
@@ -67,13 +92,37 @@ func (c *bootstrapper) minimumMagento2VersionForSearch() *version.Version {
 	return version.Must(version.NewVersion("2.4.0"))
 }
 
+// magento2VersionInfo returns the Magento version of the project resolved when the bootstrap started.
+func (c *bootstrapper) magento2VersionInfo() *config.MagentoVersionInfo {
+	return c.magentoVersionInfo
+}
+
+// magento2Version returns the major.minor.patch part of the Magento version for comparisons. Patch releases
+// (eg. 2.4.6-p3) would be parsed as pre-releases of 2.4.6 otherwise. For Mage-OS it returns the Magento Open Source
+// version the release is based on.
 func (c *bootstrapper) magento2Version() *version.Version {
-	v, err := c.MagentoVersion()
+	return c.magento2VersionInfo().MagentoVersion.Core()
+}
+
+// composerConfigureMageOS adds the Mage-OS composer repository to the project, because the Mage-OS packages are not
+// available on packagist.
+func (c *bootstrapper) composerConfigureMageOS() error {
+	if c.SkipComposerInstall() || c.MagentoType() != "mage-os" {
+		return nil
+	}
+
+	log.Println("Configuring Mage-OS composer repository...")
+
+	err := c.RunCmdEnvExec(
+		fmt.Sprintf("composer config repositories.mage-os composer %s", mageOSRepositoryURL),
+	)
 	if err != nil {
-		log.Panicln(err)
+		return fmt.Errorf("cannot configure mage-os composer repository: %w", err)
 	}
 
-	return v
+	log.Println("...Mage-OS composer repository configured.")
+
+	return nil
 }
 
 func (c *bootstrapper) magento2VerbosityFlag() string {
@@ -236,12 +285,17 @@ func (c *bootstrapper) installMagento2Reindex() error {
 func (c *bootstrapper) installMagento2ConfigureAdminUser() (string, error) {
 	log.Println("Creating admin user...")
 
-	adminPassword, err := password.Generate(16, 2, 0, false, false)
-	if err != nil {
-		return "", fmt.Errorf("cannot generate admin password: %w", err)
+	adminPassword := c.MagentoAdminPassword()
+	if adminPassword == "" {
+		var err error
+
+		adminPassword, err = password.Generate(16, 2, 0, false, false)
+		if err != nil {
+			return "", fmt.Errorf("cannot generate admin password: %w", err)
+		}
 	}
 
-	err = c.RunCmdEnvExec(
+	err := c.RunCmdEnvExec(
 		fmt.Sprintf(
 			`bin/magento admin:user:create --admin-password=%s `+
 				`--admin-user=localadmin --admin-firstname=Local --admin-lastname=Admin --admin-email="admin@example.com"`,
@@ -286,10 +340,14 @@ func (c *bootstrapper) installMagento2DeploySampleData(freshInstall bool) error
 	if freshInstall && (c.WithSampleData() || c.FullBootstrap()) {
 		log.Println("Installing sample data...")
 
-		err := c.RunCmdEnvExec(
-			"mkdir -p /var/www/html/var/composer_home/ && " +
-				"cp -va ~/.composer/auth.json /var/www/html/var/composer_home/auth.json",
-		)
+		copyAuth := "cp -va ~/.composer/auth.json /var/www/html/var/composer_home/auth.json"
+
+		// Mage-OS doesn't require repository credentials.
+		if c.MagentoType() == "mage-os" {
+			copyAuth = fmt.Sprintf("if [ -f ~/.composer/auth.json ]; then %s; fi", copyAuth)
+		}
+
+		err := c.RunCmdEnvExec("mkdir -p /var/www/html/var/composer_home/ && " + copyAuth)
 		if err != nil {
 			return fmt.Errorf("cannot copy auth.json: %w", err)
 		}
@@ -508,6 +566,15 @@ func (c *bootstrapper) buildMagento2InstallCommand() []string {
 			"--page-cache-redis-db=1",
 			"--page-cache-redis-port=6379",
 		)
+
+		if c.RedisPassword() != "" {
+			magentoCmdParams = append(
+				magentoCmdParams,
+				"--session-save-redis-password="+c.RedisPassword(),
+				"--cache-backend-redis-password="+c.RedisPassword(),
+				"--page-cache-redis-password="+c.RedisPassword(),
+			)
+		}
 	} else {
 		magentoCmdParams = append(magentoCmdParams, "--session-save=files")
 	}
@@ -523,8 +590,8 @@ func (c *bootstrapper) buildMagento2InstallCommand() []string {
 			magentoCmdParams,
 			"--amqp-host=rabbitmq",
 			"--amqp-port=5672",
-			"--amqp-user=guest",
-			"--amqp-password=guest",
+			"--amqp-user="+c.RabbitMQUser(),
+			"--amqp-password="+c.RabbitMQPassword(),
 		)
 
 		minimumVersionForRabbitMQWait := version.Must(version.NewVersion("2.4.0"))
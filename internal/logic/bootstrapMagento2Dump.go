@@ -0,0 +1,322 @@
+package logic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrBootstrapFileNotFound occurs when the dump or the media archive of the bootstrap process doesn't exist.
+var ErrBootstrapFileNotFound = func(path string) error {
+	return fmt.Errorf("file not found: %s", path)
+}
+
+// ErrBootstrapProjectNotFound occurs when the environment is bootstrapped from a dump without the project files.
+var ErrBootstrapProjectNotFound = fmt.Errorf(
+	"composer.json not found, the project files are required to bootstrap from a database dump",
+)
+
+// bootstrapMagento2FromDump bootstraps Magento 2 from the database dump of an existing store instead of a fresh
+// install. The dump is imported, the base URLs are rewritten to the domain of the environment, the production
+// integrations and caches are scrubbed, and the store is upgraded and compiled.
+func (c *bootstrapper) bootstrapMagento2FromDump() error {
+	dump, err := filepath.Abs(c.BootstrapDump())
+	if err != nil || !util.FileExists(dump) {
+		return ErrBootstrapFileNotFound(c.BootstrapDump())
+	}
+
+	media := c.BootstrapMedia()
+	if media != "" {
+		media, err = filepath.Abs(media)
+		if err != nil || !util.FileExists(media) {
+			return ErrBootstrapFileNotFound(c.BootstrapMedia())
+		}
+	}
+
+	if !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "composer.json")) {
+		return ErrBootstrapProjectNotFound
+	}
+
+	if !util.AskForConfirmation(
+		fmt.Sprintf("Would you like to bootstrap Magento from %s?", filepath.Base(dump)),
+	) {
+		return nil
+	}
+
+	log.Printf("Bootstrapping Magento from database dump %s...", dump)
+
+	if c.CryptKey() == "" && !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "app", "etc", "env.php")) {
+		log.Warnln("The crypt key of the store is not set (--crypt-key), the encrypted configuration values " +
+			"of the dump cannot be decrypted.")
+	}
+
+	err = c.prepare()
+	if err != nil {
+		return fmt.Errorf("error during preparation: %w", err)
+	}
+
+	err = c.composerPreInstall()
+	if err != nil {
+		return fmt.Errorf("error during composer configuration: %w", err)
+	}
+
+	err = c.composerInstall()
+	if err != nil {
+		return fmt.Errorf("error during composer install: %w", err)
+	}
+
+	err = c.composerPostInstall()
+	if err != nil {
+		return fmt.Errorf("error during composer post install configuration: %w", err)
+	}
+
+	adminPassword, err := c.installMagento2FromDump(dump, media)
+	if err != nil {
+		return fmt.Errorf("error during magento 2 installation: %w", err)
+	}
+
+	log.Printf("Base Url: https://%s", c.TraefikFullDomain())
+	log.Printf("Backend Url: https://%s/%s", c.TraefikFullDomain(), c.MagentoBackendFrontname())
+	log.Println("Admin user: localadmin")
+	log.Printf("Admin password: %s", adminPassword)
+	log.Println("...bootstrap process finished.")
+
+	return nil
+}
+
+func (c *bootstrapper) installMagento2FromDump(dump, media string) (string, error) {
+	log.Println("Installing Magento from database dump...")
+
+	err := c.installMagento2SetupConfigSet()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ImportDump(dump)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ImportMedia(media)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ScrubDatabase()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2SetupUpgrade()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ConfigureBasic()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ConfigureVarnish()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ConfigureSearch()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ConfigureTFA()
+	if err != nil {
+		return "", err
+	}
+
+	adminPassword, err := c.installMagento2ConfigureAdminUser()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ConfigureDeployMode()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2Compile()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2Reindex()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2ResetAdminURL()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.installMagento2FlushCache()
+	if err != nil {
+		return "", err
+	}
+
+	log.Println("...Magento installed successfully.")
+
+	return adminPassword, nil
+}
+
+// installMagento2SetupConfigSet writes app/etc/env.php with the connection settings of the environment's services.
+func (c *bootstrapper) installMagento2SetupConfigSet() error {
+	log.Println("Running Magento setup:config:set...")
+
+	// The search engine settings are stored in the database, they are not accepted by setup:config:set.
+	params := make([]string, 0)
+
+	for _, param := range c.buildMagento2InstallCommand() {
+		if !strings.HasPrefix(param, "--search-engine") && !strings.HasPrefix(param, "--elasticsearch-") {
+			params = append(params, param)
+		}
+	}
+
+	err := c.RunCmdEnvExec(
+		fmt.Sprintf("bin/magento setup:config:set --no-interaction %s", strings.Join(params, " ")),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot run bin/magento setup:config:set: %w", err)
+	}
+
+	log.Println("...Magento setup:config:set finished.")
+
+	return nil
+}
+
+// installMagento2ImportDump imports the database dump. A compressed dump is decompressed by db import.
+func (c *bootstrapper) installMagento2ImportDump(dump string) error {
+	log.Println("Importing database dump...")
+
+	f, err := os.Open(dump)
+	if err != nil {
+		return fmt.Errorf("cannot open database dump: %w", err)
+	}
+	defer f.Close()
+
+	err = c.runSelfInDir(c.Cwd(), f, "db", "import")
+	if err != nil {
+		return fmt.Errorf("cannot import database dump: %w", err)
+	}
+
+	log.Println("...database dump imported.")
+
+	return nil
+}
+
+// installMagento2ImportMedia extracts the media archive to pub/media.
+func (c *bootstrapper) installMagento2ImportMedia(media string) error {
+	if media == "" {
+		return nil
+	}
+
+	f, err := os.Open(media)
+	if err != nil {
+		return fmt.Errorf("cannot open media archive: %w", err)
+	}
+	defer f.Close()
+
+	step := c.newProgress().Step("Importing media files")
+	if stat, err := f.Stat(); err == nil {
+		step.Start(0, stat.Size())
+	}
+
+	return step.Finish(c.extractMagento2Media(util.ProgressReader(f, step)))
+}
+
+// extractMagento2Media extracts the tar archive to pub/media. The archive is decompressed locally, as tar in the
+// container may not support its compression (eg. zstd).
+func (c *bootstrapper) extractMagento2Media(archive io.Reader) error {
+	r, err := util.DecompressReader(archive)
+	if err != nil {
+		return fmt.Errorf("cannot decompress media archive: %w", err)
+	}
+	defer r.Close()
+
+	err = c.runSelfInDir(
+		c.Cwd(), r, "env", "exec", "-T", c.DefaultSyncedContainer(c.EnvType()), "bash", "-c",
+		"mkdir -p /var/www/html/pub/media && tar -xf - -C /var/www/html/pub/media",
+	)
+	if err != nil {
+		return fmt.Errorf("cannot extract media archive: %w", err)
+	}
+
+	return nil
+}
+
+// installMagento2ScrubDatabase removes the production specific settings from the imported database: the store
+// specific base URLs and the cookie domain, the active integrations and their tokens, the database caches and the
+// sessions.
+func (c *bootstrapper) installMagento2ScrubDatabase() error {
+	log.Println("Scrubbing production settings...")
+
+	err := c.runSelfInDir(c.Cwd(), strings.NewReader(magento2ScrubQueries(c.DBPrefix())), "db", "import")
+	if err != nil {
+		return fmt.Errorf("cannot scrub production settings: %w", err)
+	}
+
+	log.Println("...production settings scrubbed.")
+
+	return nil
+}
+
+func magento2ScrubQueries(prefix string) string {
+	return strings.Join([]string{
+		fmt.Sprintf(`DELETE FROM %score_config_data WHERE path LIKE 'web/%%secure/base\_%%\_url';`, prefix),
+		fmt.Sprintf(
+			`DELETE FROM %score_config_data WHERE path LIKE 'web/%%secure/base_url' AND scope <> 'default';`,
+			prefix,
+		),
+		fmt.Sprintf(`DELETE FROM %score_config_data WHERE path LIKE 'web/cookie/cookie\_%%';`, prefix),
+		fmt.Sprintf(`UPDATE %sintegration SET status = 0;`, prefix),
+		fmt.Sprintf(`DELETE FROM %soauth_token;`, prefix),
+		fmt.Sprintf(`DELETE FROM %scache_tag;`, prefix),
+		fmt.Sprintf(`DELETE FROM %scache;`, prefix),
+		fmt.Sprintf(`DELETE FROM %ssession;`, prefix),
+	}, "\n") + "\n"
+}
+
+func (c *bootstrapper) installMagento2SetupUpgrade() error {
+	log.Println("Running Magento setup:upgrade...")
+
+	err := c.RunCmdEnvExec(fmt.Sprintf("bin/magento setup:upgrade %s", c.magento2VerbosityFlag()))
+	if err != nil {
+		return fmt.Errorf("cannot run bin/magento setup:upgrade: %w", err)
+	}
+
+	log.Println("...Magento setup:upgrade finished.")
+
+	return nil
+}
+
+func (c *bootstrapper) installMagento2Compile() error {
+	// The production mode is compiled by deploy:mode:set.
+	if c.MagentoMode() == "production" {
+		return nil
+	}
+
+	log.Println("Running Magento setup:di:compile...")
+
+	err := c.RunCmdEnvExec("bin/magento setup:di:compile")
+	if err != nil {
+		return fmt.Errorf("cannot run bin/magento setup:di:compile: %w", err)
+	}
+
+	log.Println("...Magento setup:di:compile finished.")
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/sethvargo/go-password/password"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// bootstrapOro runs a full OroCommerce bootstrap process.
+func (c *bootstrapper) bootstrapOro() error {
+	oroVersion, err := c.OroVersion()
+	if err != nil {
+		return fmt.Errorf("cannot determine oro version: %w", err)
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf("Would you like to bootstrap OroCommerce %s?", oroVersion.Original())) {
+		return nil
+	}
+
+	log.Printf("Bootstrapping OroCommerce %s...", oroVersion.Original())
+
+	err = c.prepare()
+	if err != nil {
+		return fmt.Errorf("error during bootstrap preparation: %w", err)
+	}
+
+	err = c.composerPreInstall()
+	if err != nil {
+		return err
+	}
+
+	freshInstall, err := c.download()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerInstall()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerPostInstall()
+	if err != nil {
+		return err
+	}
+
+	adminPassword := ""
+	if freshInstall {
+		adminPassword, err = c.installOro()
+		if err != nil {
+			return err
+		}
+	}
+
+	// the websocket server and the message consumer wait for the installation, restarting them skips the remaining
+	// delay
+	err = c.RunCmdEnv([]string{"restart", "websocket", "consumer"})
+	if err != nil {
+		return fmt.Errorf("cannot restart websocket server and message consumer: %w", err)
+	}
+
+	log.Printf("Base Url: https://%s", c.TraefikFullDomain())
+	log.Printf("Backend Url: https://%s/admin", c.TraefikFullDomain())
+
+	if adminPassword != "" {
+		log.Println("Admin user: admin")
+		log.Printf("Admin password: %s", adminPassword)
+	}
+
+	log.Println("...bootstrap process finished.")
+
+	return nil
+}
+
+// installOro runs the Oro installer, which creates the database schema, the admin user and installs the assets.
+func (c *bootstrapper) installOro() (string, error) {
+	log.Println("Installing OroCommerce...")
+
+	adminPassword, err := password.Generate(16, 2, 0, false, false)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate admin password: %w", err)
+	}
+
+	sampleData := "n"
+	if c.FullBootstrap() {
+		sampleData = "y"
+	}
+
+	err = c.RunCmdEnvExec(
+		fmt.Sprintf(
+			"php -d memory_limit=-1 bin/console oro:install --no-interaction --timeout=3600 "+
+				"--application-url=https://%s/ --organization-name=Oro --language=en --formatting-code=en_US "+
+				"--user-name=admin --user-email=admin@example.com --user-firstname=Admin --user-lastname=Local "+
+				`--user-password="%s" --sample-data=%s`,
+			c.TraefikFullDomain(),
+			adminPassword,
+			sampleData,
+		),
+	)
+	if err != nil {
+		return "", fmt.Errorf("cannot install oro: %w", err)
+	}
+
+	log.Println("...OroCommerce installed.")
+
+	return adminPassword, nil
+}
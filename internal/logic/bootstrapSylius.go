@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+const (
+	// syliusAdminEmail and syliusAdminPassword are the credentials of the admin user which is created by the
+	// non-interactive sylius:install command.
+	syliusAdminEmail    = "sylius@example.com"
+	syliusAdminPassword = "sylius"
+)
+
+// bootstrapSylius runs a full Sylius bootstrap process.
+func (c *bootstrapper) bootstrapSylius() error {
+	syliusVersion, err := c.SyliusVersion()
+	if err != nil {
+		return fmt.Errorf("cannot determine sylius version: %w", err)
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf("Would you like to bootstrap Sylius %s?", syliusVersion.Original())) {
+		return nil
+	}
+
+	log.Printf("Bootstrapping Sylius %s...", syliusVersion.Original())
+
+	err = c.prepare()
+	if err != nil {
+		return fmt.Errorf("error during bootstrap preparation: %w", err)
+	}
+
+	err = c.composerPreInstall()
+	if err != nil {
+		return err
+	}
+
+	freshInstall, err := c.download()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerInstall()
+	if err != nil {
+		return err
+	}
+
+	err = c.composerPostInstall()
+	if err != nil {
+		return err
+	}
+
+	err = c.installSylius(freshInstall)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Base Url: https://%s", c.TraefikFullDomain())
+	log.Printf("Backend Url: https://%s/admin", c.TraefikFullDomain())
+	log.Printf("Admin user: %s", syliusAdminEmail)
+	log.Printf("Admin password: %s", syliusAdminPassword)
+	log.Println("...bootstrap process finished.")
+
+	return nil
+}
+
+func (c *bootstrapper) installSylius(freshInstall bool) error {
+	if freshInstall {
+		log.Println("Installing Sylius...")
+
+		err := c.RunCmdEnvExec("php bin/console sylius:install --no-interaction")
+		if err != nil {
+			return fmt.Errorf("cannot install sylius: %w", err)
+		}
+
+		log.Println("...Sylius installed.")
+
+		if c.FullBootstrap() {
+			log.Println("Loading Sylius sample data...")
+
+			err = c.RunCmdEnvExec("php bin/console sylius:fixtures:load default --no-interaction")
+			if err != nil {
+				return fmt.Errorf("cannot load sample data: %w", err)
+			}
+
+			log.Println("...sample data loaded.")
+		}
+	}
+
+	log.Println("Generating API keys...")
+
+	err := c.RunCmdEnvExec("php bin/console lexik:jwt:generate-keypair --skip-if-exists")
+	if err != nil {
+		return fmt.Errorf("cannot generate api keys: %w", err)
+	}
+
+	log.Println("...API keys generated.")
+
+	log.Println("Building assets...")
+
+	err = c.RunCmdEnvExec("yarn install && yarn build")
+	if err != nil {
+		return fmt.Errorf("cannot build assets: %w", err)
+	}
+
+	log.Println("...assets built.")
+
+	log.Println("Clearing cache...")
+
+	err = c.RunCmdEnvExec("php bin/console cache:clear")
+	if err != nil {
+		return fmt.Errorf("cannot clear cache: %w", err)
+	}
+
+	log.Println("...cache cleared.")
+
+	return nil
+}
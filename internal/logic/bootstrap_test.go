@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BootstrapTestSuite struct {
+	suite.Suite
+}
+
+func TestBootstrapTestSuite(t *testing.T) {
+	suite.Run(t, new(BootstrapTestSuite))
+}
+
+func (suite *BootstrapTestSuite) TestComposerVersionConstraint() {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "1.12", want: "~1.12.0"},
+		{version: "7", want: "~7.0.0"},
+		{version: "1.12.13", want: "1.12.13"},
+		{version: "v7.0.5", want: "v7.0.5"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, composerVersionConstraint(version.Must(version.NewVersion(tt.version))))
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrBuildImagesNoRegistry occurs when the registry of the images is not set.
+var ErrBuildImagesNoRegistry = fmt.Errorf(
+	"the registry is not set, use the --registry flag or the reward_build_images_registry setting",
+)
+
+// ErrBuildImagesLoadMultiPlatform occurs when the images of multiple platforms would be loaded to the docker host,
+// which is not supported by buildx.
+var ErrBuildImagesLoadMultiPlatform = fmt.Errorf(
+	"the images of multiple platforms cannot be loaded to the docker host, use --push or a single --platform",
+)
+
+// ErrBuildImagesEnvNotInitialized occurs when the image overrides should be updated but the .env file doesn't exist.
+var ErrBuildImagesEnvNotInitialized = fmt.Errorf(
+	"cannot update the image overrides, the environment is not initialized (run `reward env-init` first)",
+)
+
+// ErrBuildImagesNoDockerfile occurs when the build context of the image doesn't have a Dockerfile.
+var ErrBuildImagesNoDockerfile = func(image, dir string) error {
+	return fmt.Errorf("cannot build %s: %s doesn't contain a Dockerfile", image, dir)
+}
+
+// ErrBuildImagesUnknownVersion occurs when a requested version is not in the version matrix of the image.
+var ErrBuildImagesUnknownVersion = func(image, v string) error {
+	return fmt.Errorf("%s is not a supported version of %s", v, image)
+}
+
+// buildImageSpec describes how an image of the rewardenv images is built for the supported versions.
+type buildImageSpec struct {
+	// Service is the service of the environments which runs the image.
+	Service string
+	// VersionKey is the service in the version matrix and the build argument of the version (eg. PHP_VERSION).
+	VersionKey string
+}
+
+// buildImageSpecs are the images which are built for every supported version. The other images are built once with
+// the latest tag.
+var buildImageSpecs = map[string]buildImageSpec{
+	"php-fpm":       {Service: "php-fpm", VersionKey: "PHP_VERSION"},
+	"mariadb":       {Service: "db", VersionKey: "MARIADB_VERSION"},
+	"elasticsearch": {Service: "elasticsearch", VersionKey: "ELASTICSEARCH_VERSION"},
+	"opensearch":    {Service: "opensearch", VersionKey: "OPENSEARCH_VERSION"},
+	"rabbitmq":      {Service: "rabbitmq", VersionKey: "RABBITMQ_VERSION"},
+	"redis":         {Service: "redis", VersionKey: "REDIS_VERSION"},
+	"varnish":       {Service: "varnish", VersionKey: "VARNISH_VERSION"},
+}
+
+// buildImagesOptions are the options of the build-images command.
+type buildImagesOptions struct {
+	Context   string
+	Registry  string
+	Platforms string
+	Builder   string
+	Versions  []string
+	Push      bool
+}
+
+// imageBuild is a buildx invocation of an image version.
+type imageBuild struct {
+	Image   string
+	Service string
+	// Version is the version of the build argument, it's empty for the images without a version matrix.
+	Version string
+	Tag     string
+	Args    []string
+}
+
+// RunCmdBuildImages builds the images of a fork of the rewardenv images with buildx for every supported version and
+// platform, optionally pushes them to the registry and sets them as the image overrides of the environment.
+func (c *Client) RunCmdBuildImages(cmd *cmdpkg.Command, images []string) error {
+	var (
+		opts = buildImagesOptions{Builder: c.AppName() + "-builder"}
+		err  error
+	)
+
+	opts.Context, _ = cmd.Flags().GetString("context")
+	opts.Registry, _ = cmd.Flags().GetString("registry")
+	opts.Platforms, _ = cmd.Flags().GetString("platform")
+	opts.Versions, _ = cmd.Flags().GetStringSlice("versions")
+	opts.Push, _ = cmd.Flags().GetBool("push")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	updateOverrides, _ := cmd.Flags().GetBool("update-overrides")
+
+	if opts.Registry == "" {
+		opts.Registry = c.GetString(fmt.Sprintf("%s_build_images_registry", c.AppName()))
+	}
+
+	if opts.Platforms == "" {
+		opts.Platforms = c.GetString(fmt.Sprintf("%s_build_images_platforms", c.AppName()))
+	}
+
+	opts.Context, err = filepath.Abs(opts.Context)
+	if err != nil {
+		return fmt.Errorf("cannot resolve build context: %w", err)
+	}
+
+	builds, err := buildImagesPlan(images, opts, c.VersionMatrix().Services)
+	if err != nil {
+		return err
+	}
+
+	for _, build := range builds {
+		dir := filepath.Join(opts.Context, build.Image)
+		if !util.FileExists(filepath.Join(dir, "Dockerfile")) {
+			return ErrBuildImagesNoDockerfile(build.Image, dir)
+		}
+	}
+
+	if dryRun {
+		for _, build := range builds {
+			fmt.Println("docker " + strings.Join(build.Args, " "))
+		}
+
+		return nil
+	}
+
+	err = c.ensureImageBuilder(opts.Builder)
+	if err != nil {
+		return err
+	}
+
+	for _, build := range builds {
+		log.Printf("Building %s...", build.Tag)
+
+		buildx := cmdpkg.Cmnd("docker", build.Args...)
+		buildx.Stdout = os.Stdout
+		buildx.Stderr = os.Stderr
+
+		err = buildx.Run()
+		if err != nil {
+			return fmt.Errorf("cannot build %s: %w", build.Tag, err)
+		}
+
+		log.Printf("...%s built.", build.Tag)
+	}
+
+	if updateOverrides {
+		return c.updateImageOverrides(builds)
+	}
+
+	return nil
+}
+
+// ensureImageBuilder creates the buildx builder of the application if it doesn't exist. The default builder of the
+// docker driver cannot build the images of multiple platforms.
+func (c *Client) ensureImageBuilder(name string) error {
+	if cmdpkg.Cmnd("docker", "buildx", "inspect", name).Run() == nil {
+		return nil
+	}
+
+	log.Printf("Creating buildx builder %s...", name)
+
+	create := cmdpkg.Cmnd("docker", "buildx", "create", "--name", name, "--driver", "docker-container", "--bootstrap")
+	create.Stderr = os.Stderr
+
+	err := create.Run()
+	if err != nil {
+		return fmt.Errorf("cannot create buildx builder: %w", err)
+	}
+
+	return nil
+}
+
+// updateImageOverrides sets the built images of the versions used by the environment as the image overrides in the
+// .env file. The existing overrides of the other services are kept.
+func (c *Client) updateImageOverrides(builds []imageBuild) error {
+	if !c.EnvInitialized() {
+		return ErrBuildImagesEnvNotInitialized
+	}
+
+	overrides := c.ImageOverrides()
+	updated := false
+
+	for _, build := range builds {
+		spec, ok := buildImageSpecs[build.Image]
+		if ok && c.GetString(strings.ToLower(spec.VersionKey)) != build.Version {
+			continue
+		}
+
+		overrides[build.Service] = build.Tag
+		updated = true
+
+		log.Printf("Image override of %s: %s.", build.Service, build.Tag)
+	}
+
+	if !updated {
+		log.Println("None of the built versions are used by the environment, the image overrides are not changed.")
+
+		return nil
+	}
+
+	return setEnvFileValues(filepath.Join(c.Cwd(), ".env"), [][2]string{
+		{fmt.Sprintf("%s_IMAGE_OVERRIDES", strings.ToUpper(c.AppName())), formatImageOverrides(overrides)},
+	})
+}
+
+// buildImagesPlan returns the buildx invocations of the images. The images with a version matrix are built for the
+// supported versions (or the requested ones), the others are built once with the latest tag.
+func buildImagesPlan(images []string, opts buildImagesOptions, matrix map[string][]string) ([]imageBuild, error) {
+	if opts.Registry == "" {
+		return nil, ErrBuildImagesNoRegistry
+	}
+
+	if !opts.Push && strings.Contains(opts.Platforms, ",") {
+		return nil, ErrBuildImagesLoadMultiPlatform
+	}
+
+	var builds []imageBuild
+
+	for _, image := range images {
+		spec, ok := buildImageSpecs[image]
+		if !ok {
+			builds = append(builds, newImageBuild(image, image, "", "", opts))
+
+			continue
+		}
+
+		versions := matrix[spec.VersionKey]
+
+		if len(opts.Versions) > 0 {
+			for _, v := range opts.Versions {
+				if !util.ContainsString(versions, v) {
+					return nil, ErrBuildImagesUnknownVersion(image, v)
+				}
+			}
+
+			versions = opts.Versions
+		}
+
+		for _, v := range versions {
+			builds = append(builds, newImageBuild(image, spec.Service, spec.VersionKey, v, opts))
+		}
+	}
+
+	return builds, nil
+}
+
+func newImageBuild(image, service, versionKey, v string, opts buildImagesOptions) imageBuild {
+	tag := fmt.Sprintf("%s/%s:latest", strings.TrimSuffix(opts.Registry, "/"), image)
+	if v != "" {
+		tag = fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(opts.Registry, "/"), image, v)
+	}
+
+	args := []string{"buildx", "build", "--builder", opts.Builder, "--platform", opts.Platforms, "--tag", tag}
+	if v != "" {
+		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", versionKey, v))
+	}
+
+	if opts.Push {
+		args = append(args, "--push")
+	} else {
+		args = append(args, "--load")
+	}
+
+	args = append(args, filepath.Join(opts.Context, image))
+
+	return imageBuild{Image: image, Service: service, Version: v, Tag: tag, Args: args}
+}
+
+// formatImageOverrides returns the image overrides in the format of the .env file, sorted by service names.
+func formatImageOverrides(overrides map[string]string) string {
+	pairs := make([]string, 0, len(overrides))
+	for service, image := range overrides {
+		pairs = append(pairs, service+"="+image)
+	}
+
+	sort.Strings(pairs)
+
+	return fmt.Sprintf("%q", strings.Join(pairs, " "))
+}
@@ -0,0 +1,78 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BuildImagesTestSuite struct {
+	suite.Suite
+}
+
+func TestBuildImagesTestSuite(t *testing.T) {
+	suite.Run(t, new(BuildImagesTestSuite))
+}
+
+func (suite *BuildImagesTestSuite) TestBuildImagesPlan() {
+	var (
+		matrix = map[string][]string{"PHP_VERSION": {"8.1", "8.2"}}
+		opts   = buildImagesOptions{
+			Context:   "/src",
+			Registry:  "registry.example.com/acme/",
+			Platforms: "linux/amd64,linux/arm64",
+			Builder:   "reward-builder",
+			Push:      true,
+		}
+	)
+
+	builds, err := buildImagesPlan([]string{"php-fpm", "nginx"}, opts, matrix)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), builds, 3)
+
+	assert.Equal(suite.T(), imageBuild{
+		Image:   "php-fpm",
+		Service: "php-fpm",
+		Version: "8.1",
+		Tag:     "registry.example.com/acme/php-fpm:8.1",
+		Args: []string{
+			"buildx", "build", "--builder", "reward-builder", "--platform", "linux/amd64,linux/arm64",
+			"--tag", "registry.example.com/acme/php-fpm:8.1", "--build-arg", "PHP_VERSION=8.1", "--push",
+			"/src/php-fpm",
+		},
+	}, builds[0])
+	assert.Equal(suite.T(), "registry.example.com/acme/php-fpm:8.2", builds[1].Tag)
+	assert.Equal(suite.T(), "registry.example.com/acme/nginx:latest", builds[2].Tag)
+	assert.NotContains(suite.T(), builds[2].Args, "--build-arg")
+
+	opts.Versions = []string{"8.2"}
+
+	builds, err = buildImagesPlan([]string{"php-fpm"}, opts, matrix)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), builds, 1)
+	assert.Equal(suite.T(), "8.2", builds[0].Version)
+
+	opts.Versions = []string{"7.4"}
+
+	_, err = buildImagesPlan([]string{"php-fpm"}, opts, matrix)
+	assert.EqualError(suite.T(), err, "7.4 is not a supported version of php-fpm")
+
+	opts.Versions = nil
+	opts.Push = false
+
+	_, err = buildImagesPlan([]string{"php-fpm"}, opts, matrix)
+	assert.ErrorIs(suite.T(), err, ErrBuildImagesLoadMultiPlatform)
+
+	opts.Registry = ""
+
+	_, err = buildImagesPlan([]string{"php-fpm"}, opts, matrix)
+	assert.ErrorIs(suite.T(), err, ErrBuildImagesNoRegistry)
+}
+
+func (suite *BuildImagesTestSuite) TestFormatImageOverrides() {
+	assert.Equal(suite.T(), `"db=acme/mariadb:10.6 php-fpm=acme/php-fpm:8.1"`, formatImageOverrides(map[string]string{
+		"php-fpm": "acme/php-fpm:8.1",
+		"db":      "acme/mariadb:10.6",
+	}))
+}
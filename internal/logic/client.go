@@ -1,13 +1,46 @@
 package logic
 
-import "github.com/rewardenv/reward/internal/config"
+import (
+	"io"
+	"os"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/progress"
+	"github.com/rewardenv/reward/pkg/util"
+)
 
 type Client struct {
 	*config.Config
+
+	// Stdin is the input of the commands which read a dump (eg. db import), it's os.Stdin if it's nil.
+	Stdin io.Reader
+	// Stdout is the output of the results of the commands (eg. tables, JSON), it's os.Stdout if it's nil.
+	Stdout io.Writer
 }
 
 func New(c *config.Config) *Client {
 	return &Client{
-		c,
+		Config: c,
+	}
+}
+
+// stdin returns the input of the commands which read a dump.
+func (c *Client) stdin() io.Reader {
+	if c.Stdin != nil {
+		return c.Stdin
 	}
+
+	return os.Stdin
+}
+
+// newProgress returns the renderer of the progress of the command. The progress is logged as plain lines if stdout
+// is not a terminal or ANSI output is disabled. With --output json the progress is written to stderr, so stdout
+// contains only the JSON output.
+func (c *Client) newProgress() *progress.Renderer {
+	out := os.Stdout
+	if c.JSONOutput() {
+		out = os.Stderr
+	}
+
+	return progress.New(out, !c.NoANSI() && util.IsTerminal(out), !c.NoColor())
 }
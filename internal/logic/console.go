@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrConsoleNotAvailable occurs when the application of the environment type doesn't have a console.
+var ErrConsoleNotAvailable = func(envType string) error {
+	return fmt.Errorf("the console is not available for the %s environment type", envType)
+}
+
+// RunCmdConsole runs the console of the application (eg. bin/console of Symfony, typo3 or craft) with the arguments
+// in the php-fpm container, as the user of the shell command.
+func (c *Client) RunCmdConsole(args []string) error {
+	console := c.ConsoleCommand()
+	if len(console) == 0 {
+		return ErrConsoleNotAvailable(c.EnvType())
+	}
+
+	c.SetShellUser("php-fpm")
+
+	passedArgs := []string{"exec", "--user", c.ShellUser}
+	if !util.IsTerminal(os.Stdin) {
+		passedArgs = append(passedArgs, "-T")
+	}
+
+	passedArgs = append(passedArgs, "php-fpm")
+	passedArgs = append(passedArgs, console...)
+	passedArgs = append(passedArgs, args...)
+
+	return c.RunCmdEnvDockerCompose(passedArgs,
+		shell.WithCatchOutput(false),
+		shell.WithSuppressOutput(true),
+	)
+}
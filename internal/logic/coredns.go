@@ -0,0 +1,295 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cryptopkg "github.com/rewardenv/reward/internal/crypto"
+	"github.com/rewardenv/reward/internal/templates"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// corednsResolvedConfigFile is the systemd-resolved drop-in which routes the .test domains to CoreDNS.
+const corednsResolvedConfigFile = "/etc/systemd/resolved.conf.d/reward-coredns.conf"
+
+// ErrCorednsRequiresSystemdResolved occurs when the CoreDNS resolver is installed on Linux without systemd-resolved.
+var ErrCorednsRequiresSystemdResolved = fmt.Errorf("the coredns resolver requires systemd-resolved")
+
+// configureDNSServer passes the selected DNS server to the templates of the common services. If CoreDNS is selected,
+// its Corefile and the certificate of its DNS-over-TLS listener are generated as well.
+func (c *Client) configureDNSServer() error {
+	server, err := c.DNSServer()
+	if err != nil {
+		return err
+	}
+
+	c.Set(fmt.Sprintf("%s_dns_server", c.AppName()), server)
+
+	if server != "coredns" {
+		return nil
+	}
+
+	err = templates.New().SvcGenerateCorednsConfig(c.CorednsDir())
+	if err != nil {
+		return fmt.Errorf("cannot generate coredns config: %w", err)
+	}
+
+	return c.corednsCertificate()
+}
+
+// corednsTLSName returns the name which the clients verify in the certificate of the DNS-over-TLS listener.
+func (c *Client) corednsTLSName() string {
+	return "dns." + c.ServiceDomain()
+}
+
+// corednsCertificate creates the certificate of the DNS-over-TLS listener of CoreDNS signed by the CA of the
+// application, so the resolvers which enforce DNS-over-TLS trust it.
+func (c *Client) corednsCertificate() error {
+	dir := filepath.Join(c.CorednsDir(), "tls")
+	if util.FileExists(filepath.Join(dir, "coredns.crt.pem")) {
+		return nil
+	}
+
+	crypto := cryptopkg.New(c.Config)
+
+	caCertFilePath, err := crypto.CACertificateFilePath(c.SSLCADir())
+	if err != nil {
+		return fmt.Errorf("cannot get ca cert file path: %w", err)
+	}
+
+	caPrivKeyFilePath, err := crypto.CAPrivKeyFilePath(c.SSLCADir())
+	if err != nil {
+		return fmt.Errorf("cannot get ca priv key file path: %w", err)
+	}
+
+	err = crypto.CreatePrivateKeyAndCertificate(
+		dir, "coredns", []string{c.corednsTLSName()}, caCertFilePath, caPrivKeyFilePath,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create coredns certificate: %w", err)
+	}
+
+	// CoreDNS runs as an unprivileged user in the container.
+	err = os.Chmod(filepath.Join(dir, "coredns.key.pem"), 0o644) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("cannot chmod coredns private key: %w", err)
+	}
+
+	return nil
+}
+
+// installDNSServer returns the DNS server which resolves the .test domains. If a DNS server is passed to the --dns
+// flag, it's stored in the config file, and the resolver of the previous DNS server is removed.
+func (c *installer) installDNSServer() (string, error) {
+	current, err := c.DNSServer()
+	if err != nil {
+		return "", err
+	}
+
+	selected := c.installDNSServerFlag()
+	if selected == "" || selected == current {
+		return current, nil
+	}
+
+	key := fmt.Sprintf("%s_dns_server", c.AppName())
+	c.Set(key, selected)
+
+	selected, err = c.DNSServer()
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("Switching DNS server from %s to %s...", current, selected)
+
+	err = setConfigFileValue(c.GetString(c.AppName()+"_config_file"), key, selected)
+	if err != nil {
+		return "", err
+	}
+
+	if current == "coredns" {
+		err = c.corednsUninstallDNSResolver()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	log.Printf("...DNS server switched. Run `%s svc up` to start %s.", c.AppName(), selected)
+
+	return selected, nil
+}
+
+// corednsInstallDNSResolver routes the .test domains to CoreDNS. Unlike the dnsmasq resolver, only the queries of
+// the .test domains are sent to CoreDNS, the system resolver (and its DNS-over-TLS settings) is kept for every other
+// domain.
+func (c *installer) corednsInstallDNSResolver() error {
+	switch util.OSDistro() {
+	case "windows":
+		return c.windowsCorednsInstallDNSResolver()
+	case "darwin":
+		return c.darwinInstallDNSResolver()
+	default:
+		return c.linuxCorednsInstallDNSResolver()
+	}
+}
+
+// corednsUninstallDNSResolver removes the routing of the .test domains to CoreDNS.
+func (c *installer) corednsUninstallDNSResolver() error {
+	log.Println("Removing CoreDNS resolver configuration...")
+
+	var err error
+
+	switch util.OSDistro() {
+	case "windows":
+		err = c.windowsCorednsUninstallDNSResolver()
+	case "darwin":
+		err = runLoggedCommand(exec.Command("sudo", "rm", "-f", filepath.Join("/", "etc", "resolver", "test")))
+	default:
+		err = runLoggedCommand(exec.Command("sudo", "rm", "-f", corednsResolvedConfigFile))
+		if err == nil {
+			err = runLoggedCommand(exec.Command("sudo", "systemctl", "restart", "systemd-resolved.service"))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot remove coredns resolver configuration: %w", err)
+	}
+
+	log.Println("...CoreDNS resolver configuration removed.")
+
+	return nil
+}
+
+// linuxCorednsInstallDNSResolver adds a systemd-resolved drop-in which routes the .test domains to CoreDNS. If
+// systemd-resolved uses DNS-over-TLS, it connects to the DNS-over-TLS listener of CoreDNS and verifies its name.
+func (c *installer) linuxCorednsInstallDNSResolver() error {
+	if c.Shell.ExitCodeOfCommand("systemctl status systemd-resolved | grep 'active (running)'") != 0 {
+		return ErrCorednsRequiresSystemdResolved
+	}
+
+	err := runLoggedCommand(exec.Command("sudo", "install", "-vdm", "0755", filepath.Dir(corednsResolvedConfigFile)))
+	if err != nil {
+		return fmt.Errorf("cannot create systemd-resolved config directory: %w", err)
+	}
+
+	echoCmd := exec.Command("echo", "-e", c.corednsResolvedConfig())     //nolint:gosec
+	sudoTeeCmd := exec.Command("sudo", "tee", corednsResolvedConfigFile) //nolint:gosec
+
+	stdout, stderr, err := c.Shell.Pipeline(echoCmd, sudoTeeCmd)
+	log.Debugln(string(stdout), string(stderr))
+
+	if err != nil {
+		return fmt.Errorf("cannot write systemd-resolved config file: %w", err)
+	}
+
+	return runLoggedCommand(exec.Command("sudo", "systemctl", "restart", "systemd-resolved.service"))
+}
+
+// corednsResolvedConfig returns the systemd-resolved configuration of CoreDNS. The name after # is used as the server
+// name of DNS-over-TLS, and systemd-resolved connects to port 853 if DNS-over-TLS is enabled and to port 53 otherwise.
+func (c *installer) corednsResolvedConfig() string {
+	return fmt.Sprintf(
+		"[Resolve]\nDNS=127.0.0.1#%s\nDomains=%s\n", c.corednsTLSName(), corednsRoutingDomains(c.ServiceDomain()),
+	)
+}
+
+// windowsCorednsInstallDNSResolver adds a Name Resolution Policy Table rule which sends the queries of the .test
+// domains to CoreDNS. The rules take precedence over the DNS-over-HTTPS settings of the network adapters.
+func (c *installer) windowsCorednsInstallDNSResolver() error {
+	err := c.windowsCorednsUninstallDNSResolver()
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range strings.Fields(corednsRoutingDomains(c.ServiceDomain())) {
+		err = runLoggedCommand(exec.Command( //nolint:gosec
+			"powershell", "-NoProfile", "-Command",
+			fmt.Sprintf(
+				"Add-DnsClientNrptRule -Namespace '.%s' -NameServers '127.0.0.1' -Comment '%s'",
+				strings.TrimPrefix(domain, "~"), c.AppName(),
+			),
+		))
+		if err != nil {
+			return fmt.Errorf("cannot add name resolution policy rule: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// windowsCorednsUninstallDNSResolver removes the Name Resolution Policy Table rules of the application.
+func (c *installer) windowsCorednsUninstallDNSResolver() error {
+	return runLoggedCommand(exec.Command( //nolint:gosec
+		"powershell", "-NoProfile", "-Command",
+		fmt.Sprintf(
+			"Get-DnsClientNrptRule | Where-Object Comment -eq '%s' | Remove-DnsClientNrptRule -Force", c.AppName(),
+		),
+	))
+}
+
+// corednsRoutingDomains returns the routing domains of systemd-resolved which are resolved by CoreDNS.
+func corednsRoutingDomains(serviceDomain string) string {
+	domains := "~test"
+	if !strings.HasSuffix(serviceDomain, ".test") {
+		domains += " ~" + serviceDomain
+	}
+
+	return domains
+}
+
+// setConfigFileValue sets the value of the key in the yaml config file. The commented out setting is replaced if
+// the key is not set yet, otherwise the key is appended to the file.
+func setConfigFileValue(file, key, value string) error {
+	content, err := util.FS.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read config file %s: %w", file, err)
+	}
+
+	var (
+		setting = fmt.Sprintf("%s: %q", key, value)
+		re      = regexp.MustCompile(fmt.Sprintf(`(?m)^%s:.*$`, regexp.QuoteMeta(key)))
+		comment = regexp.MustCompile(fmt.Sprintf(`(?m)^#%s:.*$`, regexp.QuoteMeta(key)))
+		updated string
+	)
+
+	switch {
+	case re.Match(content):
+		updated = re.ReplaceAllLiteralString(string(content), setting)
+	case comment.Match(content):
+		loc := comment.FindIndex(content)
+		updated = string(content[:loc[0]]) + setting + string(content[loc[1]:])
+	default:
+		updated = string(content)
+		if updated != "" && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+
+		updated += setting + "\n"
+	}
+
+	err = util.CreateDirAndWriteToFile([]byte(updated), file)
+	if err != nil {
+		return fmt.Errorf("cannot write config file %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// runLoggedCommand runs the command and logs its output.
+func runLoggedCommand(cmd *exec.Cmd) error {
+	log.Printf("Running command: %s", cmd)
+
+	out, err := cmd.CombinedOutput()
+	log.Debugf("output: %s", string(out))
+
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type CorednsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CorednsTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestCorednsTestSuite(t *testing.T) {
+	suite.Run(t, new(CorednsTestSuite))
+}
+
+func (suite *CorednsTestSuite) TestSetConfigFileValue() {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "missing file",
+			want: "reward_dns_server: \"coredns\"\n",
+		},
+		{
+			name:    "append",
+			content: "---\nlog_level: info",
+			want:    "---\nlog_level: info\nreward_dns_server: \"coredns\"\n",
+		},
+		{
+			name:    "replace commented out setting",
+			content: "---\n#reward_dns_server: dnsmasq\ndebug: false\n",
+			want:    "---\nreward_dns_server: \"coredns\"\ndebug: false\n",
+		},
+		{
+			name:    "replace setting",
+			content: "---\n#reward_dns_server: dnsmasq\nreward_dns_server: dnsmasq\n",
+			want:    "---\n#reward_dns_server: dnsmasq\nreward_dns_server: \"coredns\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			suite.SetupTest()
+
+			file := "/home/user/.reward.yml"
+			if tt.content != "" {
+				assert.NoError(t, util.FS.WriteFile(file, []byte(tt.content), 0o640))
+			}
+
+			assert.NoError(t, setConfigFileValue(file, "reward_dns_server", "coredns"))
+
+			got, err := util.FS.ReadFile(file)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func (suite *CorednsTestSuite) TestCorednsResolvedConfig() {
+	tests := []struct {
+		name          string
+		serviceDomain string
+		want          string
+	}{
+		{
+			name:          "test domain",
+			serviceDomain: "reward.test",
+			want:          "[Resolve]\nDNS=127.0.0.1#dns.reward.test\nDomains=~test\n",
+		},
+		{
+			name:          "custom domain",
+			serviceDomain: "reward.local",
+			want:          "[Resolve]\nDNS=127.0.0.1#dns.reward.local\nDomains=~test ~reward.local\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newInstaller(newTestClient(map[string]interface{}{"reward_service_domain": tt.serviceDomain}))
+
+			assert.Equal(t, tt.want, c.corednsResolvedConfig())
+		})
+	}
+}
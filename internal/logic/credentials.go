@@ -0,0 +1,145 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sethvargo/go-password/password"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// containerUIUsernames are the admin users of the container UIs.
+var containerUIUsernames = map[string]string{
+	"portainer": "admin",
+	"yacht":     "admin@yacht.local",
+}
+
+// serviceCredentials are the generated credentials of a common service.
+type serviceCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Hash is the bcrypt hash of the password for the services which are configured with the hash.
+	Hash string `json:"hash"`
+}
+
+// serviceCredentials returns the credentials stored in the file. If the file doesn't exist, the credentials are
+// generated for the username and stored.
+func (c *Client) serviceCredentials(file, username string) (*serviceCredentials, error) {
+	content, err := util.FS.ReadFile(file)
+	if err == nil {
+		credentials := new(serviceCredentials)
+
+		err = json.Unmarshal(content, credentials)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse credentials %s: %w", file, err)
+		}
+
+		return credentials, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot read credentials %s: %w", file, err)
+	}
+
+	log.Debugf("Generating credentials %s...", file)
+
+	pass, err := password.Generate(20, 4, 0, false, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate password: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash password: %w", err)
+	}
+
+	credentials := &serviceCredentials{Username: username, Password: pass, Hash: string(hash)}
+
+	content, err = json.MarshalIndent(credentials, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal credentials: %w", err)
+	}
+
+	err = util.CreateDirAndWriteToFile(content, file, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write credentials %s: %w", file, err)
+	}
+
+	return credentials, nil
+}
+
+// containerUICredentials returns the credentials of the container UI.
+func (c *Client) containerUICredentials(ui string) (*serviceCredentials, error) {
+	return c.serviceCredentials(c.ContainerUICredentialsFile(ui), containerUIUsernames[ui])
+}
+
+// configureContainerUI passes the selected container UI and its credentials to the templates of the common services.
+func (c *Client) configureContainerUI() error {
+	ui, err := c.ContainerUI()
+	if err != nil {
+		return err
+	}
+
+	c.Set(fmt.Sprintf("%s_container_ui", c.AppName()), ui)
+
+	if ui == "none" {
+		return nil
+	}
+
+	credentials, err := c.containerUICredentials(ui)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fmt.Sprintf("%s_container_ui_username", c.AppName()), credentials.Username)
+	c.Set(fmt.Sprintf("%s_container_ui_password", c.AppName()), credentials.Password)
+	c.Set(fmt.Sprintf("%s_container_ui_password_hash", c.AppName()), credentials.Hash)
+
+	return nil
+}
+
+// RunCmdSvcCredentials prints the URLs and the credentials of the traefik dashboard and the container UI.
+func (c *Client) RunCmdSvcCredentials() error {
+	credentials, err := c.traefikCredentials()
+	if err != nil {
+		return err
+	}
+
+	if c.TraefikDashboard() {
+		fmt.Printf("Traefik dashboard: https://traefik.%s/dashboard/\n", c.ServiceDomain())
+	}
+
+	fmt.Printf("Traefik API: https://traefik.%s/api/\n", c.ServiceDomain())
+	fmt.Printf("Username: %s\n", credentials.Username)
+	fmt.Printf("Password: %s\n", credentials.Password)
+
+	ui, err := c.ContainerUI()
+	if err != nil {
+		return err
+	}
+
+	if ui == "none" {
+		return nil
+	}
+
+	credentials, err = c.containerUICredentials(ui)
+	if err != nil {
+		return err
+	}
+
+	subdomain := c.GetString(fmt.Sprintf("%s_container_ui_subdomain", c.AppName()))
+	if subdomain == "" {
+		subdomain = ui
+	}
+
+	fmt.Println()
+	fmt.Printf("Container UI (%s): https://%s.%s/\n", ui, subdomain, c.ServiceDomain())
+	fmt.Printf("Username: %s\n", credentials.Username)
+	fmt.Printf("Password: %s\n", credentials.Password)
+
+	return nil
+}
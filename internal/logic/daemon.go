@@ -0,0 +1,400 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+// daemonVersionsTTL is the time the detected versions of docker and docker-compose are cached by the daemon for, so
+// an upgrade is picked up without restarting it.
+const daemonVersionsTTL = 5 * time.Minute
+
+// ErrDaemonRunning occurs when the daemon is started while another one is running.
+var ErrDaemonRunning = func(address string) error {
+	return fmt.Errorf("daemon is already running on %s", address)
+}
+
+// ErrDaemonAddressNotLocal occurs when the daemon would listen on an address which is reachable from other hosts.
+var ErrDaemonAddressNotLocal = func(address string) error {
+	return fmt.Errorf("daemon address %s is not a loopback address, eg. use 127.0.0.1:7474", address)
+}
+
+// daemonEnv is an environment in the responses of the daemon.
+type daemonEnv struct {
+	Name       string            `json:"name"`
+	Dir        string            `json:"dir"`
+	Containers []daemonContainer `json:"containers"`
+}
+
+// daemonContainer is a container of an environment in the responses of the daemon.
+type daemonContainer struct {
+	Service string `json:"service"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Health  string `json:"health,omitempty"`
+}
+
+// daemon serves the localhost API of the application.
+type daemon struct {
+	*Client
+
+	token string
+
+	mu         sync.Mutex
+	versions   map[string]string
+	versionsAt time.Time
+}
+
+// RunCmdDaemon starts the daemon, which serves a localhost API for the IDE plugins (list the environments, start and
+// stop them, stream their logs). The commands use the running daemon to skip the detection of the docker and
+// docker-compose versions. The address and the token of the API are saved to the home directory of the application.
+func (c *Client) RunCmdDaemon() error {
+	if state := c.DaemonState(); state != nil {
+		if err := c.DaemonRequest(state, "/v1/preflight", &struct{}{}); err == nil {
+			return ErrDaemonRunning(state.Address)
+		}
+	}
+
+	address := c.DaemonAddress()
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid daemon address: %w", err)
+	}
+
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return ErrDaemonAddressNotLocal(address)
+	}
+
+	token, err := newDaemonToken()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %s: %w", address, err)
+	}
+
+	err = c.WriteDaemonState(&config.DaemonState{
+		Address: listener.Addr().String(),
+		Token:   token,
+		PID:     os.Getpid(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the state file is removed on exit by the cleanup of the application
+	c.TmpFiles.PushBack(c.DaemonStateFile())
+
+	log.Printf("Daemon listening on %s, the token is saved to %s.", listener.Addr(), c.DaemonStateFile())
+
+	server := &http.Server{
+		Handler:           newDaemon(c, token).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return server.Serve(listener) //nolint:wrapcheck
+}
+
+func newDaemon(c *Client, token string) *daemon {
+	return &daemon{Client: c, token: token}
+}
+
+func newDaemonToken() (string, error) {
+	b := make([]byte, 32)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate daemon token: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// handler returns the routes of the API:
+//
+//	GET  /v1/preflight          the versions of docker and docker-compose
+//	GET  /v1/summary            the state, health and sync health of the environments at a glance
+//	GET  /v1/events             streams the changes of the summary as server-sent events
+//	GET  /v1/envs               the environments and their containers
+//	GET  /v1/envs/<name>        the environment and its containers
+//	POST /v1/envs/<name>/start  starts the environment (env up -d)
+//	POST /v1/envs/<name>/stop   stops the environment (env stop)
+//	GET  /v1/envs/<name>/logs   streams the logs of the environment (?follow=true&tail=100)
+func (d *daemon) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/preflight", d.handlePreflight)
+	mux.HandleFunc("/v1/summary", d.handleSummary)
+	mux.HandleFunc("/v1/events", d.handleEvents)
+	mux.HandleFunc("/v1/envs", d.handleEnvs)
+	mux.HandleFunc("/v1/envs/", d.handleEnv)
+
+	return d.authorize(mux)
+}
+
+// authorize refuses the requests without the token of the daemon, so other local users and the websites opened in
+// the browser cannot control the environments.
+func (d *daemon) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(d.token)) != 1 {
+			daemonError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d *daemon) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		daemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.versions == nil || time.Since(d.versionsAt) > daemonVersionsTTL {
+		versions, err := d.ComponentVersions()
+		if err != nil {
+			daemonError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		d.versions, d.versionsAt = versions, time.Now()
+	}
+
+	daemonJSON(w, map[string]interface{}{"versions": d.versions})
+}
+
+func (d *daemon) handleEnvs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		daemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	envs, err := d.daemonEnvs()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	daemonJSON(w, envs)
+}
+
+func (d *daemon) handleEnv(w http.ResponseWriter, r *http.Request) {
+	name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/envs/"), "/")
+
+	envs, err := d.daemonEnvs()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	i := sort.Search(len(envs), func(i int) bool { return envs[i].Name >= name })
+	if i == len(envs) || envs[i].Name != name {
+		daemonError(w, http.StatusNotFound, fmt.Errorf("environment %s not found", name))
+
+		return
+	}
+
+	env := envs[i]
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		daemonJSON(w, env)
+	case action == "start" && r.Method == http.MethodPost:
+		d.runEnvCommand(w, r, env, "env", "up", "-d")
+	case action == "stop" && r.Method == http.MethodPost:
+		d.runEnvCommand(w, r, env, "env", "stop")
+	case action == "logs" && r.Method == http.MethodGet:
+		d.streamEnvLogs(w, r, env)
+	default:
+		daemonError(w, http.StatusNotFound, fmt.Errorf("%s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+// daemonEnvs returns the environments sorted by their names. The common services are not part of the list.
+func (d *daemon) daemonEnvs() ([]daemonEnv, error) {
+	containers, err := d.Docker.ContainerDetailsByLabel(d.LabelEnvName())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get containers: %w", err)
+	}
+
+	return groupDaemonEnvs(containers, d.AppName()), nil
+}
+
+// groupDaemonEnvs groups the containers by their environments, the containers of the common services are skipped.
+func groupDaemonEnvs(containers []*docker.Container, appName string) []daemonEnv {
+	byName := make(map[string]*daemonEnv)
+
+	for _, container := range containers {
+		if container.Project == "" || container.Project == appName {
+			continue
+		}
+
+		env, ok := byName[container.Project]
+		if !ok {
+			env = &daemonEnv{Name: container.Project, Dir: container.WorkingDir}
+			byName[container.Project] = env
+		}
+
+		env.Containers = append(env.Containers, daemonContainer{
+			Service: container.Service,
+			Name:    container.Name,
+			State:   container.State,
+			Health:  container.Health,
+		})
+	}
+
+	envs := make([]daemonEnv, 0, len(byName))
+	for _, env := range byName {
+		sort.Slice(env.Containers, func(i, j int) bool {
+			return env.Containers[i].Service < env.Containers[j].Service
+		})
+
+		envs = append(envs, *env)
+	}
+
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+
+	return envs
+}
+
+// runEnvCommand runs the application in the directory of the environment and responds with its output.
+func (d *daemon) runEnvCommand(w http.ResponseWriter, r *http.Request, env daemonEnv, args ...string) {
+	command, err := d.envCommand(r, env, args...)
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	out, err := command.CombinedOutput()
+
+	resp := map[string]string{"output": string(out)}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		resp["error"] = err.Error()
+		_ = json.NewEncoder(w).Encode(resp)
+
+		return
+	}
+
+	daemonJSON(w, resp)
+}
+
+// streamEnvLogs streams the logs of the containers of the environment until the command exits or the client
+// disconnects.
+func (d *daemon) streamEnvLogs(w http.ResponseWriter, r *http.Request, env daemonEnv) {
+	args := []string{"env", "logs", "--no-color"}
+
+	if follow, _ := strconv.ParseBool(r.URL.Query().Get("follow")); follow {
+		args = append(args, "--follow")
+	}
+
+	if tail := r.URL.Query().Get("tail"); tail != "" {
+		args = append(args, "--tail", tail)
+	}
+
+	command, err := d.envCommand(r, env, args...)
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	out := &flushWriter{w: w}
+	command.Stdout = out
+	command.Stderr = out
+
+	err = command.Run()
+	if err != nil && r.Context().Err() == nil {
+		log.Warnf("Cannot stream the logs of %s: %s", env.Name, err)
+	}
+}
+
+// envCommand returns the command which runs the application in the directory of the environment. It's killed when
+// the client disconnects.
+func (d *daemon) envCommand(r *http.Request, env daemonEnv, args ...string) (*exec.Cmd, error) {
+	if env.Dir == "" {
+		return nil, fmt.Errorf("directory of environment %s is unknown", env.Name)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	command := exec.CommandContext(r.Context(), self, args...)
+	command.Dir = env.Dir
+
+	return command, nil
+}
+
+// flushWriter flushes the response after every write, so the logs are streamed to the client.
+type flushWriter struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+func daemonJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Warnf("Cannot write the response of the daemon: %s", err)
+	}
+}
+
+func daemonError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,231 @@
+package logic
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type DaemonTestSuite struct {
+	suite.Suite
+}
+
+func TestDaemonTestSuite(t *testing.T) {
+	suite.Run(t, new(DaemonTestSuite))
+}
+
+func newTestDaemon() *daemon {
+	container := func(id, project, service, state string) types.Container {
+		return types.Container{
+			ID:    id,
+			Names: []string{"/" + project + "-" + service + "-1"},
+			State: state,
+			Labels: map[string]string{
+				"dev.reward.env-name":                    project,
+				"com.docker.compose.project":             project,
+				"com.docker.compose.service":             service,
+				"com.docker.compose.project.working_dir": "/home/user/" + project,
+			},
+		}
+	}
+
+	c := newTestClient(nil)
+	c.Docker = docker.NewClientWithAPI(&docker.Fake{
+		Containers: []types.Container{
+			container("1", "shop", "php-fpm", "running"),
+			container("2", "shop", "db", "running"),
+			container("3", "blog", "php-fpm", "exited"),
+			container("4", "reward", "traefik", "running"),
+		},
+	})
+
+	return newDaemon(c, "secret")
+}
+
+func (suite *DaemonTestSuite) TestHandler() {
+	handler := newTestDaemon().handler()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+		wantEnvs   []string
+	}{
+		{name: "without token", method: http.MethodGet, path: "/v1/envs", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodGet, path: "/v1/envs", token: "other", wantStatus: 401},
+		{
+			name:       "list envs",
+			method:     http.MethodGet,
+			path:       "/v1/envs",
+			token:      "secret",
+			wantStatus: http.StatusOK,
+			wantEnvs:   []string{"blog", "shop"},
+		},
+		{name: "env status", method: http.MethodGet, path: "/v1/envs/shop", token: "secret", wantStatus: 200},
+		{name: "unknown env", method: http.MethodGet, path: "/v1/envs/other", token: "secret", wantStatus: 404},
+		{name: "common services", method: http.MethodGet, path: "/v1/envs/reward", token: "secret", wantStatus: 404},
+		{name: "unknown action", method: http.MethodGet, path: "/v1/envs/shop/start", token: "secret", wantStatus: 404},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			if tt.wantEnvs != nil {
+				var envs []daemonEnv
+
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&envs))
+
+				names := make([]string, 0, len(envs))
+				for _, env := range envs {
+					names = append(names, env.Name)
+				}
+
+				assert.Equal(t, tt.wantEnvs, names)
+			}
+		})
+	}
+}
+
+func (suite *DaemonTestSuite) TestGroupDaemonEnvs() {
+	got := groupDaemonEnvs([]*docker.Container{
+		{Name: "shop-php-fpm-1", Project: "shop", Service: "php-fpm", State: "running", WorkingDir: "/home/user/shop"},
+		{Name: "reward-traefik-1", Project: "reward", Service: "traefik", State: "running"},
+		{Name: "shop-db-1", Project: "shop", Service: "db", State: "running", Health: "healthy"},
+	}, "reward")
+
+	assert.Equal(suite.T(), []daemonEnv{
+		{
+			Name: "shop",
+			Dir:  "/home/user/shop",
+			Containers: []daemonContainer{
+				{Service: "db", Name: "shop-db-1", State: "running", Health: "healthy"},
+				{Service: "php-fpm", Name: "shop-php-fpm-1", State: "running"},
+			},
+		},
+	}, got)
+}
+
+func (suite *DaemonTestSuite) TestParseSyncHealth() {
+	out := []byte(`[
+		{"identifier": "1", "labels": {"reward-sync": "shop"}, "status": "watching"},
+		{"identifier": "2", "labels": {"reward-sync": "shop"}, "status": "watching", "conflicts": [{"root": "a"}]},
+		{"identifier": "3", "labels": {"reward-sync": "blog"}, "status": "staging-beta"},
+		{"identifier": "4", "labels": {"reward-sync": "docs"}, "status": "watching", "paused": true},
+		{"identifier": "5", "labels": {"reward-sync": "api"}, "status": "watching", "lastError": "permission denied"},
+		{"identifier": "6", "labels": {"other": "x"}, "status": "watching"}
+	]`)
+
+	got, err := parseSyncHealth(out, "reward-sync")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]string{
+		"shop": "conflicts",
+		"blog": "syncing",
+		"docs": "paused",
+		"api":  "error",
+	}, got)
+}
+
+func (suite *DaemonTestSuite) TestSummarizeDaemonEnv() {
+	tests := []struct {
+		name       string
+		containers []daemonContainer
+		want       daemonEnvSummary
+	}{
+		{
+			name: "running",
+			containers: []daemonContainer{
+				{Service: "db", State: "running", Health: "healthy"},
+				{Service: "php-fpm", State: "running"},
+			},
+			want: daemonEnvSummary{Name: "shop", State: "running", Health: "healthy"},
+		},
+		{
+			name: "partial",
+			containers: []daemonContainer{
+				{Service: "db", State: "running", Health: "starting"},
+				{Service: "php-fpm", State: "exited"},
+			},
+			want: daemonEnvSummary{Name: "shop", State: "partial", Health: "starting"},
+		},
+		{
+			name: "unhealthy",
+			containers: []daemonContainer{
+				{Service: "db", State: "running", Health: "unhealthy"},
+				{Service: "redis", State: "running", Health: "starting"},
+			},
+			want: daemonEnvSummary{Name: "shop", State: "running", Health: "unhealthy"},
+		},
+		{
+			name:       "stopped",
+			containers: []daemonContainer{{Service: "db", State: "exited"}},
+			want:       daemonEnvSummary{Name: "shop", State: "stopped"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, summarizeDaemonEnv(daemonEnv{Name: "shop", Containers: tt.containers}, ""))
+		})
+	}
+}
+
+func (suite *DaemonTestSuite) TestDiffDaemonSummaries() {
+	prev := []daemonEnvSummary{
+		{Name: "blog", State: "running", Sync: "ok"},
+		{Name: "docs", State: "running"},
+		{Name: "shop", State: "running", Health: "healthy", Sync: "ok"},
+	}
+	next := []daemonEnvSummary{
+		{Name: "api", State: "running", Sync: "syncing"},
+		{Name: "blog", State: "running", Sync: "conflicts"},
+		{Name: "shop", State: "partial", Health: "healthy", Sync: "ok"},
+	}
+
+	assert.Equal(suite.T(), []daemonEvent{
+		{Type: "env", Data: next[0]},
+		{Type: "sync", Data: next[0]},
+		{Type: "sync", Data: next[1]},
+		{Type: "env", Data: next[2]},
+		{Type: "env", Data: daemonEnvSummary{Name: "docs", State: "removed"}},
+	}, diffDaemonSummaries(prev, next))
+
+	assert.Empty(suite.T(), diffDaemonSummaries(next, next))
+}
+
+func (suite *DaemonTestSuite) TestDaemonServiceFile() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/user/.reward"})
+
+	file, content, err := c.daemonServiceFile("darwin", "/usr/local/bin/reward", "/usr/local/bin:/usr/bin")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasSuffix(file, "Library/LaunchAgents/dev.reward.daemon.plist"))
+	assert.Contains(suite.T(), content, "<string>dev.reward.daemon</string>")
+	assert.Contains(suite.T(), content, "<string>/usr/local/bin/reward</string>\n    <string>daemon</string>")
+
+	file, content, err = c.daemonServiceFile("linux", "/usr/local/bin/reward", "/usr/local/bin:/usr/bin")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasSuffix(file, "systemd/user/reward-daemon.service"))
+	assert.Contains(suite.T(), content, `ExecStart="/usr/local/bin/reward" daemon`)
+	assert.Contains(suite.T(), content, `Environment="PATH=/usr/local/bin:/usr/bin"`)
+
+	_, _, err = c.daemonServiceFile("windows", "reward.exe", "")
+	assert.Error(suite.T(), err)
+}
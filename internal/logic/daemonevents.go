@@ -0,0 +1,273 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/shell"
+)
+
+// daemonEventsInterval is the interval the state of the environments is checked for changes by the event stream.
+const daemonEventsInterval = 2 * time.Second
+
+const (
+	daemonEnvRunning = "running"
+	daemonEnvPartial = "partial"
+	daemonEnvStopped = "stopped"
+	daemonEnvRemoved = "removed"
+)
+
+// syncHealthPriority orders the health of the sync sessions, the worst health of the sessions of an environment is
+// reported.
+var syncHealthPriority = map[string]int{"ok": 0, "syncing": 1, "paused": 2, "conflicts": 3, "error": 4}
+
+// daemonEnvSummary is the state of an environment at a glance, eg. for a menu bar or system tray companion.
+type daemonEnvSummary struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+	// State is running if all the containers are running, partial if some of them are and stopped otherwise.
+	State string `json:"state"`
+	// Health is the worst health status of the containers (unhealthy, starting, healthy), it's empty if none of the
+	// containers has a healthcheck.
+	Health string `json:"health,omitempty"`
+	// Sync is the worst health of the mutagen sync sessions (ok, syncing, paused, conflicts, error), it's empty if
+	// the environment is not synced.
+	Sync string `json:"sync,omitempty"`
+}
+
+// daemonEvent is an event of the event stream. The env events are sent when the state or the health of an
+// environment changes, the sync events when the health of its sync sessions changes.
+type daemonEvent struct {
+	Type string
+	Data daemonEnvSummary
+}
+
+func (d *daemon) handleSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		daemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	summaries, err := d.daemonSummaries()
+	if err != nil {
+		daemonError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	daemonJSON(w, summaries)
+}
+
+// handleEvents streams the changes of the environments as server-sent events until the client disconnects. The
+// current state of the environments is sent first.
+func (d *daemon) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		daemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		daemonError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(daemonEventsInterval)
+	defer ticker.Stop()
+
+	var prev []daemonEnvSummary
+
+	for {
+		next, err := d.daemonSummaries()
+		if err != nil {
+			log.Warnf("Cannot get the state of the environments: %s", err)
+		} else {
+			for _, event := range diffDaemonSummaries(prev, next) {
+				data, _ := json.Marshal(event.Data)
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			}
+
+			flusher.Flush()
+
+			prev = next
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// daemonSummaries returns the state of the environments and their sync sessions sorted by the environment names.
+func (d *daemon) daemonSummaries() ([]daemonEnvSummary, error) {
+	envs, err := d.daemonEnvs()
+	if err != nil {
+		return nil, err
+	}
+
+	sync := d.syncHealth()
+
+	summaries := make([]daemonEnvSummary, 0, len(envs))
+	for _, env := range envs {
+		summaries = append(summaries, summarizeDaemonEnv(env, sync[env.Name]))
+	}
+
+	return summaries, nil
+}
+
+// syncHealth returns the health of the sync sessions by the environment names. It's empty if mutagen is not
+// installed.
+func (d *daemon) syncHealth() map[string]string {
+	label := fmt.Sprintf("%s-sync", d.AppName())
+
+	out, err := d.Shell.RunCommand(
+		[]string{d.mutagenCommand(), "sync", "list", "--label-selector", label, "--template", "{{ json . }}"},
+		shell.WithCatchOutput(true),
+		shell.WithSuppressOutput(true),
+	)
+	if err != nil {
+		log.Debugf("Cannot list mutagen sync sessions: %s", err)
+
+		return nil
+	}
+
+	health, err := parseSyncHealth(out, label)
+	if err != nil {
+		log.Debugf("%s", err)
+	}
+
+	return health
+}
+
+// parseSyncHealth returns the worst health of the sync sessions by the environment names from the json output of
+// mutagen sync list. The environment of a session is the value of its label.
+func parseSyncHealth(out []byte, label string) (map[string]string, error) {
+	var sessions []syncSession
+
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	err := json.Unmarshal(out, &sessions)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse mutagen sync sessions: %w", err)
+	}
+
+	health := make(map[string]string)
+
+	for _, session := range sessions {
+		env := session.Labels[label]
+		if env == "" {
+			continue
+		}
+
+		h := syncSessionHealth(session)
+		if current, ok := health[env]; !ok || syncHealthPriority[h] > syncHealthPriority[current] {
+			health[env] = h
+		}
+	}
+
+	return health, nil
+}
+
+// syncSessionHealth returns the health of the sync session.
+func syncSessionHealth(session syncSession) string {
+	switch {
+	case session.LastError != "":
+		return "error"
+	case len(session.Conflicts) > 0:
+		return "conflicts"
+	case session.Paused:
+		return "paused"
+	case session.Status == "watching":
+		return "ok"
+	default:
+		return "syncing"
+	}
+}
+
+// summarizeDaemonEnv returns the state of the environment at a glance.
+func summarizeDaemonEnv(env daemonEnv, sync string) daemonEnvSummary {
+	summary := daemonEnvSummary{Name: env.Name, Dir: env.Dir, Sync: sync}
+
+	var running int
+
+	for _, container := range env.Containers {
+		if container.State == "running" {
+			running++
+		}
+
+		switch {
+		case container.Health == "unhealthy":
+			summary.Health = "unhealthy"
+		case container.Health == "starting" && summary.Health != "unhealthy":
+			summary.Health = "starting"
+		case container.Health == "healthy" && summary.Health == "":
+			summary.Health = "healthy"
+		}
+	}
+
+	switch running {
+	case 0:
+		summary.State = daemonEnvStopped
+	case len(env.Containers):
+		summary.State = daemonEnvRunning
+	default:
+		summary.State = daemonEnvPartial
+	}
+
+	return summary
+}
+
+// diffDaemonSummaries returns the events of the changes between the previous and the next state of the
+// environments. The environments which disappeared are reported with the removed state.
+func diffDaemonSummaries(prev, next []daemonEnvSummary) []daemonEvent {
+	previous := make(map[string]daemonEnvSummary, len(prev))
+	for _, summary := range prev {
+		previous[summary.Name] = summary
+	}
+
+	var events []daemonEvent
+
+	for _, summary := range next {
+		old, ok := previous[summary.Name]
+		delete(previous, summary.Name)
+
+		if !ok || old.State != summary.State || old.Health != summary.Health {
+			events = append(events, daemonEvent{Type: "env", Data: summary})
+		}
+
+		if ok && old.Sync != summary.Sync || !ok && summary.Sync != "" {
+			events = append(events, daemonEvent{Type: "sync", Data: summary})
+		}
+	}
+
+	removed := make([]string, 0, len(previous))
+	for name := range previous {
+		removed = append(removed, name)
+	}
+
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		events = append(events, daemonEvent{
+			Type: "env",
+			Data: daemonEnvSummary{Name: name, Dir: previous[name].Dir, State: daemonEnvRemoved},
+		})
+	}
+
+	return events
+}
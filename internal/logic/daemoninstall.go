@@ -0,0 +1,175 @@
+package logic
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrDaemonInstallUnsupported occurs when the daemon cannot be registered as a service on the operating system.
+var ErrDaemonInstallUnsupported = func(goos string) error {
+	return fmt.Errorf("registering the daemon is not supported on %s, run `reward daemon` at login instead", goos)
+}
+
+const daemonLaunchAgentTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>%[1]s</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>%[2]s</string>
+    <string>daemon</string>
+  </array>
+  <key>EnvironmentVariables</key>
+  <dict>
+    <key>PATH</key>
+    <string>%[3]s</string>
+  </dict>
+  <key>RunAtLoad</key>
+  <true/>
+  <key>KeepAlive</key>
+  <true/>
+  <key>StandardErrorPath</key>
+  <string>%[4]s</string>
+</dict>
+</plist>
+`
+
+const daemonSystemdUnitTemplate = `[Unit]
+Description=%[1]s daemon
+After=docker.service
+
+[Service]
+ExecStart=%[2]q daemon
+Environment="PATH=%[3]s"
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`
+
+// RunCmdDaemonInstall registers the daemon as a LaunchAgent on macOS or as a systemd user unit on Linux, so it's
+// started at login and restarted if it exits. The PATH of the daemon is the current PATH, so it finds docker.
+func (c *Client) RunCmdDaemonInstall() error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	file, content, err := c.daemonServiceFile(runtime.GOOS, self, os.Getenv("PATH"))
+	if err != nil {
+		return err
+	}
+
+	err = util.CreateDirAndWriteToFile([]byte(content), file, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", file, err)
+	}
+
+	log.Printf("Registering the daemon using %s...", file)
+
+	for _, command := range c.daemonServiceCommands(runtime.GOOS, file, true) {
+		out, err := cmdpkg.Cmnd(command[0], command[1:]...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot run %s: %w: %s", strings.Join(command, " "), err, out)
+		}
+	}
+
+	log.Println("...daemon registered, it's started at login.")
+
+	return nil
+}
+
+// RunCmdDaemonUninstall stops the daemon and removes its LaunchAgent or systemd user unit.
+func (c *Client) RunCmdDaemonUninstall() error {
+	file, _, err := c.daemonServiceFile(runtime.GOOS, "", "")
+	if err != nil {
+		return err
+	}
+
+	if !util.FileExists(file) {
+		log.Println("The daemon is not registered.")
+
+		return nil
+	}
+
+	log.Println("Unregistering the daemon...")
+
+	for _, command := range c.daemonServiceCommands(runtime.GOOS, file, false) {
+		out, err := cmdpkg.Cmnd(command[0], command[1:]...).CombinedOutput()
+		if err != nil {
+			log.Warnf("Cannot run %s: %s: %s", strings.Join(command, " "), err, out)
+		}
+	}
+
+	err = os.Remove(file)
+	if err != nil {
+		return fmt.Errorf("cannot remove %s: %w", file, err)
+	}
+
+	log.Println("...daemon unregistered.")
+
+	return nil
+}
+
+// daemonServiceName returns the name of the LaunchAgent or the systemd user unit of the daemon.
+func (c *Client) daemonServiceName(goos string) string {
+	if goos == "darwin" {
+		return fmt.Sprintf("dev.%s.daemon", c.AppName())
+	}
+
+	return fmt.Sprintf("%s-daemon.service", c.AppName())
+}
+
+// daemonServiceFile returns the path and the content of the LaunchAgent or the systemd user unit which starts the
+// executable as the daemon.
+func (c *Client) daemonServiceFile(goos, executable, path string) (string, string, error) {
+	switch goos {
+	case "darwin":
+		return filepath.Join(util.HomeDir(), "Library", "LaunchAgents", c.daemonServiceName(goos)+".plist"),
+			fmt.Sprintf(daemonLaunchAgentTemplate, c.daemonServiceName(goos), html.EscapeString(executable),
+				html.EscapeString(path), html.EscapeString(filepath.Join(c.AppHomeDir(), "daemon.log"))),
+			nil
+	case "linux":
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(util.HomeDir(), ".config")
+		}
+
+		return filepath.Join(configDir, "systemd", "user", c.daemonServiceName(goos)),
+			fmt.Sprintf(daemonSystemdUnitTemplate, c.AppName(), executable, path),
+			nil
+	default:
+		return "", "", ErrDaemonInstallUnsupported(goos)
+	}
+}
+
+// daemonServiceCommands returns the commands which start (or stop) the registered daemon.
+func (c *Client) daemonServiceCommands(goos, file string, start bool) [][]string {
+	switch {
+	case goos == "darwin" && start:
+		return [][]string{{"launchctl", "load", "-w", file}}
+	case goos == "darwin":
+		return [][]string{{"launchctl", "unload", "-w", file}}
+	case start:
+		return [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", c.daemonServiceName(goos)},
+		}
+	default:
+		return [][]string{
+			{"systemctl", "--user", "disable", "--now", c.daemonServiceName(goos)},
+			{"systemctl", "--user", "daemon-reload"},
+		}
+	}
+}
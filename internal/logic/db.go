@@ -29,15 +29,9 @@ func (c *Client) RunCmdDBConnect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get flag: %w", err)
 	}
 
-	mysqlDBParam := "--database=$(printenv MYSQL_DATABASE)"
-
-	var mysqlUserParam, mysqlPasswordParam string
-	if runAsRootUser {
-		mysqlUserParam = "-uroot"
-		mysqlPasswordParam = "-p$(printenv MYSQL_ROOT_PASSWORD)" //nolint:gosec
-	} else {
-		mysqlUserParam = "-u$(printenv MYSQL_USER)"
-		mysqlPasswordParam = "-p$(printenv MYSQL_PASSWORD)" //nolint:gosec
+	err = c.waitForDBReady(c.DBReadyTimeout())
+	if err != nil {
+		return err
 	}
 
 	passedArgs := []string{
@@ -45,11 +39,8 @@ func (c *Client) RunCmdDBConnect(cmd *cobra.Command, args []string) error {
 		c.DBContainer(),
 		"sh",
 		"-c",
-		fmt.Sprintf("%s %s %s %s %s",
-			c.DBCommand(),
-			mysqlUserParam,
-			mysqlPasswordParam,
-			mysqlDBParam,
+		fmt.Sprintf("%s %s",
+			c.dbClientCommand(false, runAsRootUser),
 			strings.Join(util.ExtractUnknownArgs(cmd.Flags(), args), " "),
 		),
 	}
@@ -69,33 +60,43 @@ func (c *Client) RunCmdDBImport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get flag: %w", err)
 	}
 
-	mysqlDBParam := "--database=$(printenv MYSQL_DATABASE)"
+	err = c.ImportDB(runAsRootUser, util.ExtractUnknownArgs(cmd.Flags(), args))
+	if err != nil {
+		return err
+	}
 
-	var mysqlUserParam, mysqlPasswordParam string
-	if runAsRootUser {
-		mysqlUserParam = "-uroot"
-		mysqlPasswordParam = "-p$(printenv MYSQL_ROOT_PASSWORD)" //nolint:gosec
-	} else {
-		mysqlUserParam = "-u$(printenv MYSQL_USER)"
-		mysqlPasswordParam = "-p$(printenv MYSQL_PASSWORD)" //nolint:gosec
+	if from, _ := cmd.Flags().GetString("rewrite-from"); from != "" {
+		to, _ := cmd.Flags().GetString("rewrite-to")
+
+		return c.rewriteURLs(from, to, false)
+	}
+
+	return nil
+}
+
+// ImportDB imports the dump read from Stdin (it can be compressed) into the database of the environment. The
+// mysqlArgs are passed to the database client (mysql, or psql for PostgreSQL).
+func (c *Client) ImportDB(runAsRootUser bool, mysqlArgs []string) error {
+	err := c.waitForDBReady(c.DBReadyTimeout())
+	if err != nil {
+		return err
 	}
 
-	// FIXME: ExtractUnknownArgs not working here
 	passedArgs := []string{
 		"exec",
 		"-T",
 		c.DBContainer(),
 		"sh",
 		"-c",
-		fmt.Sprintf("%s %s %s %s %s",
-			c.DBCommand(),
-			mysqlUserParam,
-			mysqlPasswordParam,
-			mysqlDBParam,
-			strings.Join(util.ExtractUnknownArgs(cmd.Flags(), args), " "),
+		fmt.Sprintf("%s %s",
+			c.dbClientCommand(false, runAsRootUser),
+			strings.Join(mysqlArgs, " "),
 		),
 	}
 
+	// the progress of the import is reported only if the dump is read from stdin (not from a terminal)
+	c.Set("db_import_progress", true)
+
 	err = c.RunCmdDBDockerCompose(passedArgs, false)
 	if err != nil {
 		return fmt.Errorf("failed to run docker-compose to import database: %w", err)
@@ -111,17 +112,6 @@ func (c *Client) RunCmdDBDump(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get flag: %w", err)
 	}
 
-	mysqlDBParam := "$(printenv MYSQL_DATABASE)"
-
-	var mysqlUserParam, mysqlPasswordParam string
-	if runAsRootUser {
-		mysqlUserParam = "-uroot"
-		mysqlPasswordParam = "-p$(printenv MYSQL_ROOT_PASSWORD)" //nolint:gosec
-	} else {
-		mysqlUserParam = "-u$(printenv MYSQL_USER)"
-		mysqlPasswordParam = "-p$(printenv MYSQL_PASSWORD)" //nolint:gosec
-	}
-
 	passedArgs := []string{
 		"exec",
 		"-T",
@@ -129,15 +119,21 @@ func (c *Client) RunCmdDBDump(cmd *cobra.Command, args []string) error {
 		"sh",
 		"-c",
 		fmt.Sprintf(
-			"%s %s %s %s %s",
-			c.DBDumpCommand(),
-			mysqlUserParam,
-			mysqlPasswordParam,
-			mysqlDBParam,
+			"%s %s",
+			c.dbClientCommand(true, runAsRootUser),
 			strings.Join(util.ExtractUnknownArgs(cmd.Flags(), args), " "),
 		),
 	}
 
+	compression, err := cmd.Flags().GetString("compress")
+	if err != nil {
+		return fmt.Errorf("failed to get flag: %w", err)
+	}
+
+	if compression != "" {
+		return c.dbDumpCompressed(passedArgs, compression)
+	}
+
 	err = c.RunCmdDBDockerCompose(passedArgs, false)
 	if err != nil {
 		return fmt.Errorf("failed to run docker-compose to dump database: %w", err)
@@ -146,6 +142,73 @@ func (c *Client) RunCmdDBDump(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// dbDumpCompressed runs the dump command with docker-compose and writes its output to stdout compressed with the
+// compression (gzip or zstd).
+func (c *Client) dbDumpCompressed(args []string, compression string) error {
+	out, err := util.CompressWriter(os.Stdout, compression)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	details, err := c.dbDockerComposeConfig()
+	if err != nil {
+		return err
+	}
+
+	composeArgs, err := c.dbDockerComposeFileArgs(details)
+	if err != nil {
+		return err
+	}
+
+	composeArgs = append(composeArgs, "--project-directory", c.Cwd(), "--project-name", c.EnvName())
+
+	cmd := exec.Command("docker-compose", append(composeArgs, args...)...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run docker-compose to dump database: %w", err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		return fmt.Errorf("cannot compress database dump: %w", err)
+	}
+
+	return nil
+}
+
+// dbClientCommand returns the shell command of the database client (or the dump tool if dump is true) with the
+// credentials and the database of the db container. The PostgreSQL database of oro is used with psql and pg_dump as
+// the user of the image, it's a superuser, so there is no separate root user.
+func (c *Client) dbClientCommand(dump, runAsRootUser bool) string {
+	if c.dbPostgres() {
+		tool := "psql"
+		if dump {
+			tool = "pg_dump"
+		}
+
+		return fmt.Sprintf(`%s -U "$POSTGRES_USER" -d "$POSTGRES_DB"`, tool)
+	}
+
+	var mysqlUserParam, mysqlPasswordParam string
+	if runAsRootUser {
+		mysqlUserParam = "-uroot"
+		mysqlPasswordParam = "-p$(printenv MYSQL_ROOT_PASSWORD)" //nolint:gosec
+	} else {
+		mysqlUserParam = "-u$(printenv MYSQL_USER)"
+		mysqlPasswordParam = "-p$(printenv MYSQL_PASSWORD)" //nolint:gosec
+	}
+
+	if dump {
+		return fmt.Sprintf("%s %s %s $(printenv MYSQL_DATABASE)", c.DBDumpCommand(), mysqlUserParam, mysqlPasswordParam)
+	}
+
+	return fmt.Sprintf("%s %s %s --database=$(printenv MYSQL_DATABASE)", c.DBCommand(), mysqlUserParam,
+		mysqlPasswordParam)
+}
+
 // RunCmdDBDockerCompose function is a wrapper around the docker-compose command.
 // It appends the current directory and current project name to the args.
 // It also changes the output if the OS StdOut is suppressed.
@@ -178,25 +241,38 @@ func (c *Client) RunCmdDBDockerCompose(args []string, suppressOsStdOut ...bool)
 
 // DBBuildDockerComposeCommand builds up the docker-compose command's templates.
 func (c *Client) RunCmdDBBuildDockerComposeCommand(args []string, suppressOsStdOut ...bool) (string, error) {
+	dockerComposeConfigs, err := c.dbDockerComposeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := c.RunCmdDBDockerComposeWithConfig(args, dockerComposeConfigs, suppressOsStdOut...)
+	if err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// dbDockerComposeConfig returns the docker-compose configuration of the environment.
+func (c *Client) dbDockerComposeConfig() (compose.ConfigDetails, error) {
 	dbTemplate := new(template.Template)
 	dbTemplateList := list.New()
 
 	err := c.RunCmdEnvBuildDockerComposeTemplate(dbTemplate, dbTemplateList)
 	if err != nil {
-		return "", err
+		return compose.ConfigDetails{}, err
 	}
 
 	dockerComposeConfigs, err := templates.New().ConvertTemplateToComposeConfig(dbTemplate, dbTemplateList)
 	if err != nil {
-		return "", err
+		return compose.ConfigDetails{}, err
 	}
 
-	out, err := c.RunCmdDBDockerComposeWithConfig(args, dockerComposeConfigs, suppressOsStdOut...)
-	if err != nil {
-		return out, err
-	}
+	dockerComposeConfigs = c.appendEnvLabelsConfig(dockerComposeConfigs)
+	dockerComposeConfigs = templates.New().AppendResourcesConfig(dockerComposeConfigs, c.ServiceMemoryLimit)
 
-	return out, nil
+	return dockerComposeConfigs, nil
 }
 
 // RunCmdDBDockerComposeWithConfig calls docker-compose with the previously built docker-compose configuration.
@@ -205,17 +281,35 @@ func (c *Client) RunCmdDBDockerComposeWithConfig(
 	details compose.ConfigDetails,
 	suppressOsStdOut ...bool,
 ) (string, error) {
+	composeArgs, err := c.dbDockerComposeFileArgs(details)
+	if err != nil {
+		return "", err
+	}
+
+	composeArgs = append(composeArgs, args...)
+
+	out, err := c.RunCmdDBDockerComposeCommandModifyStdin(composeArgs, suppressOsStdOut...)
+	if err != nil {
+		return out, fmt.Errorf("failed to run docker-compose: %w", err)
+	}
+
+	return out, nil
+}
+
+// dbDockerComposeFileArgs writes the docker-compose configuration to temporary files and returns the docker-compose
+// arguments which load them.
+func (c *Client) dbDockerComposeFileArgs(details compose.ConfigDetails) ([]string, error) {
 	tmpFiles := make([]string, len(details.ConfigFiles))
 
 	for i, conf := range details.ConfigFiles {
 		bs, err := yaml.Marshal(conf.Config)
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal config: %w", err)
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
 		}
 
 		tmpFile, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("%s-", c.AppName()))
 		if err != nil {
-			return "", fmt.Errorf("failed to create temporary file: %w", err)
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
 		}
 
 		c.TmpFiles.PushBack(tmpFile.Name())
@@ -223,12 +317,12 @@ func (c *Client) RunCmdDBDockerComposeWithConfig(
 
 		_, err = tmpFile.Write(bs)
 		if err != nil {
-			return "", fmt.Errorf("failed to write to temporary file: %w", err)
+			return nil, fmt.Errorf("failed to write to temporary file: %w", err)
 		}
 
 		err = tmpFile.Close()
 		if err != nil {
-			return "", fmt.Errorf("failed to close temporary file: %w", err)
+			return nil, fmt.Errorf("failed to close temporary file: %w", err)
 		}
 	}
 
@@ -237,14 +331,7 @@ func (c *Client) RunCmdDBDockerComposeWithConfig(
 		composeArgs = append(composeArgs, "-f", file)
 	}
 
-	composeArgs = append(composeArgs, args...)
-
-	out, err := c.RunCmdDBDockerComposeCommandModifyStdin(composeArgs, suppressOsStdOut...)
-	if err != nil {
-		return out, fmt.Errorf("failed to run docker-compose: %w", err)
-	}
-
-	return out, nil
+	return composeArgs, nil
 }
 
 // RunCmdDBDockerComposeCommandModifyStdin runs the passed parameters with docker-compose and returns the output.
@@ -259,7 +346,21 @@ func (c *Client) RunCmdDBDockerComposeCommandModifyStdin(args []string, suppress
 	globalRegex := regexp.MustCompile(`@@(GLOBAL\.GTID_PURGED|SESSION\.SQL_LOG_BIN)`)
 
 	go func() {
-		scanner := bufio.NewScanner(os.Stdin)
+		// the dump is decompressed if it's compressed (eg. reward db import < dump.sql.zst)
+		dump, progress := c.dbImportProgressReader(c.stdin())
+
+		stdin, err := util.DecompressReader(dump)
+		if err != nil {
+			// the input of docker-compose is closed with the error, so the command fails with it
+			log.Errorf("An error occurred: %s", err)
+
+			_ = w.CloseWithError(err)
+
+			return
+		}
+		defer stdin.Close()
+
+		scanner := bufio.NewScanner(stdin)
 
 		maxCapacity := c.GetInt("db_import_line_buffer_size") * 1024 * 1024 // max capacity for buffer is 10MB/line
 		bs := make([]byte, 0, 1024*1024)
@@ -277,16 +378,14 @@ func (c *Client) RunCmdDBDockerComposeCommandModifyStdin(args []string, suppress
 			)
 		}
 
-		err := scanner.Err()
+		progress.Done()
+
+		err = scanner.Err()
 		if err != nil {
 			log.Errorf("An error occurred: %s", err)
-
-			os.Exit(1)
 		}
 
-		defer func(w *io.PipeWriter) {
-			_ = w.Close()
-		}(w)
+		_ = w.CloseWithError(err)
 	}()
 
 	cmd.Stdin = r
@@ -303,3 +402,31 @@ func (c *Client) RunCmdDBDockerComposeCommandModifyStdin(args []string, suppress
 
 	return outStr, err //nolint:wrapcheck
 }
+
+// dbImportProgressReader returns a reader which reports the progress of the database import if it's enabled and the
+// dump is not read from a terminal. The total size is known if the dump is redirected from a file.
+func (c *Client) dbImportProgressReader(stdin io.Reader) (io.Reader, util.Progress) {
+	if !c.GetBool("db_import_progress") {
+		return stdin, util.NoProgress
+	}
+
+	var size int64
+
+	if f, ok := stdin.(*os.File); ok {
+		stat, err := f.Stat()
+		if err != nil || stat.Mode()&os.ModeCharDevice != 0 {
+			return stdin, util.NoProgress
+		}
+
+		if stat.Mode().IsRegular() {
+			size = stat.Size()
+		}
+	}
+
+	step := c.newProgress().Step("Importing database")
+	if size > 0 {
+		step.Start(0, size)
+	}
+
+	return util.ProgressReader(stdin, step), step
+}
@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DBTestSuite struct {
+	suite.Suite
+}
+
+func TestDBTestSuite(t *testing.T) {
+	suite.Run(t, new(DBTestSuite))
+}
+
+func (suite *DBTestSuite) TestDBClientCommand() {
+	tests := []struct {
+		name    string
+		envType string
+		dump    bool
+		root    bool
+		want    string
+	}{
+		{
+			name:    "mysql",
+			envType: "magento2",
+			want:    "mysql -u$(printenv MYSQL_USER) -p$(printenv MYSQL_PASSWORD) --database=$(printenv MYSQL_DATABASE)",
+		},
+		{
+			name:    "mysql root dump",
+			envType: "magento2",
+			dump:    true,
+			root:    true,
+			want:    "mysqldump -uroot -p$(printenv MYSQL_ROOT_PASSWORD) $(printenv MYSQL_DATABASE)",
+		},
+		{
+			name:    "postgres",
+			envType: "oro",
+			want:    `psql -U "$POSTGRES_USER" -d "$POSTGRES_DB"`,
+		},
+		{
+			name:    "postgres root dump",
+			envType: "oro",
+			dump:    true,
+			root:    true,
+			want:    `pg_dump -U "$POSTGRES_USER" -d "$POSTGRES_DB"`,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestClient(map[string]interface{}{
+				"reward_env_type":            tt.envType,
+				"reward_env_db_command":      "mysql",
+				"reward_env_db_dump_command": "mysqldump",
+			})
+
+			assert.Equal(t, tt.want, c.dbClientCommand(tt.dump, tt.root))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package logic
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+// dbExposeImage is the image of the proxy container which publishes the port of the database.
+const dbExposeImage = "docker.io/alpine/socat:latest"
+
+// ErrInvalidDBExposePort occurs when the port to publish the database on is not a valid port.
+var ErrInvalidDBExposePort = func(port int) error {
+	return fmt.Errorf("invalid port: %d, it must be between 1 and 65535, or 0 for a random free port", port)
+}
+
+// ErrDBNotExposed occurs when the database is not exposed by db expose.
+var ErrDBNotExposed = fmt.Errorf("the database is not exposed, expose it using `reward db expose`")
+
+// RunCmdDBExpose publishes the port of the database on localhost, so it can be reached by the database GUIs (eg.
+// TablePlus, DBeaver). The port is published by a proxy container on the environment network, so the db container
+// doesn't have to be recreated, and the port is not exposed permanently like with MYSQL_EXPOSE. By default, a random
+// free port is used.
+func (c *Client) RunCmdDBExpose(cmd *cobra.Command) error {
+	port, err := cmd.Flags().GetInt("port")
+	if err != nil {
+		return fmt.Errorf("failed to get flag: %w", err)
+	}
+
+	if port < 0 || port > 65535 {
+		return ErrInvalidDBExposePort(port)
+	}
+
+	if current, err := c.dbExposedPort(); err == nil {
+		if port == 0 || port == current {
+			c.printDBExposeDSN(current)
+
+			return nil
+		}
+
+		err = c.removeDBExpose()
+		if err != nil {
+			return err
+		}
+	}
+
+	dbPort := c.dbPort()
+
+	publish := fmt.Sprintf("127.0.0.1::%d", dbPort)
+	if port != 0 {
+		publish = fmt.Sprintf("127.0.0.1:%d:%d", port, dbPort)
+	}
+
+	out, err := cmdpkg.Cmnd("docker", "run", "--detach",
+		"--name", c.dbExposeContainer(),
+		"--network", c.EnvNetworkName(),
+		"--label", fmt.Sprintf("%s=%s", c.LabelEnvName(), c.EnvName()),
+		"--publish", publish,
+		dbExposeImage,
+		fmt.Sprintf("tcp-listen:%d,fork,reuseaddr", dbPort), fmt.Sprintf("tcp-connect:%s:%d", c.DBContainer(), dbPort),
+	).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot start the proxy container: %w: %s", err, out)
+	}
+
+	current, err := c.dbExposedPort()
+	if err != nil {
+		return err
+	}
+
+	c.printDBExposeDSN(current)
+
+	return nil
+}
+
+// RunCmdDBUnexpose removes the proxy container which publishes the port of the database.
+func (c *Client) RunCmdDBUnexpose() error {
+	if _, err := c.dbExposedPort(); err != nil {
+		return ErrDBNotExposed
+	}
+
+	err := c.removeDBExpose()
+	if err != nil {
+		return err
+	}
+
+	log.Println("The database is not exposed anymore.")
+
+	return nil
+}
+
+// removeDBExpose removes the proxy container of the database if it exists. It has to be removed before the
+// environment network is removed, as the network cannot be removed while the container is connected to it.
+func (c *Client) removeDBExpose() error {
+	if _, err := c.dbExposedPort(); err != nil {
+		return nil //nolint:nilerr
+	}
+
+	out, err := cmdpkg.Cmnd("docker", "rm", "--force", c.dbExposeContainer()).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot remove the proxy container: %w: %s", err, out)
+	}
+
+	return nil
+}
+
+// dbExposedPort returns the port of localhost the database is published on by the proxy container.
+func (c *Client) dbExposedPort() (int, error) {
+	out, err := cmdpkg.Cmnd("docker", "port", c.dbExposeContainer(), fmt.Sprintf("%d/tcp", c.dbPort())).Output()
+	if err != nil {
+		return 0, ErrDBNotExposed
+	}
+
+	return parseDockerPort(string(out))
+}
+
+func (c *Client) dbExposeContainer() string {
+	return c.EnvName() + "-db-expose"
+}
+
+// printDBExposeDSN prints the connection settings of the exposed database.
+func (c *Client) printDBExposeDSN(port int) {
+	var (
+		host     = net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+		user     = c.dbSetting("user")
+		password = c.dbSetting("password")
+		database = c.dbSetting("database")
+	)
+
+	log.Printf("The database is exposed on %s, remove it using `%s db unexpose`.", host, c.AppName())
+
+	fmt.Printf("Host:     127.0.0.1\nPort:     %d\nUser:     %s\nPassword: %s\nDatabase: %s\n",
+		port, user, password, database)
+
+	dsn, jdbc := dbExposeURLs(c.dbPostgres(), host, user, password, database)
+	fmt.Printf("DSN:      %s\nJDBC:     %s\n", dsn, jdbc)
+}
+
+// dbExposeURLs returns the DSN and the JDBC url of the exposed database.
+func dbExposeURLs(postgres bool, host, user, password, database string) (dsn, jdbc string) {
+	if postgres {
+		return fmt.Sprintf("postgres://%s:%s@%s/%s", user, password, host, database),
+			fmt.Sprintf("jdbc:postgresql://%s/%s?user=%s&password=%s", host, database, user, password)
+	}
+
+	return fmt.Sprintf("mysql://%s:%s@%s/%s", user, password, host, database),
+		fmt.Sprintf("jdbc:mariadb://%s/%s?user=%s&password=%s", host, database, user, password)
+}
+
+// parseDockerPort returns the port from the output of docker port (eg. 127.0.0.1:49153).
+func parseDockerPort(out string) (int, error) {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(out), "\n", 2)[0])
+
+	_, port, err := net.SplitHostPort(line)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse the published port %q: %w", line, err)
+	}
+
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse the published port %q: %w", line, err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,101 @@
+package logic
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/timing"
+)
+
+const (
+	// dbReadyMinBackoff is the time between the first attempts to connect to the database.
+	dbReadyMinBackoff = 250 * time.Millisecond
+	// dbReadyMaxBackoff is the maximum time between the attempts to connect to the database.
+	dbReadyMaxBackoff = 5 * time.Second
+)
+
+// ErrDBNotReady occurs when the database doesn't accept connections in time.
+var ErrDBNotReady = func(timeout time.Duration) error {
+	return fmt.Errorf("the database didn't accept connections in %s, check its logs using `env logs db` or increase "+
+		"the timeout using REWARD_DB_READY_TIMEOUT", timeout)
+}
+
+// waitForDBReady polls the database until it accepts TCP connections. Right after the database container is
+// (re)started, the database server is not ready yet, and the entrypoint of the image initializes the data directory
+// with a temporary server which doesn't listen on TCP. The time between the attempts grows exponentially until the
+// timeout.
+func (c *Client) waitForDBReady(timeout time.Duration) error {
+	defer timing.Start("health wait")()
+
+	var (
+		start   = time.Now()
+		waiting bool
+	)
+
+	for attempt := 0; ; attempt++ {
+		if c.dbAcceptsConnections() {
+			if waiting {
+				log.Println("...database is ready.")
+			}
+
+			return nil
+		}
+
+		if !waiting {
+			log.Println("Waiting for the database to accept connections...")
+
+			waiting = true
+		}
+
+		if time.Since(start) > timeout {
+			return ErrDBNotReady(timeout)
+		}
+
+		time.Sleep(dbReadyBackoff(attempt))
+	}
+}
+
+// dbAcceptsConnections returns true if the database container is running and it accepts TCP connections.
+func (c *Client) dbAcceptsConnections() bool {
+	container, err := c.Docker.EnvServiceContainer(c.DBContainer())
+	if err != nil || !container.Running() {
+		return false
+	}
+
+	ping := cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c", c.dbReadyProbe())
+
+	return ping.Run() == nil
+}
+
+// dbReadyProbe returns the shell command which succeeds if the database accepts TCP connections. The probe depends on
+// the database engine of the environment type.
+func (c *Client) dbReadyProbe() string {
+	if c.dbPostgres() {
+		return `pg_isready -h 127.0.0.1 -U "$POSTGRES_USER" -d "$POSTGRES_DB"`
+	}
+
+	return fmt.Sprintf(`%s -h127.0.0.1 -uroot -p"$MYSQL_ROOT_PASSWORD" -e "SELECT 1"`, c.DBCommand())
+}
+
+// dbPostgres returns true if the database of the environment type is PostgreSQL instead of MySQL/MariaDB.
+func (c *Client) dbPostgres() bool {
+	return c.EnvType() == "oro"
+}
+
+// dbReadyBackoff returns the time to wait after the failed attempt to connect to the database.
+func dbReadyBackoff(attempt int) time.Duration {
+	backoff := dbReadyMinBackoff
+
+	for i := 0; i < attempt && backoff < dbReadyMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > dbReadyMaxBackoff {
+		return dbReadyMaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DBReadyTestSuite struct {
+	suite.Suite
+}
+
+func TestDBReadyTestSuite(t *testing.T) {
+	suite.Run(t, new(DBReadyTestSuite))
+}
+
+func (suite *DBReadyTestSuite) TestDBReadyBackoff() {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 250 * time.Millisecond},
+		{attempt: 1, want: 500 * time.Millisecond},
+		{attempt: 3, want: 2 * time.Second},
+		{attempt: 5, want: 5 * time.Second},
+		{attempt: 100, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(suite.T(), tt.want, dbReadyBackoff(tt.attempt), "attempt %d", tt.attempt)
+	}
+}
+
+func (suite *DBReadyTestSuite) TestDBReadyProbe() {
+	tests := []struct {
+		envType string
+		want    string
+	}{
+		{envType: "magento2", want: `mysql -h127.0.0.1 -uroot -p"$MYSQL_ROOT_PASSWORD" -e "SELECT 1"`},
+		{envType: "oro", want: `pg_isready -h 127.0.0.1 -U "$POSTGRES_USER" -d "$POSTGRES_DB"`},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.envType, func(t *testing.T) {
+			c := newTestClient(map[string]interface{}{
+				"reward_env_type":       tt.envType,
+				"reward_env_db_command": "mysql",
+			})
+
+			assert.Equal(t, tt.want, c.dbReadyProbe())
+		})
+	}
+}
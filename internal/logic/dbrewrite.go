@@ -0,0 +1,360 @@
+package logic
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ErrRewriteURLsNotSupported occurs when the URLs of the environment type cannot be rewritten.
+	ErrRewriteURLsNotSupported = func(envType string) error {
+		return fmt.Errorf("rewriting urls is not supported for environment type: %s", envType)
+	}
+
+	errInvalidSerializedValue = fmt.Errorf("invalid php serialized value")
+)
+
+var dbQueryRowRegex = regexp.MustCompile(`(?m)^(\d+)\t(.*)$`)
+
+// urlTable is the table (and its columns) which contains the URLs of the application.
+type urlTable struct {
+	Name     string
+	IDColumn string
+	Column   string
+}
+
+// RunCmdDBRewriteURLs replaces the URL in the configuration values of the application's database.
+func (c *Client) RunCmdDBRewriteURLs(cmd *cobra.Command) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	return c.rewriteURLs(from, to, dryRun)
+}
+
+// rewriteURLs replaces the from URL with the to URL (the URL of the environment by default) in the database.
+func (c *Client) rewriteURLs(from, to string, dryRun bool) error {
+	table, err := c.urlTable()
+	if err != nil {
+		return err
+	}
+
+	if to == "" {
+		to = fmt.Sprintf("https://%s", c.TraefikFullDomain())
+	}
+
+	from, to = strings.TrimSuffix(from, "/"), strings.TrimSuffix(to, "/")
+
+	log.Printf("Rewriting %s to %s in table %s...", from, to, table.Name)
+
+	out, err := c.dbQuery(fmt.Sprintf(
+		"SELECT %[1]s, %[2]s FROM %[3]s WHERE INSTR(%[2]s, '%[4]s') > 0 OR INSTR(%[2]s, '%[5]s') > 0",
+		table.IDColumn,
+		table.Column,
+		table.Name,
+		mysqlEscape(from),
+		mysqlEscape(jsonEscapeSlashes(from)),
+	))
+	if err != nil {
+		return fmt.Errorf("cannot query %s: %s: %w", table.Name, strings.TrimSpace(out), err)
+	}
+
+	updates := make([]string, 0)
+
+	for _, row := range dbQueryRowRegex.FindAllStringSubmatch(out, -1) {
+		value := mysqlBatchUnescape(row[2])
+
+		rewritten := rewriteURLValue(value, from, to)
+		if rewritten == value {
+			continue
+		}
+
+		updates = append(updates, fmt.Sprintf(
+			"UPDATE %s SET %s = '%s' WHERE %s = %s;",
+			table.Name, table.Column, mysqlEscape(rewritten), table.IDColumn, row[1],
+		))
+	}
+
+	if dryRun {
+		log.Printf("...%d values would be rewritten.", len(updates))
+
+		return nil
+	}
+
+	if len(updates) > 0 {
+		err = c.runSelfInDir(c.Cwd(), strings.NewReader(strings.Join(updates, "\n")+"\n"), "db", "import")
+		if err != nil {
+			return fmt.Errorf("cannot update %s: %w", table.Name, err)
+		}
+	}
+
+	log.Printf("...%d values rewritten.", len(updates))
+
+	return nil
+}
+
+// urlTable returns the configuration table of the environment type.
+func (c *Client) urlTable() (urlTable, error) {
+	switch c.EnvType() {
+	case "magento1", "magento2":
+		return urlTable{Name: c.DBPrefix() + "core_config_data", IDColumn: "config_id", Column: "value"}, nil
+	case "wordpress":
+		prefix := c.DBPrefix()
+		if prefix == "" {
+			prefix = "wp_"
+		}
+
+		return urlTable{Name: prefix + "options", IDColumn: "option_id", Column: "option_value"}, nil
+	}
+
+	return urlTable{}, ErrRewriteURLsNotSupported(c.EnvType())
+}
+
+// dbQuery runs the query in the database container and returns the rows in tab separated (batch) format. The
+// query is passed in an environment variable to avoid quoting it for the shell.
+func (c *Client) dbQuery(query string) (string, error) {
+	return c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", "-e", "QUERY=" + query, c.DBContainer(), "sh", "-c",
+		fmt.Sprintf(`MYSQL_PWD="$MYSQL_PASSWORD" %s -u"$MYSQL_USER" "$MYSQL_DATABASE" -N -B -e "$QUERY"`, c.DBCommand()),
+	})
+}
+
+// rewriteURLValue replaces the from URL with the to URL in the value. The lengths of the PHP serialized strings are
+// updated and the JSON encoded (escaped slashes) URLs are replaced as well.
+func rewriteURLValue(value, from, to string) string {
+	rewritten, err := replaceSerialized(value, func(s string) string {
+		return rewriteURLValue(s, from, to)
+	})
+	if err == nil {
+		return rewritten
+	}
+
+	value = replaceURL(value, from, to)
+
+	return replaceURL(value, jsonEscapeSlashes(from), jsonEscapeSlashes(to))
+}
+
+// replaceURL replaces the occurrences of the from URL which are not followed by a hostname character, so
+// https://example.com doesn't match https://example.com.au.
+func replaceURL(s, from, to string) string {
+	if from == "" {
+		return s
+	}
+
+	var b strings.Builder
+
+	for {
+		i := strings.Index(s, from)
+		if i < 0 {
+			b.WriteString(s)
+
+			return b.String()
+		}
+
+		end := i + len(from)
+		if end < len(s) && isHostnameChar(from[len(from)-1]) && isHostnameChar(s[end]) {
+			b.WriteString(s[:end])
+			s = s[end:]
+
+			continue
+		}
+
+		b.WriteString(s[:i])
+		b.WriteString(to)
+		s = s[end:]
+	}
+}
+
+func isHostnameChar(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '.'
+}
+
+func jsonEscapeSlashes(s string) string {
+	return strings.ReplaceAll(s, "/", `\/`)
+}
+
+// replaceSerialized applies replace to the strings of the PHP serialized value and updates their lengths.
+func replaceSerialized(s string, replace func(string) string) (string, error) {
+	var b strings.Builder
+
+	rest, err := rewriteSerialized(s, replace, &b)
+	if err != nil {
+		return "", err
+	}
+
+	if rest != "" {
+		return "", errInvalidSerializedValue
+	}
+
+	return b.String(), nil
+}
+
+// rewriteSerialized writes the first serialized value of s to b and returns the rest of s.
+func rewriteSerialized(s string, replace func(string) string, b *strings.Builder) (string, error) {
+	if len(s) < 2 {
+		return "", errInvalidSerializedValue
+	}
+
+	switch s[0] {
+	case 'N':
+		if s[1] != ';' {
+			return "", errInvalidSerializedValue
+		}
+
+		b.WriteString("N;")
+
+		return s[2:], nil
+	case 'b', 'i', 'd', 'r', 'R':
+		end := strings.IndexByte(s, ';')
+		if s[1] != ':' || end < 0 {
+			return "", errInvalidSerializedValue
+		}
+
+		b.WriteString(s[:end+1])
+
+		return s[end+1:], nil
+	case 's':
+		str, rest, err := readSerializedString(s[1:], ';')
+		if err != nil {
+			return "", err
+		}
+
+		str = replace(str)
+		fmt.Fprintf(b, `s:%d:"%s";`, len(str), str)
+
+		return rest, nil
+	case 'E':
+		_, rest, err := readSerializedString(s[1:], ';')
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(s[:len(s)-len(rest)])
+
+		return rest, nil
+	case 'a':
+		n, rest, err := readSerializedCount(s[1:])
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(b, "a:%d:", n)
+
+		return rewriteSerializedMembers(rest, n, replace, b)
+	case 'O':
+		class, rest, err := readSerializedString(s[1:], ':')
+		if err != nil {
+			return "", err
+		}
+
+		n, rest, err := readSerializedCount(":" + rest)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(b, `O:%d:"%s":%d:`, len(class), class, n)
+
+		return rewriteSerializedMembers(rest, n, replace, b)
+	case 'C':
+		// Custom serialized objects contain raw data which is copied as it is.
+		_, rest, err := readSerializedString(s[1:], ':')
+		if err != nil {
+			return "", err
+		}
+
+		n, rest, err := readSerializedCount(":" + rest)
+		if err != nil || len(rest) < n+2 || rest[0] != '{' || rest[n+1] != '}' {
+			return "", errInvalidSerializedValue
+		}
+
+		rest = rest[n+2:]
+		b.WriteString(s[:len(s)-len(rest)])
+
+		return rest, nil
+	}
+
+	return "", errInvalidSerializedValue
+}
+
+// rewriteSerializedMembers writes the n key-value pairs of an array or an object enclosed in braces.
+func rewriteSerializedMembers(s string, n int, replace func(string) string, b *strings.Builder) (string, error) {
+	if s == "" || s[0] != '{' {
+		return "", errInvalidSerializedValue
+	}
+
+	b.WriteByte('{')
+
+	rest := s[1:]
+
+	for i := 0; i < n*2; i++ {
+		var err error
+
+		rest, err = rewriteSerialized(rest, replace, b)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if rest == "" || rest[0] != '}' {
+		return "", errInvalidSerializedValue
+	}
+
+	b.WriteByte('}')
+
+	return rest[1:], nil
+}
+
+// readSerializedCount reads the ":N:" part of a serialized value.
+func readSerializedCount(s string) (int, string, error) {
+	if s == "" || s[0] != ':' {
+		return 0, "", errInvalidSerializedValue
+	}
+
+	end := strings.IndexByte(s[1:], ':')
+	if end < 0 {
+		return 0, "", errInvalidSerializedValue
+	}
+
+	n, err := strconv.Atoi(s[1 : end+1])
+	if err != nil || n < 0 {
+		return 0, "", errInvalidSerializedValue
+	}
+
+	return n, s[end+2:], nil
+}
+
+// readSerializedString reads the `:N:"..."` part of a serialized value followed by the terminator.
+func readSerializedString(s string, terminator byte) (string, string, error) {
+	n, rest, err := readSerializedCount(s)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(rest) < n+3 || rest[0] != '"' || rest[n+1] != '"' || rest[n+2] != terminator {
+		return "", "", errInvalidSerializedValue
+	}
+
+	return rest[1 : n+1], rest[n+3:], nil
+}
+
+// mysqlEscape escapes the string to be used in a single-quoted MySQL string literal.
+func mysqlEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`'`, `\'`,
+		"\x00", `\0`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\x1a", `\Z`,
+	).Replace(s)
+}
+
+// mysqlBatchUnescape reverts the escaping of the values in the output of mysql's batch mode.
+func mysqlBatchUnescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t", `\0`, "\x00").Replace(s)
+}
@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DBRewriteTestSuite struct {
+	suite.Suite
+}
+
+func TestDBRewriteTestSuite(t *testing.T) {
+	suite.Run(t, new(DBRewriteTestSuite))
+}
+
+func (suite *DBRewriteTestSuite) TestRewriteURLValue() {
+	from, to := "https://www.example.com", "https://project.test"
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "plain",
+			value: "https://www.example.com/",
+			want:  "https://project.test/",
+		},
+		{
+			name:  "other domain with the same prefix",
+			value: "https://www.example.com.au/",
+			want:  "https://www.example.com.au/",
+		},
+		{
+			name:  "json",
+			value: `{"url":"https:\/\/www.example.com\/media\/"}`,
+			want:  `{"url":"https:\/\/project.test\/media\/"}`,
+		},
+		{
+			name:  "serialized array",
+			value: `a:2:{s:4:"home";s:24:"https://www.example.com/";i:0;b:1;}`,
+			want:  `a:2:{s:4:"home";s:21:"https://project.test/";i:0;b:1;}`,
+		},
+		{
+			name:  "serialized object",
+			value: `O:8:"stdClass":1:{s:3:"url";s:23:"https://www.example.com";}`,
+			want:  `O:8:"stdClass":1:{s:3:"url";s:20:"https://project.test";}`,
+		},
+		{
+			name:  "double serialized",
+			value: `s:41:"a:1:{i:0;s:23:"https://www.example.com";}";`,
+			want:  `s:38:"a:1:{i:0;s:20:"https://project.test";}";`,
+		},
+		{
+			name:  "invalid serialized length is replaced as plain text",
+			value: `s:99:"https://www.example.com";`,
+			want:  `s:99:"https://project.test";`,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rewriteURLValue(tt.value, from, to))
+		})
+	}
+}
+
+func (suite *DBRewriteTestSuite) TestMySQLEscape() {
+	value := "it's a \\ test\nline"
+
+	suite.Equal(`it\'s a \\ test\nline`, mysqlEscape(value))
+	suite.Equal("a\\b\nc\td", mysqlBatchUnescape(`a\\b\nc\td`))
+}
@@ -0,0 +1,49 @@
+package logic
+
+import (
+	"fmt"
+)
+
+// dbTemplateDefaults are the default user, password and database of the db service templates of the environment
+// types. The environment types which are not listed use the defaults of the includes/db.base.yml template.
+var dbTemplateDefaults = map[string]map[string]string{
+	"akeneo":    {"user": "akeneo_pim", "password": "akeneo_pim", "database": "akeneo_pim"},
+	"craft":     {"user": "craft", "password": "craft", "database": "craft"},
+	"laravel":   {"user": "laravel", "password": "laravel", "database": "laravel"},
+	"magento1":  {"user": "magento", "password": "magento", "database": "magento"},
+	"magento2":  {"user": "magento", "password": "magento", "database": "magento"},
+	"oro":       {"user": "oro", "password": "oro", "database": "oro"},
+	"shopware":  {"user": "app", "password": "app", "database": "shopware"},
+	"sylius":    {"user": "sylius", "password": "sylius", "database": "sylius"},
+	"symfony":   {"user": "symfony", "password": "symfony", "database": "symfony"},
+	"typo3":     {"user": "typo3", "password": "typo3", "database": "typo3"},
+	"wordpress": {"user": "wordpress", "password": "wordpress", "database": "wordpress"},
+}
+
+// dbPort returns the port the database listens on in its container.
+func (c *Client) dbPort() int {
+	if c.dbPostgres() {
+		return 5432
+	}
+
+	return 3306
+}
+
+// dbSetting returns the effective user, password or database (key) of the db service: the MYSQL_* (or POSTGRES_* for
+// PostgreSQL) setting of the environment, or the default of the db service template of the environment type.
+func (c *Client) dbSetting(key string) string {
+	prefix := "mysql"
+	if c.dbPostgres() {
+		prefix = "postgres"
+	}
+
+	if value := c.GetString(fmt.Sprintf("%s_%s", prefix, key)); value != "" {
+		return value
+	}
+
+	if defaults, ok := dbTemplateDefaults[c.EnvType()]; ok {
+		return defaults[key]
+	}
+
+	return "app"
+}
@@ -0,0 +1,196 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// dbUpgradeReadyTimeout is the time the database container of the new version has to accept connections. The
+// initialization of the data directory of the new version takes longer than a restart.
+const dbUpgradeReadyTimeout = 5 * time.Minute
+
+// ErrDBUpgradeSameVersion occurs when the database already runs the requested version.
+var ErrDBUpgradeSameVersion = func(v string) error {
+	return fmt.Errorf("the database already runs mariadb %s", v)
+}
+
+// RunCmdDBUpgrade recreates the database of the environment with another MariaDB version. The data directory of a
+// MariaDB version is not compatible with the other major versions, so the database is dumped with the running
+// version, the volume is recreated and the dump is imported with the new version. The data directory is copied to a
+// snapshot volume beforehand, so the old version can be restored if the upgrade fails.
+func (c *Client) RunCmdDBUpgrade(cmd *cobra.Command) error {
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return fmt.Errorf("failed to get flag: %w", err)
+	}
+
+	from := c.GetString("mariadb_version")
+	if from == "" {
+		// the default version of the db service template
+		from = "10.4"
+	}
+
+	if from == to {
+		return ErrDBUpgradeSameVersion(to)
+	}
+
+	warnings, err := c.ValidateEnvSetting("MARIADB_VERSION", to)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, warning := range warnings {
+		log.Warnln(warning)
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf(
+		"The database of %s is going to be recreated with MariaDB %s. Continue?", c.EnvName(), to),
+	) {
+		return nil
+	}
+
+	var (
+		stamp    = time.Now().Format("20060102150405")
+		volume   = c.EnvName() + "_dbdata"
+		snapshot = fmt.Sprintf("%s_mariadb%s_%s", volume, from, stamp)
+		dumpFile = filepath.Join(
+			c.Cwd(), "."+c.AppName(), "db-upgrade", fmt.Sprintf("%s-mariadb%s-%s.sql.gz", c.EnvName(), from, stamp),
+		)
+		steps = c.newProgress()
+	)
+
+	steps.SetTotalSteps(5)
+
+	step := steps.OutputStep(fmt.Sprintf("Dumping the database with MariaDB %s", from))
+
+	err = step.Finish(c.dumpDatabaseToFile(dumpFile))
+	if err != nil {
+		return fmt.Errorf("cannot dump the database: %w", err)
+	}
+
+	err = steps.OutputStep("Creating a snapshot of the database volume").Finish(c.dbUpgradeSnapshot(volume, snapshot))
+	if err != nil {
+		return fmt.Errorf("cannot create a snapshot of the database volume: %w", err)
+	}
+
+	err = steps.OutputStep("Removing the database volume").Finish(c.dbUpgradeRemoveVolume(volume))
+	if err != nil {
+		return fmt.Errorf("cannot remove the database volume, the snapshot is kept in the %s volume: %w", snapshot, err)
+	}
+
+	err = steps.OutputStep(fmt.Sprintf("Starting the database with MariaDB %s", to)).Finish(c.dbUpgradeStart(to))
+	if err != nil {
+		return fmt.Errorf(
+			"cannot start the database, the dump is kept in %s, the snapshot in the %s volume: %w", dumpFile, snapshot, err,
+		)
+	}
+
+	err = steps.OutputStep("Importing the database").Finish(c.dbUpgradeImport(dumpFile))
+	if err != nil {
+		return fmt.Errorf(
+			"cannot import the database, the dump is kept in %s, the snapshot in the %s volume: %w", dumpFile, snapshot, err,
+		)
+	}
+
+	log.Printf("...database upgraded to MariaDB %s.", to)
+	log.Printf("The dump is kept in %s and the old data directory in the %s volume, remove them if they're not "+
+		"needed anymore.", dumpFile, snapshot)
+
+	return nil
+}
+
+// dumpDatabaseToFile dumps the database as root to the gzip compressed file, using `db dump` of the application
+// itself.
+func (c *Client) dumpDatabaseToFile(file string) error {
+	err := util.CreateDir(filepath.Dir(file), nil)
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	out, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", file, err)
+	}
+	defer out.Close()
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	dump := cmdpkg.Cmnd(self, "db", "dump", "--root", "--compress", "gzip")
+	dump.Dir = c.Cwd()
+	dump.Stdout = out
+	dump.Stderr = os.Stderr
+
+	return dump.Run()
+}
+
+// dbUpgradeSnapshot stops the database and copies its volume to the snapshot volume.
+func (c *Client) dbUpgradeSnapshot(volume, snapshot string) error {
+	err := c.runSelfInDir(c.Cwd(), nil, "env", "stop", c.DBContainer())
+	if err != nil {
+		return fmt.Errorf("cannot stop the database: %w", err)
+	}
+
+	err = cmdpkg.Cmnd("docker", "volume", "create", snapshot).Run()
+	if err != nil {
+		return fmt.Errorf("cannot create volume %s: %w", snapshot, err)
+	}
+
+	copyCmd := cmdpkg.Cmnd("docker", "run", "--rm",
+		"-v", volume+":/from:ro",
+		"-v", snapshot+":/to",
+		"alpine", "sh", "-c", "cp -a /from/. /to/",
+	)
+	copyCmd.Stderr = os.Stderr
+
+	return copyCmd.Run()
+}
+
+// dbUpgradeRemoveVolume removes the database container and its volume.
+func (c *Client) dbUpgradeRemoveVolume(volume string) error {
+	err := c.runSelfInDir(c.Cwd(), nil, "env", "rm", "--force", "--stop", c.DBContainer())
+	if err != nil {
+		return fmt.Errorf("cannot remove the database container: %w", err)
+	}
+
+	rm := cmdpkg.Cmnd("docker", "volume", "rm", volume)
+	rm.Stderr = os.Stderr
+
+	return rm.Run()
+}
+
+// dbUpgradeStart sets the new version in the .env file, starts the database and waits until it accepts connections.
+func (c *Client) dbUpgradeStart(to string) error {
+	err := setEnvFileValues(filepath.Join(c.Cwd(), ".env"), [][2]string{{"MARIADB_VERSION", to}})
+	if err != nil {
+		return err
+	}
+
+	err = c.runSelfInDir(c.Cwd(), nil, "env", "up", "-d", c.DBContainer())
+	if err != nil {
+		return fmt.Errorf("cannot start the database: %w", err)
+	}
+
+	return c.waitForDBReady(dbUpgradeReadyTimeout)
+}
+
+// dbUpgradeImport imports the dump into the database of the new version.
+func (c *Client) dbUpgradeImport(file string) error {
+	dump, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %w", file, err)
+	}
+	defer dump.Close()
+
+	return c.runSelfInDir(c.Cwd(), dump, "db", "import", "--root")
+}
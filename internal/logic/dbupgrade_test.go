@@ -0,0 +1,38 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DBUpgradeTestSuite struct {
+	suite.Suite
+}
+
+func TestDBUpgradeTestSuite(t *testing.T) {
+	suite.Run(t, new(DBUpgradeTestSuite))
+}
+
+func (suite *DBUpgradeTestSuite) TestRunCmdDBUpgradeSameVersion() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		to       string
+	}{
+		{name: "configured version", settings: map[string]interface{}{"mariadb_version": "10.11"}, to: "10.11"},
+		{name: "default version", to: "10.4"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("to", tt.to, "")
+
+			err := newTestClient(tt.settings).RunCmdDBUpgrade(cmd)
+			assert.EqualError(t, err, ErrDBUpgradeSameVersion(tt.to).Error())
+		})
+	}
+}
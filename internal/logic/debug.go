@@ -18,21 +18,19 @@ func (c *Client) RunCmdDebug(cmd *cobra.Command, args []string) error {
 		command = util.ExtractUnknownArgs(cmd.Flags(), []string{"bash"})
 	}
 
-	// debugHost, err := c.Docker.ContainerGatewayInNetwork("php-debug", c.EnvNetworkName())
-	// if err != nil {
-	// 	return fmt.Errorf("error getting debug container gateway: %w", err)
-	// }
+	_, err := c.Docker.RunningEnvServiceContainer("php-debug")
+	if err != nil {
+		return fmt.Errorf("error looking up debug container: %w", err)
+	}
 
 	passedArgs := append(
 		[]string{
 			"exec",
-			// "-e",
-			// fmt.Sprintf("XDEBUG_REMOTE_HOST=%s", debugHost),
 			"php-debug",
 		}, command...,
 	)
 
-	err := c.RunCmdEnvDockerCompose(passedArgs, shell.WithCatchOutput(false))
+	err = c.RunCmdEnvDockerCompose(passedArgs, shell.WithCatchOutput(false))
 	if err != nil {
 		return fmt.Errorf("error running docker compose command: %w", err)
 	}
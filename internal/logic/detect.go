@@ -0,0 +1,374 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrEnvNotInitializedForApply occurs when the detected settings should be applied but the .env file doesn't exist.
+var ErrEnvNotInitializedForApply = fmt.Errorf(
+	"cannot apply the detected settings, the environment is not initialized (run `reward env-init` first)",
+)
+
+// detectedSetting is a setting inferred from the project code.
+type detectedSetting struct {
+	Key    string
+	Value  string
+	Reason string
+}
+
+// detection collects the settings inferred from the project code. The first detection of a setting wins, so the
+// more specific sources should be inspected first.
+type detection struct {
+	settings []detectedSetting
+}
+
+func (d *detection) set(key, value, reason string) {
+	for _, s := range d.settings {
+		if s.Key == key {
+			return
+		}
+	}
+
+	d.settings = append(d.settings, detectedSetting{Key: key, Value: value, Reason: reason})
+}
+
+// frameworkPackages maps composer packages to the environment type they require.
+var frameworkPackages = []struct {
+	pkg     string
+	envType string
+}{
+	{"magento/product-community-edition", "magento2"},
+	{"magento/product-enterprise-edition", "magento2"},
+	{"magento/magento2-base", "magento2"},
+	{"mage-os/product-community-edition", "magento2"},
+	{"shopware/core", "shopware"},
+	{"shopware/platform", "shopware"},
+	{"laravel/framework", "laravel"},
+	{"oro/commerce", "oro"},
+	{"oro/platform", "oro"},
+	{"typo3/cms-core", "typo3"},
+	{"craftcms/cms", "craft"},
+	{"symfony/framework-bundle", "symfony"},
+	{"johnpbloch/wordpress", "wordpress"},
+	{"roots/wordpress", "wordpress"},
+}
+
+// RunCmdDetect inspects the project code to infer the required services and versions, prints the proposed
+// configuration changes and optionally writes them to the .env file.
+func (c *Client) RunCmdDetect(cmd *cmdpkg.Command) error {
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	log.Debugln("Detecting required services...")
+
+	d := &detection{}
+	prefix := strings.ToUpper(c.AppName())
+
+	c.detectFromComposer(d, prefix)
+	c.detectFromMagentoEnvPHP(d, prefix)
+	c.detectFromWPConfig(d, prefix)
+	c.detectFromEnvExample(d, prefix)
+
+	if len(d.settings) == 0 {
+		log.Println("Cannot detect any requirements from the project code.")
+
+		return nil
+	}
+
+	changes := make([][2]string, 0, len(d.settings))
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Setting", "Current", "Proposed", "Reason"})
+
+	for _, s := range d.settings {
+		current := c.GetString(strings.ToLower(s.Key))
+		if current != s.Value {
+			changes = append(changes, [2]string{s.Key, s.Value})
+		}
+
+		t.AppendRow(table.Row{s.Key, current, s.Value, s.Reason})
+	}
+
+	t.Render()
+
+	if len(changes) == 0 {
+		log.Println("The environment configuration matches the detected requirements.")
+
+		return nil
+	}
+
+	if !apply {
+		log.Printf("Run `%s detect --apply` to write the proposed settings to the .env file.", c.AppName())
+
+		return nil
+	}
+
+	if !c.EnvInitialized() {
+		return ErrEnvNotInitializedForApply
+	}
+
+	err := setEnvFileValues(filepath.Join(c.Cwd(), ".env"), changes)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("...%d settings updated in the .env file.", len(changes))
+
+	return nil
+}
+
+func (c *Client) detectFromComposer(d *detection, prefix string) {
+	pkgs, err := c.ComposerPackages()
+	if err != nil {
+		log.Debugf("Cannot read composer packages: %s", err)
+
+		return
+	}
+
+	if pkgs == nil {
+		return
+	}
+
+	// The locked versions are more precise than the constraints.
+	requires := func(names ...string) (string, string, bool) {
+		name, ver, _, ok := pkgs.Find(names...)
+
+		return name, ver, ok
+	}
+
+	for _, f := range frameworkPackages {
+		if _, _, ok := requires(f.pkg); ok {
+			d.set(prefix+"_ENV_TYPE", f.envType, fmt.Sprintf("composer.json requires %s", f.pkg))
+
+			break
+		}
+	}
+
+	c.detectMagentoServices(d, prefix)
+
+	services := c.VersionMatrix().Services
+
+	if constraint, ok := pkgs.Require["php"]; ok {
+		if v := highestVersionInConstraint(constraint, services["PHP_VERSION"]); v != "" {
+			d.set("PHP_VERSION", v, fmt.Sprintf("composer.json requires php %s", constraint))
+		}
+	}
+
+	if pkg, _, ok := requires("predis/predis", "ext-redis", "colinmollenhour/cache-backend-redis"); ok {
+		d.set(prefix+"_REDIS", "true", fmt.Sprintf("composer.json requires %s", pkg))
+	}
+
+	if pkg, _, ok := requires("php-amqplib/php-amqplib", "enqueue/amqp-lib", "ext-amqp"); ok {
+		d.set(prefix+"_RABBITMQ", "true", fmt.Sprintf("composer.json requires %s", pkg))
+	}
+
+	if pkg, _, ok := requires("opensearch-project/opensearch-php"); ok {
+		d.set(prefix+"_OPENSEARCH", "true", fmt.Sprintf("composer.json requires %s", pkg))
+	}
+
+	if pkg, constraint, ok := requires("elasticsearch/elasticsearch"); ok {
+		d.set(prefix+"_ELASTICSEARCH", "true", fmt.Sprintf("composer.json requires %s", pkg))
+
+		if v := highestVersionInConstraint(constraint, services["ELASTICSEARCH_VERSION"]); v != "" {
+			d.set("ELASTICSEARCH_VERSION", v, fmt.Sprintf("composer.json requires %s %s", pkg, constraint))
+		}
+	}
+}
+
+// detectMagentoServices proposes the service versions required by the Magento version found in composer.
+func (c *Client) detectMagentoServices(d *detection, prefix string) {
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		log.Debugf("Cannot determine Magento version: %s", err)
+
+		return
+	}
+
+	if info.Source == config.MagentoVersionSourceConfig {
+		return
+	}
+
+	reason := fmt.Sprintf("%s %s in %s", info.Package, info.Version, info.Source)
+
+	d.set(prefix+"_MAGENTO_VERSION", info.Version.String(), reason)
+
+	switch info.Edition {
+	case "enterprise", "cloud":
+		d.set(prefix+"_MAGENTO_TYPE", "enterprise", reason)
+	case "mage-os":
+		d.set(prefix+"_MAGENTO_TYPE", "mage-os", reason)
+	}
+
+	services := c.VersionMatrix().MagentoServices(info.MagentoVersion)
+	if services == nil {
+		return
+	}
+
+	keys := make([]string, 0, len(services))
+	for k := range services {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		d.set(k, services[k], reason)
+	}
+
+	_, es := services["ELASTICSEARCH_VERSION"]
+	d.set(prefix+"_ELASTICSEARCH", fmt.Sprint(es), reason)
+	d.set(prefix+"_OPENSEARCH", fmt.Sprint(!es), reason)
+	d.set(prefix+"_RABBITMQ", "true", reason)
+	d.set(prefix+"_REDIS", "true", reason)
+	d.set(prefix+"_VARNISH", "true", reason)
+}
+
+func (c *Client) detectFromMagentoEnvPHP(d *detection, prefix string) {
+	const path = "app/etc/env.php"
+
+	file := filepath.Join(c.Cwd(), c.WebRoot(), path)
+	if !util.FileExists(file) {
+		return
+	}
+
+	if ok, _ := util.CheckRegexInFile(`Magento\\\\Framework\\\\Cache\\\\Backend\\\\Redis|Cm_Cache_Backend_Redis|`+
+		`'save'\s*=>\s*'redis'`, file); ok {
+		d.set(prefix+"_REDIS", "true", path+" uses redis")
+	}
+
+	if ok, _ := util.CheckRegexInFile(`'amqp'\s*=>`, file); ok {
+		d.set(prefix+"_RABBITMQ", "true", path+" configures amqp queues")
+	}
+
+	if ok, _ := util.CheckRegexInFile(`'http_cache_hosts'\s*=>`, file); ok {
+		d.set(prefix+"_VARNISH", "true", path+" configures http cache hosts")
+	}
+}
+
+func (c *Client) detectFromWPConfig(d *detection, prefix string) {
+	const path = "wp-config.php"
+
+	file := filepath.Join(c.Cwd(), c.WebRoot(), path)
+	if !util.FileExists(file) {
+		return
+	}
+
+	d.set(prefix+"_ENV_TYPE", "wordpress", path+" exists")
+
+	if ok, _ := util.CheckRegexInFile(`WP_REDIS_(HOST|SERVERS)`, file); ok {
+		d.set(prefix+"_REDIS", "true", path+" configures redis")
+	}
+}
+
+func (c *Client) detectFromEnvExample(d *detection, prefix string) {
+	const path = ".env.example"
+
+	file := filepath.Join(c.Cwd(), c.WebRoot(), path)
+	if !util.FileExists(file) {
+		return
+	}
+
+	checks := []struct {
+		regex string
+		key   string
+	}{
+		{`(?m)^REDIS_HOST=`, prefix + "_REDIS"},
+		{`(?m)^(RABBITMQ_HOST=|QUEUE_CONNECTION=rabbitmq)`, prefix + "_RABBITMQ"},
+		{`(?m)^(ELASTICSEARCH_HOST|ELASTICSEARCH_HOSTS|SCOUT_DRIVER=elastic)`, prefix + "_ELASTICSEARCH"},
+		{`(?m)^OPENSEARCH_HOST`, prefix + "_OPENSEARCH"},
+		{`(?m)^DB_CONNECTION=(mysql|mariadb)`, prefix + "_DB"},
+		{`(?m)^MERCURE_URL=`, prefix + "_MERCURE"},
+	}
+
+	for _, check := range checks {
+		if ok, _ := util.CheckRegexInFile(check.regex, file); ok {
+			d.set(check.key, "true", path+" references the service")
+		}
+	}
+}
+
+// highestVersionInConstraint returns the highest major.minor version which satisfies a composer version constraint
+// (eg. "~8.1.0||~8.2.0" returns "8.2", ">=8.1 <8.3" returns "8.2"). The supported versions are checked, if there are
+// none, the minor versions up to the highest one mentioned in the constraint are checked.
+func highestVersionInConstraint(constraint string, supported []string) string {
+	mentioned := make([]*version.Version, 0)
+
+	for _, m := range regexp.MustCompile(`\d+(?:\.\d+){1,2}`).FindAllString(constraint, -1) {
+		if v, err := version.NewVersion(m); err == nil {
+			mentioned = append(mentioned, v)
+		}
+	}
+
+	if len(supported) == 0 {
+		maxMinor := make(map[int]int)
+
+		for _, v := range mentioned {
+			if s := v.Segments(); s[1] >= maxMinor[s[0]] {
+				maxMinor[s[0]] = s[1]
+			}
+		}
+
+		for major, minor := range maxMinor {
+			for i := 0; i <= minor; i++ {
+				supported = append(supported, fmt.Sprintf("%d.%d", major, i))
+			}
+		}
+	}
+
+	var highest *version.Version
+
+	for _, s := range supported {
+		v, err := version.NewVersion(s)
+		if err != nil || (highest != nil && !v.GreaterThan(highest)) {
+			continue
+		}
+
+		if minorSatisfiesConstraint(v, mentioned, constraint) {
+			highest = v
+		}
+	}
+
+	if highest == nil {
+		return ""
+	}
+
+	segments := highest.Segments()
+
+	return fmt.Sprintf("%d.%d", segments[0], segments[1])
+}
+
+// minorSatisfiesConstraint returns true if any release of the major.minor version can satisfy the constraint. The
+// first and the last patch releases are checked along with the patch releases mentioned in the constraint.
+func minorSatisfiesConstraint(v *version.Version, mentioned []*version.Version, constraint string) bool {
+	segments := v.Segments()
+	candidates := []string{
+		fmt.Sprintf("%d.%d.0", segments[0], segments[1]),
+		fmt.Sprintf("%d.%d.%d", segments[0], segments[1], math.MaxInt32),
+	}
+
+	for _, m := range mentioned {
+		if s := m.Segments(); s[0] == segments[0] && s[1] == segments[1] {
+			candidates = append(candidates, m.String())
+		}
+	}
+
+	for _, c := range candidates {
+		if cv, err := version.NewVersion(c); err == nil && config.VersionSatisfiesConstraint(cv, constraint) {
+			return true
+		}
+	}
+
+	return false
+}
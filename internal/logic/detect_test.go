@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type DetectTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DetectTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestDetectTestSuite(t *testing.T) {
+	suite.Run(t, new(DetectTestSuite))
+}
+
+func (suite *DetectTestSuite) TestHighestVersionInConstraint() {
+	php := []string{"7.3", "7.4", "8.0", "8.1", "8.2"}
+
+	tests := []struct {
+		name       string
+		constraint string
+		supported  []string
+		want       string
+	}{
+		{name: "or", constraint: "~8.1.0||~8.2.0", supported: php, want: "8.2"},
+		{name: "exclusive upper bound", constraint: ">=8.1 <8.3", supported: php, want: "8.2"},
+		{name: "exclusive upper bound without supported versions", constraint: ">=8.1 <8.3", want: "8.2"},
+		{name: "caret", constraint: "^7.4", supported: php, want: "7.4"},
+		{name: "caret on major", constraint: "^7.3 || ^8.0", supported: php, want: "8.2"},
+		{name: "tilde patch", constraint: "~7.4.10", supported: php, want: "7.4"},
+		{name: "wildcard", constraint: "8.1.*", supported: php, want: "8.1"},
+		{name: "nothing satisfies", constraint: ">=9.0", supported: php, want: ""},
+		{name: "invalid", constraint: "dev-master", supported: php, want: ""},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, highestVersionInConstraint(tt.constraint, tt.supported))
+		})
+	}
+}
+
+func (suite *DetectTestSuite) TestDetectMagentoServices() {
+	tests := []struct {
+		name       string
+		constraint string
+		want       string
+	}{
+		{name: "tilde", constraint: "~2.4", want: "2.4.0"},
+		{name: "wildcard", constraint: "2.4.*", want: "2.4.0"},
+		{name: "exact patch", constraint: "2.4.6-p3", want: "2.4.6-p3"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			suite.SetupTest()
+
+			c := newTestClient(nil)
+			_ = config.FS.WriteFile(
+				filepath.Join(c.Cwd(), "composer.json"),
+				[]byte(`{"require": {"magento/product-community-edition": "`+tt.constraint+`"}}`),
+				os.FileMode(0o644),
+			)
+
+			d := &detection{}
+			c.detectMagentoServices(d, "REWARD")
+
+			got := make(map[string]string)
+			for _, s := range d.settings {
+				got[s.Key] = s.Value
+			}
+
+			assert.Equal(t, tt.want, got["REWARD_MAGENTO_VERSION"])
+			assert.NotEmpty(t, got["PHP_VERSION"])
+		})
+	}
+}
+
+func (suite *DetectTestSuite) TestDetectFromWebRoot() {
+	c := newTestClient(map[string]interface{}{"reward_web_root": "src"})
+
+	_ = config.FS.WriteFile(
+		filepath.Join(c.Cwd(), "src", "app", "etc", "env.php"),
+		[]byte(`'backend' => 'Magento\\Framework\\Cache\\Backend\\Redis', 'amqp' => [`),
+		os.FileMode(0o644),
+	)
+	_ = config.FS.WriteFile(
+		filepath.Join(c.Cwd(), "src", ".env.example"),
+		[]byte("REDIS_HOST=redis\nOPENSEARCH_HOST=opensearch\n"),
+		os.FileMode(0o644),
+	)
+
+	d := &detection{}
+	c.detectFromMagentoEnvPHP(d, "REWARD")
+	c.detectFromWPConfig(d, "REWARD")
+	c.detectFromEnvExample(d, "REWARD")
+
+	got := make(map[string]string)
+	for _, s := range d.settings {
+		got[s.Key] = s.Value
+	}
+
+	assert.Equal(suite.T(), map[string]string{
+		"REWARD_REDIS":      "true",
+		"REWARD_RABBITMQ":   "true",
+		"REWARD_OPENSEARCH": "true",
+	}, got)
+}
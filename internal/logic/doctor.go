@@ -0,0 +1,303 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	cryptopkg "github.com/rewardenv/reward/internal/crypto"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// The statuses of the doctor checks.
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorError   = "error"
+)
+
+// doctorTimeout is the timeout of the network checks of doctor.
+const doctorTimeout = 2 * time.Second
+
+// doctorMaxPaths is the number of paths listed in the result of the file permission check.
+const doctorMaxPaths = 3
+
+// ErrDoctorFailed occurs when some of the doctor checks fail.
+var ErrDoctorFailed = func(n int) error {
+	return fmt.Errorf("%d check(s) failed", n)
+}
+
+// doctorResult is the result of a doctor check.
+type doctorResult struct {
+	Status string
+	Result string
+	// Fix describes how to fix the problem.
+	Fix string
+}
+
+// doctorCheck is a check of the doctor command.
+type doctorCheck struct {
+	Name string
+	Run  func() doctorResult
+}
+
+// RunCmdDoctor checks the common causes of problems: the docker engine and docker compose, the CA certificate, the
+// DNS resolution of the .test domains, mutagen, the conflicts of the http and https ports, the file permissions in
+// the application home directory and the service versions of the environment. The failed checks are printed with
+// the way to fix them. With --strict the warnings fail as well.
+func (c *Client) RunCmdDoctor(cmd *cmdpkg.Command) error {
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	tb := c.newTable()
+	tb.AppendHeader(table.Row{"Check", "Status", "Result", "Fix"})
+
+	var failed int
+
+	for _, check := range c.doctorChecks() {
+		result := check.Run()
+		if result.Status == doctorError || strict && result.Status == doctorWarning {
+			failed++
+		}
+
+		tb.AppendRow(table.Row{check.Name, result.Status, result.Result, result.Fix})
+	}
+
+	tb.Render()
+
+	if failed > 0 {
+		return ErrDoctorFailed(failed)
+	}
+
+	return nil
+}
+
+func (c *Client) doctorChecks() []doctorCheck {
+	checks := []doctorCheck{
+		{Name: "Docker", Run: func() doctorResult { return c.doctorComponent("docker") }},
+		{Name: "Docker Compose", Run: func() doctorResult { return c.doctorComponent("docker-compose") }},
+		{Name: "CA certificate", Run: c.doctorCACertificate},
+		{Name: "DNS", Run: c.doctorDNS},
+		{Name: "Mutagen", Run: c.doctorMutagen},
+		{Name: "HTTP port", Run: func() doctorResult { return c.doctorPort(c.TraefikHTTPPort()) }},
+		{Name: "HTTPS port", Run: func() doctorResult { return c.doctorPort(c.TraefikHTTPSPort()) }},
+		{Name: "Home permissions", Run: func() doctorResult { return doctorHomePermissions(c.AppName(), c.AppHomeDir()) }},
+	}
+
+	if c.EnvInitialized() {
+		checks = append(checks, doctorCheck{Name: "Service versions", Run: c.doctorServiceVersions})
+	}
+
+	return checks
+}
+
+// doctorComponent checks if the docker engine (or docker compose) is reachable and its version is supported.
+func (c *Client) doctorComponent(name string) doctorResult {
+	if name == "docker" && c.Docker == nil {
+		return doctorResult{
+			Status: doctorError,
+			Result: "cannot create docker client",
+			Fix:    "check the DOCKER_HOST environment variable and the reward_docker_host setting",
+		}
+	}
+
+	installed, untested, err := c.CheckComponentVersion(name)
+	if err != nil {
+		fix := fmt.Sprintf("upgrade %s", name)
+		if installed == nil {
+			fix = fmt.Sprintf("install %s and make sure it's running and reachable by the current user", name)
+		}
+
+		return doctorResult{Status: doctorError, Result: err.Error(), Fix: fix}
+	}
+
+	if untested != "" {
+		return doctorResult{
+			Status: doctorWarning,
+			Result: fmt.Sprintf("version %s has not been tested yet (tested versions: %s)", installed, untested),
+			Fix:    "please report any issues",
+		}
+	}
+
+	return doctorResult{Status: doctorOK, Result: fmt.Sprintf("version %s", installed)}
+}
+
+// doctorCACertificate checks if the CA certificate which signs the certificates of the environments exists.
+func (c *Client) doctorCACertificate() doctorResult {
+	path, err := cryptopkg.New(c.Config).CACertificateFilePath(c.SSLCADir())
+	if err != nil || !util.FileExists(path) {
+		return doctorResult{
+			Status: doctorError,
+			Result: "the CA certificate doesn't exist",
+			Fix:    fmt.Sprintf("run `%s install`", c.AppName()),
+		}
+	}
+
+	return doctorResult{Status: doctorOK, Result: path}
+}
+
+// doctorDNS checks if the .test domains (eg. the service domain) are resolved to the local host.
+func (c *Client) doctorDNS() doctorResult {
+	host := "doctor." + c.ServiceDomain()
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return doctorResult{
+			Status: doctorError,
+			Result: fmt.Sprintf("cannot resolve %s", host),
+			Fix: fmt.Sprintf(
+				"run `%s install` to configure the DNS resolver and `%s svc up` to start the DNS service",
+				c.AppName(), c.AppName(),
+			),
+		}
+	}
+
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip == nil || !ip.IsLoopback() {
+			return doctorResult{
+				Status: doctorWarning,
+				Result: fmt.Sprintf("%s is resolved to %s", host, strings.Join(addrs, ", ")),
+				Fix:    "make sure the address reaches traefik, eg. the docker host is remote",
+			}
+		}
+	}
+
+	return doctorResult{Status: doctorOK, Result: fmt.Sprintf("%s is resolved to %s", host, strings.Join(addrs, ", "))}
+}
+
+// doctorMutagen checks if mutagen is installed and its version is supported. Mutagen is only used by the file sync
+// on macOS and Windows.
+func (c *Client) doctorMutagen() doctorResult {
+	if util.OSDistro() != "darwin" && util.OSDistro() != "windows" {
+		return doctorResult{Status: doctorOK, Result: "not used on this platform"}
+	}
+
+	if !util.CommandAvailable(c.mutagenCommand()) && !util.FileExists(c.mutagenCommand()) {
+		return doctorResult{
+			Status: doctorWarning,
+			Result: "mutagen is not installed",
+			Fix:    fmt.Sprintf("it's installed by `%s sync start`", c.AppName()),
+		}
+	}
+
+	installed, err := c.mutagenVersion()
+	if err != nil {
+		return doctorResult{Status: doctorError, Result: err.Error(), Fix: "reinstall mutagen"}
+	}
+
+	v, err := version.NewVersion(installed)
+	if err != nil || v.LessThan(version.Must(version.NewVersion(c.MutagenRequiredVersion()))) {
+		return doctorResult{
+			Status: doctorWarning,
+			Result: fmt.Sprintf("version %s is installed, %s or greater is required", installed,
+				c.MutagenRequiredVersion()),
+			Fix: fmt.Sprintf("run `%s sync self-update`", c.AppName()),
+		}
+	}
+
+	return doctorResult{Status: doctorOK, Result: fmt.Sprintf("version %s", installed)}
+}
+
+// doctorPort checks if the port of traefik is used by another process.
+func (c *Client) doctorPort(port string) doctorResult {
+	if c.Docker != nil {
+		traefik, err := c.Docker.ServiceContainer(c.AppName(), "traefik")
+		if err == nil && traefik.Running() {
+			return doctorResult{Status: doctorOK, Result: fmt.Sprintf("port %s is used by traefik", port)}
+		}
+	}
+
+	return doctorPortFree(port)
+}
+
+// doctorPortFree checks if no process listens on the port of the local host.
+func doctorPortFree(port string) doctorResult {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", port), doctorTimeout)
+	if err != nil {
+		return doctorResult{Status: doctorOK, Result: fmt.Sprintf("port %s is free", port)}
+	}
+
+	_ = conn.Close()
+
+	return doctorResult{
+		Status: doctorError,
+		Result: fmt.Sprintf("port %s is used by another process", port),
+		Fix:    "stop the process (eg. a local web server) or change the traefik ports in the configuration",
+	}
+}
+
+// doctorHomePermissions checks if the application home directory is writable and its files are owned by the user,
+// so they are not created by a command run with sudo.
+func doctorHomePermissions(appName, dir string) doctorResult {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return doctorResult{
+			Status: doctorError,
+			Result: fmt.Sprintf("%s doesn't exist", dir),
+			Fix:    fmt.Sprintf("run `%s install`", appName),
+		}
+	}
+
+	fix := fmt.Sprintf("run `sudo chown -R %d:%d %s`", util.UID(), util.GID(), dir)
+
+	f, err := os.CreateTemp(dir, ".doctor-")
+	if err != nil {
+		return doctorResult{Status: doctorError, Result: fmt.Sprintf("%s is not writable", dir), Fix: fix}
+	}
+
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+
+	var paths []string
+
+	_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			paths = append(paths, path)
+
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err == nil && !util.FileOwnedByUser(info) {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+
+	if len(paths) == 0 {
+		return doctorResult{Status: doctorOK, Result: fmt.Sprintf("%s is writable", dir)}
+	}
+
+	result := fmt.Sprintf("%d file(s) are owned by another user or not readable: ", len(paths))
+	if len(paths) > doctorMaxPaths {
+		paths = append(paths[:doctorMaxPaths], "...")
+	}
+
+	return doctorResult{Status: doctorError, Result: result + strings.Join(paths, ", "), Fix: fix}
+}
+
+// doctorServiceVersions checks if the service versions of the environment are past their end of life or not
+// supported by the Magento version of the project.
+func (c *Client) doctorServiceVersions() doctorResult {
+	warnings := c.ServiceVersionLifecycleWarnings(time.Now())
+	if len(warnings) == 0 {
+		return doctorResult{Status: doctorOK, Result: "the service versions are supported"}
+	}
+
+	return doctorResult{
+		Status: doctorWarning,
+		Result: strings.Join(warnings, "; "),
+		Fix:    fmt.Sprintf("upgrade the services using `%s env set`", c.AppName()),
+	}
+}
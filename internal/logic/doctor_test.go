@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DoctorTestSuite struct {
+	suite.Suite
+}
+
+func TestDoctorTestSuite(t *testing.T) {
+	suite.Run(t, new(DoctorTestSuite))
+}
+
+func (suite *DoctorTestSuite) TestDoctorPortFree() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(suite.T(), err)
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), doctorError, doctorPortFree(port).Status)
+
+	assert.NoError(suite.T(), listener.Close())
+	assert.Equal(suite.T(), doctorOK, doctorPortFree(port).Status)
+}
+
+func (suite *DoctorTestSuite) TestDoctorHomePermissions() {
+	dir := suite.T().TempDir()
+
+	result := doctorHomePermissions("reward", dir)
+	assert.Equal(suite.T(), doctorOK, result.Status)
+	assert.Empty(suite.T(), result.Fix)
+
+	result = doctorHomePermissions("reward", filepath.Join(dir, "missing"))
+	assert.Equal(suite.T(), doctorError, result.Status)
+	assert.Equal(suite.T(), "run `reward install`", result.Fix)
+}
+
+func (suite *DoctorTestSuite) TestDoctorServiceVersions() {
+	c := newTestClient(map[string]interface{}{
+		"reward_env_type": "laravel",
+		"reward_db":       true,
+		"mariadb_version": "10.3",
+	})
+
+	result := c.doctorServiceVersions()
+	assert.Equal(suite.T(), doctorWarning, result.Status)
+	assert.Contains(suite.T(), result.Result, "MARIADB_VERSION=10.3 reached its end of life")
+}
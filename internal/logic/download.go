@@ -0,0 +1,242 @@
+package logic
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrChecksumMismatch occurs when the SHA256 checksum of the downloaded file doesn't match the expected checksum.
+var ErrChecksumMismatch = func(name, want, got string) error {
+	return fmt.Errorf("checksum of %s doesn't match: expected sha256 %s, got %s", name, want, got)
+}
+
+// downloadFile downloads the file from the URL to the download cache and returns the opened file (the caller has to
+// close it). If the checksum (SHA256 in hex) is not empty, the file is verified, and a verified file in the cache is
+// not downloaded again. An interrupted download is resumed from the partial file using a range request.
+func (c *Client) downloadFile(url, name, checksum string) (afero.File, error) {
+	checksum = strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+
+	file := filepath.Join(c.DownloadCacheDir(), downloadCacheName(url, name))
+	if checksum != "" && util.FileExists(file) {
+		if got, err := fileChecksum(file); err == nil && got == checksum {
+			log.Debugf("Using cached download %s...", file)
+
+			return util.FS.Open(file)
+		}
+	}
+
+	step := c.newProgress().Step(fmt.Sprintf("Downloading %s", name))
+
+	err := step.Finish(c.downloadToFile(url, file+".part", step))
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := fileChecksum(file + ".part")
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum == "" {
+		log.Warnf("Cannot verify %s, its checksum is unknown.", name)
+	} else if got != checksum {
+		_ = util.FS.Remove(file + ".part")
+
+		return nil, ErrChecksumMismatch(name, checksum, got)
+	}
+
+	err = util.FS.Rename(file+".part", file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot move downloaded file: %w", err)
+	}
+
+	return util.FS.Open(file)
+}
+
+// downloadToFile downloads the URL to the file and reports the downloaded bytes to the progress. If the file exists,
+// the download is resumed from its end.
+func (c *Client) downloadToFile(url, file string, progress util.Progress) error {
+	err := util.FS.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return fmt.Errorf("cannot create download directory: %w", err)
+	}
+
+	out, err := util.FS.OpenFile(file, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %w", err)
+	}
+	defer out.Close()
+
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("cannot seek file: %w", err)
+	}
+
+	req, err := c.prepareRequest(url, true)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		log.Debugf("Resuming download from byte %d...", offset)
+
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		progress.Start(0, offset+resp.ContentLength)
+		progress.Add(0, offset)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file is complete
+		return nil
+	case http.StatusOK:
+		// the server doesn't support range requests, the file is downloaded from the beginning
+		err = out.Truncate(0)
+		if err == nil {
+			_, err = out.Seek(0, io.SeekStart)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot truncate file: %w", err)
+		}
+
+		progress.Start(0, resp.ContentLength)
+	default:
+		return fmt.Errorf("cannot download %s, http response status: %s", url, resp.Status)
+	}
+
+	_, err = io.Copy(out, util.ProgressReader(util.RateLimitReader(resp.Body, c.BandwidthLimit()), progress))
+	if err != nil {
+		return fmt.Errorf("cannot write downloaded file: %w", err)
+	}
+
+	return nil
+}
+
+// checksumFromURL returns the checksum of the file from the checksums file (eg. checksums.txt, SHA256SUMS) on the
+// URL. It returns an empty string if the file is not listed.
+func (c *Client) checksumFromURL(url, name string) (string, error) {
+	req, err := c.prepareRequest(url, true)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot download %s, http response status: %s", url, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("cannot read response body: %w", err)
+	}
+
+	return parseChecksums(content, name), nil
+}
+
+// releaseAssetChecksum returns the SHA256 checksum of the asset of the GitHub release. The digest of the asset is used
+// if GitHub returns it, otherwise the checksum is looked up in the checksums.txt asset of the release.
+func (c *Client) releaseAssetChecksum(rel *release, a *asset) string {
+	if strings.HasPrefix(a.Digest, "sha256:") {
+		return a.Digest
+	}
+
+	for _, checksums := range rel.Assets {
+		if checksums.Name != "checksums.txt" {
+			continue
+		}
+
+		checksum, err := c.checksumFromURL(checksums.URL, a.Name)
+		if err != nil {
+			log.Debugf("Cannot get checksum of %s: %s", a.Name, err)
+		}
+
+		return checksum
+	}
+
+	return ""
+}
+
+// mutagenChecksum returns the checksum of the mutagen archive from the settings or from the SHA256SUMS file of the
+// mutagen release.
+func (c *Client) mutagenChecksum() string {
+	if c.MutagenSHA256() != "" {
+		return c.MutagenSHA256()
+	}
+
+	mutagenURL := c.MutagenURL()
+
+	checksum, err := c.checksumFromURL(
+		mutagenURL[:strings.LastIndex(mutagenURL, "/")+1]+"SHA256SUMS", path.Base(mutagenURL),
+	)
+	if err != nil {
+		log.Debugf("Cannot get checksum of mutagen: %s", err)
+	}
+
+	return checksum
+}
+
+// parseChecksums returns the checksum of the file from the content of a checksums file in the format of sha256sum.
+func parseChecksums(content []byte, name string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0])
+		}
+	}
+
+	return ""
+}
+
+// downloadCacheName returns the name of the cached file of the URL. The hash of the URL is added to the name, so the
+// files of different URLs (eg. versions) with the same name don't overwrite each other.
+func downloadCacheName(url, name string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return fmt.Sprintf("%x-%s", sum[:6], path.Base(name))
+}
+
+// fileChecksum returns the SHA256 checksum of the file in hex.
+func fileChecksum(file string) (string, error) {
+	f, err := util.FS.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,176 @@
+package logic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type DownloadTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DownloadTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestDownloadTestSuite(t *testing.T) {
+	suite.Run(t, new(DownloadTestSuite))
+}
+
+const downloadTestContent = "archive content"
+
+func downloadTestChecksum() string {
+	sum := sha256.Sum256([]byte(downloadTestContent))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// newDownloadTestServer returns a server which serves the content (with range requests) and counts the requests.
+func newDownloadTestServer(requests *int, ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		*ranges = append(*ranges, r.Header.Get("Range"))
+
+		http.ServeContent(w, r, "archive.tar.gz", time.Time{}, strings.NewReader(downloadTestContent))
+	}))
+}
+
+func (suite *DownloadTestSuite) TestDownloadFile() {
+	tests := []struct {
+		name         string
+		checksum     string
+		partial      string
+		cached       bool
+		wantRequests int
+		wantRange    string
+		wantErr      bool
+	}{
+		{
+			name:         "download",
+			checksum:     downloadTestChecksum(),
+			wantRequests: 1,
+		},
+		{
+			name:         "without checksum",
+			wantRequests: 1,
+		},
+		{
+			name:         "github digest",
+			checksum:     "sha256:" + strings.ToUpper(downloadTestChecksum()),
+			wantRequests: 1,
+		},
+		{
+			name:         "resume",
+			checksum:     downloadTestChecksum(),
+			partial:      downloadTestContent[:7],
+			wantRequests: 1,
+			wantRange:    "bytes=7-",
+		},
+		{
+			name:         "complete partial file",
+			checksum:     downloadTestChecksum(),
+			partial:      downloadTestContent,
+			wantRequests: 1,
+			wantRange:    fmt.Sprintf("bytes=%d-", len(downloadTestContent)),
+		},
+		{
+			name:     "cached",
+			checksum: downloadTestChecksum(),
+			cached:   true,
+		},
+		{
+			name:         "checksum mismatch",
+			checksum:     strings.Repeat("0", 64),
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+			util.FS = config.FS
+
+			var (
+				requests int
+				ranges   []string
+				server   = newDownloadTestServer(&requests, &ranges)
+				url      = server.URL + "/archive.tar.gz"
+				c        = newTestClient(map[string]interface{}{"reward_home_dir": "/home/.reward"})
+				file     = filepath.Join(c.DownloadCacheDir(), downloadCacheName(url, "archive.tar.gz"))
+			)
+			defer server.Close()
+
+			if tt.partial != "" {
+				assert.NoError(t, util.CreateDirAndWriteToFile([]byte(tt.partial), file+".part"))
+			}
+
+			if tt.cached {
+				assert.NoError(t, util.CreateDirAndWriteToFile([]byte(downloadTestContent), file))
+			}
+
+			got, err := c.downloadFile(url, "archive.tar.gz", tt.checksum)
+			assert.Equal(t, tt.wantRequests, requests)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.False(t, util.FileExists(file+".part"))
+				assert.False(t, util.FileExists(file))
+
+				return
+			}
+
+			assert.NoError(t, err)
+			defer got.Close()
+
+			if tt.wantRequests > 0 {
+				assert.Equal(t, tt.wantRange, ranges[0])
+			}
+
+			content, err := io.ReadAll(got)
+			assert.NoError(t, err)
+			assert.Equal(t, downloadTestContent, string(content))
+			assert.False(t, util.FileExists(file+".part"))
+		})
+	}
+}
+
+func (suite *DownloadTestSuite) TestParseChecksums() {
+	content := []byte("" +
+		"1111  reward_Linux_x86_64.tar.gz\n" +
+		"2222 *reward_Windows_x86_64.zip\n" +
+		"ABCD  reward_Darwin_arm64.tar.gz\n")
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{name: "text mode", file: "reward_Linux_x86_64.tar.gz", want: "1111"},
+		{name: "binary mode", file: "reward_Windows_x86_64.zip", want: "2222"},
+		{name: "lowercase", file: "reward_Darwin_arm64.tar.gz", want: "abcd"},
+		{name: "missing", file: "reward_Linux_i386.tar.gz", want: ""},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseChecksums(content, tt.file))
+		})
+	}
+}
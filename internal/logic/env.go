@@ -9,12 +9,21 @@ import (
 	"strings"
 	"text/template"
 
+	compose "github.com/docker/cli/cli/compose/types"
+	log "github.com/sirupsen/logrus"
+
 	"github.com/rewardenv/reward/internal/docker"
 	"github.com/rewardenv/reward/internal/shell"
 	"github.com/rewardenv/reward/internal/templates"
+	"github.com/rewardenv/reward/internal/timing"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrCustomComposeFileNotFound occurs when a compose file of the custom environment doesn't exist.
+var ErrCustomComposeFileNotFound = func(file string) error {
+	return fmt.Errorf("compose file %s doesn't exist, set CUSTOM_COMPOSE_FILES in the .env file", file)
+}
+
 // RunCmdEnv build up the contents for the env command.
 func (c *Client) RunCmdEnv(args []string) error {
 	// Run docker-compose help command if no args are passed.
@@ -30,8 +39,61 @@ func (c *Client) RunCmdEnv(args []string) error {
 		return nil
 	}
 
+	// the flags of reward are not parsed as the arguments are passed to docker compose
+	args = c.extractRewardFlags(args)
+
+	// offline mode: the images are not pulled, only the locally cached images are used
+	args, proceed := c.offlineComposeArgs(args)
+	if !proceed {
+		return nil
+	}
+
+	// bandwidth limit: the images are pulled one by one (or by the configured concurrency)
+	c.limitPullConcurrency(args)
+
+	// apm: the license and API keys are passed in the environment instead of the compose configuration
+	c.exportAPMKeys()
+
+	// shared mode: allocate a port offset which is not used by other developers
+	err := c.resolvePortOffset()
+	if err != nil {
+		return fmt.Errorf("an error occurred while allocating the port offset: %w", err)
+	}
+
+	if args[0] == "up" {
+		c.warnNamespaceRename()
+
+		// the generated values of the .env file are persisted when the environment is started
+		err = c.PersistEnvFileReferences()
+		if err != nil {
+			return err
+		}
+
+		// the environments initialized by older versions are registered when they're started
+		err = c.registerEnvironment(c.Cwd(), c.EnvName(), c.EnvType())
+		if err != nil {
+			log.Warnf("Cannot register the environment: %s", err)
+		}
+	}
+
+	// down: list and confirm the removed volumes
+	var removeVolumes []string
+
+	if args[0] == "down" {
+		var proceed bool
+
+		args, removeVolumes, proceed, err = c.prepareEnvDownVolumes(args)
+		if err != nil {
+			return err
+		}
+
+		if !proceed {
+			return nil
+		}
+	}
+
 	// down: disconnect peered service containers from environment network
-	err := c.configureCmdDown(args)
+	err = c.configureCmdDown(args)
 	if err != nil {
 		return fmt.Errorf("an error occurred while configuring the `down` command: %w", err)
 	}
@@ -52,13 +114,48 @@ func (c *Client) RunCmdEnv(args []string) error {
 		return fmt.Errorf("cannot create local app directories: %w", err)
 	}
 
-	// pass orchestration through to docker-compose
-	err = c.RunCmdEnvDockerCompose(args, shell.WithCatchOutput(false))
+	renderer := c.newProgress()
+
+	if args[0] == "up" {
+		step := renderer.Step("Preparing environment configuration")
+
+		err = step.Finish(c.prepareEnvUp())
+		if err != nil {
+			return err
+		}
+	}
+
+	// pass orchestration through to docker-compose, the long-running commands are rendered as a step
+	if title, ok := envCommandSteps[args[0]]; ok {
+		step := renderer.OutputStep(title)
+		err = step.Finish(c.RunCmdEnvDockerCompose(args, shell.WithCatchOutput(false)))
+	} else {
+		err = c.RunCmdEnvDockerCompose(args, shell.WithCatchOutput(false))
+	}
+
+	// the containers which failed to start might have run out of memory
+	if args[0] == "up" {
+		c.warnResourcePressure(c.EnvName())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = c.removeEnvVolumes(removeVolumes)
 	if err != nil {
 		return err
 	}
 
+	if args[0] == "up" {
+		c.installAPMAgents()
+	}
+
+	stop := timing.Start("mutagen sync")
 	err = c.updateMutagen(args)
+
+	stop()
+
 	if err != nil {
 		return fmt.Errorf("an error occurred while updating mutagen: %w", err)
 	}
@@ -66,6 +163,34 @@ func (c *Client) RunCmdEnv(args []string) error {
 	return nil
 }
 
+// envCommandSteps are the titles of the long-running docker-compose commands, which are rendered as a step.
+var envCommandSteps = map[string]string{
+	"up":    "Starting containers",
+	"pull":  "Pulling images",
+	"build": "Building images",
+}
+
+// prepareEnvUp validates the service versions and prepares the configuration files of the environment.
+func (c *Client) prepareEnvUp() error {
+	for _, warning := range c.ValidateServiceVersions() {
+		log.Warnln(warning)
+	}
+
+	err := c.prepareNginxConfigs()
+	if err != nil {
+		return fmt.Errorf("cannot prepare nginx configs: %w", err)
+	}
+
+	if c.SvcEnabledStrict("varnish") {
+		err = util.CreateDir(c.VarnishCustomConfigsPath(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create varnish snippets directory: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // RunCmdEnvDockerCompose function is a wrapper around the docker-compose command.
 // It appends the current directory and current project name to the args.
 // It also changes the output if the OS StdOut is suppressed.
@@ -95,6 +220,20 @@ func (c *Client) RunCmdEnvDockerCompose(args []string, opts ...shell.Opt) error
 	return nil
 }
 
+// RunCmdEnvDockerComposeOutput runs docker-compose in the context of the current environment like
+// RunCmdEnvDockerCompose, but it returns the combined output of the command instead of printing it.
+func (c *Client) RunCmdEnvDockerComposeOutput(args []string) (string, error) {
+	passedArgs := []string{
+		"--project-directory",
+		c.Cwd(),
+		"--project-name",
+		c.EnvName(),
+	}
+	passedArgs = append(passedArgs, args...)
+
+	return c.RunCmdEnvBuildDockerCompose(passedArgs, shell.WithCatchOutput(true), shell.WithSuppressOutput(true))
+}
+
 // RunCmdEnvBuildDockerComposeTemplate builds the templates which are used to invoke docker-compose.
 func (c *Client) RunCmdEnvBuildDockerComposeTemplate(tpl *template.Template, templateList *list.List) error {
 	envType := c.EnvType()
@@ -103,6 +242,16 @@ func (c *Client) RunCmdEnvBuildDockerComposeTemplate(tpl *template.Template, tem
 	// pwa-studio: everything is disabled, except node container
 	case util.CheckRegexInString("^pwa-studio", envType):
 		c.SetPWADefaults()
+	// static: only nginx is enabled (and optionally node to run the build watcher)
+	case util.CheckRegexInString("^static", envType):
+		c.SetStaticDefaults()
+	// custom: the services are defined by the compose files of the project
+	case util.CheckRegexInString("^custom", envType):
+		c.SetCustomDefaults()
+	// typo3: the document root depends on the composer mode
+	case util.CheckRegexInString("^typo3", envType):
+		c.SetTypo3Defaults()
+		c.SetNonLocalDefaults()
 	// magento 1,2, shopware, wordpress have their own php-fpm containers
 	case util.CheckRegexInString("^magento|wordpress|shopware", envType):
 		c.SetPHPDefaults(envType)
@@ -187,9 +336,12 @@ func (c *Client) RunCmdEnvBuildDockerComposeTemplate(tpl *template.Template, tem
 
 	externalSVCs := map[string][]string{
 		"blackfire": {"blackfire", fmt.Sprintf("%s.blackfire", envType)},
+		"newrelic":  {"newrelic"},
+		"datadog":   {"datadog"},
 		"allure":    {"allure"},
 		"selenium":  {"selenium"},
 		"magepack":  {fmt.Sprintf("%s.magepack", envType)},
+		"frontend":  {fmt.Sprintf("%s.frontend", envType)},
 	}
 	for name, svcs := range externalSVCs {
 		if c.GetBool(fmt.Sprintf("%s_%s", c.AppName(), name)) {
@@ -206,6 +358,35 @@ func (c *Client) RunCmdEnvBuildDockerComposeTemplate(tpl *template.Template, tem
 		}
 	}
 
+	if c.VendorVolume() {
+		err = templates.New().AppendEnvironmentTemplates(tpl, templateList, "vendor-volume", envType)
+		if err != nil {
+			return fmt.Errorf("an error occurred while appending vendor volume templates: %w", err)
+		}
+	}
+
+	if c.GlitchTipDSN() != "" {
+		dsn, err := c.GlitchTipInternalDSN()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if !c.SvcEnabledStrict("glitchtip") {
+			log.Warnln("GLITCHTIP_DSN is set, but the glitchtip global service is not enabled.")
+		}
+
+		c.Set("glitchtip_internal_dsn", dsn)
+
+		err = templates.New().AppendEnvironmentTemplates(tpl, templateList, "glitchtip", envType)
+		if err != nil {
+			return fmt.Errorf("an error occurred while appending glitchtip templates: %w", err)
+		}
+	}
+
+	for _, warning := range c.APMWarnings() {
+		log.Warnln(warning)
+	}
+
 	// ./.reward/reward-env.yml
 	// ./.reward/reward-env.os.yml
 	additionalTemplates := []string{
@@ -230,6 +411,8 @@ func (c *Client) RunCmdEnvBuildDockerCompose(args []string, opts ...shell.Opt) (
 		envTemplateList = list.New()
 	)
 
+	stopRender := timing.Start("render templates")
+
 	err := c.RunCmdEnvBuildDockerComposeTemplate(envTemplate, envTemplateList)
 	if err != nil {
 		return "", err
@@ -240,7 +423,24 @@ func (c *Client) RunCmdEnvBuildDockerCompose(args []string, opts ...shell.Opt) (
 		return "", err
 	}
 
+	if c.EnvType() == "custom" {
+		dockerComposeConfigs, err = c.appendCustomComposeFiles(dockerComposeConfigs)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	dockerComposeConfigs = c.appendEnvLabelsConfig(dockerComposeConfigs)
+	dockerComposeConfigs = templates.New().AppendResourcesConfig(dockerComposeConfigs, c.ServiceMemoryLimit)
+	dockerComposeConfigs = templates.New().AppendImageOverridesConfig(dockerComposeConfigs, c.ImageOverrides())
+
+	stopRender()
+
+	stopCompose := timing.Start("docker compose")
 	out, err := c.DockerCompose.RunWithConfig(args, dockerComposeConfigs, opts...)
+
+	stopCompose()
+
 	if err != nil {
 		return out, err
 	}
@@ -248,12 +448,101 @@ func (c *Client) RunCmdEnvBuildDockerCompose(args []string, opts ...shell.Opt) (
 	return out, nil
 }
 
+// appendEnvLabelsConfig labels the containers, networks and volumes of the environment with the environment name and
+// type, and the containers with the version of the application as well. The version changes with every release, it
+// is not added to the networks and volumes, because docker-compose recreates them if their labels change.
+func (c *Client) appendEnvLabelsConfig(details compose.ConfigDetails) compose.ConfigDetails {
+	labels := map[string]string{
+		c.LabelEnvName(): c.EnvName(),
+		c.LabelEnvType(): c.EnvType(),
+	}
+
+	return templates.New().AppendLabelsConfig(
+		details,
+		c.EnvName(),
+		labels,
+		map[string]string{
+			c.LabelVersion(): c.AppVersion(),
+		},
+		c.labelsMismatch(labels),
+	)
+}
+
+// labelsMismatch returns a function which reports whether the network or volume exists with other labels than the
+// labels (eg. it was created by a previous version without them). docker-compose refuses to use an existing network
+// or volume if its labels don't match the configuration, so these resources are kept unlabeled.
+func (c *Client) labelsMismatch(labels map[string]string) func(kind, name string) bool {
+	return func(kind, name string) bool {
+		if c.Docker == nil {
+			return false
+		}
+
+		var (
+			existing map[string]string
+			exists   bool
+			err      error
+		)
+
+		switch kind {
+		case "networks":
+			existing, exists, err = c.Docker.NetworkLabels(name)
+		case "volumes":
+			existing, exists, err = c.Docker.ExistingVolumeLabels(name)
+		}
+
+		if err != nil {
+			log.Debugf("Cannot look up the labels of %s: %s", name, err)
+
+			return false
+		}
+
+		if !exists {
+			return false
+		}
+
+		for key, value := range labels {
+			if existing[key] != value {
+				log.Debugf("The %s %s exists without the labels of the environment, it's not labeled.",
+					strings.TrimSuffix(kind, "s"), name)
+
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// appendCustomComposeFiles appends the compose files of the project to the configurations of the custom
+// environments. The files are merged into the templates, so reward adds the traefik routing labels and the network
+// labels to the services of the project.
+func (c *Client) appendCustomComposeFiles(details compose.ConfigDetails) (compose.ConfigDetails, error) {
+	files := c.CustomComposeFiles()
+
+	for _, file := range files {
+		if !util.FileExists(file) {
+			return details, ErrCustomComposeFileNotFound(file)
+		}
+	}
+
+	return templates.New().AppendComposeFiles(details, files) //nolint:wrapcheck
+}
+
 func (c *Client) configureCmdDown(args []string) error {
 	if util.ContainsString(args, "down") {
+		stop := timing.Start("network peering")
 		err := c.DockerPeeredServices("disconnect", c.EnvNetworkName())
+
+		stop()
+
 		if err != nil {
 			return fmt.Errorf("an error occurred while disconnecting peered services: %w", err)
 		}
+
+		err = c.removeDBExpose()
+		if err != nil {
+			return fmt.Errorf("an error occurred while removing the database proxy: %w", err)
+		}
 	}
 
 	return nil
@@ -261,6 +550,11 @@ func (c *Client) configureCmdDown(args []string) error {
 
 func (c *Client) configureCmdUp(args []string) ([]string, error) {
 	if util.ContainsString(args, "up") {
+		err := c.validateImageOverrides()
+		if err != nil {
+			return nil, err
+		}
+
 		// check if network already exist
 		networkExist, err := c.Docker.NetworkExist(c.EnvNetworkName())
 		if err != nil {
@@ -286,7 +580,11 @@ func (c *Client) configureCmdUp(args []string) ([]string, error) {
 			}
 		}
 
+		stop := timing.Start("network peering")
 		err = c.DockerPeeredServices("connect", c.EnvNetworkName())
+
+		stop()
+
 		if err != nil {
 			return nil, fmt.Errorf(
 				"an error occurred while connecting peered services to docker network: %w",
@@ -378,3 +676,62 @@ func (c *Client) updateMutagen(args []string) error {
 
 	return nil
 }
+
+// extractRewardFlags returns the arguments without the flags of reward. The --force-destructive flag of the read-only
+// mode and the --wait-lock flag are checked before the command runs, the --profile, --force and --output flags are
+// applied to the settings, and the profiling flags (--cpuprofile, --memprofile) are read by main. The --force flag of
+// `docker compose rm` and the --output flag of `docker compose config` (a file) are passed to docker compose.
+//
+// With --output json, `ps` lists the containers as JSON unless its format is set.
+func (c *Client) extractRewardFlags(args []string) []string {
+	filtered := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--force-destructive", arg == "--wait-lock":
+		case arg == "--force" && !util.ContainsString(args, "rm"):
+			c.Set("force", true)
+		case arg == "--profile":
+			c.Set("profile", true)
+		case arg == "--output" && i+1 < len(args) && isOutputFormat(args[i+1]):
+			i++
+			c.setOutput(args[i])
+		case strings.HasPrefix(arg, "--output=") && isOutputFormat(strings.TrimPrefix(arg, "--output=")):
+			c.setOutput(strings.TrimPrefix(arg, "--output="))
+		case arg == "--cpuprofile" || arg == "--memprofile":
+			// the value of the flag is skipped as well
+			i++
+		case strings.HasPrefix(arg, "--cpuprofile=") || strings.HasPrefix(arg, "--memprofile="):
+		default:
+			filtered = append(filtered, arg)
+		}
+	}
+
+	if c.JSONOutput() && len(filtered) > 0 && filtered[0] == "ps" && !psFormatSet(filtered) {
+		filtered = append(filtered, "--format", "json")
+	}
+
+	return filtered
+}
+
+// isOutputFormat returns true if the value of an --output flag is an output format of reward.
+func isOutputFormat(value string) bool {
+	return value == "text" || value == "json"
+}
+
+// psFormatSet returns true if the arguments of `docker compose ps` set its output format.
+func psFormatSet(args []string) bool {
+	for _, arg := range args {
+		if arg == "--format" || strings.HasPrefix(arg, "--format=") || arg == "-q" || arg == "--quiet" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setOutput sets the output format of the commands which don't parse their flags and reconfigures the logging.
+func (c *Client) setOutput(output string) {
+	c.Set("output", output)
+	c.SetLogging()
+}
@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type EnvTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvTestSuite))
+}
+
+func (suite *EnvTestSuite) TestLabelsMismatch() {
+	labels := map[string]string{"dev.reward.env-name": "shop"}
+
+	c := newTestClient(nil)
+	c.Docker = docker.NewClientWithAPI(&docker.Fake{
+		Networks: []types.NetworkResource{
+			{Name: "shop_default", Labels: map[string]string{"dev.reward.environment.name": "shop"}},
+			{Name: "shop_labeled", Labels: map[string]string{"dev.reward.env-name": "shop"}},
+		},
+		Volumes: []types.Volume{
+			{Name: "shop_dbdata"},
+			{Name: "shop_appdata", Labels: map[string]string{"dev.reward.env-name": "shop"}},
+		},
+	})
+
+	tests := []struct {
+		kind string
+		name string
+		want bool
+	}{
+		{kind: "networks", name: "shop_default", want: true},
+		{kind: "networks", name: "shop_labeled", want: false},
+		{kind: "networks", name: "shop", want: false},
+		{kind: "volumes", name: "shop_dbdata", want: true},
+		{kind: "volumes", name: "shop_appdata", want: false},
+		{kind: "volumes", name: "shop_redis", want: false},
+	}
+
+	mismatch := c.labelsMismatch(labels)
+
+	for _, tt := range tests {
+		suite.T().Run(tt.kind+"/"+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mismatch(tt.kind, tt.name))
+		})
+	}
+}
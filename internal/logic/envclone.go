@@ -0,0 +1,272 @@
+package logic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrCloneTargetExists occurs when the target directory of the clone already exists.
+var ErrCloneTargetExists = func(s string) error {
+	return fmt.Errorf("clone target directory already exists: %s", s)
+}
+
+// RunCmdEnvClone duplicates the current environment under a new name and domain. It copies the project files,
+// rewrites the environment name and domain in the .env file, signs a certificate for the new domain, duplicates the
+// docker volumes, starts the clone and copies the database into it.
+func (c *Client) RunCmdEnvClone(cmd *cmdpkg.Command, args []string) error {
+	newName := strings.ToLower(args[0])
+	// In shared mode the namespace is added to the name and the domain when the configuration of the clone is loaded.
+	oldName, oldDomain := c.rawEnvName(), c.rawTraefikDomain()
+
+	if !validateEnvName(newName) {
+		return config.ErrEnvNameIsInvalid
+	}
+
+	if newName == oldName {
+		return fmt.Errorf("%w: clone must have a different name than %s", config.ErrEnvNameIsInvalid, oldName)
+	}
+
+	targetDir, _ := cmd.Flags().GetString("dir")
+	if targetDir == "" {
+		targetDir = filepath.Join(filepath.Dir(c.Cwd()), newName)
+	}
+
+	targetDir, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("cannot determine absolute path for clone directory: %w", err)
+	}
+
+	if util.FileExists(targetDir) {
+		return ErrCloneTargetExists(targetDir)
+	}
+
+	newDomain, _ := cmd.Flags().GetString("domain")
+	if newDomain == "" {
+		newDomain = strings.Replace(oldDomain, oldName, newName, 1)
+	}
+
+	if newDomain == oldDomain {
+		newDomain = newName + ".test"
+	}
+
+	log.Printf("Cloning environment %s to %s (%s)...", oldName, newName, targetDir)
+
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+
+	// The command history belongs to the original environment.
+	if rel, err := filepath.Rel(c.Cwd(), c.HistoryFile()); err == nil {
+		exclude = append(exclude, rel)
+	}
+
+	// Don't copy the clone into itself if it's created inside the project directory.
+	if rel, err := filepath.Rel(c.Cwd(), targetDir); err == nil && !strings.HasPrefix(rel, "..") {
+		exclude = append(exclude, rel)
+	}
+
+	err = util.CopyDir(c.Cwd(), targetDir, exclude...)
+	if err != nil {
+		return fmt.Errorf("cannot copy project files: %w", err)
+	}
+
+	err = c.rewriteCloneEnvFile(filepath.Join(targetDir, ".env"), oldName, newName, newDomain)
+	if err != nil {
+		return err
+	}
+
+	err = c.RunCmdSignCertificate([]string{c.namespaced(newDomain, ".")})
+	if err != nil {
+		return fmt.Errorf("cannot sign certificate for %s: %w", newDomain, err)
+	}
+
+	if skip, _ := cmd.Flags().GetBool("skip-volumes"); !skip {
+		err = c.cloneVolumes(c.namespaced(newName, "-"))
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Starting environment %s...", newName)
+
+	err = c.runSelfInDir(targetDir, nil, "env", "up")
+	if err != nil {
+		return fmt.Errorf("cannot start cloned environment: %w", err)
+	}
+
+	if skip, _ := cmd.Flags().GetBool("skip-db"); !skip && c.SvcEnabledPermissive("db") {
+		err = c.cloneDatabase(targetDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf(
+		"...environment %s cloned to %s. The clone is available at https://%s/.",
+		oldName,
+		newName,
+		c.namespaced(newDomain, "."),
+	)
+
+	return nil
+}
+
+// rewriteCloneEnvFile renames the environment and its domain in the .env file of the clone and registers the
+// origin of the clone.
+func (c *Client) rewriteCloneEnvFile(path, oldName, newName, newDomain string) error {
+	prefix := strings.ToUpper(c.AppName())
+
+	return setEnvFileValues(path, [][2]string{
+		{prefix + "_ENV_NAME", newName},
+		{"TRAEFIK_DOMAIN", newDomain},
+		{prefix + "_CLONE_OF", oldName},
+		{prefix + "_CLONE_OF_DIR", c.Cwd()},
+	})
+}
+
+// setEnvFileValues replaces the given key-value pairs in a .env file. Keys which are not present are appended.
+func setEnvFileValues(path string, values [][2]string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+
+	for _, v := range values {
+		line := fmt.Sprintf("%s=%s", v[0], v[1])
+		re := regexp.MustCompile(fmt.Sprintf(`(?m)^%s=.*$`, regexp.QuoteMeta(v[0])))
+
+		if re.Match(content) {
+			content = re.ReplaceAllLiteral(content, []byte(line))
+
+			continue
+		}
+
+		content = append(content, []byte("\n"+line+"\n")...)
+	}
+
+	err = util.CreateDirAndWriteToFile(content, path)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// cloneVolumes copies the docker volumes of the current environment to the volumes of the clone. Database volumes
+// are skipped because a copy of a running database is not consistent, they are copied using a database dump instead.
+func (c *Client) cloneVolumes(newName string) error {
+	volumes, err := c.Docker.VolumeNamesByLabel(fmt.Sprintf("com.docker.compose.project=%s", c.EnvName()))
+	if err != nil {
+		return fmt.Errorf("cannot list environment volumes: %w", err)
+	}
+
+	for _, volume := range volumes {
+		suffix := strings.TrimPrefix(volume, c.EnvName()+"_")
+		if strings.HasSuffix(suffix, "dbdata") {
+			continue
+		}
+
+		target := fmt.Sprintf("%s_%s", newName, suffix)
+
+		log.Printf("Copying volume %s to %s...", volume, target)
+
+		err = cmdpkg.Cmnd("docker", "volume", "create",
+			"--label", "com.docker.compose.project="+newName,
+			"--label", "com.docker.compose.volume="+suffix,
+			target,
+		).Run()
+		if err != nil {
+			return fmt.Errorf("cannot create volume %s: %w", target, err)
+		}
+
+		copyCmd := cmdpkg.Cmnd("docker", "run", "--rm",
+			"-v", volume+":/from:ro",
+			"-v", target+":/to",
+			"alpine", "sh", "-c", "cp -a /from/. /to/",
+		)
+		copyCmd.Stderr = os.Stderr
+
+		err = copyCmd.Run()
+		if err != nil {
+			return fmt.Errorf("cannot copy volume %s: %w", volume, err)
+		}
+	}
+
+	return nil
+}
+
+// cloneDatabase pipes the database dump of the current environment into the database of the clone.
+func (c *Client) cloneDatabase(targetDir string) error {
+	log.Println("Copying database to the clone...")
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	dump := cmdpkg.Cmnd(self, "db", "dump")
+	dump.Stderr = os.Stderr
+
+	stdout, err := dump.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("cannot create pipe: %w", err)
+	}
+
+	err = dump.Start()
+	if err != nil {
+		return fmt.Errorf("cannot start database dump: %w", err)
+	}
+
+	err = c.runSelfInDir(targetDir, stdout, "db", "import")
+	if err != nil {
+		_ = dump.Process.Kill()
+
+		return fmt.Errorf("cannot import database into the clone: %w", err)
+	}
+
+	err = dump.Wait()
+	if err != nil {
+		return fmt.Errorf("cannot dump database: %w", err)
+	}
+
+	log.Println("...database copied.")
+
+	return nil
+}
+
+// namespaced prefixes s with the developer's namespace if shared mode is enabled.
+func (c *Client) namespaced(s, sep string) string {
+	if !c.SharedMode() || c.Namespace() == "" {
+		return s
+	}
+
+	return c.Namespace() + sep + s
+}
+
+// runSelfInDir runs the application itself with args in dir. If stdin is nil the application's stdin is used.
+func (c *Client) runSelfInDir(dir string, stdin io.Reader, args ...string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	command := cmdpkg.Cmnd(self, args...)
+	command.Dir = dir
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Stdin = os.Stdin
+
+	if stdin != nil {
+		command.Stdin = stdin
+	}
+
+	return command.Run()
+}
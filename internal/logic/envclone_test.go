@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type EnvCloneTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EnvCloneTestSuite) SetupTest() {
+	util.FS = &afero.Afero{Fs: afero.NewOsFs()}
+}
+
+func TestEnvCloneTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvCloneTestSuite))
+}
+
+func (suite *EnvCloneTestSuite) TestSetEnvFileValues() {
+	path := filepath.Join(suite.T().TempDir(), ".env")
+	content := "REWARD_ENV_NAME=shop\nTRAEFIK_DOMAIN=shop.test\nREWARD_ENV_NAME_SUFFIX=x\n"
+
+	assert.NoError(suite.T(), os.WriteFile(path, []byte(content), 0o600))
+
+	err := setEnvFileValues(path, [][2]string{
+		{"REWARD_ENV_NAME", "shop-upgrade"},
+		{"TRAEFIK_DOMAIN", "upgrade.shop.test"},
+		{"REWARD_CLONE_OF_DIR", "/home/$USER/shop${1}"},
+	})
+	assert.NoError(suite.T(), err)
+
+	got, err := os.ReadFile(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(
+		suite.T(),
+		"REWARD_ENV_NAME=shop-upgrade\nTRAEFIK_DOMAIN=upgrade.shop.test\nREWARD_ENV_NAME_SUFFIX=x\n"+
+			"\nREWARD_CLONE_OF_DIR=/home/$USER/shop${1}\n",
+		string(got),
+	)
+
+	err = setEnvFileValues(path, [][2]string{{"REWARD_CLONE_OF_DIR", "/srv/$1"}})
+	assert.NoError(suite.T(), err)
+
+	got, err = os.ReadFile(path)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(got), "REWARD_CLONE_OF_DIR=/srv/$1\n", "$ should not be expanded")
+}
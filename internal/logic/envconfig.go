@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ErrEnvSettingNotSet occurs when the setting is not set in the .env file.
+var ErrEnvSettingNotSet = func(key string) error {
+	return fmt.Errorf("%s is not set in the .env file", key)
+}
+
+// RunCmdEnvGet prints the value of a setting of the project .env file.
+func (c *Client) RunCmdEnvGet(args []string) error {
+	key := strings.ToUpper(args[0])
+
+	env := viper.New()
+	env.SetConfigFile(filepath.Join(c.Cwd(), ".env"))
+	env.SetConfigType("dotenv")
+
+	err := env.ReadInConfig()
+	if err != nil {
+		return fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	if !env.InConfig(key) {
+		return ErrEnvSettingNotSet(key)
+	}
+
+	fmt.Println(env.GetString(key))
+
+	return nil
+}
+
+// RunCmdEnvSet validates and writes a setting to the project .env file. The existing line of the setting is
+// replaced in place, so the comments and the order of the settings are preserved.
+func (c *Client) RunCmdEnvSet(args []string) error {
+	key := strings.ToUpper(args[0])
+	value := args[1]
+
+	warnings, err := c.ValidateEnvSetting(key, value)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, warning := range warnings {
+		log.Warnln(warning)
+	}
+
+	err = setEnvFileValues(filepath.Join(c.Cwd(), ".env"), [][2]string{{key, quoteEnvValue(value)}})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%s set to %s.", key, value)
+
+	if setting, ok := c.EnvSetting(key); !ok || !setting.CLIOnly {
+		log.Printf("Run `%s env up -d` to apply the change.", c.AppName())
+	}
+
+	return nil
+}
+
+// quoteEnvValue returns the value double-quoted if it contains whitespace, quotes or a comment character.
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\"'#\\") {
+		return value
+	}
+
+	return fmt.Sprintf(`"%s"`, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value))
+}
@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvConfigTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvConfigTestSuite))
+}
+
+func (suite *EnvConfigTestSuite) TestQuoteEnvValue() {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "8.1", want: "8.1"},
+		{name: "empty", value: "", want: ""},
+		{name: "whitespace", value: "a.test b.test", want: `"a.test b.test"`},
+		{name: "quotes and comment", value: `say "hi" #1`, want: `"say \"hi\" #1"`},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quoteEnvValue(tt.value))
+		})
+	}
+}
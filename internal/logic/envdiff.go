@@ -0,0 +1,228 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+// ErrEnvDrift occurs when the --exit-code flag is set and the containers don't match the rendered configuration.
+var ErrEnvDrift = func(services []string) error {
+	return fmt.Errorf("services which do not match the configuration: %s", strings.Join(services, ", "))
+}
+
+const (
+	driftUpToDate   = "up to date"
+	driftStale      = "stale"
+	driftNotCreated = "not created"
+	driftOrphaned   = "orphaned"
+)
+
+var composeConfigHashRegex = regexp.MustCompile(`(?m)^(\S+) ([0-9a-f]{64})$`)
+
+// composeService contains the fields of a service of the rendered compose configuration which are compared with the
+// containers.
+type composeService struct {
+	Image       string             `json:"image"`
+	Environment map[string]*string `json:"environment"`
+	Labels      map[string]string  `json:"labels"`
+}
+
+// serviceDrift describes the difference between a service of the rendered configuration and its container.
+type serviceDrift struct {
+	Service string
+	Status  string
+	Reasons []string
+}
+
+// RunCmdEnvDiff compares the containers of the environment with the freshly rendered compose configuration and
+// reports the services which have to be recreated to apply the configuration.
+func (c *Client) RunCmdEnvDiff(cmd *cmdpkg.Command) error {
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{"config", "--hash", "*"})
+	if err != nil {
+		return fmt.Errorf("cannot render compose configuration: %s: %w", strings.TrimSpace(out), err)
+	}
+
+	hashes := make(map[string]string)
+	for _, m := range composeConfigHashRegex.FindAllStringSubmatch(out, -1) {
+		hashes[m[1]] = m[2]
+	}
+
+	services, err := c.renderedComposeServices()
+	if err != nil {
+		// The reasons are only informational, docker-compose v1 cannot render the configuration as JSON.
+		log.Debugf("Cannot determine the reasons of the differences: %s", err)
+	}
+
+	containers, err := c.Docker.ContainersByLabel(fmt.Sprintf("com.docker.compose.project=%s", c.EnvName()))
+	if err != nil {
+		return fmt.Errorf("cannot list environment containers: %w", err)
+	}
+
+	drifts := envDrift(hashes, services, containers)
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Service", "Status", "Reason"})
+
+	stale := make([]string, 0)
+
+	for _, d := range drifts {
+		t.AppendRow(table.Row{d.Service, d.Status, strings.Join(d.Reasons, "\n")})
+
+		if d.Status != driftUpToDate {
+			stale = append(stale, d.Service)
+		}
+	}
+
+	t.Render()
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	log.Printf("Run `%s env up -d` to recreate the stale services.", c.AppName())
+
+	if exitCode, _ := cmd.Flags().GetBool("exit-code"); exitCode {
+		return ErrEnvDrift(stale)
+	}
+
+	return nil
+}
+
+// renderedComposeServices returns the services of the rendered compose configuration.
+func (c *Client) renderedComposeServices() (map[string]composeService, error) {
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{"config", "--format", "json"})
+	if err != nil {
+		return nil, fmt.Errorf("cannot render compose configuration: %s: %w", strings.TrimSpace(out), err)
+	}
+
+	// Compose may print warnings before the configuration.
+	start := strings.Index(out, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("cannot parse compose configuration: %s", strings.TrimSpace(out))
+	}
+
+	var config struct {
+		Services map[string]composeService `json:"services"`
+	}
+
+	err = json.Unmarshal([]byte(out[start:]), &config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse compose configuration: %w", err)
+	}
+
+	return config.Services, nil
+}
+
+// envDrift compares the config hashes and the services of the rendered configuration with the containers.
+// The config hash label of the containers determines if a service is stale, the image, the environment and the
+// labels are compared to explain the difference.
+func envDrift(
+	hashes map[string]string,
+	services map[string]composeService,
+	containers []types.ContainerJSON,
+) []serviceDrift {
+	byService := make(map[string]types.ContainerJSON)
+
+	for _, container := range containers {
+		if container.Config != nil {
+			byService[container.Config.Labels["com.docker.compose.service"]] = container
+		}
+	}
+
+	names := make([]string, 0, len(hashes)+len(byService))
+	for name := range hashes {
+		names = append(names, name)
+	}
+
+	for name := range byService {
+		if _, ok := hashes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	drifts := make([]serviceDrift, 0, len(names))
+
+	for _, name := range names {
+		container, created := byService[name]
+		hash, configured := hashes[name]
+
+		switch {
+		case !configured:
+			drifts = append(drifts, serviceDrift{
+				Service: name,
+				Status:  driftOrphaned,
+				Reasons: []string{"the service is not in the configuration"},
+			})
+		case !created:
+			drifts = append(drifts, serviceDrift{Service: name, Status: driftNotCreated})
+		case container.Config.Labels["com.docker.compose.config-hash"] == hash:
+			drifts = append(drifts, serviceDrift{Service: name, Status: driftUpToDate})
+		default:
+			reasons := serviceDriftReasons(services[name], container)
+			if len(reasons) == 0 {
+				reasons = []string{"the configuration changed"}
+			}
+
+			drifts = append(drifts, serviceDrift{Service: name, Status: driftStale, Reasons: reasons})
+		}
+	}
+
+	return drifts
+}
+
+func serviceDriftReasons(service composeService, container types.ContainerJSON) []string {
+	reasons := make([]string, 0)
+
+	if service.Image != "" && service.Image != container.Config.Image {
+		reasons = append(reasons, fmt.Sprintf("image: %s -> %s", container.Config.Image, service.Image))
+	}
+
+	env := make(map[string]string)
+
+	for _, e := range container.Config.Env {
+		if k, v, ok := strings.Cut(e, "="); ok {
+			env[k] = v
+		}
+	}
+
+	for _, key := range sortedKeys(service.Environment) {
+		value := service.Environment[key]
+		if value == nil {
+			continue
+		}
+
+		if current, ok := env[key]; !ok || current != *value {
+			reasons = append(reasons, fmt.Sprintf("environment: %s", key))
+		}
+	}
+
+	for _, key := range sortedKeys(service.Labels) {
+		if current, ok := container.Config.Labels[key]; !ok || current != service.Labels[key] {
+			reasons = append(reasons, fmt.Sprintf("label: %s", key))
+		}
+	}
+
+	return reasons
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvDiffTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvDiffTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvDiffTestSuite))
+}
+
+func newTestContainer(service, hash, image string, env []string) types.ContainerJSON {
+	return types.ContainerJSON{
+		Config: &container.Config{
+			Image: image,
+			Env:   env,
+			Labels: map[string]string{
+				"com.docker.compose.service":     service,
+				"com.docker.compose.config-hash": hash,
+			},
+		},
+	}
+}
+
+func (suite *EnvDiffTestSuite) TestEnvDrift() {
+	value := "8.2"
+	hashes := map[string]string{
+		"nginx":   "aaaa",
+		"php-fpm": "bbbb",
+		"redis":   "cccc",
+	}
+	services := map[string]composeService{
+		"php-fpm": {
+			Image:       "docker.io/rewardenv/php-fpm:8.2-magento2",
+			Environment: map[string]*string{"PHP_VERSION": &value, "PASSTHROUGH": nil},
+		},
+	}
+	containers := []types.ContainerJSON{
+		newTestContainer("nginx", "aaaa", "docker.io/rewardenv/nginx:1.18", nil),
+		newTestContainer("php-fpm", "old", "docker.io/rewardenv/php-fpm:8.1-magento2", []string{"PHP_VERSION=8.1"}),
+		newTestContainer("varnish", "dddd", "docker.io/rewardenv/varnish:7.0", nil),
+	}
+
+	suite.Equal([]serviceDrift{
+		{Service: "nginx", Status: driftUpToDate},
+		{
+			Service: "php-fpm",
+			Status:  driftStale,
+			Reasons: []string{
+				"image: docker.io/rewardenv/php-fpm:8.1-magento2 -> docker.io/rewardenv/php-fpm:8.2-magento2",
+				"environment: PHP_VERSION",
+			},
+		},
+		{Service: "redis", Status: driftNotCreated},
+		{Service: "varnish", Status: driftOrphaned, Reasons: []string{"the service is not in the configuration"}},
+	}, envDrift(hashes, services, containers))
+}
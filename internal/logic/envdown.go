@@ -0,0 +1,241 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrUnknownVolumeSelector occurs when a name passed to `env down --volumes` is neither a service which has volumes
+// nor a volume of the environment.
+var ErrUnknownVolumeSelector = func(name string, volumes []string) error {
+	return fmt.Errorf(
+		"%s is neither a service with volumes nor a volume of the environment, available volumes: %s",
+		name, strings.Join(volumes, ", "),
+	)
+}
+
+// ErrSafetyDumpDBNotRunning occurs when the database volume is removed with reward_safety_dump enabled, but the
+// database cannot be dumped as its container is not running.
+var ErrSafetyDumpDBNotRunning = func(appName string) error {
+	return fmt.Errorf(
+		"cannot dump the database before removing its volume as it's not running, start it using `%[1]s env up` "+
+			"or disable %[1]s_safety_dump", appName,
+	)
+}
+
+// prepareEnvDownVolumes lists the volumes removed by `env down --volumes` and asks for confirmation, dumps the
+// database first if reward_safety_dump is enabled, and moves the volumes to the trash after the containers are
+// stopped. The removal of all volumes (-v, --volumes) is passed to docker compose, the volumes of the listed services
+// (--volumes=db,elasticsearch) are returned, so they're removed after the containers. If the removal is not confirmed,
+// proceed is false.
+func (c *Client) prepareEnvDownVolumes(args []string) (composeArgs, remove []string, proceed bool, err error) {
+	composeArgs, all, selectors := parseDownVolumes(args)
+	if !all && len(selectors) == 0 {
+		return args, nil, true, nil
+	}
+
+	volumes, err := c.Docker.VolumeNamesByLabel(fmt.Sprintf("com.docker.compose.project=%s", c.EnvName()))
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("cannot list environment volumes: %w", err)
+	}
+
+	sort.Strings(volumes)
+
+	selected := volumes
+
+	if !all {
+		containers, err := c.Docker.ContainerDetailsByLabel(fmt.Sprintf("com.docker.compose.project=%s", c.EnvName()))
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("cannot list environment containers: %w", err)
+		}
+
+		selected, err = selectEnvVolumes(c.EnvName(), volumes, containers, selectors)
+		if err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	if len(selected) == 0 {
+		log.Println("The environment doesn't have any named volumes.")
+
+		if all {
+			// the anonymous volumes are removed by docker compose
+			composeArgs = append(composeArgs, "--volumes")
+		}
+
+		return composeArgs, nil, true, nil
+	}
+
+	log.Printf("The following volumes of %s are going to be removed with all their data:", c.EnvName())
+
+	for _, volume := range selected {
+		log.Printf("  - %s", volume)
+	}
+
+	if !util.AskForConfirmation("Would you like to remove these volumes?") {
+		return nil, nil, false, nil
+	}
+
+	err = c.safetyDump(selected)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// the volumes are archived after the containers are stopped, so their files don't change while they're archived
+	if c.Trash().Enabled() {
+		err = c.RunCmdEnvDockerCompose([]string{"stop"}, shell.WithCatchOutput(false))
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("cannot stop the environment, the volumes are not removed: %w", err)
+		}
+	}
+
+	err = c.trashVolumes(selected)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if all {
+		return append(composeArgs, "--volumes"), nil, true, nil
+	}
+
+	return composeArgs, selected, true, nil
+}
+
+// safetyDump dumps the database if reward_safety_dump is enabled and its volume is going to be removed.
+func (c *Client) safetyDump(volumes []string) error {
+	if !c.SafetyDump() || !util.ContainsString(volumes, c.EnvName()+"_dbdata") {
+		return nil
+	}
+
+	if !c.Docker.ContainerRunning(c.DBContainer()) {
+		return ErrSafetyDumpDBNotRunning(c.AppName())
+	}
+
+	file := filepath.Join(
+		c.SafetyDumpDir(), fmt.Sprintf("%s-%s.sql.gz", c.EnvName(), time.Now().Format("20060102150405")),
+	)
+
+	step := c.newProgress().OutputStep("Dumping the database before removing its volume")
+
+	err := step.Finish(c.dumpDatabaseToFile(file))
+	if err != nil {
+		return fmt.Errorf("cannot dump the database, the volumes are not removed: %w", err)
+	}
+
+	log.Printf("The database is dumped to %s.", file)
+
+	return nil
+}
+
+// removeEnvVolumes removes the volumes selected by `env down --volumes=<services>` after the containers are removed.
+func (c *Client) removeEnvVolumes(volumes []string) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	rm := cmdpkg.Cmnd("docker", append([]string{"volume", "rm"}, volumes...)...)
+	rm.Stdout = os.Stdout
+	rm.Stderr = os.Stderr
+
+	err := rm.Run()
+	if err != nil {
+		return fmt.Errorf("cannot remove volumes: %w", err)
+	}
+
+	return nil
+}
+
+// parseDownVolumes removes the volume flags from the arguments of `env down`. -v and --volumes remove all the
+// volumes, --volumes=<names> and --volumes <names> only the volumes of the listed services or the listed volumes.
+func parseDownVolumes(args []string) (rest []string, all bool, selectors []string) {
+	rest = make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-v":
+			all = true
+		case arg == "--volumes":
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				selectors = append(selectors, splitVolumeSelectors(args[i+1])...)
+				i++
+
+				continue
+			}
+
+			all = true
+		case strings.HasPrefix(arg, "--volumes="):
+			selectors = append(selectors, splitVolumeSelectors(strings.TrimPrefix(arg, "--volumes="))...)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	if all {
+		selectors = nil
+	}
+
+	return rest, all, selectors
+}
+
+// splitVolumeSelectors splits the comma separated list of names passed to --volumes.
+func splitVolumeSelectors(s string) []string {
+	var selectors []string
+
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			selectors = append(selectors, name)
+		}
+	}
+
+	return selectors
+}
+
+// selectEnvVolumes returns the volumes of the environment selected by the names. A name is either a service (eg. db),
+// which selects the volumes mounted in its containers, or a volume with or without the environment name prefix
+// (eg. dbdata).
+func selectEnvVolumes(envName string, volumes []string, containers []*docker.Container, names []string) (
+	[]string, error,
+) {
+	selected := make(map[string]bool)
+
+	for _, name := range names {
+		found := false
+
+		for _, volume := range volumes {
+			if volume == name || volume == envName+"_"+name {
+				selected[volume] = true
+				found = true
+			}
+		}
+
+		for _, container := range containers {
+			if container.Service != name {
+				continue
+			}
+
+			for _, mount := range container.Mounts {
+				if mount.Type == "volume" && util.ContainsString(volumes, mount.Name) {
+					selected[mount.Name] = true
+					found = true
+				}
+			}
+		}
+
+		if !found {
+			return nil, ErrUnknownVolumeSelector(name, volumes)
+		}
+	}
+
+	return sortedKeys(selected), nil
+}
@@ -0,0 +1,93 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type EnvDownTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvDownTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvDownTestSuite))
+}
+
+func (suite *EnvDownTestSuite) TestParseDownVolumes() {
+	tests := []struct {
+		args          []string
+		wantRest      []string
+		wantAll       bool
+		wantSelectors []string
+	}{
+		{args: []string{"down"}, wantRest: []string{"down"}},
+		{args: []string{"down", "-v"}, wantRest: []string{"down"}, wantAll: true},
+		{
+			args:     []string{"down", "--volumes", "--remove-orphans"},
+			wantRest: []string{"down", "--remove-orphans"},
+			wantAll:  true,
+		},
+		{
+			args:          []string{"down", "--volumes", "db,elasticsearch"},
+			wantRest:      []string{"down"},
+			wantSelectors: []string{"db", "elasticsearch"},
+		},
+		{
+			args:          []string{"down", "--volumes=db, redis", "-t", "5"},
+			wantRest:      []string{"down", "-t", "5"},
+			wantSelectors: []string{"db", "redis"},
+		},
+		{args: []string{"down", "--volumes=db", "-v"}, wantRest: []string{"down"}, wantAll: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			rest, all, selectors := parseDownVolumes(tt.args)
+
+			assert.Equal(t, tt.wantRest, rest)
+			assert.Equal(t, tt.wantAll, all)
+			assert.Equal(t, tt.wantSelectors, selectors)
+		})
+	}
+}
+
+func (suite *EnvDownTestSuite) TestSelectEnvVolumes() {
+	volumes := []string{"shop_appdata", "shop_dbdata", "shop_esdata", "shop_redis"}
+	containers := []*docker.Container{
+		{Service: "db", Mounts: []docker.Mount{{Type: "volume", Name: "shop_dbdata"}, {Type: "bind", Source: "/tmp"}}},
+		{Service: "elasticsearch", Mounts: []docker.Mount{{Type: "volume", Name: "shop_esdata"}}},
+		{Service: "php-fpm", Mounts: []docker.Mount{{Type: "volume", Name: "shop_appdata"}}},
+		{Service: "nginx", Mounts: []docker.Mount{{Type: "bind", Source: "/home/user/shop"}}},
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "services", names: []string{"elasticsearch", "db"}, want: []string{"shop_dbdata", "shop_esdata"}},
+		{name: "volume names", names: []string{"redis", "shop_appdata"}, want: []string{"shop_appdata", "shop_redis"}},
+		{name: "service without volumes", names: []string{"nginx"}, wantErr: true},
+		{name: "unknown", names: []string{"db", "mongodb"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := selectEnvVolumes("shop", volumes, containers, tt.names)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrEnvNameRequired occurs when env init is called without an environment name.
+var ErrEnvNameRequired = fmt.Errorf("environment name is required")
+
 // RunCmdEnvInit creates a .env file for envType based on envName.
 func (c *Client) RunCmdEnvInit(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 && len(strings.TrimSpace(c.EnvName())) == 0 {
@@ -20,7 +23,7 @@ func (c *Client) RunCmdEnvInit(cmd *cobra.Command, args []string) error {
 
 		_ = cmd.Help()
 
-		os.Exit(1)
+		return ErrEnvNameRequired
 	}
 
 	if len(args) > 0 {
@@ -70,7 +73,21 @@ TRAEFIK_EXTRA_HOSTS=
 
 `, strings.ToUpper(c.AppName()), envName, envType, webRoot,
 	)
-	envFileContent := strings.Join([]string{envBase, c.EnvTypes()[envType]}, "")
+
+	if magentoType, _ := cmd.Flags().GetString("magento-type"); envType == "magento2" && magentoType != "community" {
+		c.Set(fmt.Sprintf("%s_magento_type", c.AppName()), magentoType)
+
+		if !util.ContainsString(c.ValidMagentoTypes(), c.MagentoType()) {
+			return config.ErrUnknownMagentoType
+		}
+
+		envBase += fmt.Sprintf("%s_MAGENTO_TYPE=%s\n\n", strings.ToUpper(c.AppName()), c.MagentoType())
+	}
+	c.RefreshVersionMatrix()
+
+	envFileContent := strings.Join(
+		[]string{envBase, c.applyVersionMatrixEnvDefaults(envType, c.EnvTypes()[envType])}, "",
+	)
 
 	if !envFileExist {
 		err := util.CreateDirAndWriteToFile([]byte(envFileContent), envFilePath)
@@ -84,7 +101,7 @@ TRAEFIK_EXTRA_HOSTS=
 		return fmt.Errorf("cannot create local app dirs: %w", err)
 	}
 
-	return nil
+	return c.registerEnvironment(path, envName, envType)
 }
 
 func (c *Client) CheckAndCreateLocalAppDirs() error {
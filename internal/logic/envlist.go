@@ -0,0 +1,248 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// The statuses of the registered environments.
+const (
+	envStatusRunning = "running"
+	envStatusStopped = "stopped"
+	envStatusMissing = "missing"
+)
+
+// registeredEnvironment is an environment initialized by env-init or started by env up.
+type registeredEnvironment struct {
+	Name    string    `json:"name"`
+	Dir     string    `json:"dir"`
+	Type    string    `json:"type"`
+	Created time.Time `json:"created"`
+}
+
+// RunCmdEnvList prints the registered environments with their status and domains. The name, the type and the
+// domains are read from the .env file of the environment, so they're up to date. With --prune the environments whose
+// directory was removed are removed from the registry.
+func (c *Client) RunCmdEnvList(cmd *cmdpkg.Command) error {
+	envs, err := c.registeredEnvironments()
+	if err != nil {
+		return err
+	}
+
+	containers, err := c.Docker.ContainerDetailsByLabel(c.LabelEnvName())
+	if err != nil {
+		return fmt.Errorf("cannot get containers: %w", err)
+	}
+
+	kept := make([]registeredEnvironment, 0, len(envs))
+
+	tb := c.newTable()
+	tb.AppendHeader(table.Row{"Name", "Type", "Path", "Status", "Domains"})
+
+	for _, env := range envs {
+		settings, err := readEnvFile(env.Dir)
+		if err != nil {
+			log.Debugf("Cannot read the .env file of %s: %s", env.Dir, err)
+
+			if prune, _ := cmd.Flags().GetBool("prune"); prune {
+				log.Printf("Removing environment %s (%s) from the registry.", env.Name, env.Dir)
+
+				continue
+			}
+
+			tb.AppendRow(table.Row{env.Name, env.Type, env.Dir, envStatusMissing, ""})
+			kept = append(kept, env)
+
+			continue
+		}
+
+		kept = append(kept, env)
+
+		if name := settings.GetString(fmt.Sprintf("%s_env_name", c.AppName())); name != "" {
+			env.Name = name
+		}
+
+		if envType := settings.GetString(fmt.Sprintf("%s_env_type", c.AppName())); envType != "" {
+			env.Type = envType
+		}
+
+		tb.AppendRow(table.Row{
+			env.Name,
+			env.Type,
+			env.Dir,
+			environmentStatus(env.Dir, containers),
+			strings.Join(environmentDomains(settings), " "),
+		})
+	}
+
+	tb.Render()
+
+	if len(kept) != len(envs) {
+		return c.writeRegisteredEnvironments(kept)
+	}
+
+	return nil
+}
+
+// registerEnvironment adds the environment in the directory to the registry, or updates its name and type if it's
+// already registered.
+func (c *Client) registerEnvironment(dir, name, envType string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("cannot determine absolute path of %s: %w", dir, err)
+	}
+
+	envs, err := c.registeredEnvironments()
+	if err != nil {
+		return err
+	}
+
+	for i := range envs {
+		if envs[i].Dir == dir {
+			if envs[i].Name == name && envs[i].Type == envType {
+				return nil
+			}
+
+			envs[i].Name, envs[i].Type = name, envType
+
+			return c.writeRegisteredEnvironments(envs)
+		}
+	}
+
+	return c.writeRegisteredEnvironments(append(envs, registeredEnvironment{
+		Name:    name,
+		Dir:     dir,
+		Type:    envType,
+		Created: time.Now(),
+	}))
+}
+
+// unregisterEnvironment removes the environment in the directory from the registry.
+func (c *Client) unregisterEnvironment(dir string) error {
+	envs, err := c.registeredEnvironments()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]registeredEnvironment, 0, len(envs))
+
+	for _, env := range envs {
+		if env.Dir != dir {
+			kept = append(kept, env)
+		}
+	}
+
+	if len(kept) == len(envs) {
+		return nil
+	}
+
+	return c.writeRegisteredEnvironments(kept)
+}
+
+func (c *Client) registeredEnvironments() ([]registeredEnvironment, error) {
+	content, err := util.FS.ReadFile(c.EnvironmentsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot read registered environments: %w", err)
+	}
+
+	var envs []registeredEnvironment
+
+	err = json.Unmarshal(content, &envs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse registered environments: %w", err)
+	}
+
+	return envs, nil
+}
+
+func (c *Client) writeRegisteredEnvironments(envs []registeredEnvironment) error {
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+
+	content, err := json.MarshalIndent(envs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal registered environments: %w", err)
+	}
+
+	err = util.CreateDirAndWriteToFile(content, c.EnvironmentsFile())
+	if err != nil {
+		return fmt.Errorf("cannot write registered environments: %w", err)
+	}
+
+	return nil
+}
+
+// readEnvFile reads the .env file of the environment in the directory.
+func readEnvFile(dir string) (*viper.Viper, error) {
+	settings := viper.New()
+	settings.SetConfigFile(filepath.Join(dir, ".env"))
+	settings.SetConfigType("dotenv")
+
+	err := settings.ReadInConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	return settings, nil
+}
+
+// environmentStatus returns the status of the environment in the directory from the state of its containers, eg.
+// "running" or "running (3/5)" if some of its containers are not running.
+func environmentStatus(dir string, containers []*docker.Container) string {
+	var running, total int
+
+	for _, container := range containers {
+		if container.WorkingDir != dir {
+			continue
+		}
+
+		total++
+
+		if container.State == "running" {
+			running++
+		}
+	}
+
+	switch {
+	case running == 0:
+		return envStatusStopped
+	case running < total:
+		return fmt.Sprintf("%s (%d/%d)", envStatusRunning, running, total)
+	default:
+		return envStatusRunning
+	}
+}
+
+// environmentDomains returns the domains served by traefik for the environment: its domain (with the subdomain) and
+// the extra hosts.
+func environmentDomains(settings *viper.Viper) []string {
+	var domains []string
+
+	if domain := settings.GetString("traefik_domain"); domain != "" {
+		if subdomain := settings.GetString("traefik_subdomain"); subdomain != "" {
+			domain = subdomain + "." + domain
+		}
+
+		domains = append(domains, domain)
+	}
+
+	return append(domains, strings.Fields(settings.GetString("traefik_extra_hosts"))...)
+}
@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type EnvListTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EnvListTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestEnvListTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvListTestSuite))
+}
+
+func (suite *EnvListTestSuite) TestRegisterEnvironment() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/user/.reward"})
+
+	assert.NoError(suite.T(), c.registerEnvironment("/sites/shop", "shop", "magento2"))
+	assert.NoError(suite.T(), c.registerEnvironment("/sites/blog", "blog", "wordpress"))
+
+	// the registered environment is updated
+	assert.NoError(suite.T(), c.registerEnvironment("/sites/shop", "store", "magento2"))
+
+	envs, err := c.registeredEnvironments()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), envs, 2)
+	assert.Equal(suite.T(), "blog", envs[0].Name)
+	assert.Equal(suite.T(), "store", envs[1].Name)
+	assert.Equal(suite.T(), "/sites/shop", envs[1].Dir)
+
+	assert.NoError(suite.T(), c.unregisterEnvironment("/sites/blog"))
+	assert.NoError(suite.T(), c.unregisterEnvironment("/sites/unknown"))
+
+	envs, err = c.registeredEnvironments()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), envs, 1)
+	assert.Equal(suite.T(), "store", envs[0].Name)
+}
+
+func (suite *EnvListTestSuite) TestEnvironmentStatus() {
+	containers := []*docker.Container{
+		{WorkingDir: "/sites/shop", State: "running"},
+		{WorkingDir: "/sites/shop", State: "running"},
+		{WorkingDir: "/sites/blog", State: "running"},
+		{WorkingDir: "/sites/blog", State: "exited"},
+		{WorkingDir: "/sites/old", State: "exited"},
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: "/sites/shop", want: "running"},
+		{dir: "/sites/blog", want: "running (1/2)"},
+		{dir: "/sites/old", want: "stopped"},
+		{dir: "/sites/new", want: "stopped"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.dir, func(t *testing.T) {
+			assert.Equal(t, tt.want, environmentStatus(tt.dir, containers))
+		})
+	}
+}
+
+func (suite *EnvListTestSuite) TestEnvironmentDomains() {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     []string
+	}{
+		{
+			name:     "domain",
+			settings: map[string]string{"traefik_domain": "shop.test"},
+			want:     []string{"shop.test"},
+		},
+		{
+			name: "subdomain and extra hosts",
+			settings: map[string]string{
+				"traefik_domain":      "shop.test",
+				"traefik_subdomain":   "app",
+				"traefik_extra_hosts": "other.test  third.test",
+			},
+			want: []string{"app.shop.test", "other.test", "third.test"},
+		},
+		{
+			name: "no domain",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			settings := viper.New()
+			for key, value := range tt.settings {
+				settings.Set(key, value)
+			}
+
+			assert.Equal(t, tt.want, environmentDomains(settings))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+// ErrNotACloneOfCurrentEnv occurs when the environment to promote was not cloned from the current environment.
+var ErrNotACloneOfCurrentEnv = func(clone, env string) error {
+	return fmt.Errorf("environment %s is not a clone of %s, use --force to promote it anyway", clone, env)
+}
+
+// RunCmdEnvPromote switches the traefik domains (and extra hosts) of the current environment and one of its clones,
+// so the clone serves the primary domain. Both .env files are updated before any container is recreated and they
+// are restored if the environments cannot be recreated. The clone is started on the primary domain first, the current
+// environment keeps serving it until then, so the primary domain is not unavailable if the clone cannot be started.
+func (c *Client) RunCmdEnvPromote(cmd *cmdpkg.Command, args []string) error {
+	cloneName := strings.ToLower(args[0])
+
+	cloneDir, _ := cmd.Flags().GetString("dir")
+	if cloneDir == "" {
+		cloneDir = filepath.Join(filepath.Dir(c.Cwd()), cloneName)
+	}
+
+	cloneDir, err := filepath.Abs(cloneDir)
+	if err != nil {
+		return fmt.Errorf("cannot determine absolute path for clone directory: %w", err)
+	}
+
+	origEnvFile := filepath.Join(c.Cwd(), ".env")
+	cloneEnvFile := filepath.Join(cloneDir, ".env")
+
+	clone := viper.New()
+	clone.SetConfigFile(cloneEnvFile)
+	clone.SetConfigType("dotenv")
+
+	err = clone.ReadInConfig()
+	if err != nil {
+		return fmt.Errorf("cannot read configuration of environment %s: %w", cloneName, err)
+	}
+
+	cloneOf := strings.ToLower(clone.GetString(fmt.Sprintf("%s_clone_of", c.AppName())))
+	if force, _ := cmd.Flags().GetBool("force"); !force && cloneOf != c.rawEnvName() {
+		return ErrNotACloneOfCurrentEnv(cloneName, c.rawEnvName())
+	}
+
+	// Keep the original files to be able to roll back.
+	origBackup, err := backupFile(origEnvFile)
+	if err != nil {
+		return fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	cloneBackup, err := backupFile(cloneEnvFile)
+	if err != nil {
+		return fmt.Errorf("cannot read .env file of environment %s: %w", cloneName, err)
+	}
+
+	origDomain, origExtraHosts := c.rawTraefikDomain(), c.GetString("traefik_extra_hosts")
+	cloneDomain, cloneExtraHosts := clone.GetString("traefik_domain"), clone.GetString("traefik_extra_hosts")
+
+	log.Printf("Promoting environment %s to %s...", cloneName, origDomain)
+
+	err = setEnvFileValues(origEnvFile, [][2]string{
+		{"TRAEFIK_DOMAIN", cloneDomain},
+		{"TRAEFIK_EXTRA_HOSTS", cloneExtraHosts},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = setEnvFileValues(cloneEnvFile, [][2]string{
+		{"TRAEFIK_DOMAIN", origDomain},
+		{"TRAEFIK_EXTRA_HOSTS", origExtraHosts},
+	})
+	if err != nil {
+		_ = origBackup.restore()
+
+		return err
+	}
+
+	// Recreate the containers of both environments with the swapped router rules. The clone is recreated first, so
+	// the current environment serves the primary domain until the clone is started. The environments which were
+	// recreated are recreated again with the original domains if an environment cannot be recreated.
+	recreated := make([]string, 0, 2)
+
+	for _, dir := range []string{cloneDir, c.Cwd()} {
+		recreated = append(recreated, dir)
+
+		err = c.runSelfInDir(dir, nil, "env", "up")
+		if err != nil {
+			log.Errorln("Cannot recreate environments, restoring the original domains...")
+
+			_ = origBackup.restore()
+			_ = cloneBackup.restore()
+
+			for i := len(recreated) - 1; i >= 0; i-- {
+				if rollbackErr := c.runSelfInDir(recreated[i], nil, "env", "up"); rollbackErr != nil {
+					log.Errorf("Cannot restore environment in %s: %s", recreated[i], rollbackErr)
+				}
+			}
+
+			return fmt.Errorf("cannot recreate environment in %s: %w", dir, err)
+		}
+	}
+
+	log.Printf("...environment %s is now available at https://%s/.", cloneName, c.namespaced(origDomain, "."))
+	log.Printf("Environment %s is now available at https://%s/.", c.rawEnvName(), c.namespaced(cloneDomain, "."))
+
+	return nil
+}
+
+// fileBackup is the content and the mode of a file.
+type fileBackup struct {
+	path    string
+	content []byte
+	mode    os.FileMode
+}
+
+// backupFile reads the content and the mode of the file at path.
+func backupFile(path string) (*fileBackup, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileBackup{path: path, content: content, mode: info.Mode().Perm()}, nil
+}
+
+// restore writes back the original content and mode of the file.
+func (b *fileBackup) restore() error {
+	err := os.WriteFile(b.path, b.content, b.mode)
+	if err != nil {
+		return fmt.Errorf("cannot restore %s: %w", b.path, err)
+	}
+
+	return os.Chmod(b.path, b.mode)
+}
+
+// rawEnvName returns the environment name without the namespace added in shared mode.
+func (c *Client) rawEnvName() string {
+	if !c.SharedMode() || c.Namespace() == "" {
+		return c.EnvName()
+	}
+
+	return strings.TrimPrefix(c.EnvName(), c.Namespace()+"-")
+}
+
+// rawTraefikDomain returns the traefik domain without the namespace added in shared mode.
+func (c *Client) rawTraefikDomain() string {
+	if !c.SharedMode() || c.Namespace() == "" {
+		return c.TraefikDomain()
+	}
+
+	return strings.TrimPrefix(c.TraefikDomain(), c.Namespace()+".")
+}
@@ -0,0 +1,43 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvPromoteTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvPromoteTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvPromoteTestSuite))
+}
+
+func (suite *EnvPromoteTestSuite) TestFileBackup() {
+	path := filepath.Join(suite.T().TempDir(), ".env")
+
+	assert.NoError(suite.T(), os.WriteFile(path, []byte("TRAEFIK_DOMAIN=shop.test\n"), 0o600))
+
+	backup, err := backupFile(path)
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), os.WriteFile(path, []byte("TRAEFIK_DOMAIN=upgrade.shop.test\n"), 0o600))
+	assert.NoError(suite.T(), os.Chmod(path, 0o644))
+
+	assert.NoError(suite.T(), backup.restore())
+
+	content, err := os.ReadFile(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "TRAEFIK_DOMAIN=shop.test\n", string(content))
+
+	info, err := os.Stat(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = backupFile(filepath.Join(suite.T().TempDir(), "missing"))
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// serviceDependents are the services which resolve the address of the service only when they load their
+// configuration, so they have to be reloaded after the service is restarted.
+var serviceDependents = map[string][]string{
+	"php-fpm":       {"nginx"},
+	"php-debug":     {"nginx"},
+	"php-blackfire": {"nginx"},
+	"nginx":         {"varnish"},
+}
+
+// serviceReloaders reload the configuration of the service without restarting it.
+var serviceReloaders = map[string]func(c *Client) error{
+	"nginx":   (*Client).RunCmdNginxReload,
+	"varnish": (*Client).RunCmdVarnishReload,
+}
+
+// RunCmdEnvRestart restarts (or recreates) the services of the environment without running `env up`, then reloads
+// or restarts the services which depend on them. If no services are passed, all services are restarted.
+func (c *Client) RunCmdEnvRestart(cmd *cmdpkg.Command, services []string) error {
+	applyConfig, _ := cmd.Flags().GetBool("apply-config")
+	recreate, _ := cmd.Flags().GetBool("recreate")
+
+	if applyConfig {
+		log.Println("Rendering configuration...")
+
+		err := c.prepareNginxConfigs()
+		if err != nil {
+			return fmt.Errorf("cannot prepare nginx configs: %w", err)
+		}
+
+		if c.SvcEnabledStrict("varnish") {
+			err = util.CreateDir(c.VarnishCustomConfigsPath(), nil)
+			if err != nil {
+				return fmt.Errorf("cannot create varnish snippets directory: %w", err)
+			}
+		}
+	}
+
+	// restart keeps the container, up recreates it if its configuration changed (or always with --force-recreate)
+	args := []string{"restart"}
+	if applyConfig || recreate {
+		args = []string{"up", "--detach", "--no-deps"}
+		if recreate {
+			args = append(args, "--force-recreate")
+		}
+	}
+
+	err := c.RunCmdEnvDockerCompose(append(args, services...), shell.WithCatchOutput(false))
+	if err != nil {
+		return err
+	}
+
+	if applyConfig || recreate {
+		// the synced container might have been recreated
+		err = c.updateMutagen([]string{"up"})
+		if err != nil {
+			return fmt.Errorf("an error occurred while updating mutagen: %w", err)
+		}
+	}
+
+	for _, dependent := range restartDependents(services) {
+		if !c.Docker.ContainerRunning(dependent) {
+			continue
+		}
+
+		if reload, ok := serviceReloaders[dependent]; ok {
+			err = reload(c)
+		} else {
+			err = c.RunCmdEnvDockerCompose([]string{"restart", dependent}, shell.WithCatchOutput(false))
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot reload %s: %w", dependent, err)
+		}
+	}
+
+	return nil
+}
+
+// restartDependents returns the services which depend on the restarted services (directly or transitively) in the
+// order they have to be reloaded. The restarted services themselves are not returned. If no services are passed,
+// all services are restarted, so there is nothing to reload.
+func restartDependents(services []string) []string {
+	var (
+		dependents = make([]string, 0)
+		seen       = make(map[string]bool)
+		queue      = append([]string(nil), services...)
+	)
+
+	for _, service := range services {
+		seen[service] = true
+	}
+
+	for len(queue) > 0 {
+		service := queue[0]
+		queue = queue[1:]
+
+		for _, dependent := range serviceDependents[service] {
+			if seen[dependent] {
+				continue
+			}
+
+			seen[dependent] = true
+			dependents = append(dependents, dependent)
+			queue = append(queue, dependent)
+		}
+	}
+
+	return dependents
+}
@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvRestartTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvRestartTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvRestartTestSuite))
+}
+
+func (suite *EnvRestartTestSuite) TestRestartDependents() {
+	tests := []struct {
+		name     string
+		services []string
+		want     []string
+	}{
+		{name: "all services", services: nil, want: []string{}},
+		{name: "php-fpm reloads nginx and varnish", services: []string{"php-fpm"}, want: []string{"nginx", "varnish"}},
+		{name: "nginx reloads varnish", services: []string{"nginx"}, want: []string{"varnish"}},
+		{name: "restarted services are not reloaded", services: []string{"php-fpm", "nginx"}, want: []string{"varnish"}},
+		{name: "service without dependents", services: []string{"redis"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, restartDependents(tt.services))
+		})
+	}
+}
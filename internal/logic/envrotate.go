@@ -0,0 +1,349 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/sethvargo/go-password/password"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrUnknownRotateService occurs when the credentials of the service cannot be rotated.
+var ErrUnknownRotateService = func(service string) error {
+	return fmt.Errorf("cannot rotate the credentials of %s, valid options: db, rabbitmq, redis", service)
+}
+
+// ErrRotateServiceNotRunning occurs when the service whose credentials are rotated is not running.
+var ErrRotateServiceNotRunning = func(service string) error {
+	return fmt.Errorf("%s is not running, start the environment using `reward env up`", service)
+}
+
+// ErrRotateDBUserNotFound occurs when the database user of the application doesn't exist, so its password cannot be
+// changed.
+var ErrRotateDBUserNotFound = func(user string) error {
+	return fmt.Errorf("the database user '%s'@'%%' doesn't exist, set MYSQL_USER in the .env file to the user of "+
+		"the application", user)
+}
+
+// ErrRotatePostgres occurs when the credentials of a PostgreSQL database are rotated.
+var ErrRotatePostgres = fmt.Errorf("rotating the credentials of PostgreSQL is not supported")
+
+// rotateServices are the services whose credentials can be rotated, in the order they're rotated.
+var rotateServices = []string{"db", "rabbitmq", "redis"}
+
+// rotatedCredentials are the new passwords of the services.
+type rotatedCredentials struct {
+	db       string
+	dbRoot   string
+	rabbitmq string
+	redis    string
+}
+
+// RunCmdEnvRotateCredentials generates new passwords for the database, RabbitMQ and Redis of the environment, applies
+// them in the running services, saves them to the .env file and updates the configuration of the application where
+// it's known (the env.php of Magento 2). By default, the credentials of all the enabled services are rotated.
+func (c *Client) RunCmdEnvRotateCredentials(services []string) error {
+	if len(services) == 0 {
+		for _, service := range rotateServices {
+			if c.ServiceEnabled(service) {
+				services = append(services, service)
+			}
+		}
+	}
+
+	for _, service := range services {
+		if !util.ContainsString(rotateServices, service) {
+			return ErrUnknownRotateService(service)
+		}
+
+		if !c.Docker.ContainerRunning(c.rotateContainer(service)) {
+			return ErrRotateServiceNotRunning(service)
+		}
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf(
+		"The credentials of %s are going to be replaced. Continue?", strings.Join(services, ", ")),
+	) {
+		return nil
+	}
+
+	var (
+		creds  rotatedCredentials
+		values [][2]string
+	)
+
+	for _, service := range services {
+		var err error
+
+		switch service {
+		case "db":
+			values, err = c.rotateDBCredentials(&creds, values)
+		case "rabbitmq":
+			values, err = c.rotateRabbitMQCredentials(&creds, values)
+		case "redis":
+			values, err = c.rotateRedisCredentials(&creds, values)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot rotate the credentials of %s: %w", service, err)
+		}
+
+		// the new credentials are saved after each service, so they're not lost if a later service fails
+		err = setEnvFileValues(filepath.Join(c.Cwd(), ".env"), values)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("...credentials of %s rotated.", service)
+	}
+
+	err := c.rotateApplicationCredentials(creds)
+	if err != nil {
+		return err
+	}
+
+	if util.ContainsString(services, "db") {
+		// the commands of the db container read the credentials from its environment
+		err = c.runSelfInDir(c.Cwd(), nil, "env", "up", "-d", "db")
+		if err != nil {
+			return fmt.Errorf("cannot recreate the database container: %w", err)
+		}
+	}
+
+	log.Println("Credentials rotated and saved to the .env file.")
+
+	return nil
+}
+
+func (c *Client) rotateContainer(service string) string {
+	if service == "db" {
+		return c.DBContainer()
+	}
+
+	return service
+}
+
+// rotateDBCredentials changes the passwords of the application user and the root user of the database.
+func (c *Client) rotateDBCredentials(creds *rotatedCredentials, values [][2]string) ([][2]string, error) {
+	if c.dbPostgres() {
+		return values, ErrRotatePostgres
+	}
+
+	log.Println("Rotating the credentials of the database...")
+
+	var err error
+
+	creds.db, err = generateServicePassword()
+	if err != nil {
+		return values, err
+	}
+
+	creds.dbRoot, err = generateServicePassword()
+	if err != nil {
+		return values, err
+	}
+
+	user := c.dbSetting("user")
+
+	// ALTER USER IF EXISTS silently changes nothing if the user doesn't exist, and the application would be locked out
+	// with the new password
+	out, err := c.rotateExecOutput(c.DBContainer(), fmt.Sprintf(`%s -uroot -p"$MYSQL_ROOT_PASSWORD" -N -B -e %s`,
+		c.DBCommand(), util.QuotePOSIX(dbUserExistsQuery(user))),
+	)
+	if err != nil {
+		return values, err
+	}
+
+	if strings.TrimSpace(out) != "1" {
+		return values, ErrRotateDBUserNotFound(user)
+	}
+
+	query := dbRotateQuery(user, creds.db, creds.dbRoot)
+
+	// the running container still has the previous root password in its environment
+	err = c.rotateExec(c.DBContainer(),
+		fmt.Sprintf(`%s -uroot -p"$MYSQL_ROOT_PASSWORD" -e %s`, c.DBCommand(), util.QuotePOSIX(query)),
+	)
+	if err != nil {
+		return values, err
+	}
+
+	values = append(values, [2]string{"MYSQL_PASSWORD", creds.db}, [2]string{"MYSQL_ROOT_PASSWORD", creds.dbRoot})
+
+	// the application of sylius reads the credentials from DATABASE_URL
+	if databaseURL, ok := rotateDatabaseURL(c.GetString("database_url"), user, creds.db); ok {
+		values = append(values, [2]string{"DATABASE_URL", databaseURL})
+	}
+
+	return values, nil
+}
+
+// rotateRabbitMQCredentials changes the password of the RabbitMQ user of the application.
+func (c *Client) rotateRabbitMQCredentials(creds *rotatedCredentials, values [][2]string) ([][2]string, error) {
+	log.Println("Rotating the credentials of RabbitMQ...")
+
+	var err error
+
+	creds.rabbitmq, err = generateServicePassword()
+	if err != nil {
+		return values, err
+	}
+
+	err = c.rotateExec("rabbitmq",
+		fmt.Sprintf("rabbitmqctl change_password %s %s", c.RabbitMQUser(), creds.rabbitmq),
+	)
+	if err != nil {
+		return values, err
+	}
+
+	return append(values, [2]string{"RABBITMQ_PASSWORD", creds.rabbitmq}), nil
+}
+
+// rotateRedisCredentials sets the password of Redis. It's applied in the running container, and the container is
+// started with the password from the .env file when it's recreated.
+func (c *Client) rotateRedisCredentials(creds *rotatedCredentials, values [][2]string) ([][2]string, error) {
+	log.Println("Rotating the credentials of Redis...")
+
+	var err error
+
+	creds.redis, err = generateServicePassword()
+	if err != nil {
+		return values, err
+	}
+
+	auth := ""
+	if current := c.RedisPassword(); current != "" {
+		auth = fmt.Sprintf("-a %s --no-auth-warning ", current)
+	}
+
+	err = c.rotateExec("redis", fmt.Sprintf("redis-cli %sCONFIG SET requirepass %s", auth, creds.redis))
+	if err != nil {
+		return values, err
+	}
+
+	return append(values, [2]string{"REDIS_PASSWORD", creds.redis}), nil
+}
+
+// rotateApplicationCredentials updates the new credentials in the configuration of the application if it's known.
+func (c *Client) rotateApplicationCredentials(creds rotatedCredentials) error {
+	if c.EnvType() != "magento2" || !util.FileExists(filepath.Join(c.Cwd(), c.WebRoot(), "app", "etc", "env.php")) {
+		log.Println("Update the credentials in the configuration of the application if it doesn't read them from " +
+			"the .env file.")
+
+		return nil
+	}
+
+	log.Println("Updating the credentials in app/etc/env.php...")
+
+	err := c.RunCmdEnvExec("bin/magento setup:config:set --no-interaction " + strings.Join(magento2RotateArgs(creds), " "))
+	if err != nil {
+		return fmt.Errorf("cannot update app/etc/env.php: %w", err)
+	}
+
+	return nil
+}
+
+// dbRotateQuery returns the statements which change the passwords of the application user and the root user.
+func dbRotateQuery(user, pass, rootPass string) string {
+	return fmt.Sprintf(
+		"ALTER USER IF EXISTS '%[1]s'@'%%' IDENTIFIED BY '%[2]s'; "+
+			"ALTER USER IF EXISTS 'root'@'%%' IDENTIFIED BY '%[3]s'; "+
+			"ALTER USER IF EXISTS 'root'@'localhost' IDENTIFIED BY '%[3]s'; FLUSH PRIVILEGES;",
+		user, pass, rootPass,
+	)
+}
+
+// dbUserExistsQuery returns the statement which counts the database users whose password is changed by dbRotateQuery.
+func dbUserExistsQuery(user string) string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM mysql.user WHERE User = '%s' AND Host = '%%';", user)
+}
+
+// rotateDatabaseURL returns the database url with the new password if the url belongs to the user.
+func rotateDatabaseURL(databaseURL, user, pass string) (string, bool) {
+	if databaseURL == "" {
+		return "", false
+	}
+
+	u, err := url.Parse(databaseURL)
+	if err != nil || u.User == nil || u.User.Username() != user {
+		return "", false
+	}
+
+	u.User = url.UserPassword(user, pass)
+
+	return u.String(), true
+}
+
+// magento2RotateArgs returns the arguments of setup:config:set which update the rotated credentials in env.php.
+func magento2RotateArgs(creds rotatedCredentials) []string {
+	var args []string
+
+	if creds.db != "" {
+		args = append(args, "--db-password="+creds.db)
+	}
+
+	if creds.rabbitmq != "" {
+		args = append(args, "--amqp-password="+creds.rabbitmq)
+	}
+
+	if creds.redis != "" {
+		args = append(args,
+			"--cache-backend-redis-password="+creds.redis,
+			"--page-cache-redis-password="+creds.redis,
+			"--session-save-redis-password="+creds.redis,
+		)
+	}
+
+	return args
+}
+
+// rotateExec runs the shell command in the container of the service.
+func (c *Client) rotateExec(service, command string) error {
+	container, err := c.Docker.RunningEnvServiceContainer(service)
+	if err != nil {
+		return ErrRotateServiceNotRunning(service)
+	}
+
+	out, err := cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c", command).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+
+	return nil
+}
+
+// rotateExecOutput runs the shell command in the container of the service and returns its standard output.
+func (c *Client) rotateExecOutput(service, command string) (string, error) {
+	container, err := c.Docker.RunningEnvServiceContainer(service)
+	if err != nil {
+		return "", ErrRotateServiceNotRunning(service)
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c", command)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, stderr.String())
+	}
+
+	return string(out), nil
+}
+
+// generateServicePassword returns a password which doesn't need quoting in shell commands and SQL statements.
+func generateServicePassword() (string, error) {
+	pass, err := password.Generate(24, 6, 0, false, true)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate password: %w", err)
+	}
+
+	return pass, nil
+}
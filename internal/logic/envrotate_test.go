@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvRotateTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvRotateTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvRotateTestSuite))
+}
+
+func (suite *EnvRotateTestSuite) TestDBRotateQuery() {
+	assert.Equal(suite.T(),
+		"ALTER USER IF EXISTS 'magento'@'%' IDENTIFIED BY 'new'; "+
+			"ALTER USER IF EXISTS 'root'@'%' IDENTIFIED BY 'root'; "+
+			"ALTER USER IF EXISTS 'root'@'localhost' IDENTIFIED BY 'root'; FLUSH PRIVILEGES;",
+		dbRotateQuery("magento", "new", "root"),
+	)
+}
+
+func (suite *EnvRotateTestSuite) TestDBUserExistsQuery() {
+	assert.Equal(suite.T(),
+		"SELECT COUNT(*) FROM mysql.user WHERE User = 'sylius' AND Host = '%';",
+		dbUserExistsQuery("sylius"),
+	)
+}
+
+func (suite *EnvRotateTestSuite) TestRotateDatabaseURL() {
+	tests := []struct {
+		name        string
+		databaseURL string
+		want        string
+		wantOk      bool
+	}{
+		{
+			name:        "sylius",
+			databaseURL: "mysql://sylius:sylius@db:3306/sylius?serverVersion=mariadb-10.6.0",
+			want:        "mysql://sylius:new@db:3306/sylius?serverVersion=mariadb-10.6.0",
+			wantOk:      true,
+		},
+		{
+			name:        "other user",
+			databaseURL: "mysql://app:app@db:3306/sylius",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, ok := rotateDatabaseURL(tt.databaseURL, "sylius", "new")
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *EnvRotateTestSuite) TestDBSetting() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		want     string
+	}{
+		{name: "magento2", settings: map[string]interface{}{"reward_env_type": "magento2"}, want: "magento"},
+		{name: "akeneo", settings: map[string]interface{}{"reward_env_type": "akeneo"}, want: "akeneo_pim"},
+		{name: "generic-php", settings: map[string]interface{}{"reward_env_type": "generic-php"}, want: "app"},
+		{name: "oro", settings: map[string]interface{}{"reward_env_type": "oro"}, want: "oro"},
+		{
+			name:     "mysql_user",
+			settings: map[string]interface{}{"reward_env_type": "sylius", "mysql_user": "custom"},
+			want:     "custom",
+		},
+		{
+			name:     "postgres_user",
+			settings: map[string]interface{}{"reward_env_type": "oro", "mysql_user": "app", "postgres_user": "custom"},
+			want:     "custom",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newTestClient(tt.settings).dbSetting("user"))
+		})
+	}
+}
+
+func (suite *EnvRotateTestSuite) TestMagento2RotateArgs() {
+	tests := []struct {
+		name  string
+		creds rotatedCredentials
+		want  []string
+	}{
+		{
+			name:  "db only",
+			creds: rotatedCredentials{db: "dbpass", dbRoot: "rootpass"},
+			want:  []string{"--db-password=dbpass"},
+		},
+		{
+			name:  "rabbitmq and redis",
+			creds: rotatedCredentials{rabbitmq: "amqp", redis: "cache"},
+			want: []string{
+				"--amqp-password=amqp",
+				"--cache-backend-redis-password=cache",
+				"--page-cache-redis-password=cache",
+				"--session-save-redis-password=cache",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, magento2RotateArgs(tt.creds))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrErrorLogNoSources occurs when none of the containers of the error logs are running.
+var ErrErrorLogNoSources = fmt.Errorf("none of the php-fpm and nginx containers are running, start the environment " +
+	"using `reward env up`")
+
+// ErrInvalidErrorLogGrep occurs when the --grep pattern is not a valid regular expression.
+var ErrInvalidErrorLogGrep = func(pattern string, err error) error {
+	return fmt.Errorf("invalid grep pattern %s: %w", pattern, err)
+}
+
+var (
+	// errorLogTimeRegex matches the timestamp at the beginning of the application log lines, eg.
+	// [2023-01-02T15:04:05.123456+00:00] (monolog) or [2023-01-02 15:04:05] (laravel).
+	errorLogTimeRegex = regexp.MustCompile(
+		`^\[(\d{4}-\d{2}-\d{2})[T ](\d{2}:\d{2}:\d{2})(?:\.\d+)?(Z|[+-]\d{2}:?\d{2})?\]`,
+	)
+	// errorLogFileHeaderRegex matches the header which is printed by tail when it switches to another file.
+	errorLogFileHeaderRegex = regexp.MustCompile(`^==> (.+) <==$`)
+	// errorLogColors are the colors of the sources, they're assigned in order.
+	errorLogColors = []string{"\033[35m", "\033[36m", "\033[33m", "\033[32m", "\033[34m", "\033[31m"}
+)
+
+const errorLogColorReset = "\033[0m"
+
+// errorLogSource is a stream of log lines of a container.
+type errorLogSource struct {
+	name string
+	cmd  *exec.Cmd
+	// stream is the output of the command which contains the log lines. The docker logs contain the error logs on
+	// stderr, the access logs on stdout are discarded.
+	stream func(*exec.Cmd) (io.ReadCloser, error)
+	// files is true if the source tails files and the names of the files are printed by tail.
+	files bool
+}
+
+// errorLogLine is a line of a source.
+type errorLogLine struct {
+	source string
+	text   string
+}
+
+// errorLogFilter filters the lines of a source. The lines without timestamp (eg. the stack traces) are kept or
+// dropped along with the last line which had a timestamp.
+type errorLogFilter struct {
+	grep  *regexp.Regexp
+	since time.Time
+	// dropping is true if the last line with timestamp was older than since.
+	dropping bool
+}
+
+// RunCmdErrorsTail merges the php-fpm and nginx error logs and the application logs of the environment into a single
+// stream. It runs until it's interrupted.
+func (c *Client) RunCmdErrorsTail(cmd *cmdpkg.Command) error {
+	var (
+		pattern, _ = cmd.Flags().GetString("grep")
+		since, _   = cmd.Flags().GetDuration("since")
+		lines, _   = cmd.Flags().GetInt("lines")
+		grep       *regexp.Regexp
+	)
+
+	if pattern != "" {
+		var err error
+
+		grep, err = regexp.Compile(pattern)
+		if err != nil {
+			return ErrInvalidErrorLogGrep(pattern, err)
+		}
+	}
+
+	sources := c.errorLogSources(since, lines)
+	if len(sources) == 0 {
+		return ErrErrorLogNoSources
+	}
+
+	var (
+		out    = make(chan errorLogLine)
+		wg     sync.WaitGroup
+		colors = make(map[string]string)
+		color  = !c.NoColor() && util.IsTerminal(os.Stdout)
+		width  int
+	)
+
+	for i, source := range sources {
+		colors[source.name] = errorLogColors[i%len(errorLogColors)]
+
+		if len(source.name) > width {
+			width = len(source.name)
+		}
+
+		stream, err := source.stream(source.cmd)
+		if err != nil {
+			return fmt.Errorf("cannot read the logs of %s: %w", source.name, err)
+		}
+
+		log.Debugf("Running command: %s", source.cmd.String())
+
+		err = source.cmd.Start()
+		if err != nil {
+			return fmt.Errorf("cannot read the logs of %s: %w", source.name, err)
+		}
+
+		wg.Add(1)
+
+		go func(source *errorLogSource, stream io.ReadCloser) {
+			defer wg.Done()
+
+			readErrorLog(source, stream, newErrorLogFilter(grep, since), out)
+
+			_ = source.cmd.Wait()
+		}(source, stream)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	for line := range out {
+		// the names of the application logs are known when they're read
+		if len(line.source) > width {
+			width = len(line.source)
+		}
+
+		prefix := fmt.Sprintf("%-*s |", width, line.source)
+		if color {
+			prefix = colors[strings.SplitN(line.source, ":", 2)[0]] + prefix + errorLogColorReset
+		}
+
+		fmt.Println(prefix, line.text)
+	}
+
+	return nil
+}
+
+// errorLogSources returns the sources of the running containers of the environment.
+func (c *Client) errorLogSources(since time.Duration, lines int) []*errorLogSource {
+	var sources []*errorLogSource
+
+	for _, service := range []string{"php-fpm", "nginx"} {
+		container, err := c.Docker.RunningEnvServiceContainer(service)
+		if err != nil {
+			log.Debugf("Skipping the error log of %s: %s", service, err)
+
+			continue
+		}
+
+		args := []string{"logs", "--follow", "--tail", strconv.Itoa(lines)}
+		if since > 0 {
+			args = []string{"logs", "--follow", "--since", since.String()}
+		}
+
+		sources = append(sources, &errorLogSource{
+			name:   service,
+			cmd:    cmdpkg.Cmnd("docker", append(args, container.ID)...),
+			stream: (*exec.Cmd).StderrPipe,
+		})
+	}
+
+	paths := errorLogAppPaths(c.EnvType())
+	if len(paths) == 0 {
+		return sources
+	}
+
+	container, err := c.Docker.RunningEnvServiceContainer("php-fpm")
+	if err != nil {
+		return sources
+	}
+
+	// With --since, the timestamps of the lines are filtered, so more lines of the files are read.
+	if since > 0 {
+		lines = 1000
+	}
+
+	sources = append(sources, &errorLogSource{
+		name: "app",
+		// The globs are expanded by the shell of the container. tail -F waits for the files which don't exist yet.
+		cmd: cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c",
+			fmt.Sprintf("exec tail -n %d -F %s 2>/dev/null", lines, strings.Join(paths, " ")),
+		),
+		stream: (*exec.Cmd).StdoutPipe,
+		files:  true,
+	})
+
+	return sources
+}
+
+// errorLogAppPaths returns the globs of the application logs of the environment type, relative to the document root.
+func errorLogAppPaths(envType string) []string {
+	switch envType {
+	case "magento1", "magento2", "symfony", "shopware", "sylius", "akeneo", "oro":
+		return []string{"var/log/*.log"}
+	case "laravel", "craft":
+		return []string{"storage/logs/*.log"}
+	case "typo3":
+		return []string{"var/log/*.log", "typo3temp/var/log/*.log"}
+	case "wordpress":
+		return []string{"wp-content/debug.log"}
+	default:
+		return nil
+	}
+}
+
+// readErrorLog reads the lines of the source and sends the ones which pass the filter.
+func readErrorLog(source *errorLogSource, stream io.Reader, filter *errorLogFilter, out chan<- errorLogLine) {
+	name := source.name
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		if source.files {
+			if m := errorLogFileHeaderRegex.FindStringSubmatch(text); m != nil {
+				name = source.name + ":" + strings.TrimSuffix(m[1][strings.LastIndex(m[1], "/")+1:], ".log")
+
+				continue
+			}
+
+			if text == "" {
+				continue
+			}
+		}
+
+		if filter.keep(text, source.files) {
+			out <- errorLogLine{source: name, text: text}
+		}
+	}
+}
+
+func newErrorLogFilter(grep *regexp.Regexp, since time.Duration) *errorLogFilter {
+	f := &errorLogFilter{grep: grep}
+	if since > 0 {
+		f.since = time.Now().Add(-since)
+	}
+
+	return f
+}
+
+// keep returns true if the line passes the filter. The timestamps are only checked if checkTime is true, the docker
+// logs are filtered by docker.
+func (f *errorLogFilter) keep(line string, checkTime bool) bool {
+	if checkTime && !f.since.IsZero() {
+		if t, ok := errorLogLineTime(line); ok {
+			f.dropping = t.Before(f.since)
+		}
+
+		if f.dropping {
+			return false
+		}
+	}
+
+	return f.grep == nil || f.grep.MatchString(line)
+}
+
+// errorLogLineTime returns the timestamp at the beginning of the application log line. The timestamps without time
+// zone are in UTC, as the containers run in UTC.
+func errorLogLineTime(line string) (time.Time, bool) {
+	m := errorLogTimeRegex.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	zone := strings.Replace(m[3], ":", "", 1)
+	if zone == "" || zone == "Z" {
+		zone = "+0000"
+	}
+
+	t, err := time.Parse("2006-01-02 15:04:05 -0700", fmt.Sprintf("%s %s %s", m[1], m[2], zone))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
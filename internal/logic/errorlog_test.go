@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorLogLineTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "monolog",
+			line:   "[2023-01-02T15:04:05.123456+02:00] main.CRITICAL: Exception",
+			want:   time.Date(2023, 1, 2, 13, 4, 5, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "laravel",
+			line:   "[2023-01-02 15:04:05] local.ERROR: Exception",
+			want:   time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name: "stack trace",
+			line: "#0 /var/www/html/index.php(10): main()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := errorLogLineTime(tt.line)
+			assert.Equal(t, tt.wantOK, ok)
+
+			if tt.wantOK {
+				assert.True(t, tt.want.Equal(got), "got %s", got)
+			}
+		})
+	}
+}
+
+func TestReadErrorLog(t *testing.T) {
+	since := time.Date(2023, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	input := strings.Join([]string{
+		"==> var/log/system.log <==",
+		"[2023-01-02T14:00:00+00:00] main.ERROR: old error",
+		"#0 old stack trace",
+		"",
+		"==> var/log/exception.log <==",
+		"[2023-01-02T15:30:00+00:00] main.CRITICAL: new exception",
+		"#0 new stack trace",
+		"[2023-01-02T15:31:00+00:00] main.INFO: new info",
+	}, "\n")
+
+	var (
+		out    = make(chan errorLogLine, 10)
+		source = &errorLogSource{name: "app", files: true}
+		filter = &errorLogFilter{grep: regexp.MustCompile("exception|stack"), since: since}
+	)
+
+	readErrorLog(source, strings.NewReader(input), filter, out)
+	close(out)
+
+	var got []errorLogLine
+	for line := range out {
+		got = append(got, line)
+	}
+
+	assert.Equal(t, []errorLogLine{
+		{source: "app:exception", text: "[2023-01-02T15:30:00+00:00] main.CRITICAL: new exception"},
+		{source: "app:exception", text: "#0 new stack trace"},
+	}, got)
+}
@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// RunCmdFixPermissions changes the ownership of the given paths (or the default paths of the environment type)
+// inside the synced container to the configured user and group IDs.
+func (c *Client) RunCmdFixPermissions(args []string) error {
+	paths := args
+	if len(paths) == 0 {
+		paths = c.FixPermissionsPaths()
+	}
+
+	quotedPaths := make([]string, len(paths))
+	for i, path := range paths {
+		quotedPaths[i] = util.QuotePOSIX(path)
+	}
+
+	log.Printf("Fixing permissions of %s (owner: %d:%d)...", strings.Join(paths, ", "), c.UID(), c.GID())
+
+	err := c.RunCmdEnvDockerCompose(
+		[]string{
+			"exec", "-T", "--user", "root", "--workdir", c.DefaultSyncedDir(c.EnvType()),
+			c.DefaultSyncedContainer(c.EnvType()), "sh", "-c",
+			fmt.Sprintf(
+				`for DIR in %s; do `+
+					`if [ -e "${DIR}" ]; then chown -R %d:%d "${DIR}" && chmod -R ug+rwX "${DIR}"; fi; `+
+					`done`,
+				strings.Join(quotedPaths, " "),
+				c.UID(),
+				c.GID(),
+			),
+		},
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot fix permissions: %w", err)
+	}
+
+	log.Println("...permissions fixed.")
+
+	return nil
+}
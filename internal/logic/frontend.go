@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrFrontendNotEnabled occurs when a frontend command runs but the frontend sidecar is not enabled.
+var ErrFrontendNotEnabled = func(appName string) error {
+	return fmt.Errorf(
+		"the frontend sidecar is not enabled, set %s_FRONTEND=true in the .env file",
+		strings.ToUpper(appName),
+	)
+}
+
+// ErrHyvaThemeNotSet occurs when the Hyvä theme to watch is not configured.
+var ErrHyvaThemeNotSet = func(appName string) error {
+	return fmt.Errorf(
+		"the hyva theme is not configured, set %s_HYVA_THEME=Vendor/theme in the .env file",
+		strings.ToUpper(appName),
+	)
+}
+
+// gruntThemedTasks are the tasks of the Magento grunt workflow which have a target for each theme.
+var gruntThemedTasks = []string{"clean", "exec", "less", "watch"}
+
+// checkFrontend returns an error if the frontend sidecar is not available.
+func (c *Client) checkFrontend() error {
+	if c.EnvType() != "magento2" {
+		return fmt.Errorf("the frontend sidecar is only supported for magento2 environments")
+	}
+
+	if !c.FrontendEnabled() {
+		return ErrFrontendNotEnabled(c.AppName())
+	}
+
+	return nil
+}
+
+// RunCmdFrontendWatch starts the frontend sidecar (which runs the tailwind watcher of the Hyvä theme) and streams
+// the watcher output.
+func (c *Client) RunCmdFrontendWatch(cmd *cmdpkg.Command) error {
+	err := c.checkFrontend()
+	if err != nil {
+		return err
+	}
+
+	if c.HyvaTheme() == "" {
+		return ErrHyvaThemeNotSet(c.AppName())
+	}
+
+	lines, _ := cmd.Flags().GetInt("lines")
+
+	log.Printf("Starting the tailwind watcher of the %s theme...", c.HyvaTheme())
+
+	err = c.RunCmdEnvDockerCompose([]string{"up", "-d", "frontend"})
+	if err != nil {
+		return fmt.Errorf("cannot start frontend container: %w", err)
+	}
+
+	err = c.RunCmdEnvDockerCompose(
+		[]string{"logs", "--no-log-prefix", "--follow", "--tail", strconv.Itoa(lines), "frontend"},
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot read frontend logs: %w", err)
+	}
+
+	return nil
+}
+
+// RunCmdFrontendGrunt runs a task of the Magento grunt workflow in the frontend sidecar. If the task has no target
+// and it has a target for each theme, the configured theme is used as the target (eg. `less` runs `less:luma`).
+func (c *Client) RunCmdFrontendGrunt(cmd *cmdpkg.Command, args []string) error {
+	err := c.checkFrontend()
+	if err != nil {
+		return err
+	}
+
+	theme, _ := cmd.Flags().GetString("theme")
+	if theme == "" {
+		theme = c.FrontendTheme()
+	}
+
+	tasks := make([]string, len(args))
+	for i, task := range args {
+		tasks[i] = gruntTask(task, theme)
+	}
+
+	err = c.RunCmdEnvDockerCompose([]string{"up", "-d", "frontend"})
+	if err != nil {
+		return fmt.Errorf("cannot start frontend container: %w", err)
+	}
+
+	err = c.RunCmdEnvDockerCompose(
+		append([]string{"exec", "-w", "/var/www/html", "frontend", "node_modules/.bin/grunt"}, tasks...),
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot run grunt: %w", err)
+	}
+
+	return nil
+}
+
+// gruntTask returns the task with the theme as its target if the task has a target for each theme.
+func gruntTask(task, theme string) string {
+	if theme == "" || strings.HasPrefix(task, "-") || strings.Contains(task, ":") ||
+		!util.ContainsString(gruntThemedTasks, task) {
+		return task
+	}
+
+	return fmt.Sprintf("%s:%s", task, theme)
+}
@@ -0,0 +1,37 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FrontendTestSuite struct {
+	suite.Suite
+}
+
+func TestFrontendTestSuite(t *testing.T) {
+	suite.Run(t, new(FrontendTestSuite))
+}
+
+func (suite *FrontendTestSuite) TestGruntTask() {
+	tests := []struct {
+		name  string
+		task  string
+		theme string
+		want  string
+	}{
+		{name: "themed task", task: "less", theme: "luma", want: "less:luma"},
+		{name: "no theme", task: "less", want: "less"},
+		{name: "explicit target", task: "watch:blank", theme: "luma", want: "watch:blank"},
+		{name: "not themed task", task: "default", theme: "luma", want: "default"},
+		{name: "flag", task: "--verbose", theme: "luma", want: "--verbose"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gruntTask(tt.task, tt.theme))
+		})
+	}
+}
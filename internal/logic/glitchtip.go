@@ -0,0 +1,32 @@
+package logic
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// configureGlitchTip passes the generated database password and secret key of GlitchTip to the templates of the
+// common services. They are generated when GlitchTip is enabled for the first time and kept, as the database is
+// initialized with the password.
+func (c *Client) configureGlitchTip() error {
+	if !c.SvcEnabledStrict("glitchtip") {
+		return nil
+	}
+
+	dir := filepath.Join(c.AppHomeDir(), "etc", "glitchtip")
+
+	db, err := c.serviceCredentials(filepath.Join(dir, "credentials.json"), "glitchtip")
+	if err != nil {
+		return err
+	}
+
+	secret, err := c.serviceCredentials(filepath.Join(dir, "secret-key.json"), "")
+	if err != nil {
+		return err
+	}
+
+	c.Set(fmt.Sprintf("%s_glitchtip_db_password", c.AppName()), db.Password)
+	c.Set(fmt.Sprintf("%s_glitchtip_secret_key", c.AppName()), secret.Password)
+
+	return nil
+}
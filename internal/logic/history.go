@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// stateChangingEnvCommands are the docker-compose commands which are recorded in the history log.
+var stateChangingEnvCommands = []string{"up", "down", "start", "stop", "restart", "rm", "kill", "pull", "build"}
+
+// RecordHistory appends the command, the invoking user and the result of the command to the history log of the
+// environment and notifies the webhooks and the desktop. Errors are only logged as the history log should never
+// break the command itself.
+func (c *Client) RecordHistory(cmd *cobra.Command, args []string, cmdErr error) {
+	if !c.EnvInitialized() {
+		return
+	}
+
+	if cmd.Name() == "env" && (len(args) == 0 || !util.ContainsString(stateChangingEnvCommands, args[0])) {
+		return
+	}
+
+	status := "ok"
+	if cmdErr != nil {
+		status = "failed"
+	}
+
+	line := fmt.Sprintf("%s\t%s\t%s\t%s\n",
+		time.Now().Format(time.RFC3339),
+		util.Username(),
+		status,
+		strings.TrimSpace(cmd.CommandPath()+" "+strings.Join(args, " ")),
+	)
+
+	err := util.AppendToFileOrCreateDirAndWriteToFile([]byte(line), c.HistoryFile())
+	if err != nil {
+		log.Warnf("Cannot write history log: %s", err)
+	}
+
+	c.SendWebhooks(cmd, args, cmdErr)
+	c.notifyDesktop(cmd, args, cmdErr)
+}
+
+// RunCmdHistory prints the history log of the environment.
+func (c *Client) RunCmdHistory(cmd *cmdpkg.Command) error {
+	lines, _ := cmd.Flags().GetInt("lines")
+
+	if !util.FileExists(c.HistoryFile()) {
+		log.Println("History is empty.")
+
+		return nil
+	}
+
+	f, err := os.Open(c.HistoryFile())
+	if err != nil {
+		return fmt.Errorf("cannot open history log: %w", err)
+	}
+	defer f.Close()
+
+	var rows []table.Row
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		rows = append(rows, table.Row{fields[0], fields[1], fields[2], fields[3]})
+		if lines > 0 && len(rows) > lines {
+			rows = rows[1:]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read history log: %w", err)
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Time", "User", "Status", "Command"})
+	t.AppendRows(rows)
+	t.Render()
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type HistoryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HistoryTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryTestSuite))
+}
+
+func (suite *HistoryTestSuite) TestRecordHistory() {
+	root := &cobra.Command{Use: "reward"}
+	env := &cobra.Command{Use: "env"}
+	db := &cobra.Command{Use: "import"}
+	root.AddCommand(env, db)
+
+	tests := []struct {
+		name           string
+		envInitialized bool
+		cmd            *cobra.Command
+		args           []string
+		err            error
+		want           string
+	}{
+		{
+			name:           "env up is recorded",
+			envInitialized: true,
+			cmd:            env,
+			args:           []string{"up", "-d"},
+			want:           "\tok\treward env up -d\n",
+		},
+		{
+			name:           "failed command is recorded as failed",
+			envInitialized: true,
+			cmd:            db,
+			err:            errors.New("import failed"),
+			want:           "\tfailed\treward import\n",
+		},
+		{
+			name:           "read only env command is not recorded",
+			envInitialized: true,
+			cmd:            env,
+			args:           []string{"ps"},
+		},
+		{
+			name:           "env without args is not recorded",
+			envInitialized: true,
+			cmd:            env,
+		},
+		{
+			name: "nothing is recorded outside of an environment",
+			cmd:  env,
+			args: []string{"up"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			suite.SetupTest()
+
+			if tt.envInitialized {
+				_ = config.FS.WriteFile(".env", []byte("REWARD_ENV_NAME=test\n"), 0o644)
+			}
+
+			c := newTestClient(nil)
+			c.RecordHistory(tt.cmd, tt.args, tt.err)
+
+			got, err := config.FS.ReadFile(c.HistoryFile())
+			if tt.want == "" {
+				assert.Error(t, err, "history log should not be written")
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, strings.HasSuffix(string(got), tt.want), "unexpected history line: %q", got)
+			assert.Contains(t, string(got), "\t"+util.Username()+"\t")
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrHomeMigrationUnsupported occurs when the home directory is migrated on an OS which doesn't use the XDG base
+// directories.
+var ErrHomeMigrationUnsupported = func(goos string) error {
+	return fmt.Errorf("the XDG base directories are not used on %s, there is nothing to migrate", goos)
+}
+
+// ErrHomeMigrationTargetExists occurs when the target of the migration already exists, so it would be overwritten.
+var ErrHomeMigrationTargetExists = func(path string) error {
+	return fmt.Errorf("cannot migrate, %s already exists", path)
+}
+
+// ErrHomeMigrationServicesRunning occurs when the home directory is migrated while the common services (which mount
+// it) are running.
+var ErrHomeMigrationServicesRunning = func(appName string) error {
+	return fmt.Errorf("the common services are running, stop them first using `%s svc down`", appName)
+}
+
+// homeMove is a file or directory moved by the migration of the home directory.
+type homeMove struct {
+	From string
+	To   string
+}
+
+// RunCmdHome prints the directories and the configuration file used by the application.
+func (c *Client) RunCmdHome() error {
+	t := c.newTable()
+
+	t.AppendRow(table.Row{"Home directory", c.AppHomeDir()})
+	t.AppendRow(table.Row{"Configuration file", c.GetString(fmt.Sprintf("%s_config_file", c.AppName()))})
+	t.AppendRow(table.Row{"Cache directory", c.CacheDir()})
+	t.AppendRow(table.Row{"SSL directory", c.SSLDir()})
+	t.AppendRow(table.Row{"Plugins directory", c.PluginsDir()})
+
+	t.Render()
+
+	if runtime.GOOS == "linux" && filepath.Clean(c.AppHomeDir()) == config.LegacyAppHomeDir(c.AppName()) {
+		log.Printf("The legacy home directory is used, run `%s home migrate` to move it to the XDG base directories.",
+			c.AppName())
+	}
+
+	return nil
+}
+
+// RunCmdHomeMigrate moves the legacy home directory (~/.reward) and configuration file (~/.reward.yml) to the XDG
+// base directories: the cache to $XDG_CACHE_HOME/reward, the configuration file to $XDG_CONFIG_HOME/reward and the
+// rest (certificates, service configurations, plugins) to $XDG_DATA_HOME/reward. Nothing is moved if any of the
+// targets exists or the common services are running, so the migration can be run again if it fails. The ssh config of
+// the tunnel is regenerated with the migrated paths.
+func (c *Client) RunCmdHomeMigrate(dryRun bool) error {
+	if runtime.GOOS != "linux" {
+		return ErrHomeMigrationUnsupported(runtime.GOOS)
+	}
+
+	moves := homeMoves(c.AppName())
+	if len(moves) == 0 {
+		log.Println("The legacy home directory and configuration file don't exist, there is nothing to migrate.")
+
+		return nil
+	}
+
+	for _, move := range moves {
+		if _, err := os.Lstat(move.To); err == nil {
+			return ErrHomeMigrationTargetExists(move.To)
+		}
+	}
+
+	if c.Docker != nil {
+		containers, err := c.Docker.RunningContainersByLabels("com.docker.compose.project=" + c.AppName())
+		if err != nil {
+			return fmt.Errorf("cannot check the common services: %w", err)
+		}
+
+		if len(containers) > 0 {
+			return ErrHomeMigrationServicesRunning(c.AppName())
+		}
+	}
+
+	for _, move := range moves {
+		if dryRun {
+			log.Printf("Would move %s to %s.", move.From, move.To)
+
+			continue
+		}
+
+		log.Printf("Moving %s to %s...", move.From, move.To)
+
+		err := moveHomePath(move.From, move.To)
+		if err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	// the legacy home directory is empty if only the cache was in it
+	_ = os.Remove(config.LegacyAppHomeDir(c.AppName()))
+
+	err := c.migrateSSHConfig()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("...migration finished. Run `%s svc up` to start the common services using the new directories.",
+		c.AppName())
+
+	return nil
+}
+
+// migrateSSHConfig regenerates the ssh config of the tunnel if it exists, as its IdentityFile points to the tunnel
+// directory in the legacy home directory.
+func (c *Client) migrateSSHConfig() error {
+	if filepath.Clean(c.AppHomeDir()) != config.LegacyAppHomeDir(c.AppName()) {
+		return nil
+	}
+
+	_, configFile, err := c.sshConfigPaths()
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(configFile); err != nil {
+		return nil //nolint:nilerr
+	}
+
+	c.Set(fmt.Sprintf("%s_home_dir", c.AppName()), config.XDGAppHomeDir(c.AppName()))
+
+	log.Printf("Updating the ssh config file %s...", configFile)
+
+	return c.writeSSHConfig()
+}
+
+// homeMoves returns the existing legacy files and directories and their targets in the XDG base directories. The
+// cache directory is moved separately, so it's listed before the home directory.
+func homeMoves(appName string) []homeMove {
+	var moves []homeMove
+
+	legacyHome := config.LegacyAppHomeDir(appName)
+
+	if info, err := os.Stat(legacyHome); err == nil && info.IsDir() {
+		if info, err := os.Stat(filepath.Join(legacyHome, "cache")); err == nil && info.IsDir() {
+			moves = append(moves, homeMove{From: filepath.Join(legacyHome, "cache"), To: config.XDGCacheDir(appName)})
+		}
+
+		moves = append(moves, homeMove{From: legacyHome, To: config.XDGAppHomeDir(appName)})
+	}
+
+	legacyConfig := config.LegacyConfigFile(appName)
+	if info, err := os.Stat(legacyConfig); err == nil && info.Mode().IsRegular() {
+		moves = append(moves, homeMove{From: legacyConfig, To: config.XDGConfigFile(appName)})
+	}
+
+	return moves
+}
+
+// moveHomePath moves the file or directory. If it cannot be renamed (eg. the target is on another filesystem), it's
+// copied and the source is removed after the copy succeeded.
+func moveHomePath(from, to string) error {
+	err := os.MkdirAll(filepath.Dir(to), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	if err = os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	log.Debugf("Cannot rename %s, copying it: %s", from, err)
+
+	info, err := os.Stat(from)
+	if err != nil {
+		return fmt.Errorf("cannot stat %s: %w", from, err)
+	}
+
+	if info.IsDir() {
+		err = util.CopyDir(from, to)
+	} else {
+		var content []byte
+
+		content, err = os.ReadFile(from)
+		if err == nil {
+			err = os.WriteFile(to, content, info.Mode().Perm())
+		}
+	}
+
+	if err != nil {
+		// the partial copy is removed, the source is kept
+		_ = os.RemoveAll(to)
+
+		return fmt.Errorf("cannot move %s: %w", from, err)
+	}
+
+	err = os.RemoveAll(from)
+	if err != nil {
+		return fmt.Errorf("cannot remove %s after it's copied to %s: %w", from, to, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type HomeTestSuite struct {
+	suite.Suite
+}
+
+func TestHomeTestSuite(t *testing.T) {
+	suite.Run(t, new(HomeTestSuite))
+}
+
+// newTestHome creates the legacy home directory with a cache and the legacy configuration file in a temporary home
+// directory.
+func newTestHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, env := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "REWARD_HOME"} {
+		t.Setenv(env, "")
+	}
+
+	for _, file := range []string{".reward/ssl/rootca/certs/ca.cert.pem", ".reward/cache/downloads/mutagen.tar.gz"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(home, file)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(home, file), []byte(file), 0o600))
+	}
+
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".reward.yml"), []byte("debug: true\n"), 0o600))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(home, ".ssh", "config.d"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".ssh", "config.d", "reward.conf"),
+		[]byte(`IdentityFile "`+filepath.Join(home, ".reward", "tunnel", "ssh_key")+`"`+"\n"), 0o600))
+
+	return home
+}
+
+func (suite *HomeTestSuite) TestRunCmdHomeMigrate() {
+	if runtime.GOOS != "linux" {
+		suite.T().Skip("the XDG base directories are used on linux")
+	}
+
+	tests := []struct {
+		name       string
+		dryRun     bool
+		existing   string
+		containers []types.Container
+		wantErr    bool
+		wantMoved  bool
+	}{
+		{name: "migrate", wantMoved: true},
+		{name: "dry run", dryRun: true},
+		{name: "target exists", existing: ".local/share/reward", wantErr: true},
+		{
+			name: "services running",
+			containers: []types.Container{{
+				ID:     "1",
+				State:  "running",
+				Labels: map[string]string{"com.docker.compose.project": "reward"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			home := newTestHome(t)
+
+			if tt.existing != "" {
+				assert.NoError(t, os.MkdirAll(filepath.Join(home, tt.existing), 0o755))
+			}
+
+			c := newTestClient(map[string]interface{}{"reward_home_dir": filepath.Join(home, ".reward")})
+			c.Docker = docker.NewClientWithAPI(&docker.Fake{Containers: tt.containers})
+
+			err := c.RunCmdHomeMigrate(tt.dryRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			moved := map[string]string{
+				".local/share/reward/ssl/rootca/certs/ca.cert.pem": ".reward/ssl/rootca/certs/ca.cert.pem",
+				".cache/reward/downloads/mutagen.tar.gz":           ".reward/cache/downloads/mutagen.tar.gz",
+				".config/reward/reward.yml":                        ".reward.yml",
+			}
+
+			for to, from := range moved {
+				if tt.wantMoved {
+					assert.FileExists(t, filepath.Join(home, to))
+					assert.NoFileExists(t, filepath.Join(home, from))
+				} else {
+					assert.NoFileExists(t, filepath.Join(home, to))
+					assert.FileExists(t, filepath.Join(home, from))
+				}
+			}
+
+			sshConfig, err := os.ReadFile(filepath.Join(home, ".ssh", "config.d", "reward.conf"))
+			assert.NoError(t, err)
+
+			if tt.wantMoved {
+				assert.NoDirExists(t, filepath.Join(home, ".reward"))
+				assert.NoDirExists(t, filepath.Join(home, ".local/share/reward/cache"))
+
+				// the ssh config of the tunnel points to the migrated tunnel directory
+				assert.Contains(t, string(sshConfig), filepath.Join(home, ".local/share/reward/tunnel/ssh_key"))
+				assert.FileExists(t, filepath.Join(home, ".ssh", "config"))
+			} else {
+				assert.Contains(t, string(sshConfig), filepath.Join(home, ".reward", "tunnel", "ssh_key"))
+			}
+		})
+	}
+}
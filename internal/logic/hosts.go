@@ -0,0 +1,167 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// acrylicServiceName is the name of the Windows service of Acrylic DNS Proxy.
+const acrylicServiceName = "AcrylicDNSProxySvc"
+
+// windowsInstallDNSResolver configures the resolution of the .test domains on Windows. If Acrylic DNS Proxy is
+// installed, a wildcard record is added to its hosts file. Otherwise, the domains of the common services (and the
+// domains of the environment if the command runs in an environment directory) are added to the Windows hosts file.
+func (c *installer) windowsInstallDNSResolver() error {
+	if path := acrylicHostsFile(); path != "" {
+		log.Printf("Configuring Acrylic DNS Proxy: %s...", path)
+
+		err := updateManagedHostsBlock(path, c.AppName(), []string{"127.0.0.1 >test"})
+		if err != nil {
+			return err
+		}
+
+		for _, args := range [][]string{{"stop", acrylicServiceName}, {"start", acrylicServiceName}} {
+			out, err := exec.Command("net", args...).CombinedOutput()
+
+			log.Debugf("Command output: %s", string(out))
+
+			if err != nil && args[0] == "start" {
+				return fmt.Errorf("cannot restart acrylic dns proxy: %w", err)
+			}
+		}
+
+		log.Println("...Acrylic DNS Proxy configured. Make sure the DNS server of your network adapter is 127.0.0.1.")
+
+		return nil
+	}
+
+	path := windowsHostsFile()
+
+	log.Printf("Adding DNS records to the hosts file: %s...", path)
+
+	entries := make([]string, 0)
+	for _, host := range c.hostsFileDomains() {
+		entries = append(entries, fmt.Sprintf("127.0.0.1 %s", host))
+	}
+
+	err := updateManagedHostsBlock(path, c.AppName(), entries)
+	if err != nil {
+		return err
+	}
+
+	log.Warnf(
+		"The hosts file doesn't support wildcard records. Run `%[1]s install --dns` in the environment directories "+
+			"to add their domains, or install Acrylic DNS Proxy and run `%[1]s install --dns` again.",
+		c.AppName(),
+	)
+
+	return nil
+}
+
+// windowsHostsFile returns the path of the Windows hosts file. %SystemRoot% is not set in some shells (eg. in
+// the ones started with a cleared environment), C:\Windows is used then.
+func windowsHostsFile() string {
+	systemRoot := os.Getenv("SystemRoot")
+	if systemRoot == "" {
+		systemRoot = `C:\Windows`
+	}
+
+	return filepath.Join(systemRoot, "System32", "drivers", "etc", "hosts")
+}
+
+// acrylicHostsFile returns the path of the hosts file of Acrylic DNS Proxy if it's installed.
+func acrylicHostsFile() string {
+	for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles"} {
+		if dir := os.Getenv(env); dir != "" {
+			path := filepath.Join(dir, "Acrylic DNS Proxy", "AcrylicHosts.txt")
+			if util.FileExists(path) {
+				return path
+			}
+		}
+	}
+
+	return ""
+}
+
+// hostsFileDomains returns the domains of the common services and of the current environment.
+func (c *installer) hostsFileDomains() []string {
+	domains := make([]string, 0)
+	add := func(domain string) {
+		if domain != "" && !util.ContainsString(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	add(c.ServiceDomain())
+
+	for _, svc := range append(c.Services(), c.OptionalServices()...) {
+		add(fmt.Sprintf("%s.%s", svc, c.ServiceDomain()))
+	}
+
+	if c.EnvInitialized() {
+		add(c.TraefikDomain())
+		add(c.TraefikFullDomain())
+
+		for _, host := range strings.Fields(c.GetString("traefik_extra_hosts")) {
+			add(host)
+		}
+	}
+
+	return domains
+}
+
+// updateManagedHostsBlock replaces the lines between the markers of the app in the hosts file.
+func updateManagedHostsBlock(path, appName string, lines []string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read hosts file: %w", err)
+	}
+
+	updated := replaceManagedHostsBlock(string(content), appName, lines)
+	if updated == string(content) {
+		return nil
+	}
+
+	err = os.WriteFile(path, []byte(updated), 0o644) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("cannot write hosts file: %w", err)
+	}
+
+	return nil
+}
+
+// replaceManagedHostsBlock replaces (or appends) the block of lines between the "# APP START" and "# APP END"
+// markers. The line endings of the content are preserved.
+func replaceManagedHostsBlock(content, appName string, lines []string) string {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+
+	var (
+		start = fmt.Sprintf("# %s START", strings.ToUpper(appName))
+		end   = fmt.Sprintf("# %s END", strings.ToUpper(appName))
+		block = strings.Join(append(append([]string{start}, lines...), end), eol) + eol
+		re    = regexp.MustCompile(
+			fmt.Sprintf(`(?ms)^%s\r?\n.*?^%s\r?\n?`, regexp.QuoteMeta(start), regexp.QuoteMeta(end)),
+		)
+	)
+
+	if re.MatchString(content) {
+		return re.ReplaceAllLiteralString(content, block)
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += eol
+	}
+
+	return content + block
+}
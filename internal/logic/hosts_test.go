@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HostsTestSuite struct {
+	suite.Suite
+}
+
+func TestHostsTestSuite(t *testing.T) {
+	suite.Run(t, new(HostsTestSuite))
+}
+
+func (suite *HostsTestSuite) TestReplaceManagedHostsBlock() {
+	lines := []string{"127.0.0.1 reward.test", "127.0.0.1 traefik.reward.test"}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "empty",
+			want: "# REWARD START\n127.0.0.1 reward.test\n127.0.0.1 traefik.reward.test\n# REWARD END\n",
+		},
+		{
+			name:    "append without trailing newline",
+			content: "127.0.0.1 localhost",
+			want: "127.0.0.1 localhost\n# REWARD START\n127.0.0.1 reward.test\n127.0.0.1 traefik.reward.test\n" +
+				"# REWARD END\n",
+		},
+		{
+			name:    "replace with crlf",
+			content: "127.0.0.1 localhost\r\n# REWARD START\r\n127.0.0.1 old.test\r\n# REWARD END\r\n::1 localhost\r\n",
+			want: "127.0.0.1 localhost\r\n# REWARD START\r\n127.0.0.1 reward.test\r\n127.0.0.1 traefik.reward.test\r\n" +
+				"# REWARD END\r\n::1 localhost\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got := replaceManagedHostsBlock(tt.content, "reward", lines)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, replaceManagedHostsBlock(got, "reward", lines), "idempotent")
+		})
+	}
+}
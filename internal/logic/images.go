@@ -0,0 +1,117 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+// ErrInvalidImageOverride occurs when the image override of a service cannot replace the image of the template.
+var ErrInvalidImageOverride = func(service, image string, problems []string) error {
+	return fmt.Errorf("the image override of %s (%s) cannot be used: %s", service, image, strings.Join(problems, ", "))
+}
+
+// imageOverridePorts are the ports the templates of the services connect to, the image overrides have to expose them.
+var imageOverridePorts = map[string][]string{
+	"php-fpm":       {"9000/tcp"},
+	"php-debug":     {"9000/tcp"},
+	"nginx":         {"80/tcp"},
+	"db":            {"3306/tcp"},
+	"elasticsearch": {"9200/tcp"},
+	"opensearch":    {"9200/tcp"},
+	"redis":         {"6379/tcp"},
+	"varnish":       {"80/tcp"},
+	"rabbitmq":      {"5672/tcp"},
+}
+
+// validateImageOverrides checks that the image overrides expose the ports of the services and they can be started
+// without a command. The missing images are pulled to inspect them.
+func (c *Client) validateImageOverrides() error {
+	overrides := c.ImageOverrides()
+
+	services := make([]string, 0, len(overrides))
+	for service := range overrides {
+		services = append(services, service)
+	}
+
+	sort.Strings(services)
+
+	for _, service := range services {
+		ref := overrides[service]
+
+		image, err := c.Docker.Image(ref)
+		if errors.Is(err, docker.ErrImageNotFound) && c.Offline() {
+			return fmt.Errorf("%w, the image override of %s (%s) is not available locally", config.ErrOffline,
+				service, ref)
+		}
+
+		if errors.Is(err, docker.ErrImageNotFound) {
+			log.Printf("Pulling the image override of %s (%s)...", service, ref)
+
+			pull := cmdpkg.Cmnd("docker", "pull", ref)
+			pull.Stderr = os.Stderr
+
+			err = pull.Run()
+			if err != nil {
+				return fmt.Errorf("cannot pull the image override of %s: %w", service, err)
+			}
+
+			image, err = c.Docker.Image(ref)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot validate the image override of %s: %w", service, err)
+		}
+
+		problems := imageOverrideProblems(c.imageOverridePorts(service), image)
+		if len(problems) > 0 {
+			return ErrInvalidImageOverride(service, ref, problems)
+		}
+
+		log.Debugf("Image override of %s: %s.", service, ref)
+	}
+
+	return nil
+}
+
+// imageOverridePorts returns the ports the image of the service has to expose. The database of the oro environments
+// is PostgreSQL.
+func (c *Client) imageOverridePorts(service string) []string {
+	if service == "db" && c.EnvType() == "oro" {
+		return []string{"5432/tcp"}
+	}
+
+	return imageOverridePorts[service]
+}
+
+// imageOverrideProblems returns the reasons why the image cannot replace the image of a service which is reached on
+// the ports.
+func imageOverrideProblems(ports []string, image *docker.Image) []string {
+	var problems []string
+
+	exposed := make(map[string]bool, len(image.ExposedPorts))
+	for _, port := range image.ExposedPorts {
+		exposed[port] = true
+	}
+
+	for _, port := range ports {
+		if !exposed[port] {
+			problems = append(problems, fmt.Sprintf("it doesn't expose port %s", port))
+		}
+	}
+
+	// the templates don't set the command of the built-in services, they run the default command of the image
+	if len(image.Entrypoint) == 0 && len(image.Cmd) == 0 {
+		problems = append(problems, "it has neither an entrypoint nor a default command")
+	}
+
+	return problems
+}
@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type ImagesTestSuite struct {
+	suite.Suite
+}
+
+func TestImagesTestSuite(t *testing.T) {
+	suite.Run(t, new(ImagesTestSuite))
+}
+
+func (suite *ImagesTestSuite) TestImageOverrideProblems() {
+	tests := []struct {
+		name  string
+		ports []string
+		image *docker.Image
+		want  []string
+	}{
+		{
+			name:  "valid",
+			ports: []string{"9000/tcp"},
+			image: &docker.Image{ExposedPorts: []string{"9000/tcp"}, Entrypoint: []string{"docker-php-entrypoint"}},
+		},
+		{
+			name:  "no ports required",
+			image: &docker.Image{Cmd: []string{"node"}},
+		},
+		{
+			name:  "missing port",
+			ports: []string{"3306/tcp"},
+			image: &docker.Image{ExposedPorts: []string{"5432/tcp"}, Cmd: []string{"postgres"}},
+			want:  []string{"it doesn't expose port 3306/tcp"},
+		},
+		{
+			name:  "no command",
+			ports: []string{"80/tcp"},
+			image: &docker.Image{ExposedPorts: []string{"80/tcp"}},
+			want:  []string{"it has neither an entrypoint nor a default command"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, imageOverrideProblems(tt.ports, tt.image))
+		})
+	}
+}
+
+func (suite *ImagesTestSuite) TestImageOverridePorts() {
+	c := newTestClient(map[string]interface{}{"reward_env_type": "oro"})
+	assert.Equal(suite.T(), []string{"5432/tcp"}, c.imageOverridePorts("db"))
+	assert.Equal(suite.T(), []string{"9000/tcp"}, c.imageOverridePorts("php-fpm"))
+	assert.Nil(suite.T(), c.imageOverridePorts("php-worker"))
+}
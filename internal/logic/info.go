@@ -2,7 +2,6 @@ package logic
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -14,8 +13,7 @@ import (
 
 // RunCmdInfo represents the info command.
 func (c *Client) RunCmdInfo(cmd *cmdpkg.Command) error {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
+	t := c.newTable()
 	t.AppendHeader(table.Row{"Info"})
 
 	c.infoHeader(t)
@@ -52,6 +50,12 @@ func (c *Client) infoHeader(t table.Writer) {
 }
 
 func (c *Client) infoRender(cmd *cmdpkg.Command, t table.Writer) {
+	if c.JSONOutput() {
+		t.Render()
+
+		return
+	}
+
 	style, _ := cmd.Flags().GetString("style")
 	switch style {
 	case "csv":
@@ -116,6 +120,27 @@ func (c *Client) infoEnvironment(t table.Writer) {
 			})
 		}
 
+		if c.Config.EnvType() == "sylius" || c.Config.EnvType() == "oro" {
+			t.AppendRow([]interface{}{
+				"Admin URL",
+				fmt.Sprintf("https://%s/admin", c.TraefikFullDomain()),
+			})
+		}
+
+		if c.Config.EnvType() == "typo3" {
+			t.AppendRow([]interface{}{
+				"Admin URL",
+				fmt.Sprintf("https://%s/typo3", c.TraefikFullDomain()),
+			})
+		}
+
+		if c.Config.EnvType() == "craft" {
+			t.AppendRow([]interface{}{
+				"Admin URL",
+				fmt.Sprintf("https://%s/admin", c.TraefikFullDomain()),
+			})
+		}
+
 		if c.Config.EnvType() == "wordpress" {
 			t.AppendRow([]interface{}{
 				"Admin URL",
@@ -132,12 +157,14 @@ func (c *Client) infoEnvironment(t table.Writer) {
 		}
 
 		for _, svc := range svcs {
-			if c.IsSvcEnabled(svc) && c.Docker.ContainerRunning(svc) {
-				names, _ := c.Docker.ContainerNamesByName(svc)
+			if !c.IsSvcEnabled(svc) {
+				continue
+			}
 
+			if container, err := c.Docker.RunningEnvServiceContainer(svc); err == nil {
 				t.AppendRow([]interface{}{
 					fmt.Sprintf("%s Container Name", cases.Title(language.English).String(svc)),
-					c.normalizeNames(names),
+					container.Name,
 				})
 			}
 		}
@@ -167,12 +194,3 @@ func (c *Client) infoGlobalServices(t table.Writer) {
 		}
 	}
 }
-
-func (c *Client) normalizeNames(names []string) string {
-	normalizedNames := make([]string, len(names))
-	for i, name := range names {
-		normalizedNames[i] = strings.TrimPrefix(name, "/")
-	}
-
-	return strings.Join(normalizedNames, ", ")
-}
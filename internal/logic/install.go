@@ -7,7 +7,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"strings"
 	"time"
 
@@ -20,6 +19,11 @@ import (
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrDNSResolverUnsupportedOS occurs when the DNS resolver cannot be configured on the operating system.
+var ErrDNSResolverUnsupportedOS = func(distro string) error {
+	return fmt.Errorf("configuring the DNS resolver is not supported on %s yet, use --dns-server=coredns", distro)
+}
+
 type installer struct {
 	*Client
 }
@@ -72,6 +76,15 @@ debug: false
 # If you want to enable the following services, uncomment any of them.
 # These services are disabled by default.
 #reward_adminer: true
+#reward_glitchtip: true
+
+# Container management UI: portainer, yacht or none (default: portainer).
+#reward_container_ui: portainer
+#reward_container_ui_subdomain: portainer
+
+# DNS server which resolves the .test domains: dnsmasq or coredns (default: dnsmasq).
+# CoreDNS answers on the DNS-over-TLS port as well. Use "reward install --dns coredns" to switch.
+#reward_dns_server: dnsmasq
 
 ############
 # SERVICE CONTAINERS
@@ -79,6 +92,7 @@ debug: false
 #reward_traefik_image: "traefik"
 
 #reward_portainer_image: "portainer/portainer-ce"
+#reward_yacht_image: "selfhostedpro/yacht"
 
 # Reward < v0.2.33 uses "jpillora/dnsmasq" as the default dnsmasq image.
 # Reward >= v0.2.34 uses the internally built "docker.io/rewardenv/dnsmasq"
@@ -90,6 +104,8 @@ debug: false
 
 #reward_adminer_image: "dehy/adminer"
 
+#reward_glitchtip_image: "glitchtip/glitchtip"
+
 #reward_elastichq_image: "elastichq/elasticsearch-hq"
 
 # Reward < v0.2.33 uses "panubo/sshd:1.1.0" as the default dnsmasq image.
@@ -176,12 +192,24 @@ func (c *installer) uninstall() error {
 			if confirmation := util.AskForConfirmation(
 				fmt.Sprintf("Are you sure you want to delete %s?", appHomeDir),
 			); confirmation {
+				if server, _ := c.DNSServer(); server == "coredns" {
+					err = c.corednsUninstallDNSResolver()
+					if err != nil {
+						return err
+					}
+				}
+
 				log.Debugf("Deleting: %s\n", appHomeDir)
 
 				err = os.RemoveAll(appHomeDir)
 				if err != nil {
 					return fmt.Errorf("failed to delete %s: %w", appHomeDir, err)
 				}
+
+				err = c.removeSSHConfig()
+				if err != nil {
+					return err
+				}
 			}
 
 			if confirmation := util.AskForConfirmation(
@@ -213,10 +241,18 @@ func (c *installer) install() error {
 	log.Printf("Installing %s...", cases.Title(language.English).String(c.AppName()))
 
 	// On windows this command should run in elevated command prompt
-	c.runElevated()
-	c.checkInstalled()
+	elevated, err := c.runElevated()
+	if err != nil || elevated {
+		return err
+	}
+
+	if !c.confirmReinstall() {
+		log.Println("...installation aborted.")
 
-	err := c.installAppDirectories()
+		return nil
+	}
+
+	err = c.installAppDirectories()
 	if err != nil {
 		return err
 	}
@@ -346,31 +382,40 @@ func (c *installer) installConfig() error {
 	return nil
 }
 
-func (c *installer) checkInstalled() {
+// confirmReinstall returns false if the application is already installed and the user doesn't want to reinstall it.
+func (c *installer) confirmReinstall() bool {
 	// If we are not directly call installation for cacert, dns, ssh then check if the install marker already exists.
-	if !c.installCaCertFlag() && !c.installDNSFlag() && !c.installSSHKeyFlag() && !c.installSSHConfigFlag() {
-		if util.FileExists(c.InstallMarkerFilePath()) {
-			if !util.AskForConfirmation(
-				fmt.Sprintf(
-					"%s is already installed. Would you like to reinstall?",
-					cases.Title(language.English).String(c.AppName()),
-				),
-			) {
-				log.Println("...installation aborted.")
-				os.Exit(0)
-			}
-		}
+	if c.installCaCertFlag() || c.installDNSFlag() || c.installSSHKeyFlag() || c.installSSHConfigFlag() {
+		return true
 	}
+
+	if !util.FileExists(c.InstallMarkerFilePath()) {
+		return true
+	}
+
+	return util.AskForConfirmation(
+		fmt.Sprintf(
+			"%s is already installed. Would you like to reinstall?",
+			cases.Title(language.English).String(c.AppName()),
+		),
+	)
 }
 
-func (c *installer) runElevated() {
-	if util.OSDistro() == "windows" {
-		if !util.IsAdmin() {
-			log.Printf("Running %s in an Elevated command prompt...", c.AppName())
+// runElevated runs the installation in an elevated command prompt on windows if it's not run by an admin. It returns
+// true if the elevated installation is started, this process shouldn't continue then.
+func (c *installer) runElevated() (bool, error) {
+	if util.OSDistro() != "windows" || util.IsAdmin() {
+		return false, nil
+	}
 
-			util.RunMeElevated()
-		}
+	log.Printf("Running %s in an Elevated command prompt...", c.AppName())
+
+	err := util.RunMeElevated()
+	if err != nil {
+		return false, err //nolint:wrapcheck
 	}
+
+	return true, nil
 }
 
 func (c *installer) installAppDirectories() error {
@@ -418,7 +463,19 @@ func (c *installer) installCaCertFlag() bool {
 
 // installDNSFlag returns true if --install-dns flag is set during the execution.
 func (c *installer) installDNSFlag() bool {
-	return c.GetBool(c.AppName() + "_install_dns")
+	value := c.GetString(c.AppName() + "_install_dns")
+
+	return value != "" && value != "false"
+}
+
+// installDNSServerFlag returns the DNS server passed to the --install-dns flag (eg. --dns=coredns) or an empty
+// string if the flag is not set or it's set without a DNS server.
+func (c *installer) installDNSServerFlag() string {
+	if value := c.GetString(c.AppName() + "_install_dns"); value != "true" && value != "false" {
+		return value
+	}
+
+	return ""
 }
 
 // installSSHKeyFlag returns true if --install-ssh-key flag is set during the execution.
@@ -459,19 +516,35 @@ func (c *installer) touchInstallMarkerFile() error {
 // installDNSResolver configures local DNS resolution based on the operating system.
 func (c *installer) installDNSResolver() error {
 	if !c.installCaCertFlag() && !c.installSSHKeyFlag() && !c.installSSHConfigFlag() {
-		log.Print("Configuring DNS resolver...")
+		server, err := c.installDNSServer()
+		if err != nil {
+			return err
+		}
 
-		var err error
+		log.Print("Configuring DNS resolver...")
 
-		switch util.OSDistro() {
-		case "windows":
-			log.Warnln("On Windows you should configure YogaDNS or add DNS records to hosts file manually.")
-		case "darwin":
+		switch {
+		case server == "coredns":
+			err = c.corednsInstallDNSResolver()
+		case util.OSDistro() == "windows":
+			err = c.windowsInstallDNSResolver()
+		case util.OSDistro() == "darwin":
 			err = c.darwinInstallDNSResolver()
-		case "ubuntu", "debian", "pop", "linuxmint", "fedora", "centos", "elementary", "manjaro", "arch":
+		case util.ContainsString(
+			[]string{"ubuntu", "debian", "pop", "linuxmint", "fedora", "centos", "elementary", "manjaro", "arch"},
+			util.OSDistro(),
+		):
 			err = c.linuxInstallDNSResolver()
+
+			if util.IsWSL() {
+				log.Warnf(
+					"The DNS resolver of Windows is not configured from WSL. Run `%s install --dns` using the "+
+						"Windows binary to resolve the .test domains in the Windows browsers.",
+					c.AppName(),
+				)
+			}
 		default:
-			log.Panicln("Your Operating System is not supported. Yet. :(")
+			err = ErrDNSResolverUnsupportedOS(util.OSDistro())
 		}
 
 		if err != nil {
@@ -492,7 +565,7 @@ func (c *installer) linuxInstallDNSResolver() error {
 
 	resolvConfUsesLocalNs, err := util.CheckRegexInFile("nameserver 127.0.0.1", "/etc/resolv.conf")
 	if err != nil {
-		log.Panicln(err)
+		return fmt.Errorf("cannot read /etc/resolv.conf: %w", err)
 	}
 
 	if networkManagerStatus == 0 && !resolvConfUsesLocalNs { //nolint:nestif
@@ -516,7 +589,7 @@ func (c *installer) linuxInstallDNSResolver() error {
 				fmt.Sprintf("^%s$", dhclientConfig), dhclientConfigFilePath,
 			)
 			if err != nil {
-				log.Panicln(err)
+				return fmt.Errorf("cannot read dhclient config file: %w", err)
 			}
 		}
 
@@ -556,7 +629,7 @@ func (c *installer) linuxInstallDNSResolver() error {
 
 	resolveConfFileInfo, err := os.Lstat("/etc/resolv.conf")
 	if err != nil {
-		log.Panicln(err)
+		return fmt.Errorf("cannot stat /etc/resolv.conf: %w", err)
 	}
 
 	log.Debugf("resolve conf file mode: %s", resolveConfFileInfo.Mode())
@@ -582,7 +655,7 @@ Domains=~.`
 		log.Debugln(link)
 
 		if err != nil {
-			log.Panicln(err)
+			return fmt.Errorf("cannot read the link of /etc/resolv.conf: %w", err)
 		}
 
 		if link != "../run/systemd/resolve/resolv.conf" {
@@ -615,9 +688,8 @@ func (c *installer) darwinInstallDNSResolver() error {
 		log.Printf("Creating %s directory...", dirPath)
 		log.Debugf("path: %s", dirPath)
 
-		cmdCreateDir := "sudo mkdir -v " + dirPath
-		cmd := exec.Command("/bin/sh", "-c", cmdCreateDir)
-		log.Printf("Running command: %si", cmd)
+		cmd := exec.Command("sudo", "mkdir", "-v", dirPath)
+		log.Printf("Running command: %s", cmd)
 		out, err := cmd.CombinedOutput()
 		log.Debugf("output: %s", string(out))
 
@@ -639,8 +711,8 @@ func (c *installer) darwinInstallDNSResolver() error {
 	if len(matches) == 0 {
 		log.Printf("Creating DNS resolver config...")
 
-		cmdWriteFile := fmt.Sprintf("echo '%s' | sudo tee %s", resolverConfig, resolverFilePath)
-		cmd := exec.Command("/bin/sh", "-c", cmdWriteFile)
+		cmd := exec.Command("sudo", "tee", resolverFilePath)
+		cmd.Stdin = strings.NewReader(resolverConfig + "\n")
 
 		log.Printf("Running command: %s", cmd)
 
@@ -664,122 +736,34 @@ func (c *installer) installSSHKey() error {
 	if !c.installCaCertFlag() && !c.installDNSFlag() && !c.installSSHConfigFlag() {
 		log.Print("Installing SSH key...")
 
-		crypto := cryptopkg.New(c.Config)
-		appHomeDir := c.AppHomeDir()
-		keyPath := filepath.Join(appHomeDir, "tunnel", "ssh_key")
-
-		// On linux, if we want to reinstall the pubfile we have to revert its permissions first
-		if runtime.GOOS == "linux" && util.FileExists(keyPath) {
-			cmdChown := fmt.Sprintf(
-				"sudo chown -v %d:%d %s", os.Getuid(), 0,
-				filepath.Join(appHomeDir, "tunnel", "ssh_key.pub"),
-			)
-			cmd := exec.Command("/bin/sh", "-c", cmdChown)
-
-			log.Debugf("Running command: %s", cmd)
-
-			out, err := cmd.CombinedOutput()
-
-			log.Debugf("Command output: %s", string(out))
-
-			if err != nil {
-				log.Fatalln(err)
-			}
-		}
+		keyPath := filepath.Join(c.TunnelDir(), "ssh_key")
 
 		keyFileExist := util.CheckFileExistsAndRecreate(keyPath)
 		if !keyFileExist {
-			if err := crypto.GenerateSSHKeys(2048, keyPath); err != nil {
+			if err := c.generateTunnelSSHKey(); err != nil {
 				return err
 			}
 		}
 
-		// Since bind mounts are native on linux to use .pub file as authorized_keys file in tunnel it
-		// must have proper perms.
-		if runtime.GOOS == "linux" {
-			cmdChown := fmt.Sprintf(
-				"sudo chown -v %d:%d %s", 0, 0, filepath.Join(appHomeDir, "tunnel", "ssh_key.pub"),
-			)
-			cmd := exec.Command("/bin/sh", "-c", cmdChown)
-
-			log.Debugf("Running command: %s", cmd)
-
-			out, err := cmd.CombinedOutput()
-
-			log.Debugf("Command output: %s", string(out))
-
-			if err != nil {
-				log.Fatalln(err)
-			}
-		}
-
 		log.Print("...SSH key installed.")
 	}
 
 	return nil
 }
 
-// InstallSSHConfig updates the ssh config file to use our key if the target host is the tunnel.
+// InstallSSHConfig writes the ssh config file of the tunnel to the user's ssh config directory and includes it in
+// the user's ssh config file.
 func (c *installer) installSSHConfig() error {
 	if util.OSDistro() != "windows" {
 		if !c.installCaCertFlag() && !c.installDNSFlag() && !c.installSSHKeyFlag() {
 			log.Println("Updating SSH config file...")
 
-			var (
-				sshConfig = fmt.Sprintf(
-					`## %[1]s START ##
-Host tunnel.%[2]s.test
-  HostName 127.0.0.1
-  User user
-  Port 2222
-  IdentityFile %[3]s/tunnel/ssh_key
-## %[1]s END ##`,
-					strings.ToUpper(c.AppName()),
-					c.AppName(),
-					c.AppHomeDir(),
-				)
-				//nolint:gocritic
-				sshConfigFile = filepath.Join("/etc/ssh/ssh_config")
-			)
-
-			content, err := os.ReadFile(sshConfigFile)
+			err := c.writeSSHConfig()
 			if err != nil {
-				return fmt.Errorf("%w", err)
+				return err
 			}
 
-			log.Debugf("Searching for configuration regex in ssh config file: %s...", sshConfigFile)
-
-			matches := regexp.MustCompile(
-				fmt.Sprintf(
-					"## %s START ##",
-					strings.ToUpper(c.AppName()),
-				),
-			).FindStringSubmatch(string(content))
-
-			log.Debugf("...regex match: %+v", matches)
-
-			if len(matches) == 0 {
-				log.Println("Updating SSH config file...")
-				log.Debugf("SSH config file path: %s", sshConfigFile)
-				log.Debugf("SSH config file content: %s", sshConfig)
-
-				cmdAppend := fmt.Sprintf("echo '%s' | sudo tee -a %s", sshConfig, sshConfigFile)
-				cmd := exec.Command("/bin/sh", "-c", cmdAppend)
-
-				log.Debugf("Running command: %s", cmd)
-
-				out, err := cmd.CombinedOutput()
-
-				log.Debugf("Command output: %s", string(out))
-
-				if err != nil {
-					return fmt.Errorf("%w", err)
-				}
-
-				log.Println("...SSH config file updated.")
-			} else {
-				log.Println("...SSH config file was already set.")
-			}
+			log.Println("...SSH config file updated.")
 		}
 	}
 
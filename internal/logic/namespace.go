@@ -0,0 +1,143 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// maxPortOffset is the highest port offset allocated in shared mode.
+const maxPortOffset = 9900
+
+// ErrPortOffsetInUse occurs when the port offset is already used by another namespace in shared mode.
+var ErrPortOffsetInUse = func(offset int, namespaces []string) error {
+	return fmt.Errorf(
+		"port offset %d is already used by namespace(s) %v on this docker host, "+
+			"set a different reward_port_offset in your ~/.reward.yml",
+		offset, namespaces,
+	)
+}
+
+// ErrNoFreePortOffset occurs when all the port offsets are used by other namespaces in shared mode.
+var ErrNoFreePortOffset = fmt.Errorf(
+	"cannot allocate a port offset, all of them are in use, set reward_port_offset in your ~/.reward.yml",
+)
+
+// resolvePortOffset sets the port offset of the developer's namespace in shared mode. The networks of the
+// environments are labeled with their namespace and port offset, so the docker host acts as the registry of the
+// offsets in use. An explicitly configured offset is only checked for collisions.
+func (c *Client) resolvePortOffset() error {
+	if !c.SharedMode() {
+		return nil
+	}
+
+	labels, err := c.Docker.NetworkLabelsByLabel(fmt.Sprintf("dev.%s.environment.namespace", c.AppName()))
+	if err != nil {
+		return fmt.Errorf("cannot look up port offsets in use: %w", err)
+	}
+
+	used := make(map[int][]string)
+
+	for _, l := range labels {
+		offset, err := strconv.Atoi(l[fmt.Sprintf("dev.%s.environment.port_offset", c.AppName())])
+		if err != nil {
+			continue
+		}
+
+		used[offset] = append(used[offset], l[fmt.Sprintf("dev.%s.environment.namespace", c.AppName())])
+	}
+
+	offset, err := allocatePortOffset(c.Namespace(), c.PortOffset(), c.PortOffsetConfigured(), c.PreferredPortOffset(),
+		used)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Using port offset %d for namespace %s.", offset, c.Namespace())
+
+	c.Set(fmt.Sprintf("%s_port_offset", c.AppName()), offset)
+
+	return nil
+}
+
+// allocatePortOffset returns the port offset of the namespace. If the offset is configured, it returns an error if
+// another namespace uses it. Otherwise, it returns the offset the namespace already uses, or the preferred offset
+// if it's free, or the lowest free offset.
+func allocatePortOffset(
+	ns string, configured int, isConfigured bool, preferred int, used map[int][]string,
+) (int, error) {
+	others := func(offset int) []string {
+		var result []string
+
+		for _, n := range used[offset] {
+			if n != ns && !util.ContainsString(result, n) {
+				result = append(result, n)
+			}
+		}
+
+		sort.Strings(result)
+
+		return result
+	}
+
+	if isConfigured {
+		if o := others(configured); len(o) > 0 {
+			return 0, ErrPortOffsetInUse(configured, o)
+		}
+
+		return configured, nil
+	}
+
+	owned := make([]int, 0)
+
+	for offset, namespaces := range used {
+		if util.ContainsString(namespaces, ns) && len(others(offset)) == 0 {
+			owned = append(owned, offset)
+		}
+	}
+
+	if len(owned) > 0 {
+		sort.Ints(owned)
+
+		return owned[0], nil
+	}
+
+	if len(used[preferred]) == 0 {
+		return preferred, nil
+	}
+
+	for offset := 100; offset <= maxPortOffset; offset += 100 {
+		if len(used[offset]) == 0 {
+			return offset, nil
+		}
+	}
+
+	return 0, ErrNoFreePortOffset
+}
+
+// warnNamespaceRename warns if the environment was started before the shared mode was enabled. In shared mode the
+// environment gets a new docker-compose project name, so the containers and volumes of the old project are not used
+// anymore.
+func (c *Client) warnNamespaceRename() {
+	if !c.SharedMode() || c.rawEnvName() == c.EnvName() {
+		return
+	}
+
+	networks, err := c.Docker.NetworkLabelsByLabel(
+		fmt.Sprintf("dev.%s.environment.name=%s", c.AppName(), c.rawEnvName()),
+	)
+	if err != nil || len(networks) == 0 {
+		return
+	}
+
+	log.Warnf(
+		"Environment %s was started before the shared mode was enabled. In shared mode it runs as %s with new "+
+			"containers and volumes, the data of %s is not migrated. To remove the old environment, run "+
+			"`REWARD_SHARED_MODE=false %s env down -v`.",
+		c.rawEnvName(), c.EnvName(), c.rawEnvName(), c.AppName(),
+	)
+}
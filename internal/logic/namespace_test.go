@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NamespaceTestSuite struct {
+	suite.Suite
+}
+
+func TestNamespaceTestSuite(t *testing.T) {
+	suite.Run(t, new(NamespaceTestSuite))
+}
+
+func (suite *NamespaceTestSuite) TestAllocatePortOffset() {
+	full := make(map[int][]string)
+	for offset := 0; offset <= maxPortOffset; offset += 100 {
+		full[offset] = []string{"other"}
+	}
+
+	tests := []struct {
+		name         string
+		configured   int
+		isConfigured bool
+		preferred    int
+		used         map[int][]string
+		want         int
+		wantErr      bool
+	}{
+		{
+			name:      "preferred is free",
+			preferred: 200,
+			used:      map[int][]string{100: {"bob"}},
+			want:      200,
+		},
+		{
+			name:      "preferred collides",
+			preferred: 200,
+			used:      map[int][]string{100: {"bob"}, 200: {"carol"}},
+			want:      300,
+		},
+		{
+			name:      "keeps the offset in use",
+			preferred: 200,
+			used:      map[int][]string{500: {"alice", "alice"}, 200: {"carol"}},
+			want:      500,
+		},
+		{
+			name:      "ignores offsets shared with others",
+			preferred: 200,
+			used:      map[int][]string{500: {"alice", "bob"}},
+			want:      200,
+		},
+		{
+			name:         "configured is free",
+			configured:   700,
+			isConfigured: true,
+			preferred:    200,
+			used:         map[int][]string{700: {"alice"}},
+			want:         700,
+		},
+		{
+			name:         "configured collides",
+			configured:   700,
+			isConfigured: true,
+			used:         map[int][]string{700: {"bob"}},
+			wantErr:      true,
+		},
+		{
+			name:    "no free offset",
+			used:    full,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := allocatePortOffset("alice", tt.configured, tt.isConfigured, tt.preferred, tt.used)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
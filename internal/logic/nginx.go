@@ -0,0 +1,218 @@
+package logic
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/internal/templates"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrNginxBrotliNotSupported occurs when the brotli preset is enabled but the nginx binary of the container doesn't
+// have the brotli module.
+var ErrNginxBrotliNotSupported = func(container string) error {
+	return fmt.Errorf(
+		"brotli compression is enabled (REWARD_NGINX_BROTLI=true), but nginx in the %s container is not built with "+
+			"the brotli module. Disable REWARD_NGINX_BROTLI or use an nginx image which includes the brotli module",
+		container,
+	)
+}
+
+// nginxPresetTemplates are the generated nginx snippets. The http-* and server-* prefixes are required by the
+// include directives of the nginx image.
+var nginxPresetTemplates = []string{
+	"http-reward-presets.conf",
+	"server-reward-presets.conf",
+}
+
+// nginxStaticTemplate is the generated server template of the static environments. It's mounted as the application
+// template of the nginx image, so it's not included by the snippet patterns.
+const nginxStaticTemplate = "static.conf"
+
+// nginxOroWebsocketTemplate is the generated server snippet of the oro environments which proxies the websocket
+// connections.
+const nginxOroWebsocketTemplate = "server-oro-websocket.conf"
+
+// RunCmdNginxTest regenerates the nginx presets and validates the nginx configuration inside the container.
+func (c *Client) RunCmdNginxTest() error {
+	err := c.checkNginxBrotli()
+	if err != nil {
+		return err
+	}
+
+	err = c.GenerateNginxPresets()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Testing nginx configuration...")
+
+	err = c.RunCmdEnvDockerCompose(
+		[]string{"exec", "-T", c.NginxContainer(), "nginx", "-t"},
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("nginx configuration test failed: %w", err)
+	}
+
+	log.Println("...nginx configuration is valid.")
+
+	return nil
+}
+
+// RunCmdNginxReload validates the nginx configuration and reloads nginx without recreating the container.
+func (c *Client) RunCmdNginxReload() error {
+	err := c.RunCmdNginxTest()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Reloading nginx...")
+
+	err = c.RunCmdEnvDockerCompose(
+		[]string{"exec", "-T", c.NginxContainer(), "nginx", "-s", "reload"},
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot reload nginx: %w", err)
+	}
+
+	log.Println("...nginx reloaded.")
+
+	return nil
+}
+
+// prepareNginxConfigs creates the nginx overlay directory (so docker doesn't create it as root) and generates
+// the nginx presets.
+func (c *Client) prepareNginxConfigs() error {
+	if !c.SvcEnabledPermissive("nginx") {
+		return nil
+	}
+
+	err := util.CreateDir(filepath.Join(c.NginxOverlayPath(), "http"), nil)
+	if err != nil {
+		return fmt.Errorf("cannot create nginx overlay directory: %w", err)
+	}
+
+	err = c.checkNginxBrotli()
+	if err != nil {
+		return err
+	}
+
+	if c.EnvType() == "static" {
+		err = templates.New().GenerateNginxStaticConfig(filepath.Join(c.NginxCustomConfigsPath(), nginxStaticTemplate))
+		if err != nil {
+			return fmt.Errorf("cannot generate nginx static template: %w", err)
+		}
+	}
+
+	if c.EnvType() == "oro" {
+		err = templates.New().GenerateNginxOroWebsocketConfig(
+			filepath.Join(c.NginxCustomConfigsPath(), nginxOroWebsocketTemplate),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot generate nginx websocket snippet: %w", err)
+		}
+	}
+
+	return c.GenerateNginxPresets()
+}
+
+// checkNginxBrotli returns an error if the brotli preset is enabled but the running nginx doesn't have the brotli
+// module (compiled in or loaded dynamically), because the brotli directives would prevent nginx from starting.
+// If the container is not running, the check is skipped.
+func (c *Client) checkNginxBrotli() error {
+	if !c.NginxBrotli() || !c.Docker.ContainerRunning(c.NginxContainer()) {
+		return nil
+	}
+
+	log.Debugln("Checking nginx brotli module...")
+
+	_, err := c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", c.NginxContainer(), "sh", "-c",
+		`nginx -V 2>&1 | grep -q brotli || grep -Rqs "^[^#]*load_module.*brotli" /etc/nginx/`,
+	})
+	if err != nil {
+		return ErrNginxBrotliNotSupported(c.NginxContainer())
+	}
+
+	log.Debugln("...nginx brotli module found.")
+
+	return nil
+}
+
+// GenerateNginxPresets writes the nginx compression, security header and real-ip presets to the nginx snippets
+// directory of the project. If none of the presets are enabled the previously generated files are removed.
+func (c *Client) GenerateNginxPresets() error {
+	if !c.SvcEnabledPermissive("nginx") {
+		return nil
+	}
+
+	if !c.NginxPresetsEnabled() {
+		for _, name := range nginxPresetTemplates {
+			path := filepath.Join(c.NginxCustomConfigsPath(), name)
+
+			if util.FileExists(path) {
+				err := templates.New().CheckGeneratedFile(path, nil)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				log.Debugf("Removing nginx preset file %s...", path)
+
+				err = os.Remove(path)
+				if err != nil {
+					return fmt.Errorf("cannot remove nginx preset file %s: %w", path, err)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	log.Debugln("Generating nginx presets...")
+
+	tplgen := templates.New()
+
+	for _, name := range nginxPresetTemplates {
+		var (
+			bs      bytes.Buffer
+			tpl     = template.New(name)
+			tplList = list.New()
+		)
+
+		err := tplgen.AppendTemplatesFromPathsStatic(
+			tpl,
+			tplList,
+			[]string{filepath.Join("templates", "nginx", name)},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot append nginx preset template %s: %w", name, err)
+		}
+
+		for e := tplList.Front(); e != nil; e = e.Next() {
+			tplName := fmt.Sprint(e.Value)
+
+			err = tplgen.ExecuteTemplate(tpl.Lookup(tplName), &bs)
+			if err != nil {
+				return fmt.Errorf("cannot execute nginx preset template %s: %w", tplName, err)
+			}
+		}
+
+		err = tplgen.WriteGeneratedFile(bs.Bytes(), filepath.Join(c.NginxCustomConfigsPath(), name), 0o644)
+		if err != nil {
+			return fmt.Errorf("cannot write nginx preset file %s: %w", name, err)
+		}
+	}
+
+	log.Debugln("...nginx presets generated.")
+
+	return nil
+}
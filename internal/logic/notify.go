@@ -0,0 +1,108 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/timing"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// windowsNotificationScript shows a balloon tip notification, it's kept for a while, so the notification is not
+// removed with the tray icon immediately.
+const windowsNotificationScript = `Add-Type -AssemblyName System.Windows.Forms; ` +
+	`$n = New-Object System.Windows.Forms.NotifyIcon; $n.Icon = [System.Drawing.SystemIcons]::Information; ` +
+	`$n.Visible = $true; $n.ShowBalloonTip(10000, '%s', '%s', '%s'); Start-Sleep -Seconds 10; $n.Dispose()`
+
+// notifyDesktop emits a desktop notification about the result of the command if it ran longer than the configured
+// duration, so the developers can switch to other tasks while it's running. The commands which are not run from a
+// terminal (eg. in CI) don't notify. Errors are only logged as the notification should never break the command.
+func (c *Client) notifyDesktop(cmd *cobra.Command, args []string, cmdErr error) {
+	after := c.NotifyAfter()
+	elapsed := timing.Elapsed().Round(time.Second)
+
+	if after <= 0 || elapsed < after || !util.IsTerminal(os.Stdout) {
+		return
+	}
+
+	var (
+		command = strings.TrimSpace(cmd.CommandPath() + " " + strings.Join(args, " "))
+		title   = fmt.Sprintf("%s: %s", c.AppName(), c.EnvName())
+		message = fmt.Sprintf("%s finished in %s", command, elapsed)
+	)
+
+	if cmdErr != nil {
+		message = fmt.Sprintf("%s failed after %s", command, elapsed)
+	}
+
+	notification := desktopNotificationCommand(runtime.GOOS, util.IsWSL(), title, message, cmdErr != nil)
+	if notification == nil || !util.CommandAvailable(notification[0]) {
+		log.Debugf("Desktop notifications are not supported on this system.")
+
+		return
+	}
+
+	// the command is not waited for, the windows notification is kept for a while
+	err := cmdpkg.Cmnd(notification[0], notification[1:]...).Start()
+	if err != nil {
+		log.Warnf("Cannot send desktop notification: %s", err)
+	}
+}
+
+// desktopNotificationCommand returns the command which emits the desktop notification on the operating system.
+func desktopNotificationCommand(goos string, wsl bool, title, message string, failed bool) []string {
+	switch {
+	case goos == "darwin":
+		sound := ""
+		if failed {
+			sound = ` sound name "Basso"`
+		}
+
+		return []string{
+			"osascript", "-e",
+			fmt.Sprintf(`display notification %s with title %s%s`, appleScriptQuote(message), appleScriptQuote(title),
+				sound),
+		}
+	case goos == "windows" || wsl:
+		icon := "Info"
+		if failed {
+			icon = "Error"
+		}
+
+		powershell := "powershell"
+		if wsl {
+			powershell = "powershell.exe"
+		}
+
+		return []string{
+			powershell, "-NoProfile", "-NonInteractive", "-Command",
+			fmt.Sprintf(windowsNotificationScript, powerShellQuote(title), powerShellQuote(message), icon),
+		}
+	case goos == "linux":
+		urgency := "normal"
+		if failed {
+			urgency = "critical"
+		}
+
+		return []string{"notify-send", "--urgency=" + urgency, title, message}
+	default:
+		return nil
+	}
+}
+
+// appleScriptQuote returns the string as a double-quoted AppleScript string literal.
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellQuote escapes the string to be used in a single-quoted PowerShell string literal.
+func powerShellQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
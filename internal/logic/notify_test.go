@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotifyTestSuite struct {
+	suite.Suite
+}
+
+func TestNotifyTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifyTestSuite))
+}
+
+func (suite *NotifyTestSuite) TestDesktopNotificationCommand() {
+	tests := []struct {
+		name   string
+		goos   string
+		wsl    bool
+		failed bool
+		want   []string
+	}{
+		{
+			name: "darwin",
+			goos: "darwin",
+			want: []string{"osascript", "-e", `display notification "reward \"bootstrap\" done" with title "reward: it's"`},
+		},
+		{
+			name:   "linux failed",
+			goos:   "linux",
+			failed: true,
+			want:   []string{"notify-send", "--urgency=critical", "reward: it's", `reward "bootstrap" done`},
+		},
+		{
+			name: "unsupported",
+			goos: "plan9",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want,
+				desktopNotificationCommand(tt.goos, tt.wsl, "reward: it's", `reward "bootstrap" done`, tt.failed),
+			)
+		})
+	}
+
+	got := desktopNotificationCommand("linux", true, "reward: it's", "done", false)
+	assert.Equal(suite.T(), "powershell.exe", got[0])
+	assert.Contains(suite.T(), got[len(got)-1], `ShowBalloonTip(10000, 'reward: it''s', 'done', 'Info')`)
+}
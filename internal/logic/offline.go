@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// composePullNeverConstraint is the constraint of the docker compose versions which support `up --pull never`.
+const composePullNeverConstraint = ">= 2.8.0"
+
+// offlineComposeArgs returns the docker compose arguments for offline mode, and false if the command has to be
+// skipped, because it would pull the images (eg. pull). The up command uses only the locally cached images.
+func (c *Client) offlineComposeArgs(args []string) ([]string, bool) {
+	if !c.Offline() || len(args) == 0 {
+		return args, true
+	}
+
+	switch args[0] {
+	case "pull":
+		log.Println("Offline mode, skipping pulling the images. The locally cached images are used.")
+
+		return args, false
+	case "up":
+		if util.ContainsString(args, "--pull") || !c.composeSupportsPullNever() {
+			return args, true
+		}
+
+		return append([]string{"up", "--pull", "never"}, args[1:]...), true
+	}
+
+	return args, true
+}
+
+// composeSupportsPullNever returns true if the installed docker compose supports `up --pull never`.
+func (c *Client) composeSupportsPullNever() bool {
+	installed, err := c.DockerCompose.Version()
+	if err != nil {
+		return false
+	}
+
+	return version.MustConstraints(version.NewConstraint(composePullNeverConstraint)).Check(installed)
+}
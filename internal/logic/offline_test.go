@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+)
+
+type OfflineTestSuite struct {
+	suite.Suite
+}
+
+func TestOfflineTestSuite(t *testing.T) {
+	suite.Run(t, new(OfflineTestSuite))
+}
+
+func (suite *OfflineTestSuite) TestOfflineComposeArgs() {
+	tests := []struct {
+		name        string
+		offline     bool
+		args        []string
+		want        []string
+		wantProceed bool
+	}{
+		{
+			name:        "online pull",
+			args:        []string{"pull", "--quiet"},
+			want:        []string{"pull", "--quiet"},
+			wantProceed: true,
+		},
+		{
+			name:    "offline pull",
+			offline: true,
+			args:    []string{"pull", "--quiet"},
+			want:    []string{"pull", "--quiet"},
+		},
+		{
+			name:        "offline up with pull policy",
+			offline:     true,
+			args:        []string{"up", "-d", "--pull", "missing"},
+			want:        []string{"up", "-d", "--pull", "missing"},
+			wantProceed: true,
+		},
+		{
+			name:        "offline stop",
+			offline:     true,
+			args:        []string{"stop"},
+			want:        []string{"stop"},
+			wantProceed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestClient(map[string]interface{}{"reward_offline": tt.offline})
+
+			got, proceed := c.offlineComposeArgs(tt.args)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantProceed, proceed)
+		})
+	}
+}
+
+func (suite *OfflineTestSuite) TestPrepareRequestOffline() {
+	c := newTestClient(map[string]interface{}{"reward_offline": "1"})
+
+	_, err := c.prepareRequest("https://example.com/versions.json", false)
+	assert.ErrorIs(suite.T(), err, config.ErrOffline)
+
+	_, err = c.getContentFromURL("https://example.com/versions.json")
+	assert.ErrorIs(suite.T(), err, config.ErrOffline)
+}
@@ -0,0 +1,158 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+)
+
+// jsonKeyInvalidChars matches the characters of the table headers which are replaced in the JSON keys.
+var jsonKeyInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// stdout returns the output of the results of the commands.
+func (c *Client) stdout() io.Writer {
+	if c.Stdout != nil {
+		return c.Stdout
+	}
+
+	return os.Stdout
+}
+
+// printJSON prints the value as indented JSON.
+func (c *Client) printJSON(v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal output: %w", err)
+	}
+
+	_, err = fmt.Fprintln(c.stdout(), string(content))
+	if err != nil {
+		return fmt.Errorf("cannot write output: %w", err)
+	}
+
+	return nil
+}
+
+// printJSONLine prints the value as a single line of JSON, it's used for the streamed results (eg. the container
+// events of status --watch).
+func (c *Client) printJSONLine(v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		log.Errorf("Cannot marshal output: %s", err)
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(c.stdout(), string(content))
+}
+
+// outputTable is a table which is rendered as JSON if --output json is used, so the commands printing tables don't
+// have to build their results twice. A table with a header of multiple columns is rendered as an array of objects
+// keyed by the header, other tables are key-value pairs and they're rendered as an object. The rows of a single cell
+// start a section (eg. "Global Services" of the info command), the following pairs are nested in it. Footers are not
+// rendered as JSON.
+type outputTable struct {
+	table.Writer
+
+	json   bool
+	out    io.Writer
+	header table.Row
+	rows   []table.Row
+}
+
+// newTable returns a table printed to stdout.
+func (c *Client) newTable() *outputTable {
+	t := &outputTable{
+		Writer: table.NewWriter(),
+		json:   c.JSONOutput(),
+		out:    c.stdout(),
+	}
+	t.SetOutputMirror(t.out)
+
+	return t
+}
+
+func (t *outputTable) AppendHeader(row table.Row, configs ...table.RowConfig) {
+	t.header = row
+	t.Writer.AppendHeader(row, configs...)
+}
+
+func (t *outputTable) AppendRow(row table.Row, configs ...table.RowConfig) {
+	t.rows = append(t.rows, row)
+	t.Writer.AppendRow(row, configs...)
+}
+
+func (t *outputTable) AppendRows(rows []table.Row, configs ...table.RowConfig) {
+	t.rows = append(t.rows, rows...)
+	t.Writer.AppendRows(rows, configs...)
+}
+
+// Render renders the table as text or as JSON.
+func (t *outputTable) Render() string {
+	if !t.json {
+		return t.Writer.Render()
+	}
+
+	content, err := json.MarshalIndent(t.value(), "", "  ")
+	if err != nil {
+		log.Errorf("Cannot marshal output: %s", err)
+
+		return ""
+	}
+
+	_, _ = fmt.Fprintln(t.out, string(content))
+
+	return string(content)
+}
+
+// value returns the content of the table as it's rendered as JSON.
+func (t *outputTable) value() interface{} {
+	if len(t.header) > 1 {
+		objects := make([]map[string]interface{}, 0, len(t.rows))
+
+		for _, row := range t.rows {
+			object := make(map[string]interface{}, len(row))
+
+			for i, cell := range row {
+				if i < len(t.header) {
+					object[jsonKey(t.header[i])] = cell
+				}
+			}
+
+			objects = append(objects, object)
+		}
+
+		return objects
+	}
+
+	object := make(map[string]interface{})
+	section := object
+
+	for _, row := range t.rows {
+		switch len(row) {
+		case 0:
+		case 1:
+			section = make(map[string]interface{})
+			object[jsonKey(row[0])] = section
+		case 2:
+			section[jsonKey(row[0])] = row[1]
+		default:
+			section[jsonKey(row[0])] = row[1:]
+		}
+	}
+
+	return object
+}
+
+// jsonKey returns the JSON key of a table header, eg. "Environment name" -> "environment_name".
+func jsonKey(header interface{}) string {
+	return strings.Trim(
+		jsonKeyInvalidChars.ReplaceAllString(strings.ToLower(fmt.Sprint(header)), "_"), "_",
+	)
+}
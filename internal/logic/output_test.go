@@ -0,0 +1,142 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutputTestSuite struct {
+	suite.Suite
+}
+
+func TestOutputTestSuite(t *testing.T) {
+	suite.Run(t, new(OutputTestSuite))
+}
+
+func (suite *OutputTestSuite) TestOutputTable() {
+	tests := []struct {
+		name   string
+		header table.Row
+		rows   []table.Row
+		want   string
+	}{
+		{
+			name:   "header",
+			header: table.Row{"Environment", "Max Active", "Requests/s"},
+			rows:   []table.Row{{"shop", 2, "0.50"}, {"blog", 1}},
+			want: `[{"environment": "shop", "max_active": 2, "requests_s": "0.50"},
+				{"environment": "blog", "max_active": 1}]`,
+		},
+		{
+			name:   "key-value pairs",
+			header: table.Row{"Info"},
+			rows: []table.Row{
+				{"Reward version", "v1.0.0"},
+				{"Global Services"},
+				{"traefik", "https://traefik.reward.test"},
+				{"ports", 80, 443},
+			},
+			want: `{"reward_version": "v1.0.0",
+				"global_services": {"traefik": "https://traefik.reward.test", "ports": [80, 443]}}`,
+		},
+		{
+			name: "empty",
+			want: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			c := newTestClient(map[string]interface{}{"output": "json"})
+			c.Stdout = out
+
+			tb := c.newTable()
+			if tt.header != nil {
+				tb.AppendHeader(tt.header)
+			}
+
+			tb.AppendRows(tt.rows)
+			tb.Render()
+
+			assert.JSONEq(t, tt.want, out.String())
+		})
+	}
+}
+
+func (suite *OutputTestSuite) TestOutputTableText() {
+	out := &bytes.Buffer{}
+	c := newTestClient(nil)
+	c.Stdout = out
+
+	tb := c.newTable()
+	tb.AppendHeader(table.Row{"Environment", "State"})
+	tb.AppendRow(table.Row{"shop", "running"})
+	tb.Render()
+
+	assert.Contains(suite.T(), out.String(), "| ENVIRONMENT | STATE   |")
+	assert.False(suite.T(), json.Valid(out.Bytes()))
+}
+
+func (suite *OutputTestSuite) TestExtractRewardFlagsOutput() {
+	defer log.SetFormatter(&log.TextFormatter{})
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       []string
+		wantOutput string
+	}{
+		{
+			name:       "text",
+			args:       []string{"up", "--output", "text"},
+			want:       []string{"up"},
+			wantOutput: "text",
+		},
+		{
+			name:       "json lists the containers as json",
+			args:       []string{"ps", "--output=json"},
+			want:       []string{"ps", "--format", "json"},
+			wantOutput: "json",
+		},
+		{
+			name:       "json keeps the format of ps",
+			args:       []string{"ps", "--output", "json", "--format=table"},
+			want:       []string{"ps", "--format=table"},
+			wantOutput: "json",
+		},
+		{
+			name:       "output file of docker compose config",
+			args:       []string{"config", "--output", "compose.yml"},
+			want:       []string{"config", "--output", "compose.yml"},
+			wantOutput: "text",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := newTestClient(nil)
+
+			assert.Equal(t, tt.want, c.extractRewardFlags(tt.args))
+			assert.Equal(t, tt.wantOutput, c.Output())
+		})
+	}
+}
+
+func (suite *OutputTestSuite) TestJSONKey() {
+	for header, want := range map[string]string{
+		"Environment":          "environment",
+		"Environment name":     "environment_name",
+		"Max Children Reached": "max_children_reached",
+		"Requests/s":           "requests_s",
+		"PID":                  "pid",
+	} {
+		assert.Equal(suite.T(), want, jsonKey(header), header)
+	}
+}
@@ -0,0 +1,165 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/shell"
+)
+
+// ErrPHPFPMStatusUnavailable occurs when the php-fpm status page cannot be queried.
+var ErrPHPFPMStatusUnavailable = func(s string) error {
+	return fmt.Errorf(
+		"cannot query php-fpm status, make sure the environment uses an up to date php-fpm image "+
+			"(`reward env pull php-fpm && reward env up`): %s", s,
+	)
+}
+
+//nolint:tagliatelle
+type phpFPMStatus struct {
+	Pool               string `json:"pool"`
+	ProcessManager     string `json:"process manager"`
+	StartSince         int64  `json:"start since"`
+	AcceptedConn       int64  `json:"accepted conn"`
+	ListenQueue        int64  `json:"listen queue"`
+	MaxListenQueue     int64  `json:"max listen queue"`
+	ListenQueueLen     int64  `json:"listen queue len"`
+	IdleProcesses      int64  `json:"idle processes"`
+	ActiveProcesses    int64  `json:"active processes"`
+	TotalProcesses     int64  `json:"total processes"`
+	MaxActiveProcesses int64  `json:"max active processes"`
+	MaxChildrenReached int64  `json:"max children reached"`
+	SlowRequests       int64  `json:"slow requests"`
+	Processes          []struct {
+		PID               int64   `json:"pid"`
+		State             string  `json:"state"`
+		Requests          int64   `json:"requests"`
+		RequestDuration   int64   `json:"request duration"`
+		RequestMethod     string  `json:"request method"`
+		RequestURI        string  `json:"request uri"`
+		LastRequestCPU    float64 `json:"last request cpu"`
+		LastRequestMemory int64   `json:"last request memory"`
+	} `json:"processes"`
+}
+
+// RunCmdPHPStatus queries the php-fpm status page of the php-fpm container and prints the worker and queue
+// statistics.
+func (c *Client) RunCmdPHPStatus(cmd *cmdpkg.Command) error {
+	full, _ := cmd.Flags().GetBool("full")
+
+	query := "json"
+	if full {
+		query += "&full"
+	}
+
+	out, err := c.RunCmdEnvDockerComposeOutput([]string{
+		"exec", "-T", "php-fpm", "sh", "-c",
+		fmt.Sprintf(
+			"SCRIPT_NAME=/status SCRIPT_FILENAME=/status QUERY_STRING='%s' REQUEST_METHOD=GET "+
+				"cgi-fcgi -bind -connect 127.0.0.1:${NGINX_UPSTREAM_PORT:-9000}",
+			query,
+		),
+	})
+	if err != nil {
+		return ErrPHPFPMStatusUnavailable(strings.TrimSpace(out))
+	}
+
+	// The response contains the FastCGI headers before the JSON body.
+	start := strings.Index(out, "{")
+	if start < 0 {
+		return ErrPHPFPMStatusUnavailable(strings.TrimSpace(out))
+	}
+
+	var status phpFPMStatus
+
+	err = json.Unmarshal([]byte(out[start:]), &status)
+	if err != nil {
+		return fmt.Errorf("cannot parse php-fpm status: %w", err)
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Pool", "Uptime", "Active", "Idle", "Total", "Max Active", "Queue", "Max Queue",
+		"Max Children Reached", "Slow Requests", "Accepted"})
+	t.AppendRow(table.Row{
+		status.Pool,
+		time.Duration(status.StartSince) * time.Second,
+		status.ActiveProcesses,
+		status.IdleProcesses,
+		status.TotalProcesses,
+		status.MaxActiveProcesses,
+		fmt.Sprintf("%d/%d", status.ListenQueue, status.ListenQueueLen),
+		status.MaxListenQueue,
+		status.MaxChildrenReached,
+		status.SlowRequests,
+		status.AcceptedConn,
+	})
+	t.Render()
+
+	if status.MaxChildrenReached > 0 {
+		log.Warnln("The php-fpm pool reached pm.max_children, requests were queued.")
+	}
+
+	if !full {
+		return nil
+	}
+
+	pt := c.newTable()
+	pt.AppendHeader(table.Row{"PID", "State", "Requests", "Duration", "Request", "CPU", "Memory"})
+
+	for _, p := range status.Processes {
+		pt.AppendRow(table.Row{
+			p.PID,
+			p.State,
+			p.Requests,
+			(time.Duration(p.RequestDuration) * time.Microsecond).Round(time.Millisecond),
+			fmt.Sprintf("%s %s", p.RequestMethod, p.RequestURI),
+			fmt.Sprintf("%.2f%%", p.LastRequestCPU),
+			fmt.Sprintf("%.1fM", float64(p.LastRequestMemory)/1024/1024),
+		})
+	}
+
+	pt.Render()
+
+	return nil
+}
+
+// RunCmdPHPSlowlogTail prints the php-fpm slowlog of the php-fpm container. By default the slowlog is written to
+// the container's stderr, so it's printed from the container logs among the access and error logs.
+func (c *Client) RunCmdPHPSlowlogTail(cmd *cmdpkg.Command) error {
+	lines, _ := cmd.Flags().GetInt("lines")
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	args := []string{"logs", "--no-log-prefix", "--tail", strconv.Itoa(lines)}
+	if follow {
+		args = append(args, "--follow")
+	}
+
+	args = append(args, "php-fpm")
+
+	if f := c.PHPSlowlog(); f != "" && !strings.HasPrefix(f, "/proc/self/fd/") {
+		script := fmt.Sprintf(`if [ ! -f "%[1]s" ]; then echo "No slow requests were logged yet `+
+			`(threshold: ${PHP_FPM_SLOWLOG_TIMEOUT:-5s})." >&2; fi; `, f)
+
+		if follow {
+			script += fmt.Sprintf(`exec tail -n %d -F "%s"`, lines, f)
+		} else {
+			script += fmt.Sprintf(`if [ -f "%[2]s" ]; then exec tail -n %[1]d "%[2]s"; fi`, lines, f)
+		}
+
+		args = []string{"exec", "php-fpm", "sh", "-c", script}
+	}
+
+	err := c.RunCmdEnvDockerCompose(args, shell.WithCatchOutput(false))
+	if err != nil {
+		return fmt.Errorf("cannot read php-fpm slowlog: %w", err)
+	}
+
+	return nil
+}
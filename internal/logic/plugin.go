@@ -3,6 +3,7 @@ package logic
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -23,9 +25,30 @@ import (
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrPluginNotPinnable occurs when a plugin installed from a direct URL is pinned to a version, but its URL doesn't
+// contain the {version} placeholder.
+var ErrPluginNotPinnable = func(name string) error {
+	return fmt.Errorf("plugin %s cannot be pinned to a version, its url doesn't contain the {version} placeholder", name)
+}
+
+// ErrPluginVersionNotFound occurs when the release of the version a plugin is pinned to doesn't exist.
+var ErrPluginVersionNotFound = func(name, pinned string) error {
+	return fmt.Errorf("cannot find version %s of plugin %s", pinned, name)
+}
+
+// pluginFileMode is the file mode of the installed plugin binaries.
+const pluginFileMode = 0o755
+
+// pluginArchiveSuffixes are the suffixes of the archives and binaries which can be installed from a direct URL.
+var pluginArchiveSuffixes = []string{".tar.gz", ".tgz", ".zip", ".exe"}
+
 func (c *Client) RunCmdPluginList() error {
 	plugins := c.Plugins()
 
+	if c.JSONOutput() {
+		return c.printJSON(plugins)
+	}
+
 	if len(plugins) > 0 {
 		log.Println("The following plugins are installed:")
 	} else {
@@ -40,7 +63,14 @@ func (c *Client) RunCmdPluginList() error {
 }
 
 func (c *Client) RunCmdPluginListAvailable() error {
-	plugins := c.PluginsAvailable()
+	plugins, err := c.PluginsAvailable()
+	if err != nil {
+		return err
+	}
+
+	if c.JSONOutput() {
+		return c.printJSON(plugins)
+	}
 
 	if len(plugins) > 0 {
 		log.Println("The following plugins are available online:")
@@ -64,6 +94,11 @@ func (c *Client) RunCmdPluginRemove(cmd *cmdpkg.Command, args []string) error {
 			if err != nil {
 				return err
 			}
+
+			err = c.pinPlugin(plugin, "")
+			if err != nil {
+				return err
+			}
 		}
 
 		log.Print("...plugin removed.")
@@ -72,38 +107,83 @@ func (c *Client) RunCmdPluginRemove(cmd *cmdpkg.Command, args []string) error {
 	return nil
 }
 
+// RunCmdPluginInstall installs the plugins from the index of the available plugins. A plugin can be pinned to a
+// version (eg. foo@1.2.0), the pinned plugins are not updated by `plugin update`, and installing the plugin without
+// a version unpins it. An installed plugin is only reinstalled if its version differs from the pinned version (or
+// a newer version is released) or --force is used.
 func (c *Client) RunCmdPluginInstall(cmd *cmdpkg.Command, args []string) error {
-	err := c.checkPlugins(args)
+	names := make([]string, 0, len(args))
+	pins := make(map[string]string, len(args))
+
+	for _, arg := range args {
+		name, pinned := parsePluginArg(arg)
+		names = append(names, name)
+		pins[name] = pinned
+	}
+
+	err := c.checkPlugins(names)
 	if err != nil {
 		return err
 	}
 
-	for _, plugin := range args {
+	for _, plugin := range names {
+		pinned := pins[plugin]
+
 		log.Printf("Installing plugin %s...", plugin)
 
-		needsUpdate, err := c.pluginIsNotLatest(cmd, plugin)
+		needsUpdate, err := c.pluginIsNotLatest(cmd, plugin, pinned)
 		if err != nil {
 			return err
 		}
 
-		if flag(cmd, "force") || needsUpdate {
-			if util.AskForConfirmation(fmt.Sprintf("Would you like to install plugin %s?", plugin)) {
-				err = c.pluginInstall(cmd, plugin)
-				if err != nil {
-					return err
-				}
+		switch {
+		case !flag(cmd, "force") && !needsUpdate:
+			log.Printf("...plugin %s is already up to date.", plugin)
+		case flag(cmd, "dry-run"):
+			log.Printf("...plugin %s would be installed.", plugin)
+
+			continue
+		case !util.AskForConfirmation(fmt.Sprintf("Would you like to install plugin %s?", plugin)):
+			log.Printf("...plugin %s is not installed.", plugin)
+
+			continue
+		default:
+			err = c.pluginInstall(cmd, plugin, pinned)
+			if err != nil {
+				return err
 			}
 
-			log.Print("...plugin installed.")
+			log.Printf("...plugin %s installed.", plugin)
+		}
+
+		if flag(cmd, "dry-run") {
+			continue
+		}
+
+		err = c.pinPlugin(plugin, pinned)
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// parsePluginArg returns the name of the plugin and the version it's pinned to from the argument, eg. foo@1.2.0.
+func parsePluginArg(arg string) (string, string) {
+	name, pinned, _ := strings.Cut(arg, "@")
+
+	return name, pinned
+}
+
 func (c *Client) checkPlugins(args []string) error {
+	available, err := c.PluginsAvailable()
+	if err != nil {
+		return err
+	}
+
 	for _, plugin := range args {
-		if _, ok := c.PluginsAvailable()[plugin]; !ok {
+		if _, ok := available[plugin]; !ok {
 			return fmt.Errorf("plugin %s is not available", plugin)
 		}
 	}
@@ -111,94 +191,143 @@ func (c *Client) checkPlugins(args []string) error {
 	return nil
 }
 
-func (c *Client) pluginIsNotLatest(cmd *cmdpkg.Command, name string) (bool, error) {
-	pluginURL, err := c.pluginURL(name)
-	if err != nil {
-		return false, err
+// pluginIsNotLatest returns true if the plugin is not installed, or its installed version differs from the pinned
+// version or older than the latest version in the index.
+func (c *Client) pluginIsNotLatest(cmd *cmdpkg.Command, name, pinned string) (bool, error) {
+	_, binaryPath := c.pluginBinary(name)
+	if !util.FileExists(binaryPath) {
+		return true, nil
 	}
 
-	currentRelease, err := c.fetchRelease(cmd, pluginURL)
+	remoteVersion, err := c.pluginRemoteVersion(cmd, name, pinned)
 	if err != nil {
-		return false, fmt.Errorf("cannot fetch latest release: %w", err)
+		return false, err
 	}
 
-	if currentRelease == nil {
-		return false, fmt.Errorf("cannot find latest release")
-	}
+	if remoteVersion == nil {
+		log.Printf("The remote version of plugin %s cannot be determined. Use --force to reinstall it.", name)
 
-	remoteVersion := version.Must(version.NewVersion(strings.TrimSpace(currentRelease.TagName)))
+		return false, nil
+	}
 
-	pluginVersion, err := c.pluginVersion(name)
-	if pluginVersion == "" || err != nil {
+	currentVersion, err := c.pluginInstalledVersion(name)
+	if err != nil {
 		log.Debugf("Cannot get plugin version. Error: %s", err)
 		log.Printf("Cannot determine plugin version. Remote version: %s", remoteVersion.String())
 
 		return true, nil
 	}
 
-	currentVersion := version.Must(version.NewVersion(pluginVersion))
-
 	log.Printf("Current version: %s, Remote version: %s",
 		currentVersion.String(),
 		remoteVersion.String())
 
+	if pinned != "" {
+		return !remoteVersion.Equal(currentVersion), nil
+	}
+
 	return remoteVersion.GreaterThan(currentVersion), nil
 }
 
-func (c *Client) pluginInstall(cmd *cmdpkg.Command, name string) error {
-	binaryName := fmt.Sprintf("%s-%s", c.AppName(), name)
+// pluginRemoteVersion returns the version of the plugin to install: the pinned version, or the latest version from
+// the index. It returns nil if the version is unknown (the plugin is installed from a direct URL without version).
+func (c *Client) pluginRemoteVersion(cmd *cmdpkg.Command, name, pinned string) (*version.Version, error) {
+	if pinned != "" {
+		v, err := version.NewVersion(pinned)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %s of plugin %s: %w", pinned, name, err)
+		}
 
-	binaryPath := filepath.Join(c.PluginsDir(), binaryName)
+		return v, nil
+	}
 
-	if util.OSDistro() == "windows" {
-		binaryName = fmt.Sprintf("%s.exe", binaryName)
+	plugin, err := c.availablePlugin(name)
+	if err != nil {
+		return nil, err
+	}
 
-		if !strings.HasSuffix(binaryPath, ".exe") {
-			binaryPath += ".exe"
+	remoteVersion := plugin.Version
+
+	if !pluginDirectURL(plugin.URL) {
+		currentRelease, err := c.fetchRelease(cmd, plugin.URL)
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch latest release: %w", err)
 		}
+
+		if currentRelease == nil {
+			return nil, fmt.Errorf("cannot find latest release")
+		}
+
+		remoteVersion = currentRelease.TagName
 	}
 
-	symlinkPath, _ := util.EvalSymlinkPath(binaryPath)
-	if symlinkPath != "" {
-		binaryPath = symlinkPath
+	if remoteVersion == "" {
+		return nil, nil
 	}
 
-	asset, err := c.pluginNormalizedURL(cmd, name)
+	v, err := version.NewVersion(strings.TrimSpace(remoteVersion))
 	if err != nil {
-		return fmt.Errorf("cannot get update url: %w", err)
+		return nil, fmt.Errorf("invalid remote version of plugin %s: %w", name, err)
 	}
 
-	fileURL, err := url.Parse(asset.URL)
+	return v, nil
+}
+
+// pluginInstalledVersion returns the version of the installed plugin reported by its --version flag.
+func (c *Client) pluginInstalledVersion(name string) (*version.Version, error) {
+	pluginVersion, err := c.pluginVersion(name)
 	if err != nil {
-		return fmt.Errorf("cannot parse url: %w", err)
+		return nil, err
 	}
 
-	req, err := c.prepareRequest(asset.URL, true)
+	v, err := version.NewVersion(pluginVersion)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid version of plugin %s: %w", name, err)
+	}
+
+	return v, nil
+}
+
+// pluginInstall downloads the archive (or the binary) of the plugin, extracts the binary and places it into the
+// plugins directory. An installed binary is replaced atomically.
+func (c *Client) pluginInstall(cmd *cmdpkg.Command, name, pinned string) error {
+	binaryName, binaryPath := c.pluginBinary(name)
+
+	symlinkPath, _ := util.EvalSymlinkPath(binaryPath)
+	if symlinkPath != "" {
+		binaryPath = symlinkPath
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	asset, err := c.pluginAsset(cmd, name, pinned)
 	if err != nil {
-		return fmt.Errorf("cannot run request: response %s, error: %w", resp.Body, err)
+		return fmt.Errorf("cannot get update url: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("%s URL not found", fileURL.String())
+	archive, err := c.downloadFile(asset.URL, asset.Name, asset.SHA256)
+	if err != nil {
+		return err
 	}
+	defer archive.Close()
 
-	newBinary, err := util.DecompressFileFromArchive(resp.Body, asset.Name, binaryName)
+	newBinary, err := util.DecompressFileFromArchive(archive, asset.Name, binaryName)
 	if err != nil {
 		return err
 	}
 
-	_, err = os.Open(binaryPath)
+	if closer, ok := newBinary.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// update.Apply replaces an existing file only
+	_, err = os.Stat(binaryPath)
 	if errors.Is(err, os.ErrNotExist) {
-		_ = util.CreateDirAndWriteToFile([]byte{}, binaryPath)
+		err = util.CreateDirAndWriteToFile([]byte{}, binaryPath, pluginFileMode)
+		if err != nil {
+			return fmt.Errorf("cannot create plugin binary: %w", err)
+		}
 	}
 
-	err = update.Apply(newBinary, update.Options{TargetPath: binaryPath})
+	err = update.Apply(newBinary, update.Options{TargetPath: binaryPath, TargetMode: pluginFileMode})
 	if err != nil {
 		return fmt.Errorf("cannot apply update: %w", err)
 	}
@@ -207,6 +336,10 @@ func (c *Client) pluginInstall(cmd *cmdpkg.Command, name string) error {
 }
 
 func (c *Client) prepareRequest(downloadURL string, binary bool) (*http.Request, error) {
+	if c.Offline() {
+		return nil, fmt.Errorf("%w, cannot download %s", config.ErrOffline, downloadURL)
+	}
+
 	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, downloadURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create request: %w", err)
@@ -228,14 +361,7 @@ func (c *Client) prepareRequest(downloadURL string, binary bool) (*http.Request,
 }
 
 func (c *Client) pluginRemove(name string) error {
-	binaryName := fmt.Sprintf("%s-%s", c.AppName(), name)
-	binaryPath := filepath.Join(c.PluginsDir(), binaryName)
-
-	if util.OSDistro() == "windows" {
-		if !strings.HasSuffix(binaryPath, ".exe") {
-			binaryPath += ".exe"
-		}
-	}
+	_, binaryPath := c.pluginBinary(name)
 
 	err := os.Remove(binaryPath)
 	if err != nil {
@@ -245,16 +371,150 @@ func (c *Client) pluginRemove(name string) error {
 	return nil
 }
 
-func (c *Client) pluginURL(name string) (string, error) {
-	plugin, ok := c.PluginsAvailable()[name]
+// pluginBinary returns the name of the plugin binary and its path in the plugins directory.
+func (c *Client) pluginBinary(name string) (string, string) {
+	binaryName := fmt.Sprintf("%s-%s", c.AppName(), name)
+	if util.OSDistro() == "windows" {
+		binaryName += ".exe"
+	}
+
+	return binaryName, filepath.Join(c.PluginsDir(), binaryName)
+}
+
+// availablePlugin returns the plugin from the index of the available plugins.
+func (c *Client) availablePlugin(name string) (*config.Plugin, error) {
+	available, err := c.PluginsAvailable()
+	if err != nil {
+		return nil, err
+	}
+
+	plugin, ok := available[name]
 	if !ok {
-		return "", fmt.Errorf("plugin %s is not available", name)
+		return nil, fmt.Errorf("plugin %s is not available", name)
 	}
 
-	return plugin.URL, nil
+	return plugin, nil
 }
 
-func (c *Client) pluginNormalizedURL(cmd *cmdpkg.Command, name string) (*asset, error) {
+// pluginAsset returns the archive (or the binary) of the pinned version (or the latest version) of the plugin for the
+// current platform. The URL of the plugin in the index is either the releases API of a GitHub repository or a direct
+// URL (see pluginDirectURL).
+func (c *Client) pluginAsset(cmd *cmdpkg.Command, name, pinned string) (*asset, error) {
+	plugin, err := c.availablePlugin(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pluginDirectURL(plugin.URL) {
+		var currentRelease *release
+
+		if pinned != "" {
+			currentRelease, err = c.fetchPluginRelease(name, plugin.URL, pinned)
+		} else {
+			currentRelease, err = c.fetchRelease(cmd, plugin.URL)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot fetch release: %w", err)
+		}
+
+		if currentRelease == nil {
+			return nil, fmt.Errorf("cannot find latest release")
+		}
+
+		return c.pluginReleaseAsset(name, currentRelease)
+	}
+
+	pluginVersion, checksum := plugin.Version, plugin.SHA256
+	if pinned != "" && pinned != plugin.Version {
+		if !strings.Contains(plugin.URL, "{version}") {
+			return nil, ErrPluginNotPinnable(name)
+		}
+
+		// the checksum in the index belongs to the archive of the latest version
+		pluginVersion, checksum = pinned, ""
+	}
+
+	downloadURL := pluginPlatformURL(plugin.URL, pluginVersion)
+
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse url %s: %w", downloadURL, err)
+	}
+
+	return &asset{Name: path.Base(u.Path), URL: downloadURL, SHA256: checksum}, nil
+}
+
+// fetchPluginRelease returns the release of the plugin tagged with the pinned version (eg. 1.2.0 or v1.2.0) from the
+// releases API of the GitHub repository.
+func (c *Client) fetchPluginRelease(name, releasesURL, pinned string) (*release, error) {
+	tags := []string{pinned}
+	if !strings.HasPrefix(pinned, "v") {
+		tags = append(tags, "v"+pinned)
+	}
+
+	for _, tag := range tags {
+		content, err := c.getContentFromURL(fmt.Sprintf("%s/tags/%s", strings.TrimSuffix(releasesURL, "/"), tag))
+		if errors.Is(err, config.ErrOffline) {
+			return nil, err
+		}
+
+		if err != nil {
+			log.Debugf("Cannot fetch release %s of plugin %s: %s", tag, name, err)
+
+			continue
+		}
+
+		var r release
+
+		err = json.Unmarshal(content, &r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal remote data: %w", err)
+		}
+
+		return &r, nil
+	}
+
+	return nil, ErrPluginVersionNotFound(name, pinned)
+}
+
+// pluginDirectURL returns true if the URL of the plugin points to an archive (zip, tar.gz) or a binary instead of
+// the releases API of a GitHub repository. A direct URL can contain the {os}, {arch} and {version} placeholders, eg.
+// https://example.com/{version}/reward-foo_{os}_{arch}.tar.gz.
+func pluginDirectURL(pluginURL string) bool {
+	u, err := url.Parse(pluginURL)
+	if err != nil {
+		return false
+	}
+
+	for _, placeholder := range []string{"{os}", "{arch}", "{version}"} {
+		if strings.Contains(u.Path, placeholder) {
+			return true
+		}
+	}
+
+	for _, suffix := range pluginArchiveSuffixes {
+		if strings.HasSuffix(u.Path, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pluginPlatformURL replaces the {os} and {arch} placeholders of the URL with the current platform (eg. linux and
+// amd64), and the {version} placeholder with the version.
+func pluginPlatformURL(pluginURL, pluginVersion string) string {
+	return strings.NewReplacer(
+		"{os}", runtime.GOOS,
+		"{arch}", runtime.GOARCH,
+		"{version}", pluginVersion,
+	).Replace(pluginURL)
+}
+
+// pluginReleaseAsset returns the archive of the plugin for the current platform from the assets of the release, eg.
+// reward-foo_Linux_x86_64.tar.gz.
+func (c *Client) pluginReleaseAsset(name string, release *release) (*asset, error) {
 	replacements := map[string]map[string]string{
 		"darwin": {
 			"darwin": "Darwin",
@@ -277,16 +537,6 @@ func (c *Client) pluginNormalizedURL(cmd *cmdpkg.Command, name string) (*asset,
 	goOS := runtime.GOOS
 	goArch := runtime.GOARCH
 
-	pluginURL, err := c.pluginURL(name)
-	if err != nil {
-		return nil, err
-	}
-
-	release, err := c.fetchRelease(cmd, pluginURL)
-	if err != nil {
-		return nil, fmt.Errorf("cannot fetch latest release: %w", err)
-	}
-
 	var packagename string
 
 	switch goOS {
@@ -308,18 +558,22 @@ func (c *Client) pluginNormalizedURL(cmd *cmdpkg.Command, name string) (*asset,
 
 	for _, asset := range release.Assets {
 		if asset.Name == packagename {
+			asset.SHA256 = c.releaseAssetChecksum(release, &asset)
+
 			return &asset, nil
 		}
 	}
 
-	return nil, fmt.Errorf("cannot find asset %s", name)
+	return nil, fmt.Errorf("cannot find asset %s in release %s", packagename, release.TagName)
 }
 
 func (c *Client) pluginVersion(name string) (string, error) {
 	var combinedOutBuf bytes.Buffer
 
+	_, binaryPath := c.pluginBinary(name)
+
 	//nolint:gosec
-	cmd := exec.Command(filepath.Join(c.PluginsDir(), fmt.Sprintf("%s-%s", c.AppName(), name)))
+	cmd := exec.Command(binaryPath)
 	cmd.Args = append(cmd.Args, "--version")
 	cmd.Stdout = io.Writer(&combinedOutBuf)
 	cmd.Stderr = io.Writer(&combinedOutBuf)
@@ -0,0 +1,227 @@
+package logic
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type PluginTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PluginTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewOsFs()}
+	util.FS = config.FS
+}
+
+func TestPluginTestSuite(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}
+
+// pluginTestArchive returns a tar.gz archive containing the file.
+func pluginTestArchive(t *testing.T, name, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+
+	_, err := tw.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func (suite *PluginTestSuite) TestPluginDirectURL() {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://api.github.com/repos/rewardenv/reward-plugin-template/releases", want: false},
+		{url: "https://example.com/reward-foo_linux_amd64.tar.gz", want: true},
+		{url: "https://example.com/reward-foo.tgz?token=abc", want: true},
+		{url: "https://example.com/reward-foo_{os}_{arch}.zip", want: true},
+		{url: "https://example.com/{os}/{arch}/reward-foo", want: true},
+		{url: "https://example.com/{version}/reward-foo", want: true},
+		{url: "https://example.com/releases", want: false},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, pluginDirectURL(tt.url))
+		})
+	}
+}
+
+func (suite *PluginTestSuite) TestPluginPlatformURL() {
+	assert.Equal(suite.T(),
+		"https://example.com/1.2.0/reward-foo_"+runtime.GOOS+"_"+runtime.GOARCH+".tar.gz",
+		pluginPlatformURL("https://example.com/{version}/reward-foo_{os}_{arch}.tar.gz", "1.2.0"),
+	)
+}
+
+func (suite *PluginTestSuite) TestParsePluginArg() {
+	tests := []struct {
+		arg        string
+		wantName   string
+		wantPinned string
+	}{
+		{arg: "foo", wantName: "foo"},
+		{arg: "foo@1.2.0", wantName: "foo", wantPinned: "1.2.0"},
+		{arg: "foo@v1.2.0", wantName: "foo", wantPinned: "v1.2.0"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.arg, func(t *testing.T) {
+			name, pinned := parsePluginArg(tt.arg)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantPinned, pinned)
+		})
+	}
+}
+
+func (suite *PluginTestSuite) TestPluginInstall() {
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("the plugin binaries are named .exe on windows")
+	}
+
+	var content string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "archive.tar.gz", time.Time{},
+			bytes.NewReader(pluginTestArchive(suite.T(), "reward-foo", content)))
+	}))
+	defer server.Close()
+
+	dir := suite.T().TempDir()
+	c := newTestClient(map[string]interface{}{
+		"reward_home_dir":    dir,
+		"reward_plugins_dir": dir + "/plugins",
+		"reward_plugins_available": map[string]interface{}{
+			"foo": map[string]interface{}{"url": server.URL + "/reward-foo_{os}_{arch}.tar.gz"},
+		},
+	})
+
+	_, binaryPath := c.pluginBinary("foo")
+
+	needsUpdate, err := c.pluginIsNotLatest(nil, "foo", "")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), needsUpdate)
+
+	content = "v1"
+	assert.NoError(suite.T(), c.pluginInstall(nil, "foo", ""))
+
+	got, err := os.ReadFile(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "v1", string(got))
+
+	info, err := os.Stat(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), os.FileMode(0o755), info.Mode().Perm())
+
+	// an installed plugin from a direct url is not reinstalled without --force
+	needsUpdate, err = c.pluginIsNotLatest(nil, "foo", "")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), needsUpdate)
+
+	// the reinstalled binary replaces the installed one
+	content = "v2"
+	assert.NoError(suite.T(), c.pluginInstall(nil, "foo", ""))
+
+	got, err = os.ReadFile(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "v2", string(got))
+
+	// the installed binary is kept if the checksum of the archive doesn't match
+	c.Set("reward_plugins_available", map[string]interface{}{
+		"foo": map[string]interface{}{"url": server.URL + "/reward-foo.tar.gz", "sha256": "0000"},
+	})
+
+	content = "v3"
+	assert.Error(suite.T(), c.pluginInstall(nil, "foo", ""))
+
+	got, err = os.ReadFile(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "v2", string(got))
+}
+
+func (suite *PluginTestSuite) TestPluginInstallPinned() {
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("the plugin binaries are named .exe on windows")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the content of the binary is the requested version
+		http.ServeContent(w, r, "archive.tar.gz", time.Time{},
+			bytes.NewReader(pluginTestArchive(suite.T(), "reward-foo", path.Base(path.Dir(r.URL.Path)))))
+	}))
+	defer server.Close()
+
+	dir := suite.T().TempDir()
+	c := newTestClient(map[string]interface{}{
+		"reward_home_dir":    suite.T().TempDir(),
+		"reward_plugins_dir": dir,
+		"reward_plugins_available": map[string]interface{}{
+			"foo": map[string]interface{}{"url": server.URL + "/{version}/reward-foo.tar.gz", "version": "1.3.0"},
+			"bar": map[string]interface{}{"url": server.URL + "/latest/reward-bar.tar.gz"},
+		},
+	})
+
+	_, binaryPath := c.pluginBinary("foo")
+
+	assert.NoError(suite.T(), c.pluginInstall(nil, "foo", "1.2.0"))
+
+	got, err := os.ReadFile(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1.2.0", string(got))
+
+	assert.NoError(suite.T(), c.pluginInstall(nil, "foo", ""))
+
+	got, err = os.ReadFile(binaryPath)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1.3.0", string(got))
+
+	assert.ErrorContains(suite.T(), c.pluginInstall(nil, "bar", "1.2.0"), ErrPluginNotPinnable("bar").Error())
+}
+
+func (suite *PluginTestSuite) TestFetchPluginRelease() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases/tags/v1.2.0" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0"}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(nil)
+
+	got, err := c.fetchPluginRelease("foo", server.URL+"/releases", "1.2.0")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "v1.2.0", got.TagName)
+
+	_, err = c.fetchPluginRelease("foo", server.URL+"/releases", "1.1.0")
+	assert.ErrorContains(suite.T(), err, ErrPluginVersionNotFound("foo", "1.1.0").Error())
+}
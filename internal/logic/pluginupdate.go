@@ -0,0 +1,227 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrPluginNotInstalled occurs when a plugin which is not installed is updated.
+var ErrPluginNotInstalled = func(name string) error {
+	return fmt.Errorf("plugin %s is not installed, use plugin install", name)
+}
+
+// The actions of the plugin update.
+const (
+	pluginActionUpdate   = "update"
+	pluginActionUpToDate = "up to date"
+	pluginActionPinned   = "pinned"
+	pluginActionUnknown  = "unknown remote version"
+)
+
+// pluginUpdate is the planned update of an installed plugin.
+type pluginUpdate struct {
+	Name      string
+	Installed string
+	Available string
+	Action    string
+}
+
+// RunCmdPluginUpdate updates the installed plugins (or the plugins in args) if a newer version exists in the index of
+// the available plugins. The versions are compared using semantic versioning. The pinned plugins are not updated,
+// use `plugin install foo@1.3.0` to change the pinned version. With --dry-run only the planned updates are printed.
+func (c *Client) RunCmdPluginUpdate(cmd *cmdpkg.Command, args []string) error {
+	names, err := c.pluginUpdateNames(args)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		log.Println("No plugins are installed from the available plugins.")
+
+		return nil
+	}
+
+	pins, err := c.pluginPins()
+	if err != nil {
+		return err
+	}
+
+	tb := c.newTable()
+	tb.AppendHeader(table.Row{"Plugin", "Installed", "Available", "Action"})
+
+	updates := make([]*pluginUpdate, 0, len(names))
+
+	for _, name := range names {
+		u, err := c.pluginUpdatePlan(cmd, name, pins[name])
+		if err != nil {
+			return err
+		}
+
+		updates = append(updates, u)
+		tb.AppendRow(table.Row{u.Name, u.Installed, u.Available, u.Action})
+	}
+
+	tb.Render()
+
+	if flag(cmd, "dry-run") {
+		return nil
+	}
+
+	for _, u := range updates {
+		if u.Action != pluginActionUpdate {
+			continue
+		}
+
+		log.Printf("Updating plugin %s to %s...", u.Name, u.Available)
+
+		if !util.AskForConfirmation(fmt.Sprintf("Would you like to update plugin %s?", u.Name)) {
+			log.Printf("...plugin %s is not updated.", u.Name)
+
+			continue
+		}
+
+		err = c.pluginInstall(cmd, u.Name, "")
+		if err != nil {
+			return err
+		}
+
+		log.Printf("...plugin %s updated.", u.Name)
+	}
+
+	return nil
+}
+
+// pluginUpdateNames returns the plugins to update: the plugins in args, or the installed plugins which are listed in
+// the index of the available plugins.
+func (c *Client) pluginUpdateNames(args []string) ([]string, error) {
+	available, err := c.PluginsAvailable()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 0 {
+		for _, name := range args {
+			if _, ok := available[name]; !ok {
+				return nil, fmt.Errorf("plugin %s is not available", name)
+			}
+
+			if _, binaryPath := c.pluginBinary(name); !util.FileExists(binaryPath) {
+				return nil, ErrPluginNotInstalled(name)
+			}
+		}
+
+		return args, nil
+	}
+
+	var names []string
+
+	for _, plugin := range c.Plugins() {
+		if _, ok := available[plugin.Name]; !ok {
+			log.Debugf("Plugin %s is not in the available plugins, skipping.", plugin.Name)
+
+			continue
+		}
+
+		names = append(names, plugin.Name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// pluginUpdatePlan compares the installed version of the plugin with the latest version in the index.
+func (c *Client) pluginUpdatePlan(cmd *cmdpkg.Command, name, pinned string) (*pluginUpdate, error) {
+	u := &pluginUpdate{Name: name, Installed: "unknown", Available: "unknown"}
+
+	installed, err := c.pluginInstalledVersion(name)
+	if err != nil {
+		log.Debugf("Cannot get plugin version. Error: %s", err)
+	} else {
+		u.Installed = installed.Original()
+	}
+
+	if pinned != "" {
+		u.Available = pinned
+		u.Action = pluginActionPinned
+
+		return u, nil
+	}
+
+	remote, err := c.pluginRemoteVersion(cmd, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case remote == nil:
+		u.Action = pluginActionUnknown
+	case installed == nil || remote.GreaterThan(installed):
+		u.Available = remote.Original()
+		u.Action = pluginActionUpdate
+	default:
+		u.Available = remote.Original()
+		u.Action = pluginActionUpToDate
+	}
+
+	return u, nil
+}
+
+// pluginPins returns the versions the plugins are pinned to.
+func (c *Client) pluginPins() (map[string]string, error) {
+	pins := make(map[string]string)
+
+	content, err := util.FS.ReadFile(c.PluginPinsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pins, nil
+		}
+
+		return nil, fmt.Errorf("cannot read pinned plugins: %w", err)
+	}
+
+	err = json.Unmarshal(content, &pins)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse pinned plugins: %w", err)
+	}
+
+	return pins, nil
+}
+
+// pinPlugin pins the plugin to the version, or unpins it if the version is empty.
+func (c *Client) pinPlugin(name, pinned string) error {
+	pins, err := c.pluginPins()
+	if err != nil {
+		return err
+	}
+
+	if pins[name] == pinned {
+		return nil
+	}
+
+	if pinned == "" {
+		delete(pins, name)
+	} else {
+		pins[name] = pinned
+	}
+
+	content, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal pinned plugins: %w", err)
+	}
+
+	err = util.CreateDirAndWriteToFile(content, c.PluginPinsFile())
+	if err != nil {
+		return fmt.Errorf("cannot write pinned plugins: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type PluginUpdateTestSuite struct {
+	suite.Suite
+}
+
+func (suite *PluginUpdateTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewOsFs()}
+	util.FS = config.FS
+}
+
+func TestPluginUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(PluginUpdateTestSuite))
+}
+
+func (suite *PluginUpdateTestSuite) TestPinPlugin() {
+	c := newTestClient(map[string]interface{}{"reward_plugins_config_dir": suite.T().TempDir()})
+
+	pins, err := c.pluginPins()
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), pins)
+
+	assert.NoError(suite.T(), c.pinPlugin("foo", "1.2.0"))
+	assert.NoError(suite.T(), c.pinPlugin("bar", "2.0.0"))
+	assert.NoError(suite.T(), c.pinPlugin("bar", ""))
+
+	pins, err = c.pluginPins()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]string{"foo": "1.2.0"}, pins)
+}
+
+func (suite *PluginUpdateTestSuite) TestPluginUpdatePlan() {
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("the plugin binaries are shell scripts")
+	}
+
+	tests := []struct {
+		name          string
+		installed     string
+		remote        string
+		pinned        string
+		wantAvailable string
+		wantAction    string
+	}{
+		{
+			name:          "newer version",
+			installed:     "1.0.0",
+			remote:        "1.2.0",
+			wantAvailable: "1.2.0",
+			wantAction:    pluginActionUpdate,
+		},
+		{
+			name:          "semantic versioning",
+			installed:     "1.9.0",
+			remote:        "1.10.0",
+			wantAvailable: "1.10.0",
+			wantAction:    pluginActionUpdate,
+		},
+		{
+			name:          "up to date",
+			installed:     "1.2.0",
+			remote:        "1.2.0",
+			wantAvailable: "1.2.0",
+			wantAction:    pluginActionUpToDate,
+		},
+		{
+			name:          "installed version is newer",
+			installed:     "1.3.0",
+			remote:        "1.2.0",
+			wantAvailable: "1.2.0",
+			wantAction:    pluginActionUpToDate,
+		},
+		{
+			name:          "pinned",
+			installed:     "1.0.0",
+			remote:        "1.2.0",
+			pinned:        "1.0.0",
+			wantAvailable: "1.0.0",
+			wantAction:    pluginActionPinned,
+		},
+		{
+			name:          "unknown remote version",
+			installed:     "1.0.0",
+			wantAvailable: "unknown",
+			wantAction:    pluginActionUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			c := newTestClient(map[string]interface{}{
+				"reward_plugins_dir": dir,
+				"reward_plugins_available": map[string]interface{}{
+					"foo": map[string]interface{}{"url": "https://example.com/reward-foo.tar.gz", "version": tt.remote},
+				},
+			})
+
+			script := fmt.Sprintf("#!/bin/sh\necho reward-foo version %s\n", tt.installed)
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "reward-foo"), []byte(script), 0o755))
+
+			got, err := c.pluginUpdatePlan(nil, "foo", tt.pinned)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.installed, got.Installed)
+			assert.Equal(t, tt.wantAvailable, got.Available)
+			assert.Equal(t, tt.wantAction, got.Action)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package logic
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrPrefetchFailed occurs when the images of some environments cannot be pulled.
+var ErrPrefetchFailed = func(dirs []string) error {
+	return fmt.Errorf("cannot pull the images of the environments: %s", strings.Join(dirs, ", "))
+}
+
+// composeWorkingDirLabel is the label of the containers which contains the project directory of the environment.
+const composeWorkingDirLabel = "com.docker.compose.project.working_dir"
+
+// RunCmdPrefetch pulls the missing and updated images of the common services and the environments which have
+// containers on the docker host, so the first env up isn't blocked by the downloads. It doesn't start anything, it can
+// be run from a login script.
+func (c *Client) RunCmdPrefetch(cmd *cmdpkg.Command) error {
+	if c.Offline() {
+		log.Println("Offline mode, skipping prefetching the images.")
+
+		return nil
+	}
+
+	delay, _ := cmd.Flags().GetDuration("delay")
+	if delay > 0 {
+		log.Printf("Waiting %s before pulling the images...", delay)
+		time.Sleep(delay)
+	}
+
+	var failed []string
+
+	if skip, _ := cmd.Flags().GetBool("skip-services"); !skip {
+		log.Println("Pulling the images of the common services...")
+
+		err := c.runSelfInDir(c.Cwd(), nil, "svc", "pull", "--quiet")
+		if err != nil {
+			log.Warnf("Cannot pull the images of the common services: %s", err)
+
+			failed = append(failed, "common services")
+		} else {
+			log.Println("...common service images pulled.")
+		}
+	}
+
+	dirs, err := c.prefetchEnvironmentDirs()
+	if err != nil {
+		return err
+	}
+
+	if len(dirs) == 0 {
+		log.Println("No environments found on the docker host.")
+	}
+
+	for _, dir := range dirs {
+		log.Printf("Pulling the images of the environment in %s...", dir)
+
+		err := c.runSelfInDir(dir, nil, "env", "pull", "--quiet")
+		if err != nil {
+			log.Warnf("Cannot pull the images of the environment in %s: %s", dir, err)
+
+			failed = append(failed, dir)
+
+			continue
+		}
+
+		log.Println("...environment images pulled.")
+	}
+
+	if len(failed) > 0 {
+		return ErrPrefetchFailed(failed)
+	}
+
+	return nil
+}
+
+// prefetchEnvironmentDirs returns the directories of the environments which have containers on the docker host. The
+// environments are registered by their containers, the directories which are removed since then are skipped.
+func (c *Client) prefetchEnvironmentDirs() ([]string, error) {
+	containers, err := c.Docker.ContainersByLabel(fmt.Sprintf("dev.%s.environment.name", c.AppName()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up environments: %w", err)
+	}
+
+	labels := make([]map[string]string, 0, len(containers))
+	for _, container := range containers {
+		labels = append(labels, container.Config.Labels)
+	}
+
+	var dirs []string
+
+	for _, dir := range environmentDirs(c.AppName(), labels) {
+		if !util.FileExists(filepath.Join(dir, ".env")) {
+			log.Debugf("Skipping %s, it's not an environment directory anymore.", dir)
+
+			continue
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs, nil
+}
+
+// environmentDirs returns the sorted, unique project directories from the labels of the environment containers. The
+// common services are labeled as the environment of the application, they're excluded.
+func environmentDirs(appName string, labels []map[string]string) []string {
+	seen := make(map[string]bool)
+
+	for _, l := range labels {
+		dir := l[composeWorkingDirLabel]
+		if dir == "" || l[fmt.Sprintf("dev.%s.environment.name", appName)] == appName {
+			continue
+		}
+
+		seen[dir] = true
+	}
+
+	dirs := make([]string, 0, len(seen))
+	for dir := range seen {
+		dirs = append(dirs, dir)
+	}
+
+	sort.Strings(dirs)
+
+	return dirs
+}
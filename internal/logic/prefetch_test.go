@@ -0,0 +1,61 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PrefetchTestSuite struct {
+	suite.Suite
+}
+
+func TestPrefetchTestSuite(t *testing.T) {
+	suite.Run(t, new(PrefetchTestSuite))
+}
+
+func (suite *PrefetchTestSuite) TestEnvironmentDirs() {
+	container := func(name, dir string) map[string]string {
+		return map[string]string{
+			"dev.reward.environment.name": name,
+			composeWorkingDirLabel:        dir,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		labels []map[string]string
+		want   []string
+	}{
+		{name: "no containers", want: []string{}},
+		{
+			name: "unique and sorted",
+			labels: []map[string]string{
+				container("shop", "/home/alice/shop"),
+				container("blog", "/home/alice/blog"),
+				container("shop", "/home/alice/shop"),
+			},
+			want: []string{"/home/alice/blog", "/home/alice/shop"},
+		},
+		{
+			name: "common services are excluded",
+			labels: []map[string]string{
+				container("reward", "/home/alice/.reward"),
+				container("shop", "/home/alice/shop"),
+			},
+			want: []string{"/home/alice/shop"},
+		},
+		{
+			name:   "containers without project directory",
+			labels: []map[string]string{container("shop", "")},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, environmentDirs("reward", tt.labels))
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package logic
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+const (
+	// proxyRoutePrefix is the prefix of the traefik dynamic configuration files (and the routers and services in them)
+	// of the proxy routes.
+	proxyRoutePrefix = "proxy-"
+	// proxyHostGateway is the host name of the docker host inside the traefik container.
+	proxyHostGateway = "host.docker.internal"
+)
+
+// ErrInvalidProxyDomain occurs when the domain of the proxy route is not a valid host name.
+var ErrInvalidProxyDomain = func(domain string) error {
+	return fmt.Errorf("invalid domain: %s", domain)
+}
+
+// ErrInvalidProxyTarget occurs when the target of the proxy route is not in host:port format.
+var ErrInvalidProxyTarget = func(target string) error {
+	return fmt.Errorf("invalid target: %s, it must be in host:port format (eg. localhost:3000)", target)
+}
+
+// ErrProxyRouteNotFound occurs when the proxy route of the domain doesn't exist.
+var ErrProxyRouteNotFound = func(domain string) error {
+	return fmt.Errorf("proxy route of %s doesn't exist", domain)
+}
+
+// proxyDomainRegex matches the valid host names.
+var proxyDomainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// proxyRoute is a route of traefik to an application running on the docker host.
+type proxyRoute struct {
+	Domain string `json:"domain"`
+	// URL is the address of the application as it's reachable from the traefik container.
+	URL string `json:"url"`
+}
+
+// proxyRouteConfig is the traefik dynamic configuration of a proxy route.
+type proxyRouteConfig struct {
+	HTTP struct {
+		Routers  map[string]proxyRouter  `yaml:"routers"`
+		Services map[string]proxyService `yaml:"services"`
+	} `yaml:"http"`
+}
+
+type proxyRouter struct {
+	Rule    string            `yaml:"rule"`
+	Service string            `yaml:"service"`
+	TLS     map[string]string `yaml:"tls"`
+}
+
+type proxyService struct {
+	LoadBalancer struct {
+		Servers []proxyServer `yaml:"servers"`
+	} `yaml:"loadBalancer"`
+}
+
+type proxyServer struct {
+	URL string `yaml:"url"`
+}
+
+// RunCmdProxyAdd registers a traefik route from the domain to an application running directly on the docker host,
+// so the dev servers which are not containerized are served with the same domain and TLS setup as the environments.
+// The certificate of the domain is signed by the local CA.
+func (c *Client) RunCmdProxyAdd(cmd *cmdpkg.Command, args []string) error {
+	domain := strings.ToLower(args[0])
+	if !proxyDomainRegex.MatchString(domain) {
+		return ErrInvalidProxyDomain(args[0])
+	}
+
+	to, _ := cmd.Flags().GetString("to")
+
+	url, err := proxyTargetURL(to)
+	if err != nil {
+		return err
+	}
+
+	bs, err := yaml.Marshal(newProxyRouteConfig(proxyRoute{Domain: domain, URL: url}))
+	if err != nil {
+		return fmt.Errorf("cannot marshal proxy route: %w", err)
+	}
+
+	err = c.ensureTraefikReachesHost()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Adding proxy route %s -> %s...", domain, to)
+
+	err = util.CreateDirAndWriteToFile(bs, c.proxyRouteFile(domain), 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot write proxy route: %w", err)
+	}
+
+	// signing the certificate reloads the dynamic configuration, so the route is loaded by the time it returns
+	err = c.RunCmdSignCertificate([]string{domain}, true)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("...proxy route added, %s is available on https://%s/", to, domain)
+
+	return nil
+}
+
+// RunCmdProxyRemove removes the traefik route of the domain. The certificate of the domain is kept.
+func (c *Client) RunCmdProxyRemove(args []string) error {
+	domain := strings.ToLower(args[0])
+
+	err := util.FS.Remove(c.proxyRouteFile(domain))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrProxyRouteNotFound(domain)
+		}
+
+		return fmt.Errorf("cannot remove proxy route: %w", err)
+	}
+
+	// the route is removed from traefik once it loads the changed dynamic configuration, a stopped traefik loads it
+	// when it's started
+	if traefik, err := c.Docker.ServiceContainer(c.AppName(), "traefik"); err == nil && traefik.Running() {
+		err = c.reloadTraefikDynamicConfig()
+		if err != nil {
+			return fmt.Errorf("cannot reload traefik: %w", err)
+		}
+	}
+
+	log.Printf("Proxy route of %s removed.", domain)
+
+	return nil
+}
+
+// RunCmdProxyList prints the proxy routes.
+func (c *Client) RunCmdProxyList() error {
+	routes, err := c.proxyRoutes()
+	if err != nil {
+		return err
+	}
+
+	if c.JSONOutput() {
+		return c.printJSON(routes)
+	}
+
+	if len(routes) == 0 {
+		log.Println("No proxy routes found.")
+
+		return nil
+	}
+
+	for _, route := range routes {
+		fmt.Printf("https://%s -> %s\n", route.Domain, route.URL)
+	}
+
+	return nil
+}
+
+// proxyRoutes returns the proxy routes from the traefik dynamic configuration directory, sorted by domain.
+func (c *Client) proxyRoutes() ([]proxyRoute, error) {
+	files, err := afero.Glob(util.FS, filepath.Join(c.proxyRouteDir(), proxyRoutePrefix+"*.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list proxy routes: %w", err)
+	}
+
+	routes := make([]proxyRoute, 0, len(files))
+
+	for _, file := range files {
+		bs, err := util.FS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read proxy route: %w", err)
+		}
+
+		var conf proxyRouteConfig
+
+		err = yaml.Unmarshal(bs, &conf)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse proxy route %s: %w", file, err)
+		}
+
+		domain := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), proxyRoutePrefix), ".yml")
+		route := proxyRoute{Domain: domain}
+
+		if service, ok := conf.HTTP.Services[proxyRouteName(domain)]; ok && len(service.LoadBalancer.Servers) > 0 {
+			route.URL = service.LoadBalancer.Servers[0].URL
+		}
+
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Domain < routes[j].Domain })
+
+	return routes, nil
+}
+
+// ensureTraefikReachesHost recreates traefik if it was created before the docker host was mapped in the container.
+func (c *Client) ensureTraefikReachesHost() error {
+	traefik, err := c.Docker.ServiceContainer(c.AppName(), "traefik")
+	if err != nil || !traefik.Running() {
+		// it's brought up when the dynamic configuration is reloaded
+		return nil //nolint:nilerr
+	}
+
+	for _, host := range traefik.ExtraHosts {
+		if strings.HasPrefix(host, proxyHostGateway+":") {
+			return nil
+		}
+	}
+
+	log.Println("Traefik cannot reach the docker host, recreating it...")
+
+	err = c.RunCmdSvc([]string{"up", "traefik"})
+	if err != nil {
+		return fmt.Errorf("cannot recreate traefik: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) proxyRouteDir() string {
+	return filepath.Join(c.AppHomeDir(), "etc/traefik/dynamic")
+}
+
+func (c *Client) proxyRouteFile(domain string) string {
+	return filepath.Join(c.proxyRouteDir(), proxyRoutePrefix+domain+".yml")
+}
+
+// proxyRouteName returns the name of the traefik router and service of the domain.
+func proxyRouteName(domain string) string {
+	return proxyRoutePrefix + strings.ReplaceAll(domain, ".", "-")
+}
+
+// newProxyRouteConfig returns the traefik dynamic configuration of the route. The route is served on https only,
+// the http requests are redirected by the http entrypoint.
+func newProxyRouteConfig(route proxyRoute) proxyRouteConfig {
+	var (
+		conf    proxyRouteConfig
+		name    = proxyRouteName(route.Domain)
+		service proxyService
+	)
+
+	service.LoadBalancer.Servers = []proxyServer{{URL: route.URL}}
+
+	conf.HTTP.Routers = map[string]proxyRouter{
+		name: {Rule: fmt.Sprintf("Host(`%s`)", route.Domain), Service: name, TLS: map[string]string{}},
+	}
+	conf.HTTP.Services = map[string]proxyService{name: service}
+
+	return conf
+}
+
+// proxyTargetURL returns the URL of the target (in host:port format) as it's reachable from the traefik container.
+// The loopback addresses of the docker host are replaced by the host gateway of the container.
+func proxyTargetURL(target string) (string, error) {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return "", ErrInvalidProxyTarget(target)
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return "", ErrInvalidProxyTarget(target)
+	}
+
+	if ip := net.ParseIP(host); host == "" || host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		host = proxyHostGateway
+	}
+
+	return fmt.Sprintf("http://%s", net.JoinHostPort(host, port)), nil
+}
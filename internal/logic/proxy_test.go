@@ -0,0 +1,108 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type ProxyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ProxyTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestProxyTestSuite(t *testing.T) {
+	suite.Run(t, new(ProxyTestSuite))
+}
+
+func (suite *ProxyTestSuite) TestProxyTargetURL() {
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{target: "localhost:3000", want: "http://host.docker.internal:3000"},
+		{target: "127.0.0.1:8080", want: "http://host.docker.internal:8080"},
+		{target: "[::1]:8080", want: "http://host.docker.internal:8080"},
+		{target: ":5173", want: "http://host.docker.internal:5173"},
+		{target: "192.168.1.10:3000", want: "http://192.168.1.10:3000"},
+		{target: "localhost", wantErr: true},
+		{target: "localhost:http", wantErr: true},
+		{target: "localhost:70000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.target, func(t *testing.T) {
+			got, err := proxyTargetURL(tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *ProxyTestSuite) TestProxyRoutes() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/user/.reward"})
+	c.Docker = docker.NewClientWithAPI(&docker.Fake{})
+
+	for _, route := range []proxyRoute{
+		{Domain: "web.app.test", URL: "http://host.docker.internal:3000"},
+		{Domain: "api.app.test", URL: "http://host.docker.internal:8080"},
+	} {
+		bs, err := yaml.Marshal(newProxyRouteConfig(route))
+		assert.NoError(suite.T(), err)
+		assert.NoError(suite.T(), util.CreateDirAndWriteToFile(bs, c.proxyRouteFile(route.Domain)))
+	}
+
+	// the configuration of the common services is not a proxy route
+	assert.NoError(suite.T(), util.CreateDirAndWriteToFile(
+		[]byte("tls: {}\n"), "/home/user/.reward/etc/traefik/dynamic/reward.yml",
+	))
+
+	routes, err := c.proxyRoutes()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []proxyRoute{
+		{Domain: "api.app.test", URL: "http://host.docker.internal:8080"},
+		{Domain: "web.app.test", URL: "http://host.docker.internal:3000"},
+	}, routes)
+
+	assert.NoError(suite.T(), c.RunCmdProxyRemove([]string{"api.app.test"}))
+	assert.Error(suite.T(), c.RunCmdProxyRemove([]string{"api.app.test"}))
+
+	routes, err = c.proxyRoutes()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), routes, 1)
+}
+
+func (suite *ProxyTestSuite) TestNewProxyRouteConfig() {
+	bs, err := yaml.Marshal(newProxyRouteConfig(proxyRoute{Domain: "app.test", URL: "http://host.docker.internal:3000"}))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), `http:
+    routers:
+        proxy-app-test:
+            rule: Host(`+"`app.test`"+`)
+            service: proxy-app-test
+            tls: {}
+    services:
+        proxy-app-test:
+            loadBalancer:
+                servers:
+                    - url: http://host.docker.internal:3000
+`, string(bs))
+}
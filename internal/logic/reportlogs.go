@@ -0,0 +1,286 @@
+package logic
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrReportLogsNoServices occurs when none of the services of the report are running.
+var ErrReportLogsNoServices = fmt.Errorf("no running services found, start the environment using `reward env up`")
+
+// reportLogsRedacted replaces the secret values in the report.
+const reportLogsRedacted = "********"
+
+var (
+	// reportLogsEnvLineRegex matches the KEY=value lines of the .env file.
+	reportLogsEnvLineRegex = regexp.MustCompile(`^(\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*)(.*)$`)
+	// reportLogsSecretKeyRegex matches the keys of the .env file which contain secrets.
+	reportLogsSecretKeyRegex = regexp.MustCompile(`(?i)(PASS|SECRET|TOKEN|KEY|DSN|CREDENTIAL|AUTH|(DB|DATABASE)_URL)`)
+)
+
+// reportLogsFile is a file of the report.
+type reportLogsFile struct {
+	name    string
+	content []byte
+}
+
+// RunCmdReportLogs collects the recent logs of the services and the configuration of the environment into a
+// tar.gz archive, or prints them as markdown text which can be pasted into an issue or a gist. The values of the
+// secret settings are redacted in the configuration and in the logs.
+func (c *Client) RunCmdReportLogs(cmd *cmdpkg.Command, services []string) error {
+	var (
+		since, _  = cmd.Flags().GetDuration("since")
+		output, _ = cmd.Flags().GetString("output")
+		text, _   = cmd.Flags().GetBool("text")
+	)
+
+	if len(services) == 0 {
+		var err error
+
+		services, err = c.reportLogsRunningServices()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(services) == 0 {
+		return ErrReportLogsNoServices
+	}
+
+	envFile, err := util.FS.ReadFile(filepath.Join(c.Cwd(), ".env"))
+	if err != nil {
+		return fmt.Errorf("cannot read .env file: %w", err)
+	}
+
+	config, redacted := redactEnvFile(string(envFile))
+
+	// The ${env:NAME} references are resolved to their values by the time they get into the logs.
+	secrets := make([]string, 0, len(redacted)*2)
+	for key, value := range redacted {
+		secrets = append(secrets, value, c.GetString(strings.ToLower(key)))
+	}
+
+	files := []reportLogsFile{
+		{name: "environment.txt", content: []byte(c.reportLogsSummary(services, since))},
+		{name: "env.txt", content: []byte(config)},
+	}
+
+	for _, service := range services {
+		logs, err := c.reportLogsServiceLogs(service, since)
+		if err != nil {
+			log.Warnf("Cannot collect the logs of %s: %s", service, err)
+
+			continue
+		}
+
+		files = append(files, reportLogsFile{
+			name:    filepath.Join("logs", service+".log"),
+			content: []byte(redactSecrets(logs, secrets)),
+		})
+	}
+
+	if text {
+		return c.writeReportLogsText(files, output)
+	}
+
+	if output == "" {
+		output = fmt.Sprintf("%s-logs-%s.tar.gz", c.EnvName(), time.Now().Format("20060102150405"))
+	}
+
+	err = writeReportLogsArchive(files, output, c.EnvName())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Logs of %s are saved to %s. Check the archive for sensitive data before sharing it.",
+		strings.Join(services, ", "), output)
+
+	return nil
+}
+
+// reportLogsRunningServices returns the sorted names of the running services of the environment.
+func (c *Client) reportLogsRunningServices() ([]string, error) {
+	containers, err := c.Docker.RunningContainersByLabels("com.docker.compose.project=" + c.EnvName())
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up the services: %w", err)
+	}
+
+	services := make([]string, 0, len(containers))
+
+	for _, container := range containers {
+		if service := container.Labels["com.docker.compose.service"]; service != "" {
+			services = append(services, service)
+		}
+	}
+
+	sort.Strings(services)
+
+	return services, nil
+}
+
+// reportLogsServiceLogs returns the logs of the service newer than since, with timestamps.
+func (c *Client) reportLogsServiceLogs(service string, since time.Duration) (string, error) {
+	container, err := c.Docker.EnvServiceContainer(service)
+	if err != nil {
+		return "", fmt.Errorf("cannot find the container: %w", err)
+	}
+
+	out, err := cmdpkg.Cmnd("docker", "logs", "--timestamps", "--since", since.String(), container.ID).
+		CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("cannot read the logs: %w: %s", err, out)
+	}
+
+	return string(out), nil
+}
+
+// reportLogsSummary returns the versions and the settings which are needed to reproduce the environment.
+func (c *Client) reportLogsSummary(services []string, since time.Duration) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Generated: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Logs since: %s\n", since)
+	fmt.Fprintf(&b, "Reward version: %s\n", c.AppVersion())
+	fmt.Fprintf(&b, "OS: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Environment name: %s\n", c.EnvName())
+	fmt.Fprintf(&b, "Environment type: %s\n", c.EnvType())
+	fmt.Fprintf(&b, "Services: %s\n", strings.Join(services, ", "))
+
+	if out, err := cmdpkg.Cmnd("docker", "version", "--format", "{{ .Server.Version }}").Output(); err == nil {
+		fmt.Fprintf(&b, "Docker version: %s\n", strings.TrimSpace(string(out)))
+	}
+
+	return b.String()
+}
+
+// writeReportLogsText writes the files of the report as markdown sections to the output, or to stdout if the output
+// is not set.
+func (c *Client) writeReportLogsText(files []reportLogsFile, output string) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Logs of %s\n", c.EnvName())
+
+	for _, f := range files {
+		fmt.Fprintf(&b, "\n### %s\n\n```\n%s", f.name, f.content)
+
+		if !bytes.HasSuffix(f.content, []byte("\n")) {
+			b.WriteString("\n")
+		}
+
+		b.WriteString("```\n")
+	}
+
+	if output == "" {
+		fmt.Print(b.String())
+
+		return nil
+	}
+
+	err := util.CreateDirAndWriteToFile([]byte(b.String()), output, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", output, err)
+	}
+
+	log.Printf("Logs are saved to %s. Check the file for sensitive data before sharing it.", output)
+
+	return nil
+}
+
+// writeReportLogsArchive writes the files of the report to a tar.gz archive in the dir directory.
+func writeReportLogsArchive(files []reportLogsFile, output, dir string) error {
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", output, err)
+	}
+	defer f.Close()
+
+	gz, err := util.CompressWriter(f, "gzip")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	for _, file := range files {
+		err = tw.WriteHeader(&tar.Header{
+			Name:    filepath.ToSlash(filepath.Join(dir, file.name)),
+			Mode:    0o600,
+			Size:    int64(len(file.content)),
+			ModTime: now,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot write %s: %w", output, err)
+		}
+
+		_, err = tw.Write(file.content)
+		if err != nil {
+			return fmt.Errorf("cannot write %s: %w", output, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", output, err)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", output, err)
+	}
+
+	return nil
+}
+
+// redactEnvFile returns the .env file with the values of the secret settings redacted, and the redacted values by
+// their keys.
+func redactEnvFile(content string) (string, map[string]string) {
+	var (
+		lines   = strings.SplitAfter(content, "\n")
+		secrets = make(map[string]string)
+	)
+
+	for i, line := range lines {
+		m := reportLogsEnvLineRegex.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if m == nil || !reportLogsSecretKeyRegex.MatchString(m[2]) {
+			continue
+		}
+
+		value := strings.Trim(strings.TrimSpace(m[3]), `"'`)
+		if value == "" {
+			continue
+		}
+
+		secrets[m[2]] = value
+		lines[i] = m[1] + reportLogsRedacted + line[len(strings.TrimRight(line, "\r\n")):]
+	}
+
+	return strings.Join(lines, ""), secrets
+}
+
+// redactSecrets replaces the secret values in the text. The short values are not replaced, they would match
+// unrelated parts of the text.
+func redactSecrets(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) < 4 {
+			continue
+		}
+
+		text = strings.ReplaceAll(text, secret, reportLogsRedacted)
+	}
+
+	return text
+}
@@ -0,0 +1,43 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactEnvFile(t *testing.T) {
+	content := `REWARD_ENV_NAME=shop
+REWARD_ENV_TYPE=magento2
+MYSQL_PASSWORD=app
+NEWRELIC_LICENSE_KEY="0123456789abcdef"
+BLACKFIRE_CLIENT_TOKEN=
+ORO_DB_URL=postgres://oro:oro@db:5432/oro
+APP_URL=https://shop.test
+`
+
+	got, secrets := redactEnvFile(content)
+
+	assert.Equal(t, `REWARD_ENV_NAME=shop
+REWARD_ENV_TYPE=magento2
+MYSQL_PASSWORD=********
+NEWRELIC_LICENSE_KEY=********
+BLACKFIRE_CLIENT_TOKEN=
+ORO_DB_URL=********
+APP_URL=https://shop.test
+`, got)
+	assert.Equal(t, map[string]string{
+		"MYSQL_PASSWORD":       "app",
+		"NEWRELIC_LICENSE_KEY": "0123456789abcdef",
+		"ORO_DB_URL":           "postgres://oro:oro@db:5432/oro",
+	}, secrets)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	got := redactSecrets(
+		"connecting to app with key 0123456789abcdef",
+		[]string{"app", "0123456789abcdef", ""},
+	)
+
+	assert.Equal(t, "connecting to app with key ********", got)
+}
@@ -0,0 +1,121 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+const (
+	// memoryPressureRatio is the ratio of the memory of Docker used by the containers above which a warning is shown.
+	memoryPressureRatio = 0.9
+	// diskPressureRatio is the ratio of the disk of Docker used above which a warning is shown.
+	diskPressureRatio = 0.9
+)
+
+// warnResourcePressure warns if the containers of the project (or any environment if project is empty) were killed
+// by the OOM killer, or if Docker is low on memory or disk space. The checks are best effort, they don't fail the
+// command.
+func (c *Client) warnResourcePressure(project string) {
+	containers, err := c.Docker.ContainerDetailsByLabel(c.LabelEnvName())
+	if err != nil {
+		log.Debugf("Cannot get containers: %s", err)
+
+		return
+	}
+
+	memoryTotal, err := c.Docker.MemoryTotal()
+	if err != nil {
+		log.Debugf("Cannot get the memory of Docker: %s", err)
+	}
+
+	var (
+		memoryUsed uint64
+		disk       *docker.DiskUsage
+		oomKilled  = make([]*docker.Container, 0)
+	)
+
+	for _, container := range containers {
+		if container.OOMKilled && (project == "" || container.Project == project) {
+			oomKilled = append(oomKilled, container)
+		}
+
+		if !container.Running() {
+			continue
+		}
+
+		usage, err := c.Docker.MemoryUsage(container.ID)
+		if err != nil {
+			log.Debugf("Cannot get memory usage of container %s: %s", container.Name, err)
+		}
+
+		memoryUsed += usage
+
+		if disk == nil {
+			disk, err = c.Docker.DiskUsage(container.ID)
+			if err != nil {
+				log.Debugf("Cannot get disk usage in container %s: %s", container.Name, err)
+			}
+		}
+	}
+
+	warnings := append(c.oomWarnings(oomKilled, memoryTotal), pressureWarnings(memoryTotal, memoryUsed, disk)...)
+	for _, warning := range warnings {
+		log.Warnln(warning)
+	}
+}
+
+// oomWarnings returns a warning for each container which was killed by the OOM killer with its memory limit and a
+// suggestion how to raise it.
+func (c *Client) oomWarnings(containers []*docker.Container, memoryTotal int64) []string {
+	warnings := make([]string, 0, len(containers))
+
+	for _, container := range containers {
+		limit := fmt.Sprintf("none, Docker has %s", units.BytesSize(float64(memoryTotal)))
+		if container.MemoryLimit > 0 {
+			limit = units.BytesSize(float64(container.MemoryLimit))
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"Service %s of environment %s ran out of memory (limit: %s). Raise the limit by setting %s (eg. %s=2g "+
+				"in the .env file) or the memory of Docker Desktop (Settings > Resources).",
+			container.Service,
+			container.Project,
+			limit,
+			c.ServiceMemoryLimitKey(container.Service),
+			strings.ToUpper(c.ServiceMemoryLimitKey(container.Service)),
+		))
+	}
+
+	return warnings
+}
+
+// pressureWarnings returns the warnings if the containers use most of the memory of Docker or the disk of Docker is
+// almost full.
+func pressureWarnings(memoryTotal int64, memoryUsed uint64, disk *docker.DiskUsage) []string {
+	warnings := make([]string, 0)
+
+	if memoryTotal > 0 && float64(memoryUsed) > memoryPressureRatio*float64(memoryTotal) {
+		warnings = append(warnings, fmt.Sprintf(
+			"Docker is low on memory: the containers use %s of %s. Stop the unused environments or raise the "+
+				"memory of Docker Desktop (Settings > Resources).",
+			units.BytesSize(float64(memoryUsed)),
+			units.BytesSize(float64(memoryTotal)),
+		))
+	}
+
+	if disk != nil && disk.Total > 0 && float64(disk.Total-disk.Available) > diskPressureRatio*float64(disk.Total) {
+		warnings = append(warnings, fmt.Sprintf(
+			"Docker is low on disk space: %s of %s is available. Remove the unused images and volumes "+
+				"(eg. docker system prune) or raise the disk size of Docker Desktop (Settings > Resources).",
+			units.BytesSize(float64(disk.Available)),
+			units.BytesSize(float64(disk.Total)),
+		))
+	}
+
+	return warnings
+}
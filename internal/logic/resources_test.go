@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type ResourcesTestSuite struct {
+	suite.Suite
+}
+
+func TestResourcesTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourcesTestSuite))
+}
+
+func (suite *ResourcesTestSuite) TestOOMWarnings() {
+	c := newTestClient(nil)
+
+	got := c.oomWarnings([]*docker.Container{
+		{Project: "shop", Service: "php-fpm", OOMKilled: true, MemoryLimit: 2 * 1024 * 1024 * 1024},
+		{Project: "shop", Service: "elasticsearch", OOMKilled: true},
+	}, 4*1024*1024*1024)
+
+	assert.Equal(suite.T(), []string{
+		"Service php-fpm of environment shop ran out of memory (limit: 2GiB). Raise the limit by setting " +
+			"reward_php_fpm_memory (eg. REWARD_PHP_FPM_MEMORY=2g in the .env file) or the memory of Docker Desktop " +
+			"(Settings > Resources).",
+		"Service elasticsearch of environment shop ran out of memory (limit: none, Docker has 4GiB). Raise the limit " +
+			"by setting reward_elasticsearch_memory (eg. REWARD_ELASTICSEARCH_MEMORY=2g in the .env file) or the " +
+			"memory of Docker Desktop (Settings > Resources).",
+	}, got)
+}
+
+func (suite *ResourcesTestSuite) TestPressureWarnings() {
+	const gib = 1024 * 1024 * 1024
+
+	tests := []struct {
+		name        string
+		memoryTotal int64
+		memoryUsed  uint64
+		disk        *docker.DiskUsage
+		want        int
+	}{
+		{
+			name:        "no pressure",
+			memoryTotal: 8 * gib,
+			memoryUsed:  2 * gib,
+			disk:        &docker.DiskUsage{Total: 100 * gib, Available: 50 * gib},
+		},
+		{name: "unknown resources", memoryUsed: 2 * gib},
+		{name: "low memory", memoryTotal: 8 * gib, memoryUsed: 7.5 * gib, want: 1},
+		{name: "low disk", memoryTotal: 8 * gib, disk: &docker.DiskUsage{Total: 100 * gib, Available: 5 * gib}, want: 1},
+		{
+			name:        "both",
+			memoryTotal: 8 * gib,
+			memoryUsed:  8 * gib,
+			disk:        &docker.DiskUsage{Total: 100 * gib, Available: 0},
+			want:        2,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Len(t, pressureWarnings(tt.memoryTotal, tt.memoryUsed, tt.disk), tt.want)
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+const (
+	// searchSnapshotRepository is the name of the snapshot repository of the migration.
+	searchSnapshotRepository = "reward-migrate"
+	// searchReadyTimeout is the time the search engine has to become ready.
+	searchReadyTimeout = 5 * time.Minute
+	// openSearchRestorableVersions is the constraint of the Elasticsearch versions whose snapshots can be restored by
+	// OpenSearch. The indices of the later versions are reindexed from Elasticsearch.
+	openSearchRestorableVersions = "< 7.11"
+)
+
+// ErrElasticsearchNotEnabled occurs when the search migration is started in an environment without Elasticsearch.
+var ErrElasticsearchNotEnabled = func(appName string) error {
+	return fmt.Errorf("elasticsearch is not enabled, set %s_ELASTICSEARCH=true in the .env file",
+		strings.ToUpper(appName))
+}
+
+// ErrSearchRequestFailed occurs when the search engine responds to a request with an error.
+var ErrSearchRequestFailed = func(service, request string, status int, response []byte) error {
+	return fmt.Errorf("%s %s failed with status %d: %s", service, request, status, strings.TrimSpace(string(response)))
+}
+
+// ErrSearchNotReady occurs when the search engine doesn't become ready in time.
+var ErrSearchNotReady = func(service string, timeout time.Duration) error {
+	return fmt.Errorf("%s didn't become ready in %s", service, timeout)
+}
+
+// searchIndexSettings are the settings of the Elasticsearch indices which are copied to the OpenSearch indices when
+// they are reindexed. The other settings (eg. uuid, version, creation_date) are managed by the search engine.
+var searchIndexSettings = []string{
+	"analysis", "number_of_shards", "number_of_replicas", "max_result_window", "mapping", "refresh_interval",
+}
+
+// RunCmdSearchMigrate migrates the indices of Elasticsearch to OpenSearch and switches the environment to OpenSearch.
+// The indices are snapshotted first, the snapshot is restored by OpenSearch if Elasticsearch is older than 7.11,
+// otherwise the indices are reindexed from Elasticsearch with their mappings and settings. The Elasticsearch volume
+// (including the snapshot) is kept, so the migration can be reverted.
+func (c *Client) RunCmdSearchMigrate() error {
+	if !c.ServiceEnabled("elasticsearch") {
+		return ErrElasticsearchNotEnabled(c.AppName())
+	}
+
+	if !util.AskForConfirmation(fmt.Sprintf(
+		"The indices of Elasticsearch are going to be migrated to OpenSearch in %s. Continue?", c.EnvName()),
+	) {
+		return nil
+	}
+
+	var (
+		snapshot = "migrate-" + time.Now().Format("20060102150405")
+		steps    = c.newProgress()
+	)
+
+	steps.SetTotalSteps(4)
+
+	step := steps.OutputStep("Creating a snapshot of the Elasticsearch indices")
+
+	esVersion, indices, err := c.searchSnapshot(snapshot)
+	if step.Finish(err) != nil {
+		return fmt.Errorf("cannot create a snapshot of the elasticsearch indices: %w", err)
+	}
+
+	err = steps.OutputStep("Starting OpenSearch").Finish(c.searchStartOpenSearch())
+	if err != nil {
+		return fmt.Errorf("cannot start opensearch: %w", err)
+	}
+
+	if searchRestorable(esVersion) {
+		err = steps.OutputStep("Restoring the indices").Finish(c.searchRestore(snapshot, indices))
+	} else {
+		err = steps.Step("Reindexing the indices").Finish(c.searchReindex(indices))
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot migrate the indices, elasticsearch is kept running: %w", err)
+	}
+
+	err = steps.OutputStep("Switching the environment to OpenSearch").Finish(c.searchSwitchToOpenSearch())
+	if err != nil {
+		return fmt.Errorf("cannot switch the environment to opensearch: %w", err)
+	}
+
+	log.Printf("...%d indices migrated to OpenSearch. The Elasticsearch data and the %s snapshot are kept in the "+
+		"%s_esdata volume, remove it if it's not needed anymore.", len(indices), snapshot, c.EnvName())
+
+	return nil
+}
+
+// searchSnapshot creates a snapshot of the Elasticsearch indices in its data directory. It returns the version of
+// Elasticsearch and the snapshotted indices.
+func (c *Client) searchSnapshot(snapshot string) (*version.Version, []string, error) {
+	// recreate elasticsearch if it was started without the path.repo setting
+	err := c.runSelfInDir(c.Cwd(), nil, "env", "up", "-d", "elasticsearch")
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot start elasticsearch: %w", err)
+	}
+
+	err = c.waitForSearch("elasticsearch")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, err := c.searchRequest("elasticsearch", "GET", "/", "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+
+	err = json.Unmarshal(out, &info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse elasticsearch version: %w", err)
+	}
+
+	esVersion, err := version.NewVersion(info.Version.Number)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse elasticsearch version: %w", err)
+	}
+
+	out, err = c.searchRequest("elasticsearch", "GET", "/_cat/indices?format=json&h=index", "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indices, err := searchIndices(out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("Found %d indices in Elasticsearch %s.", len(indices), esVersion)
+
+	if len(indices) == 0 {
+		return esVersion, indices, nil
+	}
+
+	_, err = c.searchRequest("elasticsearch", "PUT", "/_snapshot/"+searchSnapshotRepository, fmt.Sprintf(
+		`{"type":"fs","settings":{"location":"/usr/share/elasticsearch/data/snapshots/%s"}}`, searchSnapshotRepository,
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = c.searchRequest("elasticsearch", "PUT",
+		fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=true", searchSnapshotRepository, snapshot),
+		fmt.Sprintf(`{"indices":%q,"include_global_state":false}`, strings.Join(indices, ",")),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return esVersion, indices, nil
+}
+
+// searchStartOpenSearch enables OpenSearch in the .env file and starts it next to Elasticsearch.
+func (c *Client) searchStartOpenSearch() error {
+	err := setEnvFileValues(
+		filepath.Join(c.Cwd(), ".env"), [][2]string{{strings.ToUpper(c.AppName()) + "_OPENSEARCH", "true"}},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.runSelfInDir(c.Cwd(), nil, "env", "up", "-d", "opensearch")
+	if err != nil {
+		return fmt.Errorf("cannot start opensearch: %w", err)
+	}
+
+	return c.waitForSearch("opensearch")
+}
+
+// searchRestore copies the snapshot to the data directory of OpenSearch and restores the indices.
+func (c *Client) searchRestore(snapshot string, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+
+	copyCmd := cmdpkg.Cmnd("docker", "run", "--rm",
+		"-v", c.EnvName()+"_esdata:/from:ro",
+		"-v", c.EnvName()+"_osdata:/to",
+		"alpine", "sh", "-c", "mkdir -p /to/snapshots && cp -a /from/snapshots/. /to/snapshots/",
+	)
+	copyCmd.Stderr = os.Stderr
+
+	err := copyCmd.Run()
+	if err != nil {
+		return fmt.Errorf("cannot copy the snapshot: %w", err)
+	}
+
+	_, err = c.searchRequest("opensearch", "PUT", "/_snapshot/"+searchSnapshotRepository, fmt.Sprintf(
+		`{"type":"fs","settings":{"location":"/usr/share/opensearch/data/snapshots/%s","readonly":true}}`,
+		searchSnapshotRepository,
+	))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.searchRequest("opensearch", "POST",
+		fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", searchSnapshotRepository, snapshot),
+		fmt.Sprintf(`{"indices":%q,"include_global_state":false,"include_aliases":true}`, strings.Join(indices, ",")),
+	)
+
+	return err
+}
+
+// searchReindex creates the indices in OpenSearch with the mappings, settings and aliases of the Elasticsearch
+// indices and reindexes their documents from Elasticsearch.
+func (c *Client) searchReindex(indices []string) error {
+	for _, index := range indices {
+		log.Debugf("Reindexing %s...", index)
+
+		out, err := c.searchRequest("elasticsearch", "GET", "/"+index, "")
+		if err != nil {
+			return err
+		}
+
+		definition, err := searchIndexDefinition(out, index)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.searchRequest("opensearch", "PUT", "/"+index, string(definition))
+		if err != nil {
+			return fmt.Errorf("cannot create index %s: %w", index, err)
+		}
+
+		_, err = c.searchRequest("opensearch", "POST", "/_reindex?wait_for_completion=true", fmt.Sprintf(
+			`{"source":{"remote":{"host":"http://elasticsearch:9200"},"index":%q},"dest":{"index":%q}}`, index, index,
+		))
+		if err != nil {
+			return fmt.Errorf("cannot reindex %s: %w", index, err)
+		}
+
+		log.Debugf("...%s reindexed.", index)
+	}
+
+	return nil
+}
+
+// searchSwitchToOpenSearch removes the Elasticsearch container, disables Elasticsearch in the .env file and points
+// Magento to OpenSearch.
+func (c *Client) searchSwitchToOpenSearch() error {
+	err := c.runSelfInDir(c.Cwd(), nil, "env", "rm", "--force", "--stop", "elasticsearch")
+	if err != nil {
+		return fmt.Errorf("cannot remove elasticsearch: %w", err)
+	}
+
+	err = setEnvFileValues(
+		filepath.Join(c.Cwd(), ".env"),
+		[][2]string{{strings.ToUpper(c.AppName()) + "_ELASTICSEARCH", "false"}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if c.EnvType() == "magento2" {
+		// the search engine is configured as elasticsearch7 for opensearch too (see buildMagentoSearchHost)
+		err = c.RunCmdEnvExec("bin/magento config:set --lock-env catalog/search/elasticsearch7_server_hostname opensearch")
+		if err != nil {
+			log.Warnf("Cannot point Magento to OpenSearch, set catalog/search/elasticsearch7_server_hostname "+
+				"to opensearch manually: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// searchRequest sends a request to the API of the search engine service from its container.
+func (c *Client) searchRequest(service, method, path, body string) ([]byte, error) {
+	container, err := c.Docker.RunningEnvServiceContainer(service)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find %s container: %w", service, err)
+	}
+
+	// the status code is appended to the response, so the error responses can be reported with their body
+	args := []string{
+		"exec", container.ID, "curl", "-sS", "-w", `\n%{http_code}`, "-X", method, "-H", "Content-Type: application/json",
+		"localhost:9200" + path,
+	}
+	if body != "" {
+		args = append(args, "-d", body)
+	}
+
+	out, err := cmdpkg.Cmnd("docker", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s failed: %w", service, method, path, err)
+	}
+
+	response, status := searchResponse(out)
+	if status >= http.StatusBadRequest || status == 0 {
+		return nil, ErrSearchRequestFailed(service, method+" "+path, status, response)
+	}
+
+	return response, nil
+}
+
+// searchResponse splits the output of curl to the response and the status code appended to it.
+func searchResponse(out []byte) ([]byte, int) {
+	i := bytes.LastIndexByte(out, '\n')
+	if i < 0 {
+		return nil, 0
+	}
+
+	status, _ := strconv.Atoi(strings.TrimSpace(string(out[i+1:])))
+
+	return out[:i], status
+}
+
+// waitForSearch polls the search engine until its cluster status is yellow or green.
+func (c *Client) waitForSearch(service string) error {
+	start := time.Now()
+
+	for {
+		_, err := c.searchRequest(service, "GET", "/_cluster/health?wait_for_status=yellow&timeout=5s", "")
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(start) > searchReadyTimeout {
+			return ErrSearchNotReady(service, searchReadyTimeout)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// searchRestorable returns true if the snapshots of the Elasticsearch version can be restored by OpenSearch.
+func searchRestorable(esVersion *version.Version) bool {
+	return version.MustConstraints(version.NewConstraint(openSearchRestorableVersions)).Check(esVersion)
+}
+
+// searchIndices returns the sorted names of the indices from the output of _cat/indices. The system indices (eg.
+// .geoip_databases) are excluded.
+func searchIndices(catIndices []byte) ([]string, error) {
+	var rows []struct {
+		Index string `json:"index"`
+	}
+
+	err := json.Unmarshal(catIndices, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse indices: %w", err)
+	}
+
+	indices := make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		if strings.HasPrefix(row.Index, ".") {
+			continue
+		}
+
+		indices = append(indices, row.Index)
+	}
+
+	sort.Strings(indices)
+
+	return indices, nil
+}
+
+// searchIndexDefinition returns the body which creates the index with the mappings, aliases and the copyable
+// settings of the index from the output of GET /<index>.
+func searchIndexDefinition(raw []byte, index string) ([]byte, error) {
+	var indices map[string]struct {
+		Aliases  map[string]interface{} `json:"aliases"`
+		Mappings map[string]interface{} `json:"mappings"`
+		Settings struct {
+			Index map[string]interface{} `json:"index"`
+		} `json:"settings"`
+	}
+
+	err := json.Unmarshal(raw, &indices)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse index %s: %w", index, err)
+	}
+
+	source, ok := indices[index]
+	if !ok {
+		return nil, fmt.Errorf("cannot parse index %s: %w", index, os.ErrNotExist)
+	}
+
+	settings := make(map[string]interface{})
+
+	for _, key := range searchIndexSettings {
+		if value, ok := source.Settings.Index[key]; ok {
+			settings[key] = value
+		}
+	}
+
+	definition, err := json.Marshal(map[string]interface{}{
+		"aliases":  source.Aliases,
+		"mappings": source.Mappings,
+		"settings": map[string]interface{}{"index": settings},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal index %s: %w", index, err)
+	}
+
+	return definition, nil
+}
@@ -0,0 +1,74 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchTestSuite struct {
+	suite.Suite
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
+
+func (suite *SearchTestSuite) TestSearchRestorable() {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "6.8.23", want: true},
+		{version: "7.10.2", want: true},
+		{version: "7.11.0", want: false},
+		{version: "7.17.9", want: false},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchRestorable(version.Must(version.NewVersion(tt.version))))
+		})
+	}
+}
+
+func (suite *SearchTestSuite) TestSearchResponse() {
+	response, status := searchResponse([]byte("{\"acknowledged\":true}\n200"))
+	assert.Equal(suite.T(), `{"acknowledged":true}`, string(response))
+	assert.Equal(suite.T(), 200, status)
+
+	_, status = searchResponse([]byte("curl: (7) Failed to connect"))
+	assert.Equal(suite.T(), 0, status)
+}
+
+func (suite *SearchTestSuite) TestSearchIndices() {
+	indices, err := searchIndices([]byte(
+		`[{"index":"magento2_product_1_v2"},{"index":".geoip_databases"},{"index":"magento2_category_1_v1"}]`,
+	))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"magento2_category_1_v1", "magento2_product_1_v2"}, indices)
+
+	_, err = searchIndices([]byte("not json"))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SearchTestSuite) TestSearchIndexDefinition() {
+	raw := []byte(`{"magento2_product_1_v2":{
+		"aliases":{"magento2_product_1":{}},
+		"mappings":{"properties":{"sku":{"type":"keyword"}}},
+		"settings":{"index":{"number_of_shards":"1","uuid":"abc","creation_date":"1700000000000"}}
+	}}`)
+
+	definition, err := searchIndexDefinition(raw, "magento2_product_1_v2")
+	assert.NoError(suite.T(), err)
+	assert.JSONEq(suite.T(), `{
+		"aliases":{"magento2_product_1":{}},
+		"mappings":{"properties":{"sku":{"type":"keyword"}}},
+		"settings":{"index":{"number_of_shards":"1"}}
+	}`, string(definition))
+
+	_, err = searchIndexDefinition(raw, "missing")
+	assert.Error(suite.T(), err)
+}
@@ -169,24 +169,19 @@ func (c *Client) selfUpdate(cmd *cmdpkg.Command) error {
 		return fmt.Errorf("cannot get update url: %w", err)
 	}
 
-	req, err := c.prepareRequest(updateAsset.URL, true)
+	archive, err := c.downloadFile(updateAsset.URL, updateAsset.Name, updateAsset.SHA256)
 	if err != nil {
-		return fmt.Errorf("cannot create request: %w", err)
+		return err
 	}
+	defer archive.Close()
 
-	resp, err := http.DefaultClient.Do(req)
+	newBinary, err := util.DecompressFileFromArchive(archive, updateAsset.Name, binaryName)
 	if err != nil {
-		return fmt.Errorf("cannot run http request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("%s URL not found", updateAsset.Name)
+		return err
 	}
 
-	newBinary, err := util.DecompressFileFromArchive(resp.Body, updateAsset.Name, binaryName)
-	if err != nil {
-		return err
+	if closer, ok := newBinary.(io.Closer); ok {
+		defer closer.Close()
 	}
 
 	err = update.Apply(newBinary, update.Options{TargetPath: binaryPath})
@@ -246,6 +241,8 @@ func (c *Client) updateURL(cmd *cmdpkg.Command) (*asset, error) {
 
 	for _, asset := range release.Assets {
 		if asset.Name == packagename {
+			asset.SHA256 = c.releaseAssetChecksum(release, &asset)
+
 			return &asset, nil
 		}
 	}
@@ -270,4 +267,7 @@ type asset struct {
 	Name        string `json:"name"`
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Digest      string `json:"digest"`
+	// SHA256 is the checksum of the asset (see releaseAssetChecksum).
+	SHA256 string `json:"-"`
 }
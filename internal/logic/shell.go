@@ -1,23 +1,44 @@
 package logic
 
 import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/rewardenv/reward/internal/config"
 	"github.com/rewardenv/reward/internal/shell"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
 // RunCmdShell opens a shell in the environment's default application container.
 func (c *Client) RunCmdShell(cmd *cobra.Command, args []string) error {
+	if c.PreferredShell() != "" && !util.ContainsString(config.ValidShells(), c.PreferredShell()) {
+		return config.ErrInvalidShell
+	}
+
 	c.SetShellContainer(c.EnvType())
-	c.SetDefaultShellCommand(c.ShellContainer)
+	c.setShellCommand(cmd)
 	c.SetShellUser(c.ShellContainer)
 
+	if root, _ := cmd.Flags().GetBool("root"); root {
+		c.ShellUser = "root"
+	}
+
+	container, err := c.Docker.RunningEnvServiceContainer(c.ShellContainer)
+	if err != nil {
+		return err
+	}
+
+	if !container.Healthy() {
+		log.Warnf("Container %s is %s.", container.Name, container.Health)
+	}
+
 	var shellCommand []string
 	if len(args) > 0 {
 		shellCommand = util.ExtractUnknownArgs(cmd.Flags(), args)
 	} else {
-		shellCommand = util.ExtractUnknownArgs(cmd.Flags(), []string{c.DefaultShellCommand})
+		shellCommand = c.shellWithFallback(c.DefaultShellCommand)
 	}
 
 	passedArgs := append([]string{
@@ -27,7 +48,7 @@ func (c *Client) RunCmdShell(cmd *cobra.Command, args []string) error {
 		c.ShellContainer,
 	}, shellCommand...)
 
-	err := c.RunCmdEnvDockerCompose(passedArgs,
+	err = c.RunCmdEnvDockerCompose(passedArgs,
 		shell.WithCatchOutput(false),
 		shell.WithSuppressOutput(true),
 	)
@@ -37,3 +58,26 @@ func (c *Client) RunCmdShell(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// setShellCommand sets the command of the shell: the --command flag, the --shell flag, REWARD_SHELL_COMMAND,
+// REWARD_SHELL or the default shell of the container, in this order.
+func (c *Client) setShellCommand(cmd *cobra.Command) {
+	c.SetDefaultShellCommand(c.ShellContainer)
+
+	if cmd.Flags().Changed("shell") && !cmd.Flags().Changed("command") {
+		c.DefaultShellCommand = c.PreferredShell()
+	}
+}
+
+// shellWithFallback returns a command which launches the preferred shell if it's available in the container,
+// otherwise it falls back to bash or sh. Custom shell commands (eg. REWARD_SHELL_COMMAND) are returned as they are.
+func (c *Client) shellWithFallback(preferred string) []string {
+	if !util.ContainsString(config.ValidShells(), preferred) {
+		return []string{preferred}
+	}
+
+	return []string{
+		"sh", "-c",
+		fmt.Sprintf(`for s in %s bash sh; do command -v "${s}" >/dev/null 2>&1 && exec "${s}"; done`, preferred),
+	}
+}
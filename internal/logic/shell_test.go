@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShellTestSuite struct {
+	suite.Suite
+}
+
+func TestShellTestSuite(t *testing.T) {
+	suite.Run(t, new(ShellTestSuite))
+}
+
+func (suite *ShellTestSuite) TestShellWithFallback() {
+	tests := []struct {
+		name      string
+		preferred string
+		want      []string
+	}{
+		{
+			name:      "valid shell falls back to bash and sh",
+			preferred: "zsh",
+			want: []string{
+				"sh", "-c",
+				`for s in zsh bash sh; do command -v "${s}" >/dev/null 2>&1 && exec "${s}"; done`,
+			},
+		},
+		{
+			name:      "custom shell command is returned as it is",
+			preferred: "/usr/local/bin/fish",
+			want:      []string{"/usr/local/bin/fish"},
+		},
+	}
+
+	c := newTestClient(nil)
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.shellWithFallback(tt.preferred))
+		})
+	}
+}
+
+func (suite *ShellTestSuite) TestSetShellCommand() {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		flags    map[string]string
+		want     string
+	}{
+		{
+			name: "default shell of the container",
+			want: "bash",
+		},
+		{
+			name:     "shell command setting",
+			settings: map[string]interface{}{"reward_shell_command": "fish", "reward_shell": "zsh"},
+			want:     "fish",
+		},
+		{
+			name:     "shell flag takes precedence over the shell command setting",
+			settings: map[string]interface{}{"reward_shell_command": "fish"},
+			flags:    map[string]string{"shell": "zsh"},
+			want:     "zsh",
+		},
+		{
+			name:  "command flag takes precedence over the shell flag",
+			flags: map[string]string{"shell": "zsh", "command": "fish"},
+			want:  "fish",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("shell", "", "")
+			cmd.Flags().String("command", "", "")
+
+			for name, value := range tt.flags {
+				assert.NoError(t, cmd.Flags().Set(name, value))
+			}
+
+			c := newTestClient(tt.settings)
+			assert.NoError(t, c.BindPFlag("reward_shell", cmd.Flags().Lookup("shell")))
+			assert.NoError(t, c.BindPFlag("reward_shell_command", cmd.Flags().Lookup("command")))
+
+			c.ShellContainer = "php-fpm"
+			c.setShellCommand(cmd)
+
+			assert.Equal(t, tt.want, c.DefaultShellCommand)
+		})
+	}
+}
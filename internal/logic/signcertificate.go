@@ -67,14 +67,10 @@ func (c *Client) RunCmdSignCertificate(args []string, force ...bool) error {
 		}
 	}
 
-	err = c.RunCmdSvc([]string{"up", "traefik"})
+	// traefik loads the new certificate from the dynamic configuration, it doesn't have to be restarted
+	err = c.reloadTraefikDynamicConfig()
 	if err != nil {
-		return fmt.Errorf("cannot run bring up traefik: %w", err)
-	}
-
-	err = c.RunCmdSvc([]string{"restart", "traefik"})
-	if err != nil {
-		return fmt.Errorf("cannot run restart traefik: %w", err)
+		return fmt.Errorf("cannot reload traefik: %w", err)
 	}
 
 	return nil
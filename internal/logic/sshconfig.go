@@ -0,0 +1,212 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// legacySSHConfigFile is the system wide ssh config file which contained the tunnel configuration before it was
+// moved to the user's ssh config directory.
+//
+//nolint:gocritic
+var legacySSHConfigFile = filepath.Join("/etc/ssh/ssh_config")
+
+// sshConfigPaths returns the path of the user's ssh config file and the path of the app's ssh config file.
+func (c *Client) sshConfigPaths() (string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+
+	sshDir := filepath.Join(home, ".ssh")
+
+	return filepath.Join(sshDir, "config"), filepath.Join(sshDir, "config.d", c.AppName()+".conf"), nil
+}
+
+// sshConfigInclude returns the Include directive argument of the app's ssh config file (relative to ~/.ssh).
+func (c *Client) sshConfigInclude() string {
+	return fmt.Sprintf("config.d/%s.conf", c.AppName())
+}
+
+// sshConfig returns the content of the app's ssh config file.
+func (c *Client) sshConfig() string {
+	return fmt.Sprintf(
+		`# Generated by %[1]s. Run "%[1]s install --ssh-config" to update it.
+Host %[2]s
+  HostName 127.0.0.1
+  User user
+  Port %[3]s
+  IdentityFile "%[4]s"
+`,
+		c.AppName(),
+		c.TunnelHost(),
+		c.TunnelPort(),
+		// ssh reads the backslashes of the Windows paths as escape characters, the forward slashes work on every OS
+		filepath.ToSlash(filepath.Join(c.TunnelDir(), "ssh_key")),
+	)
+}
+
+// writeSSHConfig writes the app's ssh config file and includes it in the user's ssh config file. Both files are
+// written only if their content changes.
+func (c *Client) writeSSHConfig() error {
+	userConfigFile, configFile, err := c.sshConfigPaths()
+	if err != nil {
+		return err
+	}
+
+	err = writeFileIfChanged(configFile, c.sshConfig())
+	if err != nil {
+		return fmt.Errorf("cannot write ssh config file: %w", err)
+	}
+
+	content, err := os.ReadFile(userConfigFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read ssh config file: %w", err)
+	}
+
+	home := filepath.Dir(filepath.Dir(userConfigFile))
+
+	if !sshConfigIncludes(string(content), home, configFile) {
+		log.Debugf("Adding Include directive to ssh config file: %s...", userConfigFile)
+
+		err = writeFileIfChanged(userConfigFile, addSSHConfigInclude(string(content), c.sshConfigInclude()))
+		if err != nil {
+			return fmt.Errorf("cannot write ssh config file: %w", err)
+		}
+	}
+
+	return c.removeLegacySSHConfig()
+}
+
+// removeSSHConfig removes the app's ssh config file and its Include directive from the user's ssh config file.
+func (c *Client) removeSSHConfig() error {
+	userConfigFile, configFile, err := c.sshConfigPaths()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot remove ssh config file: %w", err)
+	}
+
+	content, err := os.ReadFile(userConfigFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot read ssh config file: %w", err)
+	}
+
+	err = writeFileIfChanged(userConfigFile, removeSSHConfigInclude(string(content), c.sshConfigInclude()))
+	if err != nil {
+		return fmt.Errorf("cannot write ssh config file: %w", err)
+	}
+
+	return nil
+}
+
+// removeLegacySSHConfig removes the tunnel configuration block from the system wide ssh config file.
+func (c *Client) removeLegacySSHConfig() error {
+	content, err := os.ReadFile(legacySSHConfigFile)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	updated := legacySSHConfigBlockRegex(c.AppName()).ReplaceAllString(string(content), "")
+	if updated == string(content) {
+		return nil
+	}
+
+	log.Printf("Removing the legacy tunnel configuration from %s...", legacySSHConfigFile)
+
+	cmd := exec.Command("sudo", "tee", legacySSHConfigFile)
+	cmd.Stdin = strings.NewReader(updated)
+
+	out, err := cmd.CombinedOutput()
+
+	log.Debugf("Command output: %s", string(out))
+
+	if err != nil {
+		log.Warnf("Cannot remove the legacy tunnel configuration from %s: %s", legacySSHConfigFile, err)
+
+		return nil
+	}
+
+	log.Println("...legacy tunnel configuration removed.")
+
+	return nil
+}
+
+func legacySSHConfigBlockRegex(appName string) *regexp.Regexp {
+	marker := regexp.QuoteMeta(strings.ToUpper(appName))
+
+	return regexp.MustCompile(fmt.Sprintf(`(?ms)^## %[1]s START ##.*?## %[1]s END ##\n?`, marker))
+}
+
+// sshConfigIncludes returns true if a top level Include directive of the ssh config content includes the file.
+// The Include directives after a Host or Match line are conditional, so they are ignored.
+func sshConfigIncludes(content, home, file string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(strings.Replace(line, "=", " ", 1))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "host", "match":
+			return false
+		case "include":
+			for _, pattern := range fields[1:] {
+				pattern = strings.Trim(pattern, `"`)
+
+				switch {
+				case strings.HasPrefix(pattern, "~/"):
+					pattern = filepath.Join(home, pattern[2:])
+				case !filepath.IsAbs(pattern):
+					pattern = filepath.Join(home, ".ssh", pattern)
+				}
+
+				if ok, _ := filepath.Match(pattern, file); ok {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// addSSHConfigInclude prepends the Include directive to the ssh config content. It has to precede the Host and Match
+// lines to apply to every host.
+func addSSHConfigInclude(content, include string) string {
+	return fmt.Sprintf("Include %s\n\n%s", include, content)
+}
+
+// removeSSHConfigInclude removes the Include directive (added by addSSHConfigInclude) from the ssh config content.
+func removeSSHConfigInclude(content, include string) string {
+	return regexp.MustCompile(fmt.Sprintf(`(?m)^Include %s\n(\n)?`, regexp.QuoteMeta(include))).
+		ReplaceAllString(content, "")
+}
+
+// writeFileIfChanged writes the content to the file (and creates its directory) if the content differs.
+func writeFileIfChanged(path, content string) error {
+	current, err := os.ReadFile(path)
+	if err == nil && string(current) == content {
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	return os.WriteFile(path, []byte(content), 0o600) //nolint:wrapcheck
+}
@@ -0,0 +1,72 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SSHConfigTestSuite struct {
+	suite.Suite
+}
+
+func TestSSHConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(SSHConfigTestSuite))
+}
+
+func (suite *SSHConfigTestSuite) TestSSHConfigIncludes() {
+	const (
+		home = "/home/alice"
+		file = "/home/alice/.ssh/config.d/reward.conf"
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "empty"},
+		{name: "relative", content: "Include config.d/reward.conf\n", want: true},
+		{name: "glob", content: "# comment\ninclude config.d/*\n", want: true},
+		{name: "tilde", content: "Include ~/.ssh/config.d/*.conf\n", want: true},
+		{name: "absolute with equals sign", content: "Include=/home/alice/.ssh/config.d/reward.conf\n", want: true},
+		{name: "other file", content: "Include config.d/other.conf\n"},
+		{name: "conditional", content: "Host example.com\n  Include config.d/reward.conf\n"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sshConfigIncludes(tt.content, home, file))
+		})
+	}
+}
+
+func (suite *SSHConfigTestSuite) TestAddAndRemoveSSHConfigInclude() {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty"},
+		{name: "existing config", content: "Host example.com\n  User alice\n"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			added := addSSHConfigInclude(tt.content, "config.d/reward.conf")
+
+			assert.True(t, sshConfigIncludes(added, "/home/alice", "/home/alice/.ssh/config.d/reward.conf"))
+			assert.Equal(t, tt.content, removeSSHConfigInclude(added, "config.d/reward.conf"))
+		})
+	}
+}
+
+func (suite *SSHConfigTestSuite) TestLegacySSHConfigBlockRegex() {
+	content := "Host *\n  SendEnv LANG\n## REWARD START ##\nHost tunnel.reward.test\n  Port 2222\n## REWARD END ##\n"
+
+	assert.Equal(
+		suite.T(),
+		"Host *\n  SendEnv LANG\n",
+		legacySSHConfigBlockRegex("reward").ReplaceAllString(content, ""),
+	)
+}
@@ -0,0 +1,112 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+// exitCodeOOMKilled is the exit code of the containers killed by SIGKILL, which is the case of the OOM killer.
+const exitCodeOOMKilled = "137"
+
+// RunCmdStatus prints the state of the containers of the environments and the common services and warns about the
+// resource pressure and the service versions of the environment which are past their end of life or unsupported.
+// With --strict these service versions are an error. If --watch is set, it keeps printing the container events and
+// reacts to them.
+func (c *Client) RunCmdStatus(cmd *cmdpkg.Command) error {
+	containers, err := c.Docker.ContainerDetailsByLabel(c.LabelEnvName())
+	if err != nil {
+		return fmt.Errorf("cannot get containers: %w", err)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		if containers[i].Project != containers[j].Project {
+			return containers[i].Project < containers[j].Project
+		}
+
+		return containers[i].Service < containers[j].Service
+	})
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Environment", "Service", "Container", "State", "Health"})
+
+	for _, container := range containers {
+		t.AppendRow(table.Row{container.Project, container.Service, container.Name, container.State, container.Health})
+	}
+
+	t.Render()
+
+	c.warnResourcePressure("")
+
+	err = c.checkServiceVersionLifecycle(cmd)
+	if err != nil {
+		return err
+	}
+
+	if watch, _ := cmd.Flags().GetBool("watch"); !watch {
+		return nil
+	}
+
+	log.Println("Watching container events...")
+
+	// The command runs until it's interrupted, the signal handler of the application exits.
+	return c.Docker.WatchContainerEvents(context.Background(), c.LabelEnvName(), c.handleContainerEvent)
+}
+
+// checkServiceVersionLifecycle warns about the service versions of the environment which are past their end of life
+// or unsupported by the Magento version of the project. With --strict it returns an error if there are any.
+func (c *Client) checkServiceVersionLifecycle(cmd *cmdpkg.Command) error {
+	if !c.EnvInitialized() {
+		return nil
+	}
+
+	warnings := c.ServiceVersionLifecycleWarnings(time.Now())
+	for _, warning := range warnings {
+		log.Warnln(warning)
+	}
+
+	if strict, _ := cmd.Flags().GetBool("strict"); strict && len(warnings) > 0 {
+		return config.ErrServiceVersionLifecycle(len(warnings))
+	}
+
+	return nil
+}
+
+// handleContainerEvent prints the container event, warns about the OOM kills and reconnects the peered services to
+// the environment networks when a common service container is recreated.
+func (c *Client) handleContainerEvent(event docker.Event) {
+	if c.JSONOutput() {
+		c.printJSONLine(event)
+	} else {
+		fmt.Println(event)
+	}
+
+	switch event.Action {
+	case docker.EventOOM:
+		log.Warnf("Container %s ran out of memory. Consider raising the memory limit of Docker.", event.Container)
+	case docker.EventDie:
+		if event.ExitCode == exitCodeOOMKilled {
+			log.Warnf("Container %s was killed (exit code: %s), it might have run out of memory.", event.Container,
+				event.ExitCode)
+		}
+	case docker.EventStart:
+		if event.Project != c.AppName() {
+			return
+		}
+
+		log.Debugf("Common service %s started, connecting it to the environment networks...", event.Service)
+
+		err := c.connectPeeredServices()
+		if err != nil {
+			log.Warnf("Cannot connect %s to the environment networks: %s", event.Service, err)
+		}
+	}
+}
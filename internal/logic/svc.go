@@ -30,9 +30,26 @@ func (c *Client) RunCmdSvc(args []string) error {
 		return nil
 	}
 
+	// the flags of reward are not parsed as the arguments are passed to docker compose
+	args = c.extractRewardFlags(args)
+
+	// offline mode: the images are not pulled, only the locally cached images are used
+	args, proceed := c.offlineComposeArgs(args)
+	if !proceed {
+		return nil
+	}
+
+	// bandwidth limit: the images are pulled one by one (or by the configured concurrency)
+	c.limitPullConcurrency(args)
+
 	tplgen := templates.New()
 
 	if util.ContainsString(args, "up") {
+		err := c.CheckTraefikHTTP3()
+		if err != nil {
+			return err
+		}
+
 		serviceDomain := c.ServiceDomain()
 
 		if !util.FileExists(filepath.Join(c.SSLDir(), "certs", serviceDomain+".crt.pem")) {
@@ -42,14 +59,26 @@ func (c *Client) RunCmdSvc(args []string) error {
 			}
 		}
 
-		err := tplgen.SvcGenerateTraefikConfig()
+		err = tplgen.SvcGenerateTraefikConfig()
 		if err != nil {
 			return fmt.Errorf("cannot generate traefik config: %w", err)
 		}
 
-		err = tplgen.SvcGenerateTraefikDynamicConfig(c.ServiceDomain())
+		if c.TraefikRollingRestart() {
+			err = tplgen.SvcGenerateTraefikProxyConfig()
+			if err != nil {
+				return fmt.Errorf("cannot generate traefik proxy config: %w", err)
+			}
+		}
+
+		_, err = c.generateTraefikDynamicConfig()
 		if err != nil {
-			return fmt.Errorf("cannot generate traefik dynamic config: %w", err)
+			return err
+		}
+
+		err = util.CreateDir(c.TraefikAccessLogDir(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create traefik log directory: %w", err)
 		}
 
 		// Add --detach to the args (to run in background) if the user didn't specify it.
@@ -70,6 +99,11 @@ func (c *Client) RunCmdSvc(args []string) error {
 	}
 
 	if util.ContainsString(args, "restart") {
+		err := c.CheckTraefikHTTP3()
+		if err != nil {
+			return err
+		}
+
 		serviceDomain := c.ServiceDomain()
 
 		if !util.FileExists(filepath.Join(c.SSLDir(), "certs", serviceDomain+".crt.pem")) {
@@ -83,38 +117,81 @@ func (c *Client) RunCmdSvc(args []string) error {
 			}
 		}
 
-		err := tplgen.SvcGenerateTraefikConfig()
+		err = tplgen.SvcGenerateTraefikConfig()
 		if err != nil {
 			return fmt.Errorf("cannot generate traefik config: %w", err)
 		}
 
-		err = tplgen.SvcGenerateTraefikDynamicConfig(c.ServiceDomain())
+		if c.TraefikRollingRestart() {
+			err = tplgen.SvcGenerateTraefikProxyConfig()
+			if err != nil {
+				return fmt.Errorf("cannot generate traefik proxy config: %w", err)
+			}
+		}
+
+		_, err = c.generateTraefikDynamicConfig()
 		if err != nil {
-			return fmt.Errorf("cannot generate traefik dynamic config: %w", err)
+			return err
 		}
 	}
 
+	if util.ContainsString(args, "restart") && c.TraefikRollingRestart() {
+		return c.runCmdSvcRollingRestart(args)
+	}
+
 	// pass orchestration through to docker-compose
 	err := c.RunCmdSvcDockerCompose(args, shell.WithCatchOutput(false))
 	if err != nil {
 		return err
 	}
 
+	// the network of the common services is created by the first `svc up`, its subnet is allowed to reach the
+	// dashboard once traefik loads the regenerated configuration
+	if util.ContainsString(args, "up") {
+		err = c.reloadTraefikDynamicConfig()
+		if err != nil {
+			return fmt.Errorf("cannot reload traefik: %w", err)
+		}
+	}
+
+	// keep the known_hosts entries of the tunnel up to date with its host keys
+	if util.ContainsString(args, "up") && c.TunnelEnabled() {
+		err = c.updateTunnelKnownHosts()
+		if err != nil {
+			log.Warnf("Cannot update the tunnel entries in the known_hosts file: %s", err)
+		}
+	}
+
 	// connect peered service containers to environment networks when 'svc up' is run
-	networks, err := c.Docker.NetworkNamesByLabel(fmt.Sprintf("label=dev.%s.environment.name", c.AppName()))
+	err = c.connectPeeredServices()
+	if err != nil {
+		return err
+	}
+
+	log.Debugln("...finished running svc command.")
+
+	return nil
+}
+
+// connectPeeredServices connects the peered service containers (eg. traefik) to the networks of all environments.
+func (c *Client) connectPeeredServices() error {
+	networks, err := c.Docker.NetworkNamesByLabel(fmt.Sprintf("dev.%s.environment.name", c.AppName()))
 	if err != nil {
 		return fmt.Errorf("cannot get environment networks: %w", err)
 	}
 
 	for _, network := range networks {
+		// The network of the common services is labeled as well.
+		if network == c.AppName() {
+			continue
+		}
+
 		err = c.DockerPeeredServices("connect", network)
 		if err != nil {
 			return fmt.Errorf("cannot connect peered services: %w", err)
 		}
 	}
 
-	log.Debugln("...finished running svc command.")
-
 	return nil
 }
 
@@ -157,7 +234,22 @@ func (c *Client) RunCmdSvcBuildDockerComposeCommand(args []string, opts ...shell
 		tplgen  = templates.New()
 	)
 
-	err := tplgen.RunCmdSvcBuildDockerComposeTemplate(tpl, tplList)
+	err := c.configureContainerUI()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.configureDNSServer()
+	if err != nil {
+		return "", err
+	}
+
+	err = c.configureGlitchTip()
+	if err != nil {
+		return "", err
+	}
+
+	err = tplgen.RunCmdSvcBuildDockerComposeTemplate(tpl, tplList)
 	if err != nil {
 		return "", err
 	}
@@ -167,6 +259,22 @@ func (c *Client) RunCmdSvcBuildDockerComposeCommand(args []string, opts ...shell
 		return "", err
 	}
 
+	// The common services are labeled as the environment of the application.
+	labels := map[string]string{
+		c.LabelEnvName(): c.AppName(),
+		c.LabelEnvType(): "svc",
+	}
+
+	svcDockerComposeConfigs = tplgen.AppendLabelsConfig(
+		svcDockerComposeConfigs,
+		c.AppName(),
+		labels,
+		map[string]string{
+			c.LabelVersion(): c.AppVersion(),
+		},
+		c.labelsMismatch(labels),
+	)
+
 	out, err := c.DockerCompose.RunWithConfig(args, svcDockerComposeConfigs, opts...)
 	if err != nil {
 		return out, err
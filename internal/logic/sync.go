@@ -2,21 +2,33 @@ package logic
 
 import (
 	"fmt"
-	"net/http"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-version"
 	log "github.com/sirupsen/logrus"
 
+	cmdpkg "github.com/rewardenv/reward/cmd"
 	"github.com/rewardenv/reward/internal/shell"
 	"github.com/rewardenv/reward/internal/templates"
 	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrMutagenNotManaged occurs when the mutagen binary is not managed by the application.
+var ErrMutagenNotManaged = fmt.Errorf("mutagen is not managed by reward, enable reward_mutagen_managed or update " +
+	"mutagen using your package manager")
+
+// ErrMutagenChecksumUnknown occurs when the checksum of the mutagen archive cannot be looked up.
+var ErrMutagenChecksumUnknown = func(name string) error {
+	return fmt.Errorf("cannot verify %s, its checksum is unknown, set reward_mutagen_sha256", name)
+}
+
 // RunCmdSyncStart represents the sync start command.
 func (c *Client) RunCmdSyncStart() error {
 	if !c.SyncEnabled() {
@@ -35,34 +47,57 @@ func (c *Client) RunCmdSyncStart() error {
 
 	log.Debugln("Looking up synced container...")
 
-	containerID, err := c.Docker.ContainerIDByName(c.Config.SyncedContainer())
+	container, err := c.Docker.RunningEnvServiceContainer(c.Config.SyncedContainer())
 	if err != nil {
 		return fmt.Errorf("cannot lookup synced container: %w", err)
 	}
 
-	log.Debugf("...synced container found: %s.", containerID)
+	log.Debugf("...synced container found: %s.", container.ID)
 	log.Println("Creating mutagen sync session...")
 
 	// Create sync session
 	// mutagen sync create -c /path/to/config/file.yml --label reward-sync=env --ignore xyz path docker://container/path
 	cmd := []string{
-		"mutagen", "sync", "create", "-c",
-		util.Quote(c.MutagenSyncFile()),
+		c.mutagenCommand(), "sync", "create", "-c",
+		c.MutagenSyncFile(),
 		"--label",
 		fmt.Sprintf(`%s-sync=%s`, c.AppName(), c.EnvName()),
 	}
 
 	// Append --ignore flag only if it's not empty
 	if strings.TrimSpace(c.MutagenSyncIgnore()) != "" {
-		cmd = append(cmd, fmt.Sprintf(`--ignore %s`, c.Config.MutagenSyncIgnore()))
+		cmd = append(cmd, "--ignore", c.Config.MutagenSyncIgnore())
+	}
+
+	// Ignore the files generated by the frontend sidecar
+	for _, ignore := range c.FrontendSyncIgnore() {
+		cmd = append(cmd, "--ignore", ignore)
+	}
+
+	// The vendor directories live in container volumes
+	if c.VendorVolume() {
+		for _, dir := range c.VendorVolumeDirs() {
+			cmd = append(cmd, "--ignore", "/"+dir)
+		}
+	}
+
+	mode, err := c.SyncMode()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if mode != "" {
+		cmd = append(cmd, "--sync-mode", mode)
+	}
+
+	// The protected paths are synced by a separate one-way session
+	protected := c.existingSyncProtectedPaths()
+	for _, p := range protected {
+		cmd = append(cmd, "--ignore", "/"+p)
 	}
 
 	// Append rest of the command line flags
-	cmd = append(
-		cmd,
-		util.Quote(fmt.Sprintf(`%s%s`, c.Config.Cwd(), c.Config.WebRoot())),
-		util.Quote(fmt.Sprintf(`docker://%s%s`, containerID, c.Config.SyncedDir())),
-	)
+	cmd = append(cmd, c.syncEndpoints(container.ID)...)
 
 	out, err := c.Shell.RunCommand(cmd)
 	log.Debugf("Mutagen sync start command output: %s", out)
@@ -71,11 +106,22 @@ func (c *Client) RunCmdSyncStart() error {
 		return fmt.Errorf("cannot create mutagen sync session: %w", err)
 	}
 
+	sessions := 1
+
+	if len(protected) > 0 {
+		err = c.startProtectedSync(container.ID, protected)
+		if err != nil {
+			return err
+		}
+
+		sessions++
+	}
+
 	log.Println("...mutagen sync session created.")
 	log.Println("Waiting for sync to be ready...")
 
 	cmd = []string{
-		"mutagen", "sync", "list", "--label-selector",
+		c.mutagenCommand(), "sync", "list", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.AppName(), c.EnvName()),
 	}
 
@@ -95,7 +141,7 @@ func (c *Client) RunCmdSyncStart() error {
 			return fmt.Errorf("mutagen encountered an error: %s, %w", out, err)
 		}
 
-		if strings.Contains(strings.ToLower(string(out)), strings.ToLower("watching for changes")) {
+		if strings.Count(strings.ToLower(string(out)), strings.ToLower("watching for changes")) >= sessions {
 			break
 		}
 
@@ -107,6 +153,93 @@ func (c *Client) RunCmdSyncStart() error {
 	return nil
 }
 
+// startProtectedSync creates a one-way sync session of the protected paths from the host to the container, so the
+// changes made in the container are reverted instead of overwriting the files on the host.
+func (c *Client) startProtectedSync(containerID string, paths []string) error {
+	log.Debugf("Creating mutagen sync session of the protected paths: %s...", strings.Join(paths, ", "))
+
+	cmd := []string{
+		c.mutagenCommand(), "sync", "create", "-c",
+		c.MutagenSyncFile(),
+		"--label",
+		fmt.Sprintf(`%s-sync=%s`, c.AppName(), c.EnvName()),
+		"--sync-mode", "one-way-replica",
+	}
+
+	for _, ignore := range syncProtectedIgnores(paths) {
+		cmd = append(cmd, "--ignore", ignore)
+	}
+
+	cmd = append(cmd, c.syncEndpoints(containerID)...)
+
+	out, err := c.Shell.RunCommand(cmd)
+	log.Debugf("Mutagen sync start command output: %s", out)
+
+	if err != nil {
+		return fmt.Errorf("cannot create mutagen sync session of the protected paths: %w", err)
+	}
+
+	log.Debugln("...mutagen sync session of the protected paths created.")
+
+	return nil
+}
+
+// syncEndpoints returns the alpha (host) and beta (container) endpoints of the sync sessions.
+func (c *Client) syncEndpoints(containerID string) []string {
+	return []string{
+		fmt.Sprintf(`%s%s`, c.Config.Cwd(), c.Config.WebRoot()),
+		fmt.Sprintf(`docker://%s%s`, containerID, c.Config.SyncedDir()),
+	}
+}
+
+// existingSyncProtectedPaths returns the protected paths which exist on the host. The paths which don't exist are not
+// protected, the one-way session would remove them from the container (eg. the env.php created by the installer).
+func (c *Client) existingSyncProtectedPaths() []string {
+	var paths []string
+
+	for _, p := range c.SyncProtectedPaths() {
+		p = strings.Trim(path.Clean("/"+filepath.ToSlash(p)), "/")
+		if p == "" {
+			continue
+		}
+
+		if _, err := util.FS.Stat(filepath.Join(c.Cwd(), c.WebRoot(), filepath.FromSlash(p))); err != nil {
+			log.Debugf("Protected path %s doesn't exist on the host, it's synced in both directions.", p)
+
+			continue
+		}
+
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+// syncProtectedIgnores returns the ignore rules of the sync session of the protected paths. Everything is ignored
+// except the protected paths and their parent directories, the later rules override the earlier ones.
+func syncProtectedIgnores(paths []string) []string {
+	var (
+		ignores = []string{"*"}
+		seen    = make(map[string]bool)
+	)
+
+	for _, p := range paths {
+		parts := strings.Split(p, "/")
+
+		for i := range parts {
+			rule := "!/" + strings.Join(parts[:i+1], "/")
+			if seen[rule] {
+				continue
+			}
+
+			seen[rule] = true
+			ignores = append(ignores, rule)
+		}
+	}
+
+	return ignores
+}
+
 // RunCmdSyncStop represents the sync stop command.
 func (c *Client) RunCmdSyncStop() error {
 	if !c.SyncEnabled() {
@@ -121,7 +254,7 @@ func (c *Client) RunCmdSyncStop() error {
 	log.Println("Terminating mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "terminate", "--label-selector",
+		c.mutagenCommand(), "sync", "terminate", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.AppName(), c.EnvName()),
 	}
 
@@ -152,7 +285,7 @@ func (c *Client) RunCmdSyncResume() error {
 	log.Println("Resuming mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "resume", "--label-selector",
+		c.mutagenCommand(), "sync", "resume", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.AppName(), c.EnvName()),
 	}
 
@@ -183,7 +316,7 @@ func (c *Client) RunCmdSyncPause() error {
 	log.Println("Pausing mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "pause", "--label-selector",
+		c.mutagenCommand(), "sync", "pause", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
 
@@ -214,7 +347,7 @@ func (c *Client) RunCmdSyncList(opts ...shell.Opt) (string, error) {
 	log.Println("Listing mutagen sync sessions...")
 
 	cmd := []string{
-		"mutagen", "sync", "list", "--label-selector",
+		c.mutagenCommand(), "sync", "list", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
 
@@ -245,7 +378,7 @@ func (c *Client) RunCmdSyncFlush() error {
 	log.Println("Flushing mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "flush", "--label-selector",
+		c.mutagenCommand(), "sync", "flush", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
 
@@ -276,7 +409,7 @@ func (c *Client) RunCmdSyncMonitor() error {
 	log.Println("Monitoring mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "monitor", "--label-selector",
+		c.mutagenCommand(), "sync", "monitor", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
 
@@ -307,7 +440,7 @@ func (c *Client) RunCmdSyncReset() error {
 	log.Println("Resetting mutagen sync session...")
 
 	cmd := []string{
-		"mutagen", "sync", "reset", "--label-selector",
+		c.mutagenCommand(), "sync", "reset", "--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
 
@@ -362,7 +495,7 @@ func (c *Client) RunCmdSyncTerminate() error {
 
 	// Terminate previous sync if it ran.
 	cmd := []string{
-		"mutagen", "sync", "terminate",
+		c.mutagenCommand(), "sync", "terminate",
 		"--label-selector",
 		fmt.Sprintf("%s-sync=%s", c.Config.AppName(), c.Config.EnvName()),
 	}
@@ -386,6 +519,10 @@ func (c *Client) CheckAndInstallMutagen() error {
 		return nil
 	}
 
+	if c.MutagenManaged() {
+		return c.checkManagedMutagen()
+	}
+
 	log.Debugln("Checking for mutagen...")
 
 	if !util.CommandAvailable("mutagen") {
@@ -398,15 +535,12 @@ func (c *Client) CheckAndInstallMutagen() error {
 	log.Debugln("...mutagen is available.")
 	log.Debugln("Checking mutagen version...")
 
-	mutagenVersion, err := c.Shell.RunCommand([]string{"mutagen", "version"},
-		shell.WithCatchOutput(true),
-		shell.WithSuppressOutput(true),
-	)
+	mutagenVersion, err := c.mutagenVersion()
 	if err != nil {
 		return fmt.Errorf("cannot get mutagen version: %w", err)
 	}
 
-	if version.Must(version.NewVersion(strings.TrimSpace(string(mutagenVersion)))).LessThan(
+	if version.Must(version.NewVersion(mutagenVersion)).LessThan(
 		version.Must(version.NewVersion(c.Config.MutagenRequiredVersion()))) {
 		log.Printf(
 			"Mutagen version %s or greater is required (version %s is installed).",
@@ -420,8 +554,234 @@ func (c *Client) CheckAndInstallMutagen() error {
 	return nil
 }
 
+// checkManagedMutagen installs the pinned version of mutagen if the managed binary doesn't exist. If another version
+// is installed (eg. the pinned version is changed by an update), it asks for running sync self-update, as replacing
+// the binary stops the running sync sessions.
+func (c *Client) checkManagedMutagen() error {
+	log.Debugln("Checking for managed mutagen...")
+
+	if !util.FileExists(c.MutagenBinary()) {
+		c.migrateSystemMutagenSessions()
+
+		return c.installManagedMutagen()
+	}
+
+	installed, err := c.mutagenVersion()
+	if err != nil {
+		return fmt.Errorf("cannot get mutagen version: %w", err)
+	}
+
+	if installed != c.MutagenVersion() {
+		log.Warnf("Mutagen %s is installed, but version %s is pinned. Run `%s sync self-update` to install it.",
+			installed, c.MutagenVersion(), c.AppName())
+	}
+
+	log.Debugln("...managed mutagen is available.")
+
+	return nil
+}
+
+// migrateSystemMutagenSessions terminates the sync sessions of the application in the daemon of the mutagen found in
+// $PATH when the managed mutagen is installed the first time, so the previous sessions don't keep syncing next to the
+// sessions of the managed mutagen. The environments of the terminated sessions are listed to start them again.
+func (c *Client) migrateSystemMutagenSessions() {
+	if !util.CommandAvailable("mutagen") {
+		return
+	}
+
+	selector := fmt.Sprintf("%s-sync", c.AppName())
+
+	out, err := c.Shell.RunCommand([]string{
+		"mutagen", "sync", "list", "--label-selector", selector,
+		"--template", fmt.Sprintf(`{{ range . }}{{ index .Labels %q }}{{ "\n" }}{{ end }}`, selector),
+	}, shell.WithCatchOutput(true), shell.WithSuppressOutput(true))
+	if err != nil {
+		log.Debugf("Cannot list the sync sessions of the mutagen found in $PATH: %s", err)
+
+		return
+	}
+
+	envs := mutagenSessionEnvs(string(out))
+	if len(envs) == 0 {
+		return
+	}
+
+	log.Printf("Terminating the sync sessions of the mutagen found in $PATH...")
+
+	_, err = c.Shell.RunCommand([]string{"mutagen", "sync", "terminate", "--label-selector", selector},
+		shell.WithCatchOutput(true),
+		shell.WithSuppressOutput(true),
+	)
+	if err != nil {
+		log.Warnf("Cannot terminate the sync sessions of the mutagen found in $PATH: %s", err)
+
+		return
+	}
+
+	log.Warnf("The sync sessions of the following environments were terminated, run `%s sync start` in them "+
+		"to start them again using the managed mutagen: %s", c.AppName(), strings.Join(envs, ", "))
+}
+
+// mutagenSessionEnvs returns the sorted unique environment names of the mutagen sync session list output.
+func mutagenSessionEnvs(out string) []string {
+	var envs []string
+
+	for _, env := range strings.Fields(out) {
+		if !util.ContainsString(envs, env) {
+			envs = append(envs, env)
+		}
+	}
+
+	sort.Strings(envs)
+
+	return envs
+}
+
+// RunCmdSyncSelfUpdate installs the pinned version of the managed mutagen binary. The mutagen daemon of the previous
+// version is stopped, the sync sessions have to be started again.
+func (c *Client) RunCmdSyncSelfUpdate(cmd *cmdpkg.Command) error {
+	if !c.MutagenManaged() {
+		return ErrMutagenNotManaged
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+
+	if util.FileExists(c.MutagenBinary()) && !force {
+		installed, err := c.mutagenVersion()
+		if err == nil && installed == c.MutagenVersion() {
+			log.Printf("Mutagen %s is already installed.", installed)
+
+			return nil
+		}
+	}
+
+	err := c.installManagedMutagen()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Run `%s sync start` to start the sync session again.", c.AppName())
+
+	return nil
+}
+
+// installManagedMutagen downloads the pinned version of mutagen and installs the binary and its agents to the mutagen
+// directory of the app home. The archive is verified using the SHA256SUMS file of the release.
+func (c *Client) installManagedMutagen() error {
+	// the installed mutagen has to be detected again
+	defer util.InvalidateCache(mutagenVersionCacheKey)
+
+	var (
+		url      = c.MutagenURL()
+		name     = path.Base(url)
+		binary   = filepath.Base(c.MutagenBinary())
+		checksum = c.mutagenChecksum()
+	)
+
+	if checksum == "" {
+		return ErrMutagenChecksumUnknown(name)
+	}
+
+	log.Printf("Installing mutagen %s...", c.MutagenVersion())
+
+	if util.FileExists(c.MutagenBinary()) {
+		log.Debugln("Stopping the mutagen daemon...")
+
+		_, _ = c.Shell.RunCommand([]string{c.mutagenCommand(), "daemon", "stop"},
+			shell.WithCatchOutput(true),
+			shell.WithSuppressOutput(true),
+		)
+	}
+
+	for _, file := range []string{binary, "mutagen-agents.tar.gz"} {
+		err := c.extractMutagenFile(url, name, checksum, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("...mutagen %s installed.", c.MutagenVersion())
+
+	return nil
+}
+
+// extractMutagenFile extracts the file from the mutagen archive to the mutagen directory. The file is written to a
+// temporary file first, so an interrupted extraction doesn't leave a broken binary behind.
+func (c *Client) extractMutagenFile(url, name, checksum, file string) error {
+	archive, err := c.downloadFile(url, name, checksum)
+	if err != nil {
+		return fmt.Errorf("cannot download mutagen: %w", err)
+	}
+	defer archive.Close()
+
+	r, err := util.DecompressFileFromArchive(archive, name, file)
+	if err != nil {
+		return fmt.Errorf("cannot extract %s: %w", file, err)
+	}
+
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	target := filepath.Join(c.MutagenDir(), file)
+
+	err = util.FS.MkdirAll(c.MutagenDir(), 0o755)
+	if err != nil {
+		return fmt.Errorf("cannot create directory %s: %w", c.MutagenDir(), err)
+	}
+
+	f, err := util.FS.OpenFile(target+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", target, err)
+	}
+
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = util.FS.Remove(target + ".tmp")
+
+		return fmt.Errorf("cannot write %s: %w", target, err)
+	}
+
+	err = util.FS.Rename(target+".tmp", target)
+	if err != nil {
+		return fmt.Errorf("cannot move %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// mutagenCommand returns the mutagen command, the managed binary or the one found in $PATH.
+func (c *Client) mutagenCommand() string {
+	return c.MutagenBinary()
+}
+
+// mutagenVersionCacheKey is the cache key of the memoized mutagen version.
+const mutagenVersionCacheKey = "mutagen-version"
+
+// mutagenVersion returns the version of the installed mutagen. The version is memoized until mutagen is installed.
+func (c *Client) mutagenVersion() (string, error) {
+	return util.Memoize(mutagenVersionCacheKey, func() (string, error) {
+		out, err := c.Shell.RunCommand([]string{c.mutagenCommand(), "version"},
+			shell.WithCatchOutput(true),
+			shell.WithSuppressOutput(true),
+		)
+		if err != nil {
+			return "", fmt.Errorf("cannot run mutagen version: %w", err)
+		}
+
+		return strings.TrimSpace(string(out)), nil
+	})
+}
+
 // InstallMutagen installs mutagen.
 func (c *Client) InstallMutagen() error {
+	// the installed mutagen has to be detected again
+	defer util.InvalidateCache(util.CommandAvailableCacheKey("mutagen"), mutagenVersionCacheKey)
+
 	switch util.OSDistro() {
 	case "darwin":
 		log.Println("Installing mutagen...")
@@ -465,26 +825,19 @@ func (c *Client) InstallMutagenForWindows() error {
 
 	log.Debugln("Downloading mutagen...")
 
-	req, err := c.prepareRequest(c.Config.MutagenURL(), true)
-	if err != nil {
-		return fmt.Errorf("cannot create HTTP request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
+	archive, err := c.downloadFile(c.Config.MutagenURL(), path.Base(c.Config.MutagenURL()), c.mutagenChecksum())
 	if err != nil {
 		return fmt.Errorf("cannot download mutagen: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("cannot download mutagen, http response status: %s", resp.Status)
-	}
+	defer archive.Close()
 
 	log.Debugln("...mutagen downloaded.")
 	log.Debugln("Extracting mutagen...")
 
-	files, err := util.Unzip(resp.Body, installDir)
-	if err != nil {
+	step := c.newProgress().Step("Extracting mutagen")
+
+	files, err := util.UnzipWithProgress(archive, installDir, step)
+	if step.Finish(err) != nil {
 		return fmt.Errorf("cannot extract mutagen: %w", err)
 	}
 
@@ -501,7 +854,7 @@ func (c *Client) ContainerChanged(container string) bool {
 	log.Println("Checking if synced container is changed...")
 	log.Debugln("Getting container state...")
 
-	containerState, err := c.Docker.ContainerStateByName(container)
+	details, err := c.Docker.EnvServiceContainer(container)
 	if err != nil {
 		log.Printf("...cannot get container state: %s. Assuming the container is changed, restarting sync session.",
 			err)
@@ -509,20 +862,9 @@ func (c *Client) ContainerChanged(container string) bool {
 		return true
 	}
 
-	log.Debugf("...current synced container (%s) state is: %s.", container, containerState)
-	log.Debugln("Getting container ID by Name...")
-
-	containerID, err := c.Docker.ContainerIDByName(container)
-	if err != nil {
-		log.Printf("...cannot get container ID: %s. Assuming the container is changed, restarting sync session.",
-			err)
-
-		return true
-	}
-
-	log.Debugf("...current synced container (%s) ID is: %s.", container, containerID)
+	log.Debugf("...current synced container (%s) state is: %s, ID is: %s.", container, details.State, details.ID)
 
-	if containerState != "running" {
+	if !details.Running() {
 		log.Println("...synced container is not running. Assuming the container is changed, restarting sync session.")
 
 		return true
@@ -544,7 +886,7 @@ func (c *Client) ContainerChanged(container string) bool {
 
 	log.Debugf("Previously synced container ID: %s", previousContainerID)
 
-	if previousContainerID != containerID {
+	if previousContainerID != details.ID {
 		log.Println("...synced container ID is changed. Assuming the container is changed, restarting sync session.")
 
 		return true
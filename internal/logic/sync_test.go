@@ -0,0 +1,40 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SyncTestSuite struct {
+	suite.Suite
+}
+
+func TestSyncTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncTestSuite))
+}
+
+func (suite *SyncTestSuite) TestMutagenSessionEnvs() {
+	tests := []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{
+			name: "no sessions",
+			out:  "",
+		},
+		{
+			name: "sessions",
+			out:  "shop\nblog\nshop\n",
+			want: []string{"blog", "shop"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mutagenSessionEnvs(tt.out))
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+// ErrSyncTimeout occurs when a file doesn't appear on the other side of the synchronization in time.
+var ErrSyncTimeout = func(file string, timeout time.Duration) error {
+	return fmt.Errorf("file %s was not synced in %s", file, timeout)
+}
+
+// syncProbeScript runs in the synced container. It reads commands from stdin: "wait <file>" waits until the file
+// created by the host appears and reports it, "touch <file>" creates a file for the host.
+const syncProbeScript = `cd %s || exit 1
+echo ready
+while read -r action file; do
+  case "$action" in
+    wait)
+      while [ ! -f "$file" ]; do sleep 0.005; done
+      rm -f "$file"
+      echo "seen $file"
+      ;;
+    touch)
+      date +%%s%%N > "$file"
+      ;;
+  esac
+done`
+
+// syncProbe measures the synchronization latency of files between the host and the synced container. It keeps a
+// shell running in the container, so the startup time of docker exec is not measured.
+type syncProbe struct {
+	hostDir string
+	timeout time.Duration
+	command *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	n       int
+}
+
+// startSyncProbe starts the probe in the synced container of the environment.
+func (c *Client) startSyncProbe(timeout time.Duration) (*syncProbe, error) {
+	container := c.DefaultSyncedContainer(c.EnvType())
+	if !c.Docker.ContainerRunning(container) {
+		return nil, ErrBenchContainerNotRunning(container)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	command := cmdpkg.Cmnd(
+		self, "env", "exec", "-T", container, "sh", "-c", fmt.Sprintf(syncProbeScript, c.DefaultSyncedDir(c.EnvType())),
+	)
+	command.Dir = c.Cwd()
+	command.Stderr = io.Discard
+
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create pipe: %w", err)
+	}
+
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create pipe: %w", err)
+	}
+
+	err = command.Start()
+	if err != nil {
+		return nil, fmt.Errorf("cannot start probe in the container: %w", err)
+	}
+
+	p := &syncProbe{
+		hostDir: filepath.Join(c.Cwd(), c.WebRoot()),
+		timeout: timeout,
+		command: command,
+		stdin:   stdin,
+		stdout:  bufio.NewReader(stdout),
+	}
+
+	line, err := p.readLine()
+	if err != nil || line != "ready" {
+		p.Close()
+
+		return nil, fmt.Errorf("cannot start probe in the container: %s", line)
+	}
+
+	return p, nil
+}
+
+// HostToContainer writes a timestamped file on the host and returns the time until it appears in the container.
+func (p *syncProbe) HostToContainer() (time.Duration, error) {
+	name := p.nextFile("host")
+	start := time.Now()
+
+	err := os.WriteFile(filepath.Join(p.hostDir, name), []byte(fmt.Sprintf("%d\n", start.UnixNano())), 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("cannot create file: %w", err)
+	}
+
+	_, err = fmt.Fprintf(p.stdin, "wait %s\n", name)
+	if err != nil {
+		return 0, fmt.Errorf("cannot send command to the probe: %w", err)
+	}
+
+	type seen struct {
+		line string
+		err  error
+	}
+
+	done := make(chan seen, 1)
+
+	go func() {
+		line, err := p.readLine()
+		done <- seen{line, err}
+	}()
+
+	select {
+	case s := <-done:
+		if s.err != nil || s.line != "seen "+name {
+			return 0, fmt.Errorf("unexpected response from the probe: %s", s.line)
+		}
+	case <-time.After(p.timeout):
+		// The probe is still waiting for the file, it cannot be used anymore.
+		_ = p.command.Process.Kill()
+
+		return 0, ErrSyncTimeout(name, p.timeout)
+	}
+
+	elapsed := time.Since(start)
+
+	// The container removes the file, the host removes it as well in case the deletion isn't synced back.
+	_ = os.Remove(filepath.Join(p.hostDir, name))
+
+	return elapsed, nil
+}
+
+// ContainerToHost writes a timestamped file in the container and returns the time until it appears on the host.
+func (p *syncProbe) ContainerToHost() (time.Duration, error) {
+	name := p.nextFile("container")
+	path := filepath.Join(p.hostDir, name)
+	start := time.Now()
+
+	_, err := fmt.Fprintf(p.stdin, "touch %s\n", name)
+	if err != nil {
+		return 0, fmt.Errorf("cannot send command to the probe: %w", err)
+	}
+
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+
+		if time.Since(start) > p.timeout {
+			return 0, ErrSyncTimeout(name, p.timeout)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	elapsed := time.Since(start)
+
+	_ = os.Remove(path)
+
+	return elapsed, nil
+}
+
+// Close stops the probe and removes the leftover files from the host.
+func (p *syncProbe) Close() {
+	_ = p.stdin.Close()
+	_ = p.command.Process.Kill()
+	_ = p.command.Wait()
+
+	files, _ := filepath.Glob(filepath.Join(p.hostDir, benchFilePrefix+"-*"))
+	for _, f := range files {
+		_ = os.Remove(f)
+	}
+}
+
+func (p *syncProbe) nextFile(origin string) string {
+	p.n++
+
+	return fmt.Sprintf("%s-%s-%d-%d", benchFilePrefix, origin, os.Getpid(), p.n)
+}
+
+func (p *syncProbe) readLine() (string, error) {
+	line, err := p.stdout.ReadString('\n')
+
+	return strings.TrimSpace(line), err
+}
+
+// RunCmdSyncBench measures the latency of the file synchronization between the host and the synced container in
+// both directions and prints the percentiles.
+func (c *Client) RunCmdSyncBench(cmd *cmdpkg.Command) error {
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if iterations < 1 {
+		iterations = 1
+	}
+
+	probe, err := c.startSyncProbe(timeout)
+	if err != nil {
+		return err
+	}
+	defer probe.Close()
+
+	log.Printf("Measuring file sync latency (%d files in each direction)...", iterations)
+
+	hostToContainer := make([]time.Duration, 0, iterations)
+	containerToHost := make([]time.Duration, 0, iterations)
+
+	for i := 0; i < iterations; i++ {
+		d, err := probe.HostToContainer()
+		if err != nil {
+			return err
+		}
+
+		hostToContainer = append(hostToContainer, d)
+
+		d, err = probe.ContainerToHost()
+		if err != nil {
+			return err
+		}
+
+		containerToHost = append(containerToHost, d)
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Direction", "Min", "P50", "P90", "P95", "P99", "Max"})
+
+	for _, row := range []struct {
+		direction string
+		samples   []time.Duration
+	}{
+		{"host -> container", hostToContainer},
+		{"container -> host", containerToHost},
+	} {
+		r := table.Row{row.direction}
+		for _, p := range []float64{0, 50, 90, 95, 99, 100} {
+			r = append(r, percentile(row.samples, p).Round(100*time.Microsecond))
+		}
+
+		t.AppendRow(r)
+	}
+
+	t.Render()
+
+	return nil
+}
+
+// percentile returns the p-th percentile of the samples using the nearest-rank method.
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
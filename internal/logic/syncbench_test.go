@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SyncBenchTestSuite struct {
+	suite.Suite
+}
+
+func TestSyncBenchTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncBenchTestSuite))
+}
+
+func (suite *SyncBenchTestSuite) TestPercentile() {
+	samples := []time.Duration{50, 10, 40, 20, 30, 60, 70, 80, 90, 100}
+
+	tests := []struct {
+		name string
+		p    float64
+		want time.Duration
+	}{
+		{name: "min", p: 0, want: 10},
+		{name: "p50", p: 50, want: 50},
+		{name: "p90", p: 90, want: 90},
+		{name: "p95", p: 95, want: 100},
+		{name: "max", p: 100, want: 100},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, percentile(samples, tt.p))
+		})
+	}
+
+	assert.Equal(suite.T(), time.Duration(0), percentile(nil, 50))
+}
+
+func (suite *SyncBenchTestSuite) TestSyncProbe() {
+	if _, err := exec.LookPath("sh"); err != nil {
+		suite.T().Skip("sh is not available")
+	}
+
+	// The probe script runs on the host, so the "container" sees the files immediately.
+	dir := suite.T().TempDir()
+	command := exec.Command("sh", "-c", fmt.Sprintf(syncProbeScript, dir))
+
+	stdin, err := command.StdinPipe()
+	assert.NoError(suite.T(), err)
+
+	stdout, err := command.StdoutPipe()
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), command.Start())
+
+	p := &syncProbe{
+		hostDir: dir,
+		timeout: 5 * time.Second,
+		command: command,
+		stdin:   stdin,
+		stdout:  bufio.NewReader(stdout),
+	}
+	defer p.Close()
+
+	line, err := p.readLine()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ready", line)
+
+	_, err = p.HostToContainer()
+	assert.NoError(suite.T(), err)
+
+	_, err = p.ContainerToHost()
+	assert.NoError(suite.T(), err)
+}
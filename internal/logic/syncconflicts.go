@@ -0,0 +1,277 @@
+package logic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrUnknownSyncConflictSide occurs when the side of the --keep flag is not supported.
+var ErrUnknownSyncConflictSide = func(side string) error {
+	return fmt.Errorf("unknown side: %s, valid options: host, container", side)
+}
+
+const (
+	syncConflictHost      = "host"
+	syncConflictContainer = "container"
+)
+
+// syncSession is a mutagen sync session as it's listed by mutagen sync list in json format.
+type syncSession struct {
+	Identifier string            `json:"identifier"`
+	Labels     map[string]string `json:"labels"`
+	Status     string            `json:"status"`
+	Paused     bool              `json:"paused"`
+	LastError  string            `json:"lastError"`
+	Conflicts  []syncConflict    `json:"conflicts"`
+}
+
+// syncConflict is a conflict of a sync session. The root is the path of the conflicting changes relative to the
+// sync root, the alpha side is the host and the beta side is the container.
+type syncConflict struct {
+	Root         string       `json:"root"`
+	AlphaChanges []syncChange `json:"alphaChanges"`
+	BetaChanges  []syncChange `json:"betaChanges"`
+}
+
+type syncChange struct {
+	Path string     `json:"path"`
+	Old  *syncEntry `json:"old"`
+	New  *syncEntry `json:"new"`
+}
+
+type syncEntry struct {
+	Kind string `json:"kind"`
+}
+
+// RunCmdSyncConflicts lists the conflicts of the sync sessions of the environment and resolves them by keeping the
+// host or the container side. The conflicts are only kept for review in two-way-safe sync mode, in the other modes
+// they're resolved by mutagen.
+func (c *Client) RunCmdSyncConflicts(cmd *cmdpkg.Command) error {
+	var (
+		keep, _ = cmd.Flags().GetString("keep")
+		list, _ = cmd.Flags().GetBool("list")
+	)
+
+	if keep != "" && keep != syncConflictHost && keep != syncConflictContainer {
+		return ErrUnknownSyncConflictSide(keep)
+	}
+
+	err := c.CheckAndInstallMutagen()
+	if err != nil {
+		return fmt.Errorf("cannot check mutagen installation: %w", err)
+	}
+
+	conflicts, err := c.syncConflicts()
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		log.Println("No sync conflicts found.")
+
+		return nil
+	}
+
+	var (
+		reader   = bufio.NewReader(os.Stdin)
+		resolved int
+	)
+
+	for i, conflict := range conflicts {
+		fmt.Printf("Conflict %d/%d: %s\n%s", i+1, len(conflicts), conflict.Root, formatSyncConflict(conflict))
+
+		if list {
+			continue
+		}
+
+		side := keep
+		if side == "" {
+			side = askSyncConflictSide(reader)
+		}
+
+		if side == "" {
+			log.Printf("Skipping %s.", conflict.Root)
+
+			continue
+		}
+
+		err = c.resolveSyncConflict(conflict.Root, side)
+		if err != nil {
+			return fmt.Errorf("cannot resolve the conflict of %s: %w", conflict.Root, err)
+		}
+
+		log.Printf("Kept the %s version of %s.", side, conflict.Root)
+
+		resolved++
+	}
+
+	if resolved == 0 {
+		return nil
+	}
+
+	// the resolved conflicts disappear when the sessions are synchronized again
+	return c.RunCmdSyncFlush()
+}
+
+// syncConflicts returns the conflicts of the sync sessions of the environment.
+func (c *Client) syncConflicts() ([]syncConflict, error) {
+	out, err := c.Shell.RunCommand(
+		[]string{
+			c.mutagenCommand(), "sync", "list", "--label-selector",
+			fmt.Sprintf("%s-sync=%s", c.AppName(), c.EnvName()),
+			"--template", "{{ json . }}",
+		},
+		shell.WithCatchOutput(true),
+		shell.WithSuppressOutput(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list mutagen sync sessions: %w: %s", err, out)
+	}
+
+	return parseSyncConflicts(out)
+}
+
+// parseSyncConflicts returns the conflicts of the sync sessions from the json output of mutagen sync list.
+func parseSyncConflicts(out []byte) ([]syncConflict, error) {
+	var sessions []syncSession
+
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	err := json.Unmarshal(out, &sessions)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse mutagen sync sessions: %w", err)
+	}
+
+	var conflicts []syncConflict
+	for _, session := range sessions {
+		conflicts = append(conflicts, session.Conflicts...)
+	}
+
+	return conflicts, nil
+}
+
+// formatSyncConflict returns the changes of the conflict on both sides.
+func formatSyncConflict(conflict syncConflict) string {
+	var b strings.Builder
+
+	for _, side := range []struct {
+		name    string
+		changes []syncChange
+	}{
+		{syncConflictHost, conflict.AlphaChanges},
+		{syncConflictContainer, conflict.BetaChanges},
+	} {
+		for _, change := range side.changes {
+			fmt.Fprintf(&b, "  %-9s %s %s\n", side.name+":", syncChangeAction(change), change.Path)
+		}
+	}
+
+	return b.String()
+}
+
+// syncChangeAction returns the description of the change, eg. "modified file".
+func syncChangeAction(change syncChange) string {
+	switch {
+	case change.Old == nil && change.New != nil:
+		return "created " + change.New.Kind
+	case change.Old != nil && change.New == nil:
+		return "deleted " + change.Old.Kind
+	case change.New != nil:
+		return "modified " + change.New.Kind
+	default:
+		return "changed"
+	}
+}
+
+// askSyncConflictSide asks which side of the conflict is kept. It returns an empty string if the conflict is skipped.
+func askSyncConflictSide(r *bufio.Reader) string {
+	for {
+		fmt.Print("Keep the (h)ost or the (c)ontainer version, or (s)kip? ")
+
+		answer, err := r.ReadString('\n')
+		if err != nil && (err != io.EOF || answer == "") {
+			return ""
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "h", "host":
+			return syncConflictHost
+		case "c", "container":
+			return syncConflictContainer
+		case "s", "skip":
+			return ""
+		}
+	}
+}
+
+// resolveSyncConflict replaces the root of the conflict on the other side with the version of the kept side. If the
+// root doesn't exist on the kept side, it's removed from the other side.
+func (c *Client) resolveSyncConflict(root, side string) error {
+	container, err := c.Docker.RunningEnvServiceContainer(c.SyncedContainer())
+	if err != nil {
+		return fmt.Errorf("cannot lookup synced container: %w", err)
+	}
+
+	var (
+		hostPath      = filepath.Join(c.Cwd(), c.WebRoot(), filepath.FromSlash(root))
+		containerPath = path.Join(c.SyncedDir(), root)
+	)
+
+	if side == syncConflictHost {
+		err = cmdpkg.Cmnd("docker", "exec", container.ID, "rm", "-rf", containerPath).Run()
+		if err != nil {
+			return fmt.Errorf("cannot remove %s from the container: %w", containerPath, err)
+		}
+
+		if !util.FileExists(hostPath) {
+			return nil
+		}
+
+		out, err := cmdpkg.Cmnd("docker", "cp", hostPath, container.ID+":"+containerPath).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot copy %s to the container: %w: %s", hostPath, err, out)
+		}
+
+		// the copied files are owned by root, they're handed over to the user of the container (the ids of the
+		// www-data user are mapped to REWARD_UID and REWARD_GID)
+		out, err = cmdpkg.Cmnd("docker", "exec", "--user", "root", container.ID,
+			"chown", "-R", fmt.Sprintf("%d:%d", c.UID(), c.GID()), containerPath,
+		).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot change the owner of %s in the container: %w: %s", containerPath, err, out)
+		}
+
+		return nil
+	}
+
+	err = util.FS.RemoveAll(hostPath)
+	if err != nil {
+		return fmt.Errorf("cannot remove %s: %w", hostPath, err)
+	}
+
+	if cmdpkg.Cmnd("docker", "exec", container.ID, "test", "-e", containerPath).Run() != nil {
+		return nil
+	}
+
+	out, err := cmdpkg.Cmnd("docker", "cp", container.ID+":"+containerPath, hostPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot copy %s from the container: %w: %s", containerPath, err, out)
+	}
+
+	return nil
+}
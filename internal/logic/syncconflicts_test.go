@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SyncConflictsTestSuite struct {
+	suite.Suite
+}
+
+func TestSyncConflictsTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncConflictsTestSuite))
+}
+
+func (suite *SyncConflictsTestSuite) TestParseSyncConflicts() {
+	out := []byte(`[
+  {"identifier": "sync_1", "conflicts": [{
+    "root": "app/etc/config.php",
+    "alphaChanges": [{"path": "app/etc/config.php", "old": {"kind": "file"}, "new": {"kind": "file"}}],
+    "betaChanges": [{"path": "app/etc/config.php", "old": {"kind": "file"}}]
+  }]},
+  {"identifier": "sync_2"}
+]`)
+
+	got, err := parseSyncConflicts(out)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), got, 1)
+	assert.Equal(suite.T(), "app/etc/config.php", got[0].Root)
+	assert.Equal(suite.T(),
+		"  host:     modified file app/etc/config.php\n  container: deleted file app/etc/config.php\n",
+		formatSyncConflict(got[0]),
+	)
+
+	got, err = parseSyncConflicts([]byte("\n"))
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), got)
+
+	_, err = parseSyncConflicts([]byte("Error: unable to connect to daemon"))
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SyncConflictsTestSuite) TestSyncProtectedIgnores() {
+	assert.Equal(suite.T(),
+		[]string{"*", "!/app", "!/app/etc", "!/app/etc/env.php", "!/.env", "!/app/etc/config.php"},
+		syncProtectedIgnores([]string{"app/etc/env.php", ".env", "app/etc/config.php"}),
+	)
+}
@@ -0,0 +1,180 @@
+package logic
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/templates"
+)
+
+// traefikReloadTimeout is the time traefik has to load the changed dynamic configuration.
+const traefikReloadTimeout = 10 * time.Second
+
+// traefikDynamicConfigDir is the directory watched by the file provider of traefik inside the container.
+const traefikDynamicConfigDir = "/etc/traefik/dynamic"
+
+// ErrTraefikConfigNotLoaded occurs when traefik doesn't load the dynamic configuration in time.
+var ErrTraefikConfigNotLoaded = func(timeout time.Duration, err error) error {
+	return fmt.Errorf("traefik didn't load the dynamic configuration in %s: %w", timeout, err)
+}
+
+// traefikHTTPClient returns a client which sends the requests to traefik directly (using the published https port),
+// so the requests don't depend on the DNS resolution of the domains.
+func (c *Client) traefikHTTPClient(serverName string, timeout time.Duration) *http.Client {
+	address := net.JoinHostPort("127.0.0.1", c.TraefikHTTPSPort())
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, address)
+			},
+			TLSClientConfig: &tls.Config{
+				ServerName:         serverName,
+				InsecureSkipVerify: true, //nolint:gosec
+			},
+		},
+	}
+}
+
+// reloadTraefikDynamicConfig regenerates the dynamic configuration of traefik (eg. the certificates of the domains)
+// and waits until traefik loads it, without restarting traefik (which would drop the routing of every environment
+// briefly). If traefik is not running, or it was created before the configuration directory was watched, traefik is
+// brought up (recreated) instead.
+func (c *Client) reloadTraefikDynamicConfig() error {
+	traefik, err := c.Docker.ServiceContainer(c.AppName(), "traefik")
+	if err != nil || !traefik.Running() || !traefikWatchesDynamicConfig(traefik.Mounts) {
+		log.Debugln("Traefik doesn't watch the dynamic configuration, bringing it up...")
+
+		return c.RunCmdSvc([]string{"up", "traefik"})
+	}
+
+	marker, err := c.generateTraefikDynamicConfig()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Waiting for traefik to load the dynamic configuration...")
+
+	err = c.waitForTraefikMiddleware(marker, traefikReloadTimeout)
+	if err != nil {
+		return err
+	}
+
+	log.Println("...traefik loaded the dynamic configuration.")
+
+	return nil
+}
+
+// generateTraefikDynamicConfig generates the dynamic configuration of traefik with the dashboard credentials and
+// returns the name of its marker middleware.
+func (c *Client) generateTraefikDynamicConfig() (string, error) {
+	credentials, err := c.traefikCredentials()
+	if err != nil {
+		return "", err
+	}
+
+	marker, err := templates.New().SvcGenerateTraefikDynamicConfig(
+		c.ServiceDomain(),
+		[]string{credentials.Username + ":" + credentials.Hash},
+		c.traefikDashboardSourceRange(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("cannot generate traefik dynamic config: %w", err)
+	}
+
+	return marker, nil
+}
+
+// traefikDashboardSourceRange returns the IP ranges the traefik dashboard and API can be reached from: the loopback,
+// the docker network of the common services (the requests to the published ports come from its gateway) and the
+// additional ranges of reward_traefik_dashboard_allowed_ips. The LAN is not allowed by default.
+func (c *Client) traefikDashboardSourceRange() []string {
+	sourceRange := []string{"127.0.0.0/8", "::1/128"}
+
+	if c.Docker != nil {
+		subnets, err := c.Docker.NetworkSubnets(c.AppName())
+		if err != nil {
+			log.Debugf("Cannot determine the subnet of the %s network: %s", c.AppName(), err)
+		}
+
+		sourceRange = append(sourceRange, subnets...)
+	}
+
+	return append(sourceRange, c.TraefikDashboardAllowedIPs()...)
+}
+
+// traefikCredentials returns the basic auth credentials of the traefik dashboard and API.
+func (c *Client) traefikCredentials() (*serviceCredentials, error) {
+	return c.serviceCredentials(c.TraefikCredentialsFile(), c.AppName())
+}
+
+// waitForTraefikMiddleware polls the traefik API until the middleware of the file provider is loaded.
+func (c *Client) waitForTraefikMiddleware(name string, timeout time.Duration) error {
+	credentials, err := c.traefikCredentials()
+	if err != nil {
+		return err
+	}
+
+	var (
+		domain = fmt.Sprintf("traefik.%s", c.ServiceDomain())
+		client = c.traefikHTTPClient(domain, time.Second)
+		url    = fmt.Sprintf("https://%s/api/http/middlewares/%s@file", domain, name)
+		start  = time.Now()
+	)
+
+	for {
+		err := traefikAPIGet(client, url, credentials)
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(start) > timeout {
+			return ErrTraefikConfigNotLoaded(timeout, err)
+		}
+
+		log.Debugf("Traefik didn't load the dynamic configuration yet: %s", err)
+
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func traefikAPIGet(client *http.Client, url string, credentials *serviceCredentials) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil) //nolint:noctx
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+
+	req.SetBasicAuth(credentials.Username, credentials.Password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach traefik api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from traefik api: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// traefikWatchesDynamicConfig returns true if the dynamic configuration directory is mounted in the traefik
+// container. The containers created by the previous versions mount a single file, which is not reloaded reliably.
+func traefikWatchesDynamicConfig(mounts []docker.Mount) bool {
+	for _, m := range mounts {
+		if m.Destination == traefikDynamicConfigDir {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package logic
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type TraefikTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TraefikTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestTraefikTestSuite(t *testing.T) {
+	suite.Run(t, new(TraefikTestSuite))
+}
+
+func (suite *TraefikTestSuite) TestWaitForTraefikMiddleware() {
+	var (
+		requests int32
+		password string
+	)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "reward" || pass != password {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		// The middleware appears after the first poll, like after a reload.
+		if r.Host != "traefik.reward.test" || r.URL.Path != "/api/http/middlewares/reward-config-abc@file" ||
+			atomic.AddInt32(&requests, 1) == 1 {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.NoError(suite.T(), err)
+
+	c := newTestClient(map[string]interface{}{
+		"reward_home_dir":           "/home/test/.reward",
+		"reward_service_domain":     "reward.test",
+		"reward_traefik_https_port": port,
+	})
+
+	credentials, err := c.traefikCredentials()
+	assert.NoError(suite.T(), err)
+
+	password = credentials.Password
+
+	assert.NoError(suite.T(), c.waitForTraefikMiddleware("reward-config-abc", 5*time.Second))
+	assert.Error(suite.T(), c.waitForTraefikMiddleware("reward-config-missing", 300*time.Millisecond))
+}
+
+func (suite *TraefikTestSuite) TestTraefikWatchesDynamicConfig() {
+	assert.True(suite.T(), traefikWatchesDynamicConfig([]docker.Mount{
+		{Type: "bind", Destination: "/etc/traefik/traefik.yml"},
+		{Type: "bind", Destination: "/etc/traefik/dynamic"},
+	}))
+	assert.False(suite.T(), traefikWatchesDynamicConfig([]docker.Mount{
+		{Type: "bind", Destination: "/etc/traefik/dynamic.yml"},
+	}))
+}
+
+func (suite *TraefikTestSuite) TestTraefikCredentials() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/test/.reward"})
+
+	credentials, err := c.traefikCredentials()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "reward", credentials.Username)
+	assert.Len(suite.T(), credentials.Password, 20)
+	assert.NoError(suite.T(), bcrypt.CompareHashAndPassword([]byte(credentials.Hash), []byte(credentials.Password)))
+
+	// The stored credentials are reused.
+	again, err := c.traefikCredentials()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), credentials, again)
+}
+
+func (suite *TraefikTestSuite) TestTraefikDashboardSourceRange() {
+	c := newTestClient(map[string]interface{}{"reward_traefik_dashboard_allowed_ips": []string{"192.168.1.0/24"}})
+	c.Docker = docker.NewClientWithAPI(&docker.Fake{Networks: []types.NetworkResource{
+		{Name: "reward", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}}}},
+		{Name: "reward-other", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.19.0.0/16"}}}},
+	}})
+
+	assert.Equal(suite.T(),
+		[]string{"127.0.0.0/8", "::1/128", "172.18.0.0/16", "192.168.1.0/24"},
+		c.traefikDashboardSourceRange(),
+	)
+}
@@ -0,0 +1,171 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/shell"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+const (
+	// traefikStandbyName is the name of the traefik instance which serves the requests while traefik is recreated.
+	traefikStandbyName = "traefik-standby"
+	// traefikStartTimeout is the time a traefik instance has to become healthy.
+	traefikStartTimeout = 30 * time.Second
+	// traefikStopTimeout is the time the standby traefik instance has to finish the requests in progress.
+	traefikStopTimeout = 10 * time.Second
+)
+
+// ErrTraefikNotHealthy occurs when a traefik instance doesn't become healthy in time.
+var ErrTraefikNotHealthy = func(name string, timeout time.Duration, state string) error {
+	return fmt.Errorf("%s didn't become healthy in %s (state: %s)", name, timeout, state)
+}
+
+// runCmdSvcRollingRestart restarts the common services if the rolling restart of traefik is enabled. Traefik is
+// restarted without downtime (see rollingRestartTraefik), the other services are restarted by docker-compose. The
+// port proxy is restarted only if it's passed explicitly, as it interrupts the connections.
+func (c *Client) runCmdSvcRollingRestart(args []string) error {
+	var (
+		flags    = make([]string, 0)
+		services = make([]string, 0)
+	)
+
+	for _, arg := range args {
+		switch {
+		case arg == "restart":
+		case strings.HasPrefix(arg, "-"):
+			flags = append(flags, arg)
+		default:
+			services = append(services, arg)
+		}
+	}
+
+	restartTraefik := len(services) == 0 || util.ContainsString(services, "traefik")
+
+	others := util.RemoveStringFromSlice(services, "traefik")
+	if len(services) == 0 {
+		containers, err := c.Docker.ContainerDetailsByLabel(fmt.Sprintf("%s=%s", c.LabelEnvName(), c.AppName()))
+		if err != nil {
+			return fmt.Errorf("cannot get common services: %w", err)
+		}
+
+		for _, container := range containers {
+			if container.Service != "" && container.Service != "traefik" && container.Service != "traefik-proxy" {
+				others = append(others, container.Service)
+			}
+		}
+	}
+
+	if len(others) > 0 {
+		err := c.RunCmdSvcDockerCompose(
+			append(append([]string{"restart"}, flags...), others...), shell.WithCatchOutput(false),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !restartTraefik {
+		return nil
+	}
+
+	return c.rollingRestartTraefik()
+}
+
+// rollingRestartTraefik recreates traefik without downtime. A copy of the running traefik (traefik-standby) is
+// started first, the port proxy forwards the connections to both instances (using the traefik-upstream network
+// alias). When the standby is healthy, traefik is recreated with the current configuration, then the standby is
+// retired.
+func (c *Client) rollingRestartTraefik() error {
+	traefik, err := c.Docker.ServiceContainer(c.AppName(), "traefik")
+	if err != nil || !traefik.Running() {
+		log.Debugln("Traefik is not running, bringing it up...")
+
+		return c.RunCmdSvcDockerCompose([]string{"up", "--detach", "traefik"}, shell.WithCatchOutput(false))
+	}
+
+	// a standby left behind by an interrupted restart
+	if standby, err := c.Docker.ContainerDetails(traefikStandbyName); err == nil {
+		err = c.Docker.RemoveContainer(standby.ID, traefikStopTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Println("Starting standby traefik...")
+
+	standbyID, err := c.Docker.CloneContainer(traefik.ID, traefikStandbyName)
+	if err == nil {
+		err = c.waitForHealthyContainer(standbyID, traefikStandbyName)
+	}
+
+	if err != nil {
+		if standbyID != "" {
+			_ = c.Docker.RemoveContainer(standbyID, traefikStopTimeout)
+		}
+
+		return fmt.Errorf("cannot start standby traefik: %w", err)
+	}
+
+	log.Println("Recreating traefik...")
+
+	err = c.RunCmdSvcDockerCompose(
+		[]string{"up", "--detach", "--no-deps", "--force-recreate", "traefik"}, shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return err
+	}
+
+	traefik, err = c.Docker.ServiceContainer(c.AppName(), "traefik")
+	if err != nil {
+		return err
+	}
+
+	err = c.waitForHealthyContainer(traefik.ID, "traefik")
+	if err != nil {
+		return err
+	}
+
+	// the recreated traefik has to join the networks of the environments before the standby is retired
+	err = c.connectPeeredServices()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Retiring standby traefik...")
+
+	err = c.Docker.RemoveContainer(standbyID, traefikStopTimeout)
+	if err != nil {
+		return err
+	}
+
+	log.Println("...traefik restarted.")
+
+	return nil
+}
+
+// waitForHealthyContainer polls the container until its healthcheck passes.
+func (c *Client) waitForHealthyContainer(id, name string) error {
+	start := time.Now()
+
+	for {
+		container, err := c.Docker.ContainerDetails(id)
+		if err != nil {
+			return err
+		}
+
+		if container.Running() && container.Health == "healthy" {
+			return nil
+		}
+
+		if time.Since(start) > traefikStartTimeout || (!container.Running() && container.State != "created") {
+			return ErrTraefikNotHealthy(name, traefikStartTimeout, strings.TrimSpace(container.State+" "+container.Health))
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+}
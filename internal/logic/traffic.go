@@ -0,0 +1,347 @@
+package logic
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrTraefikAccessLogDisabled occurs when the traefik access log is disabled or not yet created.
+var ErrTraefikAccessLogDisabled = fmt.Errorf(
+	"traefik access log is not available, make sure reward_traefik_access_log is enabled " +
+		"and restart the common services using `reward svc up`",
+)
+
+//nolint:tagliatelle
+type accessLogEntry struct {
+	StartUTC         time.Time `json:"StartUTC"`
+	RouterName       string    `json:"RouterName"`
+	RequestHost      string    `json:"RequestHost"`
+	RequestMethod    string    `json:"RequestMethod"`
+	RequestPath      string    `json:"RequestPath"`
+	DownstreamStatus int       `json:"DownstreamStatus"`
+	Duration         int64     `json:"Duration"`
+	ClientHost       string    `json:"ClientHost"`
+}
+
+func (e *accessLogEntry) String() string {
+	return fmt.Sprintf("%s %-7s %d %10s %s%s",
+		e.StartUTC.Local().Format(time.RFC3339),
+		e.RequestMethod,
+		e.DownstreamStatus,
+		time.Duration(e.Duration).Round(time.Millisecond),
+		e.RequestHost,
+		e.RequestPath,
+	)
+}
+
+// RunCmdTrafficTail prints the traefik access log entries of the current environment.
+func (c *Client) RunCmdTrafficTail(cmd *cmdpkg.Command) error {
+	lines, _ := cmd.Flags().GetInt("lines")
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	f, err := c.openTraefikAccessLog()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var last []*accessLogEntry
+
+	offset, err := c.readAccessLog(f, 0, func(e *accessLogEntry) {
+		last = append(last, e)
+		if lines >= 0 && len(last) > lines {
+			last = last[1:]
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range last {
+		fmt.Println(e)
+	}
+
+	if !follow {
+		return nil
+	}
+
+	for {
+		time.Sleep(500 * time.Millisecond)
+
+		stat, err := os.Stat(c.TraefikAccessLogFile())
+		if err != nil {
+			return fmt.Errorf("cannot stat traefik access log: %w", err)
+		}
+
+		// The log file was rotated or truncated, start over from the beginning.
+		if stat.Size() < offset {
+			log.Debugln("Traefik access log truncated, reopening...")
+
+			f.Close()
+
+			f, err = c.openTraefikAccessLog()
+			if err != nil {
+				return err
+			}
+
+			offset = 0
+		}
+
+		offset, err = c.readAccessLog(f, offset, func(e *accessLogEntry) {
+			fmt.Println(e)
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// RunCmdTrafficStats prints request rates, the slowest URLs and a status code breakdown of the current environment.
+func (c *Client) RunCmdTrafficStats(cmd *cmdpkg.Command) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	top, _ := cmd.Flags().GetInt("top")
+
+	f, err := c.openTraefikAccessLog()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var (
+		from     = time.Now().Add(-since)
+		entries  []*accessLogEntry
+		statuses = make(map[int]int)
+	)
+
+	_, err = c.readAccessLog(f, 0, func(e *accessLogEntry) {
+		if since > 0 && e.StartUTC.Before(from) {
+			return
+		}
+
+		entries = append(entries, e)
+		statuses[e.DownstreamStatus]++
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		log.Printf("No requests found for environment %s.", c.EnvName())
+
+		return nil
+	}
+
+	c.trafficSummary(entries)
+	c.trafficSlowest(entries, top)
+	c.trafficStatuses(statuses, len(entries))
+
+	return nil
+}
+
+func (c *Client) trafficSummary(entries []*accessLogEntry) {
+	first, last := entries[0].StartUTC, entries[len(entries)-1].StartUTC
+
+	rate := float64(len(entries))
+	if window := last.Sub(first).Seconds(); window >= 1 {
+		rate /= window
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Environment", "Requests", "First", "Last", "Requests/s"})
+	t.AppendRow(table.Row{
+		c.EnvName(),
+		len(entries),
+		first.Local().Format(time.RFC3339),
+		last.Local().Format(time.RFC3339),
+		fmt.Sprintf("%.2f", rate),
+	})
+	t.Render()
+}
+
+func (c *Client) trafficSlowest(entries []*accessLogEntry, top int) {
+	type urlStat struct {
+		url   string
+		count int
+		total time.Duration
+		max   time.Duration
+	}
+
+	urls := make(map[string]*urlStat)
+
+	for _, e := range entries {
+		url := fmt.Sprintf("%s %s", e.RequestMethod, e.RequestPath)
+
+		s, ok := urls[url]
+		if !ok {
+			s = &urlStat{url: url}
+			urls[url] = s
+		}
+
+		d := time.Duration(e.Duration)
+		s.count++
+		s.total += d
+
+		if d > s.max {
+			s.max = d
+		}
+	}
+
+	stats := make([]*urlStat, 0, len(urls))
+	for _, s := range urls {
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].max > stats[j].max
+	})
+
+	if top > 0 && len(stats) > top {
+		stats = stats[:top]
+	}
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Slowest URLs", "Requests", "Avg", "Max"})
+
+	for _, s := range stats {
+		t.AppendRow(table.Row{
+			s.url,
+			s.count,
+			(s.total / time.Duration(s.count)).Round(time.Millisecond),
+			s.max.Round(time.Millisecond),
+		})
+	}
+
+	t.Render()
+}
+
+func (c *Client) trafficStatuses(statuses map[int]int, total int) {
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	t := c.newTable()
+	t.AppendHeader(table.Row{"Status", "Requests", "Ratio"})
+
+	for _, code := range codes {
+		t.AppendRow(table.Row{
+			code,
+			statuses[code],
+			fmt.Sprintf("%.1f%%", float64(statuses[code])*100/float64(total)),
+		})
+	}
+
+	t.Render()
+}
+
+func (c *Client) openTraefikAccessLog() (*os.File, error) {
+	if !c.TraefikAccessLog() || !util.FileExists(c.TraefikAccessLogFile()) {
+		return nil, ErrTraefikAccessLogDisabled
+	}
+
+	f, err := os.Open(c.TraefikAccessLogFile())
+	if err != nil {
+		return nil, fmt.Errorf("cannot open traefik access log: %w", err)
+	}
+
+	return f, nil
+}
+
+// traefikRouterLabelPrefix is the prefix of the docker labels which define the traefik routers.
+const traefikRouterLabelPrefix = "traefik.http.routers."
+
+// envRouters returns the names of the traefik routers defined by the labels of the running containers of the
+// environment. The names of the routers are derived from the environment name, so a prefix match would also match
+// other environments, eg. "shop-upgrade-varnish" for env "shop".
+func (c *Client) envRouters() ([]string, error) {
+	containers, err := c.Docker.RunningContainersByLabels(fmt.Sprintf("com.docker.compose.project=%s", c.EnvName()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the containers of the environment: %w", err)
+	}
+
+	var routers []string
+
+	for _, container := range containers {
+		for label := range container.Labels {
+			name, _, ok := strings.Cut(strings.TrimPrefix(label, traefikRouterLabelPrefix), ".")
+			if !strings.HasPrefix(label, traefikRouterLabelPrefix) || !ok || util.ContainsString(routers, name) {
+				continue
+			}
+
+			routers = append(routers, name)
+		}
+	}
+
+	sort.Strings(routers)
+
+	return routers, nil
+}
+
+// isEnvRouter returns true if the traefik router of the access log entry is one of the routers of the environment.
+func isEnvRouter(routers []string, routerName string) bool {
+	name, provider, _ := strings.Cut(routerName, "@")
+	if provider != "" && provider != "docker" {
+		return false
+	}
+
+	return util.ContainsString(routers, name)
+}
+
+// readAccessLog reads the access log from the given offset and calls fn for every entry which belongs to one of the
+// current environment's routers. It returns the offset of the last complete line read.
+func (c *Client) readAccessLog(f io.ReadSeeker, offset int64, fn func(e *accessLogEntry)) (int64, error) {
+	_, err := f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return offset, fmt.Errorf("cannot seek traefik access log: %w", err)
+	}
+
+	routers, err := c.envRouters()
+	if err != nil {
+		return offset, err
+	}
+
+	reader := bufio.NewReader(f)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				// Partial lines are read again on the next call.
+				return offset, nil
+			}
+
+			return offset, fmt.Errorf("cannot read traefik access log: %w", err)
+		}
+
+		offset += int64(len(line))
+
+		var e accessLogEntry
+
+		err = json.Unmarshal(line, &e)
+		if err != nil {
+			log.Tracef("Skipping invalid access log line: %s", err)
+
+			continue
+		}
+
+		if !isEnvRouter(routers, e.RouterName) {
+			continue
+		}
+
+		fn(&e)
+	}
+}
@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+)
+
+type TrafficTestSuite struct {
+	suite.Suite
+}
+
+func TestTrafficTestSuite(t *testing.T) {
+	suite.Run(t, new(TrafficTestSuite))
+}
+
+func newTestClient(settings map[string]interface{}) *Client {
+	v := viper.New()
+	v.Set("app_name", "reward")
+
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	return New(&config.Config{Viper: v})
+}
+
+// trafficTestDocker returns a docker client with the running containers of the "shop" environment and its clone.
+func trafficTestDocker() *docker.Client {
+	return docker.NewClientWithAPI(&docker.Fake{Containers: []types.Container{
+		{
+			ID:    "nginx",
+			State: "running",
+			Labels: map[string]string{
+				"com.docker.compose.project":                                "shop",
+				"traefik.enable":                                            "true",
+				"traefik.http.routers.shop-nginx.rule":                      "Host(`shop.test`)",
+				"traefik.http.routers.shop-nginx.tls":                       "true",
+				"traefik.http.routers.shop-nginx-http.rule":                 "Host(`shop.test`)",
+				"traefik.http.services.shop-nginx.loadbalancer.server.port": "80",
+			},
+		},
+		{
+			ID:    "varnish",
+			State: "running",
+			Labels: map[string]string{
+				"com.docker.compose.project":             "shop",
+				"traefik.http.routers.shop-varnish.rule": "Host(`shop.test`)",
+			},
+		},
+		{
+			ID:    "upgrade-varnish",
+			State: "running",
+			Labels: map[string]string{
+				"com.docker.compose.project":                     "shop-upgrade",
+				"traefik.http.routers.shop-upgrade-varnish.rule": "Host(`shop-upgrade.test`)",
+			},
+		},
+	}})
+}
+
+func (suite *TrafficTestSuite) TestEnvRouters() {
+	c := newTestClient(map[string]interface{}{"reward_env_name": "shop"})
+	c.Docker = trafficTestDocker()
+
+	routers, err := c.envRouters()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"shop-nginx", "shop-nginx-http", "shop-varnish"}, routers)
+}
+
+func (suite *TrafficTestSuite) TestIsEnvRouter() {
+	routers := []string{"shop-nginx", "shop-varnish", "shop-varnish-http"}
+
+	tests := []struct {
+		name   string
+		router string
+		want   bool
+	}{
+		{name: "nginx", router: "shop-nginx@docker", want: true},
+		{name: "varnish http", router: "shop-varnish-http@docker", want: true},
+		{name: "without provider", router: "shop-nginx", want: true},
+		{name: "clone of the env", router: "shop-upgrade-varnish@docker", want: false},
+		{name: "other env", router: "blog-nginx@docker", want: false},
+		{name: "other provider", router: "shop-nginx@file", want: false},
+		{name: "global service", router: "mailhog@docker", want: false},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isEnvRouter(routers, tt.router))
+		})
+	}
+}
+
+func (suite *TrafficTestSuite) TestReadAccessLog() {
+	c := newTestClient(map[string]interface{}{"reward_env_name": "shop"})
+	c.Docker = trafficTestDocker()
+
+	lines := []string{
+		`{"RouterName":"shop-varnish@docker","RequestPath":"/a","DownstreamStatus":200}`,
+		`{"RouterName":"shop-upgrade-varnish@docker","RequestPath":"/b","DownstreamStatus":200}`,
+		`not json`,
+		`{"RouterName":"shop-nginx-http@docker","RequestPath":"/c","DownstreamStatus":301}`,
+	}
+	content := strings.Join(lines, "\n") + "\n" + `{"RouterName":"shop-nginx@docker","RequestPath":"/partial"`
+
+	var paths []string
+
+	offset, err := c.readAccessLog(strings.NewReader(content), 0, func(e *accessLogEntry) {
+		paths = append(paths, e.RequestPath)
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"/a", "/c"}, paths)
+	assert.Equal(suite.T(), int64(len(strings.Join(lines, "\n"))+1), offset, "partial lines should not be consumed")
+
+	paths = nil
+
+	_, err = c.readAccessLog(strings.NewReader(content), offset, func(e *accessLogEntry) {
+		paths = append(paths, e.RequestPath)
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), paths)
+}
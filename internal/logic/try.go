@@ -0,0 +1,223 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/sethvargo/go-password/password"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+var (
+	// ErrTryEnvTypeNotSupported occurs when the try command is called with an unsupported environment type.
+	ErrTryEnvTypeNotSupported = func(envType string) error {
+		return fmt.Errorf("environment type is not supported by the try command: %s", envType)
+	}
+	// ErrTryEnvTypeMissing occurs when the try command is called without an environment type.
+	ErrTryEnvTypeMissing = fmt.Errorf("environment type is required (eg. magento2)")
+)
+
+// tryEnvironment is an ephemeral environment created by the try command.
+type tryEnvironment struct {
+	Name    string    `json:"name"`
+	Dir     string    `json:"dir"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Created time.Time `json:"created"`
+}
+
+// RunCmdTry provisions a throwaway environment in a temporary directory, or removes the throwaway environments if
+// --cleanup is set.
+func (c *Client) RunCmdTry(cmd *cmdpkg.Command, args []string) error {
+	if cleanup, _ := cmd.Flags().GetBool("cleanup"); cleanup {
+		return c.cleanupTryEnvironments()
+	}
+
+	if len(args) == 0 {
+		return ErrTryEnvTypeMissing
+	}
+
+	if args[0] != "magento2" {
+		return ErrTryEnvTypeNotSupported(args[0])
+	}
+
+	magentoVersion, _ := cmd.Flags().GetString("version")
+	if magentoVersion == "" {
+		magentoVersion = c.VersionMatrix().Magento2.Default
+	}
+
+	v, err := version.NewVersion(magentoVersion)
+	if err != nil {
+		return fmt.Errorf("invalid magento version: %w", err)
+	}
+
+	suffix, err := password.Generate(6, 2, 0, true, true)
+	if err != nil {
+		return fmt.Errorf("cannot generate environment name: %w", err)
+	}
+
+	adminPassword, err := password.Generate(16, 2, 0, false, false)
+	if err != nil {
+		return fmt.Errorf("cannot generate admin password: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", fmt.Sprintf("%s-try-", c.AppName()))
+	if err != nil {
+		return fmt.Errorf("cannot create temporary directory: %w", err)
+	}
+
+	env := tryEnvironment{
+		Name:    "try-" + suffix,
+		Dir:     dir,
+		Type:    args[0],
+		Version: magentoVersion,
+		Created: time.Now(),
+	}
+
+	// The environment is recorded first, so it can be cleaned up even if the bootstrap fails.
+	err = c.addTryEnvironment(env)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Creating environment %s in %s...", env.Name, dir)
+
+	err = c.runSelfInDir(dir, nil, "env-init", env.Name, "--environment-type", env.Type)
+	if err != nil {
+		return fmt.Errorf("cannot initialize environment: %w", err)
+	}
+
+	values := [][2]string{{fmt.Sprintf("%s_MAGENTO_ADMIN_PASSWORD", strings.ToUpper(c.AppName())), adminPassword}}
+
+	services := c.VersionMatrix().MagentoServices(v)
+	for _, key := range sortedKeys(services) {
+		values = append(values, [2]string{key, services[key]})
+	}
+
+	err = setEnvFileValues(filepath.Join(dir, ".env"), values)
+	if err != nil {
+		return err
+	}
+
+	err = c.runSelfInDir(
+		dir, nil, "bootstrap", "--assume-yes", "--magento-version", magentoVersion, "--with-sampledata", "--disable-tfa",
+	)
+	if err != nil {
+		return fmt.Errorf("cannot bootstrap environment: %w", err)
+	}
+
+	log.Println("...environment created.")
+	log.Printf("Base Url: https://%s.test/", env.Name)
+	log.Printf("Backend Url: https://%s.test/%s", env.Name, c.MagentoBackendFrontname())
+	log.Println("Admin user: localadmin")
+	log.Printf("Admin password: %s", adminPassword)
+	log.Printf("Directory: %s", dir)
+	log.Printf("Run `%s try --cleanup` to remove the environment.", c.AppName())
+
+	return nil
+}
+
+// cleanupTryEnvironments stops and removes the throwaway environments (including their volumes and directories).
+func (c *Client) cleanupTryEnvironments() error {
+	envs, err := c.tryEnvironments()
+	if err != nil {
+		return err
+	}
+
+	if len(envs) == 0 {
+		log.Println("There are no environments to remove.")
+
+		return nil
+	}
+
+	remaining := make([]tryEnvironment, 0)
+
+	for _, env := range envs {
+		log.Printf("Removing environment %s...", env.Name)
+
+		if util.FileExists(filepath.Join(env.Dir, ".env")) {
+			err = c.runSelfInDir(env.Dir, nil, "env", "down", "--volumes", "--remove-orphans")
+			if err != nil {
+				log.Warnf("Cannot remove the containers of environment %s: %s", env.Name, err)
+
+				remaining = append(remaining, env)
+
+				continue
+			}
+		}
+
+		err = os.RemoveAll(env.Dir)
+		if err != nil {
+			log.Warnf("Cannot remove the directory of environment %s: %s", env.Name, err)
+
+			remaining = append(remaining, env)
+
+			continue
+		}
+
+		err = c.unregisterEnvironment(env.Dir)
+		if err != nil {
+			log.Warnf("Cannot unregister environment %s: %s", env.Name, err)
+		}
+
+		log.Printf("...environment %s removed.", env.Name)
+	}
+
+	return c.writeTryEnvironments(remaining)
+}
+
+func (c *Client) tryEnvironments() ([]tryEnvironment, error) {
+	content, err := util.FS.ReadFile(c.TryEnvironmentsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot read try environments: %w", err)
+	}
+
+	var envs []tryEnvironment
+
+	err = json.Unmarshal(content, &envs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse try environments: %w", err)
+	}
+
+	return envs, nil
+}
+
+func (c *Client) addTryEnvironment(env tryEnvironment) error {
+	envs, err := c.tryEnvironments()
+	if err != nil {
+		return err
+	}
+
+	return c.writeTryEnvironments(append(envs, env))
+}
+
+func (c *Client) writeTryEnvironments(envs []tryEnvironment) error {
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Created.Before(envs[j].Created)
+	})
+
+	content, err := json.MarshalIndent(envs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal try environments: %w", err)
+	}
+
+	err = util.CreateDirAndWriteToFile(content, c.TryEnvironmentsFile())
+	if err != nil {
+		return fmt.Errorf("cannot write try environments: %w", err)
+	}
+
+	return nil
+}
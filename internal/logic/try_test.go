@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type TryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TryTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	util.FS = config.FS
+}
+
+func TestTryTestSuite(t *testing.T) {
+	suite.Run(t, new(TryTestSuite))
+}
+
+func (suite *TryTestSuite) TestTryEnvironments() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/test/.reward"})
+
+	envs, err := c.tryEnvironments()
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), envs, "missing state file should mean no environments")
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := tryEnvironment{Name: "try-a", Dir: "/tmp/a", Type: "magento2", Version: "2.4.6", Created: created}
+	second := tryEnvironment{
+		Name: "try-b", Dir: "/tmp/b", Type: "magento2", Version: "2.4.7", Created: created.Add(time.Hour),
+	}
+
+	assert.NoError(suite.T(), c.addTryEnvironment(second))
+	assert.NoError(suite.T(), c.addTryEnvironment(first))
+
+	envs, err = c.tryEnvironments()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []tryEnvironment{first, second}, envs, "environments should be ordered by creation time")
+}
+
+func (suite *TryTestSuite) TestTryEnvironmentsInvalidState() {
+	c := newTestClient(map[string]interface{}{"reward_home_dir": "/home/test/.reward"})
+
+	_ = config.FS.WriteFile(c.TryEnvironmentsFile(), []byte("{"), 0o640)
+
+	_, err := c.tryEnvironments()
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,271 @@
+package logic
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	cryptopkg "github.com/rewardenv/reward/internal/crypto"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrTunnelDisabled occurs when a tunnel command runs but the tunnel service is disabled.
+var ErrTunnelDisabled = fmt.Errorf("the tunnel service is disabled")
+
+// tunnelHostKeyTypes are the types of the host keys generated by the tunnel container.
+var tunnelHostKeyTypes = []string{"rsa", "ecdsa", "ed25519"}
+
+// tunnelHostKeysTimeout is the time to wait for the tunnel container to generate its host keys.
+const tunnelHostKeysTimeout = 30 * time.Second
+
+// RunCmdTunnelRotateKeys regenerates the host keys of the tunnel container (and the ssh client key unless
+// --host-keys-only is set), recreates the tunnel container and updates the managed known_hosts entries.
+func (c *Client) RunCmdTunnelRotateKeys(cmd *cmdpkg.Command) error {
+	if !c.TunnelEnabled() {
+		return ErrTunnelDisabled
+	}
+
+	hostKeysOnly, _ := cmd.Flags().GetBool("host-keys-only")
+
+	log.Println("Removing tunnel host keys...")
+
+	// The host keys are owned by root on linux, so they are removed from a one-off tunnel container.
+	err := c.RunCmdSvcDockerCompose(
+		[]string{"run", "--rm", "--no-deps", "--entrypoint", "sh", "tunnel", "-c", "rm -f /etc/ssh/keys/ssh_host_*"},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot remove tunnel host keys: %w", err)
+	}
+
+	log.Println("...tunnel host keys removed.")
+
+	if !hostKeysOnly {
+		log.Println("Generating tunnel SSH key...")
+
+		err = c.generateTunnelSSHKey()
+		if err != nil {
+			return err
+		}
+
+		log.Println("...tunnel SSH key generated.")
+	}
+
+	// Recreating the container generates new host keys, and svc up updates the known_hosts entries.
+	err = c.RunCmdSvc([]string{"up", "--force-recreate", "tunnel"})
+	if err != nil {
+		return fmt.Errorf("cannot recreate tunnel container: %w", err)
+	}
+
+	return nil
+}
+
+// generateTunnelSSHKey (re)generates the ssh client key which is authorized in the tunnel container.
+func (c *Client) generateTunnelSSHKey() error {
+	keyPath := filepath.Join(c.TunnelDir(), "ssh_key")
+
+	// On linux, if we want to reinstall the pubfile we have to revert its permissions first
+	err := c.chownTunnelSSHPublicKey(os.Getuid())
+	if err != nil {
+		return err
+	}
+
+	err = cryptopkg.New(c.Config).GenerateSSHKeys(2048, keyPath)
+	if err != nil {
+		return fmt.Errorf("cannot generate tunnel ssh key: %w", err)
+	}
+
+	return c.chownTunnelSSHPublicKey(0)
+}
+
+// chownTunnelSSHPublicKey changes the owner of the tunnel ssh public key on linux. Since bind mounts are native on
+// linux, the public key has to be owned by root to be used as the authorized_keys file in the tunnel container.
+func (c *Client) chownTunnelSSHPublicKey(uid int) error {
+	path := filepath.Join(c.TunnelDir(), "ssh_key.pub")
+
+	if runtime.GOOS != "linux" || !util.FileExists(path) {
+		return nil
+	}
+
+	cmd := exec.Command("sudo", "chown", "-v", fmt.Sprintf("%d:%d", uid, 0), path)
+
+	log.Debugf("Running command: %s", cmd)
+
+	out, err := cmd.CombinedOutput()
+
+	log.Debugf("Command output: %s", string(out))
+
+	if err != nil {
+		return fmt.Errorf("cannot change the owner of the tunnel ssh public key: %w", err)
+	}
+
+	return nil
+}
+
+// updateTunnelKnownHosts waits for the host keys of the tunnel container and replaces the managed entries of the
+// tunnel in the user's known_hosts file.
+func (c *Client) updateTunnelKnownHosts() error {
+	keys, err := c.waitForTunnelHostKeys(tunnelHostKeysTimeout)
+	if err != nil {
+		return err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+
+	path := filepath.Join(home, ".ssh", "known_hosts")
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read known_hosts file: %w", err)
+	}
+
+	updated := replaceKnownHosts(string(content), c.tunnelKnownHostsAddresses(), keys, c.tunnelKnownHostsMarker())
+	if updated == string(content) {
+		return nil
+	}
+
+	log.Debugf("Updating tunnel entries in known_hosts file: %s...", path)
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("cannot create ssh directory: %w", err)
+	}
+
+	err = os.WriteFile(path, []byte(updated), 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write known_hosts file: %w", err)
+	}
+
+	log.Debugln("...known_hosts file updated.")
+
+	return nil
+}
+
+// waitForTunnelHostKeys returns the public host keys of the tunnel container when all of them are generated.
+func (c *Client) waitForTunnelHostKeys(timeout time.Duration) ([]ssh.PublicKey, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		keys := make([]ssh.PublicKey, 0, len(tunnelHostKeyTypes))
+
+		for _, t := range tunnelHostKeyTypes {
+			content, err := os.ReadFile(
+				filepath.Join(c.TunnelHostKeysDir(), fmt.Sprintf("ssh_host_%s_key.pub", t)),
+			)
+			if err != nil {
+				break
+			}
+
+			key, _, _, _, err := ssh.ParseAuthorizedKey(content)
+			if err != nil {
+				break
+			}
+
+			keys = append(keys, key)
+		}
+
+		if len(keys) == len(tunnelHostKeyTypes) {
+			return keys, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("cannot find tunnel host keys in %s", c.TunnelHostKeysDir())
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// tunnelKnownHostsAddresses returns the addresses the tunnel is reached on.
+func (c *Client) tunnelKnownHostsAddresses() []string {
+	return []string{
+		knownhosts.Normalize(net.JoinHostPort(c.TunnelHost(), c.TunnelPort())),
+		knownhosts.Normalize(net.JoinHostPort("127.0.0.1", c.TunnelPort())),
+	}
+}
+
+// tunnelKnownHostsMarker returns the comment which marks the known_hosts entries managed by the application.
+func (c *Client) tunnelKnownHostsMarker() string {
+	return fmt.Sprintf("%s-tunnel", c.AppName())
+}
+
+// replaceKnownHosts removes the entries of the addresses (plain or hashed) and the entries marked with the marker
+// from the known_hosts content, then it appends an entry marked with the marker for each key.
+func replaceKnownHosts(content string, addresses []string, keys []ssh.PublicKey, marker string) string {
+	var b strings.Builder
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" || !isManagedKnownHostsLine(line, addresses, marker) {
+			b.WriteString(line)
+		}
+	}
+
+	if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+
+	for _, key := range keys {
+		b.WriteString(fmt.Sprintf("%s %s\n", knownhosts.Line(addresses, key), marker))
+	}
+
+	return b.String()
+}
+
+func isManagedKnownHostsLine(line string, addresses []string, marker string) bool {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "@") {
+		return false
+	}
+
+	if len(fields) > 3 && fields[len(fields)-1] == marker {
+		return true
+	}
+
+	for _, pattern := range strings.Split(fields[0], ",") {
+		for _, address := range addresses {
+			if pattern == address || hashedHostMatches(pattern, address) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hashedHostMatches returns true if the hashed known_hosts pattern (|1|salt|hash) matches the address.
+func hashedHostMatches(pattern, address string) bool {
+	parts := strings.Split(pattern, "|")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != "1" {
+		return false
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	hash, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(address))
+
+	return hmac.Equal(mac.Sum(nil), hash)
+}
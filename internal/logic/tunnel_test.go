@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type TunnelTestSuite struct {
+	suite.Suite
+}
+
+func TestTunnelTestSuite(t *testing.T) {
+	suite.Run(t, new(TunnelTestSuite))
+}
+
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	key, err := ssh.NewPublicKey(pub)
+	assert.NoError(t, err)
+
+	return key
+}
+
+func (suite *TunnelTestSuite) TestReplaceKnownHosts() {
+	var (
+		t         = suite.T()
+		addresses = []string{"[tunnel.reward.test]:2222", "[127.0.0.1]:2222"}
+		oldKey    = newTestHostKey(t)
+		newKey    = newTestHostKey(t)
+		otherKey  = newTestHostKey(t)
+		other     = knownhosts.Line([]string{"github.com"}, otherKey)
+	)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "empty"},
+		{name: "no trailing newline", content: other},
+		{name: "managed entry", content: other + "\n" + knownhosts.Line(addresses, oldKey) + " reward-tunnel\n"},
+		{name: "unmanaged entry", content: knownhosts.Line(addresses[:1], oldKey) + "\n" + other + "\n"},
+		{
+			name:    "hashed entry",
+			content: knownhosts.Line([]string{knownhosts.HashHostname(addresses[1])}, oldKey) + "\n" + other + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got := replaceKnownHosts(tt.content, addresses, []ssh.PublicKey{newKey}, "reward-tunnel")
+
+			assert.Equal(t, tt.content != "", strings.Contains(got, other+"\n"), "unrelated entries are kept")
+			assert.NotContains(t, got, serializeKey(oldKey))
+			assert.Contains(t, got, knownhosts.Line(addresses, newKey)+" reward-tunnel\n")
+			assert.Equal(
+				t, got, replaceKnownHosts(got, addresses, []ssh.PublicKey{newKey}, "reward-tunnel"), "idempotent",
+			)
+		})
+	}
+}
+
+func serializeKey(key ssh.PublicKey) string {
+	return strings.Fields(string(ssh.MarshalAuthorizedKey(key)))[1]
+}
@@ -0,0 +1,275 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/trash"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrVolumeInUse occurs when a volume is restored from the trash, but it's used by a container.
+var ErrVolumeInUse = func(volume string) error {
+	return fmt.Errorf("cannot restore volume %s as it's used by a container, stop the environment first", volume)
+}
+
+// RunCmdUndoLast restores the data saved to the trash by the most recent destructive operation, and removes the
+// operation from the trash.
+func (c *Client) RunCmdUndoLast() error {
+	t := c.Trash()
+
+	op, err := t.Last()
+	if errors.Is(err, trash.ErrEmpty) {
+		log.Println("There is nothing to undo.")
+
+		return nil
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	log.Printf("The following data removed by `%s %s` at %s in %s is going to be restored:",
+		c.AppName(), op.Command, op.Time.Format("2006-01-02 15:04:05"), op.Dir)
+
+	for _, entry := range op.Entries {
+		log.Printf("  - %s %s", entry.Type, entry.Name)
+	}
+
+	if !util.AskForConfirmation("Would you like to restore them?") {
+		return nil
+	}
+
+	for _, entry := range op.Entries {
+		switch entry.Type {
+		case trash.EntryFile:
+			err = op.RestoreFile(entry)
+		case trash.EntryVolume:
+			err = c.restoreVolume(op, entry)
+		default:
+			err = fmt.Errorf("unknown trash entry type: %s", entry.Type)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Restored %s %s.", entry.Type, entry.Name)
+	}
+
+	return t.Remove(op) //nolint:wrapcheck
+}
+
+// RunCmdUndoList prints the operations which can be undone, the most recent first.
+func (c *Client) RunCmdUndoList() error {
+	t := c.Trash()
+
+	_, err := t.Prune()
+	if err != nil {
+		log.Warnf("Cannot prune the trash: %s", err)
+	}
+
+	ops, err := t.List()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	tb := c.newTable()
+	tb.AppendHeader(table.Row{"Time", "Command", "Directory", "Data", "Size"})
+
+	for _, op := range ops {
+		names := make([]string, 0, len(op.Entries))
+		for _, entry := range op.Entries {
+			names = append(names, fmt.Sprintf("%s %s", entry.Type, entry.Name))
+		}
+
+		tb.AppendRow(table.Row{
+			op.Time.Format("2006-01-02 15:04:05"),
+			op.Command,
+			op.Dir,
+			strings.Join(names, "\n"),
+			units.BytesSize(float64(op.Size())),
+		})
+	}
+
+	tb.Render()
+
+	return nil
+}
+
+// trashVolumes saves the content of the volumes to the trash before they're removed. The size of the volumes is
+// checked first, the volumes which don't fit in the trash by themselves are not saved, so the older operations are not
+// removed for them. The trash is pruned afterwards.
+func (c *Client) trashVolumes(volumes []string) error {
+	t := c.Trash()
+	if !t.Enabled() || len(volumes) == 0 {
+		return nil
+	}
+
+	size, err := c.volumesSize(volumes)
+	if err != nil {
+		return fmt.Errorf("cannot move the volumes to the trash, they are not removed: %w", err)
+	}
+
+	if !t.Fits(size) {
+		log.Warnf("The volumes (%s) are larger than %s_trash_max_size, they are not moved to the trash and cannot be "+
+			"restored.", units.BytesSize(float64(size)), c.AppName())
+
+		return nil
+	}
+
+	step := c.newProgress().OutputStep("Moving the volumes to the trash")
+
+	op, err := t.Operation()
+	if err == nil {
+		for _, volume := range volumes {
+			if err = c.trashVolume(op, volume); err != nil {
+				break
+			}
+		}
+	}
+
+	err = step.Finish(err)
+	if err != nil {
+		return fmt.Errorf("cannot move the volumes to the trash, they are not removed: %w", err)
+	}
+
+	removed, err := t.Prune()
+	if err != nil {
+		log.Warnf("Cannot prune the trash: %s", err)
+	}
+
+	for _, pruned := range removed {
+		if pruned.ID == op.ID {
+			log.Warnf("The volumes are larger than %s_trash_max_size, they cannot be restored.", c.AppName())
+
+			return nil
+		}
+	}
+
+	log.Printf("The volumes are moved to the trash, run `%s undo last` to restore them.", c.AppName())
+
+	return nil
+}
+
+// volumesSize returns the size of the content of the volumes. The archives in the trash are compressed, so they're
+// not larger than the content.
+func (c *Client) volumesSize(volumes []string) (int64, error) {
+	args := []string{"run", "--rm"}
+	for i, volume := range volumes {
+		args = append(args, "-v", fmt.Sprintf("%s:/volumes/%d:ro", volume, i))
+	}
+
+	out, err := cmdpkg.Cmnd("docker", append(args, "alpine", "du", "-sk", "/volumes")...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("cannot determine the size of the volumes: %w", err)
+	}
+
+	return parseDuSize(string(out))
+}
+
+// parseDuSize returns the size in bytes from the output of du -sk.
+func parseDuSize(out string) (int64, error) {
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("cannot parse the size of the volumes: %q", out)
+	}
+
+	kib, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse the size of the volumes: %w", err)
+	}
+
+	return kib * 1024, nil
+}
+
+// trashVolume archives the content of the volume to the operation directory. The archive is written to the stdout of
+// the container, so its files are owned by the user.
+func (c *Client) trashVolume(op *trash.Operation, volume string) error {
+	labels, err := c.Docker.VolumeLabels(volume)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	entry := &trash.Entry{
+		Type:   trash.EntryVolume,
+		Name:   volume,
+		Labels: labels,
+		File:   volume + ".tar.gz",
+	}
+
+	//nolint:gosec
+	f, err := os.OpenFile(op.Path(entry.File), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot create archive of volume %s: %w", volume, err)
+	}
+
+	archive := cmdpkg.Cmnd("docker", "run", "--rm", "-v", volume+":/from:ro",
+		"alpine", "tar", "czf", "-", "-C", "/from", ".",
+	)
+	archive.Stdout = f
+	archive.Stderr = os.Stderr
+
+	err = archive.Run()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot archive volume %s: %w", volume, err)
+	}
+
+	return op.Add(entry) //nolint:wrapcheck
+}
+
+// restoreVolume creates the volume with its original labels if it doesn't exist, and replaces its content with the
+// archive saved to the trash.
+func (c *Client) restoreVolume(op *trash.Operation, entry *trash.Entry) error {
+	out, err := cmdpkg.Cmnd("docker", "ps", "-aq", "--filter", "volume="+entry.Name).Output()
+	if err != nil {
+		return fmt.Errorf("cannot list the containers of volume %s: %w", entry.Name, err)
+	}
+
+	if strings.TrimSpace(string(out)) != "" {
+		return ErrVolumeInUse(entry.Name)
+	}
+
+	if _, err = c.Docker.VolumeLabels(entry.Name); err != nil {
+		args := []string{"volume", "create"}
+		for _, key := range sortedKeys(entry.Labels) {
+			args = append(args, "--label", fmt.Sprintf("%s=%s", key, entry.Labels[key]))
+		}
+
+		err = cmdpkg.Cmnd("docker", append(args, entry.Name)...).Run()
+		if err != nil {
+			return fmt.Errorf("cannot create volume %s: %w", entry.Name, err)
+		}
+	}
+
+	//nolint:gosec
+	f, err := os.Open(op.Path(entry.File))
+	if err != nil {
+		return fmt.Errorf("cannot open archive of volume %s: %w", entry.Name, err)
+	}
+	defer f.Close()
+
+	restore := cmdpkg.Cmnd("docker", "run", "--rm", "-i", "-v", entry.Name+":/to", "alpine", "sh", "-c",
+		"find /to -mindepth 1 -delete && tar xzf - -C /to")
+	restore.Stdin = f
+	restore.Stderr = os.Stderr
+
+	err = restore.Run()
+	if err != nil {
+		return fmt.Errorf("cannot restore volume %s: %w", entry.Name, err)
+	}
+
+	return nil
+}
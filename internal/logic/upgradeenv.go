@@ -0,0 +1,359 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+)
+
+// ErrUpgradeEnvNotSupported occurs when the upgrade assistant is run in an environment which is not Magento 2.
+var ErrUpgradeEnvNotSupported = func(envType string) error {
+	return fmt.Errorf("upgrade-env supports magento2 environments only, this environment is %s", envType)
+}
+
+// ErrUpgradeEnvNotNewer occurs when the target version of the upgrade is not newer than the installed version.
+var ErrUpgradeEnvNotNewer = func(installed, target string) error {
+	return fmt.Errorf("the target version %s is not newer than the installed version %s", target, installed)
+}
+
+// ErrUpgradeEnvNoMetapackage occurs when composer.json of the project doesn't require a Magento metapackage, so the
+// version constraint cannot be bumped.
+var ErrUpgradeEnvNoMetapackage = fmt.Errorf("composer.json doesn't require a magento metapackage")
+
+// ErrUpgradeEnvFailed occurs when a step of the upgrade fails in the clone, the problems are reported.
+var ErrUpgradeEnvFailed = func(step, clone string) error {
+	return fmt.Errorf("%s failed in the clone %s, see the reported problems", step, clone)
+}
+
+// upgradeMetapackages are the composer metapackages which can be bumped by the upgrade assistant in order of
+// precedence.
+var upgradeMetapackages = []string{
+	"magento/magento-cloud-metapackage",
+	"magento/product-enterprise-edition",
+	"magento/product-community-edition",
+	"mage-os/product-community-edition",
+}
+
+// composerProblemHeader matches the header of a problem in the output of a failed composer update.
+var composerProblemHeader = regexp.MustCompile(`^\s*Problem \d+\s*$`)
+
+// composerPackageLine matches a line of composer why-not starting with a package name.
+var composerPackageLine = regexp.MustCompile(`^[a-z0-9_.-]+/[a-z0-9_.-]+\s`)
+
+// upgradeEnvReport is the result of the upgrade assistant.
+type upgradeEnvReport struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Clone    string   `json:"clone"`
+	Dir      string   `json:"dir"`
+	Failed   string   `json:"failed,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// RunCmdUpgradeEnv runs the recommended Magento upgrade workflow in a clone of the environment: the environment is
+// cloned, the service versions of the target Magento version are applied to the clone, the metapackage is bumped,
+// and composer update, setup:upgrade and setup:di:compile are run in the clone. The incompatibilities found by
+// composer and the compilation are reported. The environment itself is not changed, the clone can be promoted using
+// `env promote` when the upgrade is finished.
+func (c *Client) RunCmdUpgradeEnv(cmd *cmdpkg.Command, args []string) error {
+	if c.EnvType() != "magento2" {
+		return ErrUpgradeEnvNotSupported(c.EnvType())
+	}
+
+	target, err := version.NewVersion(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid magento version: %w", err)
+	}
+
+	info, err := c.MagentoVersionInfo()
+	if err != nil {
+		return fmt.Errorf("cannot determine the installed magento version: %w", err)
+	}
+
+	pkgs, err := c.ComposerPackages()
+	if err != nil {
+		return fmt.Errorf("cannot read composer.json: %w", err)
+	}
+
+	metapackage := upgradeMetapackage(pkgs)
+	if metapackage == "" {
+		return ErrUpgradeEnvNoMetapackage
+	}
+
+	if !target.GreaterThan(info.Version) {
+		return ErrUpgradeEnvNotNewer(info.Version.Original(), target.Original())
+	}
+
+	report := &upgradeEnvReport{From: info.Version.Original(), To: target.Original()}
+
+	report.Clone, _ = cmd.Flags().GetString("name")
+	if report.Clone == "" {
+		report.Clone = upgradeCloneName(c.rawEnvName(), target)
+	}
+
+	report.Dir, _ = cmd.Flags().GetString("dir")
+	if report.Dir == "" {
+		report.Dir = filepath.Join(filepath.Dir(c.Cwd()), report.Clone)
+	}
+
+	report.Dir, err = filepath.Abs(report.Dir)
+	if err != nil {
+		return fmt.Errorf("cannot determine absolute path for clone directory: %w", err)
+	}
+
+	log.Printf("Upgrading Magento %s to %s in the clone %s (%s)...", report.From, report.To, report.Clone, report.Dir)
+
+	err = c.upgradeEnvPrepareClone(report, info.Edition, target)
+	if err != nil {
+		return err
+	}
+
+	err = c.upgradeEnvRunSteps(report, metapackage)
+
+	c.printUpgradeEnvReport(report)
+
+	return err
+}
+
+// upgradeEnvPrepareClone clones the environment and applies the service versions of the target Magento version to
+// the clone.
+func (c *Client) upgradeEnvPrepareClone(report *upgradeEnvReport, edition string, target *version.Version) error {
+	err := c.runSelfInDir(c.Cwd(), nil, "env", "clone", report.Clone, "--dir", report.Dir)
+	if err != nil {
+		return fmt.Errorf("cannot clone environment: %w", err)
+	}
+
+	magentoVersion := target
+	if edition == "mage-os" {
+		magentoVersion = c.VersionMatrix().MageOSMagentoVersion(target)
+		if magentoVersion == nil {
+			return config.ErrUnknownMageOSVersion(target.Original())
+		}
+	}
+
+	values := [][2]string{{fmt.Sprintf("%s_MAGENTO_VERSION", strings.ToUpper(c.AppName())), target.Original()}}
+
+	services := c.VersionMatrix().MagentoServices(magentoVersion)
+	for _, key := range sortedKeys(services) {
+		values = append(values, [2]string{key, services[key]})
+	}
+
+	err = setEnvFileValues(filepath.Join(report.Dir, ".env"), values)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Applying the service versions of Magento %s to the clone...", magentoVersion.Original())
+
+	err = c.runSelfInDir(report.Dir, nil, "env", "up")
+	if err != nil {
+		return fmt.Errorf("cannot start the clone with the new service versions: %w", err)
+	}
+
+	return nil
+}
+
+// upgradeEnvRunSteps bumps the metapackage and runs composer update, setup:upgrade and setup:di:compile in the
+// clone. The problems of the failed step are added to the report.
+func (c *Client) upgradeEnvRunSteps(report *upgradeEnvReport, metapackage string) error {
+	container := c.DefaultSyncedContainer(c.EnvType())
+
+	steps := []struct {
+		name     string
+		script   string
+		problems func(out string) []string
+	}{
+		{
+			name: "composer update",
+			script: fmt.Sprintf(
+				"composer require %s:%s --no-update && composer update --with-all-dependencies",
+				metapackage, report.To,
+			),
+			problems: composerProblems,
+		},
+		{name: "setup:upgrade", script: "bin/magento setup:upgrade", problems: magentoErrors},
+		{name: "setup:di:compile", script: "bin/magento setup:di:compile", problems: magentoErrors},
+	}
+
+	for _, step := range steps {
+		log.Printf("Running %s in the clone...", step.name)
+
+		out, err := c.runSelfInDirOutput(report.Dir, "env", "exec", "-T", container, "bash", "-c", step.script)
+		if err == nil {
+			continue
+		}
+
+		report.Failed = step.name
+		report.Problems = step.problems(out)
+
+		if step.name == "composer update" {
+			whyNot, _ := c.runSelfInDirOutput(report.Dir, "env", "exec", "-T", container, "bash", "-c",
+				fmt.Sprintf("composer why-not %s %s", metapackage, report.To))
+			report.Problems = append(report.Problems, composerBlockers(whyNot)...)
+		}
+
+		return ErrUpgradeEnvFailed(step.name, report.Clone)
+	}
+
+	return nil
+}
+
+// printUpgradeEnvReport prints the result of the upgrade assistant.
+func (c *Client) printUpgradeEnvReport(report *upgradeEnvReport) {
+	if c.JSONOutput() {
+		_ = c.printJSON(report)
+
+		return
+	}
+
+	if report.Failed == "" {
+		log.Printf("...Magento %s is upgraded to %s in the clone %s (%s).", report.From, report.To, report.Clone,
+			report.Dir)
+		log.Printf("Test the clone, then run `%s env promote %s` to switch the domain to it.", c.AppName(),
+			report.Clone)
+
+		return
+	}
+
+	log.Warnf("%s failed in the clone %s (%s). Incompatibilities found:", report.Failed, report.Clone, report.Dir)
+
+	for _, problem := range report.Problems {
+		log.Warnf("  - %s", problem)
+	}
+
+	log.Printf("Fix the incompatibilities in the clone and run `%s upgrade-env` again with a new --name, or continue "+
+		"the upgrade in the clone manually.", c.AppName())
+}
+
+// runSelfInDirOutput runs the application like runSelfInDir, and returns its output as well.
+func (c *Client) runSelfInDirOutput(dir string, args ...string) (string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine executable path: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	command := cmdpkg.Cmnd(self, args...)
+	command.Dir = dir
+	command.Stdout = io.MultiWriter(os.Stdout, &out)
+	command.Stderr = io.MultiWriter(os.Stderr, &out)
+
+	err = command.Run()
+
+	return out.String(), err //nolint:wrapcheck
+}
+
+// upgradeMetapackage returns the Magento metapackage required in composer.json.
+func upgradeMetapackage(pkgs *config.ComposerPackages) string {
+	if pkgs == nil {
+		return ""
+	}
+
+	for _, name := range upgradeMetapackages {
+		if pkgs.Require[name] != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// upgradeCloneName returns the default name of the clone, eg. shop-2-4-7 for shop and 2.4.7.
+func upgradeCloneName(envName string, target *version.Version) string {
+	return fmt.Sprintf("%s-%s", envName, strings.ReplaceAll(strings.TrimPrefix(target.Original(), "v"), ".", "-"))
+}
+
+// composerProblems returns the problems listed by a failed composer update, eg. "magento/module-foo 1.0.0 requires
+// magento/framework 103.0.6 -> found magento/framework[103.0.7] but it does not match the constraint.".
+func composerProblems(out string) []string {
+	var (
+		problems  []string
+		inProblem bool
+	)
+
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case composerProblemHeader.MatchString(line):
+			inProblem = true
+		case trimmed == "":
+			inProblem = false
+		case inProblem && strings.HasPrefix(trimmed, "- "):
+			problems = append(problems, strings.TrimPrefix(trimmed, "- "))
+		}
+	}
+
+	if len(problems) == 0 {
+		return lastLines(out, 10)
+	}
+
+	return problems
+}
+
+// composerBlockers returns the packages preventing the upgrade from the output of composer why-not, eg.
+// "vendor/module-foo 1.2.0 requires magento/framework (~103.0.5)".
+func composerBlockers(out string) []string {
+	var blockers []string
+
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if composerPackageLine.MatchString(trimmed) {
+			blockers = append(blockers, strings.Join(strings.Fields(trimmed), " "))
+		}
+	}
+
+	return blockers
+}
+
+// magentoErrors returns the errors of a failed bin/magento command, eg. the classes which cannot be compiled by
+// setup:di:compile.
+func magentoErrors(out string) []string {
+	lines := strings.Split(out, "\n")
+
+	for i, line := range lines {
+		if !strings.Contains(line, "Errors during compilation") {
+			continue
+		}
+
+		var errs []string
+
+		for _, l := range lines[i+1:] {
+			if l = strings.TrimSpace(l); l != "" {
+				errs = append(errs, l)
+			}
+		}
+
+		return errs
+	}
+
+	return lastLines(out, 10)
+}
+
+// lastLines returns the last n non-empty lines of the output.
+func lastLines(out string, n int) []string {
+	var lines []string
+
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines
+}
@@ -0,0 +1,109 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+)
+
+type UpgradeEnvTestSuite struct {
+	suite.Suite
+}
+
+func TestUpgradeEnvTestSuite(t *testing.T) {
+	suite.Run(t, new(UpgradeEnvTestSuite))
+}
+
+func (suite *UpgradeEnvTestSuite) TestUpgradeCloneName() {
+	assert.Equal(suite.T(), "shop-2-4-7", upgradeCloneName("shop", version.Must(version.NewVersion("2.4.7"))))
+	assert.Equal(suite.T(), "shop-2-4-7-p1", upgradeCloneName("shop", version.Must(version.NewVersion("2.4.7-p1"))))
+}
+
+func (suite *UpgradeEnvTestSuite) TestUpgradeMetapackage() {
+	tests := []struct {
+		name    string
+		require map[string]string
+		want    string
+	}{
+		{
+			name:    "community",
+			require: map[string]string{"magento/product-community-edition": "2.4.6"},
+			want:    "magento/product-community-edition",
+		},
+		{
+			name: "cloud",
+			require: map[string]string{
+				"magento/magento-cloud-metapackage":  ">=2.4.6 <2.4.7",
+				"magento/product-enterprise-edition": "2.4.6",
+			},
+			want: "magento/magento-cloud-metapackage",
+		},
+		{
+			name:    "no metapackage",
+			require: map[string]string{"magento/framework": "103.0.6"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, upgradeMetapackage(&config.ComposerPackages{Require: tt.require}))
+		})
+	}
+}
+
+func (suite *UpgradeEnvTestSuite) TestComposerProblems() {
+	out := `Loading composer repositories with package information
+Updating dependencies
+Your requirements could not be resolved to an installable set of packages.
+
+  Problem 1
+    - vendor/module-foo 1.2.0 requires magento/framework ~103.0.5 -> found magento/framework[103.0.5] but it does` +
+		` not match the constraint.
+    - Root composer.json requires vendor/module-foo ^1.2 -> satisfiable by vendor/module-foo[1.2.0].
+
+  Problem 2
+    - vendor/module-bar 2.0.0 requires php ~8.1.0 -> your php version (8.3.4) does not satisfy that requirement.
+
+Use the option --with-all-dependencies (-W) to allow upgrades, downgrades and removals for packages currently locked.`
+
+	assert.Equal(suite.T(), []string{
+		"vendor/module-foo 1.2.0 requires magento/framework ~103.0.5 -> found magento/framework[103.0.5] but it " +
+			"does not match the constraint.",
+		"Root composer.json requires vendor/module-foo ^1.2 -> satisfiable by vendor/module-foo[1.2.0].",
+		"vendor/module-bar 2.0.0 requires php ~8.1.0 -> your php version (8.3.4) does not satisfy that requirement.",
+	}, composerProblems(out))
+
+	assert.Equal(suite.T(), []string{"Could not find package magento/product-community-edition."},
+		composerProblems("\nCould not find package magento/product-community-edition.\n"))
+}
+
+func (suite *UpgradeEnvTestSuite) TestComposerBlockers() {
+	out := `magento/product-community-edition 2.4.7 requires magento/framework (103.0.7)
+vendor/module-foo                 1.2.0 requires magento/framework (~103.0.5)
+Not finding what you were looking for? Try calling ` + "`composer update \"magento/framework:103.0.7\" --dry-run`"
+
+	assert.Equal(suite.T(), []string{
+		"magento/product-community-edition 2.4.7 requires magento/framework (103.0.7)",
+		"vendor/module-foo 1.2.0 requires magento/framework (~103.0.5)",
+	}, composerBlockers(out))
+}
+
+func (suite *UpgradeEnvTestSuite) TestMagentoErrors() {
+	out := `Compilation was started.
+Interception cache generation... 6/9 [==================>---------]  66% 1 min 372.0 MiB
+Errors during compilation:
+	Vendor\Foo\Model\Bar
+		Incompatible argument type: Required type: \Magento\Framework\Model\Context. Actual type: array;`
+
+	assert.Equal(suite.T(), []string{
+		`Vendor\Foo\Model\Bar`,
+		`Incompatible argument type: Required type: \Magento\Framework\Model\Context. Actual type: array;`,
+	}, magentoErrors(out))
+
+	assert.Equal(suite.T(), []string{"There are no commands defined in the \"setup\" namespace."},
+		magentoErrors("\n  There are no commands defined in the \"setup\" namespace.  \n\n"))
+}
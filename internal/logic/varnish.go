@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/internal/shell"
+)
+
+// RunCmdVarnishReload regenerates the VCL from the managed template and the project snippets, then compiles and
+// activates it without recreating the varnish container. The previously active VCL is discarded, so the compiled
+// VCLs of the reloads don't pile up in varnish.
+func (c *Client) RunCmdVarnishReload() error {
+	log.Println("Reloading varnish configuration...")
+
+	vclName := fmt.Sprintf("reload_%d", time.Now().Unix())
+
+	err := c.RunCmdEnvDockerCompose(
+		[]string{
+			"exec", "-T", "varnish", "sh", "-c",
+			fmt.Sprintf(
+				`VCL="${VCL_CONFIG:-/etc/varnish/default.vcl}" `+
+					`&& gomplate < /etc/varnish/default.vcl.template > "${VCL}" `+
+					`&& PREVIOUS="$(varnishadm vcl.list | awk '$1 == "active" { for (i = 2; i <= NF; i++) `+
+					`if ($i == "boot" || $i ~ /^reload_/) print $i }')" `+
+					`&& varnishadm vcl.load %[1]s "${VCL}" `+
+					`&& varnishadm vcl.use %[1]s `+
+					`&& if [ -n "${PREVIOUS}" ]; then varnishadm vcl.discard "${PREVIOUS}" >/dev/null `+
+					`|| echo "cannot discard the previous VCL ${PREVIOUS}" >&2; fi`,
+				vclName,
+			),
+		},
+		shell.WithCatchOutput(false),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot reload varnish configuration: %w", err)
+	}
+
+	log.Println("...varnish configuration reloaded.")
+
+	return nil
+}
@@ -0,0 +1,344 @@
+package logic
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrVendorVolumeDisabled occurs when the vendor directories are synced, so they don't have to be pulled.
+var ErrVendorVolumeDisabled = fmt.Errorf("the vendor directories are synced with the host, enable them to live in " +
+	"container volumes by setting REWARD_VENDOR_VOLUME=true in the .env file")
+
+// ErrInvalidVendorPath occurs when the path to pull is outside of the vendor directories.
+var ErrInvalidVendorPath = func(p string) error {
+	return fmt.Errorf("invalid path: %s, it must be a path in the vendor or node_modules directory", p)
+}
+
+// ErrVendorPathNotFound occurs when the path to pull doesn't exist in the container.
+var ErrVendorPathNotFound = func(p string) error {
+	return fmt.Errorf("%s doesn't exist in the container, run composer install or npm install first", p)
+}
+
+// ErrVendorStubsNotExported occurs when the output directory of the stubs is cleaned, but the stubs weren't exported
+// to it before, so it could contain other files.
+var ErrVendorStubsNotExported = func(output string) error {
+	return fmt.Errorf("cannot clean %s, the stubs weren't exported to it before, remove it manually", output)
+}
+
+// vendorStubsScript prints a tar archive of the php sources and the composer metadata of the vendor directory, the
+// tests and the assets of the packages are left out.
+const vendorStubsScript = `cd %s && find vendor -type f \( -name '*.php' -o -name '*.phpstub' -o -name composer.json \
+  -o -path 'vendor/composer/*' \) -not -path '*/[Tt]ests/*' -not -path '*/[Tt]est/*' -print0 | tar -cf - --null -T -`
+
+// RunCmdVendorPull copies the packages from the vendor volumes of the synced container to the host, so the IDE can
+// index them. The vendor directories are not synced, the copies are not updated when the packages change in the
+// container, they have to be pulled again.
+func (c *Client) RunCmdVendorPull(args []string) error {
+	if !c.VendorVolume() {
+		return ErrVendorVolumeDisabled
+	}
+
+	paths := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		p, err := c.vendorPath(arg)
+		if err != nil {
+			return err
+		}
+
+		paths = append(paths, p)
+	}
+
+	container, err := c.Docker.RunningEnvServiceContainer(c.SyncedContainer())
+	if err != nil {
+		return fmt.Errorf("cannot lookup synced container: %w", err)
+	}
+
+	for _, p := range paths {
+		var (
+			hostPath      = filepath.Join(c.Cwd(), c.WebRoot(), filepath.FromSlash(p))
+			containerPath = path.Join(c.SyncedDir(), p)
+		)
+
+		log.Printf("Pulling %s...", p)
+
+		if cmdpkg.Cmnd("docker", "exec", container.ID, "test", "-e", containerPath).Run() != nil {
+			return ErrVendorPathNotFound(p)
+		}
+
+		// docker cp copies a directory into the destination if it already exists
+		err = util.FS.RemoveAll(hostPath)
+		if err != nil {
+			return fmt.Errorf("cannot remove %s: %w", hostPath, err)
+		}
+
+		err = util.CreateDir(filepath.Dir(hostPath), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create directory: %w", err)
+		}
+
+		out, err := cmdpkg.Cmnd("docker", "cp", container.ID+":"+containerPath, hostPath).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("cannot copy %s from the container: %w: %s", containerPath, err, out)
+		}
+	}
+
+	log.Println("...pulled. The pulled packages are not updated automatically, pull them again after they change.")
+
+	return nil
+}
+
+// vendorPath returns the path relative to the web root of the package. The paths without a vendor directory prefix
+// are in the vendor directory, eg. magento/framework is vendor/magento/framework.
+func (c *Client) vendorPath(p string) (string, error) {
+	cleaned := strings.Trim(path.Clean("/"+filepath.ToSlash(p)), "/")
+	if cleaned == "" || strings.Contains(filepath.ToSlash(p), "..") {
+		return "", ErrInvalidVendorPath(p)
+	}
+
+	for _, dir := range c.VendorVolumeDirs() {
+		if cleaned == dir || strings.HasPrefix(cleaned, dir+"/") {
+			return cleaned, nil
+		}
+	}
+
+	return path.Join(c.VendorVolumeDirs()[0], cleaned), nil
+}
+
+// RunCmdVendorExportStubs exports the php sources and the composer metadata of the vendor volume of the synced
+// container to the host, so the IDE can index the classes (autocompletion, navigation) without syncing the vendor
+// directory. By default, they're exported to the vendor directory of the host, which is not synced.
+func (c *Client) RunCmdVendorExportStubs(cmd *cmdpkg.Command) error {
+	if !c.VendorVolume() {
+		return ErrVendorVolumeDisabled
+	}
+
+	var (
+		output, _ = cmd.Flags().GetString("output")
+		clean, _  = cmd.Flags().GetBool("clean")
+	)
+
+	switch {
+	case output == "":
+		output = filepath.Join(c.Cwd(), c.WebRoot(), "vendor")
+	case !filepath.IsAbs(output):
+		output = filepath.Join(c.Cwd(), output)
+	}
+
+	container, err := c.Docker.RunningEnvServiceContainer(c.SyncedContainer())
+	if err != nil {
+		return fmt.Errorf("cannot lookup synced container: %w", err)
+	}
+
+	marker := filepath.Join(output, fmt.Sprintf(".%s-stubs", c.AppName()))
+
+	if clean {
+		err = removeVendorStubs(output, marker)
+		if err != nil {
+			return err
+		}
+	}
+
+	export := cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c", fmt.Sprintf(vendorStubsScript, c.SyncedDir()))
+	export.Stderr = os.Stderr
+
+	stdout, err := export.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("cannot create pipe: %w", err)
+	}
+
+	err = export.Start()
+	if err != nil {
+		return fmt.Errorf("cannot export stubs: %w", err)
+	}
+
+	step := c.newProgress().Step("Exporting stubs")
+	files, err := extractVendorStubs(stdout, output, step)
+
+	// the archive has to be read to the end, otherwise tar is blocked
+	_, _ = io.Copy(io.Discard, stdout)
+
+	if waitErr := export.Wait(); err == nil && waitErr != nil {
+		err = fmt.Errorf("cannot export stubs: %w", waitErr)
+	}
+
+	// the exported files are listed even if the export fails, so they can be cleaned
+	if markErr := writeVendorStubsMarker(marker, files); err == nil && markErr != nil {
+		err = markErr
+	}
+
+	err = step.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%d files exported to %s.", len(files), output)
+
+	return nil
+}
+
+// extractVendorStubs extracts the regular files of the vendor directory from the tar archive to the output directory.
+// It returns the paths of the extracted files relative to the output directory.
+func extractVendorStubs(r io.Reader, output string, progress util.Progress) ([]string, error) {
+	var (
+		archive = tar.NewReader(r)
+		files   []string
+	)
+
+	for {
+		h, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+
+		if err != nil {
+			return files, fmt.Errorf("cannot read the archive: %w", err)
+		}
+
+		name := path.Clean(h.Name)
+		if h.Typeflag != tar.TypeReg || !strings.HasPrefix(name, "vendor/") {
+			continue
+		}
+
+		target, err := vendorStubTarget(output, strings.TrimPrefix(name, "vendor/"))
+		if err != nil {
+			return files, ErrInvalidVendorPath(h.Name)
+		}
+
+		err = writeVendorStub(archive, target)
+		if err != nil {
+			return files, err
+		}
+
+		files = append(files, strings.TrimPrefix(name, "vendor/"))
+
+		progress.Add(1, h.Size)
+	}
+}
+
+func writeVendorStub(r io.Reader, target string) error {
+	err := util.FS.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	f, err := util.FS.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", target, err)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// vendorStubTarget returns the path of the stub in the output directory. The path must not be outside of the output
+// directory.
+func vendorStubTarget(output, name string) (string, error) {
+	target := filepath.Join(output, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(output, target); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", ErrInvalidVendorPath(name)
+	}
+
+	return target, nil
+}
+
+// vendorStubsMarkerFiles returns the files listed in the marker file of the previous exports.
+func vendorStubsMarkerFiles(marker string) ([]string, error) {
+	content, err := util.FS.ReadFile(marker)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return strings.Fields(string(content)), nil
+}
+
+// writeVendorStubsMarker adds the exported files to the marker file in the output directory, so only the files of the
+// exports are removed by --clean.
+func writeVendorStubsMarker(marker string, files []string) error {
+	previous, err := vendorStubsMarkerFiles(marker)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read %s: %w", marker, err)
+	}
+
+	listed := make(map[string]bool, len(previous)+len(files))
+	for _, f := range append(previous, files...) {
+		listed[f] = true
+	}
+
+	all := make([]string, 0, len(listed))
+	for f := range listed {
+		all = append(all, f)
+	}
+
+	sort.Strings(all)
+
+	err = util.CreateDirAndWriteToFile([]byte(strings.Join(all, "\n")+"\n"), marker)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", marker, err)
+	}
+
+	return nil
+}
+
+// removeVendorStubs removes the files of the previous exports listed in the marker file, and the directories which are
+// left empty. The other files of the output directory are kept. The output directory cannot be cleaned if the stubs
+// weren't exported to it before.
+func removeVendorStubs(output, marker string) error {
+	if !util.FileExists(output) {
+		return nil
+	}
+
+	files, err := vendorStubsMarkerFiles(marker)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrVendorStubsNotExported(output)
+		}
+
+		return fmt.Errorf("cannot read %s: %w", marker, err)
+	}
+
+	for _, f := range files {
+		target, err := vendorStubTarget(output, f)
+		if err != nil {
+			return err
+		}
+
+		err = util.FS.Remove(target)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot remove %s: %w", target, err)
+		}
+
+		for dir := filepath.Dir(target); dir != output; dir = filepath.Dir(dir) {
+			entries, err := util.FS.ReadDir(dir)
+			if err != nil || len(entries) > 0 {
+				break
+			}
+
+			_ = util.FS.Remove(dir)
+		}
+	}
+
+	err = util.FS.Remove(marker)
+	if err != nil {
+		return fmt.Errorf("cannot remove %s: %w", marker, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"archive/tar"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+type VendorTestSuite struct {
+	suite.Suite
+}
+
+func TestVendorTestSuite(t *testing.T) {
+	suite.Run(t, new(VendorTestSuite))
+}
+
+func (suite *VendorTestSuite) TestVendorPath() {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "package", path: "magento/framework", want: "vendor/magento/framework"},
+		{name: "vendor prefix", path: "vendor/magento/framework/", want: "vendor/magento/framework"},
+		{name: "whole vendor", path: "vendor", want: "vendor"},
+		{name: "node modules", path: "node_modules/react", want: "node_modules/react"},
+		{name: "parent directory", path: "vendor/../app/etc", wantErr: true},
+		{name: "empty", path: "/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := newTestClient(nil).vendorPath(tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *VendorTestSuite) TestExtractVendorStubs() {
+	util.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+	var (
+		buf bytes.Buffer
+		w   = tar.NewWriter(&buf)
+	)
+
+	for _, f := range []struct {
+		name string
+		typ  byte
+	}{
+		{name: "vendor/magento/framework/App/Http.php", typ: tar.TypeReg},
+		{name: "vendor/composer/installed.json", typ: tar.TypeReg},
+		{name: "vendor/magento/framework", typ: tar.TypeDir},
+		{name: "app/etc/env.php", typ: tar.TypeReg},
+	} {
+		content := []byte("<?php")
+		assert.NoError(suite.T(), w.WriteHeader(&tar.Header{
+			Name: f.name, Typeflag: f.typ, Mode: 0o644, Size: int64(len(content)),
+		}))
+
+		if f.typ == tar.TypeReg {
+			_, err := w.Write(content)
+			assert.NoError(suite.T(), err)
+		}
+	}
+
+	assert.NoError(suite.T(), w.Close())
+
+	files, err := extractVendorStubs(&buf, "/project/vendor", util.NoProgress)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"magento/framework/App/Http.php", "composer/installed.json"}, files)
+
+	got, err := util.FS.ReadFile("/project/vendor/magento/framework/App/Http.php")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "<?php", string(got))
+	assert.True(suite.T(), util.FileExists("/project/vendor/composer/installed.json"))
+	assert.False(suite.T(), util.FileExists("/project/app/etc/env.php"))
+}
+
+func (suite *VendorTestSuite) TestRemoveVendorStubs() {
+	util.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+	var (
+		output = "/project/vendor"
+		marker = output + "/.reward-stubs"
+	)
+
+	// the stubs weren't exported to the directory
+	assert.NoError(suite.T(), util.FS.WriteFile(output+"/autoload.php", []byte("<?php"), 0o644))
+	assert.ErrorContains(suite.T(), removeVendorStubs(output, marker), ErrVendorStubsNotExported(output).Error())
+	assert.True(suite.T(), util.FileExists(output+"/autoload.php"))
+
+	for _, f := range []string{"magento/framework/App/Http.php", "composer/installed.json"} {
+		assert.NoError(suite.T(), util.FS.MkdirAll(filepath.Dir(filepath.Join(output, f)), 0o755))
+		assert.NoError(suite.T(), util.FS.WriteFile(filepath.Join(output, f), []byte("<?php"), 0o644))
+	}
+
+	assert.NoError(suite.T(), writeVendorStubsMarker(marker, []string{"magento/framework/App/Http.php"}))
+	assert.NoError(suite.T(), writeVendorStubsMarker(marker, []string{"composer/installed.json"}))
+
+	assert.NoError(suite.T(), removeVendorStubs(output, marker))
+	assert.False(suite.T(), util.FileExists(output+"/magento/framework/App/Http.php"))
+	assert.False(suite.T(), util.FileExists(output+"/magento"))
+	assert.False(suite.T(), util.FileExists(output+"/composer/installed.json"))
+	assert.False(suite.T(), util.FileExists(marker))
+	assert.True(suite.T(), util.FileExists(output+"/autoload.php"))
+
+	// the listed files must be in the output directory
+	assert.NoError(suite.T(), writeVendorStubsMarker(marker, []string{"../app/etc/env.php"}))
+	assert.Error(suite.T(), removeVendorStubs(output, marker))
+}
@@ -0,0 +1,114 @@
+package logic
+
+import (
+	"context"
+	"runtime"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// versionNotAvailable is reported for the components which are not installed or not running.
+const versionNotAvailable = "not available"
+
+// versionInfo contains the versions of the application and the components it depends on.
+type versionInfo struct {
+	Version              string            `json:"version"`
+	OS                   string            `json:"os"`
+	Arch                 string            `json:"arch"`
+	ConfigSchemaVersion  int               `json:"config_schema_version"`
+	DockerVersion        string            `json:"docker_version"`
+	DockerAPIVersion     string            `json:"docker_api_version"`
+	DockerPlatform       string            `json:"docker_platform"`
+	DockerComposeVersion string            `json:"docker_compose_version"`
+	MutagenVersion       string            `json:"mutagen_version"`
+	Plugins              map[string]string `json:"plugins"`
+}
+
+// RunCmdVersion prints the versions of the application, docker, docker-compose, mutagen and the installed plugins.
+// The components which cannot be detected are reported as not available instead of failing the command, so the
+// output can be attached to bug reports as is.
+func (c *Client) RunCmdVersion(cmd *cmdpkg.Command) error {
+	info := c.versionInfo()
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON || c.JSONOutput() {
+		return c.printJSON(info)
+	}
+
+	log.Printf("%s version: %s", c.AppName(), info.Version)
+	log.Printf("GOOS: %s", info.OS)
+	log.Printf("GOARCH: %s", info.Arch)
+	log.Printf("config schema version: %d", info.ConfigSchemaVersion)
+	log.Printf("docker version: %s", info.DockerVersion)
+	log.Printf("docker API version: %s", info.DockerAPIVersion)
+	log.Printf("docker platform: %s", info.DockerPlatform)
+	log.Printf("docker-compose version: %s", info.DockerComposeVersion)
+	log.Printf("mutagen version: %s", info.MutagenVersion)
+
+	names := make([]string, 0, len(info.Plugins))
+	for name := range info.Plugins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		log.Printf("plugin %s version: %s", name, info.Plugins[name])
+	}
+
+	return nil
+}
+
+func (c *Client) versionInfo() *versionInfo {
+	info := &versionInfo{
+		Version:              c.AppVersion(),
+		OS:                   runtime.GOOS,
+		Arch:                 runtime.GOARCH,
+		ConfigSchemaVersion:  config.ConfigSchemaVersion,
+		DockerVersion:        versionNotAvailable,
+		DockerAPIVersion:     versionNotAvailable,
+		DockerPlatform:       versionNotAvailable,
+		DockerComposeVersion: versionNotAvailable,
+		MutagenVersion:       versionNotAvailable,
+		Plugins:              make(map[string]string),
+	}
+
+	if data, err := c.Docker.ServerVersion(context.Background()); err == nil {
+		info.DockerVersion = data.Version
+		info.DockerAPIVersion = data.APIVersion
+		info.DockerPlatform = data.Platform.Name
+	} else {
+		log.Debugf("Cannot get docker version: %s", err)
+	}
+
+	if v, err := c.DockerCompose.Version(); err == nil {
+		info.DockerComposeVersion = v.String()
+	} else {
+		log.Debugf("Cannot get docker-compose version: %s", err)
+	}
+
+	if (c.MutagenManaged() && util.FileExists(c.MutagenBinary())) || util.CommandAvailable(c.MutagenBinary()) {
+		if v, err := c.mutagenVersion(); err == nil {
+			info.MutagenVersion = v
+		} else {
+			log.Debugf("Cannot get mutagen version: %s", err)
+		}
+	}
+
+	for _, plugin := range c.Plugins() {
+		v, err := c.pluginVersion(plugin.Name)
+		if err != nil {
+			log.Debugf("Cannot get version of plugin %s: %s", plugin.Name, err)
+
+			v = versionNotAvailable
+		}
+
+		info.Plugins[plugin.Name] = v
+	}
+
+	return info
+}
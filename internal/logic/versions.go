@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// RefreshVersionMatrix downloads the remote version matrix and its signature to the application's home directory
+// if the cached copy is older than the refresh interval. The matrix is only stored if its signature is valid.
+// Errors are only logged, the embedded version matrix is used if the remote one is not available.
+func (c *Client) RefreshVersionMatrix() {
+	if c.VersionMatrixURL() == "" {
+		return
+	}
+
+	if c.Offline() {
+		log.Debugln("Offline mode, using the cached version matrix.")
+
+		return
+	}
+
+	if stat, err := os.Stat(c.VersionMatrixFile()); err == nil &&
+		time.Since(stat.ModTime()) < c.VersionMatrixRefreshInterval() {
+		return
+	}
+
+	log.Debugln("Refreshing version matrix...")
+
+	data, err := c.getContentFromURL(c.VersionMatrixURL())
+	if err != nil {
+		log.Debugf("Cannot download version matrix: %s", err)
+
+		return
+	}
+
+	signature, err := c.getContentFromURL(c.VersionMatrixURL() + ".sig")
+	if err != nil {
+		log.Debugf("Cannot download version matrix signature: %s", err)
+
+		return
+	}
+
+	err = c.VerifyVersionMatrix(data, signature)
+	if err != nil {
+		log.Warnf("Ignoring the downloaded version matrix: %s", err)
+
+		return
+	}
+
+	err = util.CreateDirAndWriteToFile(signature, c.VersionMatrixSignatureFile(), 0o644)
+	if err == nil {
+		err = util.CreateDirAndWriteToFile(data, c.VersionMatrixFile(), 0o644)
+	}
+
+	if err != nil {
+		log.Debugf("Cannot write version matrix: %s", err)
+
+		return
+	}
+
+	c.ApplyVersionMatrixDefaults()
+
+	log.Debugln("...version matrix refreshed.")
+}
+
+// applyVersionMatrixEnvDefaults replaces the service versions in the .env content of a new environment with the
+// defaults of the version matrix.
+func (c *Client) applyVersionMatrixEnvDefaults(envType, content string) string {
+	for key, value := range c.VersionMatrix().EnvDefaults[envType] {
+		re := regexp.MustCompile(fmt.Sprintf(`(?m)^%s=.*$`, regexp.QuoteMeta(key)))
+		content = re.ReplaceAllString(content, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return content
+}
@@ -0,0 +1,132 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+	"github.com/rewardenv/reward/internal/timing"
+)
+
+const (
+	// exitCodeWaitTimeout is the exit code of wait when the services are not ready in time, it's the same as the
+	// exit code of the timeout command of coreutils.
+	exitCodeWaitTimeout = 124
+	// exitCodeWaitNotFound is the exit code of wait when a service doesn't exist in the environment.
+	exitCodeWaitNotFound = 3
+)
+
+// ErrWaitTimeout occurs when the services are not ready before the timeout.
+var ErrWaitTimeout = func(timeout time.Duration, services []string) error {
+	return &cmdpkg.ExitError{
+		Code: exitCodeWaitTimeout,
+		Err:  fmt.Errorf("%s not ready in %s", strings.Join(services, ", "), timeout),
+	}
+}
+
+// ErrWaitServiceNotFound occurs when the service to wait for doesn't exist in the environment.
+var ErrWaitServiceNotFound = func(service string) error {
+	return &cmdpkg.ExitError{
+		Code: exitCodeWaitNotFound,
+		Err:  fmt.Errorf("service %s not found in the environment, start it using `reward env up`", service),
+	}
+}
+
+// RunCmdWait blocks until the services of the environment are ready, so it can be used in Makefiles and CI scripts
+// after env up. By default, it waits for all the services of the environment.
+func (c *Client) RunCmdWait(cmd *cmdpkg.Command) error {
+	var (
+		services, _ = cmd.Flags().GetStringSlice("for")
+		timeout, _  = cmd.Flags().GetDuration("timeout")
+	)
+
+	if len(services) == 0 {
+		containers, err := c.Docker.ContainerDetailsByLabel(c.LabelEnvName())
+		if err != nil {
+			return fmt.Errorf("cannot get containers: %w", err)
+		}
+
+		for _, container := range containers {
+			if container.Project == c.EnvName() {
+				services = append(services, container.Service)
+			}
+		}
+
+		sort.Strings(services)
+	}
+
+	for _, service := range services {
+		if _, err := c.Docker.EnvServiceContainer(service); err != nil {
+			return ErrWaitServiceNotFound(service)
+		}
+	}
+
+	log.Printf("Waiting for %s...", strings.Join(services, ", "))
+
+	defer timing.Start("health wait")()
+
+	start := time.Now()
+
+	for attempt := 0; ; attempt++ {
+		var pending []string
+
+		for _, service := range services {
+			if !c.serviceReady(service) {
+				pending = append(pending, service)
+			}
+		}
+
+		if len(pending) == 0 {
+			log.Printf("...ready in %s.", time.Since(start).Round(time.Second))
+
+			return nil
+		}
+
+		if time.Since(start) > timeout {
+			return ErrWaitTimeout(timeout, pending)
+		}
+
+		// the ready services are not checked again
+		services = pending
+
+		time.Sleep(dbReadyBackoff(attempt))
+	}
+}
+
+// serviceReady returns true if the service accepts connections. The services without a specific readiness check
+// are ready when their container is running and healthy.
+func (c *Client) serviceReady(service string) bool {
+	switch service {
+	case c.DBContainer():
+		return c.dbAcceptsConnections()
+	case "elasticsearch", "opensearch":
+		_, err := c.searchRequest(service, "GET", "/_cluster/health?wait_for_status=yellow&timeout=1s", "")
+
+		return err == nil
+	}
+
+	container, err := c.Docker.EnvServiceContainer(service)
+	if err != nil || !container.Healthy() {
+		return false
+	}
+
+	switch service {
+	case "redis":
+		auth := ""
+		if c.RedisPassword() != "" {
+			auth = fmt.Sprintf("-a %s --no-auth-warning ", c.RedisPassword())
+		}
+
+		out, err := cmdpkg.Cmnd("docker", "exec", container.ID, "sh", "-c", "redis-cli "+auth+"ping").Output()
+
+		return err == nil && strings.TrimSpace(string(out)) == "PONG"
+	case "rabbitmq":
+		return cmdpkg.Cmnd("docker", "exec", container.ID, "rabbitmq-diagnostics", "-q", "check_running").Run() == nil
+	default:
+		return true
+	}
+}
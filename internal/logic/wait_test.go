@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	cmdpkg "github.com/rewardenv/reward/cmd"
+)
+
+type WaitTestSuite struct {
+	suite.Suite
+}
+
+func TestWaitTestSuite(t *testing.T) {
+	suite.Run(t, new(WaitTestSuite))
+}
+
+func (suite *WaitTestSuite) TestWaitExitCodes() {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "timeout", err: ErrWaitTimeout(time.Minute, []string{"db"}), want: exitCodeWaitTimeout},
+		{name: "not found", err: ErrWaitServiceNotFound("redis"), want: exitCodeWaitNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// the commands wrap the errors of the logic
+			err := fmt.Errorf("error running wait command: %w", tt.err)
+
+			var exitErr *cmdpkg.ExitError
+			assert.True(t, errors.As(err, &exitErr))
+			assert.Equal(t, tt.want, exitErr.Code)
+		})
+	}
+
+	assert.Equal(suite.T(), "db not ready in 1m0s", ErrWaitTimeout(time.Minute, []string{"db"}).Error())
+}
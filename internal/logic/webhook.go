@@ -0,0 +1,188 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// webhookTimeout is the time a webhook has to accept the event, so a slow endpoint doesn't block the command.
+const webhookTimeout = 10 * time.Second
+
+// ErrUnknownWebhookType occurs when the type of the webhook is not supported.
+var ErrUnknownWebhookType = func(t string) error {
+	return fmt.Errorf("unknown webhook type: %s, valid options: generic, slack", t)
+}
+
+// ErrWebhookFailed occurs when the webhook endpoint responds with an error status.
+var ErrWebhookFailed = func(status string) error {
+	return fmt.Errorf("webhook responded with %s", status)
+}
+
+// webhookEvent is the data of a lifecycle event, the fields are available in the payload templates of the webhooks.
+type webhookEvent struct {
+	Event       string `json:"event"`
+	Environment string `json:"environment"`
+	Command     string `json:"command"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+	User        string `json:"user"`
+	Host        string `json:"host"`
+	Time        string `json:"time"`
+}
+
+// SendWebhooks notifies the configured webhooks about the result of the command, eg. the bootstrap.ok or the
+// env.up.failed event. Errors are only logged as the notifications should never break the command itself.
+func (c *Client) SendWebhooks(cmd *cobra.Command, args []string, cmdErr error) {
+	webhooks := c.Webhooks()
+	if len(webhooks) == 0 {
+		return
+	}
+
+	if c.Offline() {
+		log.Debugln("Offline mode, skipping the webhooks.")
+
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	event := webhookEvent{
+		Event:       webhookEventName(cmd, args, cmdErr),
+		Environment: c.EnvName(),
+		Command:     strings.TrimSpace(cmd.CommandPath() + " " + strings.Join(args, " ")),
+		Status:      "ok",
+		User:        util.Username(),
+		Host:        hostname,
+		Time:        time.Now().Format(time.RFC3339),
+	}
+
+	if cmdErr != nil {
+		event.Status = "failed"
+		event.Error = cmdErr.Error()
+	}
+
+	for _, webhook := range webhooks {
+		if !webhookSubscribed(webhook, event.Event) {
+			continue
+		}
+
+		err := sendWebhook(webhook, event)
+		if err != nil {
+			log.Warnf("Cannot send the %s event to webhook %s: %s", event.Event, webhook.URL, err)
+		}
+	}
+}
+
+// webhookEventName returns the name of the event of the command, eg. db.import.ok. The docker compose command is
+// part of the name for env commands, eg. env.up.failed.
+func webhookEventName(cmd *cobra.Command, args []string, cmdErr error) string {
+	name := strings.Fields(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	if cmd.Name() == "env" && len(args) > 0 {
+		name = append(name, args[0])
+	}
+
+	status := "ok"
+	if cmdErr != nil {
+		status = "failed"
+	}
+
+	return strings.Join(append(name, status), ".")
+}
+
+// webhookSubscribed returns true if the event matches one of the event patterns of the webhook.
+func webhookSubscribed(webhook *config.Webhook, event string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+
+	for _, pattern := range webhook.Events {
+		if matched, _ := path.Match(pattern, event); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookPayload returns the json payload of the event for the webhook.
+func webhookPayload(webhook *config.Webhook, event webhookEvent) ([]byte, error) {
+	if webhook.Template != "" {
+		tpl, err := template.New("webhook").Funcs(template.FuncMap{
+			// json returns the value as a json string, so it can be embedded in the payload safely
+			"json": func(v string) (string, error) {
+				b, err := json.Marshal(v)
+
+				return string(b), err
+			},
+		}).Parse(webhook.Template)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the payload template: %w", err)
+		}
+
+		var buf bytes.Buffer
+
+		err = tpl.Execute(&buf, event)
+		if err != nil {
+			return nil, fmt.Errorf("cannot render the payload template: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	switch strings.ToLower(webhook.Type) {
+	case "", "generic":
+		return json.Marshal(event)
+	case "slack":
+		text := fmt.Sprintf("*%s*: `%s` %s (%s@%s)", event.Environment, event.Command, event.Status, event.User,
+			event.Host)
+		if event.Error != "" {
+			text += "\n```" + event.Error + "```"
+		}
+
+		return json.Marshal(map[string]string{"text": text})
+	default:
+		return nil, ErrUnknownWebhookType(webhook.Type)
+	}
+}
+
+func sendWebhook(webhook *config.Webhook, event webhookEvent) error {
+	payload, err := webhookPayload(webhook, event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return ErrWebhookFailed(resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package logic
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (suite *WebhookTestSuite) TestWebhookEventName() {
+	root := &cobra.Command{Use: "reward"}
+	env := &cobra.Command{Use: "env"}
+	db := &cobra.Command{Use: "db"}
+	dbImport := &cobra.Command{Use: "import"}
+	root.AddCommand(env, db)
+	db.AddCommand(dbImport)
+
+	assert.Equal(suite.T(), "env.up.failed", webhookEventName(env, []string{"up", "-d"}, fmt.Errorf("error")))
+	assert.Equal(suite.T(), "db.import.ok", webhookEventName(dbImport, nil, nil))
+}
+
+func (suite *WebhookTestSuite) TestWebhookSubscribed() {
+	webhook := &config.Webhook{Events: []string{"bootstrap.ok", "*.failed"}}
+
+	assert.True(suite.T(), webhookSubscribed(webhook, "bootstrap.ok"))
+	assert.True(suite.T(), webhookSubscribed(webhook, "env.up.failed"))
+	assert.False(suite.T(), webhookSubscribed(webhook, "env.up.ok"))
+	assert.True(suite.T(), webhookSubscribed(&config.Webhook{}, "env.up.ok"))
+}
+
+func (suite *WebhookTestSuite) TestWebhookPayload() {
+	event := webhookEvent{
+		Event:       "bootstrap.failed",
+		Environment: "myproject",
+		Command:     "reward bootstrap",
+		Status:      "failed",
+		Error:       `cannot run "composer install"`,
+		User:        "dev",
+		Host:        "demo",
+	}
+
+	tests := []struct {
+		name    string
+		webhook *config.Webhook
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "slack",
+			webhook: &config.Webhook{Type: "slack"},
+			want: `{"text":"*myproject*: ` + "`reward bootstrap`" + ` failed (dev@demo)\n` +
+				"```cannot run \\\"composer install\\\"```" + `"}`,
+		},
+		{
+			name:    "template",
+			webhook: &config.Webhook{Template: `{"msg": {{ json .Error }}, "env": "{{ .Environment }}"}`},
+			want:    `{"msg": "cannot run \"composer install\"", "env": "myproject"}`,
+		},
+		{
+			name:    "unknown type",
+			webhook: &config.Webhook{Type: "teams"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := webhookPayload(tt.webhook, event)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func (suite *WebhookTestSuite) TestSendWebhook() {
+	var body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	event := webhookEvent{Event: "env.up.ok", Environment: "myproject", Status: "ok"}
+
+	err := sendWebhook(&config.Webhook{URL: server.URL + "/hook"}, event)
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), body, `"event":"env.up.ok"`)
+
+	err = sendWebhook(&config.Webhook{URL: server.URL + "/fail"}, event)
+	assert.Error(suite.T(), err)
+}
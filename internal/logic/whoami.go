@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// RunCmdWhoami prints the user and the namespace settings used for naming the environment's resources.
+func (c *Client) RunCmdWhoami() error {
+	t := c.newTable()
+
+	t.AppendRow(table.Row{"User", util.Username()})
+	t.AppendRow(table.Row{"Shared mode", c.SharedMode()})
+
+	if c.SharedMode() {
+		if err := c.resolvePortOffset(); err != nil {
+			return err
+		}
+
+		t.AppendRow(table.Row{"Namespace", c.Namespace()})
+		t.AppendRow(table.Row{"Port offset", c.PortOffset()})
+	}
+
+	if c.EnvInitialized() {
+		t.AppendRow(table.Row{"Environment name", c.EnvName()})
+		t.AppendRow(table.Row{"Environment domain", c.TraefikDomain()})
+	}
+
+	t.Render()
+
+	return nil
+}
@@ -0,0 +1,322 @@
+// Package progress renders the progress of the long-running commands (eg. env up, bootstrap, db import) as a list of
+// steps. The running step is rendered as a spinner, or as a progress bar if its size is known. If the output is not a
+// terminal or ANSI output is disabled (--no-ansi), the progress is logged as plain lines instead. If colors are
+// disabled (--no-color), the steps are rendered without colors and with ASCII symbols.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+const (
+	// refreshInterval is the time between two frames of the animated steps.
+	refreshInterval = 100 * time.Millisecond
+	// barWidth is the width of the progress bar in characters.
+	barWidth = 30
+	// clearLine moves the cursor to the beginning of the line and clears the line.
+	clearLine = "\r\033[K"
+
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+// symbols are the symbols of the steps.
+type symbols struct {
+	spinner []string
+	done    string
+	failed  string
+	output  string
+}
+
+var (
+	unicodeSymbols = symbols{
+		spinner: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		done:    colorGreen + "✓" + colorReset,
+		failed:  colorRed + "✗" + colorReset,
+		output:  colorCyan + "▸" + colorReset,
+	}
+	asciiSymbols = symbols{
+		spinner: []string{"|", "/", "-", "\\"},
+		done:    "+",
+		failed:  "x",
+		output:  ">",
+	}
+)
+
+var (
+	// active is the animated step which is rendered currently. Only one step is animated at a time, a nested step
+	// pauses the animation of its parent.
+	active     *Step
+	activeMu   sync.Mutex
+	activeHook sync.Once
+)
+
+// Renderer renders the steps of a command.
+type Renderer struct {
+	out     io.Writer
+	ansi    bool
+	symbols symbols
+	total   int
+	count   int
+	mu      sync.Mutex
+}
+
+// New returns a Renderer which renders the steps to the output. If ansi is false, the steps are logged as plain
+// lines. If color is false, the steps are rendered without colors and with ASCII symbols.
+func New(out io.Writer, ansi, color bool) *Renderer {
+	symbols := unicodeSymbols
+	if !color {
+		symbols = asciiSymbols
+	}
+
+	if ansi {
+		// the log entries clear the line of the animated step, which is redrawn by the next frame
+		activeHook.Do(func() {
+			log.AddHook(clearLineHook{})
+		})
+	}
+
+	return &Renderer{out: out, ansi: ansi, symbols: symbols}
+}
+
+// ANSI returns true if the steps are rendered with ANSI escape sequences.
+func (r *Renderer) ANSI() bool {
+	return r.ansi
+}
+
+// SetTotalSteps sets the number of the steps, which is rendered before the titles (eg. [1/3]).
+func (r *Renderer) SetTotalSteps(total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.total = total
+}
+
+// Step starts a step which doesn't write to the output. In ANSI mode, it's animated until it's finished.
+func (r *Renderer) Step(title string) *Step {
+	return r.start(title, true)
+}
+
+// OutputStep starts a step which writes to the output (eg. runs docker-compose), so it's not animated, only its title
+// and its result are rendered.
+func (r *Renderer) OutputStep(title string) *Step {
+	return r.start(title, false)
+}
+
+func (r *Renderer) start(title string, animated bool) *Step {
+	r.mu.Lock()
+	r.count++
+
+	if r.total > 0 {
+		title = fmt.Sprintf("[%d/%d] %s", r.count, r.total, title)
+	}
+	r.mu.Unlock()
+
+	s := &Step{
+		r:        r,
+		title:    title,
+		animated: animated && r.ansi,
+		started:  time.Now(),
+		plain:    util.NewLogProgress(title),
+		stopped:  make(chan struct{}),
+	}
+
+	switch {
+	case s.animated:
+		activeMu.Lock()
+		s.parent = active
+		active = s
+		activeMu.Unlock()
+
+		s.wg.Add(1)
+
+		go s.animate()
+	case r.ansi:
+		_, _ = fmt.Fprintf(r.out, "%s %s\n", r.symbols.output, title)
+	default:
+		log.Printf("%s...", title)
+	}
+
+	return s
+}
+
+// Step is a step of a command. It implements util.Progress, so the operations which report their progress (eg.
+// downloads, extractions) render a progress bar.
+type Step struct {
+	r        *Renderer
+	title    string
+	animated bool
+	started  time.Time
+	plain    *util.LogProgress
+	parent   *Step
+
+	totalFiles, files int64
+	totalBytes, bytes int64
+
+	stopped  chan struct{}
+	wg       sync.WaitGroup
+	finished sync.Once
+}
+
+// Start implements util.Progress.
+func (s *Step) Start(files int, bytes int64) {
+	atomic.StoreInt64(&s.totalFiles, int64(files))
+	atomic.StoreInt64(&s.totalBytes, bytes)
+	s.plain.Start(files, bytes)
+}
+
+// Add implements util.Progress.
+func (s *Step) Add(files int, bytes int64) {
+	atomic.AddInt64(&s.files, int64(files))
+	atomic.AddInt64(&s.bytes, bytes)
+
+	if !s.r.ansi {
+		s.plain.Add(files, bytes)
+	}
+}
+
+// Done finishes the step successfully.
+func (s *Step) Done() {
+	s.finish(nil)
+}
+
+// Fail finishes the step with the error. The error itself is not rendered, it's returned by the command.
+func (s *Step) Fail(err error) {
+	s.finish(err)
+}
+
+// Finish finishes the step with the result of its operation and returns the error, eg. return step.Finish(err).
+func (s *Step) Finish(err error) error {
+	s.finish(err)
+
+	return err
+}
+
+func (s *Step) finish(err error) {
+	s.finished.Do(func() {
+		if s.animated {
+			close(s.stopped)
+			s.wg.Wait()
+
+			activeMu.Lock()
+			if active == s {
+				active = s.parent
+			}
+			activeMu.Unlock()
+		}
+
+		elapsed := time.Since(s.started).Round(refreshInterval)
+
+		switch {
+		case s.r.ansi && err != nil:
+			_, _ = fmt.Fprintf(s.r.out, "%s%s %s (%s)\n", s.clear(), s.r.symbols.failed, s.title, elapsed)
+		case s.r.ansi:
+			_, _ = fmt.Fprintf(s.r.out, "%s%s %s (%s)\n", s.clear(), s.r.symbols.done, s.title, elapsed)
+		case err != nil:
+			log.Debugf("...%s failed after %s: %s", s.title, elapsed, err)
+		default:
+			log.Printf("...%s finished in %s.", s.title, elapsed)
+		}
+	})
+}
+
+func (s *Step) clear() string {
+	if s.animated {
+		return clearLine
+	}
+
+	return ""
+}
+
+func (s *Step) animate() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		activeMu.Lock()
+		if active == s {
+			_, _ = fmt.Fprint(s.r.out, clearLine+s.line(frame))
+		}
+		activeMu.Unlock()
+
+		select {
+		case <-s.stopped:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// line returns the line of the animated step: a spinner, the title and the progress.
+func (s *Step) line(frame int) string {
+	var (
+		files      = atomic.LoadInt64(&s.files)
+		bytes      = atomic.LoadInt64(&s.bytes)
+		totalFiles = atomic.LoadInt64(&s.totalFiles)
+		totalBytes = atomic.LoadInt64(&s.totalBytes)
+		spinner    = s.r.symbols.spinner
+		line       = fmt.Sprintf("%s %s", spinner[frame%len(spinner)], s.title)
+	)
+
+	switch {
+	case totalBytes > 0:
+		line += " " + bar(bytes, totalBytes) + fmt.Sprintf(
+			" %d%% %s/%s", bytes*100/totalBytes, units.BytesSize(float64(bytes)), units.BytesSize(float64(totalBytes)),
+		)
+	case bytes > 0:
+		line += " " + units.BytesSize(float64(bytes))
+	}
+
+	if totalFiles > 0 {
+		line += fmt.Sprintf(" (%d/%d files)", files, totalFiles)
+	}
+
+	return line
+}
+
+// bar returns the progress bar of the current value, eg. [=====>    ].
+func bar(current, total int64) string {
+	filled := int(current * barWidth / total)
+	if filled > barWidth {
+		filled = barWidth
+	}
+
+	head := ""
+	if filled < barWidth {
+		head = ">"
+	}
+
+	return "[" + strings.Repeat("=", filled) + head + strings.Repeat(" ", barWidth-filled-len(head)) + "]"
+}
+
+// clearLineHook clears the line of the animated step before a log entry is written.
+type clearLineHook struct{}
+
+func (clearLineHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (clearLineHook) Fire(*log.Entry) error {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	if active != nil {
+		_, _ = fmt.Fprint(active.r.out, clearLine)
+	}
+
+	return nil
+}
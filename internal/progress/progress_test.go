@@ -0,0 +1,144 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProgressTestSuite struct {
+	suite.Suite
+}
+
+func TestProgressTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressTestSuite))
+}
+
+// captureLog redirects the log to a buffer until the test finishes.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	out := log.StandardLogger().Out
+	formatter := log.StandardLogger().Formatter
+
+	log.SetOutput(&buf)
+	log.SetFormatter(&log.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	t.Cleanup(func() {
+		log.SetOutput(out)
+		log.SetFormatter(formatter)
+	})
+
+	return &buf
+}
+
+func (suite *ProgressTestSuite) TestPlain() {
+	logs := captureLog(suite.T())
+
+	var out bytes.Buffer
+
+	r := New(&out, false, true)
+	r.SetTotalSteps(2)
+
+	r.Step("Downloading mutagen").Done()
+	r.OutputStep("Starting containers").Fail(fmt.Errorf("exit status 1"))
+
+	assert.Empty(suite.T(), out.String(), "the plain steps should be logged")
+	assert.Contains(suite.T(), logs.String(), `msg="[1/2] Downloading mutagen..."`)
+	assert.Contains(suite.T(), logs.String(), `msg="...[1/2] Downloading mutagen finished in 0s."`)
+	assert.Contains(suite.T(), logs.String(), `msg="[2/2] Starting containers..."`)
+	assert.NotContains(suite.T(), logs.String(), "Starting containers finished")
+	assert.NotContains(suite.T(), logs.String(), "\033[")
+}
+
+func (suite *ProgressTestSuite) TestANSI() {
+	captureLog(suite.T())
+
+	var out bytes.Buffer
+
+	r := New(&out, true, true)
+
+	step := r.Step("Downloading mutagen")
+	step.Start(0, 2048)
+	step.Add(0, 1024)
+	time.Sleep(3 * refreshInterval / 2)
+	step.Done()
+
+	output := out.String()
+	assert.Contains(suite.T(), output, clearLine+"⠋ Downloading mutagen")
+	assert.Contains(suite.T(), output, "50% 1KiB/2KiB")
+	assert.Contains(suite.T(), output, clearLine+colorGreen+"✓"+colorReset+" Downloading mutagen (")
+
+	out.Reset()
+
+	r.OutputStep("Starting containers").Fail(fmt.Errorf("exit status 1"))
+	assert.Equal(
+		suite.T(),
+		colorCyan+"▸"+colorReset+" Starting containers\n"+colorRed+"✗"+colorReset+" Starting containers (0s)\n",
+		out.String(),
+	)
+}
+
+func (suite *ProgressTestSuite) TestANSIWithoutColor() {
+	captureLog(suite.T())
+
+	var out bytes.Buffer
+
+	r := New(&out, true, false)
+
+	step := r.Step("Downloading mutagen")
+	time.Sleep(refreshInterval / 2)
+	step.Done()
+
+	r.OutputStep("Starting containers").Fail(fmt.Errorf("exit status 1"))
+
+	output := out.String()
+	assert.Contains(suite.T(), output, clearLine+"| Downloading mutagen")
+	assert.Contains(suite.T(), output, clearLine+"+ Downloading mutagen (")
+	assert.Contains(suite.T(), output, "> Starting containers\nx Starting containers (0s)\n")
+	assert.NotContains(suite.T(), output, "\033[3", "the output should not be colored")
+	assert.NotContains(suite.T(), output, "✓")
+}
+
+func (suite *ProgressTestSuite) TestNestedSteps() {
+	captureLog(suite.T())
+
+	var out bytes.Buffer
+
+	r := New(&out, true, true)
+
+	parent := r.Step("Bootstrapping")
+	child := r.Step("Downloading mutagen")
+	assert.Equal(suite.T(), child, active)
+
+	child.Done()
+	assert.Equal(suite.T(), parent, active, "the parent should be animated again")
+
+	parent.Done()
+	parent.Done()
+	assert.Nil(suite.T(), active)
+}
+
+func (suite *ProgressTestSuite) TestBar() {
+	tests := []struct {
+		current, total int64
+		want           string
+	}{
+		{current: 0, total: 100, want: "[>                             ]"},
+		{current: 50, total: 100, want: "[===============>              ]"},
+		{current: 100, total: 100, want: "[==============================]"},
+		{current: 150, total: 100, want: "[==============================]"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(fmt.Sprintf("%d of %d", tt.current, tt.total), func(t *testing.T) {
+			assert.Equal(t, tt.want, bar(tt.current, tt.total))
+		})
+	}
+}
@@ -7,12 +7,16 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"runtime"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/rewardenv/reward/pkg/util"
 )
 
+// ErrEmptyCommand occurs when RunCommand is called without a command.
+var ErrEmptyCommand = fmt.Errorf("command is empty")
+
 type Shell interface {
 	Execute(name string, args ...string) (output []byte, err error)
 	ExecuteWithOptions(name string, args []string, opts ...Opt) (output []byte, err error)
@@ -53,14 +57,25 @@ func WithSuppressOutput(b bool) Opt {
 	}
 }
 
+// WithShell makes RunCommand run the command line by the shell of the operating system (sh or cmd.exe), so it can
+// use pipes, redirections and variables. The arguments have to be quoted by util.Quote then.
+func WithShell() Opt {
+	return func(c *LocalShell) {
+		b := true
+		c.UseShell = &b
+	}
+}
+
 type LocalShell struct {
 	CatchStdout    *bool
 	SuppressStdout *bool
+	UseShell       *bool
 }
 
 func (c *LocalShell) Reset() {
 	c.CatchStdout = nil
 	c.SuppressStdout = nil
+	c.UseShell = nil
 }
 
 func (c *LocalShell) ExecuteWithOptions(name string, args []string, opts ...Opt) ([]byte, error) {
@@ -87,15 +102,30 @@ func (c *LocalShell) SuppressOutput() bool {
 	return *c.SuppressStdout
 }
 
+func (c *LocalShell) ShellInterpretation() bool {
+	if c.UseShell == nil {
+		return false
+	}
+
+	return *c.UseShell
+}
+
 func (c *LocalShell) Execute(name string, arg ...string) ([]byte, error) {
 	log.Debugf("Executing command: %s %s", name, strings.Join(arg, " "))
+
+	cmd := exec.Command(name)
+	cmd.Args = append(cmd.Args, arg...)
+
+	return c.run(cmd)
+}
+
+// run runs the command with the output options of the shell and resets them.
+func (c *LocalShell) run(cmd *exec.Cmd) ([]byte, error) {
 	log.Debugf("Catch stdout: %t", c.CatchOutput())
 	log.Debugf("Suppress stdout: %t", c.SuppressOutput())
 
 	defer c.Reset()
 
-	cmd := exec.Command(name)
-	cmd.Args = append(cmd.Args, arg...)
 	cmd.Stdin = os.Stdin
 
 	var combinedOutBuf bytes.Buffer
@@ -118,7 +148,7 @@ func (c *LocalShell) Execute(name string, arg ...string) ([]byte, error) {
 	log.Debugf("Command output: %s", outStr)
 
 	if err != nil {
-		return outStr, fmt.Errorf("error running command: %s: %w", name, err)
+		return outStr, fmt.Errorf("error running command: %s: %w", cmd.Args[0], err)
 	}
 
 	return outStr, nil
@@ -194,43 +224,52 @@ func (c *MockShell) Pipeline(cmds ...*exec.Cmd) (pipeLineOutput, collectedStanda
 	return c.Output, []byte(c.Err.Error()), nil
 }
 
-// RunCommand is going to run a command depending on the caller's operating system.
+// RunCommand runs the first arg as the command and passes the rest of the args to it as they are, without shell
+// interpretation. With the WithShell option the args are joined into a command line which is run by the shell of
+// the caller's operating system (sh or cmd.exe), the arguments containing spaces or special characters have to be
+// quoted by util.Quote then.
 func (c *LocalShell) RunCommand(args []string, opts ...Opt) ([]byte, error) {
-	var cmd string
-	if runtime.GOOS == "windows" {
-		cmd = "cmd"
+	if len(args) == 0 {
+		c.Reset()
+
+		return nil, ErrEmptyCommand
+	}
 
-		args = append([]string{"/c"}, args...)
-	} else {
-		cmd = "sh"
+	for _, opt := range opts {
+		opt(c)
+	}
 
-		args = append([]string{"-c"}, strings.Join(args, " "))
+	if !c.ShellInterpretation() {
+		return c.Execute(args[0], args[1:]...)
 	}
 
-	return c.ExecuteWithOptions(cmd, args, opts...)
+	log.Debugf("Executing command line: %s", strings.Join(args, " "))
+
+	return c.run(util.ShellCommand(strings.Join(args, " ")))
 }
 
-// RunCommand is going to run a command in a shell depending on the caller's operating system.
+// RunCommand runs the first arg as the command. With the WithShell option the args are joined into a command line
+// which is run by the shell of the caller's operating system.
 func (c *MockShell) RunCommand(args []string, opts ...Opt) ([]byte, error) {
-	var cmd string
-	if runtime.GOOS == "windows" {
-		cmd = "cmd"
-
-		args = append([]string{"/c"}, args...)
-	} else {
-		cmd = "sh"
+	if len(args) == 0 {
+		return nil, ErrEmptyCommand
+	}
 
-		args = append([]string{"-c"}, strings.Join(args, " "))
+	options := NewLocalShellWithOpts(opts...)
+	if !options.ShellInterpretation() {
+		return c.Execute(args[0], args[1:]...)
 	}
 
-	return c.ExecuteWithOptions(cmd, args, opts...)
+	shellArgs := util.HostPlatform.ShellArgs(strings.Join(args, " "))
+
+	return c.Execute(shellArgs[0], shellArgs[1:]...)
 }
 
-// ExitCodeOfCommand runs a command and returns its exit code.
+// ExitCodeOfCommand runs a command line by the shell and returns its exit code.
 func (c *LocalShell) ExitCodeOfCommand(command string) int {
 	var status int
 
-	_, err := c.RunCommand([]string{command})
+	_, err := c.RunCommand([]string{command}, WithShell())
 	if err != nil {
 		var exitError *exec.ExitError
 		if ok := errors.As(err, &exitError); ok {
@@ -241,11 +280,11 @@ func (c *LocalShell) ExitCodeOfCommand(command string) int {
 	return status
 }
 
-// ExitCodeOfCommand runs a command and returns its exit code.
+// ExitCodeOfCommand runs a command line by the shell and returns its exit code.
 func (c *MockShell) ExitCodeOfCommand(command string) int {
 	var status int
 
-	_, err := c.RunCommand([]string{command})
+	_, err := c.RunCommand([]string{command}, WithShell())
 	if err != nil {
 		var exitError *exec.ExitError
 		if ok := errors.As(err, &exitError); ok {
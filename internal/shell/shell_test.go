@@ -370,3 +370,72 @@ func (suite *ShellTestSuite) TestMockShell_ExecuteWithOptions() {
 		})
 	}
 }
+
+func (suite *ShellTestSuite) TestLocalShell_RunCommand() {
+	tests := []struct {
+		name    string
+		args    []string
+		opts    []Opt
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "argument with spaces",
+			args: []string{"echo", "/home/John Doe/shop"},
+			want: "/home/John Doe/shop\n",
+		},
+		{
+			name: "special characters are not interpreted",
+			args: []string{"echo", "shop; touch pwned", "$HOME", "`id`", "a|b"},
+			want: "shop; touch pwned $HOME `id` a|b\n",
+		},
+		{
+			name:    "empty command",
+			args:    []string{},
+			wantErr: true,
+		},
+		{
+			name: "quoted argument with shell",
+			args: []string{"echo", util.Quote("{{ json . }}")},
+			opts: []Opt{WithShell()},
+			want: "{{ json . }}\n",
+		},
+		{
+			name: "pipeline with shell",
+			args: []string{"echo", "test", "|", "tr", "t", "T"},
+			opts: []Opt{WithShell()},
+			want: "TesT\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			c := NewLocalShellWithOpts()
+
+			got, err := c.RunCommand(tt.args, append(tt.opts, WithCatchOutput(true), WithSuppressOutput(true))...)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.False(t, c.ShellInterpretation(), "the options should be reset")
+		})
+	}
+}
+
+func (suite *ShellTestSuite) TestMockShell_RunCommand() {
+	c := NewMockShell("", nil, nil)
+
+	_, err := c.RunCommand([]string{"echo", "test"})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "echo", c.LastCommand)
+
+	_, err = c.RunCommand([]string{"echo", "test"}, WithShell())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), util.HostPlatform.ShellArgs("")[0], c.LastCommand)
+}
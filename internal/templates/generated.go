@@ -0,0 +1,128 @@
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/docker/go-units"
+	"github.com/pmezard/go-difflib/difflib"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/rewardenv/reward/internal/trash"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// ErrGeneratedFileModified occurs when a generated file was changed since it was generated, so it's not overwritten
+// to keep the local changes.
+var ErrGeneratedFileModified = func(file string) error {
+	return fmt.Errorf("%s was modified since it was generated, run the command with --force to overwrite it", file)
+}
+
+// generatedHeader matches the first line of the generated files, which contains the checksum of the rest of the file.
+var generatedHeader = regexp.MustCompile(`^# Generated by \S+, checksum: sha256:([0-9a-f]{64})\b.*\n`)
+
+// WriteGeneratedFile writes the content to the file with a header containing its checksum. If the file was modified
+// since it was generated, the diff of the local changes is logged and the file is not overwritten unless the --force
+// flag is used.
+func (c *Client) WriteGeneratedFile(content []byte, file string, perm os.FileMode) error {
+	err := c.CheckGeneratedFile(file, content)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf(
+		"# Generated by %s, checksum: sha256:%x. Local changes are kept unless --force is used.\n",
+		c.AppName(), sha256.Sum256(content),
+	)
+
+	err = util.CreateDirAndWriteToFile(append([]byte(header), content...), file, perm)
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// CheckGeneratedFile returns ErrGeneratedFileModified if the generated file was modified since it was generated and
+// the --force flag is not used. The diff between the modified file and the content replacing it (nil if the file is
+// removed) is logged. The files which don't exist, don't have the header (eg. they were generated by an older
+// version) or already have the content are not checked.
+func (c *Client) CheckGeneratedFile(file string, content []byte) error {
+	current, err := os.ReadFile(file)
+	if err != nil {
+		// the file doesn't exist yet, or writing it fails with the same error
+		return nil //nolint:nilerr
+	}
+
+	match := generatedHeader.FindSubmatch(current)
+	if match == nil {
+		log.Debugf("%s doesn't have a checksum, overwriting it.", file)
+
+		return nil
+	}
+
+	body := current[len(match[0]):]
+	if fmt.Sprintf("%x", sha256.Sum256(body)) == string(match[1]) || bytes.Equal(body, content) {
+		return nil
+	}
+
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(body)),
+		B:        difflib.SplitLines(string(content)),
+		FromFile: file + " (modified)",
+		ToFile:   file + " (generated)",
+		Context:  3,
+	})
+
+	if viper.GetBool("force") {
+		log.Warnf("Overwriting the local changes of %s:\n%s", file, diff)
+
+		return c.saveToTrash(file)
+	}
+
+	log.Warnf("Local changes of %s:\n%s", file, diff)
+
+	return ErrGeneratedFileModified(file)
+}
+
+// saveToTrash saves the modified generated file to the trash before it's overwritten, so it can be restored by
+// `reward undo last`.
+func (c *Client) saveToTrash(file string) error {
+	t := c.trash()
+	if !t.Enabled() {
+		return nil
+	}
+
+	op, err := t.Operation()
+	if err != nil {
+		return fmt.Errorf("cannot save %s to the trash: %w", file, err)
+	}
+
+	err = op.SaveFile(file)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	log.Printf("The modified %s is saved to the trash, run `%s undo last` to restore it.", file, c.AppName())
+
+	_, err = t.Prune()
+	if err != nil {
+		log.Warnf("Cannot prune the trash: %s", err)
+	}
+
+	return nil
+}
+
+// trash returns the trash of the destructive operations (see config.Config.Trash).
+func (c *Client) trash() *trash.Trash {
+	maxSize, _ := units.RAMInBytes(viper.GetString(fmt.Sprintf("%s_trash_max_size", c.AppName())))
+
+	return trash.New(
+		filepath.Join(c.AppHomeDir(), "trash"), viper.GetInt(fmt.Sprintf("%s_trash_retention", c.AppName())), maxSize,
+	)
+}
@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func (suite *TemplatesTestSuite) TestWriteGeneratedFile() {
+	c := New()
+	file := filepath.Join(suite.T().TempDir(), "etc", "traefik.yml")
+
+	assert.NoError(suite.T(), c.WriteGeneratedFile([]byte("a: 1\n"), file, 0o644))
+
+	content, err := os.ReadFile(file)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(string(content), "# Generated by reward, checksum: sha256:"))
+	assert.True(suite.T(), strings.HasSuffix(string(content), "\na: 1\n"))
+
+	// the unmodified file is regenerated
+	assert.NoError(suite.T(), c.WriteGeneratedFile([]byte("a: 2\n"), file, 0o644))
+
+	// the modified file is kept
+	content, _ = os.ReadFile(file)
+	assert.NoError(suite.T(), os.WriteFile(file, append(content, "b: custom\n"...), 0o600))
+	assert.EqualError(suite.T(), c.WriteGeneratedFile([]byte("a: 3\n"), file, 0o644),
+		ErrGeneratedFileModified(file).Error())
+	assert.EqualError(suite.T(), c.CheckGeneratedFile(file, nil), ErrGeneratedFileModified(file).Error())
+
+	content, _ = os.ReadFile(file)
+	assert.Contains(suite.T(), string(content), "b: custom\n")
+
+	// unless it's forced
+	viper.Set("force", true)
+
+	assert.NoError(suite.T(), c.WriteGeneratedFile([]byte("a: 3\n"), file, 0o644))
+
+	content, _ = os.ReadFile(file)
+	assert.NotContains(suite.T(), string(content), "b: custom\n")
+}
+
+func (suite *TemplatesTestSuite) TestWriteGeneratedFileWithoutChecksum() {
+	file := filepath.Join(suite.T().TempDir(), "Corefile")
+
+	// the files generated by older versions don't have a checksum
+	assert.NoError(suite.T(), os.WriteFile(file, []byte("old\n"), 0o600))
+	assert.NoError(suite.T(), New().WriteGeneratedFile([]byte("new\n"), file, 0o644))
+}
+
+func (suite *TemplatesTestSuite) TestWriteGeneratedFileTrash() {
+	home := suite.T().TempDir()
+	file := filepath.Join(suite.T().TempDir(), "default.conf")
+
+	viper.Set("reward_home_dir", home)
+	viper.Set("reward_trash_retention", 7)
+	viper.Set("force", true)
+
+	c := New()
+
+	assert.NoError(suite.T(), c.WriteGeneratedFile([]byte("a: 1\n"), file, 0o644))
+
+	content, _ := os.ReadFile(file)
+	assert.NoError(suite.T(), os.WriteFile(file, append(content, "b: custom\n"...), 0o600))
+	assert.NoError(suite.T(), c.WriteGeneratedFile([]byte("a: 2\n"), file, 0o644))
+
+	// the modified file is saved to the trash before it's overwritten
+	op, err := c.trash().Last()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), op.Entries, 1)
+	assert.Equal(suite.T(), file, op.Entries[0].Name)
+
+	saved, err := os.ReadFile(op.Path(op.Entries[0].File))
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), string(saved), "b: custom\n")
+}
@@ -3,12 +3,14 @@ package templates
 import (
 	"bytes"
 	"container/list"
+	"crypto/sha256"
 	"embed"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"runtime"
 	"strings"
 	"text/template"
@@ -33,11 +35,12 @@ func New() *Client {
 	}
 }
 
-// Cwd returns the current working directory.
+// Cwd returns the current working directory. It's empty if the working directory cannot be determined, the commands
+// report it before rendering the templates.
 func (c *Client) Cwd() string {
 	cwd, err := os.Getwd()
 	if err != nil {
-		log.Panicln(err)
+		log.Debugf("Cannot determine the working directory: %s", err)
 	}
 
 	return cwd
@@ -452,11 +455,7 @@ func (c *Client) SvcGenerateTraefikConfig() error {
 		}
 	}
 
-	err = util.CreateDirAndWriteToFile(
-		bs.Bytes(),
-		filepath.Join(c.AppHomeDir(), "etc/traefik/traefik.yml"),
-		0o644,
-	)
+	err = c.WriteGeneratedFile(bs.Bytes(), filepath.Join(c.AppHomeDir(), "etc/traefik/traefik.yml"), 0o644)
 	if err != nil {
 		return fmt.Errorf("cannot write traefik template file: %w", err)
 	}
@@ -464,8 +463,63 @@ func (c *Client) SvcGenerateTraefikConfig() error {
 	return nil
 }
 
-// SvcGenerateTraefikDynamicConfig generates the dynamic traefik configuration.
-func (c *Client) SvcGenerateTraefikDynamicConfig(svcDomain string) error {
+// SvcGenerateCorednsConfig generates the Corefile of the CoreDNS common service to the directory.
+func (c *Client) SvcGenerateCorednsConfig(dir string) error {
+	return c.generateConfigFile("templates/coredns/Corefile", filepath.Join(dir, "Corefile"))
+}
+
+// SvcGenerateTraefikProxyConfig generates the configuration of the port proxy which forwards the connections to
+// traefik, if the rolling restart of traefik is enabled.
+func (c *Client) SvcGenerateTraefikProxyConfig() error {
+	return c.generateConfigFile(
+		"templates/traefik/haproxy.cfg", filepath.Join(c.AppHomeDir(), "etc/traefik/haproxy.cfg"),
+	)
+}
+
+// GenerateNginxStaticConfig generates the nginx server template of the static environments, which serves the build
+// directory with an optional single page application fallback.
+func (c *Client) GenerateNginxStaticConfig(file string) error {
+	return c.generateConfigFile("templates/nginx/static.conf", file)
+}
+
+// GenerateNginxOroWebsocketConfig generates the nginx server snippet of the oro environments, which proxies the
+// websocket connections to the websocket server.
+func (c *Client) GenerateNginxOroWebsocketConfig(file string) error {
+	return c.generateConfigFile("templates/nginx/server-oro-websocket.conf", file)
+}
+
+// generateConfigFile renders the static template to the file.
+func (c *Client) generateConfigFile(path, file string) error {
+	var (
+		bs      bytes.Buffer
+		tpl     = template.New(filepath.Base(path))
+		tplList = list.New()
+	)
+
+	err := c.AppendTemplatesFromPathsStatic(tpl, tplList, []string{path})
+	if err != nil {
+		return fmt.Errorf("cannot append %s template: %w", filepath.Base(path), err)
+	}
+
+	for e := tplList.Front(); e != nil; e = e.Next() {
+		tplName := fmt.Sprint(e.Value)
+
+		err = c.ExecuteTemplate(tpl.Lookup(tplName), &bs)
+		if err != nil {
+			return fmt.Errorf("cannot execute template %s: %w", tplName, err)
+		}
+	}
+
+	return c.WriteGeneratedFile(bs.Bytes(), file, 0o644)
+}
+
+// SvcGenerateTraefikDynamicConfig generates the dynamic traefik configuration in the directory watched by the file
+// provider of traefik. The dashboardUsers (in htpasswd format) can access the dashboard and the API of traefik.
+// The configuration contains an empty middleware named after the checksum of the configuration, its name is
+// returned, so the caller can verify via the traefik API that traefik loaded the configuration.
+func (c *Client) SvcGenerateTraefikDynamicConfig(
+	svcDomain string, dashboardUsers, dashboardSourceRange []string,
+) (string, error) {
 	traefikConfig := fmt.Sprintf(
 		`tls:
   stores:
@@ -478,7 +532,7 @@ func (c *Client) SvcGenerateTraefikDynamicConfig(svcDomain string) error {
 
 	files, err := filepath.Glob(filepath.Join(c.AppHomeDir(), "ssl/certs", "*.crt.pem"))
 	if err != nil {
-		return fmt.Errorf("cannot list ssl certificates: %w", err)
+		return "", fmt.Errorf("cannot list ssl certificates: %w", err)
 	}
 
 	log.Debugf("Available certificates: %s", files)
@@ -497,12 +551,314 @@ func (c *Client) SvcGenerateTraefikDynamicConfig(svcDomain string) error {
 		)
 	}
 
-	err = util.CreateDirAndWriteToFile(
-		[]byte(traefikConfig), filepath.Join(c.AppHomeDir(), "etc/traefik", "dynamic.yml"), 0o644,
+	traefikConfig += c.traefikTLSOptions()
+	traefikConfig += c.traefikDashboardMiddlewares(dashboardUsers, dashboardSourceRange)
+
+	checksum := sha256.Sum256([]byte(traefikConfig))
+	marker := fmt.Sprintf("%s-config-%x", c.AppName(), checksum[:6])
+	traefikConfig += fmt.Sprintf(
+		`    %s:
+      headers: {}
+`, marker,
+	)
+
+	err = c.WriteGeneratedFile(
+		[]byte(traefikConfig), filepath.Join(c.AppHomeDir(), "etc/traefik/dynamic", c.AppName()+".yml"), 0o644,
 	)
 	if err != nil {
-		return fmt.Errorf("cannot write traefik dynamic configuration file: %w", err)
+		return "", fmt.Errorf("cannot write traefik dynamic configuration file: %w", err)
 	}
 
-	return nil
+	return marker, nil
+}
+
+// traefikTLSOptions returns the default TLS options of the traefik dynamic configuration based on the
+// min/max TLS version and cipher suite settings.
+func (c *Client) traefikTLSOptions() string {
+	var (
+		minVersion   = viper.GetString(fmt.Sprintf("%s_traefik_tls_min_version", c.AppName()))
+		maxVersion   = viper.GetString(fmt.Sprintf("%s_traefik_tls_max_version", c.AppName()))
+		cipherSuites = viper.GetStringSlice(fmt.Sprintf("%s_traefik_tls_cipher_suites", c.AppName()))
+	)
+
+	if minVersion == "" && maxVersion == "" && len(cipherSuites) == 0 {
+		return ""
+	}
+
+	options := `
+  options:
+    default:`
+
+	if minVersion != "" {
+		options += fmt.Sprintf(`
+      minVersion: %s`, minVersion)
+	}
+
+	if maxVersion != "" {
+		options += fmt.Sprintf(`
+      maxVersion: %s`, maxVersion)
+	}
+
+	if len(cipherSuites) > 0 {
+		options += `
+      cipherSuites:`
+
+		for _, cipherSuite := range cipherSuites {
+			options += fmt.Sprintf(`
+        - %s`, cipherSuite)
+		}
+	}
+
+	return options + "\n"
+}
+
+// traefikDashboardMiddlewares returns the middlewares of the traefik dashboard and API router: basic auth for the
+// users and an allow list of the source ranges, so the dashboard is not reachable from the other hosts even if traefik
+// listens on every interface.
+func (c *Client) traefikDashboardMiddlewares(users, sourceRange []string) string {
+	// ipWhiteList was renamed to ipAllowList in traefik 3.0.
+	allowList := "ipAllowList"
+	if regexp.MustCompile(`^v?2\.`).MatchString(
+		viper.GetString(fmt.Sprintf("%s_traefik_version", c.AppName())),
+	) {
+		allowList = "ipWhiteList"
+	}
+
+	middlewares := fmt.Sprintf(`
+http:
+  middlewares:
+    %[1]s-internal-network:
+      %[2]s:
+        sourceRange:
+`, c.AppName(), allowList)
+
+	for _, ipRange := range sourceRange {
+		middlewares += fmt.Sprintf("          - %q\n", ipRange)
+	}
+
+	middlewares += fmt.Sprintf(`    %s-dashboard-auth:
+      basicAuth:
+        users:
+`, c.AppName())
+
+	for _, user := range users {
+		middlewares += fmt.Sprintf("          - %q\n", user)
+	}
+
+	return middlewares
+}
+
+// AppendComposeFiles appends the docker-compose files to the configurations as they are, they're not rendered as
+// templates (eg. the go templates of the healthcheck commands are kept).
+func (c *Client) AppendComposeFiles(details compose.ConfigDetails, files []string) (compose.ConfigDetails, error) {
+	for _, file := range files {
+		bs, err := util.FS.ReadFile(file)
+		if err != nil {
+			return details, fmt.Errorf("cannot read compose file %s: %w", file, err)
+		}
+
+		config, err := loader.ParseYAML(bs)
+		if err != nil {
+			return details, fmt.Errorf("error parsing compose file %s: %w", file, err)
+		}
+
+		details.ConfigFiles = append(details.ConfigFiles, compose.ConfigFile{Filename: file, Config: config})
+	}
+
+	return details, nil
+}
+
+// AppendLabelsConfig appends a docker-compose configuration which adds the labels to every service, network and
+// volume of the configurations. The serviceLabels are added to the services only. External networks and volumes are
+// not managed by docker-compose, so they are not labeled. The labels of the existing networks and volumes cannot be
+// changed, so the ones for which skip returns true (eg. created by a previous version without the labels) are not
+// labeled either. skip is called with the kind ("networks" or "volumes") and the docker name of the resource in the
+// project.
+func (c *Client) AppendLabelsConfig(
+	details compose.ConfigDetails,
+	project string,
+	labels, serviceLabels map[string]string,
+	skip func(kind, name string) bool,
+) compose.ConfigDetails {
+	var (
+		services = make(map[string]interface{})
+		networks = make(map[string]interface{})
+		volumes  = make(map[string]interface{})
+		external = map[string]map[string]bool{"networks": {}, "volumes": {}}
+		names    = map[string]map[string]string{"networks": {}, "volumes": {}}
+	)
+
+	for _, configFile := range details.ConfigFiles {
+		for key, target := range map[string]map[string]interface{}{
+			"services": services,
+			"networks": networks,
+			"volumes":  volumes,
+		} {
+			resources, ok := configFile.Config[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for name, resource := range resources {
+				if r, ok := resource.(map[string]interface{}); ok && r["external"] != nil && r["external"] != false {
+					external[key][name] = true
+				}
+
+				if r, ok := resource.(map[string]interface{}); ok && key != "services" {
+					if n, ok := r["name"].(string); ok && n != "" {
+						names[key][name] = n
+					}
+				}
+
+				target[name] = nil
+			}
+		}
+	}
+
+	config := make(map[string]interface{})
+
+	if len(services) > 0 {
+		for name := range services {
+			services[name] = map[string]interface{}{"labels": mergeLabels(labels, serviceLabels)}
+		}
+
+		config["services"] = services
+	}
+
+	for key, resources := range map[string]map[string]interface{}{"networks": networks, "volumes": volumes} {
+		for name := range resources {
+			dockerName, ok := names[key][name]
+			if !ok {
+				dockerName = project + "_" + name
+			}
+
+			if external[key][name] || (skip != nil && skip(key, dockerName)) {
+				delete(resources, name)
+
+				continue
+			}
+
+			resources[name] = map[string]interface{}{"labels": mergeLabels(labels)}
+		}
+
+		if len(resources) > 0 {
+			config[key] = resources
+		}
+	}
+
+	if len(config) == 0 {
+		return details
+	}
+
+	return c.appendConfig(details, fmt.Sprintf("%s-labels.yml", c.AppName()), config)
+}
+
+// AppendResourcesConfig appends a configuration to the docker-compose config details which limits the memory of the
+// services. The memoryLimit function returns the limit of a service (eg. 2g), or an empty string if the service is
+// not limited.
+func (c *Client) AppendResourcesConfig(
+	details compose.ConfigDetails,
+	memoryLimit func(service string) string,
+) compose.ConfigDetails {
+	services := make(map[string]interface{})
+
+	for _, configFile := range details.ConfigFiles {
+		resources, ok := configFile.Config["services"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name := range resources {
+			limit := memoryLimit(name)
+			if limit == "" {
+				continue
+			}
+
+			services[name] = map[string]interface{}{
+				"deploy": map[string]interface{}{
+					"resources": map[string]interface{}{
+						"limits": map[string]interface{}{"memory": limit},
+					},
+				},
+			}
+		}
+	}
+
+	if len(services) == 0 {
+		return details
+	}
+
+	return c.appendConfig(
+		details,
+		fmt.Sprintf("%s-resources.yml", c.AppName()),
+		map[string]interface{}{"services": services},
+	)
+}
+
+// AppendImageOverridesConfig appends a configuration to the docker-compose config details which replaces the images
+// of the services with the overrides. The overrides of the services which are not in the configurations are ignored,
+// so a disabled service is not started by its override.
+func (c *Client) AppendImageOverridesConfig(
+	details compose.ConfigDetails,
+	overrides map[string]string,
+) compose.ConfigDetails {
+	services := make(map[string]interface{})
+
+	for _, configFile := range details.ConfigFiles {
+		configured, ok := configFile.Config["services"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for name := range configured {
+			if image, ok := overrides[name]; ok {
+				services[name] = map[string]interface{}{"image": image}
+			}
+		}
+	}
+
+	if len(services) == 0 {
+		return details
+	}
+
+	return c.appendConfig(
+		details,
+		fmt.Sprintf("%s-images.yml", c.AppName()),
+		map[string]interface{}{"services": services},
+	)
+}
+
+// appendConfig appends the generated config to the config details using the version of the existing configurations.
+func (c *Client) appendConfig(
+	details compose.ConfigDetails,
+	filename string,
+	config map[string]interface{},
+) compose.ConfigDetails {
+	// The version of the configurations has to match.
+	for _, configFile := range details.ConfigFiles {
+		if v, ok := configFile.Config["version"]; ok {
+			config["version"] = v
+
+			break
+		}
+	}
+
+	details.ConfigFiles = append(details.ConfigFiles, compose.ConfigFile{
+		Filename: filename,
+		Config:   config,
+	})
+
+	return details
+}
+
+func mergeLabels(labels ...map[string]string) map[string]interface{} {
+	merged := make(map[string]interface{})
+
+	for _, l := range labels {
+		for k, v := range l {
+			merged[k] = v
+		}
+	}
+
+	return merged
 }
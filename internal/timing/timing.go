@@ -0,0 +1,121 @@
+// Package timing records how long the phases of a command take (eg. rendering the templates, running docker compose,
+// connecting the peered services, waiting for the services to become healthy). The phases with the same name are
+// aggregated. The breakdown is printed after the command if the --profile flag is set.
+package timing
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Phase is the aggregated time of the runs of a phase of the command.
+type Phase struct {
+	Name     string
+	Count    int
+	Duration time.Duration
+}
+
+var (
+	// started is the time the command was started, every invocation of reward runs a single command.
+	started = time.Now()
+	phases  []*Phase
+	mu      sync.Mutex
+)
+
+// Start starts measuring the phase, the returned function stops it. It's meant to be deferred:
+//
+//	defer timing.Start("render templates")()
+func Start(name string) func() {
+	start := time.Now()
+
+	return func() {
+		Record(name, time.Since(start))
+	}
+}
+
+// Record adds the duration to the phase.
+func Record(name string, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, phase := range phases {
+		if phase.Name == name {
+			phase.Count++
+			phase.Duration += d
+
+			return
+		}
+	}
+
+	phases = append(phases, &Phase{Name: name, Count: 1, Duration: d})
+}
+
+// Phases returns the recorded phases in the order they were first started.
+func Phases() []Phase {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := make([]Phase, 0, len(phases))
+	for _, phase := range phases {
+		result = append(result, *phase)
+	}
+
+	return result
+}
+
+// Reset removes the recorded phases.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	phases = nil
+	started = time.Now()
+}
+
+// Elapsed returns the time since the command was started.
+func Elapsed() time.Duration {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return time.Since(started)
+}
+
+// Write writes the breakdown of the phases and the total time of the command. The phases can overlap (eg. the
+// templates are rendered for every docker compose run), the rest is the time which is not covered by any phase.
+func Write(w io.Writer, total time.Duration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	_, _ = fmt.Fprintln(tw, "Phase\tRuns\tTime\t%\t")
+
+	var covered time.Duration
+
+	for _, phase := range Phases() {
+		covered += phase.Duration
+
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%.1f\t\n", phase.Name, phase.Count, round(phase.Duration),
+			percent(phase.Duration, total))
+	}
+
+	if rest := total - covered; rest > 0 {
+		_, _ = fmt.Fprintf(tw, "other\t\t%s\t%.1f\t\n", round(rest), percent(rest, total))
+	}
+
+	_, _ = fmt.Fprintf(tw, "total\t\t%s\t\t\n", round(total))
+
+	return tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+func percent(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return float64(d) / float64(total) * 100
+}
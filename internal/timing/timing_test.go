@@ -0,0 +1,55 @@
+package timing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TimingTestSuite struct {
+	suite.Suite
+}
+
+func TestTimingTestSuite(t *testing.T) {
+	suite.Run(t, new(TimingTestSuite))
+}
+
+func (suite *TimingTestSuite) SetupTest() {
+	Reset()
+}
+
+func (suite *TimingTestSuite) TestRecord() {
+	Record("render templates", 100*time.Millisecond)
+	Record("docker compose", 2*time.Second)
+	Record("render templates", 50*time.Millisecond)
+
+	assert.Equal(suite.T(), []Phase{
+		{Name: "render templates", Count: 2, Duration: 150 * time.Millisecond},
+		{Name: "docker compose", Count: 1, Duration: 2 * time.Second},
+	}, Phases())
+
+	stop := Start("network peering")
+	stop()
+
+	assert.Len(suite.T(), Phases(), 3)
+}
+
+func (suite *TimingTestSuite) TestWrite() {
+	Record("render templates", 500*time.Millisecond)
+	Record("docker compose", 3*time.Second)
+
+	var buf bytes.Buffer
+
+	assert.NoError(suite.T(), Write(&buf, 4*time.Second))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(suite.T(), lines, 5)
+	assert.Regexp(suite.T(), `render templates\s+1\s+500ms\s+12.5`, lines[1])
+	assert.Regexp(suite.T(), `docker compose\s+1\s+3s\s+75.0`, lines[2])
+	assert.Regexp(suite.T(), `other\s+500ms\s+12.5`, lines[3])
+	assert.Regexp(suite.T(), `total\s+4s`, lines[4])
+}
@@ -0,0 +1,395 @@
+// Package trash keeps the data removed or overwritten by the destructive operations (eg. the volumes removed by
+// `env down --volumes`, the generated files overwritten with --force) for a limited time, so the last operation can be
+// undone. Every invocation of reward runs a single command, the data saved by a command is stored in a single
+// operation directory with a manifest describing how to restore it.
+package trash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrEmpty occurs when there is no operation in the trash to undo.
+var ErrEmpty = fmt.Errorf("the trash is empty, there is nothing to undo")
+
+const (
+	// EntryFile is an entry of a file, the copy of the file is stored in the operation directory.
+	EntryFile = "file"
+	// EntryVolume is an entry of a docker volume, the archive of its content is stored in the operation directory.
+	EntryVolume = "volume"
+
+	manifestFile = "manifest.json"
+	timeFormat   = "20060102150405"
+)
+
+var (
+	// current is the operation of the running command by trash directories.
+	current = make(map[string]*Operation)
+	mu      sync.Mutex
+)
+
+// Trash is the directory of the saved operations. The operations are removed after the retention period, and the
+// oldest ones are removed if the size of the trash exceeds its maximum size.
+type Trash struct {
+	dir       string
+	retention time.Duration
+	maxSize   int64
+}
+
+// Operation is the data saved by a command.
+type Operation struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	// Dir is the working directory of the command.
+	Dir     string   `json:"dir"`
+	Entries []*Entry `json:"entries"`
+
+	path string
+}
+
+// Entry is a file or a volume saved by the operation.
+type Entry struct {
+	Type string `json:"type"`
+	// Name is the original path of a file, or the name of a volume.
+	Name string `json:"name"`
+	// Mode is the permission of a file.
+	Mode os.FileMode `json:"mode,omitempty"`
+	// Labels are the labels of a volume, they're set when the volume is created again.
+	Labels map[string]string `json:"labels,omitempty"`
+	// File is the name of the saved copy or archive in the operation directory.
+	File string `json:"file"`
+}
+
+// New returns the trash in the directory. The trash is disabled if the retention is not positive, the size of the
+// trash is not limited if maxSize is not positive.
+func New(dir string, retentionDays int, maxSize int64) *Trash {
+	return &Trash{
+		dir:       dir,
+		retention: time.Duration(retentionDays) * 24 * time.Hour,
+		maxSize:   maxSize,
+	}
+}
+
+// Enabled returns true if the destructive operations are saved to the trash.
+func (t *Trash) Enabled() bool {
+	return t.retention > 0
+}
+
+// Dir returns the directory of the trash.
+func (t *Trash) Dir() string {
+	return t.dir
+}
+
+// Operation returns the operation of the running command, it's created on the first call.
+func (t *Trash) Operation() (*Operation, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if op, ok := current[t.dir]; ok {
+		return op, nil
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("%s-%d", now.Format(timeFormat), os.Getpid())
+	cwd, _ := os.Getwd()
+
+	op := &Operation{
+		ID:      id,
+		Time:    now,
+		Command: strings.Join(os.Args[1:], " "),
+		Dir:     cwd,
+		Entries: []*Entry{},
+		path:    filepath.Join(t.dir, id),
+	}
+
+	err := os.MkdirAll(op.path, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trash directory: %w", err)
+	}
+
+	err = op.save()
+	if err != nil {
+		return nil, err
+	}
+
+	current[t.dir] = op
+
+	return op, nil
+}
+
+// List returns the operations which are not expired, the most recent first.
+func (t *Trash) List() ([]*Operation, error) {
+	dirs, err := os.ReadDir(t.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot list trash: %w", err)
+	}
+
+	ops := make([]*Operation, 0, len(dirs))
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		op, err := load(filepath.Join(t.dir, dir.Name()))
+		if err != nil || t.expired(op) {
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Time.After(ops[j].Time)
+	})
+
+	return ops, nil
+}
+
+// Last returns the most recent operation which is not expired, or ErrEmpty.
+func (t *Trash) Last() (*Operation, error) {
+	ops, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		if len(op.Entries) > 0 {
+			return op, nil
+		}
+	}
+
+	return nil, ErrEmpty
+}
+
+// Remove removes the operation from the trash.
+func (t *Trash) Remove(op *Operation) error {
+	mu.Lock()
+	if running, ok := current[t.dir]; ok && running.ID == op.ID {
+		delete(current, t.dir)
+	}
+	mu.Unlock()
+
+	err := os.RemoveAll(op.path)
+	if err != nil {
+		return fmt.Errorf("cannot remove %s from the trash: %w", op.ID, err)
+	}
+
+	return nil
+}
+
+// Fits returns true if an operation of the size can be saved to the trash without exceeding its maximum size.
+func (t *Trash) Fits(size int64) bool {
+	return t.maxSize <= 0 || size <= t.maxSize
+}
+
+// Prune removes the expired operations, the operations which are larger than the maximum size of the trash, and the
+// oldest operations while the size of the trash exceeds its maximum size. The older operations are never removed for
+// an operation which doesn't fit in the trash by itself. The removed operations are returned.
+func (t *Trash) Prune() ([]*Operation, error) {
+	dirs, err := os.ReadDir(t.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot list trash: %w", err)
+	}
+
+	var (
+		removed []*Operation
+		kept    []*Operation
+		size    int64
+	)
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		op, err := load(filepath.Join(t.dir, dir.Name()))
+		if err != nil {
+			// the directory is not an operation or its manifest is broken, it cannot be restored
+			op = &Operation{ID: dir.Name(), path: filepath.Join(t.dir, dir.Name())}
+		}
+
+		if err != nil || t.expired(op) {
+			removed = append(removed, op)
+
+			continue
+		}
+
+		opSize := op.Size()
+		if !t.Fits(opSize) {
+			removed = append(removed, op)
+
+			continue
+		}
+
+		kept = append(kept, op)
+		size += opSize
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].Time.Before(kept[j].Time)
+	})
+
+	for len(kept) > 0 && t.maxSize > 0 && size > t.maxSize {
+		size -= kept[0].Size()
+		removed = append(removed, kept[0])
+		kept = kept[1:]
+	}
+
+	for _, op := range removed {
+		err = t.Remove(op)
+		if err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// expired returns true if the operation is older than the retention period.
+func (t *Trash) expired(op *Operation) bool {
+	return time.Since(op.Time) > t.retention
+}
+
+// Path returns the path of a file in the operation directory.
+func (op *Operation) Path(file string) string {
+	return filepath.Join(op.path, file)
+}
+
+// Size returns the size of the files saved by the operation.
+func (op *Operation) Size() int64 {
+	var size int64
+
+	_ = filepath.Walk(op.path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+// SaveFile copies the file to the operation before it's overwritten or removed.
+func (op *Operation) SaveFile(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("cannot determine the path of %s: %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot save %s to the trash: %w", path, err)
+	}
+
+	entry := &Entry{
+		Type: EntryFile,
+		Name: path,
+		Mode: info.Mode().Perm(),
+		File: fmt.Sprintf("%d-%s", len(op.Entries), filepath.Base(path)),
+	}
+
+	err = copyFile(path, op.Path(entry.File), 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot save %s to the trash: %w", path, err)
+	}
+
+	return op.Add(entry)
+}
+
+// RestoreFile restores the file saved by the operation to its original path.
+func (op *Operation) RestoreFile(entry *Entry) error {
+	err := os.MkdirAll(filepath.Dir(entry.Name), 0o755)
+	if err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	err = copyFile(op.Path(entry.File), entry.Name, entry.Mode)
+	if err != nil {
+		return fmt.Errorf("cannot restore %s: %w", entry.Name, err)
+	}
+
+	return nil
+}
+
+// Add adds the entry to the manifest of the operation, its file has to be saved to the operation directory.
+func (op *Operation) Add(entry *Entry) error {
+	op.Entries = append(op.Entries, entry)
+
+	return op.save()
+}
+
+// save writes the manifest of the operation.
+func (op *Operation) save() error {
+	content, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal trash manifest: %w", err)
+	}
+
+	err = os.WriteFile(op.Path(manifestFile), content, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write trash manifest: %w", err)
+	}
+
+	return nil
+}
+
+// load reads the operation from its directory.
+func load(path string) (*Operation, error) {
+	content, err := os.ReadFile(filepath.Join(path, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read trash manifest: %w", err)
+	}
+
+	op := &Operation{}
+
+	err = json.Unmarshal(content, op)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse trash manifest: %w", err)
+	}
+
+	op.path = path
+
+	return op, nil
+}
+
+func copyFile(from, to string, perm os.FileMode) error {
+	//nolint:gosec
+	src, err := os.Open(from)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	defer src.Close()
+
+	//nolint:gosec
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		_ = dst.Close()
+
+		return err //nolint:wrapcheck
+	}
+
+	return dst.Close() //nolint:wrapcheck
+}
@@ -0,0 +1,141 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TrashTestSuite struct {
+	suite.Suite
+}
+
+func TestTrashTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashTestSuite))
+}
+
+// newTestOperation saves an operation with a file of the size to the trash.
+func newTestOperation(t *testing.T, dir, id string, age time.Duration, size int) {
+	t.Helper()
+
+	op := &Operation{ID: id, Time: time.Now().Add(-age), Entries: []*Entry{}, path: filepath.Join(dir, id)}
+
+	assert.NoError(t, os.MkdirAll(op.path, 0o700))
+	assert.NoError(t, os.WriteFile(op.Path("data"), make([]byte, size), 0o600))
+	assert.NoError(t, op.Add(&Entry{Type: EntryVolume, Name: id, File: "data"}))
+}
+
+func (suite *TrashTestSuite) TestSaveAndRestoreFile() {
+	dir := suite.T().TempDir()
+	file := filepath.Join(suite.T().TempDir(), "nginx", "default.conf")
+
+	assert.NoError(suite.T(), os.MkdirAll(filepath.Dir(file), 0o755))
+	assert.NoError(suite.T(), os.WriteFile(file, []byte("custom"), 0o640))
+
+	t := New(dir, 7, 0)
+
+	_, err := t.Last()
+	assert.ErrorIs(suite.T(), err, ErrEmpty)
+
+	op, err := t.Operation()
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), op.SaveFile(file))
+
+	// the operation of the running command is reused
+	same, err := t.Operation()
+	assert.NoError(suite.T(), err)
+	assert.Same(suite.T(), op, same)
+
+	assert.NoError(suite.T(), os.RemoveAll(filepath.Dir(file)))
+
+	last, err := t.Last()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), op.ID, last.ID)
+	assert.Len(suite.T(), last.Entries, 1)
+
+	assert.NoError(suite.T(), last.RestoreFile(last.Entries[0]))
+
+	content, err := os.ReadFile(file)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "custom", string(content))
+
+	info, err := os.Stat(file)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), os.FileMode(0o640), info.Mode().Perm())
+
+	assert.NoError(suite.T(), t.Remove(last))
+
+	_, err = t.Last()
+	assert.ErrorIs(suite.T(), err, ErrEmpty)
+}
+
+func (suite *TrashTestSuite) TestPrune() {
+	tests := []struct {
+		name    string
+		maxSize int64
+		huge    int
+		want    []string
+	}{
+		{
+			name: "expired",
+			want: []string{"new", "old"},
+		},
+		{
+			name:    "max size",
+			maxSize: 1500,
+			want:    []string{"new"},
+		},
+		{
+			name:    "larger than max size",
+			maxSize: 2500,
+			huge:    3000,
+			want:    []string{"new", "old"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			newTestOperation(t, dir, "expired", 8*24*time.Hour, 1000)
+			newTestOperation(t, dir, "old", 2*time.Hour, 1000)
+			newTestOperation(t, dir, "new", time.Hour, 1000)
+
+			if tt.huge > 0 {
+				newTestOperation(t, dir, "huge", time.Minute, tt.huge)
+			}
+			assert.NoError(t, os.MkdirAll(filepath.Join(dir, "broken"), 0o700))
+
+			tr := New(dir, 7, tt.maxSize)
+
+			_, err := tr.Prune()
+			assert.NoError(t, err)
+
+			ops, err := tr.List()
+			assert.NoError(t, err)
+
+			got := make([]string, 0, len(ops))
+			for _, op := range ops {
+				got = append(got, op.ID)
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.NoDirExists(t, filepath.Join(dir, "broken"))
+		})
+	}
+}
+
+func (suite *TrashTestSuite) TestFits() {
+	assert.True(suite.T(), New(suite.T().TempDir(), 7, 0).Fits(1<<40))
+	assert.True(suite.T(), New(suite.T().TempDir(), 7, 1000).Fits(1000))
+	assert.False(suite.T(), New(suite.T().TempDir(), 7, 1000).Fits(1001))
+}
+
+func (suite *TrashTestSuite) TestEnabled() {
+	assert.True(suite.T(), New(suite.T().TempDir(), 7, 0).Enabled())
+	assert.False(suite.T(), New(suite.T().TempDir(), 0, 0).Enabled())
+}
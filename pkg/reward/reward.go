@@ -0,0 +1,301 @@
+// Package reward is the Go API of reward. It drives the same operations as the CLI (eg. env up, db import), so
+// tools can embed them instead of running the reward binary and parsing its output.
+//
+// The settings of reward are process-wide (like in the CLI), so a process drives a single project at a time, and
+// the working directory of the process is changed to the directory of the project.
+package reward
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/logic"
+	"github.com/rewardenv/reward/pkg/util"
+)
+
+// appName is the name of the application, the settings and the labels of the containers are prefixed with it.
+const appName = "reward"
+
+// ErrEnvNotInitialized occurs when the directory of the project doesn't contain an initialized environment.
+var ErrEnvNotInitialized = func(dir string) error {
+	return fmt.Errorf("environment is not initialized in %s, run `reward env-init` first", dir)
+}
+
+// Reward drives the environment of a project.
+type Reward struct {
+	client *logic.Client
+}
+
+// Container is the state of a container of the environment.
+type Container struct {
+	// Service is the name of the docker compose service (eg. php-fpm).
+	Service string
+	Name    string
+	// State is the state of the container (eg. running, exited).
+	State string
+	// Health is the health status of the container (eg. healthy, starting), it's empty if it has no healthcheck.
+	Health string
+}
+
+// EnvResult is the result of EnvUp and EnvDown.
+type EnvResult struct {
+	Environment string
+	// Containers are the containers of the environment after the operation.
+	Containers []Container
+	Duration   time.Duration
+}
+
+// DBImportResult is the result of DBImport.
+type DBImportResult struct {
+	Environment string
+	Duration    time.Duration
+}
+
+type options struct {
+	dir        string
+	configFile string
+	version    string
+}
+
+// Option configures New.
+type Option func(*options)
+
+// WithDir sets the directory of the project, it's the working directory by default.
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithConfigFile sets the configuration file of reward, it's ~/.reward.yml (or ~/.config/reward/reward.yml on linux
+// if ~/.reward.yml doesn't exist) by default.
+func WithConfigFile(file string) Option {
+	return func(o *options) {
+		o.configFile = file
+	}
+}
+
+// WithVersion sets the version of reward the containers are labelled with. It's the version of the reward module
+// the program is built with by default. The containers are recreated by env up if it changes, so it should match the
+// version of the CLI used on the same projects.
+func WithVersion(ver string) Option {
+	return func(o *options) {
+		o.version = ver
+	}
+}
+
+// New loads the settings of reward and the environment of the project.
+func New(opts ...Option) (*Reward, error) {
+	o := &options{
+		configFile: config.DefaultConfigFile(appName),
+		version:    moduleVersion(),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.dir != "" {
+		err := os.Chdir(o.dir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot change to the directory of the project: %w", err)
+		}
+	}
+
+	conf := config.New(appName, o.version)
+	conf.Set(fmt.Sprintf("%s_config_file", appName), o.configFile)
+	conf.Init()
+
+	err := conf.InitErr()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conf.EnvCheck()
+	if err != nil {
+		return nil, fmt.Errorf("error checking env: %w", err)
+	}
+
+	return &Reward{client: logic.New(conf)}, nil
+}
+
+// EnvUp starts the environment like `reward env up -d`. If services are passed, only those are started. The context
+// is checked before the operation, a started docker compose run is not interrupted.
+func (r *Reward) EnvUp(ctx context.Context, services ...string) (*EnvResult, error) {
+	return r.runEnv(ctx, append([]string{"up", "-d"}, services...))
+}
+
+// EnvDown stops and removes the containers of the environment like `reward env down`. The volumes are removed as well
+// if volumes is true.
+func (r *Reward) EnvDown(ctx context.Context, volumes bool) (*EnvResult, error) {
+	args := []string{"down"}
+	if volumes {
+		args = append(args, "--volumes")
+	}
+
+	return r.runEnv(ctx, args)
+}
+
+// DBImport imports the dump read from in (it can be compressed) into the database of the environment like
+// `reward db import`. The import is aborted if the context is canceled.
+func (r *Reward) DBImport(ctx context.Context, in io.Reader) (*DBImportResult, error) {
+	err := r.check(ctx, "db import", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	r.client.Stdin = &contextReader{ctx: ctx, r: in}
+	defer func() {
+		r.client.Stdin = nil
+	}()
+
+	err = r.client.ImportDB(false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DBImportResult{
+		Environment: r.client.EnvName(),
+		Duration:    time.Since(start),
+	}, nil
+}
+
+// Status returns the containers of the environment (including the stopped ones) sorted by their service names.
+func (r *Reward) Status(ctx context.Context) ([]Container, error) {
+	err := r.check(ctx, "status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := r.client.Docker.API.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", r.client.LabelEnvName(), r.client.EnvName()))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list containers: %w", err)
+	}
+
+	containers := make([]Container, 0, len(list))
+	for _, c := range list {
+		containers = append(containers, newContainer(c))
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Service < containers[j].Service
+	})
+
+	return containers, nil
+}
+
+func (r *Reward) runEnv(ctx context.Context, args []string) (*EnvResult, error) {
+	err := r.check(ctx, "env", args)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	err = r.client.RunCmdEnv(args)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(start)
+
+	containers, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnvResult{
+		Environment: r.client.EnvName(),
+		Containers:  containers,
+		Duration:    duration,
+	}, nil
+}
+
+// check returns an error if the context is done, the environment is not initialized or the command is destructive
+// and the read-only mode is enabled.
+func (r *Reward) check(ctx context.Context, command string, args []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !r.client.EnvInitialized() {
+		return ErrEnvNotInitialized(r.client.Cwd())
+	}
+
+	if r.client.ReadOnly() && config.DestructiveCommand(command, args) {
+		return config.ErrReadOnly(strings.TrimSpace(command + " " + strings.Join(args, " ")))
+	}
+
+	return nil
+}
+
+// newContainer returns the state of the container from the container list of the docker engine. The health status
+// is only part of the status text in the list (eg. "Up 2 minutes (healthy)").
+func newContainer(c types.Container) Container {
+	container := Container{
+		Service: c.Labels["com.docker.compose.service"],
+		State:   c.State,
+	}
+
+	if len(c.Names) > 0 {
+		container.Name = strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	if i := strings.LastIndex(c.Status, "("); i >= 0 && strings.HasSuffix(c.Status, ")") {
+		health := strings.TrimPrefix(c.Status[i+1:len(c.Status)-1], "health: ")
+		if util.ContainsString([]string{"healthy", "unhealthy", "starting"}, health) {
+			container.Health = health
+		}
+	}
+
+	return container
+}
+
+// moduleVersion returns the version of the reward module the program is built with.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "0.0.0"
+	}
+
+	if info.Main.Path == "github.com/rewardenv/reward" && info.Main.Version != "(devel)" && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/rewardenv/reward" {
+			return dep.Version
+		}
+	}
+
+	return "0.0.0"
+}
+
+// contextReader returns the error of the context once it's done, so a running import is aborted.
+type contextReader struct {
+	ctx context.Context //nolint:containedctx
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
@@ -0,0 +1,145 @@
+package reward
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rewardenv/reward/internal/config"
+	"github.com/rewardenv/reward/internal/docker"
+	"github.com/rewardenv/reward/internal/logic"
+)
+
+type RewardTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RewardTestSuite) SetupTest() {
+	config.FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+	_ = config.FS.WriteFile(".env", []byte("REWARD_ENV_NAME=myproject\n"), 0o644)
+}
+
+func TestRewardTestSuite(t *testing.T) {
+	suite.Run(t, new(RewardTestSuite))
+}
+
+func newTestReward(settings map[string]interface{}, api docker.DockerAPI) *Reward {
+	v := viper.New()
+	v.Set("app_name", "reward")
+	v.Set("reward_env_name", "myproject")
+
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	client := logic.New(&config.Config{Viper: v, Docker: docker.NewClientWithAPI(api)})
+
+	return &Reward{client: client}
+}
+
+func testContainer(id, env, service, state, status string) types.Container {
+	return types.Container{
+		ID:    id,
+		Names: []string{"/" + env + "-" + service + "-1"},
+		Labels: map[string]string{
+			"dev.reward.env-name":        env,
+			"com.docker.compose.service": service,
+		},
+		State:  state,
+		Status: status,
+	}
+}
+
+func (suite *RewardTestSuite) TestStatus() {
+	r := newTestReward(nil, &docker.Fake{
+		Containers: []types.Container{
+			testContainer("1", "myproject", "php-fpm", "running", "Up 2 minutes (healthy)"),
+			testContainer("2", "myproject", "db", "running", "Up 5 seconds (health: starting)"),
+			testContainer("3", "myproject", "redis", "exited", "Exited (0) 3 minutes ago"),
+			testContainer("4", "other", "php-fpm", "running", "Up 2 minutes"),
+		},
+	})
+
+	got, err := r.Status(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []Container{
+		{Service: "db", Name: "myproject-db-1", State: "running", Health: "starting"},
+		{Service: "php-fpm", Name: "myproject-php-fpm-1", State: "running", Health: "healthy"},
+		{Service: "redis", Name: "myproject-redis-1", State: "exited"},
+	}, got)
+}
+
+func (suite *RewardTestSuite) TestCheck() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		ctx      context.Context //nolint:containedctx
+		op       func(r *Reward, ctx context.Context) error
+		wantErr  string
+	}{
+		{
+			name: "canceled context",
+			ctx:  ctx,
+			op: func(r *Reward, ctx context.Context) error {
+				_, err := r.Status(ctx)
+
+				return err
+			},
+			wantErr: "context canceled",
+		},
+		{
+			name:     "db import in read-only mode",
+			settings: map[string]interface{}{"reward_readonly": true},
+			ctx:      context.Background(),
+			op: func(r *Reward, ctx context.Context) error {
+				_, err := r.DBImport(ctx, strings.NewReader(""))
+
+				return err
+			},
+			wantErr: "`db import` is refused in read-only mode",
+		},
+		{
+			name:     "env down with volumes in read-only mode",
+			settings: map[string]interface{}{"reward_readonly": true},
+			ctx:      context.Background(),
+			op: func(r *Reward, ctx context.Context) error {
+				_, err := r.EnvDown(ctx, true)
+
+				return err
+			},
+			wantErr: "`env down --volumes` is refused in read-only mode",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			err := tt.op(newTestReward(tt.settings, &docker.Fake{}), tt.ctx)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func (suite *RewardTestSuite) TestContextReader() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &contextReader{ctx: ctx, r: strings.NewReader("dump")}
+
+	buf := make([]byte, 2)
+
+	n, err := r.Read(buf)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, n)
+
+	cancel()
+
+	_, err = r.Read(buf)
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+}
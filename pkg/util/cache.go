@@ -0,0 +1,52 @@
+package util
+
+import (
+	"sync"
+)
+
+// cache contains the memoized results of the expensive detection calls (eg. reading /etc/os-release, looking up
+// the commands in $PATH). The results are kept until the end of the invocation or until they are invalidated.
+var cache = struct {
+	sync.Mutex
+	values map[string]interface{}
+}{values: make(map[string]interface{})}
+
+// Memoize returns the memoized result of the key. If the key is not memoized yet, it calls fn and memoizes its
+// result. The errors are not memoized, so the next call retries.
+func Memoize[T any](key string, fn func() (T, error)) (T, error) {
+	cache.Lock()
+	value, ok := cache.values[key]
+	cache.Unlock()
+
+	if ok {
+		return value.(T), nil //nolint:forcetypeassert
+	}
+
+	result, err := fn()
+	if err != nil {
+		return result, err
+	}
+
+	cache.Lock()
+	cache.values[key] = result
+	cache.Unlock()
+
+	return result, nil
+}
+
+// InvalidateCache removes the memoized results of the keys, or all the memoized results if no keys are passed. It
+// has to be called when the result of a detection changes, eg. after installing a command.
+func InvalidateCache(keys ...string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if len(keys) == 0 {
+		cache.values = make(map[string]interface{})
+
+		return
+	}
+
+	for _, key := range keys {
+		delete(cache.values, key)
+	}
+}
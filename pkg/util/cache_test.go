@@ -0,0 +1,71 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CacheTestSuite) SetupTest() {
+	InvalidateCache()
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
+
+func (suite *CacheTestSuite) TestMemoize() {
+	calls := 0
+	detect := func() (string, error) {
+		calls++
+
+		return fmt.Sprintf("result %d", calls), nil
+	}
+
+	got, err := Memoize("key", detect)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "result 1", got)
+
+	got, _ = Memoize("key", detect)
+	assert.Equal(suite.T(), "result 1", got, "the result should be memoized")
+
+	InvalidateCache("other")
+
+	got, _ = Memoize("key", detect)
+	assert.Equal(suite.T(), "result 1", got, "only the passed keys should be invalidated")
+
+	InvalidateCache("key")
+
+	got, _ = Memoize("key", detect)
+	assert.Equal(suite.T(), "result 2", got)
+
+	InvalidateCache()
+
+	got, _ = Memoize("key", detect)
+	assert.Equal(suite.T(), "result 3", got)
+}
+
+func (suite *CacheTestSuite) TestMemoizeError() {
+	calls := 0
+	detect := func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, fmt.Errorf("failed")
+		}
+
+		return calls, nil
+	}
+
+	_, err := Memoize("key", detect)
+	assert.Error(suite.T(), err)
+
+	got, err := Memoize("key", detect)
+	assert.NoError(suite.T(), err, "the errors should not be memoized")
+	assert.Equal(suite.T(), 2, got)
+}
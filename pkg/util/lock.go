@@ -0,0 +1,92 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrFileLocked occurs when the file is locked by another process.
+var ErrFileLocked = fmt.Errorf("file is locked by another process")
+
+// FileLock is an advisory lock on a file. The operating system releases the lock when the process exits, so a
+// crashed process doesn't leave a stale lock behind.
+type FileLock struct {
+	file *os.File
+}
+
+// TryLockFile creates the file (and its directory) if it doesn't exist and locks it without waiting. The PID of the
+// process is written to the file, so if the file is already locked, ErrFileLocked and the PID of the process holding
+// the lock are returned (the PID is 0 if it's unknown).
+func TryLockFile(path string) (*FileLock, int, error) {
+	err := os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	//nolint:gosec
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot open lock file: %w", err)
+	}
+
+	err = lockFile(f)
+	if err != nil {
+		_ = f.Close()
+
+		if errors.Is(err, ErrFileLocked) {
+			return nil, lockHolder(path), ErrFileLocked
+		}
+
+		return nil, 0, fmt.Errorf("cannot lock %s: %w", path, err)
+	}
+
+	if err = f.Truncate(0); err == nil {
+		_, err = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	if err != nil {
+		_ = unlockFile(f)
+		_ = f.Close()
+
+		return nil, 0, fmt.Errorf("cannot write lock file: %w", err)
+	}
+
+	return &FileLock{file: f}, 0, nil
+}
+
+// Path returns the path of the locked file.
+func (l *FileLock) Path() string {
+	return l.file.Name()
+}
+
+// Unlock releases the lock. The file is kept, removing it could let two processes lock different files of the same
+// path.
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.file)
+	if err != nil {
+		_ = l.file.Close()
+
+		return fmt.Errorf("cannot unlock %s: %w", l.file.Name(), err)
+	}
+
+	return l.file.Close() //nolint:wrapcheck
+}
+
+// lockHolder returns the PID written to the lock file by the process holding the lock, or 0 if it's unknown.
+func lockHolder(path string) int {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0
+	}
+
+	return pid
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LockTestSuite struct {
+	suite.Suite
+}
+
+func TestLockTestSuite(t *testing.T) {
+	suite.Run(t, new(LockTestSuite))
+}
+
+func (suite *LockTestSuite) TestTryLockFile() {
+	path := filepath.Join(suite.T().TempDir(), "locks", "reward.lock")
+
+	lock, _, err := TryLockFile(path)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), path, lock.Path())
+
+	_, pid, err := TryLockFile(path)
+	assert.ErrorIs(suite.T(), err, ErrFileLocked)
+	assert.Equal(suite.T(), os.Getpid(), pid)
+
+	assert.NoError(suite.T(), lock.Unlock())
+
+	lock, _, err = TryLockFile(path)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), lock.Unlock())
+}
@@ -0,0 +1,117 @@
+package util
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// HostPlatform is the platform the application runs on, the commands run on the host are built for it.
+var HostPlatform = Platform{GOOS: runtime.GOOS}
+
+// ContainerPlatform is the platform of the containers, the scripts run by `sh -c` in them are quoted for it
+// regardless of the host.
+var ContainerPlatform = Platform{GOOS: "linux"}
+
+// posixSafeArg and windowsSafeArg match the arguments which can be passed to the shell without quoting.
+var (
+	posixSafeArg   = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+	windowsSafeArg = regexp.MustCompile(`^[A-Za-z0-9_@+=:,./\\-]+$`)
+)
+
+// Platform builds the command lines for the shell of an operating system: sh on Linux and macOS, cmd.exe on
+// Windows.
+type Platform struct {
+	GOOS string
+}
+
+// IsWindows returns true if the shell of the platform is cmd.exe.
+func (p Platform) IsWindows() bool {
+	return p.GOOS == "windows"
+}
+
+// Quote quotes s as a single argument for the shell of the platform.
+//
+// For sh the argument is put between single quotes, so nothing is expanded in it. For cmd.exe the argument is put
+// between double quotes (so the spaces don't split it), the double quotes in it are doubled and the backslashes
+// before a double quote are escaped as the Windows programs parse their command line.
+func (p Platform) Quote(s string) string {
+	if !p.IsWindows() {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	backslashes := 0
+
+	for _, r := range s {
+		switch r {
+		case '\\':
+			backslashes++
+
+			continue
+		case '"':
+			b.WriteString(strings.Repeat(`\`, backslashes*2))
+			b.WriteString(`""`)
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+			b.WriteRune(r)
+		}
+
+		backslashes = 0
+	}
+
+	// the backslashes before the closing quote would escape it
+	b.WriteString(strings.Repeat(`\`, backslashes*2))
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// Join quotes the arguments which contain characters special to the shell of the platform and joins them into a
+// command line.
+func (p Platform) Join(args ...string) string {
+	safe := posixSafeArg
+	if p.IsWindows() {
+		safe = windowsSafeArg
+	}
+
+	quoted := make([]string, len(args))
+
+	for i, arg := range args {
+		if safe.MatchString(arg) {
+			quoted[i] = arg
+		} else {
+			quoted[i] = p.Quote(arg)
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// ShellArgs returns the shell of the platform and its arguments which run the command line.
+//
+// cmd.exe is started with /s, so it only strips the quotes around the command line and keeps the quoted arguments
+// in it as they are.
+func (p Platform) ShellArgs(commandLine string) []string {
+	if p.IsWindows() {
+		return []string{"cmd", "/d", "/s", "/c", `"` + commandLine + `"`}
+	}
+
+	return []string{"sh", "-c", commandLine}
+}
+
+// ShellCommand returns the command which runs the command line by the shell of the host. On Windows the command
+// line is passed to cmd.exe as it is, without the escaping of the arguments by the exec package.
+func ShellCommand(commandLine string) *exec.Cmd {
+	args := HostPlatform.ShellArgs(commandLine)
+
+	//nolint:gosec
+	cmd := exec.Command(args[0], args[1:]...)
+	setRawCommandLine(cmd, args)
+
+	return cmd
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlatformTestSuite struct {
+	suite.Suite
+}
+
+func TestPlatformTestSuite(t *testing.T) {
+	suite.Run(t, new(PlatformTestSuite))
+}
+
+func (suite *PlatformTestSuite) TestQuote() {
+	tests := []struct {
+		name        string
+		arg         string
+		wantPOSIX   string
+		wantWindows string
+	}{
+		{name: "simple", arg: "mutagen", wantPOSIX: `'mutagen'`, wantWindows: `"mutagen"`},
+		{name: "empty", arg: "", wantPOSIX: `''`, wantWindows: `""`},
+		{
+			name:        "spaces",
+			arg:         `C:\Program Files\Reward\mutagen.exe`,
+			wantPOSIX:   `'C:\Program Files\Reward\mutagen.exe'`,
+			wantWindows: `"C:\Program Files\Reward\mutagen.exe"`,
+		},
+		{
+			name:        "trailing backslash",
+			arg:         `C:\Users\John Doe\`,
+			wantPOSIX:   `'C:\Users\John Doe\'`,
+			wantWindows: `"C:\Users\John Doe\\"`,
+		},
+		{name: "single quote", arg: `it's`, wantPOSIX: `'it'\''s'`, wantWindows: `"it's"`},
+		{name: "double quotes", arg: `say "hi"`, wantPOSIX: `'say "hi"'`, wantWindows: `"say ""hi"""`},
+		{name: "backslash before quote", arg: `a\"b`, wantPOSIX: `'a\"b'`, wantWindows: `"a\\""b"`},
+		{name: "variables", arg: `$HOME %PATH%`, wantPOSIX: `'$HOME %PATH%'`, wantWindows: `"$HOME %PATH%"`},
+		{name: "template", arg: "{{ json . }}", wantPOSIX: `'{{ json . }}'`, wantWindows: `"{{ json . }}"`},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPOSIX, Platform{GOOS: "linux"}.Quote(tt.arg))
+			assert.Equal(t, tt.wantPOSIX, Platform{GOOS: "darwin"}.Quote(tt.arg))
+			assert.Equal(t, tt.wantWindows, Platform{GOOS: "windows"}.Quote(tt.arg))
+		})
+	}
+}
+
+func (suite *PlatformTestSuite) TestJoin() {
+	tests := []struct {
+		name        string
+		args        []string
+		wantPOSIX   string
+		wantWindows string
+	}{
+		{
+			name:        "safe arguments",
+			args:        []string{"sudo", "cp", "-va", "/tmp/ca.pem", "--label=reward-sync=shop"},
+			wantPOSIX:   `sudo cp -va /tmp/ca.pem --label=reward-sync=shop`,
+			wantWindows: `sudo cp -va /tmp/ca.pem --label=reward-sync=shop`,
+		},
+		{
+			name:        "windows path",
+			args:        []string{"mutagen", "sync", "create", `C:\Users\me\shop`},
+			wantPOSIX:   `mutagen sync create 'C:\Users\me\shop'`,
+			wantWindows: `mutagen sync create C:\Users\me\shop`,
+		},
+		{
+			name:        "special characters",
+			args:        []string{"echo", "a b", "x|y", "", "$1"},
+			wantPOSIX:   `echo 'a b' 'x|y' '' '$1'`,
+			wantWindows: `echo "a b" "x|y" "" "$1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPOSIX, Platform{GOOS: "linux"}.Join(tt.args...))
+			assert.Equal(t, tt.wantWindows, Platform{GOOS: "windows"}.Join(tt.args...))
+		})
+	}
+}
+
+func (suite *PlatformTestSuite) TestShellArgs() {
+	assert.Equal(suite.T(),
+		[]string{"sh", "-c", `'/usr/bin/mutagen' version`},
+		Platform{GOOS: "linux"}.ShellArgs(`'/usr/bin/mutagen' version`),
+	)
+	assert.Equal(suite.T(),
+		[]string{"cmd", "/d", "/s", "/c", `""C:\Program Files\mutagen.exe" version"`},
+		Platform{GOOS: "windows"}.ShellArgs(`"C:\Program Files\mutagen.exe" version`),
+	)
+}
+
+func (suite *PlatformTestSuite) TestShellCommandRoundTrip() {
+	if runtime.GOOS == "windows" {
+		suite.T().Skip("the round trip is tested with sh")
+	}
+
+	for _, arg := range []string{"", "a b", `it's`, `say "hi"`, `$HOME`, "`id`", `C:\Users\John Doe\`, "a\nb"} {
+		out, err := ShellCommand("printf %s " + Quote(arg)).Output()
+
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), arg, string(out))
+	}
+}
@@ -0,0 +1,118 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/go-units"
+	log "github.com/sirupsen/logrus"
+)
+
+// progressLogInterval is the minimum time between two progress log messages.
+const progressLogInterval = 2 * time.Second
+
+// Progress receives the progress of the long-running operations (eg. downloads, extractions and database imports).
+// The methods are safe to call concurrently.
+type Progress interface {
+	// Start reports the total files and bytes of the operation. Zero means unknown.
+	Start(files int, bytes int64)
+	// Add reports the processed files and bytes.
+	Add(files int, bytes int64)
+	// Done reports that the operation is finished.
+	Done()
+}
+
+// NoProgress is the Progress which discards the progress.
+var NoProgress Progress = noProgress{}
+
+type noProgress struct{}
+
+func (noProgress) Start(int, int64) {}
+func (noProgress) Add(int, int64)   {}
+func (noProgress) Done()            {}
+
+// LogProgress is the Progress which logs the processed files and bytes periodically.
+type LogProgress struct {
+	action     string
+	totalFiles int64
+	totalBytes int64
+	files      int64
+	bytes      int64
+
+	mu      sync.Mutex
+	lastLog time.Time
+}
+
+// NewLogProgress returns a LogProgress. The action is the prefix of the log messages (eg. "Extracting mutagen").
+func NewLogProgress(action string) *LogProgress {
+	return &LogProgress{action: action, lastLog: time.Now()}
+}
+
+func (p *LogProgress) Start(files int, bytes int64) {
+	atomic.StoreInt64(&p.totalFiles, int64(files))
+	atomic.StoreInt64(&p.totalBytes, bytes)
+}
+
+func (p *LogProgress) Add(files int, bytes int64) {
+	atomic.AddInt64(&p.files, int64(files))
+	atomic.AddInt64(&p.bytes, bytes)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Since(p.lastLog) < progressLogInterval {
+		return
+	}
+
+	p.lastLog = time.Now()
+
+	log.Printf("%s: %s...", p.action, p)
+}
+
+func (p *LogProgress) Done() {
+	log.Debugf("%s: %s, done.", p.action, p)
+}
+
+// String returns the processed files and bytes, eg. "3/10 files, 1.5MiB/6MiB (25%)".
+func (p *LogProgress) String() string {
+	var (
+		files      = atomic.LoadInt64(&p.files)
+		bytes      = atomic.LoadInt64(&p.bytes)
+		totalFiles = atomic.LoadInt64(&p.totalFiles)
+		totalBytes = atomic.LoadInt64(&p.totalBytes)
+		s          = units.BytesSize(float64(bytes))
+	)
+
+	if totalBytes > 0 {
+		s = fmt.Sprintf("%s/%s (%d%%)", s, units.BytesSize(float64(totalBytes)), bytes*100/totalBytes)
+	}
+
+	switch {
+	case totalFiles > 0:
+		s = fmt.Sprintf("%d/%d files, %s", files, totalFiles, s)
+	case files > 0:
+		s = fmt.Sprintf("%d files, %s", files, s)
+	}
+
+	return s
+}
+
+// ProgressReader returns a reader which reports the bytes read from r to the progress.
+func ProgressReader(r io.Reader, progress Progress) io.Reader {
+	return &progressReader{Reader: r, progress: progress}
+}
+
+type progressReader struct {
+	io.Reader
+	progress Progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.progress.Add(0, int64(n))
+
+	return n, err //nolint:wrapcheck
+}
@@ -0,0 +1,139 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProgressTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ProgressTestSuite) SetupTest() {
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+}
+
+func TestProgressTestSuite(t *testing.T) {
+	suite.Run(t, new(ProgressTestSuite))
+}
+
+// countingProgress records the reported progress.
+type countingProgress struct {
+	mu                      sync.Mutex
+	totalFiles, files, done int
+	totalBytes, bytes       int64
+}
+
+func (p *countingProgress) Start(files int, bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.totalFiles, p.totalBytes = files, bytes
+}
+
+func (p *countingProgress) Add(files int, bytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.files += files
+	p.bytes += bytes
+}
+
+func (p *countingProgress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+}
+
+func (suite *ProgressTestSuite) TestLogProgressString() {
+	tests := []struct {
+		name       string
+		totalFiles int
+		totalBytes int64
+		files      int
+		bytes      int64
+		want       string
+	}{
+		{name: "bytes", bytes: 2048, want: "2KiB"},
+		{name: "bytes with total", totalBytes: 8192, bytes: 2048, want: "2KiB/8KiB (25%)"},
+		{name: "files", files: 3, bytes: 2048, want: "3 files, 2KiB"},
+		{
+			name:       "files with total",
+			totalFiles: 10,
+			totalBytes: 8192,
+			files:      3,
+			bytes:      4096,
+			want:       "3/10 files, 4KiB/8KiB (50%)",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			p := NewLogProgress("Testing")
+			p.Start(tt.totalFiles, tt.totalBytes)
+			p.Add(tt.files, tt.bytes)
+
+			assert.Equal(t, tt.want, p.String())
+		})
+	}
+}
+
+func (suite *ProgressTestSuite) TestProgressReader() {
+	p := &countingProgress{}
+
+	got, err := io.ReadAll(ProgressReader(strings.NewReader("hello world"), p))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hello world", string(got))
+	assert.Equal(suite.T(), int64(11), p.bytes)
+}
+
+func (suite *ProgressTestSuite) TestUnzipWithProgress() {
+	files := make(map[string]string)
+	want := make([]string, 0)
+
+	var size int64
+
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("dir%d/file%d.txt", i%3, i)
+		files[name] = strings.Repeat("x", i*100)
+		want = append(want, "/dest/"+name)
+		size += int64(i * 100)
+	}
+
+	p := &countingProgress{}
+
+	got, err := UnzipWithProgress(bytes.NewReader(testZipArchive(suite.T(), files)), "/dest", p)
+	assert.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), want, got)
+
+	for name, content := range files {
+		extracted, err := FS.ReadFile("/dest/" + name)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), content, string(extracted))
+	}
+
+	assert.Equal(suite.T(), 20, p.totalFiles)
+	assert.Equal(suite.T(), 20, p.files)
+	assert.Equal(suite.T(), size, p.totalBytes)
+	assert.Equal(suite.T(), size, p.bytes)
+	assert.Equal(suite.T(), 1, p.done)
+}
+
+func (suite *ProgressTestSuite) TestUnzipWithProgressError() {
+	archive := testZipArchive(suite.T(), map[string]string{"a/file": "a", "b/file": "b", "c/file": "c"})
+
+	// the files can't be created in a read-only filesystem
+	FS = &afero.Afero{Fs: afero.NewReadOnlyFs(afero.NewMemMapFs())}
+
+	_, err := UnzipWithProgress(bytes.NewReader(archive), "/dest", &countingProgress{})
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"io"
+	"time"
+)
+
+// rateLimitChunks is the number of chunks the bytes of a second are read in, so the transfer is smooth instead of
+// bursting at the start of every second.
+const rateLimitChunks = 10
+
+// RateLimitReader returns a reader which reads from r at most bytesPerSecond bytes per second. If bytesPerSecond is
+// not positive r is returned.
+func RateLimitReader(r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+
+	return &rateLimitReader{Reader: r, rate: bytesPerSecond, now: time.Now, sleep: time.Sleep}
+}
+
+type rateLimitReader struct {
+	io.Reader
+	rate  int64
+	read  int64
+	start time.Time
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func (r *rateLimitReader) Read(p []byte) (int, error) {
+	if r.start.IsZero() {
+		r.start = r.now()
+	}
+
+	chunk := r.rate / rateLimitChunks
+	if chunk < 1 {
+		chunk = 1
+	}
+
+	if int64(len(p)) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+
+	// wait until the bytes read so far are allowed by the rate
+	if wait := time.Duration(r.read*int64(time.Second)/r.rate) - r.now().Sub(r.start); wait > 0 {
+		r.sleep(wait)
+	}
+
+	return n, err //nolint:wrapcheck
+}
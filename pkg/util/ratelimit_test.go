@@ -0,0 +1,64 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitReader() {
+	tests := []struct {
+		name      string
+		rate      int64
+		size      int
+		wantSlept time.Duration
+	}{
+		{name: "1 second", rate: 1000, size: 1000, wantSlept: time.Second},
+		{name: "2.5 seconds", rate: 1000, size: 2500, wantSlept: 2500 * time.Millisecond},
+		{name: "small rate", rate: 5, size: 10, wantSlept: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			var (
+				slept time.Duration
+				now   = time.Now()
+			)
+
+			r := &rateLimitReader{
+				Reader: strings.NewReader(strings.Repeat("a", tt.size)),
+				rate:   tt.rate,
+				now:    func() time.Time { return now },
+				sleep: func(d time.Duration) {
+					slept += d
+					now = now.Add(d)
+				},
+			}
+
+			got, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Len(t, got, tt.size)
+			// the reads take no time, the waits add up to the transfer time allowed by the rate
+			assert.Equal(t, tt.wantSlept, slept)
+		})
+	}
+}
+
+func (suite *RateLimitTestSuite) TestRateLimitReaderUnlimited() {
+	r := bytes.NewReader([]byte("content"))
+
+	assert.Same(suite.T(), r, RateLimitReader(r, 0))
+}
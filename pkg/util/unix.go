@@ -4,7 +4,13 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -19,6 +25,71 @@ func IsAdmin() bool {
 }
 
 // RunMeElevated does nothing on unix systems.
-func RunMeElevated() {
+func RunMeElevated() error {
 	// But it needs to be implemented for the testing.
+	return nil
+}
+
+// UID returns the user ID of the user who runs the command. If the command is invoked using sudo, it returns the
+// ID of the user who invoked sudo (like Username does).
+func UID() int {
+	if uid, err := strconv.Atoi(os.Getenv("SUDO_UID")); err == nil {
+		return uid
+	}
+
+	return os.Getuid()
+}
+
+// GID returns the primary group ID of the user who runs the command. If the command is invoked using sudo, it
+// returns the primary group ID of the user who invoked sudo.
+func GID() int {
+	if gid, err := strconv.Atoi(os.Getenv("SUDO_GID")); err == nil {
+		return gid
+	}
+
+	return os.Getgid()
+}
+
+// AddCertificateToRootStore is only supported on Windows.
+func AddCertificateToRootStore(der []byte, localMachine bool) error {
+	return fmt.Errorf("adding certificates to the windows root certificate store is only supported on windows")
+}
+
+// IsWSL returns true if the command runs inside the Windows Subsystem for Linux.
+func IsWSL() bool {
+	version, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(version)), "microsoft")
+}
+
+// setRawCommandLine does nothing on unix systems, the arguments are passed to the process as they are.
+func setRawCommandLine(_ *exec.Cmd, _ []string) {}
+
+// lockFile locks the file exclusively using flock without waiting. It returns ErrFileLocked if the file is locked by
+// another process.
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrFileLocked
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// unlockFile releases the lock of the file.
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:wrapcheck
+}
+
+// FileOwnedByUser returns true if the file is owned by the user who runs the command (or the user who invoked sudo).
+func FileOwnedByUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+
+	return int(stat.Uid) == UID()
 }
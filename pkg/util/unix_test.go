@@ -1,6 +1,7 @@
 package util
 
 import (
+	"os"
 	"os/user"
 	"testing"
 
@@ -34,7 +35,39 @@ func (suite *UtilTestSuite) TestRunMeElevated() {
 	}
 	for _, tt := range tests {
 		suite.T().Run(tt.name, func(t *testing.T) {
-			RunMeElevated()
+			assert.NoError(t, RunMeElevated())
+		})
+	}
+}
+
+func (suite *UtilTestSuite) TestUIDAndGID() {
+	tests := []struct {
+		name    string
+		sudoUID string
+		sudoGID string
+		wantUID int
+		wantGID int
+	}{
+		{
+			name:    "invoked using sudo",
+			sudoUID: "1234",
+			sudoGID: "2345",
+			wantUID: 1234,
+			wantGID: 2345,
+		},
+		{
+			name:    "invoked without sudo",
+			wantUID: os.Getuid(),
+			wantGID: os.Getgid(),
+		},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			t.Setenv("SUDO_UID", tt.sudoUID)
+			t.Setenv("SUDO_GID", tt.sudoGID)
+
+			assert.Equal(t, tt.wantUID, UID())
+			assert.Equal(t, tt.wantGID, GID())
 		})
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"archive/zip"
 	"bufio"
 	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"encoding/json"
 	"errors"
@@ -12,12 +13,15 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 
 	dockerClient "github.com/docker/docker/client"
+	"github.com/klauspost/compress/zstd"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
@@ -36,6 +40,10 @@ var (
 	ErrFileNotFound = func(s string) error {
 		return fmt.Errorf("file not found: %s", s)
 	}
+	// ErrUnknownCompression occurs when the compression is not supported.
+	ErrUnknownCompression = func(s string) error {
+		return fmt.Errorf("unknown compression: %s (valid options: gzip, zstd)", s)
+	}
 )
 
 // CreateDir creates the directory if not exist.
@@ -43,7 +51,7 @@ func CreateDir(dir string, perm *os.FileMode) error {
 	log.Debugf("Creating directory %s...", dir)
 
 	if dir == "" {
-		log.Panicln("Directory path is empty.")
+		return fmt.Errorf("directory path is empty")
 	}
 
 	dirPath, err := filepath.Abs(dir)
@@ -218,7 +226,7 @@ func FileExists(file string) bool {
 	log.Tracef("Checking if file exist: %s...", file)
 
 	if file == "" {
-		log.Panicln("Path is empty.")
+		return false
 	}
 
 	exist := false
@@ -284,38 +292,104 @@ func evalSymlink(fs afero.Fs, filename string) (string, os.FileInfo, error) {
 	return link, fi, nil
 }
 
-// CommandAvailable returns if the parameter can be find in $PATH.
+// CommandAvailable returns if the parameter can be find in $PATH. The result is memoized, call
+// InvalidateCache(CommandAvailableCacheKey(name)) after installing the command.
 func CommandAvailable(name string) bool {
-	_, err := exec.LookPath(name)
+	available, _ := Memoize(CommandAvailableCacheKey(name), func() (bool, error) {
+		_, err := exec.LookPath(name)
+
+		return err == nil, nil
+	})
+
+	return available
+}
+
+// CommandAvailableCacheKey returns the cache key of the memoized result of CommandAvailable.
+func CommandAvailableCacheKey(name string) string {
+	return "command-available-" + name
+}
+
+// Username returns the name of the user who invoked the command. If the command was invoked using sudo,
+// the original user is returned.
+func Username() string {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" {
+		return sudoUser
+	}
+
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+// UserInGroup returns true if the current user is a member of the group. It returns false if the groups of the user
+// cannot be looked up (eg. on Windows).
+func UserInGroup(group string) bool {
+	u, err := user.Current()
+	if err != nil {
+		return false
+	}
+
+	ids, err := u.GroupIds()
+	if err != nil {
+		return false
+	}
+
+	for _, id := range ids {
+		if g, err := user.LookupGroupId(id); err == nil && g.Name == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTerminal returns true if the file (eg. os.Stdout) is a terminal.
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
 
-	return err == nil
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
 }
 
-// OSDistro returns the linux distro name if GOOS is linux, else "darwin" or "windows".
+// OSDistro returns the linux distro name if GOOS is linux, else "darwin" or "windows". The result is memoized. It's
+// empty if the distro cannot be determined (eg. /etc/os-release doesn't exist).
 func OSDistro() string {
-	if runtime.GOOS == "linux" {
+	if runtime.GOOS != "linux" {
+		return runtime.GOOS
+	}
+
+	distro, err := Memoize("os-distro", func() (string, error) {
 		cfg, err := ini.Load("/etc/os-release")
 		if err != nil {
-			log.Panicln("Fail to read file: ", err)
+			return "", err //nolint:wrapcheck
 		}
 
-		ConfigParams := make(map[string]string)
-		ConfigParams["ID"] = cfg.Section("").Key("ID").String()
-
-		return strings.ToLower(ConfigParams["ID"])
+		return strings.ToLower(cfg.Section("").Key("ID").String()), nil
+	})
+	if err != nil {
+		log.Debugf("Cannot determine the linux distro: %s", err)
 	}
 
-	return runtime.GOOS
+	return distro
 }
 
-// HomeDir returns the invoking user's home directory.
+// HomeDir returns the invoking user's home directory. It's looked up in the user database if $HOME is not set, and
+// it's empty if the home directory cannot be determined.
 func HomeDir() string {
 	home, err := os.UserHomeDir()
+	if err == nil {
+		return home
+	}
+
+	u, err := user.Current()
 	if err != nil {
-		log.Panicln(err)
+		log.Debugf("Cannot determine the home directory: %s", err)
+
+		return ""
 	}
 
-	return home
+	return u.HomeDir
 }
 
 func DockerHost() string {
@@ -323,7 +397,7 @@ func DockerHost() string {
 		return dockerClient.DefaultDockerHost
 	}
 
-	cmd := exec.Command("/bin/sh", "-c", "docker context list --format json")
+	cmd := exec.Command("docker", "context", "list", "--format", "json")
 
 	out, err := cmd.Output()
 	if err != nil {
@@ -452,31 +526,28 @@ func ContainsString(slice []string, vals ...string) bool {
 	return false
 }
 
-// Quote puts a quote around s string in Unix-like systems and returns it, while it just returns s as-is on Windows.
+// Quote quotes s as a single argument for the shell of the host (see Platform.Quote). Use QuotePOSIX for the
+// commands run in the containers.
 func Quote(s string) string {
-	switch OSDistro() {
-	case "windows":
-		return s
-	default:
-		return fmt.Sprintf("%q", s)
-	}
+	return HostPlatform.Quote(s)
+}
+
+// QuotePOSIX quotes s as a single argument for the sh of the containers.
+func QuotePOSIX(s string) string {
+	return ContainerPlatform.Quote(s)
 }
 
+// DecompressFileFromArchive returns the reader of the file from the archive. The type of the archive is determined by
+// its extension. The returned reader should be closed if it implements io.Closer, as a zip archive which is not
+// read from a file is buffered in a temporary file until the reader is closed.
 func DecompressFileFromArchive(src io.Reader, archive, filename string) (io.Reader, error) {
 	switch {
 	case strings.HasSuffix(archive, ".zip"):
 		log.Debugf("Decompressing zip file %s...", archive)
 
-		buf, err := io.ReadAll(src)
-		if err != nil {
-			return nil, fmt.Errorf("cannot read file: %w", err)
-		}
-
-		r := bytes.NewReader(buf)
-
-		z, err := zip.NewReader(r, r.Size())
+		z, cleanup, err := newZipReader(src)
 		if err != nil {
-			return nil, fmt.Errorf("cannot read zip file: %w", err)
+			return nil, err
 		}
 
 		for _, file := range z.File {
@@ -486,13 +557,17 @@ func DecompressFileFromArchive(src io.Reader, archive, filename string) (io.Read
 
 				f, err := file.Open()
 				if err != nil {
+					cleanup()
+
 					return nil, fmt.Errorf("cannot open file: %w", err)
 				}
 
-				return f, nil
+				return &zipFileReader{ReadCloser: f, cleanup: cleanup}, nil
 			}
 		}
 
+		cleanup()
+
 		return nil, ErrFileNotFound(filename)
 	case strings.HasSuffix(archive, ".tar.gz"), strings.HasSuffix(archive, ".tgz"):
 		log.Debugf("Decompressing tar.gz file %s...", archive)
@@ -519,6 +594,35 @@ func DecompressFileFromArchive(src io.Reader, archive, filename string) (io.Read
 		log.Debugf("...%s found in gzip file.", name)
 
 		return r, nil
+	case strings.HasSuffix(archive, ".tar.zst"), strings.HasSuffix(archive, ".tzst"):
+		log.Debugf("Decompressing tar.zst file %s...", archive)
+
+		zst, err := zstd.NewReader(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read zstd file: %w", err)
+		}
+
+		return unarchiveTar(zst.IOReadCloser(), archive, filename)
+	case strings.HasSuffix(archive, ".zst"):
+		log.Debugf("Decompressing zstd file %s...", archive)
+
+		zst, err := zstd.NewReader(src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read zstd file: %w", err)
+		}
+
+		log.Debugf("...%s found in zstd file.", filename)
+
+		return zst.IOReadCloser(), nil
+	case strings.HasSuffix(archive, ".tar.bz2"), strings.HasSuffix(archive, ".tbz2"):
+		log.Debugf("Decompressing tar.bz2 file %s...", archive)
+
+		return unarchiveTar(bzip2.NewReader(src), archive, filename)
+	case strings.HasSuffix(archive, ".bz2"):
+		log.Debugf("Decompressing bzip2 file %s...", archive)
+		log.Debugf("...%s found in bzip2 file.", filename)
+
+		return bzip2.NewReader(src), nil
 	case strings.HasSuffix(archive, ".tar.xz"):
 		log.Debugf("Decompressing tar.xz file %s...", archive)
 
@@ -546,6 +650,82 @@ func DecompressFileFromArchive(src io.Reader, archive, filename string) (io.Read
 	return src, nil
 }
 
+// compressionMagic is the magic bytes of the supported compressions.
+var compressionMagic = map[string][]byte{
+	"gzip":  {0x1f, 0x8b},
+	"zstd":  {0x28, 0xb5, 0x2f, 0xfd},
+	"bzip2": []byte("BZh"),
+	"xz":    {0xfd, '7', 'z', 'X', 'Z', 0x00},
+}
+
+// DecompressReader returns a reader which decompresses src if it's compressed with gzip, zstd, bzip2 or xz. The
+// compression is detected by the magic bytes, so the stream (eg. a database dump on stdin) can be passed regardless
+// of its compression. An uncompressed src is returned as-is.
+func DecompressReader(src io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(src)
+
+	header, err := buffered.Peek(6) //nolint:gomnd
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cannot read stream: %w", err)
+	}
+
+	compression := ""
+
+	for name, magic := range compressionMagic {
+		if bytes.HasPrefix(header, magic) {
+			compression = name
+		}
+	}
+
+	log.Debugf("Detected compression of the stream: %q.", compression)
+
+	switch compression {
+	case "gzip":
+		r, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read gzip stream: %w", err)
+		}
+
+		return r, nil
+	case "zstd":
+		r, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read zstd stream: %w", err)
+		}
+
+		return r.IOReadCloser(), nil
+	case "bzip2":
+		return io.NopCloser(bzip2.NewReader(buffered)), nil
+	case "xz":
+		r, err := xzpkg.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read xz stream: %w", err)
+		}
+
+		return io.NopCloser(r), nil
+	default:
+		return io.NopCloser(buffered), nil
+	}
+}
+
+// CompressWriter returns a writer which compresses the data written to it with the compression (gzip or zstd) to
+// dst. The writer has to be closed to flush the compressed data.
+func CompressWriter(dst io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case "gzip":
+		return gzip.NewWriter(dst), nil
+	case "zstd":
+		w, err := zstd.NewWriter(dst)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create zstd writer: %w", err)
+		}
+
+		return w, nil
+	default:
+		return nil, ErrUnknownCompression(compression)
+	}
+}
+
 func unarchiveTar(src io.Reader, archive, filename string) (io.Reader, error) {
 	tar := tarpkg.NewReader(src)
 
@@ -595,19 +775,26 @@ func matchExecutableName(cmd, target string) bool {
 }
 
 // Unzip will decompress a zip archive, moving all files and folders within the zip file (parameter 1) to an
-// output directory (parameter 2).
+// output directory (parameter 2). The files are extracted one by one using a bounded buffer, and the archive is read
+// directly if it's a file (see newZipReader).
 func Unzip(src io.Reader, dest string) ([]string, error) {
-	body, err := io.ReadAll(src)
-	if err != nil {
-		log.Panicln(err)
-	}
+	return UnzipWithProgress(src, dest, NoProgress)
+}
 
-	z, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+// UnzipWithProgress is Unzip which extracts the files concurrently (see unzipWorkers) and reports the extracted
+// files and bytes to the progress.
+func UnzipWithProgress(src io.Reader, dest string, progress Progress) ([]string, error) {
+	z, cleanup, err := newZipReader(src)
 	if err != nil {
-		log.Panicln(err)
+		return nil, err
 	}
+	defer cleanup()
 
-	filenames := make([]string, 0, len(z.File))
+	var (
+		filenames = make([]string, 0, len(z.File))
+		files     = make([]*zip.File, 0, len(z.File))
+		size      int64
+	)
 
 	//nolint:varnamelen
 	for _, f := range z.File {
@@ -621,48 +808,247 @@ func Unzip(src io.Reader, dest string) ([]string, error) {
 
 		filenames = append(filenames, fpath)
 
+		// The directories are created before the files are extracted concurrently.
+		dir := filepath.Dir(fpath)
 		if f.FileInfo().IsDir() {
-			// Make Folder
-			err = FS.MkdirAll(fpath, os.ModePerm)
-			if err != nil {
-				return []string{}, fmt.Errorf("cannot create directory: %w", err)
+			dir = fpath
+		}
+
+		err = FS.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			return []string{}, fmt.Errorf("cannot create directory: %w", err)
+		}
+
+		if !f.FileInfo().IsDir() {
+			files = append(files, f)
+			size += int64(f.UncompressedSize64)
+		}
+	}
+
+	progress.Start(len(files), size)
+	defer progress.Done()
+
+	err = unzipFiles(files, dest, progress)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return filenames, nil
+}
+
+// unzipWorkers is the maximum number of files extracted concurrently.
+var unzipWorkers = runtime.NumCPU()
+
+// unzipFiles extracts the files to the destination using at most unzipWorkers workers. It returns the first error.
+func unzipFiles(files []*zip.File, dest string, progress Progress) error {
+	var (
+		queue    = make(chan *zip.File)
+		errs     = make(chan error, len(files))
+		wg       sync.WaitGroup
+		stopOnce sync.Once
+		stop     = make(chan struct{})
+	)
+
+	workers := unzipWorkers
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			buf := make([]byte, zipCopyBufferSize)
+
+			for f := range queue {
+				err := unzipFile(f, filepath.Join(dest, f.Name), buf, progress) //nolint:gosec
+				if err != nil {
+					errs <- err
+
+					stopOnce.Do(func() { close(stop) })
+
+					continue
+				}
+
+				progress.Add(1, 0)
 			}
+		}()
+	}
 
-			continue
+out:
+	for _, f := range files {
+		select {
+		case queue <- f:
+		case <-stop:
+			break out
+		}
+	}
+
+	close(queue)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// zipCopyBufferSize is the size of the buffer used to extract the files of zip archives.
+const zipCopyBufferSize = 32 * 1024
+
+// zipFileReader is the reader of a file in a zip archive, which releases the archive when it's closed.
+type zipFileReader struct {
+	io.ReadCloser
+	cleanup func()
+}
+
+func (r *zipFileReader) Close() error {
+	defer r.cleanup()
+
+	return r.ReadCloser.Close() //nolint:wrapcheck
+}
+
+// newZipReader returns the zip reader of the archive and a function which releases it. Zip archives can only be read
+// with random access, so if src is a file (eg. afero.File), it's read directly, otherwise it's streamed into a
+// temporary file first instead of being read into the memory.
+func newZipReader(src io.Reader) (*zip.Reader, func(), error) {
+	cleanup := func() {}
+
+	r, ok := src.(interface {
+		io.ReaderAt
+		io.Seeker
+	})
+	if !ok {
+		tmp, err := FS.TempFile("", "reward-*.zip")
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot create temporary file: %w", err)
 		}
 
-		// Make File
-		if err = FS.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return filenames, fmt.Errorf("cannot create directory: %w", err)
+		cleanup = func() {
+			_ = tmp.Close()
+			_ = FS.Remove(tmp.Name())
 		}
 
-		outFile, err := FS.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		_, err = io.CopyBuffer(tmp, src, make([]byte, zipCopyBufferSize))
 		if err != nil {
-			return filenames, fmt.Errorf("cannot open file: %w", err)
+			cleanup()
+
+			return nil, nil, fmt.Errorf("cannot write temporary file: %w", err)
 		}
 
-		rc, err := f.Open()
+		r = tmp
+	}
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		cleanup()
+
+		return nil, nil, fmt.Errorf("cannot seek zip file: %w", err)
+	}
+
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		cleanup()
+
+		return nil, nil, fmt.Errorf("cannot read zip file: %w", err)
+	}
+
+	return z, cleanup, nil
+}
+
+// unzipFile extracts the file of the zip archive to the path using the buffer.
+func unzipFile(f *zip.File, path string, buf []byte, progress Progress) error {
+	outFile, err := FS.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return fmt.Errorf("cannot open file: %w", err)
+	}
+	defer outFile.Close()
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("cannot open file in zip: %w", err)
+	}
+	defer rc.Close()
+
+	_, err = io.CopyBuffer(outFile, ProgressReader(rc, progress), buf)
+	if err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	return nil
+}
+
+// CopyDir recursively copies the src directory to dst preserving file modes and symlinks. Paths (relative to src)
+// listed in exclude are skipped.
+func CopyDir(src, dst string, exclude ...string) error {
+	log.Debugf("Copying directory %s to %s...", src, dst)
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return filenames, fmt.Errorf("cannot open file in zip: %w", err)
+			return err
 		}
 
-		for {
-			_, err := io.CopyN(outFile, rc, 1024)
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					break
-				}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return fmt.Errorf("cannot determine relative path: %w", err)
+		}
 
-				return []string{}, fmt.Errorf("failed to copy file: %w", err)
+		if ContainsString(exclude, rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
 			}
+
+			return nil
 		}
 
-		// Close the file without defer to close before next iteration of loop
-		_ = outFile.Close()
-		_ = rc.Close()
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			return FS.MkdirAll(target, info.Mode().Perm())
+		case isSymlink(info):
+			link, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("cannot read symlink: %w", err)
+			}
+
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			log.Debugf("Skipping non-regular file %s.", path)
+
+			return nil
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+	if err != nil {
+		return fmt.Errorf("cannot copy directory %s: %w", src, err)
 	}
 
-	return filenames, nil
+	log.Debugf("...directory %s copied.", src)
+
+	return nil
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := FS.Open(src)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %w", err)
+	}
+	defer in.Close()
+
+	out, err := FS.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return fmt.Errorf("cannot copy file: %w", err)
+	}
+
+	return nil
 }
 
 // InsertStringBeforeOccurrence inserts insertStr before occurrence of searchStr (if exist) to args and returns args.
@@ -1,6 +1,11 @@
 package util
 
 import (
+	tarpkg "archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +13,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	xzpkg "github.com/ulikunitz/xz"
 )
 
 type UtilTestSuite struct {
@@ -37,11 +43,10 @@ func (suite *UtilTestSuite) TestCreateDir() {
 	}
 
 	tests := []struct {
-		name      string
-		args      args
-		want      os.FileMode
-		wantErr   error
-		wantPanic bool
+		name    string
+		args    args
+		want    os.FileMode
+		wantErr error
 	}{
 		{
 			name: "valid test",
@@ -81,22 +86,13 @@ func (suite *UtilTestSuite) TestCreateDir() {
 			args: args{
 				dir: "",
 			},
-			want:      os.FileMode(0o755),
-			wantErr:   nil,
-			wantPanic: true,
+			want:    os.FileMode(0o755),
+			wantErr: fmt.Errorf("directory path is empty"),
 		},
 	}
 
 	for _, tt := range tests {
 		suite.T().Run(tt.name, func(t *testing.T) {
-			if tt.wantPanic {
-				assert.Panics(t, func() {
-					_ = CreateDir(tt.args.dir, tt.args.perm)
-				})
-
-				return
-			}
-
 			err := CreateDir(tt.args.dir, tt.args.perm)
 
 			if err != nil || tt.wantErr != nil {
@@ -245,3 +241,281 @@ func (suite *UtilTestSuite) TestAppendToFileOrCreateDirAndWriteToFile() {
 		})
 	}
 }
+
+func (suite *UtilTestSuite) TestCopyDir() {
+	// CopyDir walks the real filesystem.
+	FS = &afero.Afero{Fs: afero.NewOsFs()}
+
+	src := suite.T().TempDir()
+	dst := filepath.Join(suite.T().TempDir(), "clone")
+
+	files := map[string]os.FileMode{
+		"composer.json":       0o644,
+		"bin/magento":         0o755,
+		"var/cache/file":      0o644,
+		".reward/history.log": 0o640,
+	}
+
+	for name, mode := range files {
+		assert.NoError(suite.T(), os.MkdirAll(filepath.Join(src, filepath.Dir(name)), 0o755))
+		assert.NoError(suite.T(), os.WriteFile(filepath.Join(src, name), []byte(name), mode))
+	}
+
+	assert.NoError(suite.T(), os.Symlink("bin/magento", filepath.Join(src, "magento")))
+
+	err := CopyDir(src, dst, "var/cache", filepath.Join(".reward", "history.log"))
+	assert.NoError(suite.T(), err)
+
+	for _, name := range []string{"composer.json", "bin/magento"} {
+		content, err := os.ReadFile(filepath.Join(dst, name))
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), name, string(content))
+
+		info, err := os.Stat(filepath.Join(dst, name))
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), files[name], info.Mode().Perm())
+	}
+
+	link, err := os.Readlink(filepath.Join(dst, "magento"))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "bin/magento", link)
+
+	assert.NoFileExists(suite.T(), filepath.Join(dst, "var/cache/file"))
+	assert.DirExists(suite.T(), filepath.Join(dst, "var"))
+	assert.NoFileExists(suite.T(), filepath.Join(dst, ".reward/history.log"))
+}
+
+func (suite *UtilTestSuite) TestCopyFile() {
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+	_ = FS.WriteFile("/src", []byte("new content"), 0o600)
+	_ = FS.WriteFile("/dst", []byte("old and longer content"), 0o644)
+
+	assert.NoError(suite.T(), copyFile("/src", "/dst", 0o600))
+
+	content, err := FS.ReadFile("/dst")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "new content", string(content), "the target should be truncated")
+
+	assert.Error(suite.T(), copyFile("/missing", "/dst", 0o600))
+}
+
+func testZipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+// tempFiles returns the files in the temporary directory of FS.
+func tempFiles(t *testing.T) []string {
+	t.Helper()
+
+	files, err := FS.ReadDir(os.TempDir())
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+
+	return names
+}
+
+func (suite *UtilTestSuite) TestUnzip() {
+	archive := testZipArchive(suite.T(), map[string]string{
+		"mutagen.exe":           "mutagen",
+		"mutagen-agents.tar.gz": "agents",
+	})
+
+	tests := []struct {
+		name string
+		src  func() io.Reader
+	}{
+		{
+			name: "file",
+			src: func() io.Reader {
+				_ = FS.WriteFile("/archive.zip", archive, 0o644)
+				f, _ := FS.Open("/archive.zip")
+
+				return f
+			},
+		},
+		{
+			name: "stream",
+			src: func() io.Reader {
+				return bytes.NewBuffer(archive)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+			files, err := Unzip(tt.src(), "/install")
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"/install/mutagen.exe", "/install/mutagen-agents.tar.gz"}, files)
+
+			content, err := FS.ReadFile("/install/mutagen.exe")
+			assert.NoError(t, err)
+			assert.Equal(t, "mutagen", string(content))
+			assert.Empty(t, tempFiles(t), "the temporary file should be removed")
+		})
+	}
+
+	_, err := Unzip(bytes.NewBuffer(testZipArchive(suite.T(), map[string]string{"../evil": "x"})), "/install")
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), tempFiles(suite.T()))
+}
+
+func (suite *UtilTestSuite) TestDecompressFileFromArchiveZip() {
+	FS = &afero.Afero{Fs: afero.NewMemMapFs()}
+
+	archive := testZipArchive(suite.T(), map[string]string{"reward.exe": "binary", "README.md": "readme"})
+
+	r, err := DecompressFileFromArchive(bytes.NewBuffer(archive), "reward_Windows_x86_64.zip", "reward.exe")
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), tempFiles(suite.T()), "the stream should be buffered in a temporary file")
+
+	content, err := io.ReadAll(r)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "binary", string(content))
+
+	closer, ok := r.(io.Closer)
+	assert.True(suite.T(), ok)
+	assert.NoError(suite.T(), closer.Close())
+	assert.Empty(suite.T(), tempFiles(suite.T()), "the temporary file should be removed")
+
+	_, err = DecompressFileFromArchive(bytes.NewBuffer(archive), "reward_Windows_x86_64.zip", "missing.exe")
+	assert.Error(suite.T(), err)
+	assert.Empty(suite.T(), tempFiles(suite.T()))
+}
+
+// testBzip2Content is "hello bzip2\n" compressed with bzip2 (there's no bzip2 compressor in the standard library).
+var testBzip2Content = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xab, 0x6b, 0xa1, 0xf1, 0x00, 0x00, 0x02, 0xd9, 0x80,
+	0x00, 0x10, 0x40, 0x00, 0x10, 0x00, 0x12, 0x64, 0xc0, 0x10, 0x20, 0x00, 0x31, 0x00, 0xd3, 0x4d, 0x04, 0x00, 0x1e,
+	0xa3, 0xef, 0x4e, 0x51, 0xa2, 0x07, 0x8b, 0xb9, 0x22, 0x9c, 0x28, 0x48, 0x55, 0xb5, 0xd0, 0xf8, 0x80,
+}
+
+func testCompress(t *testing.T, compression, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := CompressWriter(&buf, compression)
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func (suite *UtilTestSuite) TestDecompressReader() {
+	var xzBuf bytes.Buffer
+
+	xz, err := xzpkg.NewWriter(&xzBuf)
+	assert.NoError(suite.T(), err)
+
+	_, _ = xz.Write([]byte("hello xz\n"))
+	assert.NoError(suite.T(), xz.Close())
+
+	tests := []struct {
+		name string
+		src  []byte
+		want string
+	}{
+		{name: "plain", src: []byte("SELECT 1;\n"), want: "SELECT 1;\n"},
+		{name: "short plain", src: []byte("x"), want: "x"},
+		{name: "empty", src: []byte{}, want: ""},
+		{name: "gzip", src: testCompress(suite.T(), "gzip", "hello gzip\n"), want: "hello gzip\n"},
+		{name: "zstd", src: testCompress(suite.T(), "zstd", "hello zstd\n"), want: "hello zstd\n"},
+		{name: "bzip2", src: testBzip2Content, want: "hello bzip2\n"},
+		{name: "xz", src: xzBuf.Bytes(), want: "hello xz\n"},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			r, err := DecompressReader(bytes.NewReader(tt.src))
+			assert.NoError(t, err)
+
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	_, err = CompressWriter(io.Discard, "bzip2")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *UtilTestSuite) TestDecompressFileFromArchiveZstdBzip2() {
+	var tarBuf bytes.Buffer
+
+	tw := tarpkg.NewWriter(&tarBuf)
+	assert.NoError(suite.T(), tw.WriteHeader(&tarpkg.Header{Name: "reward", Mode: 0o755, Size: 6}))
+
+	_, _ = tw.Write([]byte("binary"))
+	assert.NoError(suite.T(), tw.Close())
+
+	tests := []struct {
+		name     string
+		archive  string
+		src      []byte
+		filename string
+		want     string
+	}{
+		{
+			name:     "tar.zst",
+			archive:  "reward_Linux_x86_64.tar.zst",
+			src:      testCompress(suite.T(), "zstd", tarBuf.String()),
+			filename: "reward",
+			want:     "binary",
+		},
+		{
+			name:     "zst",
+			archive:  "reward.zst",
+			src:      testCompress(suite.T(), "zstd", "binary"),
+			filename: "reward",
+			want:     "binary",
+		},
+		{
+			name:     "bz2",
+			archive:  "dump.sql.bz2",
+			src:      testBzip2Content,
+			filename: "dump.sql",
+			want:     "hello bzip2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			r, err := DecompressFileFromArchive(bytes.NewReader(tt.src), tt.archive, tt.filename)
+			assert.NoError(t, err)
+
+			got, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -4,9 +4,13 @@
 package util
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 	"syscall"
+	"unsafe"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sys/windows"
@@ -24,7 +28,9 @@ func IsAdmin() bool {
 		&sid,
 	)
 	if err != nil {
-		log.Panicf("SID Error: %s", err)
+		log.Debugf("SID Error: %s", err)
+
+		return false
 	}
 	// This appears to cast a null pointer so I'm not sure why this
 	// works, but this guy says it does and it Works for Me™:
@@ -33,14 +39,17 @@ func IsAdmin() bool {
 
 	member, err := token.IsMember(sid)
 	if err != nil {
-		log.Panicf("Token Membership Error: %s", err)
+		log.Debugf("Token Membership Error: %s", err)
+
+		return false
 	}
 
 	return (token.IsElevated() || member)
 }
 
-// RunMeElevated runs the program itself in elevated command prompt
-func RunMeElevated() {
+// RunMeElevated runs the program itself in elevated command prompt. The caller should stop after it returns without
+// an error, the elevated program continues the work.
+func RunMeElevated() error {
 	verb := "runas"
 	exe, _ := os.Executable()
 	cwd, _ := os.Getwd()
@@ -55,8 +64,103 @@ func RunMeElevated() {
 
 	err := windows.ShellExecute(0, verbPtr, exePtr, argPtr, cwdPtr, showCmd)
 	if err != nil {
-		log.Panicln(err)
+		return fmt.Errorf("cannot run elevated command prompt: %w", err)
 	}
 
-	os.Exit(0)
+	return nil
+}
+
+// UID returns the default user ID used in the containers as Windows doesn't have numeric user IDs.
+func UID() int {
+	return 1000
+}
+
+// GID returns the default group ID used in the containers as Windows doesn't have numeric group IDs.
+func GID() int {
+	return 1000
+}
+
+// AddCertificateToRootStore adds the DER encoded certificate to the Trusted Root Certification Authorities store
+// using the CryptoAPI. If localMachine is true the certificate is added to the store of the local computer (requires
+// admin privileges), otherwise it's added to the store of the current user (Windows asks for confirmation).
+func AddCertificateToRootStore(der []byte, localMachine bool) error {
+	if len(der) == 0 {
+		return fmt.Errorf("empty certificate")
+	}
+
+	location := uint32(windows.CERT_SYSTEM_STORE_CURRENT_USER)
+	if localMachine {
+		location = windows.CERT_SYSTEM_STORE_LOCAL_MACHINE
+	}
+
+	name, err := windows.UTF16PtrFromString("ROOT")
+	if err != nil {
+		return fmt.Errorf("cannot convert store name: %w", err)
+	}
+
+	store, err := windows.CertOpenStore(
+		windows.CERT_STORE_PROV_SYSTEM, 0, 0, location, uintptr(unsafe.Pointer(name)),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot open root certificate store: %w", err)
+	}
+	defer windows.CertCloseStore(store, 0) //nolint:errcheck
+
+	ctx, err := windows.CertCreateCertificateContext(
+		windows.X509_ASN_ENCODING|windows.PKCS_7_ASN_ENCODING, &der[0], uint32(len(der)),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create certificate context: %w", err)
+	}
+	defer windows.CertFreeCertificateContext(ctx) //nolint:errcheck
+
+	err = windows.CertAddCertificateContextToStore(store, ctx, windows.CERT_STORE_ADD_REPLACE_EXISTING, nil)
+	if err != nil {
+		return fmt.Errorf("cannot add certificate to root certificate store: %w", err)
+	}
+
+	return nil
+}
+
+// IsWSL returns false on Windows, the Windows binary is not running inside the WSL virtual machine.
+func IsWSL() bool {
+	return false
+}
+
+// setRawCommandLine passes the arguments to the process as they are. The exec package would escape the quotes of the
+// command line passed to cmd.exe, which cmd.exe doesn't understand.
+func setRawCommandLine(cmd *exec.Cmd, args []string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: strings.Join(args, " ")}
+}
+
+// lockFileOffset is the offset of the byte range locked by lockFile. The locks are mandatory on Windows, so a range
+// beyond the end of the file is locked, and the PID written to the file can still be read by the other processes.
+const lockFileOffset = 0x7fffffff
+
+// lockFile locks the file exclusively using LockFileEx without waiting. It returns ErrFileLocked if the file is locked
+// by another process.
+func lockFile(f *os.File) error {
+	err := windows.LockFileEx(
+		windows.Handle(f.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, 1, 0,
+		&windows.Overlapped{OffsetHigh: lockFileOffset},
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrFileLocked
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// unlockFile releases the lock of the file.
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx( //nolint:wrapcheck
+		windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{OffsetHigh: lockFileOffset},
+	)
+}
+
+// FileOwnedByUser returns true on Windows, the files are not owned by numeric user IDs.
+func FileOwnedByUser(_ os.FileInfo) bool {
+	return true
 }